@@ -1,19 +1,69 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
 
 	"go-seo/internal/infrastructure/config"
+	"go-seo/internal/infrastructure/database/migrations"
 	"go-seo/internal/infrastructure/database/postgres"
 )
 
+const usage = `Usage: migrate [-dir path] <command> [args]
+
+Commands:
+  up [N]         apply all pending migrations or the next N
+  down [N]       roll back the last applied migration or the last N
+  status         list migrations and when they were applied
+  create <name>  create empty up/down files with the next version in -dir
+
+Without a command, up is run.
+`
+
 func main() {
+	dir := flag.String("dir", migrations.Dir, "directory for new migration files (create)")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	command := "up"
+	args := flag.Args()
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	if command == "create" {
+		if len(args) != 1 {
+			log.Fatal("create expects a migration name, e.g. migrate create add_keyword_tags")
+		}
+		upPath, downPath, err := migrations.Create(*dir, args[0])
+		if err != nil {
+			log.Fatal("Failed to create migration: ", err)
+		}
+		log.Printf("Created %s and %s", upPath, downPath)
+		return
+	}
+
+	steps := 0
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			log.Fatalf("Invalid number of steps %q", args[0])
+		}
+		steps = n
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatal("Failed to load config:", err)
 	}
 
-	db, err := postgres.NewDatabaseWithMigration(postgres.Config{
+	db, err := postgres.NewDatabase(postgres.Config{
 		Host:     cfg.Database.Host,
 		Port:     cfg.Database.Port,
 		User:     cfg.Database.User,
@@ -26,5 +76,59 @@ func main() {
 	}
 	defer db.Close()
 
-	log.Println("Database migration completed successfully!")
+	migrator, err := migrations.NewMigrator(db.DB)
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(steps)
+		logMigrations("Applied", applied)
+		if err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		if len(applied) == 0 {
+			log.Println("Database is up to date")
+		}
+	case "down":
+		rolledBack, err := migrator.Down(steps)
+		logMigrations("Rolled back", rolledBack)
+		if err != nil {
+			log.Fatal("Rollback failed: ", err)
+		}
+		if len(rolledBack) == 0 {
+			log.Println("No applied migrations to roll back")
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatal("Failed to read migration status: ", err)
+		}
+		printStatus(statuses)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func logMigrations(action string, list []migrations.Migration) {
+	for _, migration := range list {
+		log.Printf("%s %04d_%s", action, migration.Version, migration.Name)
+	}
+}
+
+func printStatus(statuses []migrations.MigrationStatus) {
+	fmt.Printf("%-8s %-40s %s\n", "VERSION", "NAME", "APPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		name := status.Name
+		if status.Missing {
+			name += " (file missing)"
+		}
+		fmt.Printf("%-8s %-40s %s\n", fmt.Sprintf("%04d", status.Version), name, appliedAt)
+	}
 }
@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	_ "go-seo/docs"
 
 	httpDelivery "go-seo/internal/delivery/http"
+	"go-seo/internal/domain/entities"
+	domainservices "go-seo/internal/domain/services"
 	"go-seo/internal/infrastructure/config"
+	"go-seo/internal/infrastructure/database/migrations"
 	"go-seo/internal/infrastructure/database/postgres"
 	"go-seo/internal/infrastructure/services"
 	"go-seo/internal/repositories"
@@ -36,34 +44,81 @@ func main() {
 	}
 	defer db.Close()
 
+	migrator, err := migrations.NewMigrator(db.DB)
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+	// Код рассчитан на последнюю схему, поэтому с непримененными миграциями сервер не стартует
+	pending, err := migrator.Pending()
+	if err != nil {
+		log.Fatal("Failed to check database migrations:", err)
+	}
+	if pending > 0 {
+		log.Fatalf("%d database migrations are not applied, run `make migrate`", pending)
+	}
+
 	repos := repositories.NewContainer(db.DB)
 
-	xmlRiverService, err := services.NewXMLRiverService(
-		cfg.XMLRiver.BaseURL,
-		cfg.XMLRiver.UserID,
-		cfg.XMLRiver.APIKey,
-		cfg.XMLRiver.SoftID,
-	)
+	limiter := services.NewProviderRateLimiter(map[string]domainservices.ProviderLimits{
+		services.ProviderXMLRiver: domainservices.ProviderLimits(cfg.XMLRiver.Limits),
+		services.ProviderXMLStock: domainservices.ProviderLimits(cfg.XMLStock.Limits),
+	})
+
+	xmlRiverService, err := services.NewXMLRiverService(services.ProviderConfig{
+		Name:      services.ProviderXMLRiver,
+		BaseURL:   cfg.XMLRiver.BaseURL,
+		UserID:    cfg.XMLRiver.UserID,
+		APIKey:    cfg.XMLRiver.APIKey,
+		SoftID:    cfg.XMLRiver.SoftID,
+		Endpoints: services.XMLRiverEndpoints,
+		Capabilities: domainservices.ProviderCapabilities{
+			Sources:        []string{entities.GoogleSearch, entities.YandexSearch},
+			MaxPages:       cfg.XMLRiver.MaxPages,
+			GroupBy:        true,
+			CostPerRequest: cfg.XMLRiver.CostPerRequest,
+		},
+		Limiter: limiter,
+	})
 	if err != nil {
 		log.Fatal("Failed to create XMLRiver service:", err)
 	}
-	defer xmlRiverService.Close()
 
-	xmlStockService, err := services.NewXMLRiverService(
-		cfg.XMLStock.BaseURL,
-		cfg.XMLStock.UserID,
-		cfg.XMLStock.APIKey,
-		cfg.XMLStock.SoftID,
-	)
+	xmlStockService, err := services.NewXMLRiverService(services.ProviderConfig{
+		Name:      services.ProviderXMLStock,
+		BaseURL:   cfg.XMLStock.BaseURL,
+		UserID:    cfg.XMLStock.UserID,
+		APIKey:    cfg.XMLStock.APIKey,
+		SoftID:    cfg.XMLStock.SoftID,
+		Endpoints: services.XMLStockEndpoints,
+		Capabilities: domainservices.ProviderCapabilities{
+			Sources:        []string{entities.GoogleSearch, entities.YandexSearch},
+			MaxPages:       cfg.XMLStock.MaxPages,
+			GroupBy:        true,
+			CostPerRequest: cfg.XMLStock.CostPerRequest,
+		},
+		Limiter: limiter,
+	})
 	if err != nil {
 		log.Fatal("Failed to create XMLStock service:", err)
 	}
-	defer xmlStockService.Close()
+
+	providers := services.NewProviderRegistry(cfg.Search.DefaultProvider)
+	for _, provider := range []domainservices.SearchService{xmlRiverService, xmlStockService} {
+		if err := providers.Register(provider); err != nil {
+			log.Fatal("Failed to register search provider:", err)
+		}
+	}
+	if _, err := providers.Get(""); err != nil {
+		log.Fatal("Invalid default search provider:", err)
+	}
+	defer providers.Close()
 
 	wordstatService, err := services.NewWordstatService(
 		cfg.XMLRiver.BaseURL,
 		cfg.XMLRiver.UserID,
 		cfg.XMLRiver.APIKey,
+		cfg.XMLRiver.WordstatCostPerRequest,
+		limiter,
 	)
 	if err != nil {
 		log.Fatal("Failed to create Wordstat service:", err)
@@ -79,7 +134,57 @@ func main() {
 	idGenerator := services.NewIDGeneratorService()
 	retryService := services.NewRetryService(5, 10*time.Second)
 
-	useCases := usecases.NewContainer(repos, xmlRiverService, xmlStockService, wordstatService, kafkaService, idGenerator, retryService, cfg.Async.WorkerCount, cfg.Async.BatchSize, cfg.XMLRiver.SoftID, cfg.XMLStock.SoftID)
+	// Интерфейс остается nil без ключа: аккаунты провайдеров тогда отключены, запуски идут с учетными данными из конфигурации
+	var cipher domainservices.SecretCipher
+	if cfg.Security.CredentialsEncryptionKey != "" {
+		aesCipher, err := services.NewAESCipher(cfg.Security.CredentialsEncryptionKey)
+		if err != nil {
+			log.Fatal("Invalid CREDENTIALS_ENCRYPTION_KEY:", err)
+		}
+		cipher = aesCipher
+	} else {
+		log.Println("WARNING: CREDENTIALS_ENCRYPTION_KEY is not set, provider accounts are disabled")
+	}
+
+	retention := usecases.PositionRetentionSettings{
+		DetailDays:      cfg.Retention.DetailDays,
+		DailyRollupDays: cfg.Retention.DailyRollupDays,
+		SnapshotDays:    cfg.Retention.SnapshotDays,
+		PartitionsAhead: cfg.Retention.PartitionsAhead,
+	}
+	useCases := usecases.NewContainer(repos, providers, limiter, cipher, wordstatService, kafkaService, idGenerator, retryService, retention, cfg.Async.WorkerCount, cfg.Async.BatchSize)
+
+	if cfg.Auth.Enabled {
+		if err := useCases.APIKey.EnsureBootstrapKey(cfg.Auth.BootstrapKey); err != nil {
+			log.Fatal("Failed to register bootstrap API key:", err)
+		}
+	} else {
+		log.Println("WARNING: AUTH_ENABLED=false, HTTP API is available without API keys")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	hostname, _ := os.Hostname()
+	workerID := fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	queueDone := make(chan struct{})
+	go func() {
+		defer close(queueDone)
+		useCases.AsyncPositionTracking.RunQueue(ctx, workerID, cfg.Async.PollInterval, cfg.Async.LeaseDuration, cfg.Async.MaxJobs)
+	}()
+	log.Printf("Tracking job queue started as %s, up to %d jobs at once", workerID, cfg.Async.MaxJobs)
+
+	if cfg.Scheduler.Enabled {
+		go useCases.TrackingSchedule.Run(ctx, cfg.Scheduler.Interval)
+		log.Printf("Tracking scheduler started, checking every %s", cfg.Scheduler.Interval)
+	}
+
+	// Секции positions на следующие месяцы создаются этим же заданием, поэтому оно работает и без сроков хранения
+	go useCases.PositionRetention.Run(ctx, cfg.Retention.Interval)
+	log.Printf("Position retention started: details %d days, daily rollups %d days, SERP snapshots %d days (0 - keep)",
+		cfg.Retention.DetailDays, cfg.Retention.DailyRollupDays, cfg.Retention.SnapshotDays)
+
+	go useCases.Visibility.Run(ctx, cfg.Visibility.Interval)
 
 	r := gin.Default()
 
@@ -90,7 +195,7 @@ func main() {
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 
-	httpDelivery.SetupRoutes(r, useCases)
+	httpDelivery.SetupRoutes(r, useCases, cfg.Auth.Enabled)
 
 	srv := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -100,8 +205,22 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	log.Printf("Server starting on port %s", cfg.Server.Port)
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatal("Failed to start server:", err)
+	go func() {
+		log.Printf("Server starting on port %s", cfg.Server.Port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down server...")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown error: %v", err)
 	}
+
+	// Незавершенные джобы остаются в БД и будут продолжены после запуска
+	<-queueDone
 }
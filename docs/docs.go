@@ -15,6 +15,168 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/api-keys": {
+            "get": {
+                "description": "Возвращает выпущенные ключи, включая отозванные. Сами ключи не возвращаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Получить ключи API",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.APIKeyResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает ключ с указанными правами. Ключ возвращается в поле key только в этом ответе, в БД хранится его хеш",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Выпустить ключ API",
+                "parameters": [
+                    {
+                        "description": "Имя и права ключа",
+                        "name": "key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateAPIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/api-keys/{id}/revoke": {
+            "post": {
+                "description": "Ключ перестает приниматься сразу; запись остается, чтобы по джобам было видно, кто их запускал",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Отозвать ключ API",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ключа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.APIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/groups": {
             "get": {
                 "description": "Get list of all groups for a specific site",
@@ -50,6 +212,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -94,6 +262,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -221,6 +395,22 @@ const docTemplate = `{
                         "name": "site_id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag IDs, comma separated",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Keywords with any of the tags or with all of them (default any)",
+                        "name": "tags_match",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -239,6 +429,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -283,6 +479,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -292,42 +494,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/keywords/{id}": {
-            "put": {
-                "description": "Update keyword group_id",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/keywords/duplicates": {
+            "get": {
+                "description": "Группы ключевых слов сайта, совпадающих после нормализации: пробелы схлопываются, регистр и ё/е не учитываются. По выбору не учитываются стоп-слова (предлоги, союзы, частицы) и порядок слов. Слова группы объединяются через POST /api/keywords/merge",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "keywords"
                 ],
-                "summary": "Update keyword group",
+                "summary": "Получить дубли ключевых слов",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Keyword ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "ID сайта",
+                        "name": "site_id",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Keyword update data",
-                        "name": "keyword",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateKeywordRequest"
-                        }
+                        "type": "boolean",
+                        "description": "Не учитывать стоп-слова",
+                        "name": "ignore_stop_words",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Не учитывать порядок слов",
+                        "name": "ignore_word_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Страница (по умолчанию 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Групп на странице (по умолчанию 20, не больше 100)",
+                        "name": "per_page",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.KeywordResponse"
+                            "$ref": "#/definitions/dto.KeywordDuplicatesResponse"
                         }
                     },
                     "400": {
@@ -349,27 +563,84 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a keyword and all its tracking data",
+            }
+        },
+        "/api/keywords/import": {
+            "post": {
+                "description": "Импорт ключевых слов сайта из CSV (UTF-8 или Windows-1251, разделитель , ; или табуляция) или XLSX (первый лист). Колонки слова, группы и меток задаются номером с 1 или текстом заголовка. Недостающие группы и метки создаются, существующие слова переносятся в группу из файла и получают метки. Повторы и ошибочные строки пропускаются и перечисляются в ответе; dry_run только показывает, что будет сделано. Не больше 50000 строк и 20 МБ",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "keywords"
                 ],
-                "summary": "Delete a keyword",
+                "summary": "Импортировать ключевые слова из файла",
                 "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV или XLSX файл",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "description": "Keyword ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "ID сайта",
+                        "name": "site_id",
+                        "in": "formData",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Колонка ключевого слова (по умолчанию 1)",
+                        "name": "keyword_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Колонка имени группы",
+                        "name": "group_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Колонка меток",
+                        "name": "tags_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Разделитель меток (по умолчанию запятая)",
+                        "name": "tags_separator",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Первая строка - заголовок (по умолчанию true)",
+                        "name": "has_header",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только проверить файл",
+                        "name": "dry_run",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.DeleteKeywordResponse"
+                            "$ref": "#/definitions/dto.KeywordImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
@@ -378,6 +649,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -387,19 +664,166 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/positions/combined": {
-            "get": {
-                "description": "Get paginated combined positions from multiple sources",
+        "/api/keywords/merge": {
+            "post": {
+                "description": "Переносит историю позиций, агрегаты, снимки выдачи, задачи проверки и метки слов source_ids на target_id и удаляет эти слова. Если за один день по одним настройкам проверки есть позиции нескольких слов, остаются позиции target_id. Все слова должны принадлежать одному сайту",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get combined positions",
+                "tags": [
+                    "keywords"
+                ],
+                "summary": "Объединить ключевые слова",
                 "parameters": [
                     {
-                        "type": "integer",
+                        "description": "Объединяемые слова",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MergeKeywordsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.KeywordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/keywords/{id}": {
+            "put": {
+                "description": "Update keyword group_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keywords"
+                ],
+                "summary": "Update keyword group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyword ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Keyword update data",
+                        "name": "keyword",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateKeywordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.KeywordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a keyword and all its tracking data",
+                "tags": [
+                    "keywords"
+                ],
+                "summary": "Delete a keyword",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyword ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteKeywordResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/positions/combined": {
+            "get": {
+                "description": "Get paginated combined positions from multiple sources",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get combined positions",
+                "parameters": [
+                    {
+                        "type": "integer",
                         "description": "Site ID",
                         "name": "site_id",
                         "in": "query",
@@ -465,6 +889,40 @@ const docTemplate = `{
                         "name": "group_id",
                         "in": "query"
                     },
+                    {
+                        "type": "integer",
+                        "description": "Filter by position filter_group_id",
+                        "name": "filter_group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag IDs, comma separated",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Keywords with any of the tags or with all of them (default any)",
+                        "name": "tags_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Return positions of this competitor instead of the site",
+                        "name": "competitor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter Wordstat by query type (default, quotes, quotes_exclamation_marks, exclamation_marks)",
+                        "name": "wordstat_query_type",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Page number (default 1)",
@@ -491,6 +949,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -548,6 +1012,22 @@ const docTemplate = `{
                         "name": "last",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Tag IDs, comma separated",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Keywords with any of the tags or with all of them (default any)",
+                        "name": "tags_match",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Page number (default 1)",
@@ -574,6 +1054,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -618,32 +1104,78 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/positions/statistics": {
-            "post": {
-                "description": "Get position statistics for a site within date range",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/positions/movement": {
+            "get": {
+                "description": "Сравнивает последние за день позиции ключевых слов сайта в две даты проверки: up - поднялись, down - опустились, new - появились в выдаче, lost - выпали из выдачи, unchanged - без изменений. В отчет входят ключевые слова, проверенные в date_to. delta и net_score считаются в позициях, \"не найдено\" считается 101 позицией. Без дат сравниваются две последние проверки; status фильтрует только список ключевых слов",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get position statistics",
+                "tags": [
+                    "positions"
+                ],
+                "summary": "Получить отчет о движении позиций",
                 "parameters": [
                     {
-                        "description": "Statistics parameters",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.PositionStatisticsRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "site_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "google",
+                            "yandex"
+                        ],
+                        "type": "string",
+                        "description": "Источник",
+                        "name": "source",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID группы ключевых слов",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID группы фильтров",
+                        "name": "filter_group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Предыдущая дата проверки (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущая дата проверки (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "up",
+                            "down",
+                            "new",
+                            "lost",
+                            "unchanged"
+                        ],
+                        "type": "string",
+                        "description": "Вид изменения",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.PositionStatisticsResponse"
+                            "$ref": "#/definitions/dto.MovementResponse"
                         }
                     },
                     "400": {
@@ -652,6 +1184,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -661,24 +1199,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/positions/track-google": {
+        "/api/positions/statistics": {
             "post": {
-                "description": "Start async Google position tracking for site keywords",
+                "description": "Get position statistics for a site within date range",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Track Google positions",
+                "summary": "Get position statistics",
                 "parameters": [
                     {
-                        "description": "Google tracking parameters",
+                        "description": "Statistics parameters",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.TrackGooglePositionsRequest"
+                            "$ref": "#/definitions/dto.PositionStatisticsRequest"
                         }
                     }
                 ],
@@ -686,7 +1224,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.AsyncTrackPositionsResponse"
+                            "$ref": "#/definitions/dto.PositionStatisticsResponse"
                         }
                     },
                     "400": {
@@ -695,9 +1233,64 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/positions/track-google": {
+            "post": {
+                "description": "Start async Google position tracking for site keywords",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Track Google positions",
+                "parameters": [
+                    {
+                        "description": "Google tracking parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackGooglePositionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AsyncTrackPositionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
@@ -706,7 +1299,7 @@ const docTemplate = `{
         },
         "/api/positions/track-wordstat": {
             "post": {
-                "description": "Start async Wordstat position tracking for site keywords",
+                "description": "Start async Wordstat position tracking for site keywords with query type options",
                 "consumes": [
                     "application/json"
                 ],
@@ -738,6 +1331,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -781,6 +1380,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -790,21 +1395,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/sites": {
+        "/api/positions/visibility": {
             "get": {
-                "description": "Get list of tracked sites. If ids parameter is provided, returns only sites with specified IDs",
+                "description": "Видимость - ожидаемый трафик по кривой CTR позиций с весом частоты Wordstat в процентах от трафика при первых позициях по всем ключевым словам. Ключевые слова без частоты Wordstat в трафик не входят. Значения сохраняются по дням; дни, которые еще не считались, рассчитываются при запросе. Период не больше 366 дней",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sites"
+                    "positions"
                 ],
-                "summary": "Get sites",
+                "summary": "Получить видимость сайта по дням",
                 "parameters": [
                     {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "site_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "google",
+                            "yandex"
+                        ],
                         "type": "string",
-                        "description": "Comma-separated list of site IDs to filter by",
-                        "name": "ids",
+                        "description": "Источник",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID группы ключевых слов",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID группы фильтров",
+                        "name": "filter_group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "date_to",
                         "in": "query"
                     }
                 ],
@@ -812,10 +1452,54 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.SiteResponse"
-                            }
+                            "$ref": "#/definitions/dto.VisibilityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/positions/{id}/serp": {
+            "get": {
+                "description": "Get the full top of search results saved when the position was checked.\nSnapshots are kept after the position is rolled up, until SERP_SNAPSHOT_RETENTION_DAYS (0 - forever)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "positions"
+                ],
+                "summary": "Get SERP snapshot for a position",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Position ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SerpSnapshotResponse"
                         }
                     },
                     "400": {
@@ -824,6 +1508,41 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/provider-accounts": {
+            "get": {
+                "description": "Возвращает сохраненные аккаунты без API-ключей",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "provider-accounts"
+                ],
+                "summary": "Получить аккаунты провайдеров",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProviderAccountResponse"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -833,7 +1552,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new site for tracking",
+                "description": "Сохраняет учетные данные провайдера; ключ шифруется ключом из CREDENTIALS_ENCRYPTION_KEY и в ответах не возвращается. ID аккаунта передается в account_id запросов отслеживания",
                 "consumes": [
                     "application/json"
                 ],
@@ -841,17 +1560,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sites"
+                    "provider-accounts"
                 ],
-                "summary": "Create a new site",
+                "summary": "Создать аккаунт провайдера",
                 "parameters": [
                     {
-                        "description": "Site data",
-                        "name": "site",
+                        "description": "Учетные данные",
+                        "name": "account",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateSiteRequest"
+                            "$ref": "#/definitions/dto.ProviderAccountRequest"
                         }
                     }
                 ],
@@ -859,7 +1578,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.SiteResponse"
+                            "$ref": "#/definitions/dto.ProviderAccountResponse"
                         }
                     },
                     "400": {
@@ -868,26 +1587,40 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/sites/{id}": {
-            "delete": {
-                "description": "Delete a site and all its tracking data",
+        "/api/provider-accounts/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "sites"
+                    "provider-accounts"
                 ],
-                "summary": "Delete a site",
+                "summary": "Получить аккаунт провайдера",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Site ID",
+                        "description": "ID аккаунта",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -897,27 +1630,25 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.DeleteSiteResponse"
+                            "$ref": "#/definitions/dto.ProviderAccountResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/tracking-jobs": {
-            "get": {
-                "description": "Возвращает постраничный список джобов отслеживания позиций с возможностью фильтрации по сайту и статусу",
+            },
+            "put": {
+                "description": "Заменяет данные аккаунта. Пустой api_key оставляет сохраненный ключ",
                 "consumes": [
                     "application/json"
                 ],
@@ -925,40 +1656,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tracking-jobs"
+                    "provider-accounts"
                 ],
-                "summary": "Получить список джобов с пагинацией",
+                "summary": "Обновить аккаунт провайдера",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID сайта для фильтрации",
-                        "name": "site_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Статус джоба (pending, running, completed, failed, cancelled)",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Номер страницы (по умолчанию 1)",
-                        "name": "page",
-                        "in": "query"
+                        "description": "ID аккаунта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Количество записей на странице (по умолчанию 20, максимум 100)",
-                        "name": "per_page",
-                        "in": "query"
+                        "description": "Учетные данные",
+                        "name": "account",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProviderAccountRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.TrackingJobsResponse"
+                            "$ref": "#/definitions/dto.ProviderAccountResponse"
                         }
                     },
                     "400": {
@@ -967,6 +1690,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -974,30 +1709,3377 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "description": "Джобы и расписания, ссылающиеся на аккаунт, завершатся ошибкой при следующем запуске",
+                "tags": [
+                    "provider-accounts"
+                ],
+                "summary": "Удалить аккаунт провайдера",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID аккаунта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/providers": {
+            "get": {
+                "description": "Возвращает зарегистрированных провайдеров и их возможности. Имя провайдера передается в поле provider запросов отслеживания",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Получить список SERP провайдеров",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProviderResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/providers/limits": {
+            "get": {
+                "description": "Показывает для каждого аккаунта провайдера настроенные лимиты, число запросов в работе и в ожидании, а также расход дневной квоты. Счетчики ведутся отдельно в каждом инстансе сервиса. Ключ пространства видит только аккаунты своего пространства",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Получить состояние лимитов провайдеров",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProviderLimitsResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites": {
+            "get": {
+                "description": "Get list of tracked sites. If ids parameter is provided, returns only sites with specified IDs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Get sites",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of site IDs to filter by",
+                        "name": "ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.SiteResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new site for tracking",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Create a new site",
+                "parameters": [
+                    {
+                        "description": "Site data",
+                        "name": "site",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateSiteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SiteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}": {
+            "delete": {
+                "description": "Delete a site and all its tracking data",
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Delete a site",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteSiteResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}/budget": {
+            "put": {
+                "description": "Set the monthly spend limit for provider requests of the site. When a new tracking job would exceed the remaining budget it is refused (refuse) or started only for the keywords that fit (trim). Spend is counted from the first day of the calendar month",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Set site budget",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Budget",
+                        "name": "budget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SiteBudgetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SiteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}/cannibalization": {
+            "get": {
+                "description": "Ключевые слова, по которым конкурируют несколько URL сайта: ранжирующийся URL возвращается к уже ранжировавшемуся раньше (rotating) или несколько URL сайта и его поддоменов попадают в одну сохраненную выдачу (shared_checks). История позиций каждого URL собирается из позиций и снимков выдачи. Период не больше 92 дней",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Получить каннибализацию ключевых слов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "google",
+                            "yandex"
+                        ],
+                        "type": "string",
+                        "description": "Источник",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID группы ключевых слов",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Страница (по умолчанию 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ключевых слов на странице (по умолчанию 20, не больше 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CannibalizationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}/clusters": {
+            "get": {
+                "description": "Кластеры последней кластеризации сайта по убыванию размера со словами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Получить кластеры ключевых слов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Страница (по умолчанию 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Кластеров на странице (по умолчанию 20, не больше 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.KeywordClustersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Группирует слова сайта по числу общих URL в топ-10 последней сохраненной выдачи источника и заменяет прежние кластеры сайта. Маркерами становятся слова с наибольшим числом похожих; в режиме soft слово должно быть похоже на маркер, в режиме hard - на каждое слово кластера. Слова без похожих остаются без кластера. Группы не меняются, для переноса в группы есть /clusters/apply",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Кластеризовать ключевые слова по выдаче",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Параметры кластеризации",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ClusterKeywordsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ClusterKeywordsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}/clusters/apply": {
+            "post": {
+                "description": "Переносит слова кластеров в группы с именами кластеров; недостающие группы создаются, одноименные существующие используются. Без cluster_ids применяются все кластеры сайта",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Перенести кластеры в группы",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Кластеры",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApplyKeywordClustersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApplyKeywordClustersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}/competitors": {
+            "get": {
+                "description": "Get list of competitor domains tracked together with the site",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "competitors"
+                ],
+                "summary": "Get site competitors",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.CompetitorResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a competitor domain; its positions are recorded from the same SERP requests as the site",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "competitors"
+                ],
+                "summary": "Add a competitor",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Competitor data",
+                        "name": "competitor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CompetitorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CompetitorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}/competitors/{competitor_id}": {
+            "put": {
+                "description": "Change competitor domain",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "competitors"
+                ],
+                "summary": "Update a competitor",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Competitor ID",
+                        "name": "competitor_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Competitor data",
+                        "name": "competitor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CompetitorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CompetitorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a competitor together with its positions",
+                "tags": [
+                    "competitors"
+                ],
+                "summary": "Delete a competitor",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Competitor ID",
+                        "name": "competitor_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}/keyword-suggestions": {
+            "get": {
+                "description": "Сохраненные подсказки сайта по убыванию частоты. Фразы, которые уже стали ключевыми словами, не возвращаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Получить подсказки ключевых слов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Регион Wordstat",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "popular",
+                            "association"
+                        ],
+                        "type": "string",
+                        "description": "Источник подсказки",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Страница (по умолчанию 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Подсказок на странице (по умолчанию 20, не больше 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.KeywordSuggestionsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Запрашивает Wordstat по каждой исходной фразе в каждом регионе и сохраняет популярные и похожие запросы с частотами. Фразы, которые уже есть среди ключевых слов сайта, пропускаются; повторный сбор обновляет частоты. Запросы учитываются в расходах и бюджете сайта",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Собрать подсказки ключевых слов из Wordstat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Исходные фразы и регионы",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.KeywordSuggestionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectKeywordSuggestionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}/keyword-suggestions/accept": {
+            "post": {
+                "description": "Создает из подсказок ключевые слова сайта в указанной группе одним запросом. Фразы, которые уже есть у сайта, пропускаются; принятые подсказки удаляются во всех регионах",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Принять подсказки ключевых слов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Подсказки и группа",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AcceptKeywordSuggestionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.KeywordSuggestionsCountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}/keyword-suggestions/dismiss": {
+            "post": {
+                "description": "Удаляет подсказки; при следующем сборе они могут появиться снова",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Отклонить подсказки ключевых слов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Подсказки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DismissKeywordSuggestionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.KeywordSuggestionsCountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}/url-changes": {
+            "get": {
+                "description": "Ключевые слова, у которых ранжирующийся URL сайта менялся между проверками периода, со всеми сменами и историей позиций каждого URL. Сравниваются последние за день найденные позиции отдельно по источнику и группе фильтров; http/https, www и завершающий слеш сменой не считаются. Период не больше 92 дней",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Получить смены посадочных страниц",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "google",
+                            "yandex"
+                        ],
+                        "type": "string",
+                        "description": "Источник",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID группы ключевых слов",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Страница (по умолчанию 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ключевых слов на странице (по умолчанию 20, не больше 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.URLChangesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags": {
+            "get": {
+                "description": "Метки сайта по имени с числом помеченных ключевых слов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получить метки сайта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "site_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.TagResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создать метку ключевых слов сайта. Имя уникально в сайте без учета регистра, до 100 символов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Создать метку",
+                "parameters": [
+                    {
+                        "description": "Метка",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags/assign": {
+            "post": {
+                "description": "Назначает каждую метку каждому слову. Слова и метки должны относиться к одному сайту, уже назначенные метки пропускаются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Назначить метки ключевым словам",
+                "parameters": [
+                    {
+                        "description": "Слова и метки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AssignTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AssignTagsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags/unassign": {
+            "post": {
+                "description": "Снимает каждую метку с каждого слова. Слова и метки должны относиться к одному сайту",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Снять метки с ключевых слов",
+                "parameters": [
+                    {
+                        "description": "Слова и метки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AssignTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AssignTagsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Переименовать метку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID метки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Метка",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удалить метку; со слов она снимается, сами слова не удаляются",
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Удалить метку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID метки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tracking-jobs": {
+            "get": {
+                "description": "Возвращает постраничный список джобов отслеживания позиций с возможностью фильтрации по сайту и статусу",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-jobs"
+                ],
+                "summary": "Получить список джобов с пагинацией",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта для фильтрации",
+                        "name": "site_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статус джоба (pending, running, paused, completed, failed, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы (по умолчанию 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей на странице (по умолчанию 20, максимум 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingJobsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tracking-jobs/{id}": {
+            "get": {
+                "description": "Возвращает параметры запуска, время выполнения, провайдера и постраничный список итогов по каждому ключевому слову: позиция, URL, ошибка, число попыток",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-jobs"
+                ],
+                "summary": "Получить джоб с результатами по ключевым словам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID джоба",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статус задачи (pending, completed, failed, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы (по умолчанию 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей на странице (по умолчанию 20, максимум 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingJobDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tracking-jobs/{id}/cancel": {
+            "post": {
+                "description": "Останавливает запуск новых задач; запросы, уже отправленные провайдеру, завершаются. Оставшиеся задачи помечаются cancelled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-jobs"
+                ],
+                "summary": "Отменить джоб",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID джоба",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingJobItem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tracking-jobs/{id}/pause": {
+            "post": {
+                "description": "Останавливает запуск новых задач; невыполненные задачи продолжатся после resume",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-jobs"
+                ],
+                "summary": "Приостановить джоб",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID джоба",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingJobItem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tracking-jobs/{id}/resume": {
+            "post": {
+                "description": "Возвращает приостановленный джоб в очередь; выполняются только невыполненные задачи",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-jobs"
+                ],
+                "summary": "Возобновить джоб",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID джоба",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingJobItem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tracking-jobs/{id}/retry-failed": {
+            "post": {
+                "description": "Возвращает завершенный джоб в очередь; повторно проверяются только ключевые слова со статусом failed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-jobs"
+                ],
+                "summary": "Перезапустить неудачные ключевые слова джоба",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID джоба",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingJobItem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tracking-schedules": {
+            "get": {
+                "description": "Get tracking schedules, optionally filtered by site",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-schedules"
+                ],
+                "summary": "Get tracking schedules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Site ID",
+                        "name": "site_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.TrackingScheduleResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a recurring schedule that starts async tracking for a site by cron expression",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-schedules"
+                ],
+                "summary": "Create a tracking schedule",
+                "parameters": [
+                    {
+                        "description": "Schedule data",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingScheduleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tracking-schedules/{id}": {
+            "get": {
+                "description": "Get tracking schedule with last and next run",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-schedules"
+                ],
+                "summary": "Get a tracking schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingScheduleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace schedule source, parameters, cron expression and timezone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-schedules"
+                ],
+                "summary": "Update a tracking schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule data",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingScheduleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a tracking schedule; already started jobs are not affected",
+                "tags": [
+                    "tracking-schedules"
+                ],
+                "summary": "Delete a tracking schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tracking-schedules/{id}/pause": {
+            "post": {
+                "description": "Disable a schedule without deleting it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-schedules"
+                ],
+                "summary": "Pause a tracking schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingScheduleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tracking-schedules/{id}/resume": {
+            "post": {
+                "description": "Enable a paused schedule; the next run is calculated from now",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-schedules"
+                ],
+                "summary": "Resume a tracking schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrackingScheduleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/usage": {
+            "get": {
+                "description": "Возвращает число запросов и стоимость по дням, сайтам и источникам из журнала расхода. Без дат возвращается текущий месяц",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "usage"
+                ],
+                "summary": "Получить расход запросов к провайдерам",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сайта",
+                        "name": "site_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "google",
+                            "yandex",
+                            "wordstat"
+                        ],
+                        "type": "string",
+                        "description": "Источник",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UsageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/workspaces": {
+            "get": {
+                "description": "Доступно только системному ключу",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Получить пространства",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.WorkspaceResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Сайты, аккаунты провайдеров и ключи API пространства изолированы от других пространств. Ключи пространства выпускаются запросом к /api/api-keys с заголовком X-Workspace-ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Создать пространство",
+                "parameters": [
+                    {
+                        "description": "Пространство",
+                        "name": "workspace",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.WorkspaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WorkspaceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/workspaces/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Получить пространство",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пространства",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WorkspaceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Заменяет название и общий месячный бюджет пространства",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Обновить пространство",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пространства",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Пространство",
+                        "name": "workspace",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.WorkspaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WorkspaceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет пустое пространство: без сайтов, аккаунтов провайдеров и действующих ключей. Пространство по умолчанию удалить нельзя",
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Удалить пространство",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пространства",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "workspace_id": {
+                    "description": "nil - системный ключ",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AcceptKeywordSuggestionsRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "group_id": {
+                    "description": "null - слова без группы",
+                    "type": "integer"
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.ApplyKeywordClustersRequest": {
+            "type": "object",
+            "properties": {
+                "cluster_ids": {
+                    "description": "Пусто - все кластеры сайта",
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.ApplyKeywordClustersResponse": {
+            "type": "object",
+            "properties": {
+                "groups_created": {
+                    "type": "integer"
+                },
+                "keywords_moved": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AssignTagsRequest": {
+            "type": "object",
+            "required": [
+                "keyword_ids",
+                "tag_ids"
+            ],
+            "properties": {
+                "keyword_ids": {
+                    "type": "array",
+                    "maxItems": 10000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tag_ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.AssignTagsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Добавленные или удаленные связи; уже существующие не считаются",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AsyncTrackPositionsResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CannibalizationItem": {
+            "type": "object",
+            "properties": {
+                "filter_group_id": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "keyword_id": {
+                    "type": "integer"
+                },
+                "rotating": {
+                    "description": "Ранжирующийся URL возвращался к ранжировавшемуся раньше",
+                    "type": "boolean"
+                },
+                "shared_checks": {
+                    "description": "Проверок, где в выдаче было несколько URL сайта",
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.URLHistoryItem"
+                    }
+                }
+            }
+        },
+        "dto.CannibalizationResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CannibalizationItem"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/dto.PaginationInfo"
+                }
+            }
+        },
+        "dto.ClusterKeywordsRequest": {
+            "type": "object",
+            "required": [
+                "source"
+            ],
+            "properties": {
+                "filter_group_id": {
+                    "description": "Только проверки этой группы фильтров",
+                    "type": "integer"
+                },
+                "mode": {
+                    "description": "soft - похожи на маркер кластера, hard - на каждое слово; по умолчанию soft",
+                    "type": "string",
+                    "enum": [
+                        "soft",
+                        "hard"
+                    ]
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "google",
+                        "yandex"
+                    ]
+                },
+                "threshold": {
+                    "description": "Общих URL для попадания в кластер, по умолчанию 4",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1
+                }
+            }
+        },
+        "dto.ClusterKeywordsResponse": {
+            "type": "object",
+            "properties": {
+                "clustered": {
+                    "type": "integer"
+                },
+                "clusters": {
+                    "type": "integer"
+                },
+                "keywords": {
+                    "description": "Слова с сохраненной выдачей",
+                    "type": "integer"
+                },
+                "unclustered": {
+                    "description": "Слова, для которых не нашлось похожих",
+                    "type": "integer"
+                },
+                "without_serp": {
+                    "description": "Слова без сохраненной выдачи",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CollectKeywordSuggestionsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KeywordSuggestionItem"
+                    }
+                },
+                "existing": {
+                    "description": "Уже есть среди ключевых слов сайта",
+                    "type": "integer"
+                },
+                "failed_seeds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "found": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "saved": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CombinedPositionItem": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "keyword_id": {
+                    "type": "integer"
+                },
+                "positions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PositionData"
+                    }
+                },
+                "site_id": {
+                    "type": "integer"
+                },
+                "wordstat": {
+                    "$ref": "#/definitions/dto.PositionData"
+                }
+            }
+        },
+        "dto.CombinedPositionsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CombinedPositionItem"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/dto.MetaInfo"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/dto.PaginationInfo"
+                }
+            }
+        },
+        "dto.CompetitorRequest": {
+            "type": "object",
+            "required": [
+                "domain"
+            ],
+            "properties": {
+                "domain": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CompetitorResponse": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "site_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "scopes": {
+                    "description": "positions:read, keywords:manage, tracking:start, admin",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "workspace_id": {
+                    "description": "nil - системный ключ",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateGroupRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "site_id"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "site_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateKeywordRequest": {
+            "type": "object",
+            "required": [
+                "site_id",
+                "value"
+            ],
+            "properties": {
+                "group_id": {
+                    "type": "integer"
+                },
+                "site_id": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateSiteRequest": {
+            "type": "object",
+            "required": [
+                "domain"
+            ],
+            "properties": {
+                "domain": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateTagRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "site_id"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "site_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.DeleteKeywordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeleteSiteResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DismissKeywordSuggestionsRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GroupMovementItem": {
+            "type": "object",
+            "properties": {
+                "group_id": {
+                    "description": "null - ключевые слова без группы",
+                    "type": "integer"
+                },
+                "summary": {
+                    "$ref": "#/definitions/dto.MovementSummary"
+                }
+            }
+        },
+        "dto.GroupResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "site_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.KeywordClusterItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "filter_group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KeywordResponse"
+                    }
+                },
+                "marker_keyword_id": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.KeywordClustersResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KeywordClusterItem"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/dto.PaginationInfo"
+                }
+            }
+        },
+        "dto.KeywordDuplicateGroupItem": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "description": "По возрастанию ID",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KeywordResponse"
+                    }
+                }
+            }
+        },
+        "dto.KeywordDuplicatesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KeywordDuplicateGroupItem"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/dto.PaginationInfo"
+                }
+            }
+        },
+        "dto.KeywordImportResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "duplicate": {
+                    "type": "integer"
+                },
+                "groups_created": {
+                    "type": "integer"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "new": {
+                    "type": "integer"
+                },
+                "new_groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "new_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rows": {
+                    "description": "Только повторы и ошибочные строки",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KeywordImportRowItem"
+                    }
+                },
+                "tags_assigned": {
+                    "type": "integer"
+                },
+                "tags_created": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "Существующие слова, перенесенные в группу из файла",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.KeywordImportRowItem": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "duplicate, invalid",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.KeywordMovementItem": {
+            "type": "object",
+            "properties": {
+                "current_rank": {
+                    "description": "0 - не найдено",
+                    "type": "integer"
+                },
+                "delta": {
+                    "description": "Положительное - слово поднялось",
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "keyword_id": {
+                    "type": "integer"
+                },
+                "previous_rank": {
+                    "description": "0 - не найдено или не проверялось",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "up, down, new, lost, unchanged",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.KeywordResponse": {
+            "type": "object",
+            "properties": {
+                "cluster_id": {
+                    "description": "Кластер последней кластеризации сайта по выдаче",
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "normalized_value": {
+                    "description": "null - дубль другого слова, который нужно объединить",
+                    "type": "string"
+                },
+                "site_id": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TagResponse"
+                    }
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.KeywordSuggestionItem": {
+            "type": "object",
+            "properties": {
+                "frequency": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "popular, association",
+                    "type": "string"
+                },
+                "region": {
+                    "description": "null - все регионы",
+                    "type": "integer"
+                },
+                "seed": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.KeywordSuggestionsCountResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Созданные слова или удаленные подсказки",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.KeywordSuggestionsListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KeywordSuggestionItem"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/dto.PaginationInfo"
+                }
+            }
+        },
+        "dto.KeywordSuggestionsRequest": {
+            "type": "object",
+            "required": [
+                "seeds"
+            ],
+            "properties": {
+                "account_id": {
+                    "description": "Аккаунт xmlriver из /api/provider-accounts",
+                    "type": "integer"
+                },
+                "regions": {
+                    "type": "array",
+                    "maxItems": 5,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "seeds": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.KeywordURLChangesItem": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.URLChangeItem"
+                    }
+                },
+                "filter_group_id": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "keyword_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.URLHistoryItem"
+                    }
+                }
+            }
+        },
+        "dto.MergeKeywordsRequest": {
+            "type": "object",
+            "required": [
+                "source_ids",
+                "target_id"
+            ],
+            "properties": {
+                "source_ids": {
+                    "description": "Слова, которые переносятся в target и удаляются",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "target_id": {
+                    "description": "Слово, которое остается",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.MetaInfo": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "type": "boolean"
+                },
+                "query_time_ms": {
+                    "type": "integer"
+                }
             }
-        }
-    },
-    "definitions": {
-        "dto.AsyncTrackPositionsResponse": {
+        },
+        "dto.MovementResponse": {
             "type": "object",
             "properties": {
-                "message": {
+                "filter_group_id": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GroupMovementItem"
+                    }
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KeywordMovementItem"
+                    }
+                },
+                "site_id": {
+                    "type": "integer"
+                },
+                "source": {
                     "type": "string"
                 },
-                "status": {
+                "summary": {
+                    "description": "null - сравнивать не с чем",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MovementSummary"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.MovementSummary": {
+            "type": "object",
+            "properties": {
+                "current_date": {
                     "type": "string"
                 },
-                "task_id": {
+                "down": {
+                    "type": "integer"
+                },
+                "lost": {
+                    "type": "integer"
+                },
+                "net_score": {
+                    "description": "Сумма изменений позиций; \"не найдено\" считается 101 позицией",
+                    "type": "integer"
+                },
+                "new": {
+                    "type": "integer"
+                },
+                "previous_date": {
                     "type": "string"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "up": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.CombinedPositionItem": {
+        "dto.PaginationInfo": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "last_page": {
+                    "type": "integer"
+                },
+                "per_page": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PositionData": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PositionHistoryItem": {
             "type": "object",
             "properties": {
+                "avg_rank": {
+                    "type": "number"
+                },
+                "country": {
+                    "type": "string"
+                },
                 "date": {
                     "type": "string"
                 },
+                "device": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -1007,27 +5089,43 @@ const docTemplate = `{
                 "keyword_id": {
                     "type": "integer"
                 },
-                "positions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.PositionData"
-                    }
+                "lang": {
+                    "type": "string"
+                },
+                "period": {
+                    "description": "Заполнены у старых позиций, свернутых в агрегаты: period - day или week,\nrank и date - последняя проверка периода, id равен 0",
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "samples": {
+                    "type": "integer"
                 },
                 "site_id": {
                     "type": "integer"
                 },
-                "wordstat": {
-                    "$ref": "#/definitions/dto.PositionData"
+                "source": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "dto.CombinedPositionsResponse": {
+        "dto.PositionHistoryResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.CombinedPositionItem"
+                        "$ref": "#/definitions/dto.PositionHistoryItem"
                     }
                 },
                 "meta": {
@@ -1038,210 +5136,326 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.CreateGroupRequest": {
+        "dto.PositionRanges": {
             "type": "object",
-            "required": [
-                "name",
-                "site_id"
-            ],
             "properties": {
-                "name": {
+                "100_plus": {
+                    "type": "integer"
+                },
+                "11_30": {
+                    "type": "integer"
+                },
+                "1_3": {
+                    "type": "integer"
+                },
+                "31_50": {
+                    "type": "integer"
+                },
+                "4_10": {
+                    "type": "integer"
+                },
+                "51_100": {
+                    "type": "integer"
+                },
+                "not_found": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PositionResponse": {
+            "type": "object",
+            "properties": {
+                "ads": {
+                    "type": "boolean"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "device": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "keyword_id": {
+                    "type": "integer"
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "os": {
+                    "type": "string"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "site": {
                     "type": "string"
                 },
                 "site_id": {
                     "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "dto.CreateKeywordRequest": {
+        "dto.PositionStatisticsRequest": {
             "type": "object",
             "required": [
+                "date_from",
+                "date_to",
                 "site_id",
-                "value"
+                "source"
             ],
             "properties": {
-                "group_id": {
+                "competitor_id": {
+                    "description": "Статистика конкурента вместо сайта",
+                    "type": "integer"
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "filter_group_id": {
+                    "type": "integer"
+                },
+                "site_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "google",
+                        "yandex",
+                        "wordstat"
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tags_match": {
+                    "description": "По умолчанию any",
+                    "type": "string",
+                    "enum": [
+                        "any",
+                        "all"
+                    ]
+                }
+            }
+        },
+        "dto.PositionStatisticsResponse": {
+            "type": "object",
+            "properties": {
+                "keywords_count": {
                     "type": "integer"
                 },
-                "site_id": {
+                "not_visible": {
                     "type": "integer"
                 },
-                "value": {
-                    "type": "string"
+                "position_ranges": {
+                    "$ref": "#/definitions/dto.PositionRanges"
+                },
+                "total_positions": {
+                    "type": "integer"
+                },
+                "trends": {
+                    "$ref": "#/definitions/dto.Trends"
+                },
+                "visibility_stats": {
+                    "$ref": "#/definitions/dto.VisibilityStats"
+                },
+                "visible": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.CreateSiteRequest": {
+        "dto.ProviderAccountRequest": {
             "type": "object",
             "required": [
-                "domain"
+                "name",
+                "provider",
+                "user_id"
             ],
             "properties": {
-                "domain": {
+                "api_key": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.DeleteKeywordResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
+                },
+                "base_url": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.DeleteSiteResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "provider": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 100
                 }
             }
         },
-        "dto.ErrorResponse": {
+        "dto.ProviderAccountResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "base_url": {
                     "type": "string"
                 },
-                "message": {
+                "created_at": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.GroupResponse": {
-            "type": "object",
-            "properties": {
+                },
                 "id": {
                     "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
-                "site_id": {
+                "provider": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "workspace_id": {
                     "type": "integer"
                 }
             }
         },
-        "dto.KeywordResponse": {
+        "dto.ProviderLimitsResponse": {
             "type": "object",
             "properties": {
-                "group_id": {
+                "account": {
+                    "description": "user id аккаунта провайдера",
+                    "type": "string"
+                },
+                "daily_quota": {
+                    "description": "0 - без ограничения",
                     "type": "integer"
                 },
-                "id": {
+                "in_flight": {
+                    "description": "Запросов выполняется сейчас",
                     "type": "integer"
                 },
-                "site_id": {
+                "max_in_flight": {
+                    "description": "0 - без ограничения",
                     "type": "integer"
                 },
-                "value": {
+                "provider": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.MetaInfo": {
-            "type": "object",
-            "properties": {
-                "cached": {
-                    "type": "boolean"
                 },
-                "query_time_ms": {
+                "quota_reset_at": {
+                    "type": "string"
+                },
+                "remaining_today": {
+                    "type": "integer"
+                },
+                "requests_per_second": {
+                    "description": "0 - без ограничения",
+                    "type": "number"
+                },
+                "used_today": {
+                    "description": "Запросов отправлено за сутки",
+                    "type": "integer"
+                },
+                "waiting": {
+                    "description": "Запросов ждут лимита",
                     "type": "integer"
                 }
             }
         },
-        "dto.PaginationInfo": {
+        "dto.ProviderResponse": {
             "type": "object",
             "properties": {
-                "current_page": {
-                    "type": "integer"
-                },
-                "from": {
-                    "type": "integer"
+                "cost_per_request": {
+                    "type": "number"
                 },
-                "has_more": {
+                "default": {
                     "type": "boolean"
                 },
-                "last_page": {
-                    "type": "integer"
+                "groupby": {
+                    "type": "boolean"
                 },
-                "per_page": {
+                "max_pages": {
                     "type": "integer"
                 },
-                "to": {
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 },
-                "total": {
-                    "type": "integer"
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "dto.PositionData": {
+        "dto.SerpItemResponse": {
             "type": "object",
             "properties": {
-                "date": {
+                "content_type": {
                     "type": "string"
                 },
-                "rank": {
-                    "type": "integer"
-                },
-                "source": {
+                "domain": {
                     "type": "string"
                 },
-                "title": {
+                "passage": {
                     "type": "string"
                 },
-                "url": {
-                    "type": "string"
-                }
-            }
-        },
-        "dto.PositionDistribution": {
-            "type": "object",
-            "properties": {
-                "not_found": {
+                "place": {
                     "type": "integer"
                 },
-                "top_10": {
+                "rank": {
                     "type": "integer"
                 },
-                "top_20": {
-                    "type": "integer"
+                "title": {
+                    "type": "string"
                 },
-                "top_3": {
-                    "type": "integer"
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "dto.PositionHistoryItem": {
+        "dto.SerpSnapshotResponse": {
             "type": "object",
             "properties": {
-                "country": {
-                    "type": "string"
-                },
                 "date": {
                     "type": "string"
                 },
-                "device": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "keyword": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SerpItemResponse"
+                    }
                 },
                 "keyword_id": {
                     "type": "integer"
                 },
-                "lang": {
-                    "type": "string"
-                },
-                "position": {
-                    "type": "integer"
-                },
-                "rank": {
+                "position_id": {
                     "type": "integer"
                 },
                 "site_id": {
@@ -1249,197 +5463,207 @@ const docTemplate = `{
                 },
                 "source": {
                     "type": "string"
-                },
-                "title": {
-                    "type": "string"
-                },
-                "url": {
-                    "type": "string"
                 }
             }
         },
-        "dto.PositionHistoryResponse": {
+        "dto.SiteBudgetRequest": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.PositionHistoryItem"
-                    }
-                },
-                "meta": {
-                    "$ref": "#/definitions/dto.MetaInfo"
+                "budget_action": {
+                    "type": "string",
+                    "enum": [
+                        "refuse",
+                        "trim"
+                    ]
                 },
-                "pagination": {
-                    "$ref": "#/definitions/dto.PaginationInfo"
+                "monthly_budget": {
+                    "description": "null снимает ограничение",
+                    "type": "number",
+                    "minimum": 0
                 }
             }
         },
-        "dto.PositionRanges": {
+        "dto.SiteResponse": {
             "type": "object",
             "properties": {
-                "100_plus": {
-                    "type": "integer"
+                "budget_action": {
+                    "type": "string"
                 },
-                "11_30": {
+                "domain": {
+                    "type": "string"
+                },
+                "google_movement": {
+                    "$ref": "#/definitions/dto.MovementSummary"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "1_3": {
+                "keywords_count": {
                     "type": "integer"
                 },
-                "31_50": {
+                "last_position_update": {
+                    "type": "string"
+                },
+                "monthly_budget": {
+                    "type": "number"
+                },
+                "workspace_id": {
                     "type": "integer"
                 },
-                "4_10": {
+                "yandex_movement": {
+                    "description": "Движение позиций между двумя последними проверками; null - сравнивать пока не с чем",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MovementSummary"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.TagResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "integer"
                 },
-                "51_100": {
+                "keywords_count": {
+                    "description": "Только в списке меток сайта",
                     "type": "integer"
                 },
-                "not_found": {
+                "name": {
+                    "type": "string"
+                },
+                "site_id": {
                     "type": "integer"
                 }
             }
         },
-        "dto.PositionResponse": {
+        "dto.TrackGooglePositionsRequest": {
             "type": "object",
+            "required": [
+                "site_id"
+            ],
             "properties": {
+                "account_id": {
+                    "description": "Аккаунт провайдера из /api/provider-accounts",
+                    "type": "integer"
+                },
                 "ads": {
                     "type": "boolean"
                 },
-                "country": {
-                    "type": "string"
+                "ai": {
+                    "type": "integer"
                 },
-                "date": {
+                "country": {
                     "type": "string"
                 },
                 "device": {
-                    "type": "string"
+                    "type": "string",
+                    "enum": [
+                        "desktop",
+                        "tablet",
+                        "mobile"
+                    ]
                 },
-                "id": {
+                "domain": {
                     "type": "integer"
                 },
-                "keyword": {
-                    "type": "string"
+                "filter": {
+                    "type": "integer"
                 },
-                "keyword_id": {
+                "filter_group_id": {
+                    "type": "integer"
+                },
+                "highlights": {
                     "type": "integer"
                 },
                 "lang": {
                     "type": "string"
                 },
+                "loc": {
+                    "type": "integer"
+                },
+                "lr": {
+                    "type": "integer"
+                },
+                "nfpr": {
+                    "type": "integer"
+                },
                 "os": {
-                    "type": "string"
+                    "type": "string",
+                    "enum": [
+                        "ios",
+                        "android"
+                    ]
                 },
                 "pages": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1
                 },
-                "rank": {
-                    "type": "integer"
+                "provider": {
+                    "type": "string"
                 },
-                "site": {
+                "raw": {
                     "type": "string"
                 },
                 "site_id": {
                     "type": "integer"
                 },
-                "source": {
-                    "type": "string"
+                "strict": {
+                    "type": "integer"
                 },
-                "title": {
-                    "type": "string"
+                "subdomains": {
+                    "type": "boolean"
                 },
-                "url": {
+                "tbs": {
                     "type": "string"
                 }
             }
         },
-        "dto.PositionStatisticsRequest": {
+        "dto.TrackWordstatPositionsRequest": {
             "type": "object",
             "required": [
-                "date_from",
-                "date_to",
-                "site_id",
-                "source"
+                "site_id"
             ],
             "properties": {
-                "date_from": {
-                    "type": "string"
-                },
-                "date_to": {
-                    "type": "string"
-                },
-                "site_id": {
-                    "type": "integer"
-                },
-                "source": {
-                    "type": "string",
-                    "enum": [
-                        "google",
-                        "yandex",
-                        "wordstat"
-                    ]
-                }
-            }
-        },
-        "dto.PositionStatisticsResponse": {
-            "type": "object",
-            "properties": {
-                "keywords_count": {
-                    "type": "integer"
-                },
-                "not_visible": {
+                "account_id": {
+                    "description": "Аккаунт xmlriver из /api/provider-accounts",
                     "type": "integer"
                 },
-                "position_distribution": {
-                    "$ref": "#/definitions/dto.PositionDistribution"
-                },
-                "position_ranges": {
-                    "$ref": "#/definitions/dto.PositionRanges"
-                },
-                "total_positions": {
-                    "type": "integer"
+                "default": {
+                    "type": "boolean"
                 },
-                "trends": {
-                    "$ref": "#/definitions/dto.Trends"
+                "exclamation_marks": {
+                    "type": "boolean"
                 },
-                "visibility_stats": {
-                    "$ref": "#/definitions/dto.VisibilityStats"
+                "quotes": {
+                    "type": "boolean"
                 },
-                "visible": {
-                    "type": "integer"
-                }
-            }
-        },
-        "dto.SiteResponse": {
-            "type": "object",
-            "properties": {
-                "domain": {
-                    "type": "string"
+                "quotes_exclamation_marks": {
+                    "type": "boolean"
                 },
-                "id": {
+                "regions": {
                     "type": "integer"
                 },
-                "keywords_count": {
+                "site_id": {
                     "type": "integer"
-                },
-                "last_position_update": {
-                    "type": "string"
                 }
             }
         },
-        "dto.TrackGooglePositionsRequest": {
+        "dto.TrackYandexPositionsRequest": {
             "type": "object",
             "required": [
                 "site_id"
             ],
             "properties": {
+                "account_id": {
+                    "description": "Аккаунт провайдера из /api/provider-accounts",
+                    "type": "integer"
+                },
                 "ads": {
                     "type": "boolean"
                 },
-                "ai": {
-                    "type": "integer"
-                },
                 "country": {
                     "type": "string"
                 },
@@ -1452,7 +5676,16 @@ const docTemplate = `{
                     ]
                 },
                 "domain": {
-                    "type": "integer"
+                    "description": "Домен Яндекса",
+                    "type": "string",
+                    "enum": [
+                        "ru",
+                        "com",
+                        "ua",
+                        "com.tr",
+                        "by",
+                        "kz"
+                    ]
                 },
                 "filter": {
                     "type": "integer"
@@ -1460,20 +5693,23 @@ const docTemplate = `{
                 "filter_group_id": {
                     "type": "integer"
                 },
+                "groupby": {
+                    "type": "integer"
+                },
                 "highlights": {
                     "type": "integer"
                 },
+                "inindex": {
+                    "type": "integer"
+                },
                 "lang": {
                     "type": "string"
                 },
-                "loc": {
-                    "type": "integer"
-                },
                 "lr": {
                     "type": "integer"
                 },
-                "nfpr": {
-                    "type": "integer"
+                "organic": {
+                    "type": "boolean"
                 },
                 "os": {
                     "type": "string",
@@ -1487,65 +5723,224 @@ const docTemplate = `{
                     "maximum": 10,
                     "minimum": 1
                 },
+                "provider": {
+                    "type": "string"
+                },
                 "raw": {
                     "type": "string"
                 },
                 "site_id": {
                     "type": "integer"
                 },
+                "strict": {
+                    "type": "integer"
+                },
                 "subdomains": {
                     "type": "boolean"
                 },
-                "tbs": {
+                "within": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.TrackingJobDetailResponse": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "description": "Ключ API, которым запущен джоб",
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "completed_tasks": {
+                    "type": "integer"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "description": "От первого запуска до завершения или до текущего момента",
+                    "type": "number"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed_requests": {
+                    "type": "integer"
+                },
+                "failed_tasks": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/dto.PaginationInfo"
+                },
+                "params": {
+                    "$ref": "#/definitions/dto.TrackingParams"
+                },
+                "progress": {
+                    "description": "Процент выполнения",
+                    "type": "number"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "requests": {
+                    "description": "Платных запросов к провайдеру по журналу расхода",
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TrackingJobKeywordResult"
+                    }
+                },
+                "site_id": {
+                    "type": "integer"
+                },
+                "source": {
                     "type": "string"
                 },
-                "xml_api_key": {
+                "started_at": {
                     "type": "string"
                 },
-                "xml_base_url": {
+                "status": {
                     "type": "string"
                 },
-                "xml_user_id": {
+                "total_tasks": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "dto.TrackWordstatPositionsRequest": {
+        "dto.TrackingJobItem": {
             "type": "object",
-            "required": [
-                "site_id"
-            ],
             "properties": {
-                "regions": {
-                    "description": "ID региона Яндекса, nullable",
+                "api_key_id": {
+                    "description": "Ключ API, которым запущен джоб",
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "completed_tasks": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed_tasks": {
                     "type": "integer"
                 },
+                "id": {
+                    "type": "string"
+                },
+                "progress": {
+                    "description": "Процент выполнения",
+                    "type": "number"
+                },
                 "site_id": {
                     "type": "integer"
                 },
-                "xml_api_key": {
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_tasks": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TrackingJobKeywordResult": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "keyword_id": {
+                    "type": "integer"
+                },
+                "query_type": {
+                    "description": "Тип запроса Wordstat",
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "task_id": {
                     "type": "string"
                 },
-                "xml_base_url": {
+                "title": {
                     "type": "string"
                 },
-                "xml_user_id": {
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "dto.TrackYandexPositionsRequest": {
+        "dto.TrackingJobsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TrackingJobItem"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/dto.MetaInfo"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/dto.PaginationInfo"
+                }
+            }
+        },
+        "dto.TrackingParams": {
             "type": "object",
-            "required": [
-                "site_id"
-            ],
             "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
                 "ads": {
                     "type": "boolean"
                 },
+                "ai": {
+                    "type": "integer"
+                },
                 "country": {
                     "type": "string"
                 },
+                "default": {
+                    "type": "boolean"
+                },
                 "device": {
                     "type": "string",
                     "enum": [
@@ -1554,6 +5949,12 @@ const docTemplate = `{
                         "mobile"
                     ]
                 },
+                "domain": {
+                    "type": "integer"
+                },
+                "exclamation_marks": {
+                    "type": "boolean"
+                },
                 "filter": {
                     "type": "integer"
                 },
@@ -1572,9 +5973,18 @@ const docTemplate = `{
                 "lang": {
                     "type": "string"
                 },
+                "loc": {
+                    "type": "integer"
+                },
                 "lr": {
                     "type": "integer"
                 },
+                "nfpr": {
+                    "type": "integer"
+                },
+                "organic": {
+                    "type": "boolean"
+                },
                 "os": {
                     "type": "string",
                     "enum": [
@@ -1587,10 +5997,19 @@ const docTemplate = `{
                     "maximum": 10,
                     "minimum": 1
                 },
+                "provider": {
+                    "type": "string"
+                },
+                "quotes": {
+                    "type": "boolean"
+                },
+                "quotes_exclamation_marks": {
+                    "type": "boolean"
+                },
                 "raw": {
                     "type": "string"
                 },
-                "site_id": {
+                "regions": {
                     "type": "integer"
                 },
                 "strict": {
@@ -1599,44 +6018,90 @@ const docTemplate = `{
                 "subdomains": {
                     "type": "boolean"
                 },
+                "tbs": {
+                    "type": "string"
+                },
                 "within": {
                     "type": "integer"
                 },
-                "xml_api_key": {
+                "yandex_domain": {
+                    "description": "Домен Яндекса; domain - ID домена Google",
+                    "type": "string",
+                    "enum": [
+                        "ru",
+                        "com",
+                        "ua",
+                        "com.tr",
+                        "by",
+                        "kz"
+                    ]
+                }
+            }
+        },
+        "dto.TrackingScheduleRequest": {
+            "type": "object",
+            "required": [
+                "cron_expr",
+                "site_id",
+                "source"
+            ],
+            "properties": {
+                "cron_expr": {
                     "type": "string"
                 },
-                "xml_base_url": {
-                    "type": "string"
+                "enabled": {
+                    "description": "По умолчанию true",
+                    "type": "boolean"
+                },
+                "params": {
+                    "$ref": "#/definitions/dto.TrackingParams"
+                },
+                "site_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "google",
+                        "yandex",
+                        "wordstat"
+                    ]
                 },
-                "xml_user_id": {
+                "timezone": {
+                    "description": "IANA, по умолчанию UTC",
                     "type": "string"
                 }
             }
         },
-        "dto.TrackingJobItem": {
+        "dto.TrackingScheduleResponse": {
             "type": "object",
             "properties": {
-                "completed_at": {
+                "created_at": {
                     "type": "string"
                 },
-                "completed_tasks": {
+                "cron_expr": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "created_at": {
+                "last_error": {
                     "type": "string"
                 },
-                "error": {
+                "last_job_id": {
                     "type": "string"
                 },
-                "failed_tasks": {
-                    "type": "integer"
+                "last_run_at": {
+                    "type": "string"
                 },
-                "id": {
+                "next_run_at": {
                     "type": "string"
                 },
-                "progress": {
-                    "description": "Процент выполнения",
-                    "type": "number"
+                "params": {
+                    "$ref": "#/definitions/dto.TrackingParams"
                 },
                 "site_id": {
                     "type": "integer"
@@ -1644,44 +6109,92 @@ const docTemplate = `{
                 "source": {
                     "type": "string"
                 },
-                "status": {
+                "timezone": {
                     "type": "string"
                 },
-                "total_tasks": {
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Trends": {
+            "type": "object",
+            "properties": {
+                "declined": {
                     "type": "integer"
                 },
-                "updated_at": {
+                "improved": {
+                    "type": "integer"
+                },
+                "stable": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.URLChangeItem": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "previous_rank": {
+                    "type": "integer"
+                },
+                "previous_url": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "dto.TrackingJobsResponse": {
+        "dto.URLChangesResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.TrackingJobItem"
+                        "$ref": "#/definitions/dto.KeywordURLChangesItem"
                     }
                 },
-                "meta": {
-                    "$ref": "#/definitions/dto.MetaInfo"
-                },
                 "pagination": {
                     "$ref": "#/definitions/dto.PaginationInfo"
                 }
             }
         },
-        "dto.Trends": {
+        "dto.URLHistoryItem": {
             "type": "object",
             "properties": {
-                "declined": {
+                "best_rank": {
                     "type": "integer"
                 },
-                "improved": {
-                    "type": "integer"
+                "first_seen": {
+                    "type": "string"
                 },
-                "stable": {
+                "last_seen": {
+                    "type": "string"
+                },
+                "ranks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.URLRankItem"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.URLRankItem": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "rank": {
                     "type": "integer"
                 }
             }
@@ -1705,6 +6218,114 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdateTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UsageItem": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "site_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UsageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UsageItem"
+                    }
+                },
+                "totals": {
+                    "$ref": "#/definitions/dto.UsageTotals"
+                }
+            }
+        },
+        "dto.UsageTotals": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "number"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.VisibilityPoint": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "estimated_traffic": {
+                    "description": "Сумма частота * CTR позиции",
+                    "type": "number"
+                },
+                "found_count": {
+                    "type": "integer"
+                },
+                "frequency": {
+                    "description": "Суммарная частота Wordstat ключевых слов",
+                    "type": "integer"
+                },
+                "keywords_count": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "top10_count": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "description": "Процент от трафика при первых позициях по всем ключевым словам",
+                    "type": "number"
+                }
+            }
+        },
+        "dto.VisibilityResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VisibilityPoint"
+                    }
+                },
+                "filter_group_id": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "site_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "dto.VisibilityStats": {
             "type": "object",
             "properties": {
@@ -1721,6 +6342,43 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "dto.WorkspaceRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "monthly_budget": {
+                    "description": "Общий месячный бюджет сайтов пространства; null - без ограничения",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dto.WorkspaceResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "monthly_budget": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
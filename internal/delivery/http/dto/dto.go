@@ -8,11 +8,20 @@ type CreateSiteRequest struct {
 
 type SiteResponse struct {
 	ID                 int        `json:"id"`
+	WorkspaceID        int        `json:"workspace_id"`
 	Domain             string     `json:"domain"`
 	KeywordsCount      int        `json:"keywords_count"`
 	LastPositionUpdate *time.Time `json:"last_position_update,omitempty"`
-	YandexDynamic      *int       `json:"yandex_dynamic"`
-	GoogleDynamic      *int       `json:"google_dynamic"`
+	MonthlyBudget      *float64   `json:"monthly_budget"`
+	BudgetAction       string     `json:"budget_action"`
+	// Движение позиций между двумя последними проверками; null - сравнивать пока не с чем
+	YandexMovement *MovementSummary `json:"yandex_movement"`
+	GoogleMovement *MovementSummary `json:"google_movement"`
+}
+
+type SiteBudgetRequest struct {
+	MonthlyBudget *float64 `json:"monthly_budget" binding:"omitempty,gte=0"` // null снимает ограничение
+	BudgetAction  string   `json:"budget_action" binding:"omitempty,oneof=refuse trim"`
 }
 
 type DeleteSiteResponse struct {
@@ -36,10 +45,125 @@ type UpdateKeywordRequest struct {
 }
 
 type KeywordResponse struct {
-	ID      int    `json:"id"`
-	Value   string `json:"value"`
-	SiteID  int    `json:"site_id"`
-	GroupID *int   `json:"group_id"`
+	ID              int           `json:"id"`
+	Value           string        `json:"value"`
+	SiteID          int           `json:"site_id"`
+	GroupID         *int          `json:"group_id"`
+	NormalizedValue *string       `json:"normalized_value"` // null - дубль другого слова, который нужно объединить
+	ClusterID       *int          `json:"cluster_id"`       // Кластер последней кластеризации сайта по выдаче
+	Tags            []TagResponse `json:"tags,omitempty"`
+}
+
+type KeywordDuplicatesRequest struct {
+	SiteID          int  `form:"site_id" binding:"required"`
+	IgnoreStopWords bool `form:"ignore_stop_words"` // Не учитывать предлоги, союзы и частицы
+	IgnoreWordOrder bool `form:"ignore_word_order"`
+	Page            int  `form:"page" binding:"omitempty,min=1"`
+	PerPage         int  `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+type KeywordDuplicateGroupItem struct {
+	Key      string            `json:"key"`
+	Keywords []KeywordResponse `json:"keywords"` // По возрастанию ID
+}
+
+type KeywordDuplicatesResponse struct {
+	Data       []KeywordDuplicateGroupItem `json:"data"`
+	Pagination PaginationInfo              `json:"pagination"`
+}
+
+type MergeKeywordsRequest struct {
+	TargetID  int   `json:"target_id" binding:"required"`                // Слово, которое остается
+	SourceIDs []int `json:"source_ids" binding:"required,min=1,max=100"` // Слова, которые переносятся в target и удаляются
+}
+
+// KeywordSuggestionsRequest исходные фразы для подсказок Wordstat. Каждая фраза запрашивается в каждом регионе;
+// без регионов - один запрос по всем регионам
+type KeywordSuggestionsRequest struct {
+	Seeds     []string `json:"seeds" binding:"required,min=1,max=20"`
+	Regions   []int    `json:"regions" binding:"max=5"`
+	AccountID *int     `json:"account_id"` // Аккаунт xmlriver из /api/provider-accounts
+}
+
+type KeywordSuggestionItem struct {
+	ID        int    `json:"id"`
+	Value     string `json:"value"`
+	Seed      string `json:"seed"`
+	Kind      string `json:"kind"`   // popular, association
+	Region    *int   `json:"region"` // null - все регионы
+	Frequency int    `json:"frequency"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type CollectKeywordSuggestionsResponse struct {
+	Requests    int                     `json:"requests"`
+	Found       int                     `json:"found"`
+	Existing    int                     `json:"existing"` // Уже есть среди ключевых слов сайта
+	Saved       int                     `json:"saved"`
+	FailedSeeds []string                `json:"failed_seeds"`
+	Data        []KeywordSuggestionItem `json:"data"`
+}
+
+type KeywordSuggestionsListRequest struct {
+	Region  *int    `form:"region"`
+	Kind    *string `form:"kind" binding:"omitempty,oneof=popular association"`
+	Page    int     `form:"page" binding:"omitempty,min=1"`
+	PerPage int     `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+type KeywordSuggestionsListResponse struct {
+	Data       []KeywordSuggestionItem `json:"data"`
+	Pagination PaginationInfo          `json:"pagination"`
+}
+
+type AcceptKeywordSuggestionsRequest struct {
+	IDs     []int `json:"ids" binding:"required,min=1,max=1000"`
+	GroupID *int  `json:"group_id"` // null - слова без группы
+}
+
+type DismissKeywordSuggestionsRequest struct {
+	IDs []int `json:"ids" binding:"required,min=1,max=1000"`
+}
+
+type KeywordSuggestionsCountResponse struct {
+	Count int `json:"count"` // Созданные слова или удаленные подсказки
+}
+
+// KeywordImportRequest поля multipart-формы импорта; сам файл передается в поле file.
+// Колонка задается номером с 1 или текстом заголовка
+type KeywordImportRequest struct {
+	SiteID        int    `form:"site_id" binding:"required"`
+	KeywordColumn string `form:"keyword_column"` // По умолчанию первая колонка
+	GroupColumn   string `form:"group_column"`
+	TagsColumn    string `form:"tags_column"`
+	TagsSeparator string `form:"tags_separator"` // По умолчанию запятая
+	HasHeader     *bool  `form:"has_header"`     // По умолчанию true
+	DryRun        bool   `form:"dry_run"`
+}
+
+type KeywordImportRowItem struct {
+	Row     int      `json:"row"`
+	Keyword string   `json:"keyword"`
+	Group   string   `json:"group,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Status  string   `json:"status"` // duplicate, invalid
+	Reason  string   `json:"reason"`
+}
+
+type KeywordImportResponse struct {
+	DryRun        bool                   `json:"dry_run"`
+	Total         int                    `json:"total"`
+	New           int                    `json:"new"`
+	Duplicate     int                    `json:"duplicate"`
+	Invalid       int                    `json:"invalid"`
+	NewGroups     []string               `json:"new_groups"`
+	NewTags       []string               `json:"new_tags"`
+	Created       int                    `json:"created"`
+	Updated       int                    `json:"updated"` // Существующие слова, перенесенные в группу из файла
+	GroupsCreated int                    `json:"groups_created"`
+	TagsCreated   int                    `json:"tags_created"`
+	TagsAssigned  int                    `json:"tags_assigned"`
+	Rows          []KeywordImportRowItem `json:"rows"` // Только повторы и ошибочные строки
 }
 
 type CreateGroupRequest struct {
@@ -57,6 +181,145 @@ type GroupResponse struct {
 	SiteID int    `json:"site_id"`
 }
 
+type CreateTagRequest struct {
+	Name   string `json:"name" binding:"required"`
+	SiteID int    `json:"site_id" binding:"required"`
+}
+
+type UpdateTagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type TagResponse struct {
+	ID            int    `json:"id"`
+	SiteID        int    `json:"site_id"`
+	Name          string `json:"name"`
+	KeywordsCount *int   `json:"keywords_count,omitempty"` // Только в списке меток сайта
+}
+
+// AssignTagsRequest назначает или снимает каждую метку у каждого слова; слова и метки должны быть одного сайта
+type AssignTagsRequest struct {
+	KeywordIDs []int `json:"keyword_ids" binding:"required,min=1,max=10000"`
+	TagIDs     []int `json:"tag_ids" binding:"required,min=1,max=100"`
+}
+
+type AssignTagsResponse struct {
+	Count int `json:"count"` // Добавленные или удаленные связи; уже существующие не считаются
+}
+
+// ClusterKeywordsRequest параметры кластеризации слов сайта по общим URL в топ-10 последней сохраненной выдачи
+type ClusterKeywordsRequest struct {
+	Source        string `json:"source" binding:"required,oneof=google yandex"`
+	FilterGroupID *int   `json:"filter_group_id"`                            // Только проверки этой группы фильтров
+	Threshold     int    `json:"threshold" binding:"omitempty,min=1,max=10"` // Общих URL для попадания в кластер, по умолчанию 4
+	Mode          string `json:"mode" binding:"omitempty,oneof=soft hard"`   // soft - похожи на маркер кластера, hard - на каждое слово; по умолчанию soft
+}
+
+type ClusterKeywordsResponse struct {
+	Keywords    int `json:"keywords"`     // Слова с сохраненной выдачей
+	WithoutSerp int `json:"without_serp"` // Слова без сохраненной выдачи
+	Clustered   int `json:"clustered"`
+	Unclustered int `json:"unclustered"` // Слова, для которых не нашлось похожих
+	Clusters    int `json:"clusters"`
+}
+
+type KeywordClusterItem struct {
+	ID              int               `json:"id"`
+	Name            string            `json:"name"`
+	Source          string            `json:"source"`
+	FilterGroupID   *int              `json:"filter_group_id"`
+	Mode            string            `json:"mode"`
+	Threshold       int               `json:"threshold"`
+	MarkerKeywordID *int              `json:"marker_keyword_id"`
+	Keywords        []KeywordResponse `json:"keywords"`
+	CreatedAt       string            `json:"created_at"`
+}
+
+type KeywordClustersRequest struct {
+	Page    int `form:"page" binding:"omitempty,min=1"`
+	PerPage int `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+type KeywordClustersResponse struct {
+	Data       []KeywordClusterItem `json:"data"`
+	Pagination PaginationInfo       `json:"pagination"`
+}
+
+type ApplyKeywordClustersRequest struct {
+	ClusterIDs []int `json:"cluster_ids" binding:"max=1000"` // Пусто - все кластеры сайта
+}
+
+type ApplyKeywordClustersResponse struct {
+	GroupsCreated int `json:"groups_created"`
+	KeywordsMoved int `json:"keywords_moved"`
+}
+
+type CompetitorRequest struct {
+	Domain string `json:"domain" binding:"required"`
+}
+
+type CompetitorResponse struct {
+	ID     int    `json:"id"`
+	SiteID int    `json:"site_id"`
+	Domain string `json:"domain"`
+}
+
+// ProviderAccountRequest учетные данные провайдера. При обновлении пустой api_key оставляет прежний ключ
+type ProviderAccountRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Provider string `json:"provider" binding:"required"`
+	UserID   string `json:"user_id" binding:"required,max=100"`
+	APIKey   string `json:"api_key"`
+	BaseURL  string `json:"base_url" binding:"omitempty,url"`
+}
+
+// ProviderAccountResponse аккаунт провайдера без ключа: ключ после сохранения не возвращается
+type ProviderAccountResponse struct {
+	ID          int       `json:"id"`
+	WorkspaceID int       `json:"workspace_id"`
+	Name        string    `json:"name"`
+	Provider    string    `json:"provider"`
+	UserID      string    `json:"user_id"`
+	BaseURL     string    `json:"base_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1"` // positions:read, keywords:manage, tracking:start, admin
+}
+
+type APIKeyResponse struct {
+	ID          int        `json:"id"`
+	WorkspaceID *int       `json:"workspace_id,omitempty"` // nil - системный ключ
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Scopes      []string   `json:"scopes"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+type WorkspaceRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	MonthlyBudget *float64 `json:"monthly_budget" binding:"omitempty,gte=0"` // Общий месячный бюджет сайтов пространства; null - без ограничения
+}
+
+type WorkspaceResponse struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	MonthlyBudget *float64  `json:"monthly_budget"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CreateAPIKeyResponse содержит ключ в открытом виде; он возвращается только при выпуске
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
 type DeleteKeywordResponse struct {
 	Message string `json:"message"`
 }
@@ -87,11 +350,10 @@ type TrackGooglePositionsRequest struct {
 	Country       string `json:"country"`
 	Lang          string `json:"lang"`
 	Subdomains    bool   `json:"subdomains"`
-	XMLUserID     string `json:"xml_user_id"`
-	XMLAPIKey     string `json:"xml_api_key"`
-	XMLBaseURL    string `json:"xml_base_url"`
+	AccountID     *int   `json:"account_id"` // Аккаунт провайдера из /api/provider-accounts
+	Provider      string `json:"provider"`
 	TBS           string `json:"tbs"`
-	Filter        int    `json:"filter"`
+	Filter        *int   `json:"filter"`
 	Highlights    int    `json:"highlights"`
 	NFPR          int    `json:"nfpr"`
 	Loc           int    `json:"loc"`
@@ -99,6 +361,7 @@ type TrackGooglePositionsRequest struct {
 	Raw           string `json:"raw"`
 	LR            int    `json:"lr"`
 	Domain        int    `json:"domain"`
+	Strict        int    `json:"strict"`
 	FilterGroupID *int   `json:"filter_group_id"`
 }
 
@@ -111,14 +374,14 @@ type TrackYandexPositionsRequest struct {
 	Country       string `json:"country"`
 	Lang          string `json:"lang"`
 	Subdomains    bool   `json:"subdomains"`
-	XMLUserID     string `json:"xml_user_id"`
-	XMLAPIKey     string `json:"xml_api_key"`
-	XMLBaseURL    string `json:"xml_base_url"`
+	AccountID     *int   `json:"account_id"` // Аккаунт провайдера из /api/provider-accounts
+	Provider      string `json:"provider"`
 	GroupBy       int    `json:"groupby"`
-	Filter        int    `json:"filter"`
+	Filter        *int   `json:"filter"`
 	Highlights    int    `json:"highlights"`
 	Within        int    `json:"within"`
 	LR            int    `json:"lr"`
+	Domain        string `json:"domain" binding:"omitempty,oneof=ru com ua com.tr by kz"` // Домен Яндекса
 	Raw           string `json:"raw"`
 	InIndex       int    `json:"inindex"`
 	Strict        int    `json:"strict"`
@@ -126,16 +389,214 @@ type TrackYandexPositionsRequest struct {
 	FilterGroupID *int   `json:"filter_group_id"`
 }
 
+type SerpItemResponse struct {
+	Place       int    `json:"place"`
+	Rank        int    `json:"rank"`
+	URL         string `json:"url"`
+	Domain      string `json:"domain"`
+	Title       string `json:"title"`
+	Passage     string `json:"passage"`
+	ContentType string `json:"content_type"`
+}
+
+type SerpSnapshotResponse struct {
+	PositionID int                `json:"position_id"`
+	KeywordID  int                `json:"keyword_id"`
+	SiteID     int                `json:"site_id"`
+	Source     string             `json:"source"`
+	Date       time.Time          `json:"date"`
+	Items      []SerpItemResponse `json:"items"`
+}
+
+type ProviderResponse struct {
+	Name           string   `json:"name"`
+	Sources        []string `json:"sources"`
+	MaxPages       int      `json:"max_pages"`
+	GroupBy        bool     `json:"groupby"`
+	CostPerRequest float64  `json:"cost_per_request"`
+	Default        bool     `json:"default"`
+}
+
+type ProviderLimitsResponse struct {
+	Provider          string    `json:"provider"`
+	Account           string    `json:"account"`             // user id аккаунта провайдера
+	RequestsPerSecond float64   `json:"requests_per_second"` // 0 - без ограничения
+	MaxInFlight       int       `json:"max_in_flight"`       // 0 - без ограничения
+	DailyQuota        int       `json:"daily_quota"`         // 0 - без ограничения
+	InFlight          int       `json:"in_flight"`           // Запросов выполняется сейчас
+	Waiting           int       `json:"waiting"`             // Запросов ждут лимита
+	UsedToday         int       `json:"used_today"`          // Запросов отправлено за сутки
+	RemainingToday    *int      `json:"remaining_today,omitempty"`
+	QuotaResetAt      time.Time `json:"quota_reset_at"`
+}
+
+type UsageRequest struct {
+	SiteID   *int    `form:"site_id"`
+	Source   *string `form:"source" binding:"omitempty,oneof=google yandex wordstat"`
+	DateFrom *string `form:"date_from"` // YYYY-MM-DD, по умолчанию начало текущего месяца
+	DateTo   *string `form:"date_to"`   // YYYY-MM-DD включительно, по умолчанию сегодня
+}
+
+type UsageItem struct {
+	Date     string  `json:"date"`
+	SiteID   int     `json:"site_id"`
+	Source   string  `json:"source"`
+	Requests int     `json:"requests"`
+	Cost     float64 `json:"cost"`
+}
+
+type UsageTotals struct {
+	Requests int     `json:"requests"`
+	Cost     float64 `json:"cost"`
+}
+
+type UsageResponse struct {
+	Data   []UsageItem `json:"data"`
+	Totals UsageTotals `json:"totals"`
+}
+
+type VisibilityRequest struct {
+	SiteID        int     `form:"site_id" binding:"required"`
+	Source        *string `form:"source" binding:"omitempty,oneof=google yandex"`
+	GroupID       *int    `form:"group_id"`        // Без группы - все ключевые слова сайта
+	FilterGroupID *int    `form:"filter_group_id"` // Без группы фильтров - позиции, снятые без нее
+	DateFrom      *string `form:"date_from"`       // YYYY-MM-DD, по умолчанию 29 дней до date_to
+	DateTo        *string `form:"date_to"`         // YYYY-MM-DD включительно, по умолчанию сегодня
+}
+
+type VisibilityPoint struct {
+	Date             string  `json:"date"`
+	Source           string  `json:"source"`
+	KeywordsCount    int     `json:"keywords_count"`
+	FoundCount       int     `json:"found_count"`
+	Top10Count       int     `json:"top10_count"`
+	Frequency        int64   `json:"frequency"`         // Суммарная частота Wordstat ключевых слов
+	EstimatedTraffic float64 `json:"estimated_traffic"` // Сумма частота * CTR позиции
+	Visibility       float64 `json:"visibility"`        // Процент от трафика при первых позициях по всем ключевым словам
+}
+
+type VisibilityResponse struct {
+	SiteID        int               `json:"site_id"`
+	GroupID       *int              `json:"group_id,omitempty"`
+	FilterGroupID *int              `json:"filter_group_id,omitempty"`
+	Data          []VisibilityPoint `json:"data"`
+}
+
+type MovementRequest struct {
+	SiteID        int     `form:"site_id" binding:"required"`
+	Source        string  `form:"source" binding:"required,oneof=google yandex"`
+	GroupID       *int    `form:"group_id"`        // Без группы - все ключевые слова сайта
+	FilterGroupID *int    `form:"filter_group_id"` // Без группы фильтров - позиции, снятые без нее
+	DateFrom      *string `form:"date_from"`       // YYYY-MM-DD, по умолчанию предыдущая проверка перед date_to
+	DateTo        *string `form:"date_to"`         // YYYY-MM-DD, по умолчанию последняя проверка
+	Status        string  `form:"status" binding:"omitempty,oneof=up down new lost unchanged"`
+}
+
+type MovementSummary struct {
+	PreviousDate string `json:"previous_date"`
+	CurrentDate  string `json:"current_date"`
+	Up           int    `json:"up"`
+	Down         int    `json:"down"`
+	New          int    `json:"new"`
+	Lost         int    `json:"lost"`
+	Unchanged    int    `json:"unchanged"`
+	NetScore     int    `json:"net_score"` // Сумма изменений позиций; "не найдено" считается 101 позицией
+}
+
+type GroupMovementItem struct {
+	GroupID *int            `json:"group_id"` // null - ключевые слова без группы
+	Summary MovementSummary `json:"summary"`
+}
+
+type KeywordMovementItem struct {
+	KeywordID    int    `json:"keyword_id"`
+	Keyword      string `json:"keyword"`
+	GroupID      *int   `json:"group_id"`
+	PreviousRank int    `json:"previous_rank"` // 0 - не найдено или не проверялось
+	CurrentRank  int    `json:"current_rank"`  // 0 - не найдено
+	Delta        int    `json:"delta"`         // Положительное - слово поднялось
+	Status       string `json:"status"`        // up, down, new, lost, unchanged
+}
+
+type MovementResponse struct {
+	SiteID        int                   `json:"site_id"`
+	Source        string                `json:"source"`
+	GroupID       *int                  `json:"group_id,omitempty"`
+	FilterGroupID *int                  `json:"filter_group_id,omitempty"`
+	Summary       *MovementSummary      `json:"summary"` // null - сравнивать не с чем
+	Groups        []GroupMovementItem   `json:"groups"`
+	Keywords      []KeywordMovementItem `json:"keywords"`
+}
+
+type LandingPageRequest struct {
+	Source   *string `form:"source" binding:"omitempty,oneof=google yandex"`
+	GroupID  *int    `form:"group_id"`  // Без группы - все ключевые слова сайта
+	DateFrom *string `form:"date_from"` // YYYY-MM-DD, по умолчанию 29 дней до date_to
+	DateTo   *string `form:"date_to"`   // YYYY-MM-DD включительно, по умолчанию сегодня
+	Page     int     `form:"page" binding:"omitempty,min=1"`
+	PerPage  int     `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+type URLRankItem struct {
+	Date string `json:"date"`
+	Rank int    `json:"rank"`
+}
+
+type URLHistoryItem struct {
+	URL       string        `json:"url"`
+	FirstSeen string        `json:"first_seen"`
+	LastSeen  string        `json:"last_seen"`
+	BestRank  int           `json:"best_rank"`
+	Ranks     []URLRankItem `json:"ranks"`
+}
+
+type URLChangeItem struct {
+	Date         string `json:"date"`
+	PreviousURL  string `json:"previous_url"`
+	URL          string `json:"url"`
+	PreviousRank int    `json:"previous_rank"`
+	Rank         int    `json:"rank"`
+}
+
+type KeywordURLChangesItem struct {
+	KeywordID     int              `json:"keyword_id"`
+	Keyword       string           `json:"keyword"`
+	GroupID       *int             `json:"group_id"`
+	Source        string           `json:"source"`
+	FilterGroupID *int             `json:"filter_group_id,omitempty"`
+	Changes       []URLChangeItem  `json:"changes"`
+	URLs          []URLHistoryItem `json:"urls"`
+}
+
+type URLChangesResponse struct {
+	Data       []KeywordURLChangesItem `json:"data"`
+	Pagination PaginationInfo          `json:"pagination"`
+}
+
+type CannibalizationItem struct {
+	KeywordID     int              `json:"keyword_id"`
+	Keyword       string           `json:"keyword"`
+	GroupID       *int             `json:"group_id"`
+	Source        string           `json:"source"`
+	FilterGroupID *int             `json:"filter_group_id,omitempty"`
+	Rotating      bool             `json:"rotating"`      // Ранжирующийся URL возвращался к ранжировавшемуся раньше
+	SharedChecks  int              `json:"shared_checks"` // Проверок, где в выдаче было несколько URL сайта
+	URLs          []URLHistoryItem `json:"urls"`
+}
+
+type CannibalizationResponse struct {
+	Data       []CannibalizationItem `json:"data"`
+	Pagination PaginationInfo        `json:"pagination"`
+}
+
 type TrackWordstatPositionsRequest struct {
-	SiteID                 int    `json:"site_id" binding:"required"`
-	XMLUserID              string `json:"xml_user_id"`
-	XMLAPIKey              string `json:"xml_api_key"`
-	XMLBaseURL             string `json:"xml_base_url"`
-	Regions                *int   `json:"regions"`
-	Default                *bool  `json:"default"`
-	Quotes                 *bool  `json:"quotes"`
-	QuotesExclamationMarks *bool  `json:"quotes_exclamation_marks"`
-	ExclamationMarks       *bool  `json:"exclamation_marks"`
+	SiteID                 int   `json:"site_id" binding:"required"`
+	AccountID              *int  `json:"account_id"` // Аккаунт xmlriver из /api/provider-accounts
+	Regions                *int  `json:"regions"`
+	Default                *bool `json:"default"`
+	Quotes                 *bool `json:"quotes"`
+	QuotesExclamationMarks *bool `json:"quotes_exclamation_marks"`
+	ExclamationMarks       *bool `json:"exclamation_marks"`
 }
 
 type PositionResponse struct {
@@ -177,6 +638,11 @@ type PositionHistoryItem struct {
 	Device    string    `json:"device"`
 	Country   string    `json:"country"`
 	Lang      string    `json:"lang"`
+	// Заполнены у старых позиций, свернутых в агрегаты: period - day или week,
+	// rank и date - последняя проверка периода, id равен 0
+	Period  string   `json:"period,omitempty"`
+	AvgRank *float64 `json:"avg_rank,omitempty"`
+	Samples int      `json:"samples,omitempty"`
 }
 
 type PaginationInfo struct {
@@ -195,14 +661,16 @@ type MetaInfo struct {
 }
 
 type PositionHistoryRequest struct {
-	SiteID    int     `form:"site_id" binding:"required"`
-	KeywordID *int    `form:"keyword_id"`
-	Source    *string `form:"source"`
-	DateFrom  *string `form:"date_from"`
-	DateTo    *string `form:"date_to"`
-	Last      *bool   `form:"last"`
-	Page      int     `form:"page" binding:"omitempty,min=1"`
-	PerPage   int     `form:"per_page" binding:"omitempty,min=1,max=100"`
+	SiteID    int      `form:"site_id" binding:"required"`
+	KeywordID *int     `form:"keyword_id"`
+	Source    *string  `form:"source"`
+	DateFrom  *string  `form:"date_from"`
+	DateTo    *string  `form:"date_to"`
+	Last      *bool    `form:"last"`
+	Tags      []string `form:"tags"` // ID меток через запятую или повтором параметра
+	TagsMatch *string  `form:"tags_match" binding:"omitempty,oneof=any all"`
+	Page      int      `form:"page" binding:"omitempty,min=1"`
+	PerPage   int      `form:"per_page" binding:"omitempty,min=1,max=100"`
 }
 
 type TrackPositionsResponse struct {
@@ -222,6 +690,9 @@ type PositionStatisticsRequest struct {
 	DateTo        string `json:"date_to" binding:"required"`
 	Source        string `json:"source" binding:"required,oneof=google yandex wordstat"`
 	FilterGroupID *int   `json:"filter_group_id"`
+	CompetitorID  *int   `json:"competitor_id"` // Статистика конкурента вместо сайта
+	Tags          []int  `json:"tags"`
+	TagsMatch     string `json:"tags_match" binding:"omitempty,oneof=any all"` // По умолчанию any
 }
 
 type PositionStatisticsResponse struct {
@@ -258,21 +729,24 @@ type Trends struct {
 }
 
 type CombinedPositionsRequest struct {
-	SiteID            int     `form:"site_id" binding:"required"`
-	Source            *string `form:"source" binding:"omitempty,oneof=google yandex"`
-	Wordstat          *bool   `form:"wordstat"`
-	WordstatSort      *string `form:"wordstat_sort" binding:"omitempty,oneof=asc desc"`
-	DateFrom          *string `form:"date_from"`
-	DateTo            *string `form:"date_to"`
-	DateSort          *string `form:"date_sort"`
-	SortType          *string `form:"sort_type" binding:"omitempty,oneof=asc desc"`
-	RankFrom          *int    `form:"rank_from" binding:"omitempty,min=0"`
-	RankTo            *int    `form:"rank_to" binding:"omitempty,min=0"`
-	Page              int     `form:"page" binding:"omitempty,min=1"`
-	PerPage           int     `form:"per_page" binding:"omitempty,min=1,max=100"`
-	GroupID           *int    `form:"group_id"`
-	FilterGroupID     *int    `form:"filter_group_id"`
-	WordstatQueryType *string `form:"wordstat_query_type" binding:"omitempty,oneof=default quotes quotes_exclamation_marks exclamation_marks"`
+	SiteID            int      `form:"site_id" binding:"required"`
+	Source            *string  `form:"source" binding:"omitempty,oneof=google yandex"`
+	Wordstat          *bool    `form:"wordstat"`
+	WordstatSort      *string  `form:"wordstat_sort" binding:"omitempty,oneof=asc desc"`
+	DateFrom          *string  `form:"date_from"`
+	DateTo            *string  `form:"date_to"`
+	DateSort          *string  `form:"date_sort"`
+	SortType          *string  `form:"sort_type" binding:"omitempty,oneof=asc desc"`
+	RankFrom          *int     `form:"rank_from" binding:"omitempty,min=0"`
+	RankTo            *int     `form:"rank_to" binding:"omitempty,min=0"`
+	Page              int      `form:"page" binding:"omitempty,min=1"`
+	PerPage           int      `form:"per_page" binding:"omitempty,min=1,max=100"`
+	GroupID           *int     `form:"group_id"`
+	FilterGroupID     *int     `form:"filter_group_id"`
+	CompetitorID      *int     `form:"competitor_id"`
+	Tags              []string `form:"tags"`
+	TagsMatch         *string  `form:"tags_match" binding:"omitempty,oneof=any all"`
+	WordstatQueryType *string  `form:"wordstat_query_type" binding:"omitempty,oneof=default quotes quotes_exclamation_marks exclamation_marks"`
 }
 
 type CombinedPositionsResponse struct {
@@ -293,9 +767,68 @@ type CombinedPositionItem struct {
 	Wordstat *PositionData `json:"wordstat"`
 }
 
+// TrackingParams параметры запуска, те же, что у track-google, track-yandex и track-wordstat
+type TrackingParams struct {
+	Pages                  int    `json:"pages" binding:"omitempty,min=1,max=10"`
+	Device                 string `json:"device" binding:"omitempty,oneof=desktop tablet mobile"`
+	OS                     string `json:"os" binding:"omitempty,oneof=ios android"`
+	Ads                    bool   `json:"ads"`
+	Country                string `json:"country"`
+	Lang                   string `json:"lang"`
+	Subdomains             bool   `json:"subdomains"`
+	AccountID              *int   `json:"account_id"`
+	Provider               string `json:"provider"`
+	TBS                    string `json:"tbs"`
+	Filter                 *int   `json:"filter"`
+	Highlights             int    `json:"highlights"`
+	NFPR                   int    `json:"nfpr"`
+	Loc                    int    `json:"loc"`
+	AI                     int    `json:"ai"`
+	Raw                    string `json:"raw"`
+	GroupBy                int    `json:"groupby"`
+	Within                 int    `json:"within"`
+	LR                     int    `json:"lr"`
+	Domain                 int    `json:"domain"`
+	YandexDomain           string `json:"yandex_domain" binding:"omitempty,oneof=ru com ua com.tr by kz"` // Домен Яндекса; domain - ID домена Google
+	InIndex                int    `json:"inindex"`
+	Strict                 int    `json:"strict"`
+	Organic                bool   `json:"organic"`
+	Regions                *int   `json:"regions"`
+	FilterGroupID          *int   `json:"filter_group_id"`
+	Default                *bool  `json:"default"`
+	Quotes                 bool   `json:"quotes"`
+	QuotesExclamationMarks bool   `json:"quotes_exclamation_marks"`
+	ExclamationMarks       bool   `json:"exclamation_marks"`
+}
+
+type TrackingScheduleRequest struct {
+	SiteID   int            `json:"site_id" binding:"required"`
+	Source   string         `json:"source" binding:"required,oneof=google yandex wordstat"`
+	CronExpr string         `json:"cron_expr" binding:"required"`
+	Timezone string         `json:"timezone"` // IANA, по умолчанию UTC
+	Enabled  *bool          `json:"enabled"`  // По умолчанию true
+	Params   TrackingParams `json:"params"`
+}
+
+type TrackingScheduleResponse struct {
+	ID        int            `json:"id"`
+	SiteID    int            `json:"site_id"`
+	Source    string         `json:"source"`
+	CronExpr  string         `json:"cron_expr"`
+	Timezone  string         `json:"timezone"`
+	Enabled   bool           `json:"enabled"`
+	Params    TrackingParams `json:"params"`
+	LastRunAt *time.Time     `json:"last_run_at,omitempty"`
+	NextRunAt *time.Time     `json:"next_run_at,omitempty"`
+	LastJobID string         `json:"last_job_id,omitempty"`
+	LastError string         `json:"last_error,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
 type TrackingJobsRequest struct {
 	SiteID  *int    `form:"site_id"`
-	Status  *string `form:"status" binding:"omitempty,oneof=pending running completed failed cancelled"`
+	Status  *string `form:"status" binding:"omitempty,oneof=pending running paused completed failed cancelled"`
 	Page    int     `form:"page" binding:"omitempty,min=1"`
 	PerPage int     `form:"per_page" binding:"omitempty,min=1,max=100"`
 }
@@ -318,7 +851,41 @@ type TrackingJobItem struct {
 	CompletedTasks int        `json:"completed_tasks"`
 	FailedTasks    int        `json:"failed_tasks"`
 	Error          string     `json:"error,omitempty"`
-	Progress       float64    `json:"progress"` // Процент выполнения
+	APIKeyID       *int       `json:"api_key_id,omitempty"` // Ключ API, которым запущен джоб
+	Progress       float64    `json:"progress"`             // Процент выполнения
+}
+
+type TrackingJobDetailRequest struct {
+	Status  *string `form:"status" binding:"omitempty,oneof=pending completed failed cancelled"`
+	Page    int     `form:"page" binding:"omitempty,min=1"`
+	PerPage int     `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+type TrackingJobDetailResponse struct {
+	TrackingJobItem
+	FailedRequests  int                        `json:"failed_requests"`
+	StartedAt       *time.Time                 `json:"started_at,omitempty"`
+	DurationSeconds *float64                   `json:"duration_seconds,omitempty"` // От первого запуска до завершения или до текущего момента
+	Provider        string                     `json:"provider,omitempty"`
+	Requests        int                        `json:"requests"` // Платных запросов к провайдеру по журналу расхода
+	Cost            float64                    `json:"cost"`
+	Params          TrackingParams             `json:"params"`
+	Results         []TrackingJobKeywordResult `json:"results"`
+	Pagination      PaginationInfo             `json:"pagination"`
+}
+
+type TrackingJobKeywordResult struct {
+	TaskID      string     `json:"task_id"`
+	KeywordID   int        `json:"keyword_id"`
+	Keyword     string     `json:"keyword"`
+	QueryType   string     `json:"query_type,omitempty"` // Тип запроса Wordstat
+	Status      string     `json:"status"`
+	Rank        *int       `json:"rank,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Attempts    int        `json:"attempts"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
 }
 
 type PositionData struct {
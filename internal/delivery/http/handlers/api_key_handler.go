@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyUseCase *usecases.APIKeyUseCase
+}
+
+func NewAPIKeyHandler(apiKeyUseCase *usecases.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// GetAPIKeys godoc
+// @Summary Получить ключи API
+// @Description Возвращает выпущенные ключи, включая отозванные. Сами ключи не возвращаются
+// @Tags api-keys
+// @Produce json
+// @Success 200 {array} dto.APIKeyResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyUseCase.GetKeys(middleware.WorkspaceID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := make([]dto.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		response[i] = toAPIKeyResponse(key)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateAPIKey godoc
+// @Summary Выпустить ключ API
+// @Description Создает ключ с указанными правами. Ключ возвращается в поле key только в этом ответе, в БД хранится его хеш
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param key body dto.CreateAPIKeyRequest true "Имя и права ключа"
+// @Success 201 {object} dto.CreateAPIKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	key, raw, err := h.apiKeyUseCase.IssueKey(middleware.WorkspaceID(c), req.Name, req.Scopes)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            raw,
+	})
+}
+
+// RevokeAPIKey godoc
+// @Summary Отозвать ключ API
+// @Description Ключ перестает приниматься сразу; запись остается, чтобы по джобам было видно, кто их запускал
+// @Tags api-keys
+// @Produce json
+// @Param id path int true "ID ключа"
+// @Success 200 {object} dto.APIKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/api-keys/{id}/revoke [post]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid API key ID",
+		})
+		return
+	}
+
+	key, err := h.apiKeyUseCase.RevokeKey(middleware.WorkspaceID(c), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAPIKeyResponse(key))
+}
+
+func (h *APIKeyHandler) handleError(c *gin.Context, err error) {
+	if !usecases.IsDomainError(err) {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	code := usecases.GetDomainErrorCode(err)
+	status := http.StatusInternalServerError
+
+	switch code {
+	case usecases.ErrorValidation:
+		status = http.StatusBadRequest
+	case usecases.ErrorAPIKeyNotFound:
+		status = http.StatusNotFound
+	case usecases.ErrorAPIKeyRevoked:
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Error:   code,
+		Message: err.Error(),
+	})
+}
+
+func toAPIKeyResponse(key *entities.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:          key.ID,
+		WorkspaceID: key.WorkspaceID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Scopes:      key.Scopes,
+		CreatedAt:   key.CreatedAt,
+		LastUsedAt:  key.LastUsedAt,
+		RevokedAt:   key.RevokedAt,
+	}
+}
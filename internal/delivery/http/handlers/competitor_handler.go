@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CompetitorHandler struct {
+	competitorUseCase usecases.CompetitorUseCaseInterface
+}
+
+func NewCompetitorHandler(competitorUseCase usecases.CompetitorUseCaseInterface) *CompetitorHandler {
+	return &CompetitorHandler{
+		competitorUseCase: competitorUseCase,
+	}
+}
+
+// GetCompetitors godoc
+// @Summary Get site competitors
+// @Description Get list of competitor domains tracked together with the site
+// @Tags competitors
+// @Produce json
+// @Param id path int true "Site ID"
+// @Success 200 {array} dto.CompetitorResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/competitors [get]
+func (h *CompetitorHandler) GetCompetitors(c *gin.Context) {
+	siteID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid site ID",
+		})
+		return
+	}
+
+	competitors, err := h.competitorUseCase.GetCompetitorsBySite(middleware.WorkspaceID(c), siteID)
+	if err != nil {
+		if usecases.IsDomainError(err) {
+			code := usecases.GetDomainErrorCode(err)
+			status := http.StatusInternalServerError
+
+			switch code {
+			case usecases.ErrorSiteNotFound:
+				status = http.StatusNotFound
+			case usecases.ErrorCompetitorFetch:
+				status = http.StatusInternalServerError
+			}
+
+			c.JSON(status, dto.ErrorResponse{
+				Error:   code,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	response := make([]dto.CompetitorResponse, len(competitors))
+	for i, competitor := range competitors {
+		response[i] = dto.CompetitorResponse{
+			ID:     competitor.ID,
+			SiteID: competitor.SiteID,
+			Domain: competitor.Domain,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateCompetitor godoc
+// @Summary Add a competitor
+// @Description Add a competitor domain; its positions are recorded from the same SERP requests as the site
+// @Tags competitors
+// @Accept json
+// @Produce json
+// @Param id path int true "Site ID"
+// @Param competitor body dto.CompetitorRequest true "Competitor data"
+// @Success 201 {object} dto.CompetitorResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/competitors [post]
+func (h *CompetitorHandler) CreateCompetitor(c *gin.Context) {
+	siteID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid site ID",
+		})
+		return
+	}
+
+	var req dto.CompetitorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	competitor, err := h.competitorUseCase.CreateCompetitor(middleware.WorkspaceID(c), siteID, req.Domain)
+	if err != nil {
+		if usecases.IsDomainError(err) {
+			code := usecases.GetDomainErrorCode(err)
+			status := http.StatusInternalServerError
+
+			switch code {
+			case usecases.ErrorValidation:
+				status = http.StatusBadRequest
+			case usecases.ErrorSiteNotFound:
+				status = http.StatusNotFound
+			case usecases.ErrorCompetitorExists:
+				status = http.StatusConflict
+			case usecases.ErrorCompetitorCreation:
+				status = http.StatusInternalServerError
+			}
+
+			c.JSON(status, dto.ErrorResponse{
+				Error:   code,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CompetitorResponse{
+		ID:     competitor.ID,
+		SiteID: competitor.SiteID,
+		Domain: competitor.Domain,
+	})
+}
+
+// UpdateCompetitor godoc
+// @Summary Update a competitor
+// @Description Change competitor domain
+// @Tags competitors
+// @Accept json
+// @Produce json
+// @Param id path int true "Site ID"
+// @Param competitor_id path int true "Competitor ID"
+// @Param competitor body dto.CompetitorRequest true "Competitor data"
+// @Success 200 {object} dto.CompetitorResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/competitors/{competitor_id} [put]
+func (h *CompetitorHandler) UpdateCompetitor(c *gin.Context) {
+	siteID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid site ID",
+		})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("competitor_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid competitor ID",
+		})
+		return
+	}
+
+	var req dto.CompetitorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	competitor, err := h.competitorUseCase.UpdateCompetitor(middleware.WorkspaceID(c), siteID, id, req.Domain)
+	if err != nil {
+		if usecases.IsDomainError(err) {
+			code := usecases.GetDomainErrorCode(err)
+			status := http.StatusInternalServerError
+
+			switch code {
+			case usecases.ErrorValidation:
+				status = http.StatusBadRequest
+			case usecases.ErrorSiteNotFound, usecases.ErrorCompetitorNotFound:
+				status = http.StatusNotFound
+			case usecases.ErrorCompetitorExists:
+				status = http.StatusConflict
+			case usecases.ErrorCompetitorUpdate:
+				status = http.StatusInternalServerError
+			}
+
+			c.JSON(status, dto.ErrorResponse{
+				Error:   code,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CompetitorResponse{
+		ID:     competitor.ID,
+		SiteID: competitor.SiteID,
+		Domain: competitor.Domain,
+	})
+}
+
+// DeleteCompetitor godoc
+// @Summary Delete a competitor
+// @Description Delete a competitor together with its positions
+// @Tags competitors
+// @Param id path int true "Site ID"
+// @Param competitor_id path int true "Competitor ID"
+// @Success 200 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/competitors/{competitor_id} [delete]
+func (h *CompetitorHandler) DeleteCompetitor(c *gin.Context) {
+	siteID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid site ID",
+		})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("competitor_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid competitor ID",
+		})
+		return
+	}
+
+	if err := h.competitorUseCase.DeleteCompetitor(middleware.WorkspaceID(c), siteID, id); err != nil {
+		if usecases.IsDomainError(err) {
+			code := usecases.GetDomainErrorCode(err)
+			status := http.StatusInternalServerError
+
+			switch code {
+			case usecases.ErrorSiteNotFound, usecases.ErrorCompetitorNotFound:
+				status = http.StatusNotFound
+			case usecases.ErrorCompetitorDeletion, usecases.ErrorPositionDeletion:
+				status = http.StatusInternalServerError
+			}
+
+			c.JSON(status, dto.ErrorResponse{
+				Error:   code,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ErrorResponse{
+		Error:   "success",
+		Message: "Competitor deleted successfully",
+	})
+}
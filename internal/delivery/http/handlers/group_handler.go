@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
 	"go-seo/internal/usecases"
 
 	"github.com/gin-gonic/gin"
@@ -29,6 +30,7 @@ func NewGroupHandler(groupUseCase usecases.GroupUseCaseInterface) *GroupHandler
 // @Param group body dto.CreateGroupRequest true "Group data"
 // @Success 201 {object} dto.GroupResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/groups [post]
 func (h *GroupHandler) CreateGroup(c *gin.Context) {
@@ -41,13 +43,15 @@ func (h *GroupHandler) CreateGroup(c *gin.Context) {
 		return
 	}
 
-	group, err := h.groupUseCase.CreateGroup(req.Name, req.SiteID)
+	group, err := h.groupUseCase.CreateGroup(middleware.WorkspaceID(c), req.Name, req.SiteID)
 	if err != nil {
 		if usecases.IsDomainError(err) {
 			code := usecases.GetDomainErrorCode(err)
 			status := http.StatusInternalServerError
 
 			switch code {
+			case usecases.ErrorSiteNotFound:
+				status = http.StatusNotFound
 			case usecases.ErrorGroupExists:
 				status = http.StatusConflict
 			case usecases.ErrorGroupCreation:
@@ -108,7 +112,7 @@ func (h *GroupHandler) UpdateGroup(c *gin.Context) {
 		return
 	}
 
-	group, err := h.groupUseCase.UpdateGroup(id, req.Name)
+	group, err := h.groupUseCase.UpdateGroup(middleware.WorkspaceID(c), id, req.Name)
 	if err != nil {
 		if usecases.IsDomainError(err) {
 			code := usecases.GetDomainErrorCode(err)
@@ -163,7 +167,7 @@ func (h *GroupHandler) DeleteGroup(c *gin.Context) {
 		return
 	}
 
-	err = h.groupUseCase.DeleteGroup(id)
+	err = h.groupUseCase.DeleteGroup(middleware.WorkspaceID(c), id)
 	if err != nil {
 		if usecases.IsDomainError(err) {
 			code := usecases.GetDomainErrorCode(err)
@@ -204,6 +208,7 @@ func (h *GroupHandler) DeleteGroup(c *gin.Context) {
 // @Param site_id query int true "Site ID"
 // @Success 200 {array} dto.GroupResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/groups [get]
 func (h *GroupHandler) GetGroups(c *gin.Context) {
@@ -225,10 +230,14 @@ func (h *GroupHandler) GetGroups(c *gin.Context) {
 		return
 	}
 
-	groups, err := h.groupUseCase.GetGroupsBySite(siteID)
+	groups, err := h.groupUseCase.GetGroupsBySite(middleware.WorkspaceID(c), siteID)
 	if err != nil {
 		if usecases.IsDomainError(err) {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			status := http.StatusInternalServerError
+			if usecases.GetDomainErrorCode(err) == usecases.ErrorSiteNotFound {
+				status = http.StatusNotFound
+			}
+			c.JSON(status, dto.ErrorResponse{
 				Error:   usecases.GetDomainErrorCode(err),
 				Message: err.Error(),
 			})
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KeywordClusterHandler struct {
+	clusterUseCase *usecases.KeywordClusterUseCase
+}
+
+func NewKeywordClusterHandler(clusterUseCase *usecases.KeywordClusterUseCase) *KeywordClusterHandler {
+	return &KeywordClusterHandler{
+		clusterUseCase: clusterUseCase,
+	}
+}
+
+// ClusterKeywords godoc
+// @Summary Кластеризовать ключевые слова по выдаче
+// @Description Группирует слова сайта по числу общих URL в топ-10 последней сохраненной выдачи источника и заменяет прежние кластеры сайта. Маркерами становятся слова с наибольшим числом похожих; в режиме soft слово должно быть похоже на маркер, в режиме hard - на каждое слово кластера. Слова без похожих остаются без кластера. Группы не меняются, для переноса в группы есть /clusters/apply
+// @Tags sites
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сайта"
+// @Param request body dto.ClusterKeywordsRequest true "Параметры кластеризации"
+// @Success 200 {object} dto.ClusterKeywordsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/clusters [post]
+func (h *KeywordClusterHandler) ClusterKeywords(c *gin.Context) {
+	siteID, ok := parseSiteID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ClusterKeywordsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.clusterUseCase.ClusterKeywords(middleware.WorkspaceID(c), siteID, req.Source, req.FilterGroupID, req.Threshold, req.Mode)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ClusterKeywordsResponse{
+		Keywords:    result.Keywords,
+		WithoutSerp: result.WithoutSerp,
+		Clustered:   result.Keywords - result.Unclustered,
+		Unclustered: result.Unclustered,
+		Clusters:    len(result.Clusters),
+	})
+}
+
+// GetClusters godoc
+// @Summary Получить кластеры ключевых слов
+// @Description Кластеры последней кластеризации сайта по убыванию размера со словами
+// @Tags sites
+// @Produce json
+// @Param id path int true "ID сайта"
+// @Param page query int false "Страница (по умолчанию 1)"
+// @Param per_page query int false "Кластеров на странице (по умолчанию 20, не больше 100)"
+// @Success 200 {object} dto.KeywordClustersResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/clusters [get]
+func (h *KeywordClusterHandler) GetClusters(c *gin.Context) {
+	siteID, ok := parseSiteID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.KeywordClustersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PerPage <= 0 {
+		req.PerPage = 20
+	}
+
+	clusters, total, err := h.clusterUseCase.GetClusters(middleware.WorkspaceID(c), siteID, req.Page, req.PerPage)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.KeywordClustersResponse{
+		Data:       make([]dto.KeywordClusterItem, len(clusters)),
+		Pagination: landingPagePagination(req.Page, req.PerPage, total),
+	}
+	for i, cluster := range clusters {
+		keywords := make([]dto.KeywordResponse, len(cluster.Keywords))
+		for j, keyword := range cluster.Keywords {
+			keywords[j] = toKeywordResponse(keyword)
+		}
+		response.Data[i] = dto.KeywordClusterItem{
+			ID:              cluster.ID,
+			Name:            cluster.Name,
+			Source:          cluster.Source,
+			FilterGroupID:   cluster.FilterGroupID,
+			Mode:            cluster.Mode,
+			Threshold:       cluster.Threshold,
+			MarkerKeywordID: cluster.MarkerKeywordID,
+			Keywords:        keywords,
+			CreatedAt:       cluster.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ApplyClusters godoc
+// @Summary Перенести кластеры в группы
+// @Description Переносит слова кластеров в группы с именами кластеров; недостающие группы создаются, одноименные существующие используются. Без cluster_ids применяются все кластеры сайта
+// @Tags sites
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сайта"
+// @Param request body dto.ApplyKeywordClustersRequest false "Кластеры"
+// @Success 200 {object} dto.ApplyKeywordClustersResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/clusters/apply [post]
+func (h *KeywordClusterHandler) ApplyClusters(c *gin.Context) {
+	siteID, ok := parseSiteID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ApplyKeywordClustersRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	result, err := h.clusterUseCase.ApplyClusters(middleware.WorkspaceID(c), siteID, req.ClusterIDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ApplyKeywordClustersResponse{
+		GroupsCreated: result.GroupsCreated,
+		KeywordsMoved: result.KeywordsMoved,
+	})
+}
+
+func (h *KeywordClusterHandler) handleError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch usecases.GetDomainErrorCode(err) {
+	case usecases.ErrorValidation:
+		status = http.StatusBadRequest
+	case usecases.ErrorSiteNotFound, usecases.ErrorClusterNotFound:
+		status = http.StatusNotFound
+	}
+	c.JSON(status, dto.ErrorResponse{
+		Error:   usecases.GetDomainErrorCode(err),
+		Message: err.Error(),
+	})
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"io"
 	"net/http"
 	"strconv"
 
 	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
 	"go-seo/internal/domain/entities"
 	"go-seo/internal/usecases"
 
@@ -30,6 +32,7 @@ func NewKeywordHandler(keywordUseCase usecases.KeywordUseCaseInterface) *Keyword
 // @Param keyword body dto.CreateKeywordRequest true "Keyword data"
 // @Success 201 {object} dto.KeywordResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/keywords [post]
 func (h *KeywordHandler) CreateKeyword(c *gin.Context) {
@@ -42,13 +45,17 @@ func (h *KeywordHandler) CreateKeyword(c *gin.Context) {
 		return
 	}
 
-	keyword, err := h.keywordUseCase.CreateKeyword(req.Value, req.SiteID, req.GroupID)
+	keyword, err := h.keywordUseCase.CreateKeyword(middleware.WorkspaceID(c), req.Value, req.SiteID, req.GroupID)
 	if err != nil {
 		if usecases.IsDomainError(err) {
 			code := usecases.GetDomainErrorCode(err)
 			status := http.StatusInternalServerError
 
 			switch code {
+			case usecases.ErrorValidation:
+				status = http.StatusBadRequest
+			case usecases.ErrorSiteNotFound:
+				status = http.StatusNotFound
 			case usecases.ErrorKeywordExists:
 				status = http.StatusConflict
 			case usecases.ErrorKeywordCreation:
@@ -69,12 +76,7 @@ func (h *KeywordHandler) CreateKeyword(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.KeywordResponse{
-		ID:      keyword.ID,
-		Value:   keyword.Value,
-		SiteID:  keyword.SiteID,
-		GroupID: keyword.GroupID,
-	})
+	c.JSON(http.StatusCreated, toKeywordResponse(keyword))
 }
 
 // DeleteKeyword godoc
@@ -97,7 +99,7 @@ func (h *KeywordHandler) DeleteKeyword(c *gin.Context) {
 		return
 	}
 
-	err = h.keywordUseCase.DeleteKeyword(id)
+	err = h.keywordUseCase.DeleteKeyword(middleware.WorkspaceID(c), id)
 	if err != nil {
 		if usecases.IsDomainError(err) {
 			code := usecases.GetDomainErrorCode(err)
@@ -162,7 +164,7 @@ func (h *KeywordHandler) UpdateKeyword(c *gin.Context) {
 		return
 	}
 
-	keyword, err := h.keywordUseCase.UpdateKeyword(id, req.GroupID)
+	keyword, err := h.keywordUseCase.UpdateKeyword(middleware.WorkspaceID(c), id, req.GroupID)
 	if err != nil {
 		if usecases.IsDomainError(err) {
 			code := usecases.GetDomainErrorCode(err)
@@ -189,12 +191,7 @@ func (h *KeywordHandler) UpdateKeyword(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.KeywordResponse{
-		ID:      keyword.ID,
-		Value:   keyword.Value,
-		SiteID:  keyword.SiteID,
-		GroupID: keyword.GroupID,
-	})
+	c.JSON(http.StatusOK, toKeywordResponse(keyword))
 }
 
 // GetKeywords godoc
@@ -203,8 +200,11 @@ func (h *KeywordHandler) UpdateKeyword(c *gin.Context) {
 // @Tags keywords
 // @Produce json
 // @Param site_id query int true "Site ID"
+// @Param tags query string false "Tag IDs, comma separated"
+// @Param tags_match query string false "Keywords with any of the tags or with all of them (default any)" Enums(any, all)
 // @Success 200 {array} dto.KeywordResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/keywords [get]
 func (h *KeywordHandler) GetKeywords(c *gin.Context) {
@@ -226,31 +226,28 @@ func (h *KeywordHandler) GetKeywords(c *gin.Context) {
 		return
 	}
 
-	keywords, err := h.keywordUseCase.GetKeywordsBySite(siteID)
+	var tagsMatch *string
+	if value, ok := c.GetQuery("tags_match"); ok {
+		tagsMatch = &value
+	}
+	tags, err := parseTagFilter(c.QueryArray("tags"), tagsMatch)
 	if err != nil {
-		if usecases.IsDomainError(err) {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				Error:   usecases.GetDomainErrorCode(err),
-				Message: err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Internal server error",
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
 		})
 		return
 	}
 
+	keywords, err := h.keywordUseCase.GetKeywordsBySite(middleware.WorkspaceID(c), siteID, tags)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
 	response := make([]dto.KeywordResponse, len(keywords))
 	for i, keyword := range keywords {
-		response[i] = dto.KeywordResponse{
-			ID:      keyword.ID,
-			Value:   keyword.Value,
-			SiteID:  keyword.SiteID,
-			GroupID: keyword.GroupID,
-		}
+		response[i] = toKeywordResponse(keyword)
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -283,16 +280,11 @@ func (h *KeywordHandler) CreateKeywordsBatch(c *gin.Context) {
 		}
 	}
 
-	created, errors := h.keywordUseCase.CreateKeywordsBatch(keywords)
+	created, errors := h.keywordUseCase.CreateKeywordsBatch(middleware.WorkspaceID(c), keywords)
 
 	response := make([]dto.KeywordResponse, len(created))
 	for i, keyword := range created {
-		response[i] = dto.KeywordResponse{
-			ID:      keyword.ID,
-			Value:   keyword.Value,
-			SiteID:  keyword.SiteID,
-			GroupID: keyword.GroupID,
-		}
+		response[i] = toKeywordResponse(keyword)
 	}
 
 	errorMessages := make([]string, len(errors))
@@ -313,3 +305,233 @@ func (h *KeywordHandler) CreateKeywordsBatch(c *gin.Context) {
 		"errors":  errorMessages,
 	})
 }
+
+// maxKeywordImportFileSize ограничивает загружаемый файл импорта
+const maxKeywordImportFileSize = 20 << 20
+
+// ImportKeywords godoc
+// @Summary Импортировать ключевые слова из файла
+// @Description Импорт ключевых слов сайта из CSV (UTF-8 или Windows-1251, разделитель , ; или табуляция) или XLSX (первый лист). Колонки слова, группы и меток задаются номером с 1 или текстом заголовка. Недостающие группы и метки создаются, существующие слова переносятся в группу из файла и получают метки. Повторы и ошибочные строки пропускаются и перечисляются в ответе; dry_run только показывает, что будет сделано. Не больше 50000 строк и 20 МБ
+// @Tags keywords
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV или XLSX файл"
+// @Param site_id formData int true "ID сайта"
+// @Param keyword_column formData string false "Колонка ключевого слова (по умолчанию 1)"
+// @Param group_column formData string false "Колонка имени группы"
+// @Param tags_column formData string false "Колонка меток"
+// @Param tags_separator formData string false "Разделитель меток (по умолчанию запятая)"
+// @Param has_header formData bool false "Первая строка - заголовок (по умолчанию true)"
+// @Param dry_run formData bool false "Только проверить файл"
+// @Success 200 {object} dto.KeywordImportResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/keywords/import [post]
+func (h *KeywordHandler) ImportKeywords(c *gin.Context) {
+	var req dto.KeywordImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "file is required",
+		})
+		return
+	}
+	if fileHeader.Size > maxKeywordImportFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "File must not exceed 20 MB",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Failed to read file",
+		})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxKeywordImportFileSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Failed to read file",
+		})
+		return
+	}
+
+	options := usecases.KeywordImportOptions{
+		KeywordColumn: req.KeywordColumn,
+		GroupColumn:   req.GroupColumn,
+		TagsColumn:    req.TagsColumn,
+		TagSeparator:  req.TagsSeparator,
+		HasHeader:     req.HasHeader == nil || *req.HasHeader,
+		DryRun:        req.DryRun,
+	}
+	result, err := h.keywordUseCase.ImportKeywords(middleware.WorkspaceID(c), req.SiteID, fileHeader.Filename, data, options)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.KeywordImportResponse{
+		DryRun:        result.DryRun,
+		Total:         result.Total,
+		New:           result.New,
+		Duplicate:     result.Duplicate,
+		Invalid:       result.Invalid,
+		NewGroups:     append([]string{}, result.NewGroups...),
+		NewTags:       append([]string{}, result.NewTags...),
+		Created:       result.Stats.Created,
+		Updated:       result.Stats.Updated,
+		GroupsCreated: result.Stats.GroupsCreated,
+		TagsCreated:   result.Stats.TagsCreated,
+		TagsAssigned:  result.Stats.TagsAssigned,
+		Rows:          make([]dto.KeywordImportRowItem, len(result.Rows)),
+	}
+	for i, row := range result.Rows {
+		response.Rows[i] = dto.KeywordImportRowItem{
+			Row:     row.Row,
+			Keyword: row.Value,
+			Group:   row.GroupName,
+			Tags:    row.Tags,
+			Status:  row.Status,
+			Reason:  row.Reason,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetNearDuplicates godoc
+// @Summary Получить дубли ключевых слов
+// @Description Группы ключевых слов сайта, совпадающих после нормализации: пробелы схлопываются, регистр и ё/е не учитываются. По выбору не учитываются стоп-слова (предлоги, союзы, частицы) и порядок слов. Слова группы объединяются через POST /api/keywords/merge
+// @Tags keywords
+// @Produce json
+// @Param site_id query int true "ID сайта"
+// @Param ignore_stop_words query bool false "Не учитывать стоп-слова"
+// @Param ignore_word_order query bool false "Не учитывать порядок слов"
+// @Param page query int false "Страница (по умолчанию 1)"
+// @Param per_page query int false "Групп на странице (по умолчанию 20, не больше 100)"
+// @Success 200 {object} dto.KeywordDuplicatesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/keywords/duplicates [get]
+func (h *KeywordHandler) GetNearDuplicates(c *gin.Context) {
+	var req dto.KeywordDuplicatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PerPage <= 0 {
+		req.PerPage = 20
+	}
+
+	groups, total, err := h.keywordUseCase.GetNearDuplicates(middleware.WorkspaceID(c), req.SiteID, req.IgnoreStopWords, req.IgnoreWordOrder, req.Page, req.PerPage)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.KeywordDuplicatesResponse{
+		Data:       make([]dto.KeywordDuplicateGroupItem, len(groups)),
+		Pagination: landingPagePagination(req.Page, req.PerPage, total),
+	}
+	for i, group := range groups {
+		keywords := make([]dto.KeywordResponse, len(group.Keywords))
+		for j, keyword := range group.Keywords {
+			keywords[j] = toKeywordResponse(keyword)
+		}
+		response.Data[i] = dto.KeywordDuplicateGroupItem{Key: group.Key, Keywords: keywords}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// MergeKeywords godoc
+// @Summary Объединить ключевые слова
+// @Description Переносит историю позиций, агрегаты, снимки выдачи, задачи проверки и метки слов source_ids на target_id и удаляет эти слова. Если за один день по одним настройкам проверки есть позиции нескольких слов, остаются позиции target_id. Все слова должны принадлежать одному сайту
+// @Tags keywords
+// @Accept json
+// @Produce json
+// @Param request body dto.MergeKeywordsRequest true "Объединяемые слова"
+// @Success 200 {object} dto.KeywordResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/keywords/merge [post]
+func (h *KeywordHandler) MergeKeywords(c *gin.Context) {
+	var req dto.MergeKeywordsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	keyword, err := h.keywordUseCase.MergeKeywords(middleware.WorkspaceID(c), req.TargetID, req.SourceIDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toKeywordResponse(keyword))
+}
+
+func (h *KeywordHandler) handleError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch usecases.GetDomainErrorCode(err) {
+	case usecases.ErrorValidation:
+		status = http.StatusBadRequest
+	case usecases.ErrorSiteNotFound, usecases.ErrorKeywordNotFound:
+		status = http.StatusNotFound
+	}
+	c.JSON(status, dto.ErrorResponse{
+		Error:   usecases.GetDomainErrorCode(err),
+		Message: err.Error(),
+	})
+}
+
+func toKeywordResponse(keyword *entities.Keyword) dto.KeywordResponse {
+	return dto.KeywordResponse{
+		ID:              keyword.ID,
+		Value:           keyword.Value,
+		SiteID:          keyword.SiteID,
+		GroupID:         keyword.GroupID,
+		NormalizedValue: keyword.NormalizedValue,
+		ClusterID:       keyword.ClusterID,
+		Tags:            toTagResponses(keyword.Tags),
+	}
+}
+
+func toTagResponses(tags []*entities.Tag) []dto.TagResponse {
+	if len(tags) == 0 {
+		return nil
+	}
+	response := make([]dto.TagResponse, len(tags))
+	for i, tag := range tags {
+		response[i] = toTagResponse(tag)
+	}
+	return response
+}
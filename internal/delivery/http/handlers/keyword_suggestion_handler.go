@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KeywordSuggestionHandler struct {
+	suggestionUseCase *usecases.KeywordSuggestionUseCase
+}
+
+func NewKeywordSuggestionHandler(suggestionUseCase *usecases.KeywordSuggestionUseCase) *KeywordSuggestionHandler {
+	return &KeywordSuggestionHandler{
+		suggestionUseCase: suggestionUseCase,
+	}
+}
+
+// CollectSuggestions godoc
+// @Summary Собрать подсказки ключевых слов из Wordstat
+// @Description Запрашивает Wordstat по каждой исходной фразе в каждом регионе и сохраняет популярные и похожие запросы с частотами. Фразы, которые уже есть среди ключевых слов сайта, пропускаются; повторный сбор обновляет частоты. Запросы учитываются в расходах и бюджете сайта
+// @Tags sites
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сайта"
+// @Param request body dto.KeywordSuggestionsRequest true "Исходные фразы и регионы"
+// @Success 200 {object} dto.CollectKeywordSuggestionsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/keyword-suggestions [post]
+func (h *KeywordSuggestionHandler) CollectSuggestions(c *gin.Context) {
+	siteID, ok := parseSiteID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.KeywordSuggestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.suggestionUseCase.CollectSuggestions(middleware.WorkspaceID(c), siteID, req.Seeds, req.Regions, req.AccountID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	failedSeeds := result.FailedSeeds
+	if failedSeeds == nil {
+		failedSeeds = []string{}
+	}
+	c.JSON(http.StatusOK, dto.CollectKeywordSuggestionsResponse{
+		Requests:    result.Requests,
+		Found:       result.Found,
+		Existing:    result.Existing,
+		Saved:       len(result.Suggestions),
+		FailedSeeds: failedSeeds,
+		Data:        toKeywordSuggestionItems(result.Suggestions),
+	})
+}
+
+// GetSuggestions godoc
+// @Summary Получить подсказки ключевых слов
+// @Description Сохраненные подсказки сайта по убыванию частоты. Фразы, которые уже стали ключевыми словами, не возвращаются
+// @Tags sites
+// @Produce json
+// @Param id path int true "ID сайта"
+// @Param region query int false "Регион Wordstat"
+// @Param kind query string false "Источник подсказки" Enums(popular, association)
+// @Param page query int false "Страница (по умолчанию 1)"
+// @Param per_page query int false "Подсказок на странице (по умолчанию 20, не больше 100)"
+// @Success 200 {object} dto.KeywordSuggestionsListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/keyword-suggestions [get]
+func (h *KeywordSuggestionHandler) GetSuggestions(c *gin.Context) {
+	siteID, ok := parseSiteID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.KeywordSuggestionsListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PerPage <= 0 {
+		req.PerPage = 20
+	}
+
+	suggestions, total, err := h.suggestionUseCase.GetSuggestions(middleware.WorkspaceID(c), siteID, req.Region, req.Kind, req.Page, req.PerPage)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KeywordSuggestionsListResponse{
+		Data:       toKeywordSuggestionItems(suggestions),
+		Pagination: landingPagePagination(req.Page, req.PerPage, total),
+	})
+}
+
+// AcceptSuggestions godoc
+// @Summary Принять подсказки ключевых слов
+// @Description Создает из подсказок ключевые слова сайта в указанной группе одним запросом. Фразы, которые уже есть у сайта, пропускаются; принятые подсказки удаляются во всех регионах
+// @Tags sites
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сайта"
+// @Param request body dto.AcceptKeywordSuggestionsRequest true "Подсказки и группа"
+// @Success 200 {object} dto.KeywordSuggestionsCountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/keyword-suggestions/accept [post]
+func (h *KeywordSuggestionHandler) AcceptSuggestions(c *gin.Context) {
+	siteID, ok := parseSiteID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AcceptKeywordSuggestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	created, err := h.suggestionUseCase.AcceptSuggestions(middleware.WorkspaceID(c), siteID, req.IDs, req.GroupID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KeywordSuggestionsCountResponse{Count: created})
+}
+
+// DismissSuggestions godoc
+// @Summary Отклонить подсказки ключевых слов
+// @Description Удаляет подсказки; при следующем сборе они могут появиться снова
+// @Tags sites
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сайта"
+// @Param request body dto.DismissKeywordSuggestionsRequest true "Подсказки"
+// @Success 200 {object} dto.KeywordSuggestionsCountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/keyword-suggestions/dismiss [post]
+func (h *KeywordSuggestionHandler) DismissSuggestions(c *gin.Context) {
+	siteID, ok := parseSiteID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.DismissKeywordSuggestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	deleted, err := h.suggestionUseCase.DismissSuggestions(middleware.WorkspaceID(c), siteID, req.IDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KeywordSuggestionsCountResponse{Count: deleted})
+}
+
+func (h *KeywordSuggestionHandler) handleError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch usecases.GetDomainErrorCode(err) {
+	case usecases.ErrorValidation, usecases.ErrorProviderUnsupported, usecases.ErrorBudgetExceeded:
+		status = http.StatusBadRequest
+	case usecases.ErrorSiteNotFound, usecases.ErrorSuggestionNotFound, usecases.ErrorGroupNotFound, usecases.ErrorAccountNotFound:
+		status = http.StatusNotFound
+	}
+	c.JSON(status, dto.ErrorResponse{
+		Error:   usecases.GetDomainErrorCode(err),
+		Message: err.Error(),
+	})
+}
+
+func parseSiteID(c *gin.Context) (int, bool) {
+	siteID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid site ID",
+		})
+		return 0, false
+	}
+	return siteID, true
+}
+
+func toKeywordSuggestionItems(suggestions []*entities.KeywordSuggestion) []dto.KeywordSuggestionItem {
+	items := make([]dto.KeywordSuggestionItem, len(suggestions))
+	for i, suggestion := range suggestions {
+		items[i] = dto.KeywordSuggestionItem{
+			ID:        suggestion.ID,
+			Value:     suggestion.Value,
+			Seed:      suggestion.Seed,
+			Kind:      suggestion.Kind,
+			Region:    suggestion.Region,
+			Frequency: suggestion.Frequency,
+			UpdatedAt: suggestion.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+	return items
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LandingPageHandler struct {
+	landingPageUseCase *usecases.LandingPageUseCase
+}
+
+func NewLandingPageHandler(landingPageUseCase *usecases.LandingPageUseCase) *LandingPageHandler {
+	return &LandingPageHandler{
+		landingPageUseCase: landingPageUseCase,
+	}
+}
+
+// GetURLChanges godoc
+// @Summary Получить смены посадочных страниц
+// @Description Ключевые слова, у которых ранжирующийся URL сайта менялся между проверками периода, со всеми сменами и историей позиций каждого URL. Сравниваются последние за день найденные позиции отдельно по источнику и группе фильтров; http/https, www и завершающий слеш сменой не считаются. Период не больше 92 дней
+// @Tags sites
+// @Produce json
+// @Param id path int true "ID сайта"
+// @Param source query string false "Источник" Enums(google, yandex)
+// @Param group_id query int false "ID группы ключевых слов"
+// @Param date_from query string false "Начало периода (YYYY-MM-DD)"
+// @Param date_to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Param page query int false "Страница (по умолчанию 1)"
+// @Param per_page query int false "Ключевых слов на странице (по умолчанию 20, не больше 100)"
+// @Success 200 {object} dto.URLChangesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/url-changes [get]
+func (h *LandingPageHandler) GetURLChanges(c *gin.Context) {
+	siteID, req, dateFrom, dateTo, ok := parseLandingPageRequest(c)
+	if !ok {
+		return
+	}
+
+	keywords, total, err := h.landingPageUseCase.GetURLChanges(middleware.WorkspaceID(c), siteID, req.Source, req.GroupID, dateFrom, dateTo, req.Page, req.PerPage)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.URLChangesResponse{
+		Data:       make([]dto.KeywordURLChangesItem, 0, len(keywords)),
+		Pagination: landingPagePagination(req.Page, req.PerPage, total),
+	}
+	for _, keyword := range keywords {
+		changes := make([]dto.URLChangeItem, len(keyword.Changes))
+		for i, change := range keyword.Changes {
+			changes[i] = dto.URLChangeItem{
+				Date:         change.Date.Format("2006-01-02"),
+				PreviousURL:  change.PreviousURL,
+				URL:          change.URL,
+				PreviousRank: change.PreviousRank,
+				Rank:         change.Rank,
+			}
+		}
+
+		response.Data = append(response.Data, dto.KeywordURLChangesItem{
+			KeywordID:     keyword.KeywordID,
+			Keyword:       keyword.Keyword,
+			GroupID:       keyword.GroupID,
+			Source:        keyword.Source,
+			FilterGroupID: keyword.FilterGroupID,
+			Changes:       changes,
+			URLs:          toURLHistoryItems(keyword.URLs),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCannibalization godoc
+// @Summary Получить каннибализацию ключевых слов
+// @Description Ключевые слова, по которым конкурируют несколько URL сайта: ранжирующийся URL возвращается к уже ранжировавшемуся раньше (rotating) или несколько URL сайта и его поддоменов попадают в одну сохраненную выдачу (shared_checks). История позиций каждого URL собирается из позиций и снимков выдачи. Период не больше 92 дней
+// @Tags sites
+// @Produce json
+// @Param id path int true "ID сайта"
+// @Param source query string false "Источник" Enums(google, yandex)
+// @Param group_id query int false "ID группы ключевых слов"
+// @Param date_from query string false "Начало периода (YYYY-MM-DD)"
+// @Param date_to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Param page query int false "Страница (по умолчанию 1)"
+// @Param per_page query int false "Ключевых слов на странице (по умолчанию 20, не больше 100)"
+// @Success 200 {object} dto.CannibalizationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/cannibalization [get]
+func (h *LandingPageHandler) GetCannibalization(c *gin.Context) {
+	siteID, req, dateFrom, dateTo, ok := parseLandingPageRequest(c)
+	if !ok {
+		return
+	}
+
+	cases, total, err := h.landingPageUseCase.GetCannibalization(middleware.WorkspaceID(c), siteID, req.Source, req.GroupID, dateFrom, dateTo, req.Page, req.PerPage)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.CannibalizationResponse{
+		Data:       make([]dto.CannibalizationItem, 0, len(cases)),
+		Pagination: landingPagePagination(req.Page, req.PerPage, total),
+	}
+	for _, item := range cases {
+		response.Data = append(response.Data, dto.CannibalizationItem{
+			KeywordID:     item.KeywordID,
+			Keyword:       item.Keyword,
+			GroupID:       item.GroupID,
+			Source:        item.Source,
+			FilterGroupID: item.FilterGroupID,
+			Rotating:      item.Rotating,
+			SharedChecks:  item.SharedChecks,
+			URLs:          toURLHistoryItems(item.URLs),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *LandingPageHandler) handleError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch usecases.GetDomainErrorCode(err) {
+	case usecases.ErrorValidation:
+		status = http.StatusBadRequest
+	case usecases.ErrorSiteNotFound:
+		status = http.StatusNotFound
+	}
+	c.JSON(status, dto.ErrorResponse{
+		Error:   usecases.GetDomainErrorCode(err),
+		Message: err.Error(),
+	})
+}
+
+func parseLandingPageRequest(c *gin.Context) (int, dto.LandingPageRequest, *time.Time, *time.Time, bool) {
+	var req dto.LandingPageRequest
+
+	siteID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid site ID",
+		})
+		return 0, req, nil, nil, false
+	}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return 0, req, nil, nil, false
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PerPage <= 0 {
+		req.PerPage = 20
+	}
+
+	var dateFrom, dateTo *time.Time
+	if req.DateFrom != nil {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.DateFrom, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid date_from parameter. Use YYYY-MM-DD format",
+			})
+			return 0, req, nil, nil, false
+		}
+		dateFrom = &parsed
+	}
+	if req.DateTo != nil {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.DateTo, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid date_to parameter. Use YYYY-MM-DD format",
+			})
+			return 0, req, nil, nil, false
+		}
+		dateTo = &parsed
+	}
+
+	return siteID, req, dateFrom, dateTo, true
+}
+
+func toURLHistoryItems(histories []entities.URLHistory) []dto.URLHistoryItem {
+	items := make([]dto.URLHistoryItem, len(histories))
+	for i, history := range histories {
+		ranks := make([]dto.URLRankItem, len(history.Ranks))
+		for j, rank := range history.Ranks {
+			ranks[j] = dto.URLRankItem{Date: rank.Date.Format("2006-01-02"), Rank: rank.Rank}
+		}
+		items[i] = dto.URLHistoryItem{
+			URL:       history.URL,
+			FirstSeen: history.FirstSeen.Format("2006-01-02"),
+			LastSeen:  history.LastSeen.Format("2006-01-02"),
+			BestRank:  history.BestRank,
+			Ranks:     ranks,
+		}
+	}
+	return items
+}
+
+func landingPagePagination(page, perPage int, total int64) dto.PaginationInfo {
+	lastPage := int((total + int64(perPage) - 1) / int64(perPage))
+	from := (page-1)*perPage + 1
+	to := page * perPage
+	if to > int(total) {
+		to = int(total)
+	}
+	if from > int(total) {
+		from = 0
+	}
+
+	return dto.PaginationInfo{
+		CurrentPage: page,
+		PerPage:     perPage,
+		Total:       int(total),
+		LastPage:    lastPage,
+		From:        from,
+		To:          to,
+		HasMore:     page < lastPage,
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MovementHandler struct {
+	movementUseCase *usecases.MovementUseCase
+}
+
+func NewMovementHandler(movementUseCase *usecases.MovementUseCase) *MovementHandler {
+	return &MovementHandler{
+		movementUseCase: movementUseCase,
+	}
+}
+
+// GetMovement godoc
+// @Summary Получить отчет о движении позиций
+// @Description Сравнивает последние за день позиции ключевых слов сайта в две даты проверки: up - поднялись, down - опустились, new - появились в выдаче, lost - выпали из выдачи, unchanged - без изменений. В отчет входят ключевые слова, проверенные в date_to. delta и net_score считаются в позициях, "не найдено" считается 101 позицией. Без дат сравниваются две последние проверки; status фильтрует только список ключевых слов
+// @Tags positions
+// @Produce json
+// @Param site_id query int true "ID сайта"
+// @Param source query string true "Источник" Enums(google, yandex)
+// @Param group_id query int false "ID группы ключевых слов"
+// @Param filter_group_id query int false "ID группы фильтров"
+// @Param date_from query string false "Предыдущая дата проверки (YYYY-MM-DD)"
+// @Param date_to query string false "Текущая дата проверки (YYYY-MM-DD)"
+// @Param status query string false "Вид изменения" Enums(up, down, new, lost, unchanged)
+// @Success 200 {object} dto.MovementResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/positions/movement [get]
+func (h *MovementHandler) GetMovement(c *gin.Context) {
+	var req dto.MovementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var dateFrom, dateTo *time.Time
+	if req.DateFrom != nil {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.DateFrom, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid date_from parameter. Use YYYY-MM-DD format",
+			})
+			return
+		}
+		dateFrom = &parsed
+	}
+	if req.DateTo != nil {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.DateTo, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid date_to parameter. Use YYYY-MM-DD format",
+			})
+			return
+		}
+		dateTo = &parsed
+	}
+
+	report, err := h.movementUseCase.GetMovement(middleware.WorkspaceID(c), req.SiteID, req.Source, req.GroupID, req.FilterGroupID, dateFrom, dateTo, req.Status)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch usecases.GetDomainErrorCode(err) {
+		case usecases.ErrorValidation:
+			status = http.StatusBadRequest
+		case usecases.ErrorSiteNotFound:
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Error:   usecases.GetDomainErrorCode(err),
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response := dto.MovementResponse{
+		SiteID:        report.SiteID,
+		Source:        report.Source,
+		GroupID:       req.GroupID,
+		FilterGroupID: req.FilterGroupID,
+		Groups:        make([]dto.GroupMovementItem, 0, len(report.Groups)),
+		Keywords:      make([]dto.KeywordMovementItem, 0, len(report.Keywords)),
+	}
+	if !report.Summary.CurrentDate.IsZero() {
+		response.Summary = toMovementSummary(&report.Summary)
+	}
+	for _, group := range report.Groups {
+		response.Groups = append(response.Groups, dto.GroupMovementItem{
+			GroupID: group.GroupID,
+			Summary: *toMovementSummary(&group.Summary),
+		})
+	}
+	for _, movement := range report.Keywords {
+		response.Keywords = append(response.Keywords, dto.KeywordMovementItem{
+			KeywordID:    movement.KeywordID,
+			Keyword:      movement.Keyword,
+			GroupID:      movement.GroupID,
+			PreviousRank: movement.PreviousRank,
+			CurrentRank:  movement.CurrentRank,
+			Delta:        movement.Delta,
+			Status:       movement.Status,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func toMovementSummary(summary *entities.MovementSummary) *dto.MovementSummary {
+	if summary == nil {
+		return nil
+	}
+	return &dto.MovementSummary{
+		PreviousDate: summary.PreviousDate.Format("2006-01-02"),
+		CurrentDate:  summary.CurrentDate.Format("2006-01-02"),
+		Up:           summary.Up,
+		Down:         summary.Down,
+		New:          summary.New,
+		Lost:         summary.Lost,
+		Unchanged:    summary.Unchanged,
+		NetScore:     summary.NetScore,
+	}
+}
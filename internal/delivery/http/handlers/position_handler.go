@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/domain/entities"
 	"go-seo/internal/usecases"
 	"go-seo/pkg/logger"
 
@@ -30,6 +32,7 @@ func NewPositionHandler(positionTrackingUseCase *usecases.PositionTrackingUseCas
 // @Param request body dto.TrackGooglePositionsRequest true "Google tracking parameters"
 // @Success 200 {object} dto.AsyncTrackPositionsResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/positions/track-google [post]
 func (h *PositionHandler) TrackGooglePositions(c *gin.Context) {
@@ -64,6 +67,7 @@ func (h *PositionHandler) TrackGooglePositions(c *gin.Context) {
 	)
 
 	taskID, err := h.asyncPositionTrackingUseCase.StartAsyncGoogleTracking(
+		middleware.WorkspaceID(c),
 		req.SiteID,
 		req.Device,
 		req.OS,
@@ -72,9 +76,8 @@ func (h *PositionHandler) TrackGooglePositions(c *gin.Context) {
 		req.Lang,
 		req.Pages,
 		req.Subdomains,
-		req.XMLUserID,
-		req.XMLAPIKey,
-		req.XMLBaseURL,
+		req.AccountID,
+		req.Provider,
 		req.TBS,
 		req.Filter,
 		req.Highlights,
@@ -84,12 +87,14 @@ func (h *PositionHandler) TrackGooglePositions(c *gin.Context) {
 		req.Raw,
 		req.LR,
 		req.Domain,
+		req.Strict,
 		req.FilterGroupID,
+		middleware.CurrentAPIKeyID(c),
 	)
 
 	if err != nil {
 		if usecases.IsDomainError(err) {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			c.JSON(positionErrorStatus(err), dto.ErrorResponse{
 				Error:   usecases.GetDomainErrorCode(err),
 				Message: err.Error(),
 			})
@@ -116,6 +121,7 @@ func (h *PositionHandler) TrackGooglePositions(c *gin.Context) {
 // @Param request body dto.TrackYandexPositionsRequest true "Yandex tracking parameters"
 // @Success 200 {object} dto.AsyncTrackPositionsResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/positions/track-yandex [post]
 func (h *PositionHandler) TrackYandexPositions(c *gin.Context) {
@@ -150,6 +156,7 @@ func (h *PositionHandler) TrackYandexPositions(c *gin.Context) {
 	)
 
 	taskID, err := h.asyncPositionTrackingUseCase.StartAsyncYandexTracking(
+		middleware.WorkspaceID(c),
 		req.SiteID,
 		req.Device,
 		req.OS,
@@ -158,24 +165,25 @@ func (h *PositionHandler) TrackYandexPositions(c *gin.Context) {
 		req.Lang,
 		req.Pages,
 		req.Subdomains,
-		req.XMLUserID,
-		req.XMLAPIKey,
-		req.XMLBaseURL,
+		req.AccountID,
+		req.Provider,
 		req.GroupBy,
 		req.Filter,
 		req.Highlights,
 		req.Within,
 		req.LR,
+		req.Domain,
 		req.Raw,
 		req.InIndex,
 		req.Strict,
 		req.Organic,
 		req.FilterGroupID,
+		middleware.CurrentAPIKeyID(c),
 	)
 
 	if err != nil {
 		if usecases.IsDomainError(err) {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			c.JSON(positionErrorStatus(err), dto.ErrorResponse{
 				Error:   usecases.GetDomainErrorCode(err),
 				Message: err.Error(),
 			})
@@ -202,6 +210,7 @@ func (h *PositionHandler) TrackYandexPositions(c *gin.Context) {
 // @Param request body dto.TrackWordstatPositionsRequest true "Wordstat tracking parameters"
 // @Success 200 {object} dto.AsyncTrackPositionsResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/positions/track-wordstat [post]
 func (h *PositionHandler) TrackWordstatPositions(c *gin.Context) {
@@ -248,20 +257,20 @@ func (h *PositionHandler) TrackWordstatPositions(c *gin.Context) {
 	}
 
 	taskID, err := h.asyncPositionTrackingUseCase.StartAsyncWordstatTracking(
+		middleware.WorkspaceID(c),
 		req.SiteID,
-		req.XMLUserID,
-		req.XMLAPIKey,
-		req.XMLBaseURL,
+		req.AccountID,
 		req.Regions,
 		defaultQuery,
 		quotes,
 		quotesExclamationMarks,
 		exclamationMarks,
+		middleware.CurrentAPIKeyID(c),
 	)
 
 	if err != nil {
 		if usecases.IsDomainError(err) {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			c.JSON(positionErrorStatus(err), dto.ErrorResponse{
 				Error:   usecases.GetDomainErrorCode(err),
 				Message: err.Error(),
 			})
@@ -291,10 +300,13 @@ func (h *PositionHandler) TrackWordstatPositions(c *gin.Context) {
 // @Param date_from query string false "Start date (YYYY-MM-DD)"
 // @Param date_to query string false "End date (YYYY-MM-DD)"
 // @Param last query bool false "Get only last positions"
+// @Param tags query string false "Tag IDs, comma separated"
+// @Param tags_match query string false "Keywords with any of the tags or with all of them (default any)" Enums(any, all)
 // @Param page query int false "Page number (default 1)"
 // @Param per_page query int false "Items per page (default 50, max 100)"
 // @Success 200 {object} dto.PositionHistoryResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/positions/history [get]
 func (h *PositionHandler) GetPositionsHistory(c *gin.Context) {
@@ -358,11 +370,20 @@ func (h *PositionHandler) GetPositionsHistory(c *gin.Context) {
 		last = *req.Last
 	}
 
+	tags, err := parseTagFilter(req.Tags, req.TagsMatch)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	positions, total, err := h.positionTrackingUseCase.GetPositionsHistoryPaginated(
-		req.SiteID, req.KeywordID, req.Source, dateFrom, dateTo, last, req.Page, req.PerPage)
+		middleware.WorkspaceID(c), req.SiteID, req.KeywordID, tags, req.Source, dateFrom, dateTo, last, req.Page, req.PerPage)
 	if err != nil {
 		if usecases.IsDomainError(err) {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			c.JSON(positionErrorStatus(err), dto.ErrorResponse{
 				Error:   usecases.GetDomainErrorCode(err),
 				Message: err.Error(),
 			})
@@ -403,6 +424,9 @@ func (h *PositionHandler) GetPositionsHistory(c *gin.Context) {
 			Device:    pos.Device,
 			Country:   pos.Country,
 			Lang:      pos.Lang,
+			Period:    pos.Period,
+			AvgRank:   pos.AvgRank,
+			Samples:   pos.Samples,
 		})
 	}
 
@@ -443,11 +467,15 @@ func (h *PositionHandler) GetPositionsHistory(c *gin.Context) {
 // @Param rank_to query int false "Maximum rank filter"
 // @Param group_id query int false "Filter by keyword group ID"
 // @Param filter_group_id query int false "Filter by position filter_group_id"
+// @Param tags query string false "Tag IDs, comma separated"
+// @Param tags_match query string false "Keywords with any of the tags or with all of them (default any)" Enums(any, all)
+// @Param competitor_id query int false "Return positions of this competitor instead of the site"
 // @Param wordstat_query_type query string false "Filter Wordstat by query type (default, quotes, quotes_exclamation_marks, exclamation_marks)"
 // @Param page query int false "Page number (default 1)"
 // @Param per_page query int false "Items per page (default 50, max 100)"
 // @Success 200 {object} dto.CombinedPositionsResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/positions/combined [get]
 func (h *PositionHandler) GetCombinedPositions(c *gin.Context) {
@@ -559,11 +587,20 @@ func (h *PositionHandler) GetCombinedPositions(c *gin.Context) {
 		sortType = *req.WordstatSort
 	}
 
+	tags, err := parseTagFilter(req.Tags, req.TagsMatch)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	combinedPositions, total, err := h.positionTrackingUseCase.GetCombinedPositionsPaginated(
-		req.SiteID, req.Source, includeWordstat, wordstatSort, dateFrom, dateTo, dateSort, sortType, req.RankFrom, req.RankTo, req.GroupID, req.FilterGroupID, req.WordstatQueryType, req.Page, req.PerPage)
+		middleware.WorkspaceID(c), req.SiteID, req.CompetitorID, req.Source, includeWordstat, wordstatSort, dateFrom, dateTo, dateSort, sortType, req.RankFrom, req.RankTo, req.GroupID, tags, req.FilterGroupID, req.WordstatQueryType, req.Page, req.PerPage)
 	if err != nil {
 		if usecases.IsDomainError(err) {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			c.JSON(positionErrorStatus(err), dto.ErrorResponse{
 				Error:   usecases.GetDomainErrorCode(err),
 				Message: err.Error(),
 			})
@@ -651,6 +688,7 @@ func (h *PositionHandler) GetCombinedPositions(c *gin.Context) {
 // @Param request body dto.PositionStatisticsRequest true "Statistics parameters"
 // @Success 200 {object} dto.PositionStatisticsResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/positions/statistics [post]
 func (h *PositionHandler) GetPositionStatistics(c *gin.Context) {
@@ -681,10 +719,18 @@ func (h *PositionHandler) GetPositionStatistics(c *gin.Context) {
 		return
 	}
 
-	stats, err := h.positionTrackingUseCase.GetPositionStatistics(req.SiteID, req.Source, dateFrom, dateTo, req.FilterGroupID)
+	var tags *entities.TagFilter
+	if len(req.Tags) > 0 {
+		tags = &entities.TagFilter{TagIDs: req.Tags, Match: entities.TagMatchAny}
+		if req.TagsMatch != "" {
+			tags.Match = req.TagsMatch
+		}
+	}
+
+	stats, err := h.positionTrackingUseCase.GetPositionStatistics(middleware.WorkspaceID(c), req.SiteID, req.CompetitorID, req.Source, dateFrom, dateTo, req.FilterGroupID, tags)
 	if err != nil {
 		if usecases.IsDomainError(err) {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			c.JSON(positionErrorStatus(err), dto.ErrorResponse{
 				Error:   usecases.GetDomainErrorCode(err),
 				Message: err.Error(),
 			})
@@ -736,7 +782,7 @@ func (h *PositionHandler) GetPositionStatistics(c *gin.Context) {
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/positions/latest [get]
 func (h *PositionHandler) GetLatestPositions(c *gin.Context) {
-	positions, err := h.positionTrackingUseCase.GetLatestPositions()
+	positions, err := h.positionTrackingUseCase.GetLatestPositions(middleware.WorkspaceID(c))
 	if err != nil {
 		if usecases.IsDomainError(err) {
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -774,3 +820,11 @@ func (h *PositionHandler) GetLatestPositions(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// positionErrorStatus статус ошибки use case: сайт вне пространства запроса не найден, остальное - ошибка запроса
+func positionErrorStatus(err error) int {
+	if usecases.GetDomainErrorCode(err) == usecases.ErrorSiteNotFound {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProviderAccountHandler struct {
+	accountUseCase *usecases.ProviderAccountUseCase
+}
+
+func NewProviderAccountHandler(accountUseCase *usecases.ProviderAccountUseCase) *ProviderAccountHandler {
+	return &ProviderAccountHandler{
+		accountUseCase: accountUseCase,
+	}
+}
+
+// GetProviderAccounts godoc
+// @Summary Получить аккаунты провайдеров
+// @Description Возвращает сохраненные аккаунты без API-ключей
+// @Tags provider-accounts
+// @Produce json
+// @Success 200 {array} dto.ProviderAccountResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/provider-accounts [get]
+func (h *ProviderAccountHandler) GetProviderAccounts(c *gin.Context) {
+	accounts, err := h.accountUseCase.GetAccounts(middleware.WorkspaceID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := make([]dto.ProviderAccountResponse, len(accounts))
+	for i, account := range accounts {
+		response[i] = toProviderAccountResponse(account)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetProviderAccount godoc
+// @Summary Получить аккаунт провайдера
+// @Tags provider-accounts
+// @Produce json
+// @Param id path int true "ID аккаунта"
+// @Success 200 {object} dto.ProviderAccountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/provider-accounts/{id} [get]
+func (h *ProviderAccountHandler) GetProviderAccount(c *gin.Context) {
+	id, ok := parseProviderAccountID(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.accountUseCase.GetAccount(middleware.WorkspaceID(c), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toProviderAccountResponse(account))
+}
+
+// CreateProviderAccount godoc
+// @Summary Создать аккаунт провайдера
+// @Description Сохраняет учетные данные провайдера; ключ шифруется ключом из CREDENTIALS_ENCRYPTION_KEY и в ответах не возвращается. ID аккаунта передается в account_id запросов отслеживания
+// @Tags provider-accounts
+// @Accept json
+// @Produce json
+// @Param account body dto.ProviderAccountRequest true "Учетные данные"
+// @Success 201 {object} dto.ProviderAccountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/provider-accounts [post]
+func (h *ProviderAccountHandler) CreateProviderAccount(c *gin.Context) {
+	var req dto.ProviderAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	account, err := h.accountUseCase.CreateAccount(middleware.WorkspaceID(c), req.Name, req.Provider, req.UserID, req.APIKey, req.BaseURL)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toProviderAccountResponse(account))
+}
+
+// UpdateProviderAccount godoc
+// @Summary Обновить аккаунт провайдера
+// @Description Заменяет данные аккаунта. Пустой api_key оставляет сохраненный ключ
+// @Tags provider-accounts
+// @Accept json
+// @Produce json
+// @Param id path int true "ID аккаунта"
+// @Param account body dto.ProviderAccountRequest true "Учетные данные"
+// @Success 200 {object} dto.ProviderAccountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/provider-accounts/{id} [put]
+func (h *ProviderAccountHandler) UpdateProviderAccount(c *gin.Context) {
+	id, ok := parseProviderAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ProviderAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	account, err := h.accountUseCase.UpdateAccount(middleware.WorkspaceID(c), id, req.Name, req.Provider, req.UserID, req.APIKey, req.BaseURL)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toProviderAccountResponse(account))
+}
+
+// DeleteProviderAccount godoc
+// @Summary Удалить аккаунт провайдера
+// @Description Джобы и расписания, ссылающиеся на аккаунт, завершатся ошибкой при следующем запуске
+// @Tags provider-accounts
+// @Param id path int true "ID аккаунта"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/provider-accounts/{id} [delete]
+func (h *ProviderAccountHandler) DeleteProviderAccount(c *gin.Context) {
+	id, ok := parseProviderAccountID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.accountUseCase.DeleteAccount(middleware.WorkspaceID(c), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ProviderAccountHandler) handleError(c *gin.Context, err error) {
+	if !usecases.IsDomainError(err) {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	code := usecases.GetDomainErrorCode(err)
+	status := http.StatusInternalServerError
+
+	switch code {
+	case usecases.ErrorValidation, usecases.ErrorProviderNotFound:
+		status = http.StatusBadRequest
+	case usecases.ErrorAccountNotFound:
+		status = http.StatusNotFound
+	case usecases.ErrorAccountExists:
+		status = http.StatusConflict
+	case usecases.ErrorEncryptionNotConfigured:
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Error:   code,
+		Message: err.Error(),
+	})
+}
+
+func parseProviderAccountID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid provider account ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func toProviderAccountResponse(account *entities.ProviderAccount) dto.ProviderAccountResponse {
+	return dto.ProviderAccountResponse{
+		ID:          account.ID,
+		WorkspaceID: account.WorkspaceID,
+		Name:        account.Name,
+		Provider:    account.Provider,
+		UserID:      account.UserID,
+		BaseURL:     account.BaseURL,
+		CreatedAt:   account.CreatedAt,
+		UpdatedAt:   account.UpdatedAt,
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProviderHandler struct {
+	providerUseCase *usecases.ProviderUseCase
+}
+
+func NewProviderHandler(providerUseCase *usecases.ProviderUseCase) *ProviderHandler {
+	return &ProviderHandler{
+		providerUseCase: providerUseCase,
+	}
+}
+
+// GetProviders godoc
+// @Summary Получить список SERP провайдеров
+// @Description Возвращает зарегистрированных провайдеров и их возможности. Имя провайдера передается в поле provider запросов отслеживания
+// @Tags providers
+// @Produce json
+// @Success 200 {array} dto.ProviderResponse
+// @Router /api/providers [get]
+func (h *ProviderHandler) GetProviders(c *gin.Context) {
+	providers, defaultName := h.providerUseCase.GetProviders()
+
+	response := make([]dto.ProviderResponse, 0, len(providers))
+	for _, provider := range providers {
+		capabilities := provider.Capabilities()
+		response = append(response, dto.ProviderResponse{
+			Name:           provider.Name(),
+			Sources:        capabilities.Sources,
+			MaxPages:       capabilities.MaxPages,
+			GroupBy:        capabilities.GroupBy,
+			CostPerRequest: capabilities.CostPerRequest,
+			Default:        provider.Name() == defaultName,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetProviderLimits godoc
+// @Summary Получить состояние лимитов провайдеров
+// @Description Показывает для каждого аккаунта провайдера настроенные лимиты, число запросов в работе и в ожидании, а также расход дневной квоты. Счетчики ведутся отдельно в каждом инстансе сервиса. Ключ пространства видит только аккаунты своего пространства
+// @Tags providers
+// @Produce json
+// @Success 200 {array} dto.ProviderLimitsResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/providers/limits [get]
+func (h *ProviderHandler) GetProviderLimits(c *gin.Context) {
+	states, err := h.providerUseCase.GetLimits(middleware.WorkspaceID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   usecases.GetDomainErrorCode(err),
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response := make([]dto.ProviderLimitsResponse, 0, len(states))
+	for _, state := range states {
+		item := dto.ProviderLimitsResponse{
+			Provider:          state.Provider,
+			Account:           state.Account,
+			RequestsPerSecond: state.Limits.RequestsPerSecond,
+			MaxInFlight:       state.Limits.MaxInFlight,
+			DailyQuota:        state.Limits.DailyQuota,
+			InFlight:          state.InFlight,
+			Waiting:           state.Waiting,
+			UsedToday:         state.UsedToday,
+			QuotaResetAt:      state.QuotaResetAt,
+		}
+		if state.Limits.DailyQuota > 0 {
+			remaining := state.Limits.DailyQuota - state.UsedToday
+			item.RemainingToday = &remaining
+		}
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
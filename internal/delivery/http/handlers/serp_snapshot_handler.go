@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SerpSnapshotHandler struct {
+	serpSnapshotUseCase *usecases.SerpSnapshotUseCase
+}
+
+func NewSerpSnapshotHandler(serpSnapshotUseCase *usecases.SerpSnapshotUseCase) *SerpSnapshotHandler {
+	return &SerpSnapshotHandler{
+		serpSnapshotUseCase: serpSnapshotUseCase,
+	}
+}
+
+// GetSerpSnapshot godoc
+// @Summary Get SERP snapshot for a position
+// @Description Get the full top of search results saved when the position was checked.
+// @Description Snapshots are kept after the position is rolled up, until SERP_SNAPSHOT_RETENTION_DAYS (0 - forever)
+// @Tags positions
+// @Produce json
+// @Param id path int true "Position ID"
+// @Success 200 {object} dto.SerpSnapshotResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/positions/{id}/serp [get]
+func (h *SerpSnapshotHandler) GetSerpSnapshot(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid position ID",
+		})
+		return
+	}
+
+	snapshot, err := h.serpSnapshotUseCase.GetByPositionID(middleware.WorkspaceID(c), id)
+	if err != nil {
+		if usecases.IsDomainError(err) {
+			code := usecases.GetDomainErrorCode(err)
+			status := http.StatusInternalServerError
+
+			switch code {
+			case usecases.ErrorSerpSnapshotNotFound:
+				status = http.StatusNotFound
+			}
+
+			c.JSON(status, dto.ErrorResponse{
+				Error:   code,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	items := make([]dto.SerpItemResponse, len(snapshot.Items))
+	for i, item := range snapshot.Items {
+		items[i] = dto.SerpItemResponse{
+			Place:       item.Place,
+			Rank:        item.Rank,
+			URL:         item.URL,
+			Domain:      item.Domain,
+			Title:       item.Title,
+			Passage:     item.Passage,
+			ContentType: item.ContentType,
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.SerpSnapshotResponse{
+		PositionID: snapshot.PositionID,
+		KeywordID:  snapshot.KeywordID,
+		SiteID:     snapshot.SiteID,
+		Source:     snapshot.Source,
+		Date:       snapshot.Date,
+		Items:      items,
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
 	"go-seo/internal/domain/entities"
 	"go-seo/internal/usecases"
 
@@ -68,7 +69,7 @@ func (h *SiteHandler) CreateSite(c *gin.Context) {
 		return
 	}
 
-	site, err := h.siteUseCase.CreateSite(req.Domain)
+	site, err := h.siteUseCase.CreateSite(middleware.WorkspaceID(c), req.Domain)
 	if err != nil {
 		if usecases.IsDomainError(err) {
 			code := usecases.GetDomainErrorCode(err)
@@ -97,9 +98,11 @@ func (h *SiteHandler) CreateSite(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, dto.SiteResponse{
 		ID:                 site.ID,
+		WorkspaceID:        site.WorkspaceID,
 		Domain:             site.Domain,
 		KeywordsCount:      0,
 		LastPositionUpdate: nil,
+		BudgetAction:       site.BudgetAction,
 	})
 }
 
@@ -123,7 +126,7 @@ func (h *SiteHandler) DeleteSite(c *gin.Context) {
 		return
 	}
 
-	err = h.siteUseCase.DeleteSite(id)
+	err = h.siteUseCase.DeleteSite(middleware.WorkspaceID(c), id)
 	if err != nil {
 		if usecases.IsDomainError(err) {
 			code := usecases.GetDomainErrorCode(err)
@@ -177,9 +180,9 @@ func (h *SiteHandler) GetSites(c *gin.Context) {
 
 	var sites []*entities.Site
 	if ids != nil {
-		sites, err = h.siteUseCase.GetSitesByIDs(ids)
+		sites, err = h.siteUseCase.GetSitesByIDs(middleware.WorkspaceID(c), ids)
 	} else {
-		sites, err = h.siteUseCase.GetAllSites()
+		sites, err = h.siteUseCase.GetAllSites(middleware.WorkspaceID(c))
 	}
 
 	if err != nil {
@@ -212,13 +215,84 @@ func (h *SiteHandler) GetSites(c *gin.Context) {
 
 		response[i] = dto.SiteResponse{
 			ID:                 site.ID,
+			WorkspaceID:        site.WorkspaceID,
 			Domain:             site.Domain,
 			KeywordsCount:      keywordsCount,
 			LastPositionUpdate: lastPositionUpdate,
-			YandexDynamic:      site.YandexDynamic,
-			GoogleDynamic:      site.GoogleDynamic,
+			MonthlyBudget:      site.MonthlyBudget,
+			BudgetAction:       site.BudgetAction,
+			YandexMovement:     toMovementSummary(site.YandexMovement),
+			GoogleMovement:     toMovementSummary(site.GoogleMovement),
 		}
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+// SetSiteBudget godoc
+// @Summary Set site budget
+// @Description Set the monthly spend limit for provider requests of the site. When a new tracking job would exceed the remaining budget it is refused (refuse) or started only for the keywords that fit (trim). Spend is counted from the first day of the calendar month
+// @Tags sites
+// @Accept json
+// @Produce json
+// @Param id path int true "Site ID"
+// @Param budget body dto.SiteBudgetRequest true "Budget"
+// @Success 200 {object} dto.SiteResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/sites/{id}/budget [put]
+func (h *SiteHandler) SetSiteBudget(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid site ID",
+		})
+		return
+	}
+
+	var req dto.SiteBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	site, err := h.siteUseCase.SetBudget(middleware.WorkspaceID(c), id, req.MonthlyBudget, req.BudgetAction)
+	if err != nil {
+		if usecases.IsDomainError(err) {
+			code := usecases.GetDomainErrorCode(err)
+			status := http.StatusInternalServerError
+
+			switch code {
+			case usecases.ErrorSiteNotFound:
+				status = http.StatusNotFound
+			}
+
+			c.JSON(status, dto.ErrorResponse{
+				Error:   code,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SiteResponse{
+		ID:             site.ID,
+		WorkspaceID:    site.WorkspaceID,
+		Domain:         site.Domain,
+		MonthlyBudget:  site.MonthlyBudget,
+		BudgetAction:   site.BudgetAction,
+		YandexMovement: toMovementSummary(site.YandexMovement),
+		GoogleMovement: toMovementSummary(site.GoogleMovement),
+	})
+}
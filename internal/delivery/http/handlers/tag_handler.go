@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	tagUseCase usecases.TagUseCaseInterface
+}
+
+func NewTagHandler(tagUseCase usecases.TagUseCaseInterface) *TagHandler {
+	return &TagHandler{
+		tagUseCase: tagUseCase,
+	}
+}
+
+// CreateTag godoc
+// @Summary Создать метку
+// @Description Создать метку ключевых слов сайта. Имя уникально в сайте без учета регистра, до 100 символов
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param tag body dto.CreateTagRequest true "Метка"
+// @Success 201 {object} dto.TagResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	tag, err := h.tagUseCase.CreateTag(middleware.WorkspaceID(c), req.SiteID, req.Name)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTagResponse(tag))
+}
+
+// UpdateTag godoc
+// @Summary Переименовать метку
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID метки"
+// @Param tag body dto.UpdateTagRequest true "Метка"
+// @Success 200 {object} dto.TagResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tag ID",
+		})
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	tag, err := h.tagUseCase.UpdateTag(middleware.WorkspaceID(c), id, req.Name)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTagResponse(tag))
+}
+
+// DeleteTag godoc
+// @Summary Удалить метку
+// @Description Удалить метку; со слов она снимается, сами слова не удаляются
+// @Tags tags
+// @Param id path int true "ID метки"
+// @Success 200 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid tag ID",
+		})
+		return
+	}
+
+	if err := h.tagUseCase.DeleteTag(middleware.WorkspaceID(c), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ErrorResponse{
+		Error:   "success",
+		Message: "Tag deleted successfully",
+	})
+}
+
+// GetTags godoc
+// @Summary Получить метки сайта
+// @Description Метки сайта по имени с числом помеченных ключевых слов
+// @Tags tags
+// @Produce json
+// @Param site_id query int true "ID сайта"
+// @Success 200 {array} dto.TagResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tags [get]
+func (h *TagHandler) GetTags(c *gin.Context) {
+	siteID, err := strconv.Atoi(c.Query("site_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "site_id parameter is required and must be a number",
+		})
+		return
+	}
+
+	tags, err := h.tagUseCase.GetTagsBySite(middleware.WorkspaceID(c), siteID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := make([]dto.TagResponse, len(tags))
+	for i, tag := range tags {
+		response[i] = toTagResponse(tag)
+		count := tag.KeywordsCount
+		response[i].KeywordsCount = &count
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AssignTags godoc
+// @Summary Назначить метки ключевым словам
+// @Description Назначает каждую метку каждому слову. Слова и метки должны относиться к одному сайту, уже назначенные метки пропускаются
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body dto.AssignTagsRequest true "Слова и метки"
+// @Success 200 {object} dto.AssignTagsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tags/assign [post]
+func (h *TagHandler) AssignTags(c *gin.Context) {
+	h.changeAssignment(c, h.tagUseCase.AssignTags)
+}
+
+// UnassignTags godoc
+// @Summary Снять метки с ключевых слов
+// @Description Снимает каждую метку с каждого слова. Слова и метки должны относиться к одному сайту
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body dto.AssignTagsRequest true "Слова и метки"
+// @Success 200 {object} dto.AssignTagsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tags/unassign [post]
+func (h *TagHandler) UnassignTags(c *gin.Context) {
+	h.changeAssignment(c, h.tagUseCase.UnassignTags)
+}
+
+func (h *TagHandler) changeAssignment(c *gin.Context, change func(workspaceID *int, keywordIDs, tagIDs []int) (int, error)) {
+	var req dto.AssignTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	count, err := change(middleware.WorkspaceID(c), req.KeywordIDs, req.TagIDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AssignTagsResponse{Count: count})
+}
+
+func (h *TagHandler) handleError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch usecases.GetDomainErrorCode(err) {
+	case usecases.ErrorValidation:
+		status = http.StatusBadRequest
+	case usecases.ErrorSiteNotFound, usecases.ErrorTagNotFound, usecases.ErrorKeywordNotFound:
+		status = http.StatusNotFound
+	case usecases.ErrorTagExists:
+		status = http.StatusConflict
+	}
+	c.JSON(status, dto.ErrorResponse{
+		Error:   usecases.GetDomainErrorCode(err),
+		Message: err.Error(),
+	})
+}
+
+func toTagResponse(tag *entities.Tag) dto.TagResponse {
+	return dto.TagResponse{
+		ID:     tag.ID,
+		SiteID: tag.SiteID,
+		Name:   tag.Name,
+	}
+}
+
+// parseTagFilter разбирает ID меток из параметра tags (через запятую или повтором параметра).
+// Без меток возвращает nil - фильтр не применяется
+func parseTagFilter(values []string, match *string) (*entities.TagFilter, error) {
+	filter := &entities.TagFilter{Match: entities.TagMatchAny}
+	if match != nil && *match != "" {
+		filter.Match = *match
+	}
+	if filter.Match != entities.TagMatchAny && filter.Match != entities.TagMatchAll {
+		return nil, fmt.Errorf("tags_match must be %q or %q", entities.TagMatchAny, entities.TagMatchAll)
+	}
+
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := strconv.Atoi(part)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("invalid tag ID %q in tags parameter", part)
+			}
+			filter.TagIDs = append(filter.TagIDs, id)
+		}
+	}
+
+	if len(filter.TagIDs) == 0 {
+		return nil, nil
+	}
+	return filter, nil
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"go-seo/internal/domain/entities"
+)
+
+func TestParseTagFilter(t *testing.T) {
+	all, invalid, empty := entities.TagMatchAll, "some", ""
+
+	tests := []struct {
+		name    string
+		values  []string
+		match   *string
+		want    *entities.TagFilter
+		wantErr bool
+	}{
+		{name: "no tags", values: nil, want: nil},
+		{name: "only separators", values: []string{" , ,"}, want: nil},
+		{
+			name:   "comma separated and repeated parameter",
+			values: []string{"1, 2", "3"},
+			want:   &entities.TagFilter{TagIDs: []int{1, 2, 3}, Match: entities.TagMatchAny},
+		},
+		{
+			name:   "empty match defaults to any",
+			values: []string{"4"},
+			match:  &empty,
+			want:   &entities.TagFilter{TagIDs: []int{4}, Match: entities.TagMatchAny},
+		},
+		{
+			name:   "match all",
+			values: []string{"4,5"},
+			match:  &all,
+			want:   &entities.TagFilter{TagIDs: []int{4, 5}, Match: entities.TagMatchAll},
+		},
+		{name: "not a number", values: []string{"1,abc"}, wantErr: true},
+		{name: "zero id", values: []string{"0"}, wantErr: true},
+		{name: "negative id", values: []string{"-2"}, wantErr: true},
+		{name: "invalid match", values: []string{"1"}, match: &invalid, wantErr: true},
+		// Неверный режим - ошибка, даже если метки не заданы
+		{name: "invalid match without tags", match: &invalid, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTagFilter(tt.values, tt.match)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v, %v", tt.want, got, err)
+			}
+		})
+	}
+}
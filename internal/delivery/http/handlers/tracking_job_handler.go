@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/domain/entities"
 	"go-seo/internal/usecases"
 	"go-seo/pkg/logger"
 
@@ -13,11 +15,13 @@ import (
 
 type TrackingJobHandler struct {
 	trackingJobUseCase *usecases.TrackingJobUseCase
+	asyncTracking      *usecases.AsyncPositionTrackingUseCase
 }
 
-func NewTrackingJobHandler(trackingJobUseCase *usecases.TrackingJobUseCase) *TrackingJobHandler {
+func NewTrackingJobHandler(trackingJobUseCase *usecases.TrackingJobUseCase, asyncTracking *usecases.AsyncPositionTrackingUseCase) *TrackingJobHandler {
 	return &TrackingJobHandler{
 		trackingJobUseCase: trackingJobUseCase,
+		asyncTracking:      asyncTracking,
 	}
 }
 
@@ -28,7 +32,7 @@ func NewTrackingJobHandler(trackingJobUseCase *usecases.TrackingJobUseCase) *Tra
 // @Accept json
 // @Produce json
 // @Param site_id query int false "ID сайта для фильтрации"
-// @Param status query string false "Статус джоба (pending, running, completed, failed, cancelled)"
+// @Param status query string false "Статус джоба (pending, running, paused, completed, failed, cancelled)"
 // @Param page query int false "Номер страницы (по умолчанию 1)"
 // @Param per_page query int false "Количество записей на странице (по умолчанию 20, максимум 100)"
 // @Success 200 {object} dto.TrackingJobsResponse
@@ -49,7 +53,7 @@ func (h *TrackingJobHandler) GetTrackingJobs(c *gin.Context) {
 	}
 
 	// Получаем данные из use case
-	response, err := h.trackingJobUseCase.GetJobsWithPagination(&req)
+	response, err := h.trackingJobUseCase.GetJobsWithPagination(middleware.WorkspaceID(c), &req)
 	if err != nil {
 		logger.ErrorLogger.Printf("Failed to get tracking jobs: %v", err)
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
@@ -67,3 +71,189 @@ func (h *TrackingJobHandler) GetTrackingJobs(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// GetTrackingJob godoc
+// @Summary Получить джоб с результатами по ключевым словам
+// @Description Возвращает параметры запуска, время выполнения, провайдера и постраничный список итогов по каждому ключевому слову: позиция, URL, ошибка, число попыток
+// @Tags tracking-jobs
+// @Produce json
+// @Param id path string true "ID джоба"
+// @Param status query string false "Статус задачи (pending, completed, failed, cancelled)"
+// @Param page query int false "Номер страницы (по умолчанию 1)"
+// @Param per_page query int false "Количество записей на странице (по умолчанию 20, максимум 100)"
+// @Success 200 {object} dto.TrackingJobDetailResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tracking-jobs/{id} [get]
+func (h *TrackingJobHandler) GetTrackingJob(c *gin.Context) {
+	var req dto.TrackingJobDetailRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.ErrorLogger.Printf("Failed to bind query parameters: %v", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	job, err := h.trackingJobUseCase.GetJob(middleware.WorkspaceID(c), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	results, pagination, err := h.trackingJobUseCase.GetJobResults(job.ID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	requests, cost, err := h.trackingJobUseCase.GetJobUsage(job.ID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	var duration *float64
+	if job.StartedAt != nil {
+		end := time.Now()
+		if job.CompletedAt != nil {
+			end = *job.CompletedAt
+		}
+		seconds := end.Sub(*job.StartedAt).Seconds()
+		duration = &seconds
+	}
+
+	c.JSON(http.StatusOK, dto.TrackingJobDetailResponse{
+		TrackingJobItem: toTrackingJobItem(job),
+		FailedRequests:  job.FailedRequests,
+		StartedAt:       job.StartedAt,
+		DurationSeconds: duration,
+		Provider:        job.Params.Provider,
+		Requests:        requests,
+		Cost:            cost,
+		Params:          toTrackingParamsResponse(job.Params),
+		Results:         results,
+		Pagination:      pagination,
+	})
+}
+
+// CancelTrackingJob godoc
+// @Summary Отменить джоб
+// @Description Останавливает запуск новых задач; запросы, уже отправленные провайдеру, завершаются. Оставшиеся задачи помечаются cancelled
+// @Tags tracking-jobs
+// @Produce json
+// @Param id path string true "ID джоба"
+// @Success 200 {object} dto.TrackingJobItem
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tracking-jobs/{id}/cancel [post]
+func (h *TrackingJobHandler) CancelTrackingJob(c *gin.Context) {
+	h.changeJobStatus(c, h.asyncTracking.CancelJob)
+}
+
+// PauseTrackingJob godoc
+// @Summary Приостановить джоб
+// @Description Останавливает запуск новых задач; невыполненные задачи продолжатся после resume
+// @Tags tracking-jobs
+// @Produce json
+// @Param id path string true "ID джоба"
+// @Success 200 {object} dto.TrackingJobItem
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tracking-jobs/{id}/pause [post]
+func (h *TrackingJobHandler) PauseTrackingJob(c *gin.Context) {
+	h.changeJobStatus(c, h.asyncTracking.PauseJob)
+}
+
+// ResumeTrackingJob godoc
+// @Summary Возобновить джоб
+// @Description Возвращает приостановленный джоб в очередь; выполняются только невыполненные задачи
+// @Tags tracking-jobs
+// @Produce json
+// @Param id path string true "ID джоба"
+// @Success 200 {object} dto.TrackingJobItem
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tracking-jobs/{id}/resume [post]
+func (h *TrackingJobHandler) ResumeTrackingJob(c *gin.Context) {
+	h.changeJobStatus(c, h.asyncTracking.ResumeJob)
+}
+
+// RetryFailedTrackingJob godoc
+// @Summary Перезапустить неудачные ключевые слова джоба
+// @Description Возвращает завершенный джоб в очередь; повторно проверяются только ключевые слова со статусом failed
+// @Tags tracking-jobs
+// @Produce json
+// @Param id path string true "ID джоба"
+// @Success 200 {object} dto.TrackingJobItem
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tracking-jobs/{id}/retry-failed [post]
+func (h *TrackingJobHandler) RetryFailedTrackingJob(c *gin.Context) {
+	h.changeJobStatus(c, h.asyncTracking.RetryFailedJob)
+}
+
+func (h *TrackingJobHandler) changeJobStatus(c *gin.Context, change func(workspaceID *int, id string) (*entities.TrackingJob, error)) {
+	job, err := change(middleware.WorkspaceID(c), c.Param("id"))
+	if err != nil {
+		logger.ErrorLogger.Printf("Failed to change tracking job %s status: %v", c.Param("id"), err)
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTrackingJobItem(job))
+}
+
+func (h *TrackingJobHandler) handleError(c *gin.Context, err error) {
+	if usecases.IsDomainError(err) {
+		code := usecases.GetDomainErrorCode(err)
+		status := http.StatusInternalServerError
+
+		switch code {
+		case usecases.ErrorJobNotFound:
+			status = http.StatusNotFound
+		case usecases.ErrorJobStateConflict:
+			status = http.StatusConflict
+		}
+
+		c.JSON(status, dto.ErrorResponse{
+			Error:   code,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "internal_error",
+		Message: "Internal server error",
+	})
+}
+
+func toTrackingJobItem(job *entities.TrackingJob) dto.TrackingJobItem {
+	progress := 0.0
+	if job.TotalTasks > 0 {
+		progress = float64(job.CompletedTasks) / float64(job.TotalTasks) * 100
+	}
+
+	return dto.TrackingJobItem{
+		ID:             job.ID,
+		SiteID:         job.SiteID,
+		Source:         job.Source,
+		Status:         string(job.Status),
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+		CompletedAt:    job.CompletedAt,
+		TotalTasks:     job.TotalTasks,
+		CompletedTasks: job.CompletedTasks,
+		FailedTasks:    job.FailedTasks,
+		Error:          job.Error,
+		APIKeyID:       job.APIKeyID,
+		Progress:       progress,
+	}
+}
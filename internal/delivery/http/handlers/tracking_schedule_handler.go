@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrackingScheduleHandler struct {
+	scheduleUseCase *usecases.TrackingScheduleUseCase
+}
+
+func NewTrackingScheduleHandler(scheduleUseCase *usecases.TrackingScheduleUseCase) *TrackingScheduleHandler {
+	return &TrackingScheduleHandler{
+		scheduleUseCase: scheduleUseCase,
+	}
+}
+
+// CreateSchedule godoc
+// @Summary Create a tracking schedule
+// @Description Create a recurring schedule that starts async tracking for a site by cron expression
+// @Tags tracking-schedules
+// @Accept json
+// @Produce json
+// @Param schedule body dto.TrackingScheduleRequest true "Schedule data"
+// @Success 201 {object} dto.TrackingScheduleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tracking-schedules [post]
+func (h *TrackingScheduleHandler) CreateSchedule(c *gin.Context) {
+	var req dto.TrackingScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.Params.Device == "mobile" && req.Params.OS == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "OS parameter is required when device is mobile",
+		})
+		return
+	}
+
+	schedule, err := h.scheduleUseCase.CreateSchedule(middleware.WorkspaceID(c), toTrackingSchedule(&req))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTrackingScheduleResponse(schedule))
+}
+
+// GetSchedules godoc
+// @Summary Get tracking schedules
+// @Description Get tracking schedules, optionally filtered by site
+// @Tags tracking-schedules
+// @Produce json
+// @Param site_id query int false "Site ID"
+// @Success 200 {array} dto.TrackingScheduleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tracking-schedules [get]
+func (h *TrackingScheduleHandler) GetSchedules(c *gin.Context) {
+	var siteID *int
+	if siteIDStr := c.Query("site_id"); siteIDStr != "" {
+		parsed, err := strconv.Atoi(siteIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid site_id",
+			})
+			return
+		}
+		siteID = &parsed
+	}
+
+	schedules, err := h.scheduleUseCase.GetSchedules(middleware.WorkspaceID(c), siteID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := make([]dto.TrackingScheduleResponse, len(schedules))
+	for i, schedule := range schedules {
+		response[i] = toTrackingScheduleResponse(schedule)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSchedule godoc
+// @Summary Get a tracking schedule
+// @Description Get tracking schedule with last and next run
+// @Tags tracking-schedules
+// @Produce json
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} dto.TrackingScheduleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/tracking-schedules/{id} [get]
+func (h *TrackingScheduleHandler) GetSchedule(c *gin.Context) {
+	id, ok := parseScheduleID(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.scheduleUseCase.GetSchedule(middleware.WorkspaceID(c), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTrackingScheduleResponse(schedule))
+}
+
+// UpdateSchedule godoc
+// @Summary Update a tracking schedule
+// @Description Replace schedule source, parameters, cron expression and timezone
+// @Tags tracking-schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Schedule ID"
+// @Param schedule body dto.TrackingScheduleRequest true "Schedule data"
+// @Success 200 {object} dto.TrackingScheduleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tracking-schedules/{id} [put]
+func (h *TrackingScheduleHandler) UpdateSchedule(c *gin.Context) {
+	id, ok := parseScheduleID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.TrackingScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.Params.Device == "mobile" && req.Params.OS == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "OS parameter is required when device is mobile",
+		})
+		return
+	}
+
+	schedule, err := h.scheduleUseCase.UpdateSchedule(middleware.WorkspaceID(c), id, toTrackingSchedule(&req))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTrackingScheduleResponse(schedule))
+}
+
+// DeleteSchedule godoc
+// @Summary Delete a tracking schedule
+// @Description Delete a tracking schedule; already started jobs are not affected
+// @Tags tracking-schedules
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tracking-schedules/{id} [delete]
+func (h *TrackingScheduleHandler) DeleteSchedule(c *gin.Context) {
+	id, ok := parseScheduleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.scheduleUseCase.DeleteSchedule(middleware.WorkspaceID(c), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ErrorResponse{
+		Error:   "success",
+		Message: "Tracking schedule deleted successfully",
+	})
+}
+
+// PauseSchedule godoc
+// @Summary Pause a tracking schedule
+// @Description Disable a schedule without deleting it
+// @Tags tracking-schedules
+// @Produce json
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} dto.TrackingScheduleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tracking-schedules/{id}/pause [post]
+func (h *TrackingScheduleHandler) PauseSchedule(c *gin.Context) {
+	h.setEnabled(c, false)
+}
+
+// ResumeSchedule godoc
+// @Summary Resume a tracking schedule
+// @Description Enable a paused schedule; the next run is calculated from now
+// @Tags tracking-schedules
+// @Produce json
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} dto.TrackingScheduleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/tracking-schedules/{id}/resume [post]
+func (h *TrackingScheduleHandler) ResumeSchedule(c *gin.Context) {
+	h.setEnabled(c, true)
+}
+
+func (h *TrackingScheduleHandler) setEnabled(c *gin.Context, enabled bool) {
+	id, ok := parseScheduleID(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.scheduleUseCase.SetEnabled(middleware.WorkspaceID(c), id, enabled)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTrackingScheduleResponse(schedule))
+}
+
+func (h *TrackingScheduleHandler) handleError(c *gin.Context, err error) {
+	if !usecases.IsDomainError(err) {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	code := usecases.GetDomainErrorCode(err)
+	status := http.StatusInternalServerError
+
+	switch code {
+	case usecases.ErrorValidation, usecases.ErrorProviderNotFound, usecases.ErrorProviderUnsupported:
+		status = http.StatusBadRequest
+	case usecases.ErrorSiteNotFound, usecases.ErrorScheduleNotFound:
+		status = http.StatusNotFound
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Error:   code,
+		Message: err.Error(),
+	})
+}
+
+func parseScheduleID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid schedule ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func toTrackingSchedule(req *dto.TrackingScheduleRequest) *entities.TrackingSchedule {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	defaultQuery := true
+	if req.Params.Default != nil {
+		defaultQuery = *req.Params.Default
+	}
+
+	p := req.Params
+	return &entities.TrackingSchedule{
+		SiteID:   req.SiteID,
+		Source:   req.Source,
+		CronExpr: req.CronExpr,
+		Timezone: req.Timezone,
+		Enabled:  enabled,
+		Params: entities.TrackingParams{
+			Device:                 p.Device,
+			OS:                     p.OS,
+			Ads:                    p.Ads,
+			Country:                p.Country,
+			Lang:                   p.Lang,
+			Pages:                  p.Pages,
+			Subdomains:             p.Subdomains,
+			AccountID:              p.AccountID,
+			Provider:               p.Provider,
+			TBS:                    p.TBS,
+			Filter:                 p.Filter,
+			Highlights:             p.Highlights,
+			NFPR:                   p.NFPR,
+			Loc:                    p.Loc,
+			AI:                     p.AI,
+			Raw:                    p.Raw,
+			GroupBy:                p.GroupBy,
+			Within:                 p.Within,
+			LR:                     p.LR,
+			Domain:                 p.Domain,
+			YandexDomain:           p.YandexDomain,
+			InIndex:                p.InIndex,
+			Strict:                 p.Strict,
+			Organic:                p.Organic,
+			Regions:                p.Regions,
+			FilterGroupID:          p.FilterGroupID,
+			DefaultQuery:           defaultQuery,
+			Quotes:                 p.Quotes,
+			QuotesExclamationMarks: p.QuotesExclamationMarks,
+			ExclamationMarks:       p.ExclamationMarks,
+		},
+	}
+}
+
+func toTrackingScheduleResponse(schedule *entities.TrackingSchedule) dto.TrackingScheduleResponse {
+	return dto.TrackingScheduleResponse{
+		ID:        schedule.ID,
+		SiteID:    schedule.SiteID,
+		Source:    schedule.Source,
+		CronExpr:  schedule.CronExpr,
+		Timezone:  schedule.Timezone,
+		Enabled:   schedule.Enabled,
+		Params:    toTrackingParamsResponse(schedule.Params),
+		LastRunAt: schedule.LastRunAt,
+		NextRunAt: schedule.NextRunAt,
+		LastJobID: schedule.LastJobID,
+		LastError: schedule.LastError,
+		CreatedAt: schedule.CreatedAt,
+		UpdatedAt: schedule.UpdatedAt,
+	}
+}
+
+// toTrackingParamsResponse возвращает параметры запуска; учетные данные хранятся только в аккаунте провайдера
+func toTrackingParamsResponse(p entities.TrackingParams) dto.TrackingParams {
+	defaultQuery := p.DefaultQuery
+
+	return dto.TrackingParams{
+		Pages:                  p.Pages,
+		Device:                 p.Device,
+		OS:                     p.OS,
+		Ads:                    p.Ads,
+		Country:                p.Country,
+		Lang:                   p.Lang,
+		Subdomains:             p.Subdomains,
+		AccountID:              p.AccountID,
+		Provider:               p.Provider,
+		TBS:                    p.TBS,
+		Filter:                 p.Filter,
+		Highlights:             p.Highlights,
+		NFPR:                   p.NFPR,
+		Loc:                    p.Loc,
+		AI:                     p.AI,
+		Raw:                    p.Raw,
+		GroupBy:                p.GroupBy,
+		Within:                 p.Within,
+		LR:                     p.LR,
+		Domain:                 p.Domain,
+		YandexDomain:           p.YandexDomain,
+		InIndex:                p.InIndex,
+		Strict:                 p.Strict,
+		Organic:                p.Organic,
+		Regions:                p.Regions,
+		FilterGroupID:          p.FilterGroupID,
+		Default:                &defaultQuery,
+		Quotes:                 p.Quotes,
+		QuotesExclamationMarks: p.QuotesExclamationMarks,
+		ExclamationMarks:       p.ExclamationMarks,
+	}
+}
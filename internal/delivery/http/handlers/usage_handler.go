@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UsageHandler struct {
+	usageUseCase *usecases.UsageUseCase
+}
+
+func NewUsageHandler(usageUseCase *usecases.UsageUseCase) *UsageHandler {
+	return &UsageHandler{
+		usageUseCase: usageUseCase,
+	}
+}
+
+// GetUsage godoc
+// @Summary Получить расход запросов к провайдерам
+// @Description Возвращает число запросов и стоимость по дням, сайтам и источникам из журнала расхода. Без дат возвращается текущий месяц
+// @Tags usage
+// @Produce json
+// @Param site_id query int false "ID сайта"
+// @Param source query string false "Источник" Enums(google, yandex, wordstat)
+// @Param date_from query string false "Начало периода (YYYY-MM-DD)"
+// @Param date_to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Success 200 {object} dto.UsageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/usage [get]
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	var req dto.UsageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var dateFrom, dateTo *time.Time
+	if req.DateFrom != nil {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.DateFrom, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid date_from parameter. Use YYYY-MM-DD format",
+			})
+			return
+		}
+		dateFrom = &parsed
+	}
+	if req.DateTo != nil {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.DateTo, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid date_to parameter. Use YYYY-MM-DD format",
+			})
+			return
+		}
+		dateTo = &parsed
+	}
+
+	summary, err := h.usageUseCase.GetUsage(middleware.WorkspaceID(c), req.SiteID, req.Source, dateFrom, dateTo)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if usecases.GetDomainErrorCode(err) == usecases.ErrorValidation {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Error:   usecases.GetDomainErrorCode(err),
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response := dto.UsageResponse{
+		Data: make([]dto.UsageItem, 0, len(summary)),
+	}
+	for _, row := range summary {
+		response.Data = append(response.Data, dto.UsageItem{
+			Date:     row.Date.Format("2006-01-02"),
+			SiteID:   row.SiteID,
+			Source:   row.Source,
+			Requests: row.Requests,
+			Cost:     row.Cost,
+		})
+		response.Totals.Requests += row.Requests
+		response.Totals.Cost += row.Cost
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VisibilityHandler struct {
+	visibilityUseCase *usecases.VisibilityUseCase
+}
+
+func NewVisibilityHandler(visibilityUseCase *usecases.VisibilityUseCase) *VisibilityHandler {
+	return &VisibilityHandler{
+		visibilityUseCase: visibilityUseCase,
+	}
+}
+
+// GetVisibility godoc
+// @Summary Получить видимость сайта по дням
+// @Description Видимость - ожидаемый трафик по кривой CTR позиций с весом частоты Wordstat в процентах от трафика при первых позициях по всем ключевым словам. Ключевые слова без частоты Wordstat в трафик не входят. Значения сохраняются по дням; дни, которые еще не считались, рассчитываются при запросе. Период не больше 366 дней
+// @Tags positions
+// @Produce json
+// @Param site_id query int true "ID сайта"
+// @Param source query string false "Источник" Enums(google, yandex)
+// @Param group_id query int false "ID группы ключевых слов"
+// @Param filter_group_id query int false "ID группы фильтров"
+// @Param date_from query string false "Начало периода (YYYY-MM-DD)"
+// @Param date_to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Success 200 {object} dto.VisibilityResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/positions/visibility [get]
+func (h *VisibilityHandler) GetVisibility(c *gin.Context) {
+	var req dto.VisibilityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var dateFrom, dateTo *time.Time
+	if req.DateFrom != nil {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.DateFrom, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid date_from parameter. Use YYYY-MM-DD format",
+			})
+			return
+		}
+		dateFrom = &parsed
+	}
+	if req.DateTo != nil {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.DateTo, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid date_to parameter. Use YYYY-MM-DD format",
+			})
+			return
+		}
+		dateTo = &parsed
+	}
+
+	series, err := h.visibilityUseCase.GetVisibility(middleware.WorkspaceID(c), req.SiteID, req.Source, req.GroupID, req.FilterGroupID, dateFrom, dateTo)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch usecases.GetDomainErrorCode(err) {
+		case usecases.ErrorValidation:
+			status = http.StatusBadRequest
+		case usecases.ErrorSiteNotFound:
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Error:   usecases.GetDomainErrorCode(err),
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response := dto.VisibilityResponse{
+		SiteID:        req.SiteID,
+		GroupID:       req.GroupID,
+		FilterGroupID: req.FilterGroupID,
+		Data:          make([]dto.VisibilityPoint, 0, len(series)),
+	}
+	for _, point := range series {
+		response.Data = append(response.Data, dto.VisibilityPoint{
+			Date:             point.Date.Format("2006-01-02"),
+			Source:           point.Source,
+			KeywordsCount:    point.KeywordsCount,
+			FoundCount:       point.FoundCount,
+			Top10Count:       point.Top10Count,
+			Frequency:        point.Frequency,
+			EstimatedTraffic: point.EstimatedTraffic,
+			Visibility:       point.Visibility,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
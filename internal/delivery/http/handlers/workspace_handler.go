@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkspaceHandler struct {
+	workspaceUseCase *usecases.WorkspaceUseCase
+}
+
+func NewWorkspaceHandler(workspaceUseCase *usecases.WorkspaceUseCase) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceUseCase: workspaceUseCase,
+	}
+}
+
+// GetWorkspaces godoc
+// @Summary Получить пространства
+// @Description Доступно только системному ключу
+// @Tags workspaces
+// @Produce json
+// @Success 200 {array} dto.WorkspaceResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/workspaces [get]
+func (h *WorkspaceHandler) GetWorkspaces(c *gin.Context) {
+	workspaces, err := h.workspaceUseCase.GetWorkspaces()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := make([]dto.WorkspaceResponse, len(workspaces))
+	for i, workspace := range workspaces {
+		response[i] = toWorkspaceResponse(workspace)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetWorkspace godoc
+// @Summary Получить пространство
+// @Tags workspaces
+// @Produce json
+// @Param id path int true "ID пространства"
+// @Success 200 {object} dto.WorkspaceResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/workspaces/{id} [get]
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	id, ok := parseWorkspaceID(c)
+	if !ok {
+		return
+	}
+
+	workspace, err := h.workspaceUseCase.GetWorkspace(id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWorkspaceResponse(workspace))
+}
+
+// CreateWorkspace godoc
+// @Summary Создать пространство
+// @Description Сайты, аккаунты провайдеров и ключи API пространства изолированы от других пространств. Ключи пространства выпускаются запросом к /api/api-keys с заголовком X-Workspace-ID
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param workspace body dto.WorkspaceRequest true "Пространство"
+// @Success 201 {object} dto.WorkspaceResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/workspaces [post]
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	var req dto.WorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	workspace, err := h.workspaceUseCase.CreateWorkspace(req.Name, req.MonthlyBudget)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toWorkspaceResponse(workspace))
+}
+
+// UpdateWorkspace godoc
+// @Summary Обновить пространство
+// @Description Заменяет название и общий месячный бюджет пространства
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пространства"
+// @Param workspace body dto.WorkspaceRequest true "Пространство"
+// @Success 200 {object} dto.WorkspaceResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/workspaces/{id} [put]
+func (h *WorkspaceHandler) UpdateWorkspace(c *gin.Context) {
+	id, ok := parseWorkspaceID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.WorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	workspace, err := h.workspaceUseCase.UpdateWorkspace(id, req.Name, req.MonthlyBudget)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWorkspaceResponse(workspace))
+}
+
+// DeleteWorkspace godoc
+// @Summary Удалить пространство
+// @Description Удаляет пустое пространство: без сайтов, аккаунтов провайдеров и действующих ключей. Пространство по умолчанию удалить нельзя
+// @Tags workspaces
+// @Param id path int true "ID пространства"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/workspaces/{id} [delete]
+func (h *WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
+	id, ok := parseWorkspaceID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.workspaceUseCase.DeleteWorkspace(id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WorkspaceHandler) handleError(c *gin.Context, err error) {
+	if !usecases.IsDomainError(err) {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	code := usecases.GetDomainErrorCode(err)
+	status := http.StatusInternalServerError
+
+	switch code {
+	case usecases.ErrorValidation:
+		status = http.StatusBadRequest
+	case usecases.ErrorWorkspaceNotFound:
+		status = http.StatusNotFound
+	case usecases.ErrorWorkspaceExists, usecases.ErrorWorkspaceNotEmpty:
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Error:   code,
+		Message: err.Error(),
+	})
+}
+
+func parseWorkspaceID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid workspace ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func toWorkspaceResponse(workspace *entities.Workspace) dto.WorkspaceResponse {
+	return dto.WorkspaceResponse{
+		ID:            workspace.ID,
+		Name:          workspace.Name,
+		MonthlyBudget: workspace.MonthlyBudget,
+		CreatedAt:     workspace.CreatedAt,
+		UpdatedAt:     workspace.UpdatedAt,
+	}
+}
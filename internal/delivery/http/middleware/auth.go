@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+const apiKeyContextKey = "api_key"
+
+// APIKeyAuth проверяет ключ из заголовка Authorization: Bearer <key> или X-API-Key
+// и сохраняет его в контексте запроса для RequireScope и обработчиков
+func APIKeyAuth(apiKeys *usecases.APIKeyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, err := apiKeys.Authenticate(extractAPIKey(c))
+		if err != nil {
+			status := http.StatusInternalServerError
+			code := "internal_error"
+			if usecases.IsDomainError(err) {
+				code = usecases.GetDomainErrorCode(err)
+				if code == usecases.ErrorUnauthorized {
+					status = http.StatusUnauthorized
+				}
+			}
+			c.AbortWithStatusJSON(status, dto.ErrorResponse{
+				Error:   code,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// RequireScope пропускает запрос, только если у ключа есть право scope (admin дает все права).
+// Без APIKeyAuth в цепочке (авторизация выключена) запрос пропускается
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get(apiKeyContextKey); !exists {
+			c.Next()
+			return
+		}
+
+		key := CurrentAPIKey(c)
+		if key == nil || !key.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+				Error:   usecases.ErrorForbidden,
+				Message: "API key does not have scope " + scope,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CurrentAPIKey ключ, которым авторизован запрос; nil, если авторизация выключена
+func CurrentAPIKey(c *gin.Context) *entities.APIKey {
+	value, exists := c.Get(apiKeyContextKey)
+	if !exists {
+		return nil
+	}
+	key, _ := value.(*entities.APIKey)
+	return key
+}
+
+// CurrentAPIKeyID ID ключа запроса для записи в джобы
+func CurrentAPIKeyID(c *gin.Context) *int {
+	if key := CurrentAPIKey(c); key != nil {
+		id := key.ID
+		return &id
+	}
+	return nil
+}
+
+func extractAPIKey(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); header != "" {
+		if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return strings.TrimSpace(c.GetHeader("X-API-Key"))
+}
@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// fakeAPIKeyRepository хранит ключи в памяти
+type fakeAPIKeyRepository struct {
+	keys      []*entities.APIKey
+	lookupErr error
+}
+
+func (r *fakeAPIKeyRepository) Create(key *entities.APIKey) error {
+	key.ID = len(r.keys) + 1
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *fakeAPIKeyRepository) GetByID(id int) (*entities.APIKey, error) {
+	for _, key := range r.keys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAPIKeyRepository) GetByHash(hash string) (*entities.APIKey, error) {
+	if r.lookupErr != nil {
+		return nil, r.lookupErr
+	}
+	for _, key := range r.keys {
+		if key.KeyHash == hash {
+			return key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAPIKeyRepository) GetAll(workspaceID *int) ([]*entities.APIKey, error) {
+	return r.keys, nil
+}
+
+func (r *fakeAPIKeyRepository) Revoke(id int, at time.Time) (bool, error) {
+	key, err := r.GetByID(id)
+	if err != nil {
+		return false, err
+	}
+	key.RevokedAt = &at
+	return true, nil
+}
+
+func (r *fakeAPIKeyRepository) UpdateLastUsed(id int, at time.Time) error {
+	return nil
+}
+
+type testKeys struct {
+	repo    *fakeAPIKeyRepository
+	apiKeys *usecases.APIKeyUseCase
+	raw     map[string]string
+}
+
+// newTestKeys выпускает ключи reader (positions:read), admin и revoked (отозванный admin)
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	repo := &fakeAPIKeyRepository{}
+	keys := &testKeys{repo: repo, apiKeys: usecases.NewAPIKeyUseCase(repo), raw: map[string]string{}}
+	for _, name := range []string{"reader", "admin", "revoked"} {
+		scope := entities.ScopeAdmin
+		if name == "reader" {
+			scope = entities.ScopePositionsRead
+		}
+		key, raw, err := keys.apiKeys.IssueKey(nil, name, []string{scope})
+		if err != nil {
+			t.Fatalf("issue %s key: %v", name, err)
+		}
+		if name == "revoked" {
+			repo.Revoke(key.ID, time.Now())
+		}
+		keys.raw[name] = raw
+	}
+	return keys
+}
+
+func serve(router *gin.Engine, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	keys := newTestKeys(t)
+
+	var current *entities.APIKey
+	router := gin.New()
+	router.GET("/test", APIKeyAuth(keys.apiKeys), func(c *gin.Context) {
+		current = CurrentAPIKey(c)
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+		keyName string
+	}{
+		{name: "bearer", headers: map[string]string{"Authorization": "Bearer " + keys.raw["reader"]}, status: http.StatusOK, keyName: "reader"},
+		{name: "x-api-key", headers: map[string]string{"X-API-Key": keys.raw["admin"]}, status: http.StatusOK, keyName: "admin"},
+		{
+			name:    "bearer wins over x-api-key",
+			headers: map[string]string{"Authorization": "Bearer " + keys.raw["reader"], "X-API-Key": keys.raw["admin"]},
+			status:  http.StatusOK,
+			keyName: "reader",
+		},
+		{
+			name:    "other scheme falls back to x-api-key",
+			headers: map[string]string{"Authorization": "Basic dXNlcjpwYXNz", "X-API-Key": keys.raw["admin"]},
+			status:  http.StatusOK,
+			keyName: "admin",
+		},
+		{name: "missing key", status: http.StatusUnauthorized},
+		{name: "unknown key", headers: map[string]string{"X-API-Key": "gs_unknown"}, status: http.StatusUnauthorized},
+		{name: "revoked key", headers: map[string]string{"Authorization": "Bearer " + keys.raw["revoked"]}, status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current = nil
+			w := serve(router, tt.headers)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.keyName == "" {
+				if current != nil {
+					t.Fatalf("handler must not run, got key %+v", current)
+				}
+				return
+			}
+			if current == nil || current.Name != tt.keyName {
+				t.Fatalf("expected key %s in context, got %+v", tt.keyName, current)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthRepositoryError(t *testing.T) {
+	keys := newTestKeys(t)
+	keys.repo.lookupErr = errors.New("connection refused")
+
+	router := gin.New()
+	router.GET("/test", APIKeyAuth(keys.apiKeys), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	if w := serve(router, map[string]string{"X-API-Key": keys.raw["reader"]}); w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	keys := newTestKeys(t)
+
+	tests := []struct {
+		name    string
+		keyName string
+		scope   string
+		status  int
+	}{
+		{name: "granted scope", keyName: "reader", scope: entities.ScopePositionsRead, status: http.StatusOK},
+		{name: "missing scope", keyName: "reader", scope: entities.ScopeTrackingStart, status: http.StatusForbidden},
+		{name: "reader is not admin", keyName: "reader", scope: entities.ScopeAdmin, status: http.StatusForbidden},
+		{name: "admin implies tracking", keyName: "admin", scope: entities.ScopeTrackingStart, status: http.StatusOK},
+		{name: "admin implies keywords", keyName: "admin", scope: entities.ScopeKeywordsManage, status: http.StatusOK},
+		{name: "admin scope", keyName: "admin", scope: entities.ScopeAdmin, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/test", APIKeyAuth(keys.apiKeys), RequireScope(tt.scope), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := serve(router, map[string]string{"X-API-Key": keys.raw[tt.keyName]})
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequireScopeWithoutAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/test", RequireScope(entities.ScopeAdmin), func(c *gin.Context) {
+		if CurrentAPIKey(c) != nil || CurrentAPIKeyID(c) != nil {
+			t.Error("expected no API key when auth is disabled")
+		}
+		c.Status(http.StatusOK)
+	})
+
+	if w := serve(router, nil); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 with auth disabled, got %d", w.Code)
+	}
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+const workspaceContextKey = "workspace_id"
+
+// WorkspaceScope определяет пространство запроса. Ключ пространства видит только свои данные;
+// системный ключ (и запрос без авторизации) работает со всеми пространствами или выбирает одно заголовком X-Workspace-ID
+func WorkspaceScope(workspaces *usecases.WorkspaceUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("X-Workspace-ID")
+
+		if key := CurrentAPIKey(c); key != nil && key.WorkspaceID != nil {
+			if header != "" && header != strconv.Itoa(*key.WorkspaceID) {
+				c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+					Error:   usecases.ErrorForbidden,
+					Message: "API key belongs to another workspace",
+				})
+				return
+			}
+			c.Set(workspaceContextKey, *key.WorkspaceID)
+			c.Next()
+			return
+		}
+
+		if header != "" {
+			id, err := strconv.Atoi(header)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+					Error:   "invalid_workspace_id",
+					Message: "X-Workspace-ID must be a number",
+				})
+				return
+			}
+
+			if _, err := workspaces.GetWorkspace(id); err != nil {
+				status := http.StatusInternalServerError
+				code := "internal_error"
+				if usecases.IsDomainError(err) {
+					code = usecases.GetDomainErrorCode(err)
+					if code == usecases.ErrorWorkspaceNotFound {
+						status = http.StatusNotFound
+					}
+				}
+				c.AbortWithStatusJSON(status, dto.ErrorResponse{
+					Error:   code,
+					Message: err.Error(),
+				})
+				return
+			}
+			c.Set(workspaceContextKey, id)
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSystemKey пропускает только системные ключи с правом admin, не привязанные к пространству.
+// Без APIKeyAuth в цепочке (авторизация выключена) запрос пропускается
+func RequireSystemKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get(apiKeyContextKey); !exists {
+			c.Next()
+			return
+		}
+
+		key := CurrentAPIKey(c)
+		if key == nil || key.WorkspaceID != nil || !key.HasScope(entities.ScopeAdmin) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+				Error:   usecases.ErrorForbidden,
+				Message: "Only a system API key can manage workspaces",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// WorkspaceID пространство запроса; nil - запрос видит все пространства
+func WorkspaceID(c *gin.Context) *int {
+	value, exists := c.Get(workspaceContextKey)
+	if !exists {
+		return nil
+	}
+	id, ok := value.(int)
+	if !ok {
+		return nil
+	}
+	return &id
+}
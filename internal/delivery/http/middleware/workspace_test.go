@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// fakeWorkspaceRepository реализует только GetByID, которым WorkspaceScope проверяет X-Workspace-ID
+type fakeWorkspaceRepository struct {
+	repositories.WorkspaceRepository
+	workspaces map[int]*entities.Workspace
+}
+
+func (r *fakeWorkspaceRepository) GetByID(id int) (*entities.Workspace, error) {
+	if workspace, ok := r.workspaces[id]; ok {
+		return workspace, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// newWorkspaceRouter отдает на /test пространство запроса: -1, если запрос видит все пространства.
+// Кроме ключей newTestKeys выпускает ключ tenant пространства 1
+func newWorkspaceRouter(t *testing.T) (*gin.Engine, *testKeys) {
+	t.Helper()
+	keys := newTestKeys(t)
+
+	tenantWorkspace := 1
+	_, raw, err := keys.apiKeys.IssueKey(&tenantWorkspace, "tenant", []string{entities.ScopeAdmin})
+	if err != nil {
+		t.Fatalf("issue tenant key: %v", err)
+	}
+	keys.raw["tenant"] = raw
+
+	workspaces := usecases.NewWorkspaceUseCase(&fakeWorkspaceRepository{workspaces: map[int]*entities.Workspace{
+		1: {ID: 1, Name: "first"},
+		2: {ID: 2, Name: "second"},
+	}}, nil, nil, nil)
+
+	router := gin.New()
+	router.GET("/test", APIKeyAuth(keys.apiKeys), WorkspaceScope(workspaces), func(c *gin.Context) {
+		id := -1
+		if workspaceID := WorkspaceID(c); workspaceID != nil {
+			id = *workspaceID
+		}
+		c.JSON(http.StatusOK, gin.H{"workspace_id": id})
+	})
+	return router, keys
+}
+
+func TestWorkspaceScope(t *testing.T) {
+	router, keys := newWorkspaceRouter(t)
+
+	tests := []struct {
+		name      string
+		keyName   string
+		header    string
+		status    int
+		workspace string
+	}{
+		{name: "workspace key is scoped to its workspace", keyName: "tenant", status: http.StatusOK, workspace: "1"},
+		{name: "workspace key with its own header", keyName: "tenant", header: "1", status: http.StatusOK, workspace: "1"},
+		{name: "workspace key with another workspace header", keyName: "tenant", header: "2", status: http.StatusForbidden},
+		{name: "workspace key with invalid header", keyName: "tenant", header: "abc", status: http.StatusForbidden},
+		{name: "system key sees every workspace", keyName: "admin", status: http.StatusOK, workspace: "-1"},
+		{name: "system key selects a workspace", keyName: "admin", header: "2", status: http.StatusOK, workspace: "2"},
+		{name: "system key and unknown workspace", keyName: "admin", header: "9", status: http.StatusNotFound},
+		{name: "system key and invalid header", keyName: "admin", header: "abc", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{"X-API-Key": keys.raw[tt.keyName]}
+			if tt.header != "" {
+				headers["X-Workspace-ID"] = tt.header
+			}
+
+			w := serve(router, headers)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.workspace != "" && w.Body.String() != `{"workspace_id":`+tt.workspace+`}` {
+				t.Fatalf("expected workspace %s, got %s", tt.workspace, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequireSystemKey(t *testing.T) {
+	keys := newTestKeys(t)
+	tenantWorkspace := 1
+	_, tenantRaw, err := keys.apiKeys.IssueKey(&tenantWorkspace, "tenant", []string{entities.ScopeAdmin})
+	if err != nil {
+		t.Fatalf("issue tenant key: %v", err)
+	}
+
+	router := gin.New()
+	router.GET("/test", APIKeyAuth(keys.apiKeys), RequireSystemKey(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name   string
+		raw    string
+		status int
+	}{
+		{name: "system admin key", raw: keys.raw["admin"], status: http.StatusOK},
+		{name: "system key without admin", raw: keys.raw["reader"], status: http.StatusForbidden},
+		{name: "workspace admin key", raw: tenantRaw, status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(router, map[string]string{"X-API-Key": tt.raw}); w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+
+	unauthenticated := gin.New()
+	unauthenticated.GET("/test", RequireSystemKey(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	if w := serve(unauthenticated, nil); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 with auth disabled, got %d", w.Code)
+	}
+}
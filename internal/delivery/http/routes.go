@@ -2,6 +2,8 @@ package http
 
 import (
 	"go-seo/internal/delivery/http/handlers"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/domain/entities"
 	"go-seo/internal/usecases"
 
 	"github.com/gin-gonic/gin"
@@ -9,59 +11,161 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRoutes(r *gin.Engine, useCases *usecases.Container) {
+// SetupRoutes регистрирует маршруты. При authEnabled все /api запросы требуют ключ API,
+// а каждый маршрут - право ключа; /health и /swagger остаются открытыми.
+// Данные запроса ограничены пространством ключа (или X-Workspace-ID для системного ключа)
+func SetupRoutes(r *gin.Engine, useCases *usecases.Container, authEnabled bool) {
 	siteHandler := handlers.NewSiteHandler(useCases.Site)
 	keywordHandler := handlers.NewKeywordHandler(useCases.Keyword)
 	groupHandler := handlers.NewGroupHandler(useCases.Group)
+	tagHandler := handlers.NewTagHandler(useCases.Tag)
 	positionHandler := handlers.NewPositionHandler(useCases.PositionTracking, useCases.AsyncPositionTracking)
-	trackingJobHandler := handlers.NewTrackingJobHandler(useCases.TrackingJob)
+	trackingJobHandler := handlers.NewTrackingJobHandler(useCases.TrackingJob, useCases.AsyncPositionTracking)
+	providerHandler := handlers.NewProviderHandler(useCases.Provider)
+	serpSnapshotHandler := handlers.NewSerpSnapshotHandler(useCases.SerpSnapshot)
+	competitorHandler := handlers.NewCompetitorHandler(useCases.Competitor)
+	trackingScheduleHandler := handlers.NewTrackingScheduleHandler(useCases.TrackingSchedule)
+	usageHandler := handlers.NewUsageHandler(useCases.Usage)
+	visibilityHandler := handlers.NewVisibilityHandler(useCases.Visibility)
+	movementHandler := handlers.NewMovementHandler(useCases.Movement)
+	landingPageHandler := handlers.NewLandingPageHandler(useCases.LandingPage)
+	suggestionHandler := handlers.NewKeywordSuggestionHandler(useCases.KeywordSuggestion)
+	clusterHandler := handlers.NewKeywordClusterHandler(useCases.KeywordCluster)
+	providerAccountHandler := handlers.NewProviderAccountHandler(useCases.ProviderAccount)
+	apiKeyHandler := handlers.NewAPIKeyHandler(useCases.APIKey)
+	workspaceHandler := handlers.NewWorkspaceHandler(useCases.Workspace)
 	debugHandler := handlers.NewDebugHandler(useCases.Debug)
 
+	read := middleware.RequireScope(entities.ScopePositionsRead)
+	manage := middleware.RequireScope(entities.ScopeKeywordsManage)
+	track := middleware.RequireScope(entities.ScopeTrackingStart)
+	admin := middleware.RequireScope(entities.ScopeAdmin)
+	system := middleware.RequireSystemKey()
+
 	api := r.Group("/api")
+	if authEnabled {
+		api.Use(middleware.APIKeyAuth(useCases.APIKey))
+	}
+	api.Use(middleware.WorkspaceScope(useCases.Workspace))
 	{
 		sites := api.Group("/sites")
 		{
-			sites.POST("", siteHandler.CreateSite)
-			sites.GET("", siteHandler.GetSites)
-			sites.DELETE("/:id", siteHandler.DeleteSite)
+			sites.POST("", manage, siteHandler.CreateSite)
+			sites.GET("", read, siteHandler.GetSites)
+			sites.DELETE("/:id", admin, siteHandler.DeleteSite) // Удаляет всю историю сайта
+			sites.PUT("/:id/budget", admin, siteHandler.SetSiteBudget)
+			sites.GET("/:id/competitors", read, competitorHandler.GetCompetitors)
+			sites.POST("/:id/competitors", manage, competitorHandler.CreateCompetitor)
+			sites.PUT("/:id/competitors/:competitor_id", manage, competitorHandler.UpdateCompetitor)
+			sites.DELETE("/:id/competitors/:competitor_id", manage, competitorHandler.DeleteCompetitor)
+			sites.GET("/:id/url-changes", read, landingPageHandler.GetURLChanges)
+			sites.GET("/:id/cannibalization", read, landingPageHandler.GetCannibalization)
+			sites.POST("/:id/keyword-suggestions", manage, suggestionHandler.CollectSuggestions)
+			sites.GET("/:id/keyword-suggestions", read, suggestionHandler.GetSuggestions)
+			sites.POST("/:id/keyword-suggestions/accept", manage, suggestionHandler.AcceptSuggestions)
+			sites.POST("/:id/keyword-suggestions/dismiss", manage, suggestionHandler.DismissSuggestions)
+			sites.POST("/:id/clusters", manage, clusterHandler.ClusterKeywords)
+			sites.GET("/:id/clusters", read, clusterHandler.GetClusters)
+			sites.POST("/:id/clusters/apply", manage, clusterHandler.ApplyClusters)
 		}
 
 		groups := api.Group("/groups")
 		{
-			groups.POST("", groupHandler.CreateGroup)
-			groups.GET("", groupHandler.GetGroups)
-			groups.PUT("/:id", groupHandler.UpdateGroup)
-			groups.DELETE("/:id", groupHandler.DeleteGroup)
+			groups.POST("", manage, groupHandler.CreateGroup)
+			groups.GET("", read, groupHandler.GetGroups)
+			groups.PUT("/:id", manage, groupHandler.UpdateGroup)
+			groups.DELETE("/:id", manage, groupHandler.DeleteGroup)
+		}
+
+		tags := api.Group("/tags")
+		{
+			tags.POST("", manage, tagHandler.CreateTag)
+			tags.GET("", read, tagHandler.GetTags)
+			tags.POST("/assign", manage, tagHandler.AssignTags)
+			tags.POST("/unassign", manage, tagHandler.UnassignTags)
+			tags.PUT("/:id", manage, tagHandler.UpdateTag)
+			tags.DELETE("/:id", manage, tagHandler.DeleteTag)
 		}
 
 		keywords := api.Group("/keywords")
 		{
-			keywords.POST("", keywordHandler.CreateKeyword)
-			keywords.POST("/batch", keywordHandler.CreateKeywordsBatch)
-			keywords.GET("", keywordHandler.GetKeywords)
-			keywords.PUT("/:id", keywordHandler.UpdateKeyword)
-			keywords.DELETE("/:id", keywordHandler.DeleteKeyword)
+			keywords.POST("", manage, keywordHandler.CreateKeyword)
+			keywords.POST("/batch", manage, keywordHandler.CreateKeywordsBatch)
+			keywords.POST("/import", manage, keywordHandler.ImportKeywords)
+			keywords.POST("/merge", manage, keywordHandler.MergeKeywords)
+			keywords.GET("/duplicates", read, keywordHandler.GetNearDuplicates)
+			keywords.GET("", read, keywordHandler.GetKeywords)
+			keywords.PUT("/:id", manage, keywordHandler.UpdateKeyword)
+			keywords.DELETE("/:id", manage, keywordHandler.DeleteKeyword)
 		}
 
 		positions := api.Group("/positions")
 		{
-			positions.POST("/track-google", positionHandler.TrackGooglePositions)
-			positions.POST("/track-yandex", positionHandler.TrackYandexPositions)
-			positions.POST("/track-wordstat", positionHandler.TrackWordstatPositions)
-			positions.GET("/history", positionHandler.GetPositionsHistory)
-			positions.GET("/latest", positionHandler.GetLatestPositions)
-			positions.POST("/statistics", positionHandler.GetPositionStatistics)
-			positions.GET("/combined", positionHandler.GetCombinedPositions)
+			positions.POST("/track-google", track, positionHandler.TrackGooglePositions)
+			positions.POST("/track-yandex", track, positionHandler.TrackYandexPositions)
+			positions.POST("/track-wordstat", track, positionHandler.TrackWordstatPositions)
+			positions.GET("/history", read, positionHandler.GetPositionsHistory)
+			positions.GET("/latest", read, positionHandler.GetLatestPositions)
+			positions.POST("/statistics", read, positionHandler.GetPositionStatistics)
+			positions.GET("/combined", read, positionHandler.GetCombinedPositions)
+			positions.GET("/visibility", read, visibilityHandler.GetVisibility)
+			positions.GET("/movement", read, movementHandler.GetMovement)
+			positions.GET("/:id/serp", read, serpSnapshotHandler.GetSerpSnapshot)
 		}
 
 		trackingJobs := api.Group("/tracking-jobs")
 		{
-			trackingJobs.GET("", trackingJobHandler.GetTrackingJobs)
+			trackingJobs.GET("", read, trackingJobHandler.GetTrackingJobs)
+			trackingJobs.GET("/:id", read, trackingJobHandler.GetTrackingJob)
+			trackingJobs.POST("/:id/cancel", track, trackingJobHandler.CancelTrackingJob)
+			trackingJobs.POST("/:id/pause", track, trackingJobHandler.PauseTrackingJob)
+			trackingJobs.POST("/:id/resume", track, trackingJobHandler.ResumeTrackingJob)
+			trackingJobs.POST("/:id/retry-failed", track, trackingJobHandler.RetryFailedTrackingJob)
+		}
+
+		trackingSchedules := api.Group("/tracking-schedules")
+		{
+			trackingSchedules.GET("", read, trackingScheduleHandler.GetSchedules)
+			trackingSchedules.POST("", track, trackingScheduleHandler.CreateSchedule)
+			trackingSchedules.GET("/:id", read, trackingScheduleHandler.GetSchedule)
+			trackingSchedules.PUT("/:id", track, trackingScheduleHandler.UpdateSchedule)
+			trackingSchedules.DELETE("/:id", track, trackingScheduleHandler.DeleteSchedule)
+			trackingSchedules.POST("/:id/pause", track, trackingScheduleHandler.PauseSchedule)
+			trackingSchedules.POST("/:id/resume", track, trackingScheduleHandler.ResumeSchedule)
+		}
+
+		providerAccounts := api.Group("/provider-accounts")
+		{
+			providerAccounts.GET("", admin, providerAccountHandler.GetProviderAccounts)
+			providerAccounts.POST("", admin, providerAccountHandler.CreateProviderAccount)
+			providerAccounts.GET("/:id", admin, providerAccountHandler.GetProviderAccount)
+			providerAccounts.PUT("/:id", admin, providerAccountHandler.UpdateProviderAccount)
+			providerAccounts.DELETE("/:id", admin, providerAccountHandler.DeleteProviderAccount)
+		}
+
+		api.GET("/providers", read, providerHandler.GetProviders)
+		api.GET("/providers/limits", read, providerHandler.GetProviderLimits)
+		api.GET("/usage", read, usageHandler.GetUsage)
+
+		apiKeys := api.Group("/api-keys")
+		{
+			apiKeys.GET("", admin, apiKeyHandler.GetAPIKeys)
+			apiKeys.POST("", admin, apiKeyHandler.CreateAPIKey)
+			apiKeys.POST("/:id/revoke", admin, apiKeyHandler.RevokeAPIKey)
+		}
+
+		workspaces := api.Group("/workspaces")
+		{
+			workspaces.GET("", system, workspaceHandler.GetWorkspaces)
+			workspaces.POST("", system, workspaceHandler.CreateWorkspace)
+			workspaces.GET("/:id", system, workspaceHandler.GetWorkspace)
+			workspaces.PUT("/:id", system, workspaceHandler.UpdateWorkspace)
+			workspaces.DELETE("/:id", system, workspaceHandler.DeleteWorkspace)
 		}
 
 		debug := api.Group("/debug")
 		{
-			debug.POST("/kafka/job-status", debugHandler.SendKafkaJobStatus)
+			debug.POST("/kafka/job-status", system, debugHandler.SendKafkaJobStatus)
 		}
 	}
 
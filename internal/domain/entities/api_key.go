@@ -0,0 +1,48 @@
+package entities
+
+import "time"
+
+// Права API-ключей
+const (
+	ScopePositionsRead  = "positions:read"  // Чтение сайтов, ключевых слов, позиций, джобов и расхода
+	ScopeKeywordsManage = "keywords:manage" // Изменение сайтов, групп, ключевых слов и конкурентов
+	ScopeTrackingStart  = "tracking:start"  // Запуск и управление джобами и расписаниями
+	ScopeAdmin          = "admin"           // Все права, включая выпуск ключей, аккаунты провайдеров и debug
+)
+
+var APIKeyScopes = []string{ScopePositionsRead, ScopeKeywordsManage, ScopeTrackingStart, ScopeAdmin}
+
+// APIKey ключ доступа к HTTP API. Сам ключ показывается один раз при выпуске, в БД хранится только его хеш
+type APIKey struct {
+	ID          int        `json:"id"`
+	WorkspaceID *int       `json:"workspace_id,omitempty"` // nil - системный ключ, не привязанный к пространству
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"` // Отпечаток ключа (начало SHA-256), чтобы его можно было узнать в списке
+	KeyHash     string     `json:"-"`
+	Scopes      []string   `json:"scopes"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+func IsValidAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
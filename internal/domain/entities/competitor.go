@@ -0,0 +1,7 @@
+package entities
+
+type Competitor struct {
+	ID     int
+	SiteID int
+	Domain string
+}
@@ -5,7 +5,18 @@ type Keyword struct {
 	Value   string
 	SiteID  int
 	GroupID *int
+	// NormalizedValue ключ уникальности слова в сайте; nil - дубль другого слова, оставшийся
+	// с момента введения ключа и еще не объединенный
+	NormalizedValue *string
+	ClusterID       *int   // Кластер последней кластеризации сайта по выдаче
+	Tags            []*Tag // Заполняется только в списке ключевых слов сайта
 
 	Site  *Site
 	Group *Group
 }
+
+// KeywordDuplicateGroup слова сайта, совпадающие после нормализации, то есть один и тот же запрос
+type KeywordDuplicateGroup struct {
+	Key      string
+	Keywords []*Keyword // По возрастанию ID
+}
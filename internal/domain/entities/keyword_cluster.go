@@ -0,0 +1,46 @@
+package entities
+
+import "time"
+
+// Способ объединения слов в кластер по пересечению топа выдачи
+const (
+	ClusterModeSoft = "soft" // Слово пересекается с маркером кластера
+	ClusterModeHard = "hard" // Слово пересекается с каждым словом кластера
+)
+
+// ClusterDepth глубина топа выдачи, по которой сравниваются слова
+const ClusterDepth = 10
+
+// KeywordSerp URL топа последней сохраненной выдачи слова по порядку позиций
+type KeywordSerp struct {
+	KeywordID int
+	URLs      []string
+}
+
+type KeywordCluster struct {
+	ID              int
+	SiteID          int
+	Source          string
+	FilterGroupID   *int
+	Mode            string
+	Threshold       int // Сколько общих URL топа нужно для попадания в кластер
+	Name            string
+	MarkerKeywordID *int // Слово с наибольшим числом похожих; nil, если оно удалено
+	KeywordIDs      []int
+	Keywords        []*Keyword // Заполняется при чтении кластеров
+	CreatedAt       time.Time
+}
+
+// KeywordClusterResult итог кластеризации сайта
+type KeywordClusterResult struct {
+	Keywords    int // Слова сайта с сохраненной выдачей источника
+	WithoutSerp int // Слова без сохраненной выдачи, не участвовали
+	Unclustered int // Слова, для которых не нашлось похожих
+	Clusters    []*KeywordCluster
+}
+
+// KeywordClusterApplyResult итог переноса кластеров в группы
+type KeywordClusterApplyResult struct {
+	GroupsCreated int
+	KeywordsMoved int
+}
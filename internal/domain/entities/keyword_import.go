@@ -0,0 +1,40 @@
+package entities
+
+// Результат разбора строки файла импорта ключевых слов
+const (
+	ImportRowNew       = "new"
+	ImportRowDuplicate = "duplicate" // Слово уже есть у сайта или повторяется в файле
+	ImportRowInvalid   = "invalid"
+)
+
+type KeywordImportRow struct {
+	Row       int // Номер строки файла, начиная с 1
+	Value     string
+	Key       string // Нормализованное значение
+	GroupName string // Пусто - группа не меняется
+	Tags      []string
+	Status    string
+	Reason    string
+}
+
+// KeywordImportStats изменения, внесенные импортом
+type KeywordImportStats struct {
+	Created       int
+	Updated       int // Существующие слова, у которых сменилась группа
+	GroupsCreated int
+	TagsCreated   int
+	TagsAssigned  int
+}
+
+type KeywordImportResult struct {
+	DryRun    bool
+	Total     int
+	New       int
+	Duplicate int
+	Invalid   int
+	NewGroups []string // Группы, которые будут (или были) созданы
+	NewTags   []string
+	Stats     KeywordImportStats // Пусто при пробном запуске
+	// Только повторы и ошибки: новые строки не перечисляются, чтобы ответ на 50 тысяч слов оставался небольшим
+	Rows []*KeywordImportRow
+}
@@ -0,0 +1,31 @@
+package entities
+
+import "time"
+
+// Откуда взята подсказка в ответе Wordstat
+const (
+	SuggestionPopular     = "popular"     // Запросы, содержащие исходную фразу
+	SuggestionAssociation = "association" // Похожие запросы
+)
+
+type KeywordSuggestion struct {
+	ID              int
+	SiteID          int
+	Value           string
+	NormalizedValue string
+	Seed            string // Исходная фраза, по которой найдена подсказка
+	Kind            string
+	Region          *int // nil - все регионы
+	Frequency       int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// KeywordSuggestionResult итог сбора подсказок
+type KeywordSuggestionResult struct {
+	Requests    int
+	Found       int // Уникальные фразы из ответов Wordstat
+	Existing    int // Уже есть среди ключевых слов сайта
+	Suggestions []*KeywordSuggestion
+	FailedSeeds []string // Фразы, по которым Wordstat вернул ошибку хотя бы в одном регионе
+}
@@ -0,0 +1,64 @@
+package entities
+
+import "time"
+
+// LandingCheck URL сайта в выдаче по ключевому слову за день проверки. Для позиций - последняя
+// найденная позиция дня, для снимков выдачи - лучшая позиция URL в одной проверке (PositionID)
+type LandingCheck struct {
+	PositionID    int
+	KeywordID     int
+	Keyword       string
+	GroupID       *int
+	Source        string
+	FilterGroupID *int
+	Date          time.Time
+	URL           string
+	Rank          int
+}
+
+type URLRank struct {
+	Date time.Time
+	Rank int
+}
+
+// URLHistory позиции одного URL сайта по ключевому слову по дням
+type URLHistory struct {
+	URL       string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	BestRank  int
+	Ranks     []URLRank
+}
+
+// URLChange смена ранжирующегося URL между соседними проверками, где сайт был найден
+type URLChange struct {
+	Date         time.Time
+	PreviousURL  string
+	URL          string
+	PreviousRank int
+	Rank         int
+}
+
+// KeywordURLChanges ключевое слово, у которого за период менялся ранжирующийся URL
+type KeywordURLChanges struct {
+	KeywordID     int
+	Keyword       string
+	GroupID       *int
+	Source        string
+	FilterGroupID *int
+	Changes       []URLChange
+	URLs          []URLHistory
+}
+
+// CannibalizationCase ключевое слово, по которому конкурируют несколько URL сайта: ранжирующийся URL
+// возвращался к уже ранжировавшемуся раньше (Rotating) или несколько URL были в одной выдаче (SharedChecks)
+type CannibalizationCase struct {
+	KeywordID     int
+	Keyword       string
+	GroupID       *int
+	Source        string
+	FilterGroupID *int
+	Rotating      bool
+	SharedChecks  int
+	URLs          []URLHistory
+}
@@ -0,0 +1,71 @@
+package entities
+
+import "time"
+
+// Изменение позиции ключевого слова между двумя датами проверки
+const (
+	MovementUp        = "up"        // Позиция улучшилась
+	MovementDown      = "down"      // Позиция ухудшилась
+	MovementNew       = "new"       // Раньше не найдено, теперь найдено
+	MovementLost      = "lost"      // Раньше найдено, теперь не найдено
+	MovementUnchanged = "unchanged" // Позиция не изменилась (в том числе не найдено в обе даты)
+)
+
+// MovementNotFoundRank позиция, которой считается "не найдено" при расчете изменения
+const MovementNotFoundRank = 101
+
+// KeywordMovement последние позиции ключевого слова в две даты проверки. 0 - не найдено;
+// если в первую дату слово не проверялось, PreviousRank тоже 0
+type KeywordMovement struct {
+	KeywordID    int
+	Keyword      string
+	GroupID      *int
+	PreviousRank int
+	CurrentRank  int
+	// Delta на сколько позиций поднялось слово, отрицательное - опустилось
+	Delta  int
+	Status string
+}
+
+// MovementSummary число ключевых слов по видам изменения. NetScore - сумма Delta по всем словам
+type MovementSummary struct {
+	PreviousDate time.Time
+	CurrentDate  time.Time
+	Up           int
+	Down         int
+	New          int
+	Lost         int
+	Unchanged    int
+	NetScore     int
+}
+
+// Add учитывает изменение ключевого слова в сводке
+func (s *MovementSummary) Add(movement *KeywordMovement) {
+	switch movement.Status {
+	case MovementUp:
+		s.Up++
+	case MovementDown:
+		s.Down++
+	case MovementNew:
+		s.New++
+	case MovementLost:
+		s.Lost++
+	default:
+		s.Unchanged++
+	}
+	s.NetScore += movement.Delta
+}
+
+type GroupMovement struct {
+	GroupID *int // nil - ключевые слова без группы
+	Summary MovementSummary
+}
+
+// MovementReport изменение позиций сайта по источнику между двумя датами проверки
+type MovementReport struct {
+	SiteID   int
+	Source   string
+	Summary  MovementSummary
+	Groups   []GroupMovement
+	Keywords []*KeywordMovement
+}
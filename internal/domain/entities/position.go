@@ -2,10 +2,17 @@ package entities
 
 import "time"
 
+// Периоды агрегатов старых позиций
+const (
+	PositionPeriodDay  = "day"
+	PositionPeriodWeek = "week"
+)
+
 type Position struct {
 	ID                int
 	KeywordID         int
 	SiteID            int
+	CompetitorID      *int // nil - позиция самого сайта
 	Rank              int
 	URL               string
 	Title             string
@@ -20,6 +27,12 @@ type Position struct {
 	FilterGroupID     *int
 	WordstatQueryType string
 
+	// Заполнены у агрегатов старых позиций: Rank и Date - последняя проверка периода,
+	// AvgRank - средняя позиция по проверкам с найденным сайтом (nil - сайт не найден ни разу)
+	Period  string
+	AvgRank *float64
+	Samples int
+
 	Keyword *Keyword
 	Site    *Site
 }
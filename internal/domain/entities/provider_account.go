@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// ProviderAccount именованные учетные данные провайдера. В БД ключ хранится только зашифрованным,
+// APIKey заполняется при расшифровке перед запросами и в ответы API не попадает
+type ProviderAccount struct {
+	ID              int       `json:"id"`
+	WorkspaceID     int       `json:"workspace_id"`
+	Name            string    `json:"name"`
+	Provider        string    `json:"provider"` // Имя SERP провайдера; Wordstat работает через аккаунты xmlriver
+	UserID          string    `json:"user_id"`
+	APIKey          string    `json:"-"`
+	EncryptedAPIKey string    `json:"-"`
+	BaseURL         string    `json:"base_url,omitempty"` // Необязательный адрес API провайдера для поиска и Wordstat
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// ProviderUsage запись журнала расхода: запросы к провайдеру, сделанные при проверке одного keyword
+type ProviderUsage struct {
+	ID        int64     `json:"id"`
+	JobID     string    `json:"job_id,omitempty"` // Пусто для синхронного отслеживания
+	SiteID    int       `json:"site_id"`
+	Source    string    `json:"source"`
+	Provider  string    `json:"provider"`
+	Account   string    `json:"account"`
+	Requests  int       `json:"requests"`
+	Cost      float64   `json:"cost"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UsageSummary расход за день по сайту и источнику
+type UsageSummary struct {
+	Date     time.Time `json:"date"`
+	SiteID   int       `json:"site_id"`
+	Source   string    `json:"source"`
+	Requests int       `json:"requests"`
+	Cost     float64   `json:"cost"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// SerpItem один результат выдачи на момент проверки
+type SerpItem struct {
+	Place       int // Порядковый номер блока в выдаче, включая неорганические
+	Rank        int // Позиция в органике; 0 для блоков, которые не учитываются в позиции
+	URL         string
+	Domain      string
+	Title       string
+	Passage     string
+	ContentType string
+}
+
+// SerpSnapshot полный топ выдачи, полученный при снятии позиции
+type SerpSnapshot struct {
+	PositionID int
+	KeywordID  int
+	SiteID     int
+	Source     string
+	Date       time.Time
+	Items      []SerpItem
+}
@@ -1,8 +1,18 @@
 package entities
 
+// Действие при превышении месячного бюджета сайта
+const (
+	BudgetActionRefuse = "refuse" // Не запускать джоб
+	BudgetActionTrim   = "trim"   // Запустить только keywords, укладывающиеся в остаток
+)
+
 type Site struct {
 	ID            int
+	WorkspaceID   int
 	Domain        string
-	YandexDynamic *int
-	GoogleDynamic *int
+	MonthlyBudget *float64 // nil - без ограничения
+	BudgetAction  string
+	// Изменение позиций между двумя последними проверками; nil - проверок по источнику меньше двух
+	YandexMovement *MovementSummary
+	GoogleMovement *MovementSummary
 }
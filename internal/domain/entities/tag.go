@@ -0,0 +1,21 @@
+package entities
+
+// Tag метка ключевых слов сайта; в отличие от группы, у слова может быть несколько меток
+type Tag struct {
+	ID            int
+	SiteID        int
+	Name          string
+	KeywordsCount int // Заполняется только в списке меток сайта
+}
+
+// Способ отбора ключевых слов по нескольким меткам
+const (
+	TagMatchAny = "any" // Есть хотя бы одна из меток
+	TagMatchAll = "all" // Есть все метки
+)
+
+// TagFilter отбор ключевых слов по меткам; nil - без отбора
+type TagFilter struct {
+	TagIDs []int
+	Match  string
+}
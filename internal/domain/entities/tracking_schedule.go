@@ -0,0 +1,57 @@
+package entities
+
+import "time"
+
+// TrackingParams сохраненный набор параметров запуска отслеживания
+type TrackingParams struct {
+	Device     string `json:"device,omitempty"`
+	OS         string `json:"os,omitempty"`
+	Ads        bool   `json:"ads"`
+	Country    string `json:"country,omitempty"`
+	Lang       string `json:"lang,omitempty"`
+	Pages      int    `json:"pages"`
+	Subdomains bool   `json:"subdomains"`
+	// External service parameters
+	AccountID *int   `json:"account_id,omitempty"` // Аккаунт провайдера; nil - учетные данные из конфигурации
+	Provider  string `json:"provider,omitempty"`
+	// Source-specific parameters
+	TBS           string `json:"tbs,omitempty"`
+	Filter        *int   `json:"filter,omitempty"`
+	Highlights    int    `json:"highlights"`
+	NFPR          int    `json:"nfpr"`
+	Loc           int    `json:"loc"`
+	AI            int    `json:"ai"`
+	Raw           string `json:"raw,omitempty"`
+	GroupBy       int    `json:"groupby"`
+	Within        int    `json:"within"`
+	LR            int    `json:"lr"`
+	Domain        int    `json:"domain"`
+	YandexDomain  string `json:"yandex_domain,omitempty"`
+	InIndex       int    `json:"inindex"`
+	Strict        int    `json:"strict"`
+	Organic       bool   `json:"organic"`
+	Regions       *int   `json:"regions,omitempty"`
+	FilterGroupID *int   `json:"filter_group_id,omitempty"`
+	// Wordstat query types
+	DefaultQuery           bool `json:"default_query"`
+	Quotes                 bool `json:"quotes"`
+	QuotesExclamationMarks bool `json:"quotes_exclamation_marks"`
+	ExclamationMarks       bool `json:"exclamation_marks"`
+}
+
+type TrackingSchedule struct {
+	ID        int            `json:"id"`
+	SiteID    int            `json:"site_id"`
+	Source    string         `json:"source"`
+	Params    TrackingParams `json:"params"`
+	CronExpr  string         `json:"cron_expr"`
+	Timezone  string         `json:"timezone"`
+	Enabled   bool           `json:"enabled"`
+	LastRunAt *time.Time     `json:"last_run_at,omitempty"`
+	NextRunAt *time.Time     `json:"next_run_at,omitempty"`
+	LastJobID string         `json:"last_job_id,omitempty"`
+	// Причина, по которой последний запуск не состоялся (пропуск или ошибка)
+	LastError string    `json:"last_error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -12,6 +12,7 @@ const (
 	TaskStatusCompleted TrackingTaskStatus = "completed"
 	TaskStatusFailed    TrackingTaskStatus = "failed"
 	TaskStatusCancelled TrackingTaskStatus = "cancelled"
+	TaskStatusPaused    TrackingTaskStatus = "paused"
 )
 
 type TrackingJob struct {
@@ -21,12 +22,21 @@ type TrackingJob struct {
 	Status         TrackingTaskStatus `json:"status"`
 	CreatedAt      time.Time          `json:"created_at"`
 	UpdatedAt      time.Time          `json:"updated_at"`
+	StartedAt      *time.Time         `json:"started_at,omitempty"`
 	CompletedAt    *time.Time         `json:"completed_at,omitempty"`
 	TotalTasks     int                `json:"total_tasks"`
 	CompletedTasks int                `json:"completed_tasks"`
 	FailedTasks    int                `json:"failed_tasks"`
 	FailedRequests int                `json:"failed_requests"`
 	Error          string             `json:"error,omitempty"`
+	APIKeyID       *int               `json:"api_key_id,omitempty"` // Ключ API, которым запущен джоб; nil - расписание или запуск без авторизации
+	// Параметры запуска хранятся вместе с джобом, чтобы его можно было продолжить после рестарта
+	Params TrackingParams `json:"-"`
+	// Аренда джоба воркером: пока LockedUntil не истекло, другие инстансы его не берут
+	LockedBy    string     `json:"-"`
+	LockedUntil *time.Time `json:"-"`
+	// Оценка стоимости при запуске: пока джоб не завершен, ее неизрасходованная часть резервирует бюджет
+	EstimatedCost float64 `json:"-"`
 }
 
 type TrackingTask struct {
@@ -42,6 +52,8 @@ type TrackingTask struct {
 	RetryCount  int                `json:"retry_count"`
 	MaxRetries  int                `json:"max_retries"`
 	Error       string             `json:"error,omitempty"`
+	// Сколько раз задача обращалась к провайдеру, включая повторы и retry-failed
+	Attempts int `json:"attempts"`
 	// Task-specific parameters
 	Device     string `json:"device,omitempty"`
 	OS         string `json:"os,omitempty"`
@@ -50,10 +62,6 @@ type TrackingTask struct {
 	Lang       string `json:"lang,omitempty"`
 	Pages      int    `json:"pages"`
 	Subdomains bool   `json:"subdomains"`
-	// External service parameters
-	XMLUserID  string `json:"xml_user_id,omitempty"`
-	XMLAPIKey  string `json:"xml_api_key,omitempty"`
-	XMLBaseURL string `json:"xml_base_url,omitempty"`
 	// Source-specific parameters
 	TBS               string `json:"tbs,omitempty"`
 	Filter            int    `json:"filter"`
@@ -74,6 +82,21 @@ type TrackingTask struct {
 	WordstatQueryType string `json:"wordstat_query_type,omitempty"`
 }
 
+// TrackingTaskOutcome итог проверки одного keyword в джобе: задача и ее результат, если он есть
+type TrackingTaskOutcome struct {
+	TaskID            string
+	KeywordID         int
+	Keyword           string
+	WordstatQueryType string
+	Status            TrackingTaskStatus
+	Rank              *int
+	URL               string
+	Title             string
+	Error             string
+	Attempts          int
+	CompletedAt       *time.Time
+}
+
 type TrackingResult struct {
 	TaskID    string    `json:"task_id"`
 	JobID     string    `json:"job_id"`
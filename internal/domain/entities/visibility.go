@@ -0,0 +1,47 @@
+package entities
+
+import "time"
+
+// CTRCurve доля кликов по позиции выдачи: CTRCurve[0] - первая позиция. Позиции за пределами кривой кликов не получают
+type CTRCurve []float64
+
+// DefaultCTRCurve усредненная кривая CTR органической выдачи для первых двух страниц
+var DefaultCTRCurve = CTRCurve{
+	0.28, 0.157, 0.11, 0.08, 0.072, 0.051, 0.04, 0.032, 0.028, 0.025,
+	0.012, 0.011, 0.01, 0.009, 0.008, 0.007, 0.006, 0.005, 0.004, 0.004,
+}
+
+// CTR доля кликов для позиции rank; для 0 (сайт не найден) - 0
+func (c CTRCurve) CTR(rank int) float64 {
+	if rank < 1 || rank > len(c) {
+		return 0
+	}
+	return c[rank-1]
+}
+
+// KeywordRank последняя за день позиция ключевого слова в источнике и его частота Wordstat на этот день
+type KeywordRank struct {
+	KeywordID     int
+	GroupID       *int
+	Source        string
+	FilterGroupID *int
+	Rank          int
+	Frequency     int // 0 - частота не собрана
+}
+
+// SiteVisibility видимость сайта за день в источнике. GroupID nil - все ключевые слова сайта,
+// FilterGroupID nil - позиции без группы фильтров
+type SiteVisibility struct {
+	SiteID           int
+	Date             time.Time
+	Source           string
+	GroupID          *int
+	FilterGroupID    *int
+	KeywordsCount    int
+	FoundCount       int
+	Top10Count       int
+	Frequency        int64   // Суммарная частота Wordstat ключевых слов
+	EstimatedTraffic float64 // Ожидаемые переходы в месяц: сумма частота * CTR позиции
+	Visibility       float64 // Процент от трафика, который дали бы первые позиции по всем ключевым словам
+	CalculatedAt     time.Time
+}
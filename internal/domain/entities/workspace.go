@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// DefaultWorkspaceID пространство, в которое перенесены данные, созданные до появления пространств.
+// В него же попадают сайты, созданные без авторизации
+const DefaultWorkspaceID = 1
+
+// Workspace рабочее пространство клиента. Ему принадлежат сайты (а через них ключевые слова, группы,
+// позиции, джобы и расписания), аккаунты провайдеров и ключи API
+type Workspace struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	MonthlyBudget *float64  `json:"monthly_budget,omitempty"` // Общий месячный бюджет сайтов пространства; nil - без ограничения
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+type APIKeyRepository interface {
+	Create(key *entities.APIKey) error
+	GetByID(id int) (*entities.APIKey, error)
+	GetByHash(hash string) (*entities.APIKey, error)
+	// GetAll возвращает ключи пространства; nil - все ключи, включая системные
+	GetAll(workspaceID *int) ([]*entities.APIKey, error)
+	// Revoke отзывает ключ; false, если ключ уже был отозван
+	Revoke(id int, at time.Time) (bool, error)
+	UpdateLastUsed(id int, at time.Time) error
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import "go-seo/internal/domain/entities"
+
+type CompetitorRepository interface {
+	Create(competitor *entities.Competitor) error
+	GetByID(id int) (*entities.Competitor, error)
+	GetBySiteID(siteID int) ([]*entities.Competitor, error)
+	Update(competitor *entities.Competitor) error
+	Delete(id int) error
+	DeleteBySiteID(siteID int) error
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import "go-seo/internal/domain/entities"
+
+type KeywordClusterRepository interface {
+	// GetTopURLs первые depth URL последней сохраненной выдачи каждого слова сайта по источнику.
+	// filterGroupID ограничивает проверки одной группой фильтров; выдача конкурентов не учитывается
+	GetTopURLs(siteID int, source string, filterGroupID *int, depth int) ([]*entities.KeywordSerp, error)
+	// Replace заменяет кластеры сайта новыми и проставляет cluster_id их словам; ID кластеров заполняются
+	Replace(siteID int, clusters []*entities.KeywordCluster) error
+	// GetBySite кластеры сайта по убыванию размера со словами
+	GetBySite(siteID int, offset, limit int) ([]*entities.KeywordCluster, int64, error)
+	GetByIDs(ids []int) ([]*entities.KeywordCluster, error)
+	// Apply переносит слова кластеров в группы с именами кластеров; недостающие группы создаются
+	Apply(siteID int, clusterIDs []int) (*entities.KeywordClusterApplyResult, error)
+}
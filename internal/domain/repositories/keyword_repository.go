@@ -6,12 +6,24 @@ type KeywordRepository interface {
 	Create(keyword *entities.Keyword) error
 	CreateBatch(keywords []*entities.Keyword) error
 	GetByID(id int) (*entities.Keyword, error)
+	// GetByIDInWorkspace ищет слово только среди сайтов пространства workspaceID (nil - без ограничения)
+	GetByIDInWorkspace(id int, workspaceID *int) (*entities.Keyword, error)
 	GetByIDs(ids []int) ([]*entities.Keyword, error)
-	GetByValueAndSite(value string, siteID int) (*entities.Keyword, error)
+	GetByNormalizedValue(key string, siteID int) (*entities.Keyword, error)
 	GetBySiteID(siteID int) ([]*entities.Keyword, error)
+	// GetBySiteIDAndTags слова сайта, подходящие под отбор по меткам
+	GetBySiteIDAndTags(siteID int, tags *entities.TagFilter) ([]*entities.Keyword, error)
 	GetAll() ([]*entities.Keyword, error)
 	Update(keyword *entities.Keyword) error
 	Delete(id int) error
 	DeleteBySiteID(siteID int) error
 	CountBySiteID(siteID int) (int, error)
+	// GetExistingKeys нормализованные значения из keys, которые уже заняты словами сайта
+	GetExistingKeys(siteID int, keys []string) ([]string, error)
+	// Import в одной транзакции создает недостающие группы, метки и ключевые слова, переносит существующие
+	// слова в группу из строки и добавляет им метки. Строки должны быть уникальны по Key
+	Import(siteID int, rows []*entities.KeywordImportRow) (*entities.KeywordImportStats, error)
+	// Merge в одной транзакции переносит позиции, снимки выдачи, агрегаты, задачи и метки слов sourceIDs
+	// на target и удаляет эти слова. Если у target нет ключа, он получает target.NormalizedValue
+	Merge(target *entities.Keyword, sourceIDs []int) error
 }
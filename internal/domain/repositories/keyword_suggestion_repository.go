@@ -0,0 +1,16 @@
+package repositories
+
+import "go-seo/internal/domain/entities"
+
+type KeywordSuggestionRepository interface {
+	// Save сохраняет подсказки и заполняет их ID; у уже сохраненной фразы региона обновляются частота,
+	// источник и исходная фраза. Фраза не должна повторяться в suggestions в пределах региона
+	Save(suggestions []*entities.KeywordSuggestion) error
+	GetByIDs(ids []int) ([]*entities.KeywordSuggestion, error)
+	// GetBySite подсказки сайта по убыванию частоты без фраз, которые уже стали ключевыми словами
+	GetBySite(siteID int, region *int, kind *string, offset, limit int) ([]*entities.KeywordSuggestion, int64, error)
+	// Accept создает из подсказок ключевые слова в группе и удаляет подсказки с теми же фразами во всех регионах.
+	// Возвращает число созданных слов; фразы, которые уже есть у сайта, пропускаются
+	Accept(siteID int, ids []int, groupID *int) (int, error)
+	Delete(siteID int, ids []int) (int, error)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+type LandingPageRepository interface {
+	// GetRankingURLs последняя за день найденная позиция сайта (без конкурентов) с URL по ключевым словам
+	// периода [dateFrom, dateTo], по возрастанию ключевого слова, источника, группы фильтров и дня.
+	// source nil - google и yandex, groupID nil - все ключевые слова
+	GetRankingURLs(siteID int, source *string, groupID *int, dateFrom, dateTo time.Time) ([]*entities.LandingCheck, error)
+	// GetSharedSerpURLs URL домена сайта и его поддоменов из снимков выдачи тех проверок периода,
+	// где в выдаче было больше одного URL сайта
+	GetSharedSerpURLs(siteID int, domain string, source *string, groupID *int, dateFrom, dateTo time.Time) ([]*entities.LandingCheck, error)
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+type MovementRepository interface {
+	// GetCheckDays последние limit дней не позже until (по убыванию), когда позиции сайта (без конкурентов)
+	// проверялись по источнику; filterGroupID nil - позиции, снятые без группы фильтров
+	GetCheckDays(siteID int, source string, filterGroupID *int, until time.Time, limit int) ([]time.Time, error)
+	// GetKeywordMovement последние за день позиции ключевых слов, проверенных в день current,
+	// вместе с последней позицией за день previous. Delta и Status не заполняются
+	GetKeywordMovement(siteID int, source string, filterGroupID *int, previous, current time.Time) ([]*entities.KeywordMovement, error)
+}
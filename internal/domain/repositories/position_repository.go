@@ -24,8 +24,9 @@ type PositionRepository interface {
 
 	DeleteBySiteID(siteID int) error
 	DeleteByKeywordID(keywordID int) error
+	DeleteByCompetitorID(competitorID int) error
 
-	GetTodayByKeywordAndSiteAndSource(keywordID, siteID int, source string, wordstatQueryType string, filterGroupID *int) (*entities.Position, error)
+	GetTodayByKeywordAndSiteAndSource(keywordID, siteID int, competitorID *int, source string, wordstatQueryType string, filterGroupID *int) (*entities.Position, error)
 	CreateOrUpdateToday(position *entities.Position) error
 
 	GetHistoryBySiteIDWithOnePerDay(siteID int, dateFrom, dateTo *time.Time) ([]*entities.Position, error)
@@ -36,11 +37,11 @@ type PositionRepository interface {
 	GetLatestBySiteID(siteID int) ([]*entities.Position, error)
 	GetLatestBySiteIDAndSource(siteID int, source string) ([]*entities.Position, error)
 
-	GetPositionStatistics(siteID int, source string, dateFrom, dateTo time.Time, filterGroupID *int) (*entities.PositionStatistics, error)
+	GetPositionStatistics(siteID int, competitorID *int, source string, dateFrom, dateTo time.Time, filterGroupID *int, tags *entities.TagFilter) (*entities.PositionStatistics, error)
 
-	GetPositionsHistoryPaginated(siteID int, keywordID *int, source *string, dateFrom, dateTo *time.Time, last bool, page, perPage int) ([]*entities.Position, int64, error)
+	GetPositionsHistoryPaginated(siteID int, keywordID *int, tags *entities.TagFilter, source *string, dateFrom, dateTo *time.Time, last bool, page, perPage int) ([]*entities.Position, int64, error)
 
-	GetCombinedPositionsPaginated(siteID int, source *string, includeWordstat bool, wordstatSort bool, dateFrom, dateTo, dateSort *time.Time, sortType string, rankFrom, rankTo *int, groupID *int, filterGroupID *int, wordstatQueryType *string, page, perPage int) ([]*entities.CombinedPosition, int64, error)
+	GetCombinedPositionsPaginated(siteID int, competitorID *int, source *string, includeWordstat bool, wordstatSort bool, dateFrom, dateTo, dateSort *time.Time, sortType string, rankFrom, rankTo *int, groupID *int, tags *entities.TagFilter, filterGroupID *int, wordstatQueryType *string, page, perPage int) ([]*entities.CombinedPosition, int64, error)
 
 	GetLastUpdateDateBySiteIDExcludingSource(siteID int, excludeSource string) (*time.Time, error)
 }
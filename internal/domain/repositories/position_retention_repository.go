@@ -0,0 +1,16 @@
+package repositories
+
+import "time"
+
+// PositionRetentionRepository обслуживает месячные секции positions и сворачивание старых позиций в position_rollups
+type PositionRetentionRepository interface {
+	// EnsurePartitions создает секции positions для месяцев с from по to включительно
+	EnsurePartitions(from, to time.Time) error
+	// RollupDays сворачивает детальные позиции старше before в дневные агрегаты и удаляет их.
+	// Возвращает число записанных агрегатов
+	RollupDays(before time.Time) (int64, error)
+	// DeleteSnapshots удаляет SERP снапшоты старше before и возвращает число удаленных строк
+	DeleteSnapshots(before time.Time) (int64, error)
+	// RollupWeeks сворачивает дневные агрегаты, начавшиеся до before, в недельные
+	RollupWeeks(before time.Time) (int64, error)
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import "go-seo/internal/domain/entities"
+
+type ProviderAccountRepository interface {
+	Create(account *entities.ProviderAccount) error
+	GetByID(id int) (*entities.ProviderAccount, error)
+	// GetAll возвращает аккаунты пространства; nil - все аккаунты
+	GetAll(workspaceID *int) ([]*entities.ProviderAccount, error)
+	Update(account *entities.ProviderAccount) error
+	Delete(id int) error
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import "go-seo/internal/domain/entities"
+
+type SerpSnapshotRepository interface {
+	// ReplaceForPosition перезаписывает снимок выдачи для позиции (повторная проверка за день)
+	ReplaceForPosition(snapshot *entities.SerpSnapshot) error
+	// GetByPositionID возвращает nil, если для позиции снимок не сохранялся или позиция принадлежит сайту
+	// вне пространства workspaceID (nil - без ограничения)
+	GetByPositionID(positionID int, workspaceID *int) (*entities.SerpSnapshot, error)
+	DeleteBySiteID(siteID int) error
+	DeleteByKeywordID(keywordID int) error
+}
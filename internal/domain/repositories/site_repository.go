@@ -5,9 +5,15 @@ import "go-seo/internal/domain/entities"
 type SiteRepository interface {
 	Create(site *entities.Site) error
 	GetByID(id int) (*entities.Site, error)
+	// GetByIDInWorkspace ищет сайт только в пространстве workspaceID (nil - без ограничения): чужой сайт не найден
+	GetByIDInWorkspace(id int, workspaceID *int) (*entities.Site, error)
 	GetByDomain(domain string) (*entities.Site, error)
 	GetAll() ([]*entities.Site, error)
-	GetByIDs(ids []int) ([]*entities.Site, error)
+	GetByWorkspaceID(workspaceID int) ([]*entities.Site, error)
+	// GetByIDs возвращает найденные сайты пространства workspaceID (nil - без ограничения)
+	GetByIDs(ids []int, workspaceID *int) ([]*entities.Site, error)
 	Update(site *entities.Site) error
+	// UpdateMovement сохраняет сводку изменения позиций по источнику google или yandex; nil очищает ее
+	UpdateMovement(siteID int, source string, summary *entities.MovementSummary) error
 	Delete(id int) error
 }
\ No newline at end of file
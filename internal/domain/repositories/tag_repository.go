@@ -0,0 +1,19 @@
+package repositories
+
+import "go-seo/internal/domain/entities"
+
+type TagRepository interface {
+	Create(tag *entities.Tag) error
+	GetByID(id int) (*entities.Tag, error)
+	GetByIDs(ids []int) ([]*entities.Tag, error)
+	// GetAllBySite метки сайта по имени с числом ключевых слов каждой
+	GetAllBySite(siteID int) ([]*entities.Tag, error)
+	// GetByKeywordIDs метки слов по ID слова
+	GetByKeywordIDs(keywordIDs []int) (map[int][]*entities.Tag, error)
+	Update(tag *entities.Tag) error
+	Delete(id int) error
+	// Assign добавляет каждому слову каждую метку; возвращает число новых связей
+	Assign(keywordIDs, tagIDs []int) (int, error)
+	// Unassign снимает метки со слов; возвращает число удаленных связей
+	Unassign(keywordIDs, tagIDs []int) (int, error)
+}
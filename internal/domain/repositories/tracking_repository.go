@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"time"
+
 	"go-seo/internal/domain/entities"
 )
 
@@ -8,13 +10,29 @@ import (
 type TrackingJobRepository interface {
 	Create(job *entities.TrackingJob) error
 	GetByID(id string) (*entities.TrackingJob, error)
+	// GetByIDInWorkspace ищет джоб только среди сайтов пространства workspaceID (nil - без ограничения)
+	GetByIDInWorkspace(id string, workspaceID *int) (*entities.TrackingJob, error)
 	Update(job *entities.TrackingJob) error
 	UpdateStatus(id string, status entities.TrackingTaskStatus) error
+	// UpdateStatusIf меняет статус, только если джоб все еще в статусе from. Переход в completed/failed снимает аренду
+	UpdateStatusIf(id string, from, to entities.TrackingTaskStatus) (bool, error)
+	UpdateError(id string, errText string) error
+	// RequeueFailed в одной транзакции возвращает джоб из статуса from в pending с новой оценкой стоимости,
+	// а его failed-задачи в pending. false - статус джоба уже изменился; 0 задач - перезапускать нечего, джоб не меняется
+	RequeueFailed(id string, from entities.TrackingTaskStatus, estimatedCost float64) (bool, int64, error)
 	UpdateProgress(id string, completed, failed int) error
 	UpdateFailedRequests(id string, failedRequests int) error
 	GetBySiteID(siteID int) ([]*entities.TrackingJob, error)
 	GetByStatus(status entities.TrackingTaskStatus) ([]*entities.TrackingJob, error)
-	GetJobsWithPagination(page, perPage int, siteID *int, status *entities.TrackingTaskStatus) ([]*entities.TrackingJob, int64, error)
+	// GetJobsWithPagination возвращает джобы с фильтрами; workspaceID nil - джобы всех пространств
+	GetJobsWithPagination(page, perPage int, workspaceID, siteID *int, status *entities.TrackingTaskStatus) ([]*entities.TrackingJob, int64, error)
+	// HasActiveJob проверяет, есть ли у сайта незавершенный (pending/running/paused) джоб по источнику
+	HasActiveJob(siteID int, source string) (bool, error)
+	// ClaimNext арендует следующий pending/running джоб без действующей аренды; nil, если брать нечего
+	ClaimNext(workerID string, leaseUntil time.Time) (*entities.TrackingJob, error)
+	// ExtendLease продлевает аренду running-джоба; false, если джоб арендован другим воркером или остановлен
+	ExtendLease(id, workerID string, leaseUntil time.Time) (bool, error)
+	ReleaseLease(id, workerID string) error
 	Delete(id string) error
 	DeleteBySiteID(siteID int) error
 }
@@ -24,9 +42,17 @@ type TrackingTaskRepository interface {
 	Create(task *entities.TrackingTask) error
 	GetByID(id string) (*entities.TrackingTask, error)
 	GetByJobID(jobID string) ([]*entities.TrackingTask, error)
+	GetPendingByJobID(jobID string) ([]*entities.TrackingTask, error)
+	CountByStatus(jobID string) (map[entities.TrackingTaskStatus]int, error)
+	CreateBatch(tasks []*entities.TrackingTask) error
 	Update(task *entities.TrackingTask) error
 	UpdateStatus(id string, status entities.TrackingTaskStatus) error
 	UpdateRetryCount(id string, retryCount int) error
+	// Finish фиксирует итог задачи: статус, ошибку и время завершения
+	Finish(id string, status entities.TrackingTaskStatus, errMsg string, attempts int) error
+	CancelPending(jobID string) error
+	// GetOutcomesByJobID возвращает постранично итоги по keyword с последним результатом задачи
+	GetOutcomesByJobID(jobID string, status *entities.TrackingTaskStatus, page, perPage int) ([]*entities.TrackingTaskOutcome, int64, error)
 	GetPendingTasks(limit int) ([]*entities.TrackingTask, error)
 	GetFailedTasks(limit int) ([]*entities.TrackingTask, error)
 	Delete(id string) error
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+type TrackingScheduleRepository interface {
+	Create(schedule *entities.TrackingSchedule) error
+	GetByID(id int) (*entities.TrackingSchedule, error)
+	// GetAll возвращает расписания с фильтрами; workspaceID nil - расписания всех пространств
+	GetAll(workspaceID, siteID *int) ([]*entities.TrackingSchedule, error)
+	// GetDue возвращает включенные расписания, у которых время следующего запуска уже наступило
+	GetDue(now time.Time) ([]*entities.TrackingSchedule, error)
+	// Claim переносит следующий запуск расписания, если он все еще равен dueAt. false - запуск уже забрал
+	// другой экземпляр сервиса или расписание изменили
+	Claim(id int, dueAt time.Time, nextRunAt *time.Time) (bool, error)
+	Update(schedule *entities.TrackingSchedule) error
+	UpdateRunInfo(id int, lastRunAt time.Time, nextRunAt *time.Time, lastJobID, lastError string) error
+	Delete(id int) error
+	DeleteBySiteID(siteID int) error
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+type UsageRepository interface {
+	Create(usage *entities.ProviderUsage) error
+	// GetSpentSince возвращает стоимость запросов сайта начиная с since
+	GetSpentSince(siteID int, since time.Time) (float64, error)
+	// GetWorkspaceSpentSince возвращает стоимость запросов всех сайтов пространства начиная с since
+	GetWorkspaceSpentSince(workspaceID int, since time.Time) (float64, error)
+	// GetReserved возвращает неизрасходованную часть оценок стоимости незавершенных джобов сайта
+	GetReserved(siteID int) (float64, error)
+	// GetWorkspaceReserved возвращает неизрасходованную часть оценок стоимости незавершенных джобов пространства
+	GetWorkspaceReserved(workspaceID int) (float64, error)
+	// WithBudgetLock выполняет fn под блокировкой бюджета пространства, общей для всех инстансов сервиса
+	WithBudgetLock(workspaceID int, fn func() error) error
+	// GetJobTotals возвращает число запросов и стоимость джоба
+	GetJobTotals(jobID string) (int, float64, error)
+	// GetDailySummary группирует расход по дням, сайтам и источникам в периоде [dateFrom, dateTo);
+	// workspaceID nil - расход всех пространств
+	GetDailySummary(workspaceID, siteID *int, source *string, dateFrom, dateTo time.Time) ([]*entities.UsageSummary, error)
+}
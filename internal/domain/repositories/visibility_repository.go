@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+type VisibilityRepository interface {
+	// GetKeywordRanks последние за день позиции сайта (без конкурентов) по ключевым словам, источникам
+	// и группам фильтров вместе с частотой Wordstat, собранной не позже этого дня
+	GetKeywordRanks(siteID int, day time.Time) ([]*entities.KeywordRank, error)
+	// ReplaceDay заменяет сохраненную видимость сайта за день
+	ReplaceDay(siteID int, day time.Time, rows []*entities.SiteVisibility) error
+	// GetUncalculatedDays дни периода [dateFrom, dateTo], когда сайт проверялся, но видимость не сохранена
+	GetUncalculatedDays(siteID int, dateFrom, dateTo time.Time) ([]time.Time, error)
+	// GetSeries видимость сайта по дням периода [dateFrom, dateTo]; source nil - все источники,
+	// groupID nil - все ключевые слова, filterGroupID nil - позиции без группы фильтров
+	GetSeries(siteID int, source *string, groupID, filterGroupID *int, dateFrom, dateTo time.Time) ([]*entities.SiteVisibility, error)
+}
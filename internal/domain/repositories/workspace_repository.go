@@ -0,0 +1,11 @@
+package repositories
+
+import "go-seo/internal/domain/entities"
+
+type WorkspaceRepository interface {
+	Create(workspace *entities.Workspace) error
+	GetByID(id int) (*entities.Workspace, error)
+	GetAll() ([]*entities.Workspace, error)
+	Update(workspace *entities.Workspace) error
+	Delete(id int) error
+}
@@ -0,0 +1,11 @@
+package services
+
+// KeywordNormalizer приводит ключевые слова к ключам для поиска одинаковых запросов
+type KeywordNormalizer interface {
+	// Clean убирает пробелы по краям и схлопывает пробелы внутри; регистр сохраняется
+	Clean(value string) string
+	// Normalize ключ уникальности слова в сайте: Clean, нижний регистр, ё -> е
+	Normalize(value string) string
+	// MatchKey ключ поиска близких дублей: Normalize и, по выбору, без стоп-слов и без учета порядка слов
+	MatchKey(value string, ignoreStopWords, ignoreWordOrder bool) string
+}
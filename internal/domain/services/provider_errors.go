@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ProviderErrorKind класс ошибки провайдера, по которому принимается решение о повторе
+type ProviderErrorKind string
+
+const (
+	ProviderErrorAuth         ProviderErrorKind = "auth"          // Неверный пользователь, ключ или запрещенный IP
+	ProviderErrorBalance      ProviderErrorKind = "balance"       // Закончились средства
+	ProviderErrorQuota        ProviderErrorKind = "quota"         // Исчерпана настроенная дневная квота аккаунта
+	ProviderErrorOverloaded   ProviderErrorKind = "overloaded"    // Провайдер перегружен или ограничил частоту запросов
+	ProviderErrorNoResults    ProviderErrorKind = "no_results"    // Поисковик ничего не нашел
+	ProviderErrorInvalidQuery ProviderErrorKind = "invalid_query" // Некорректный запрос или параметры
+	ProviderErrorNetwork      ProviderErrorKind = "network"       // Сбой соединения или таймаут
+	ProviderErrorUnknown      ProviderErrorKind = "unknown"
+)
+
+// ProviderError ошибка внешнего провайдера (SERP, Wordstat) с классом и подсказкой о паузе перед повтором
+type ProviderError struct {
+	Provider   string
+	Kind       ProviderErrorKind
+	Code       string // Код ошибки из ответа провайдера или HTTP статус
+	Message    string
+	RetryAfter time.Duration // 0, если провайдер не сообщил, когда повторить
+	Err        error
+}
+
+func (e *ProviderError) Error() string {
+	msg := fmt.Sprintf("%s %s error", e.Provider, e.Kind)
+	if e.Code != "" {
+		msg += " " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable сообщает, может ли повтор того же запроса пройти успешно
+func (e *ProviderError) Retryable() bool {
+	return e.Kind == ProviderErrorOverloaded || e.Kind == ProviderErrorNetwork
+}
+
+// Fatal сообщает, что остальные запросы с теми же учетными данными тоже не пройдут
+func (e *ProviderError) Fatal() bool {
+	return e.Kind == ProviderErrorAuth || e.Kind == ProviderErrorBalance || e.Kind == ProviderErrorQuota
+}
+
+// AsProviderError находит ProviderError в цепочке ошибок
+func AsProviderError(err error) (*ProviderError, bool) {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr, true
+	}
+	return nil, false
+}
+
+// IsProviderErrorKind проверяет класс ошибки провайдера в цепочке ошибок
+func IsProviderErrorKind(err error, kind ProviderErrorKind) bool {
+	providerErr, ok := AsProviderError(err)
+	return ok && providerErr.Kind == kind
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// ProviderLimits лимиты одного аккаунта провайдера; нулевое значение означает отсутствие ограничения
+type ProviderLimits struct {
+	RequestsPerSecond float64
+	MaxInFlight       int
+	DailyQuota        int
+}
+
+// ProviderLimiterState текущее состояние лимитов аккаунта
+type ProviderLimiterState struct {
+	Provider     string
+	Account      string
+	Limits       ProviderLimits
+	InFlight     int
+	Waiting      int
+	UsedToday    int
+	QuotaResetAt time.Time
+}
+
+// RateLimiter ограничивает запросы к провайдерам по аккаунтам, общий для синхронного и асинхронного отслеживания
+type RateLimiter interface {
+	// Acquire ждет разрешения на запрос; release нужно вызвать после получения ответа
+	Acquire(ctx context.Context, provider, account string) (release func(), err error)
+	States() []ProviderLimiterState
+}
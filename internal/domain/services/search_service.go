@@ -1,6 +1,88 @@
 package services
 
+import (
+	"context"
+
+	"go-seo/internal/domain/entities"
+)
+
+type SearchRequest struct {
+	Query        string
+	Page         int // Номер страницы, начиная с 0 (для Google пересчитывается в нумерацию с 1)
+	Device       string
+	OS           string
+	Ads          bool
+	Country      string
+	Lang         string
+	Domain       int    // ID домена Google
+	YandexDomain string // Домен Яндекса: ru, com, ua, com.tr, by, kz
+	LR           int    // ID региона Яндекса
+	GroupBy      int    // GroupBy для Yandex (page*10 для получения всех результатов сразу)
+	Organic      bool   // Если true, используем yandexlive endpoint
+	TBS          string // Период поиска Google (qdr:d, cdr:1,cd_min:...,cd_max:...)
+	Filter       *int   // Скрывать похожие результаты; nil - значение поисковика по умолчанию
+	Highlights   int    // Подсветка ключевых слов
+	NFPR         int    // Отключить исправление запроса Google
+	Loc          int    // ID местоположения Google
+	AI           int    // Парсить блок "Обзор от ИИ" Google
+	Raw          string // raw=page возвращает полный html выдачи
+	Within       int    // Фильтр Яндекса по периоду (77, 1, 2)
+	InIndex      int    // Проверка индексации
+	Strict       int    // Строгое соответствие при проверке индексации
+}
+
+// DomainPosition позиция отдельного домена в той же выдаче
+type DomainPosition struct {
+	Domain   string
+	Position int // 0, если домен не найден
+	URL      string
+	Title    string
+}
+
+// SitePositionResult позиция сайта, конкурентов и полный топ просмотренных страниц выдачи
+type SitePositionResult struct {
+	Position    int // 0, если сайт не найден
+	URL         string
+	Title       string
+	Competitors []DomainPosition // В порядке переданных competitorDomains
+	Items       []entities.SerpItem
+	Requests    int // Сколько платных запросов к провайдеру потребовалось
+}
+
+// ProviderCapabilities описывает, что умеет SERP провайдер
+type ProviderCapabilities struct {
+	Sources        []string // Поддерживаемые источники (google, yandex)
+	MaxPages       int      // Максимальная глубина поиска в страницах
+	GroupBy        bool     // Поддержка groupby (весь топ одним запросом)
+	CostPerRequest float64  // Стоимость одного запроса к провайдеру
+}
+
+func (c ProviderCapabilities) SupportsSource(source string) bool {
+	for _, s := range c.Sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
 type SearchService interface {
-	FindSitePosition(query, siteDomain, source string, maxPages int, device, os string, ads bool, country, lang string) (int, string, string, error)
+	Name() string
+	// Account идентификатор аккаунта провайдера (user id), от имени которого идут запросы
+	Account() string
+	Capabilities() ProviderCapabilities
+	// FindSitePosition ищет сайт и конкурентов одним проходом по выдаче, без отдельных запросов на каждый домен.
+	// ctx прерывает ожидание лимита и запросы к провайдеру. При ошибке result не nil и содержит число уже выполненных запросов
+	FindSitePosition(ctx context.Context, req SearchRequest, siteDomain string, competitorDomains []string, source string, maxPages int, subdomains bool) (*SitePositionResult, error)
+	// WithCredentials возвращает копию провайдера, работающую с переданными учетными данными; пустой baseURL оставляет адрес провайдера
+	WithCredentials(baseURL, userID, apiKey string) (SearchService, error)
+	Close() error
+}
+
+type SearchProviderRegistry interface {
+	Register(provider SearchService) error
+	// Get возвращает провайдера по имени; пустое имя - провайдер по умолчанию
+	Get(name string) (SearchService, error)
+	List() []SearchService
 	Close() error
 }
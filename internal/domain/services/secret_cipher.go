@@ -0,0 +1,7 @@
+package services
+
+// SecretCipher шифрует секреты (ключи провайдеров) для хранения в БД
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
@@ -0,0 +1,7 @@
+package services
+
+// SpreadsheetReader читает первый лист XLSX или CSV файла в строки ячеек.
+// Номер строки в результате соответствует номеру строки файла (начиная с 0), пустые строки сохраняются
+type SpreadsheetReader interface {
+	Read(filename string, data []byte) ([][]string, error)
+}
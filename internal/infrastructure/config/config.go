@@ -5,17 +5,24 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	XMLRiver XMLRiverConfig
-	XMLStock XMLStockConfig
-	Kafka    KafkaConfig
-	Async    AsyncConfig
+	Database   DatabaseConfig
+	Server     ServerConfig
+	XMLRiver   XMLRiverConfig
+	XMLStock   XMLStockConfig
+	Search     SearchConfig
+	Kafka      KafkaConfig
+	Async      AsyncConfig
+	Scheduler  SchedulerConfig
+	Retention  RetentionConfig
+	Visibility VisibilityConfig
+	Security   SecurityConfig
+	Auth       AuthConfig
 }
 
 type DatabaseConfig struct {
@@ -33,17 +40,35 @@ type ServerConfig struct {
 }
 
 type XMLRiverConfig struct {
-	UserID  string
-	APIKey  string
-	BaseURL string
-	SoftID  string
+	UserID                 string
+	APIKey                 string
+	BaseURL                string
+	SoftID                 string
+	MaxPages               int
+	CostPerRequest         float64
+	WordstatCostPerRequest float64
+	Limits                 ProviderLimitsConfig
 }
 
 type XMLStockConfig struct {
-	UserID  string
-	APIKey  string
-	BaseURL string
-	SoftID  string
+	UserID         string
+	APIKey         string
+	BaseURL        string
+	SoftID         string
+	MaxPages       int
+	CostPerRequest float64
+	Limits         ProviderLimitsConfig
+}
+
+// ProviderLimitsConfig лимиты одного аккаунта провайдера; 0 - без ограничения
+type ProviderLimitsConfig struct {
+	RequestsPerSecond float64
+	MaxInFlight       int
+	DailyQuota        int
+}
+
+type SearchConfig struct {
+	DefaultProvider string
 }
 
 type KafkaConfig struct {
@@ -53,6 +78,40 @@ type KafkaConfig struct {
 type AsyncConfig struct {
 	WorkerCount int
 	BatchSize   int
+	// Очередь джобов в БД
+	PollInterval  time.Duration
+	LeaseDuration time.Duration
+	MaxJobs       int
+}
+
+type SchedulerConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+// RetentionConfig хранение позиций: сроки в днях, 0 - хранить без сворачивания
+type RetentionConfig struct {
+	DetailDays      int
+	DailyRollupDays int
+	SnapshotDays    int
+	PartitionsAhead int
+	Interval        time.Duration
+}
+
+type VisibilityConfig struct {
+	// Как часто пересчитывается видимость сайтов за сегодня и вчера
+	Interval time.Duration
+}
+
+type SecurityConfig struct {
+	// Ключ AES-256 в base64 для шифрования ключей провайдеров; без него аккаунты провайдеров недоступны
+	CredentialsEncryptionKey string
+}
+
+type AuthConfig struct {
+	Enabled bool
+	// Ключ с правами admin, который регистрируется при старте, чтобы выпустить первые ключи через API
+	BootstrapKey string
 }
 
 func Load() (*Config, error) {
@@ -74,27 +133,69 @@ func Load() (*Config, error) {
 			TrustedProxies: getEnvAsStringSlice("SERVER_TRUSTED_PROXIES", []string{"127.0.0.1", "::1"}),
 		},
 		XMLRiver: XMLRiverConfig{
-			UserID:  getEnv("XMLRIVER_USER_ID", ""),
-			APIKey:  getEnv("XMLRIVER_API_KEY", ""),
-			BaseURL: getEnv("XMLRIVER_BASE_URL", "https://xmlriver.com"),
-			SoftID:  getEnv("XMLRIVER_SOFT_ID", "14"),
+			UserID:                 getEnv("XMLRIVER_USER_ID", ""),
+			APIKey:                 getEnv("XMLRIVER_API_KEY", ""),
+			BaseURL:                getEnv("XMLRIVER_BASE_URL", "https://xmlriver.com"),
+			SoftID:                 getEnv("XMLRIVER_SOFT_ID", "14"),
+			MaxPages:               getEnvAsInt("XMLRIVER_MAX_PAGES", 10),
+			CostPerRequest:         getEnvAsFloat("XMLRIVER_COST_PER_REQUEST", 0),
+			WordstatCostPerRequest: getEnvAsFloat("XMLRIVER_WORDSTAT_COST_PER_REQUEST", 0),
+			Limits:                 getProviderLimits("XMLRIVER"),
 		},
 		XMLStock: XMLStockConfig{
-			UserID:  getEnv("XMLSTOCK_USER_ID", ""),
-			APIKey:  getEnv("XMLSTOCK_API_KEY", ""),
-			BaseURL: getEnv("XMLSTOCK_BASE_URL", "https://xmlstock.com"),
-			SoftID:  getEnv("XMLSTOCK_SOFT_ID", "9b1db4389aad91266a6b9c1b7a349e93"),
+			UserID:         getEnv("XMLSTOCK_USER_ID", ""),
+			APIKey:         getEnv("XMLSTOCK_API_KEY", ""),
+			BaseURL:        getEnv("XMLSTOCK_BASE_URL", "https://xmlstock.com"),
+			SoftID:         getEnv("XMLSTOCK_SOFT_ID", "9b1db4389aad91266a6b9c1b7a349e93"),
+			MaxPages:       getEnvAsInt("XMLSTOCK_MAX_PAGES", 10),
+			CostPerRequest: getEnvAsFloat("XMLSTOCK_COST_PER_REQUEST", 0),
+			Limits:         getProviderLimits("XMLSTOCK"),
+		},
+		Search: SearchConfig{
+			DefaultProvider: getEnv("SEARCH_DEFAULT_PROVIDER", "xmlstock"),
 		},
 		Kafka: KafkaConfig{
 			Brokers: getEnvAsStringSlice("KAFKA_BROKERS", []string{"localhost:9092"}),
 		},
 		Async: AsyncConfig{
-			WorkerCount: getEnvAsInt("WORKER_COUNT", 20),
-			BatchSize:   getEnvAsInt("BATCH_SIZE", 100),
+			WorkerCount:   getEnvAsInt("WORKER_COUNT", 20),
+			BatchSize:     getEnvAsInt("BATCH_SIZE", 100),
+			PollInterval:  time.Duration(getEnvAsInt("QUEUE_POLL_INTERVAL_SECONDS", 5)) * time.Second,
+			LeaseDuration: time.Duration(getEnvAsInt("QUEUE_LEASE_SECONDS", 120)) * time.Second,
+			MaxJobs:       getEnvAsInt("QUEUE_MAX_JOBS", 4),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:  getEnvAsBool("SCHEDULER_ENABLED", true),
+			Interval: time.Duration(getEnvAsInt("SCHEDULER_INTERVAL_SECONDS", 30)) * time.Second,
+		},
+		Retention: RetentionConfig{
+			DetailDays:      getEnvAsInt("POSITIONS_DETAIL_RETENTION_DAYS", 0),
+			DailyRollupDays: getEnvAsInt("POSITIONS_DAILY_ROLLUP_RETENTION_DAYS", 0),
+			SnapshotDays:    getEnvAsInt("SERP_SNAPSHOT_RETENTION_DAYS", 0),
+			PartitionsAhead: getEnvAsInt("POSITIONS_PARTITIONS_AHEAD_MONTHS", 3),
+			Interval:        time.Duration(getEnvAsInt("POSITIONS_RETENTION_INTERVAL_HOURS", 6)) * time.Hour,
+		},
+		Visibility: VisibilityConfig{
+			Interval: time.Duration(getEnvAsInt("VISIBILITY_INTERVAL_MINUTES", 60)) * time.Minute,
+		},
+		Security: SecurityConfig{
+			CredentialsEncryptionKey: getEnv("CREDENTIALS_ENCRYPTION_KEY", ""),
+		},
+		Auth: AuthConfig{
+			Enabled:      getEnvAsBool("AUTH_ENABLED", true),
+			BootstrapKey: getEnv("AUTH_BOOTSTRAP_KEY", ""),
 		},
 	}, nil
 }
 
+func getProviderLimits(prefix string) ProviderLimitsConfig {
+	return ProviderLimitsConfig{
+		RequestsPerSecond: getEnvAsFloat(prefix+"_REQUESTS_PER_SECOND", 0),
+		MaxInFlight:       getEnvAsInt(prefix+"_MAX_IN_FLIGHT", 10),
+		DailyQuota:        getEnvAsInt(prefix+"_DAILY_QUOTA", 0),
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -111,6 +212,24 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsStringSlice(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		return strings.Split(value, ",")
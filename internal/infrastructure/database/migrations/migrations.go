@@ -1,271 +1,333 @@
 package migrations
 
 import (
-	"go-seo/internal/infrastructure/database/postgres/models"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
-func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&models.Site{},
-		&models.Group{},
-		&models.Keyword{},
-		&models.Position{},
-		&models.TrackingJob{},
-		&models.TrackingTask{},
-		&models.TrackingResult{},
-	)
+// Dir каталог миграций в репозитории; create пишет новые файлы сюда
+const Dir = "internal/infrastructure/database/migrations/sql"
+
+// noTransactionDirective в первой строке файла выполняет его вне транзакции
+// (нужно, например, для CREATE INDEX CONCURRENTLY)
+const noTransactionDirective = "-- migrate:no-transaction"
+
+// lockKey ключ advisory lock, чтобы два процесса не применяли миграции одновременно
+const lockKey = 7240315
+
+//go:embed sql/*.sql
+var embedded embed.FS
+
+var (
+	fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	nameCleaner     = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
 }
 
-func CreateTables(db *gorm.DB) error {
-	if err := db.AutoMigrate(&models.Group{}); err != nil {
-		return err
-	}
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time // nil - миграция не применена
+	Missing   bool       // Версия записана в БД, но файла миграции нет
+}
 
-	// Удаляем колонку name из таблицы sites, если она существует
-	var hasNameColumn bool
-	if err := db.Raw(`
-		SELECT EXISTS (
-			SELECT 1 
-			FROM information_schema.columns 
-			WHERE table_name = 'sites' 
-			AND column_name = 'name'
-		)
-	`).Scan(&hasNameColumn).Error; err != nil {
-		return err
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null;type:varchar(255)"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator применяет пронумерованные SQL миграции и хранит примененные версии в schema_migrations
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator использует миграции, встроенные в бинарник
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	sub, err := fs.Sub(embedded, "sql")
+	if err != nil {
+		return nil, err
 	}
 
-	if hasNameColumn {
-		if err := db.Exec(`ALTER TABLE sites DROP COLUMN IF EXISTS name`).Error; err != nil {
-			return err
-		}
+	migrations, err := Load(sub)
+	if err != nil {
+		return nil, err
 	}
 
-	// Проверяем и исправляем столбец domain в tracking_tasks
-	var hasDomainColumn bool
-	if err := db.Raw(`
-		SELECT EXISTS (
-			SELECT 1 
-			FROM information_schema.columns 
-			WHERE table_name = 'tracking_tasks' 
-			AND column_name = 'domain'
-		)
-	`).Scan(&hasDomainColumn).Error; err != nil {
-		return err
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load читает пары NNNN_name.up.sql / NNNN_name.down.sql и сортирует их по версии
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
 	}
 
-	if hasDomainColumn {
-		// Проверяем тип столбца
-		var columnType string
-		if err := db.Raw(`
-			SELECT data_type 
-			FROM information_schema.columns 
-			WHERE table_name = 'tracking_tasks' 
-			AND column_name = 'domain'
-		`).Scan(&columnType).Error; err != nil {
-			return err
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
 		}
 
-		// Если столбец имеет тип character varying (varchar), удаляем его
-		if columnType == "character varying" || columnType == "varchar" {
-			if err := db.Exec(`ALTER TABLE tracking_tasks DROP COLUMN IF EXISTS domain`).Error; err != nil {
-				return err
-			}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q, expected NNNN_name.up.sql or NNNN_name.down.sql", entry.Name())
 		}
-	}
 
-	if err := db.AutoMigrate(
-		&models.Site{},
-		&models.Keyword{},
-		&models.Position{},
-		&models.TrackingJob{},
-		&models.TrackingTask{},
-		&models.TrackingResult{},
-	); err != nil {
-		return err
-	}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_positions_keyword_site_date 
-		ON positions (keyword_id, site_id, date DESC);
-	`).Error; err != nil {
-		return err
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names: %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_positions_site_date 
-		ON positions (site_id, date DESC);
-	`).Error; err != nil {
-		return err
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have non-empty up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
 	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_positions_keyword_site_source 
-		ON positions (keyword_id, site_id, source);
-	`).Error; err != nil {
-		return err
+	return migrations, nil
+}
+
+// Up применяет не более steps непримененных миграций по возрастанию версии; steps <= 0 - все
+func (m *Migrator) Up(steps int) ([]Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_positions_source_date 
-		ON positions (source, date DESC);
-	`).Error; err != nil {
-		return err
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if steps > 0 && len(done) >= steps {
+			break
+		}
+
+		if err := m.run(migration, migration.Up, true); err != nil {
+			return done, fmt.Errorf("migration %04d_%s up: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_tracking_jobs_site_id 
-		ON tracking_jobs (site_id);
-	`).Error; err != nil {
-		return err
+	return done, nil
+}
+
+// Down откатывает steps последних примененных миграций; steps <= 0 - одну
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_tracking_jobs_status 
-		ON tracking_jobs (status);
-	`).Error; err != nil {
-		return err
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_tracking_tasks_job_id 
-		ON tracking_tasks (job_id);
-	`).Error; err != nil {
-		return err
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		if err := m.run(migration, migration.Down, false); err != nil {
+			return done, fmt.Errorf("migration %04d_%s down: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_tracking_tasks_keyword_id 
-		ON tracking_tasks (keyword_id);
-	`).Error; err != nil {
-		return err
+	return done, nil
+}
+
+// Status возвращает все миграции и время их применения. Версии из schema_migrations,
+// для которых нет файлов (например, после отката кода), помечаются Missing
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_tracking_tasks_site_id 
-		ON tracking_tasks (site_id);
-	`).Error; err != nil {
-		return err
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range applied {
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, MigrationStatus{Version: row.Version, Name: row.Name, AppliedAt: &appliedAt, Missing: true})
 	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_tracking_tasks_status 
-		ON tracking_tasks (status);
-	`).Error; err != nil {
-		return err
+	return statuses, nil
+}
+
+// Pending число непримененных миграций
+func (m *Migrator) Pending() (int, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return 0, err
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_tracking_results_task_id 
-		ON tracking_results (task_id);
-	`).Error; err != nil {
-		return err
+	pending := 0
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending++
+		}
 	}
+	return pending, nil
+}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_tracking_results_job_id 
-		ON tracking_results (job_id);
-	`).Error; err != nil {
-		return err
+// Create создает пару файлов со следующим номером версии в dir и возвращает их пути
+func Create(dir, name string) (string, string, error) {
+	name = strings.Trim(nameCleaner.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migration name is required")
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_tracking_results_keyword_id 
-		ON tracking_results (keyword_id);
-	`).Error; err != nil {
-		return err
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_tracking_results_site_id 
-		ON tracking_results (site_id);
-	`).Error; err != nil {
-		return err
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_positions_stats_main 
-		ON positions (site_id, source, date DESC, rank);
-	`).Error; err != nil {
-		return err
+	base := fmt.Sprintf("%04d_%s", version, name)
+	upPath := filepath.Join(dir, base+".up.sql")
+	downPath := filepath.Join(dir, base+".down.sql")
+
+	if err := os.WriteFile(upPath, []byte("-- "+base+": опишите изменение схемы\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(downPath, []byte("-- "+base+": отмена изменений из up\n"), 0o644); err != nil {
+		return "", "", err
 	}
 
-	var hasSiteIDColumn bool
-	if err := db.Raw(`
-		SELECT EXISTS (
-			SELECT 1 
-			FROM information_schema.columns 
-			WHERE table_name = 'groups' 
-			AND column_name = 'site_id'
-		)
-	`).Scan(&hasSiteIDColumn).Error; err != nil {
-		return err
+	return upPath, downPath, nil
+}
+
+func (m *Migrator) appliedVersions() (map[int64]schemaMigration, error) {
+	if err := m.db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
 	}
 
-	if !hasSiteIDColumn {
-		if err := db.Exec(`ALTER TABLE groups ADD COLUMN site_id INTEGER NOT NULL DEFAULT 1`).Error; err != nil {
-			return err
-		}
-		if err := db.Exec(`ALTER TABLE groups ALTER COLUMN site_id DROP DEFAULT`).Error; err != nil {
-			return err
-		}
+	var rows []schemaMigration
+	if err := m.db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
 	}
 
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_groups_site_id 
-		ON groups (site_id);
-	`).Error; err != nil {
-		return err
+	applied := make(map[int64]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
 	}
+	return applied, nil
+}
 
-	// Удаляем уникальное ограничение на поле domain в таблице sites, если оно существует
-	// Это позволяет создавать сайты с одинаковыми доменами
-	var constraintName string
-	if err := db.Raw(`
-		SELECT conname 
-		FROM pg_constraint 
-		WHERE conrelid = 'sites'::regclass 
-		AND contype = 'u'
-		AND pg_get_constraintdef(oid) LIKE '%domain%'
-		LIMIT 1
-	`).Scan(&constraintName).Error; err != nil {
-		// Игнорируем ошибку, если таблица еще не существует или ограничение не найдено
+// run выполняет SQL миграции и записывает (или удаляет) версию в одной транзакции.
+// Под блокировкой версия проверяется повторно: параллельный процесс мог уже применить миграцию
+func (m *Migrator) run(migration Migration, sql string, up bool) error {
+	if strings.HasPrefix(strings.TrimSpace(sql), noTransactionDirective) {
+		return m.runWithoutTransaction(migration, sql, up)
 	}
 
-	if constraintName != "" {
-		if err := db.Exec(`ALTER TABLE sites DROP CONSTRAINT IF EXISTS ` + constraintName).Error; err != nil {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockKey).Error; err != nil {
 			return err
 		}
-	}
-
-	// Также удаляем уникальные индексы на domain, если они существуют
-	var indexName string
-	if err := db.Raw(`
-		SELECT indexname 
-		FROM pg_indexes 
-		WHERE schemaname = 'public'
-		AND tablename = 'sites' 
-		AND indexdef LIKE '%UNIQUE%'
-		AND indexdef LIKE '%domain%'
-		LIMIT 1
-	`).Scan(&indexName).Error; err != nil {
-		// Игнорируем ошибку, если индекс не найден
-	}
+		return applyLocked(tx, migration, sql, up)
+	})
+}
 
-	if indexName != "" {
-		if err := db.Exec(`DROP INDEX IF EXISTS ` + indexName).Error; err != nil {
+// runWithoutTransaction держит сессионную блокировку на одном соединении, так как SQL идет вне транзакции
+func (m *Migrator) runWithoutTransaction(migration Migration, sql string, up bool) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
 			return err
 		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", lockKey)
+
+		return applyLocked(conn, migration, sql, up)
+	})
+}
+
+func applyLocked(db *gorm.DB, migration Migration, sql string, up bool) error {
+	var count int64
+	if err := db.Model(&schemaMigration{}).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+		return err
+	}
+	if (count > 0) == up {
+		return nil
 	}
 
-	//if err := db.Exec(`
-	//	CREATE INDEX IF NOT EXISTS idx_positions_trends
-	//	ON positions (keyword_id, date DESC, rank)
-	//	WHERE date >= CURRENT_DATE - INTERVAL '30 days';
-	//`).Error; err != nil {
-	//	return err
-	//} - пока без этого, ждем переезда на 16 версию
+	// Без аргументов SQL уходит простым протоколом, поэтому в файле может быть несколько команд
+	if err := db.Exec(sql).Error; err != nil {
+		return err
+	}
 
-	return nil
+	if up {
+		return db.Create(&schemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now(),
+		}).Error
+	}
+	return db.Delete(&schemaMigration{}, "version = ?", migration.Version).Error
 }
@@ -0,0 +1,201 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_tags.up.sql":   {Data: []byte("CREATE TABLE tags (id BIGSERIAL);")},
+		"0002_add_tags.down.sql": {Data: []byte("DROP TABLE tags;")},
+		"0001_baseline.up.sql":   {Data: []byte("CREATE TABLE sites (id BIGSERIAL);")},
+		"0001_baseline.down.sql": {Data: []byte("DROP TABLE sites;")},
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(migrations) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Version != 1 || migrations[0].Name != "baseline" {
+		t.Errorf("expected 0001_baseline first, got %04d_%s", migrations[0].Version, migrations[0].Name)
+	}
+	if migrations[1].Up != "CREATE TABLE tags (id BIGSERIAL);" || migrations[1].Down != "DROP TABLE tags;" {
+		t.Errorf("unexpected SQL for 0002: %+v", migrations[1])
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{
+				"0001_baseline.up.sql": {Data: []byte("SELECT 1;")},
+			},
+		},
+		{
+			name: "invalid file name",
+			fsys: fstest.MapFS{
+				"baseline.sql": {Data: []byte("SELECT 1;")},
+			},
+		},
+		{
+			name: "different names for one version",
+			fsys: fstest.MapFS{
+				"0001_baseline.up.sql":  {Data: []byte("SELECT 1;")},
+				"0001_initial.down.sql": {Data: []byte("SELECT 1;")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.fsys); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrator, err := NewMigrator(nil)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+
+	if len(migrator.migrations) == 0 || migrator.migrations[0].Name != "baseline" {
+		t.Fatalf("expected baseline as the first migration, got %+v", migrator.migrations)
+	}
+	for i, migration := range migrator.migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration versions must be sequential: expected %d, got %04d_%s", i+1, migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"0001_baseline.up.sql":   "SELECT 1;",
+		"0001_baseline.down.sql": "SELECT 1;",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	upPath, downPath, err := Create(dir, "Add keyword tags")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if filepath.Base(upPath) != "0002_add_keyword_tags.up.sql" {
+		t.Errorf("unexpected up file %s", upPath)
+	}
+	if filepath.Base(downPath) != "0002_add_keyword_tags.down.sql" {
+		t.Errorf("unexpected down file %s", downPath)
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("created files must load: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Errorf("expected 2 migrations, got %d", len(migrations))
+	}
+}
+
+// legacySchema таблицы в том виде, в каком их создавал AutoMigrate прежнего cmd/migrate
+const legacySchema = `
+CREATE TABLE sites (id BIGSERIAL PRIMARY KEY, domain TEXT NOT NULL, yandex_dynamic SMALLINT, google_dynamic SMALLINT,
+    created_at TIMESTAMPTZ, updated_at TIMESTAMPTZ);
+CREATE TABLE groups (id BIGSERIAL PRIMARY KEY, name TEXT NOT NULL, site_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ, updated_at TIMESTAMPTZ);
+CREATE TABLE keywords (id BIGSERIAL PRIMARY KEY, value TEXT NOT NULL, site_id BIGINT NOT NULL, group_id BIGINT,
+    created_at TIMESTAMPTZ, updated_at TIMESTAMPTZ);
+CREATE TABLE positions (id BIGSERIAL PRIMARY KEY, keyword_id BIGINT NOT NULL, site_id BIGINT NOT NULL,
+    rank BIGINT NOT NULL, url TEXT NOT NULL, title TEXT NOT NULL, source TEXT, device TEXT NOT NULL, os TEXT,
+    ads BOOLEAN NOT NULL, country TEXT, lang TEXT, pages BIGINT NOT NULL, date TIMESTAMPTZ NOT NULL,
+    filter_group_id BIGINT, wordstat_query_type VARCHAR(50), created_at TIMESTAMPTZ, updated_at TIMESTAMPTZ);
+CREATE TABLE tracking_jobs (id VARCHAR(50) PRIMARY KEY, site_id BIGINT NOT NULL, source VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL, created_at TIMESTAMPTZ NOT NULL, updated_at TIMESTAMPTZ NOT NULL, completed_at TIMESTAMPTZ,
+    total_tasks BIGINT NOT NULL DEFAULT 0, completed_tasks BIGINT NOT NULL DEFAULT 0, failed_tasks BIGINT NOT NULL DEFAULT 0,
+    failed_requests BIGINT NOT NULL DEFAULT 0, error TEXT);
+CREATE TABLE tracking_tasks (id VARCHAR(50) PRIMARY KEY, job_id VARCHAR(50) NOT NULL, keyword_id BIGINT NOT NULL,
+    site_id BIGINT NOT NULL, source VARCHAR(20) NOT NULL, status VARCHAR(20) NOT NULL, created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL, completed_at TIMESTAMPTZ, retry_count BIGINT NOT NULL DEFAULT 0,
+    max_retries BIGINT NOT NULL DEFAULT 5, error TEXT, xml_user_id VARCHAR(100), xml_api_key VARCHAR(100),
+    xml_base_url VARCHAR(200));
+INSERT INTO sites (domain) VALUES ('legacy.com');
+`
+
+// Базовая миграция на базе, созданной до версионных миграций, добавляет недостающие колонки
+// и удаляет ключи провайдера из задач. Схема создается внутри транзакции, которая откатывается в конце
+func TestBaselineUpgradesLegacySchema(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	tx := db.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+
+	migrator, err := NewMigrator(nil)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	for _, sql := range []string{
+		"CREATE SCHEMA legacy_baseline_test",
+		"SET LOCAL search_path TO legacy_baseline_test",
+		legacySchema,
+		migrator.migrations[0].Up,
+	} {
+		if err := tx.Exec(sql).Error; err != nil {
+			t.Fatalf("exec: %v", err)
+		}
+	}
+
+	var columns []string
+	if err := tx.Raw(`SELECT table_name || '.' || column_name FROM information_schema.columns
+		WHERE table_schema = 'legacy_baseline_test'`).Scan(&columns).Error; err != nil {
+		t.Fatalf("columns: %v", err)
+	}
+	present := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		present[column] = true
+	}
+	for _, column := range []string{
+		"sites.workspace_id", "sites.budget_action", "positions.competitor_id", "tracking_jobs.params",
+		"tracking_jobs.api_key_id", "tracking_jobs.locked_by", "tracking_jobs.started_at", "tracking_tasks.attempts",
+	} {
+		if !present[column] {
+			t.Errorf("column %s was not added", column)
+		}
+	}
+	for _, column := range []string{"tracking_tasks.xml_user_id", "tracking_tasks.xml_api_key", "tracking_tasks.xml_base_url"} {
+		if present[column] {
+			t.Errorf("legacy column %s was not dropped", column)
+		}
+	}
+
+	var workspaceID int
+	if err := tx.Raw("SELECT workspace_id FROM sites WHERE domain = 'legacy.com'").Scan(&workspaceID).Error; err != nil || workspaceID != 1 {
+		t.Fatalf("expected legacy site in the default workspace, got %d, %v", workspaceID, err)
+	}
+}
@@ -0,0 +1,16 @@
+-- Удаляет всю схему вместе с данными
+DROP TABLE IF EXISTS
+    api_keys,
+    provider_accounts,
+    provider_usage,
+    tracking_schedules,
+    competitors,
+    serp_snapshots,
+    tracking_results,
+    tracking_tasks,
+    tracking_jobs,
+    positions,
+    keywords,
+    groups,
+    sites,
+    workspaces;
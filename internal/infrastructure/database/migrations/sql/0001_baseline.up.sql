@@ -0,0 +1,288 @@
+-- Схема на момент перехода с AutoMigrate на версионные миграции.
+-- Все объекты создаются с IF NOT EXISTS: база, обновленная последней версией прежнего cmd/migrate
+-- (AutoMigrate), сохраняет данные. Таблицы из нее дополняются колонками, которых в прежних моделях
+-- не было, а колонки, которые больше не используются, удаляются
+
+CREATE TABLE IF NOT EXISTS workspaces (
+    id             BIGSERIAL PRIMARY KEY,
+    name           VARCHAR(100) NOT NULL,
+    monthly_budget NUMERIC(12,2) DEFAULT NULL,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspaces_name ON workspaces (name);
+
+INSERT INTO workspaces (id, name, created_at, updated_at)
+VALUES (1, 'default', NOW(), NOW())
+ON CONFLICT DO NOTHING;
+SELECT setval(pg_get_serial_sequence('workspaces', 'id'), GREATEST((SELECT MAX(id) FROM workspaces), 1));
+
+CREATE TABLE IF NOT EXISTS sites (
+    id             BIGSERIAL PRIMARY KEY,
+    workspace_id   BIGINT NOT NULL,
+    domain         TEXT NOT NULL,
+    yandex_dynamic SMALLINT DEFAULT NULL,
+    google_dynamic SMALLINT DEFAULT NULL,
+    monthly_budget NUMERIC(12,2) DEFAULT NULL,
+    budget_action  VARCHAR(10) NOT NULL DEFAULT 'refuse',
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ
+);
+-- Сайты прежней базы попадают в пространство по умолчанию
+ALTER TABLE sites ADD COLUMN IF NOT EXISTS workspace_id BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE sites ALTER COLUMN workspace_id DROP DEFAULT;
+ALTER TABLE sites ADD COLUMN IF NOT EXISTS monthly_budget NUMERIC(12,2) DEFAULT NULL;
+ALTER TABLE sites ADD COLUMN IF NOT EXISTS budget_action VARCHAR(10) NOT NULL DEFAULT 'refuse';
+CREATE INDEX IF NOT EXISTS idx_sites_workspace_id ON sites (workspace_id);
+
+CREATE TABLE IF NOT EXISTS groups (
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    site_id    BIGINT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_groups_site_id ON groups (site_id);
+
+CREATE TABLE IF NOT EXISTS keywords (
+    id         BIGSERIAL PRIMARY KEY,
+    value      TEXT NOT NULL,
+    site_id    BIGINT NOT NULL,
+    group_id   BIGINT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT fk_keywords_site FOREIGN KEY (site_id) REFERENCES sites (id),
+    CONSTRAINT fk_keywords_group FOREIGN KEY (group_id) REFERENCES groups (id)
+);
+CREATE INDEX IF NOT EXISTS idx_keywords_site_id ON keywords (site_id);
+CREATE INDEX IF NOT EXISTS idx_keywords_group_id ON keywords (group_id);
+
+CREATE TABLE IF NOT EXISTS positions (
+    id                  BIGSERIAL PRIMARY KEY,
+    keyword_id          BIGINT NOT NULL,
+    site_id             BIGINT NOT NULL,
+    competitor_id       BIGINT,
+    rank                BIGINT NOT NULL,
+    url                 TEXT NOT NULL,
+    title               TEXT NOT NULL,
+    source              TEXT,
+    device              TEXT NOT NULL,
+    os                  TEXT,
+    ads                 BOOLEAN NOT NULL,
+    country             TEXT,
+    lang                TEXT,
+    pages               BIGINT NOT NULL,
+    date                TIMESTAMPTZ NOT NULL,
+    filter_group_id     BIGINT,
+    wordstat_query_type VARCHAR(50),
+    created_at          TIMESTAMPTZ,
+    updated_at          TIMESTAMPTZ,
+    CONSTRAINT fk_positions_keyword FOREIGN KEY (keyword_id) REFERENCES keywords (id),
+    CONSTRAINT fk_positions_site FOREIGN KEY (site_id) REFERENCES sites (id)
+);
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS competitor_id BIGINT;
+CREATE INDEX IF NOT EXISTS idx_positions_keyword_id ON positions (keyword_id);
+CREATE INDEX IF NOT EXISTS idx_positions_site_id ON positions (site_id);
+CREATE INDEX IF NOT EXISTS idx_positions_competitor_id ON positions (competitor_id);
+CREATE INDEX IF NOT EXISTS idx_positions_source ON positions (source);
+CREATE INDEX IF NOT EXISTS idx_positions_filter_group_id ON positions (filter_group_id);
+CREATE INDEX IF NOT EXISTS idx_positions_keyword_site_date ON positions (keyword_id, site_id, date DESC);
+CREATE INDEX IF NOT EXISTS idx_positions_site_date ON positions (site_id, date DESC);
+CREATE INDEX IF NOT EXISTS idx_positions_keyword_site_source ON positions (keyword_id, site_id, source);
+CREATE INDEX IF NOT EXISTS idx_positions_source_date ON positions (source, date DESC);
+CREATE INDEX IF NOT EXISTS idx_positions_stats_main ON positions (site_id, source, date DESC, rank);
+
+CREATE TABLE IF NOT EXISTS tracking_jobs (
+    id              VARCHAR(50) PRIMARY KEY,
+    site_id         BIGINT NOT NULL,
+    source          VARCHAR(20) NOT NULL,
+    status          VARCHAR(20) NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL,
+    started_at      TIMESTAMPTZ,
+    completed_at    TIMESTAMPTZ,
+    total_tasks     BIGINT NOT NULL DEFAULT 0,
+    completed_tasks BIGINT NOT NULL DEFAULT 0,
+    failed_tasks    BIGINT NOT NULL DEFAULT 0,
+    failed_requests BIGINT NOT NULL DEFAULT 0,
+    error           TEXT,
+    params          JSONB NOT NULL DEFAULT '{}',
+    api_key_id      INTEGER,
+    locked_by       VARCHAR(100),
+    locked_until    TIMESTAMPTZ,
+    estimated_cost  DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+ALTER TABLE tracking_jobs ADD COLUMN IF NOT EXISTS started_at TIMESTAMPTZ;
+ALTER TABLE tracking_jobs ADD COLUMN IF NOT EXISTS params JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE tracking_jobs ADD COLUMN IF NOT EXISTS api_key_id INTEGER;
+ALTER TABLE tracking_jobs ADD COLUMN IF NOT EXISTS locked_by VARCHAR(100);
+ALTER TABLE tracking_jobs ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+ALTER TABLE tracking_jobs ADD COLUMN IF NOT EXISTS estimated_cost DOUBLE PRECISION NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_tracking_jobs_site_id ON tracking_jobs (site_id);
+CREATE INDEX IF NOT EXISTS idx_tracking_jobs_status ON tracking_jobs (status);
+CREATE INDEX IF NOT EXISTS idx_tracking_jobs_api_key_id ON tracking_jobs (api_key_id);
+
+CREATE TABLE IF NOT EXISTS tracking_tasks (
+    id                  VARCHAR(50) PRIMARY KEY,
+    job_id              VARCHAR(50) NOT NULL,
+    keyword_id          BIGINT NOT NULL,
+    site_id             BIGINT NOT NULL,
+    source              VARCHAR(20) NOT NULL,
+    status              VARCHAR(20) NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL,
+    updated_at          TIMESTAMPTZ NOT NULL,
+    completed_at        TIMESTAMPTZ,
+    retry_count         BIGINT NOT NULL DEFAULT 0,
+    max_retries         BIGINT NOT NULL DEFAULT 5,
+    error               TEXT,
+    attempts            BIGINT NOT NULL DEFAULT 0,
+    device              VARCHAR(20),
+    os                  VARCHAR(20),
+    ads                 BOOLEAN DEFAULT FALSE,
+    country             VARCHAR(10),
+    lang                VARCHAR(10),
+    pages               BIGINT DEFAULT 0,
+    subdomains          BOOLEAN DEFAULT FALSE,
+    tbs                 VARCHAR(50),
+    filter              BIGINT DEFAULT 0,
+    highlights          BIGINT DEFAULT 0,
+    nfpr                BIGINT DEFAULT 0,
+    loc                 BIGINT DEFAULT 0,
+    ai                  BIGINT DEFAULT 0,
+    raw                 VARCHAR(50),
+    group_by            BIGINT DEFAULT 0,
+    within              BIGINT DEFAULT 0,
+    lr                  BIGINT DEFAULT 0,
+    domain              BIGINT DEFAULT 0,
+    in_index            BIGINT DEFAULT 0,
+    strict              BIGINT DEFAULT 0,
+    organic             BOOLEAN DEFAULT FALSE,
+    regions             INTEGER,
+    filter_group_id     INTEGER,
+    wordstat_query_type VARCHAR(50)
+);
+ALTER TABLE tracking_tasks ADD COLUMN IF NOT EXISTS attempts BIGINT NOT NULL DEFAULT 0;
+-- Учетные данные провайдера задачи хранили открытым текстом, теперь они берутся из provider_accounts
+ALTER TABLE tracking_tasks DROP COLUMN IF EXISTS xml_user_id;
+ALTER TABLE tracking_tasks DROP COLUMN IF EXISTS xml_api_key;
+ALTER TABLE tracking_tasks DROP COLUMN IF EXISTS xml_base_url;
+CREATE INDEX IF NOT EXISTS idx_tracking_tasks_job_id ON tracking_tasks (job_id);
+CREATE INDEX IF NOT EXISTS idx_tracking_tasks_keyword_id ON tracking_tasks (keyword_id);
+CREATE INDEX IF NOT EXISTS idx_tracking_tasks_site_id ON tracking_tasks (site_id);
+CREATE INDEX IF NOT EXISTS idx_tracking_tasks_status ON tracking_tasks (status);
+CREATE INDEX IF NOT EXISTS idx_tracking_tasks_filter_group_id ON tracking_tasks (filter_group_id);
+
+CREATE TABLE IF NOT EXISTS tracking_results (
+    id         BIGSERIAL PRIMARY KEY,
+    task_id    VARCHAR(50) NOT NULL,
+    job_id     VARCHAR(50) NOT NULL,
+    keyword_id BIGINT NOT NULL,
+    site_id    BIGINT NOT NULL,
+    source     VARCHAR(20) NOT NULL,
+    rank       BIGINT NOT NULL,
+    url        TEXT,
+    title      TEXT,
+    device     VARCHAR(20),
+    os         VARCHAR(20),
+    ads        BOOLEAN DEFAULT FALSE,
+    country    VARCHAR(10),
+    lang       VARCHAR(10),
+    pages      BIGINT DEFAULT 0,
+    date       TIMESTAMPTZ NOT NULL,
+    success    BOOLEAN NOT NULL,
+    error      TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_tracking_results_task_id ON tracking_results (task_id);
+CREATE INDEX IF NOT EXISTS idx_tracking_results_job_id ON tracking_results (job_id);
+CREATE INDEX IF NOT EXISTS idx_tracking_results_keyword_id ON tracking_results (keyword_id);
+CREATE INDEX IF NOT EXISTS idx_tracking_results_site_id ON tracking_results (site_id);
+
+CREATE TABLE IF NOT EXISTS serp_snapshots (
+    id           BIGSERIAL PRIMARY KEY,
+    position_id  BIGINT NOT NULL,
+    keyword_id   BIGINT NOT NULL,
+    site_id      BIGINT NOT NULL,
+    source       VARCHAR(20) NOT NULL,
+    place        BIGINT NOT NULL,
+    rank         BIGINT NOT NULL DEFAULT 0,
+    url          TEXT,
+    domain       VARCHAR(255),
+    title        TEXT,
+    passage      TEXT,
+    content_type VARCHAR(50),
+    date         TIMESTAMPTZ NOT NULL,
+    created_at   TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_serp_snapshots_position_id ON serp_snapshots (position_id);
+CREATE INDEX IF NOT EXISTS idx_serp_snapshots_keyword_id ON serp_snapshots (keyword_id);
+CREATE INDEX IF NOT EXISTS idx_serp_snapshots_site_id ON serp_snapshots (site_id);
+CREATE INDEX IF NOT EXISTS idx_serp_snapshots_domain ON serp_snapshots (domain);
+CREATE INDEX IF NOT EXISTS idx_serp_snapshots_keyword_site_date ON serp_snapshots (keyword_id, site_id, date DESC);
+
+CREATE TABLE IF NOT EXISTS competitors (
+    id         BIGSERIAL PRIMARY KEY,
+    site_id    BIGINT NOT NULL,
+    domain     TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_competitors_site_domain ON competitors (site_id, domain);
+
+CREATE TABLE IF NOT EXISTS tracking_schedules (
+    id          BIGSERIAL PRIMARY KEY,
+    site_id     BIGINT NOT NULL,
+    source      VARCHAR(20) NOT NULL,
+    params      JSONB NOT NULL DEFAULT '{}',
+    cron_expr   VARCHAR(100) NOT NULL,
+    timezone    VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    enabled     BOOLEAN NOT NULL DEFAULT TRUE,
+    last_run_at TIMESTAMPTZ,
+    next_run_at TIMESTAMPTZ,
+    last_job_id VARCHAR(50),
+    last_error  TEXT,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_tracking_schedules_site_id ON tracking_schedules (site_id);
+CREATE INDEX IF NOT EXISTS idx_tracking_schedules_next_run_at ON tracking_schedules (next_run_at);
+
+CREATE TABLE IF NOT EXISTS provider_usage (
+    id         BIGSERIAL PRIMARY KEY,
+    job_id     VARCHAR(50),
+    site_id    BIGINT NOT NULL,
+    source     VARCHAR(20) NOT NULL,
+    provider   VARCHAR(50) NOT NULL,
+    account    VARCHAR(100),
+    requests   BIGINT NOT NULL,
+    cost       NUMERIC(12,4) NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_provider_usage_job_id ON provider_usage (job_id);
+CREATE INDEX IF NOT EXISTS idx_provider_usage_site_created ON provider_usage (site_id, created_at);
+
+CREATE TABLE IF NOT EXISTS provider_accounts (
+    id                BIGSERIAL PRIMARY KEY,
+    workspace_id      BIGINT NOT NULL,
+    name              VARCHAR(100) NOT NULL,
+    provider          VARCHAR(50) NOT NULL,
+    user_id           VARCHAR(100) NOT NULL,
+    encrypted_api_key TEXT NOT NULL,
+    base_url          VARCHAR(200),
+    created_at        TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_provider_accounts_workspace_name ON provider_accounts (workspace_id, name);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           BIGSERIAL PRIMARY KEY,
+    workspace_id INTEGER,
+    name         VARCHAR(100) NOT NULL,
+    prefix       VARCHAR(20) NOT NULL,
+    key_hash     VARCHAR(64) NOT NULL,
+    scopes       VARCHAR(200) NOT NULL,
+    created_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys (key_hash);
+CREATE INDEX IF NOT EXISTS idx_api_keys_workspace_id ON api_keys (workspace_id);
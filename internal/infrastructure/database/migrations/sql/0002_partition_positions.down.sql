@@ -0,0 +1,53 @@
+-- Возвращает обычную таблицу positions. Свернутые в position_rollups позиции при откате теряются
+
+DROP TABLE IF EXISTS position_rollups;
+
+ALTER TABLE positions RENAME TO positions_partitioned;
+ALTER INDEX positions_pkey RENAME TO positions_partitioned_pkey;
+ALTER SEQUENCE positions_id_seq OWNED BY NONE;
+
+CREATE TABLE positions (
+    id                  BIGINT PRIMARY KEY DEFAULT nextval('positions_id_seq'),
+    keyword_id          BIGINT NOT NULL,
+    site_id             BIGINT NOT NULL,
+    competitor_id       BIGINT,
+    rank                BIGINT NOT NULL,
+    url                 TEXT NOT NULL,
+    title               TEXT NOT NULL,
+    source              TEXT,
+    device              TEXT NOT NULL,
+    os                  TEXT,
+    ads                 BOOLEAN NOT NULL,
+    country             TEXT,
+    lang                TEXT,
+    pages               BIGINT NOT NULL,
+    date                TIMESTAMPTZ NOT NULL,
+    filter_group_id     BIGINT,
+    wordstat_query_type VARCHAR(50),
+    created_at          TIMESTAMPTZ,
+    updated_at          TIMESTAMPTZ
+);
+
+INSERT INTO positions (id, keyword_id, site_id, competitor_id, rank, url, title, source, device, os, ads,
+                       country, lang, pages, date, filter_group_id, wordstat_query_type, created_at, updated_at)
+SELECT id, keyword_id, site_id, competitor_id, rank, url, title, source, device, os, ads,
+       country, lang, pages, date, filter_group_id, wordstat_query_type, created_at, updated_at
+FROM positions_partitioned;
+
+DROP TABLE positions_partitioned;
+DROP FUNCTION IF EXISTS ensure_positions_partition(DATE);
+ALTER SEQUENCE positions_id_seq OWNED BY positions.id;
+
+ALTER TABLE positions ADD CONSTRAINT fk_positions_keyword FOREIGN KEY (keyword_id) REFERENCES keywords (id);
+ALTER TABLE positions ADD CONSTRAINT fk_positions_site FOREIGN KEY (site_id) REFERENCES sites (id);
+
+CREATE INDEX idx_positions_keyword_id ON positions (keyword_id);
+CREATE INDEX idx_positions_site_id ON positions (site_id);
+CREATE INDEX idx_positions_competitor_id ON positions (competitor_id);
+CREATE INDEX idx_positions_source ON positions (source);
+CREATE INDEX idx_positions_filter_group_id ON positions (filter_group_id);
+CREATE INDEX idx_positions_keyword_site_date ON positions (keyword_id, site_id, date DESC);
+CREATE INDEX idx_positions_site_date ON positions (site_id, date DESC);
+CREATE INDEX idx_positions_keyword_site_source ON positions (keyword_id, site_id, source);
+CREATE INDEX idx_positions_source_date ON positions (source, date DESC);
+CREATE INDEX idx_positions_stats_main ON positions (site_id, source, date DESC, rank);
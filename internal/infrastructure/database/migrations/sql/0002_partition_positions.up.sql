@@ -0,0 +1,129 @@
+-- Позиции разбиваются на месячные секции по date, старые детальные записи сворачиваются
+-- заданием хранения в position_rollups
+
+ALTER TABLE positions RENAME TO positions_unpartitioned;
+ALTER INDEX positions_pkey RENAME TO positions_unpartitioned_pkey;
+ALTER SEQUENCE positions_id_seq OWNED BY NONE;
+
+CREATE TABLE positions (
+    id                  BIGINT NOT NULL DEFAULT nextval('positions_id_seq'),
+    keyword_id          BIGINT NOT NULL,
+    site_id             BIGINT NOT NULL,
+    competitor_id       BIGINT,
+    rank                BIGINT NOT NULL,
+    url                 TEXT NOT NULL,
+    title               TEXT NOT NULL,
+    source              TEXT,
+    device              TEXT NOT NULL,
+    os                  TEXT,
+    ads                 BOOLEAN NOT NULL,
+    country             TEXT,
+    lang                TEXT,
+    pages               BIGINT NOT NULL,
+    date                TIMESTAMPTZ NOT NULL,
+    filter_group_id     BIGINT,
+    wordstat_query_type VARCHAR(50),
+    created_at          TIMESTAMPTZ,
+    updated_at          TIMESTAMPTZ,
+    PRIMARY KEY (id, date)
+) PARTITION BY RANGE (date);
+
+-- Сюда попадают даты, для которых секция еще не создана
+CREATE TABLE positions_default PARTITION OF positions DEFAULT;
+
+-- ensure_positions_partition создает секцию месяца positions_pYYYY_MM, если ее нет.
+-- Строки этого месяца, уже попавшие в секцию по умолчанию, переносятся в новую секцию
+CREATE OR REPLACE FUNCTION ensure_positions_partition(month DATE) RETURNS TEXT AS $$
+DECLARE
+    start_date DATE := date_trunc('month', month)::date;
+    end_date   DATE := (date_trunc('month', month) + INTERVAL '1 month')::date;
+    part_name  TEXT := format('positions_p%s', to_char(start_date, 'YYYY_MM'));
+BEGIN
+    IF to_regclass(part_name) IS NOT NULL THEN
+        RETURN part_name;
+    END IF;
+
+    EXECUTE format('CREATE TABLE %I (LIKE positions INCLUDING DEFAULTS)', part_name);
+    EXECUTE format('INSERT INTO %I SELECT * FROM positions_default WHERE date >= %L AND date < %L', part_name, start_date, end_date);
+    EXECUTE format('DELETE FROM positions_default WHERE date >= %L AND date < %L', start_date, end_date);
+    EXECUTE format('ALTER TABLE positions ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)', part_name, start_date, end_date);
+
+    RETURN part_name;
+END;
+$$ LANGUAGE plpgsql;
+
+SELECT ensure_positions_partition(month::date)
+FROM generate_series(
+    date_trunc('month', COALESCE((SELECT MIN(date) FROM positions_unpartitioned), NOW())),
+    date_trunc('month', NOW()) + INTERVAL '3 months',
+    INTERVAL '1 month'
+) AS month;
+
+INSERT INTO positions (id, keyword_id, site_id, competitor_id, rank, url, title, source, device, os, ads,
+                       country, lang, pages, date, filter_group_id, wordstat_query_type, created_at, updated_at)
+SELECT id, keyword_id, site_id, competitor_id, rank, url, title, source, device, os, ads,
+       country, lang, pages, date, filter_group_id, wordstat_query_type, created_at, updated_at
+FROM positions_unpartitioned;
+
+DROP TABLE positions_unpartitioned;
+ALTER SEQUENCE positions_id_seq OWNED BY positions.id;
+
+ALTER TABLE positions ADD CONSTRAINT fk_positions_keyword FOREIGN KEY (keyword_id) REFERENCES keywords (id);
+ALTER TABLE positions ADD CONSTRAINT fk_positions_site FOREIGN KEY (site_id) REFERENCES sites (id);
+
+CREATE INDEX idx_positions_id ON positions (id);
+CREATE INDEX idx_positions_keyword_id ON positions (keyword_id);
+CREATE INDEX idx_positions_site_id ON positions (site_id);
+CREATE INDEX idx_positions_competitor_id ON positions (competitor_id);
+CREATE INDEX idx_positions_source ON positions (source);
+CREATE INDEX idx_positions_filter_group_id ON positions (filter_group_id);
+CREATE INDEX idx_positions_keyword_site_date ON positions (keyword_id, site_id, date DESC);
+CREATE INDEX idx_positions_site_date ON positions (site_id, date DESC);
+CREATE INDEX idx_positions_keyword_site_source ON positions (keyword_id, site_id, source);
+CREATE INDEX idx_positions_source_date ON positions (source, date DESC);
+CREATE INDEX idx_positions_stats_main ON positions (site_id, source, date DESC, rank);
+
+-- Агрегаты позиций за день (period = 'day') или неделю (period = 'week').
+-- rank, url, title и date - последняя проверка периода, остальные поля считаются по всем проверкам
+CREATE TABLE position_rollups (
+    id                  BIGSERIAL PRIMARY KEY,
+    period              VARCHAR(10) NOT NULL,
+    period_start        DATE NOT NULL,
+    period_end          DATE NOT NULL,
+    keyword_id          BIGINT NOT NULL,
+    site_id             BIGINT NOT NULL,
+    competitor_id       BIGINT,
+    source              TEXT,
+    filter_group_id     BIGINT,
+    wordstat_query_type VARCHAR(50) NOT NULL DEFAULT '',
+    rank                BIGINT NOT NULL,
+    url                 TEXT NOT NULL,
+    title               TEXT NOT NULL,
+    device              TEXT NOT NULL,
+    os                  TEXT,
+    ads                 BOOLEAN NOT NULL,
+    country             TEXT,
+    lang                TEXT,
+    pages               BIGINT NOT NULL,
+    date                TIMESTAMPTZ NOT NULL,
+    samples             BIGINT NOT NULL,
+    visible_samples     BIGINT NOT NULL,
+    rank_sum            BIGINT NOT NULL,
+    best_rank           BIGINT,
+    worst_rank          BIGINT,
+    range_1_3           BIGINT NOT NULL,
+    range_4_10          BIGINT NOT NULL,
+    range_11_30         BIGINT NOT NULL,
+    range_31_50         BIGINT NOT NULL,
+    range_51_100        BIGINT NOT NULL,
+    range_100_plus      BIGINT NOT NULL,
+    CONSTRAINT fk_position_rollups_keyword FOREIGN KEY (keyword_id) REFERENCES keywords (id),
+    CONSTRAINT fk_position_rollups_site FOREIGN KEY (site_id) REFERENCES sites (id)
+);
+CREATE UNIQUE INDEX idx_position_rollups_key ON position_rollups (
+    period, period_start, keyword_id, site_id, (COALESCE(competitor_id, 0)), (COALESCE(source, '')),
+    (COALESCE(filter_group_id, 0)), wordstat_query_type
+);
+CREATE INDEX idx_position_rollups_site_period ON position_rollups (site_id, period_start DESC);
+CREATE INDEX idx_position_rollups_keyword_site ON position_rollups (keyword_id, site_id, period_start DESC);
+CREATE INDEX idx_position_rollups_competitor_id ON position_rollups (competitor_id);
@@ -0,0 +1,18 @@
+-- Видимость сайта по дням: источник, группа ключевых слов (0 - все) и группа фильтров (0 - без группы)
+CREATE TABLE site_visibility (
+    id                BIGSERIAL PRIMARY KEY,
+    site_id           BIGINT NOT NULL,
+    date              DATE NOT NULL,
+    source            VARCHAR(20) NOT NULL,
+    group_id          BIGINT NOT NULL DEFAULT 0,
+    filter_group_id   BIGINT NOT NULL DEFAULT 0,
+    keywords_count    BIGINT NOT NULL DEFAULT 0,
+    found_count       BIGINT NOT NULL DEFAULT 0,
+    top10_count       BIGINT NOT NULL DEFAULT 0,
+    frequency         BIGINT NOT NULL DEFAULT 0,
+    estimated_traffic NUMERIC(14,2) NOT NULL DEFAULT 0,
+    visibility        NUMERIC(6,2) NOT NULL DEFAULT 0,
+    calculated_at     TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_site_visibility_site FOREIGN KEY (site_id) REFERENCES sites (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_site_visibility_key ON site_visibility (site_id, date, source, group_id, filter_group_id);
@@ -0,0 +1,11 @@
+ALTER TABLE sites
+    ADD COLUMN yandex_dynamic SMALLINT DEFAULT NULL,
+    ADD COLUMN google_dynamic SMALLINT DEFAULT NULL;
+
+UPDATE sites SET
+    yandex_dynamic = CASE WHEN (yandex_movement->>'net_score')::int > 0 THEN 1 WHEN (yandex_movement->>'net_score')::int < 0 THEN 0 END,
+    google_dynamic = CASE WHEN (google_movement->>'net_score')::int > 0 THEN 1 WHEN (google_movement->>'net_score')::int < 0 THEN 0 END;
+
+ALTER TABLE sites
+    DROP COLUMN IF EXISTS yandex_movement,
+    DROP COLUMN IF EXISTS google_movement;
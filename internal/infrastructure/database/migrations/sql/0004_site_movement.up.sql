@@ -0,0 +1,9 @@
+-- Бинарная динамика сайта (1 - рост, 0 - падение) заменяется сводкой движения позиций
+-- между двумя последними проверками; сводки заполнятся после следующих проверок
+ALTER TABLE sites
+    ADD COLUMN yandex_movement JSONB DEFAULT NULL,
+    ADD COLUMN google_movement JSONB DEFAULT NULL;
+
+ALTER TABLE sites
+    DROP COLUMN IF EXISTS yandex_dynamic,
+    DROP COLUMN IF EXISTS google_dynamic;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_groups_site_name;
+DROP INDEX IF EXISTS idx_keywords_site_value;
+DROP TABLE IF EXISTS keyword_tags;
+DROP TABLE IF EXISTS tags;
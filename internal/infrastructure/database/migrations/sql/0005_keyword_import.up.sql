@@ -0,0 +1,22 @@
+-- Метки ключевых слов (многие ко многим) и индекс для поиска слов сайта по значению при импорте
+CREATE TABLE tags (
+    id         BIGSERIAL PRIMARY KEY,
+    site_id    BIGINT NOT NULL,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT fk_tags_site FOREIGN KEY (site_id) REFERENCES sites (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_tags_site_name ON tags (site_id, lower(name));
+
+CREATE TABLE keyword_tags (
+    keyword_id BIGINT NOT NULL,
+    tag_id     BIGINT NOT NULL,
+    PRIMARY KEY (keyword_id, tag_id),
+    CONSTRAINT fk_keyword_tags_keyword FOREIGN KEY (keyword_id) REFERENCES keywords (id) ON DELETE CASCADE,
+    CONSTRAINT fk_keyword_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_keyword_tags_tag_id ON keyword_tags (tag_id);
+
+CREATE INDEX idx_keywords_site_value ON keywords (site_id, value);
+CREATE INDEX idx_groups_site_name ON groups (site_id, lower(name));
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_keywords_site_value ON keywords (site_id, value);
+DROP INDEX IF EXISTS idx_keywords_site_normalized;
+ALTER TABLE keywords DROP COLUMN IF EXISTS normalized_value;
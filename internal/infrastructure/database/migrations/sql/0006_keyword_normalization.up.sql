@@ -0,0 +1,18 @@
+-- Нормализованный ключ ключевого слова (пробелы схлопнуты, нижний регистр, ё -> е) уникален в сайте.
+-- Из уже существующих дублей ключ получает самое старое слово, у остальных он пустой, пока их не объединят
+ALTER TABLE keywords ADD COLUMN normalized_value TEXT;
+
+-- lower() с правилами ICU приводит кириллицу к нижнему регистру как strings.ToLower при любой collation базы
+UPDATE keywords
+SET normalized_value = translate(lower(btrim(regexp_replace(value, '[[:space:]]+', ' ', 'g')) COLLATE "und-x-icu"), 'ё', 'е');
+
+UPDATE keywords k
+SET normalized_value = NULL
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY site_id, normalized_value ORDER BY id) AS n
+    FROM keywords
+) d
+WHERE d.id = k.id AND d.n > 1;
+
+CREATE UNIQUE INDEX idx_keywords_site_normalized ON keywords (site_id, normalized_value);
+DROP INDEX IF EXISTS idx_keywords_site_value;
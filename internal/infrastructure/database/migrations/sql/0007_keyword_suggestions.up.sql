@@ -0,0 +1,15 @@
+-- Подсказки ключевых слов из Wordstat. region NULL - все регионы; фраза хранится один раз на сайт и регион
+CREATE TABLE keyword_suggestions (
+    id               BIGSERIAL PRIMARY KEY,
+    site_id          BIGINT NOT NULL,
+    value            TEXT NOT NULL,
+    normalized_value TEXT NOT NULL,
+    seed             TEXT NOT NULL,
+    kind             VARCHAR(20) NOT NULL,
+    region           INTEGER,
+    frequency        INTEGER NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ,
+    CONSTRAINT fk_keyword_suggestions_site FOREIGN KEY (site_id) REFERENCES sites (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_keyword_suggestions_site_key ON keyword_suggestions (site_id, normalized_value, COALESCE(region, 0));
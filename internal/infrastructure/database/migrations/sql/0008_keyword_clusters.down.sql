@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_keywords_cluster_id;
+ALTER TABLE keywords DROP CONSTRAINT IF EXISTS fk_keywords_cluster;
+ALTER TABLE keywords DROP COLUMN IF EXISTS cluster_id;
+DROP TABLE IF EXISTS keyword_clusters;
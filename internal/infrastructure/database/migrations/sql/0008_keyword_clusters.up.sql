@@ -0,0 +1,20 @@
+-- Кластеры ключевых слов по пересечению топа выдачи. У сайта хранится одна, последняя кластеризация:
+-- новый запуск удаляет прежние кластеры, а cluster_id слов обнуляется внешним ключом
+CREATE TABLE keyword_clusters (
+    id                BIGSERIAL PRIMARY KEY,
+    site_id           BIGINT NOT NULL,
+    source            VARCHAR(20) NOT NULL,
+    filter_group_id   BIGINT,
+    mode              VARCHAR(10) NOT NULL,
+    threshold         INTEGER NOT NULL,
+    name              TEXT NOT NULL,
+    marker_keyword_id BIGINT,
+    created_at        TIMESTAMPTZ,
+    CONSTRAINT fk_keyword_clusters_site FOREIGN KEY (site_id) REFERENCES sites (id) ON DELETE CASCADE,
+    CONSTRAINT fk_keyword_clusters_marker FOREIGN KEY (marker_keyword_id) REFERENCES keywords (id) ON DELETE SET NULL
+);
+CREATE INDEX idx_keyword_clusters_site_id ON keyword_clusters (site_id);
+
+ALTER TABLE keywords ADD COLUMN cluster_id BIGINT;
+ALTER TABLE keywords ADD CONSTRAINT fk_keywords_cluster FOREIGN KEY (cluster_id) REFERENCES keyword_clusters (id) ON DELETE SET NULL;
+CREATE INDEX idx_keywords_cluster_id ON keywords (cluster_id);
@@ -0,0 +1 @@
+-- Начало ключа по отпечатку не восстановить, откат оставляет отпечатки как есть
@@ -0,0 +1,3 @@
+-- prefix хранил начало самого ключа. Заменяем его отпечатком - началом SHA-256 ключа, по которому ключ
+-- можно узнать в списке, не раскрывая его
+UPDATE api_keys SET prefix = left(key_hash, 12);
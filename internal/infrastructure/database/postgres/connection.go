@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"log"
 
-	"go-seo/internal/infrastructure/database/migrations"
-
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -24,29 +22,6 @@ type Database struct {
 	DB *gorm.DB
 }
 
-func NewDatabaseWithMigration(cfg Config) (*Database, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
-	)
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
-	}
-
-	if err := migrations.CreateTables(db); err != nil {
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
-	}
-
-	log.Println("Database connected and migrated successfully")
-
-	return &Database{DB: db}, nil
-}
-
 func NewDatabase(cfg Config) (*Database, error) {
 	dsn := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
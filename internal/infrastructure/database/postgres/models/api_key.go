@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type APIKey struct {
+	ID          int       `gorm:"primaryKey;autoIncrement"`
+	WorkspaceID *int      `gorm:"type:integer;index"` // NULL - системный ключ
+	Name        string    `gorm:"not null;type:varchar(100)"`
+	Prefix      string    `gorm:"not null;type:varchar(20)"`
+	KeyHash     string    `gorm:"not null;type:varchar(64);uniqueIndex"`
+	Scopes      string    `gorm:"not null;type:varchar(200)"` // Через запятую
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
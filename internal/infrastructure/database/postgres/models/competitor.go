@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type Competitor struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	SiteID    int       `gorm:"not null;uniqueIndex:idx_competitors_site_domain"`
+	Domain    string    `gorm:"not null;uniqueIndex:idx_competitors_site_domain"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Competitor) TableName() string {
+	return "competitors"
+}
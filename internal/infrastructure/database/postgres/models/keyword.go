@@ -3,12 +3,14 @@ package models
 import "time"
 
 type Keyword struct {
-	ID        int       `gorm:"primaryKey;autoIncrement"`
-	Value     string    `gorm:"not null"`
-	SiteID    int       `gorm:"not null;index"`
-	GroupID   *int      `gorm:"index"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID              int       `gorm:"primaryKey;autoIncrement"`
+	Value           string    `gorm:"not null"`
+	SiteID          int       `gorm:"not null;index"`
+	GroupID         *int      `gorm:"index"`
+	NormalizedValue *string   // NULL у дублей, оставшихся с миграции 0006 и еще не объединенных
+	ClusterID       *int      `gorm:"index"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
 
 	Site  Site  `gorm:"foreignKey:SiteID"`
 	Group Group `gorm:"foreignKey:GroupID"`
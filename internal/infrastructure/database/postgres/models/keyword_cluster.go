@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type KeywordCluster struct {
+	ID              int    `gorm:"primaryKey;autoIncrement"`
+	SiteID          int    `gorm:"not null;index"`
+	Source          string `gorm:"not null;type:varchar(20)"`
+	FilterGroupID   *int
+	Mode            string `gorm:"not null;type:varchar(10)"`
+	Threshold       int    `gorm:"not null"`
+	Name            string `gorm:"not null"`
+	MarkerKeywordID *int
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+}
+
+func (KeywordCluster) TableName() string {
+	return "keyword_clusters"
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type KeywordSuggestion struct {
+	ID              int    `gorm:"primaryKey;autoIncrement"`
+	SiteID          int    `gorm:"not null;index"`
+	Value           string `gorm:"not null"`
+	NormalizedValue string `gorm:"not null"`
+	Seed            string `gorm:"not null"`
+	Kind            string `gorm:"not null"`
+	Region          *int
+	Frequency       int       `gorm:"not null"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
+
+func (KeywordSuggestion) TableName() string {
+	return "keyword_suggestions"
+}
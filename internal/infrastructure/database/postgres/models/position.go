@@ -6,6 +6,7 @@ type Position struct {
 	ID                int       `gorm:"primaryKey;autoIncrement"`
 	KeywordID         int       `gorm:"not null;index"`
 	SiteID            int       `gorm:"not null;index"`
+	CompetitorID      *int      `gorm:"index"`
 	Rank              int       `gorm:"not null"`
 	URL               string    `gorm:"not null"`
 	Title             string    `gorm:"not null"`
@@ -29,3 +30,44 @@ type Position struct {
 func (Position) TableName() string {
 	return "positions"
 }
+
+// PositionRollup агрегат позиций за день или неделю; детальные строки периода удаляются заданием хранения
+type PositionRollup struct {
+	ID                int       `gorm:"primaryKey;autoIncrement"`
+	Period            string    `gorm:"not null;type:varchar(10)"`
+	PeriodStart       time.Time `gorm:"not null;type:date"`
+	PeriodEnd         time.Time `gorm:"not null;type:date"`
+	KeywordID         int       `gorm:"not null"`
+	SiteID            int       `gorm:"not null"`
+	CompetitorID      *int      `gorm:"index"`
+	Source            string    `gorm:""`
+	FilterGroupID     *int      `gorm:""`
+	WordstatQueryType string    `gorm:"not null;type:varchar(50);default:''"`
+	Rank              int       `gorm:"not null"`
+	URL               string    `gorm:"not null"`
+	Title             string    `gorm:"not null"`
+	Device            string    `gorm:"not null"`
+	OS                string    `gorm:""`
+	Ads               bool      `gorm:"not null"`
+	Country           string    `gorm:""`
+	Lang              string    `gorm:""`
+	Pages             int       `gorm:"not null"`
+	Date              time.Time `gorm:"not null"`
+	Samples           int       `gorm:"not null"`
+	VisibleSamples    int       `gorm:"not null"`
+	RankSum           int64     `gorm:"not null"`
+	BestRank          *int      `gorm:""`
+	WorstRank         *int      `gorm:""`
+	Range1_3          int       `gorm:"column:range_1_3;not null"`
+	Range4_10         int       `gorm:"column:range_4_10;not null"`
+	Range11_30        int       `gorm:"column:range_11_30;not null"`
+	Range31_50        int       `gorm:"column:range_31_50;not null"`
+	Range51_100       int       `gorm:"column:range_51_100;not null"`
+	Range100Plus      int       `gorm:"column:range_100_plus;not null"`
+
+	Keyword Keyword `gorm:"foreignKey:KeywordID"`
+}
+
+func (PositionRollup) TableName() string {
+	return "position_rollups"
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type ProviderAccount struct {
+	ID              int       `gorm:"primaryKey;autoIncrement"`
+	WorkspaceID     int       `gorm:"not null;uniqueIndex:idx_provider_accounts_workspace_name"`
+	Name            string    `gorm:"not null;type:varchar(100);uniqueIndex:idx_provider_accounts_workspace_name"`
+	Provider        string    `gorm:"not null;type:varchar(50)"`
+	UserID          string    `gorm:"not null;type:varchar(100)"`
+	EncryptedAPIKey string    `gorm:"not null;type:text"`
+	BaseURL         string    `gorm:"type:varchar(200)"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
+
+func (ProviderAccount) TableName() string {
+	return "provider_accounts"
+}
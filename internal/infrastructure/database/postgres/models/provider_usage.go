@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type ProviderUsage struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	JobID     string    `gorm:"type:varchar(50);index"`
+	SiteID    int       `gorm:"not null;index:idx_provider_usage_site_created,priority:1"`
+	Source    string    `gorm:"not null;type:varchar(20)"`
+	Provider  string    `gorm:"not null;type:varchar(50)"`
+	Account   string    `gorm:"type:varchar(100)"`
+	Requests  int       `gorm:"not null"`
+	Cost      float64   `gorm:"not null;type:numeric(12,4);default:0"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index:idx_provider_usage_site_created,priority:2"`
+}
+
+func (ProviderUsage) TableName() string {
+	return "provider_usage"
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// SerpSnapshot одна строка выдачи; строки одной проверки объединены position_id
+type SerpSnapshot struct {
+	ID          int       `gorm:"primaryKey;autoIncrement"`
+	PositionID  int       `gorm:"not null;index"`
+	KeywordID   int       `gorm:"not null;index"`
+	SiteID      int       `gorm:"not null;index"`
+	Source      string    `gorm:"not null;type:varchar(20)"`
+	Place       int       `gorm:"not null"`
+	Rank        int       `gorm:"not null;default:0"`
+	URL         string    `gorm:"type:text"`
+	Domain      string    `gorm:"type:varchar(255);index"`
+	Title       string    `gorm:"type:text"`
+	Passage     string    `gorm:"type:text"`
+	ContentType string    `gorm:"type:varchar(50)"`
+	Date        time.Time `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (SerpSnapshot) TableName() string {
+	return "serp_snapshots"
+}
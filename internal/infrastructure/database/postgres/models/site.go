@@ -3,10 +3,14 @@ package models
 import "time"
 
 type Site struct {
-	ID            int       `gorm:"primaryKey;autoIncrement"`
-	Domain        string    `gorm:"not null"`
-	YandexDynamic *int      `gorm:"type:smallint;default:null"`
-	GoogleDynamic *int      `gorm:"type:smallint;default:null"`
-	CreatedAt     time.Time `gorm:"autoCreateTime"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+	ID            int      `gorm:"primaryKey;autoIncrement"`
+	WorkspaceID   int      `gorm:"not null;index"`
+	Domain        string   `gorm:"not null"`
+	MonthlyBudget *float64 `gorm:"type:numeric(12,2);default:null"`
+	BudgetAction  string   `gorm:"type:varchar(10);not null;default:'refuse'"`
+	// Сводка изменения позиций (JSON), nil - сравнивать пока не с чем
+	YandexMovement *string   `gorm:"type:jsonb;default:null"`
+	GoogleMovement *string   `gorm:"type:jsonb;default:null"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
 }
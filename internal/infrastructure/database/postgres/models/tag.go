@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type Tag struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	SiteID    int       `gorm:"not null;index"`
+	Name      string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// KeywordTag связь ключевого слова с меткой
+type KeywordTag struct {
+	KeywordID int `gorm:"primaryKey;autoIncrement:false"`
+	TagID     int `gorm:"primaryKey;autoIncrement:false"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+func (KeywordTag) TableName() string {
+	return "keyword_tags"
+}
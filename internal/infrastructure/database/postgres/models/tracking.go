@@ -11,12 +11,18 @@ type TrackingJob struct {
 	Status         string    `gorm:"not null;type:varchar(20);index"`
 	CreatedAt      time.Time `gorm:"not null"`
 	UpdatedAt      time.Time `gorm:"not null"`
+	StartedAt      *time.Time
 	CompletedAt    *time.Time
 	TotalTasks     int    `gorm:"not null;default:0"`
 	CompletedTasks int    `gorm:"not null;default:0"`
 	FailedTasks    int    `gorm:"not null;default:0"`
 	FailedRequests int    `gorm:"not null;default:0"`
 	Error          string `gorm:"type:text"`
+	Params         string `gorm:"not null;type:jsonb;default:'{}'"`
+	APIKeyID       *int   `gorm:"type:integer;index"`
+	LockedBy       string `gorm:"type:varchar(100)"`
+	LockedUntil    *time.Time
+	EstimatedCost  float64 `gorm:"not null;default:0"`
 }
 
 func (TrackingJob) TableName() string {
@@ -36,6 +42,7 @@ type TrackingTask struct {
 	RetryCount        int    `gorm:"not null;default:0"`
 	MaxRetries        int    `gorm:"not null;default:5"`
 	Error             string `gorm:"type:text"`
+	Attempts          int    `gorm:"not null;default:0"`
 	Device            string `gorm:"type:varchar(20)"`
 	OS                string `gorm:"type:varchar(20)"`
 	Ads               bool   `gorm:"default:false"`
@@ -43,9 +50,6 @@ type TrackingTask struct {
 	Lang              string `gorm:"type:varchar(10)"`
 	Pages             int    `gorm:"default:0"`
 	Subdomains        bool   `gorm:"default:false"`
-	XMLUserID         string `gorm:"type:varchar(100)"`
-	XMLAPIKey         string `gorm:"type:varchar(100)"`
-	XMLBaseURL        string `gorm:"type:varchar(200)"`
 	TBS               string `gorm:"type:varchar(50)"`
 	Filter            int    `gorm:"default:0"`
 	Highlights        int    `gorm:"default:0"`
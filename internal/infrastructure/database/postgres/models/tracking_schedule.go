@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type TrackingSchedule struct {
+	ID        int    `gorm:"primaryKey;autoIncrement"`
+	SiteID    int    `gorm:"not null;index"`
+	Source    string `gorm:"not null;type:varchar(20)"`
+	Params    string `gorm:"not null;type:jsonb;default:'{}'"`
+	CronExpr  string `gorm:"not null;type:varchar(100)"`
+	Timezone  string `gorm:"not null;type:varchar(64);default:'UTC'"`
+	Enabled   bool   `gorm:"not null;default:true"`
+	LastRunAt *time.Time
+	NextRunAt *time.Time `gorm:"index"`
+	LastJobID string     `gorm:"type:varchar(50)"`
+	LastError string     `gorm:"type:text"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (TrackingSchedule) TableName() string {
+	return "tracking_schedules"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type SiteVisibility struct {
+	ID               int64     `gorm:"primaryKey;autoIncrement"`
+	SiteID           int       `gorm:"not null;uniqueIndex:idx_site_visibility_key,priority:1"`
+	Date             time.Time `gorm:"not null;type:date;uniqueIndex:idx_site_visibility_key,priority:2"`
+	Source           string    `gorm:"not null;type:varchar(20);uniqueIndex:idx_site_visibility_key,priority:3"`
+	GroupID          int       `gorm:"not null;default:0;uniqueIndex:idx_site_visibility_key,priority:4"` // 0 - все ключевые слова сайта
+	FilterGroupID    int       `gorm:"not null;default:0;uniqueIndex:idx_site_visibility_key,priority:5"` // 0 - позиции без группы фильтров
+	KeywordsCount    int       `gorm:"not null;default:0"`
+	FoundCount       int       `gorm:"not null;default:0"`
+	Top10Count       int       `gorm:"column:top10_count;not null;default:0"`
+	Frequency        int64     `gorm:"not null;default:0"`
+	EstimatedTraffic float64   `gorm:"not null;type:numeric(14,2);default:0"`
+	Visibility       float64   `gorm:"not null;type:numeric(6,2);default:0"`
+	CalculatedAt     time.Time `gorm:"not null"`
+}
+
+func (SiteVisibility) TableName() string {
+	return "site_visibility"
+}
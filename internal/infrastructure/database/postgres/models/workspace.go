@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type Workspace struct {
+	ID            int       `gorm:"primaryKey;autoIncrement"`
+	Name          string    `gorm:"not null;type:varchar(100);uniqueIndex"`
+	MonthlyBudget *float64  `gorm:"type:numeric(12,2);default:null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Workspace) TableName() string {
+	return "workspaces"
+}
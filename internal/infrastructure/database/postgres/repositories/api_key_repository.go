@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) repositories.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(key *entities.APIKey) error {
+	model := &models.APIKey{
+		WorkspaceID: key.WorkspaceID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		KeyHash:     key.KeyHash,
+		Scopes:      strings.Join(key.Scopes, ","),
+	}
+
+	if err := r.db.Create(model).Error; err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	key.ID = model.ID
+	key.CreatedAt = model.CreatedAt
+	return nil
+}
+
+func (r *apiKeyRepository) GetByID(id int) (*entities.APIKey, error) {
+	var model models.APIKey
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+func (r *apiKeyRepository) GetByHash(hash string) (*entities.APIKey, error) {
+	var model models.APIKey
+	if err := r.db.Where("key_hash = ?", hash).First(&model).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+func (r *apiKeyRepository) GetAll(workspaceID *int) ([]*entities.APIKey, error) {
+	query := r.db.Order("id")
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+
+	var models []models.APIKey
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	keys := make([]*entities.APIKey, len(models))
+	for i, model := range models {
+		keys[i] = r.toDomain(&model)
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(id int, at time.Time) (bool, error) {
+	result := r.db.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *apiKeyRepository) UpdateLastUsed(id int, at time.Time) error {
+	return r.db.Model(&models.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
+
+func (r *apiKeyRepository) toDomain(model *models.APIKey) *entities.APIKey {
+	var scopes []string
+	if model.Scopes != "" {
+		scopes = strings.Split(model.Scopes, ",")
+	}
+
+	return &entities.APIKey{
+		ID:          model.ID,
+		WorkspaceID: model.WorkspaceID,
+		Name:        model.Name,
+		Prefix:      model.Prefix,
+		KeyHash:     model.KeyHash,
+		Scopes:      scopes,
+		CreatedAt:   model.CreatedAt,
+		LastUsedAt:  model.LastUsedAt,
+		RevokedAt:   model.RevokedAt,
+	}
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+)
+
+type competitorRepository struct {
+	db *gorm.DB
+}
+
+func NewCompetitorRepository(db *gorm.DB) repositories.CompetitorRepository {
+	return &competitorRepository{db: db}
+}
+
+func (r *competitorRepository) Create(competitor *entities.Competitor) error {
+	model := &models.Competitor{
+		SiteID: competitor.SiteID,
+		Domain: competitor.Domain,
+	}
+
+	if err := r.db.Create(model).Error; err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	competitor.ID = model.ID
+	return nil
+}
+
+func (r *competitorRepository) GetByID(id int) (*entities.Competitor, error) {
+	var model models.Competitor
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+func (r *competitorRepository) GetBySiteID(siteID int) ([]*entities.Competitor, error) {
+	var models []models.Competitor
+	if err := r.db.Where("site_id = ?", siteID).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	competitors := make([]*entities.Competitor, len(models))
+	for i, model := range models {
+		competitors[i] = r.toDomain(&model)
+	}
+
+	return competitors, nil
+}
+
+func (r *competitorRepository) Update(competitor *entities.Competitor) error {
+	if err := r.db.Model(&models.Competitor{}).
+		Where("id = ?", competitor.ID).
+		Update("domain", competitor.Domain).Error; err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *competitorRepository) Delete(id int) error {
+	return r.db.Delete(&models.Competitor{}, id).Error
+}
+
+func (r *competitorRepository) DeleteBySiteID(siteID int) error {
+	return r.db.Where("site_id = ?", siteID).Delete(&models.Competitor{}).Error
+}
+
+func (r *competitorRepository) toDomain(model *models.Competitor) *entities.Competitor {
+	return &entities.Competitor{
+		ID:     model.ID,
+		SiteID: model.SiteID,
+		Domain: model.Domain,
+	}
+}
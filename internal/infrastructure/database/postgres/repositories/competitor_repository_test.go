@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/infrastructure/database"
+)
+
+func TestCompetitorRepository(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &competitorRepository{db: tx}
+	siteID, _, _, _ := seedVisibilitySite(t, tx)
+
+	var otherSiteID int
+	if err := tx.Raw("INSERT INTO sites (workspace_id, domain, created_at, updated_at) VALUES (1, 'other.example', NOW(), NOW()) RETURNING id").
+		Scan(&otherSiteID).Error; err != nil {
+		t.Fatalf("insert site: %v", err)
+	}
+
+	first := &entities.Competitor{SiteID: siteID, Domain: "rival.com"}
+	second := &entities.Competitor{SiteID: siteID, Domain: "another.com"}
+	// Тот же домен у другого сайта - отдельный конкурент
+	foreign := &entities.Competitor{SiteID: otherSiteID, Domain: "rival.com"}
+	for _, competitor := range []*entities.Competitor{first, second, foreign} {
+		if err := repo.Create(competitor); err != nil || competitor.ID == 0 {
+			t.Fatalf("create %s: %v", competitor.Domain, err)
+		}
+	}
+
+	// Нарушение уникального индекса прерывает транзакцию, поэтому каждая попытка идет в своей точке сохранения
+	duplicates := map[string]func() error{
+		"create": func() error { return repo.Create(&entities.Competitor{SiteID: siteID, Domain: "rival.com"}) },
+		"update": func() error {
+			return repo.Update(&entities.Competitor{ID: second.ID, SiteID: siteID, Domain: "rival.com"})
+		},
+	}
+	for name, run := range duplicates {
+		tx.SavePoint("duplicate")
+		err := run()
+		tx.RollbackTo("duplicate")
+		if !database.IsDatabaseError(err) || database.GetDatabaseErrorCode(err) != "DUPLICATE_ENTRY" {
+			t.Fatalf("%s: expected DUPLICATE_ENTRY, got %v", name, err)
+		}
+	}
+
+	second.Domain = "renamed.com"
+	if err := repo.Update(second); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if competitor, err := repo.GetByID(second.ID); err != nil || competitor.Domain != "renamed.com" || competitor.SiteID != siteID {
+		t.Fatalf("expected the renamed competitor, got %+v, %v", competitor, err)
+	}
+
+	competitors, err := repo.GetBySiteID(siteID)
+	if err != nil || len(competitors) != 2 || competitors[0].ID != first.ID || competitors[1].ID != second.ID {
+		t.Fatalf("expected competitors of the site in creation order, got %+v, %v", competitors, err)
+	}
+
+	if err := repo.Delete(first.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repo.GetByID(first.ID); err == nil {
+		t.Fatal("expected the deleted competitor to be gone")
+	}
+	if competitors, err := repo.GetBySiteID(otherSiteID); err != nil || len(competitors) != 1 || competitors[0].ID != foreign.ID {
+		t.Fatalf("competitor of another site must stay, got %+v, %v", competitors, err)
+	}
+}
+
+func TestCompetitorPositions(t *testing.T) {
+	tx := openTestDB(t)
+	competitorRepo := &competitorRepository{db: tx}
+	positionRepo := &positionRepository{db: tx}
+	siteID, _, grouped, ungrouped := seedVisibilitySite(t, tx)
+
+	competitor := &entities.Competitor{SiteID: siteID, Domain: "rival.com"}
+	if err := competitorRepo.Create(competitor); err != nil {
+		t.Fatalf("create competitor: %v", err)
+	}
+	competitorID := competitor.ID
+
+	// Позиции сайта и конкурента из одной выдачи хранятся отдельными строками и обновляются независимо
+	for _, rank := range []int{4, 6} {
+		for _, position := range []*entities.Position{
+			{KeywordID: grouped, SiteID: siteID, Rank: rank, Source: entities.GoogleSearch, Device: "desktop", Pages: 1, Date: time.Now()},
+			{KeywordID: grouped, SiteID: siteID, CompetitorID: &competitorID, Rank: rank + 1, Source: entities.GoogleSearch, Device: "desktop", Pages: 1, Date: time.Now()},
+		} {
+			if err := positionRepo.CreateOrUpdateToday(position); err != nil {
+				t.Fatalf("save position: %v", err)
+			}
+		}
+	}
+	for _, id := range []*int{nil, &competitorID} {
+		var count int64
+		if err := tx.Table("positions").Where("keyword_id = ? AND DATE(date) = CURRENT_DATE", grouped).
+			Scopes(byCompetitor(id)).Count(&count).Error; err != nil || count != 1 {
+			t.Fatalf("competitor %v: expected one position today, got %d, %v", id, count, err)
+		}
+	}
+	today, err := positionRepo.GetTodayByKeywordAndSiteAndSource(grouped, siteID, &competitorID, entities.GoogleSearch, "", nil)
+	if err != nil || today == nil || today.Rank != 7 {
+		t.Fatalf("expected the updated competitor position, got %+v, %v", today, err)
+	}
+
+	noon := func(day int) time.Time { return time.Date(2026, 9, day, 12, 0, 0, 0, time.UTC) }
+	for day, rank := range map[int]int{1: 5, 2: 3} {
+		insertVisibilityPosition(t, tx, grouped, siteID, nil, entities.GoogleSearch, rank, noon(day), "")
+		insertVisibilityPosition(t, tx, ungrouped, siteID, nil, entities.GoogleSearch, rank, noon(day), "")
+		insertVisibilityPosition(t, tx, grouped, siteID, &competitorID, entities.GoogleSearch, rank*10, noon(day), "")
+	}
+
+	from, to := noon(1), noon(2)
+	tests := []struct {
+		name         string
+		competitorID *int
+		keywords     []int
+		ranks        map[int]bool
+	}{
+		{"site", nil, []int{grouped, ungrouped}, map[int]bool{5: true, 3: true}},
+		{"competitor", &competitorID, []int{grouped}, map[int]bool{50: true, 30: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			combined, total, err := positionRepo.GetCombinedPositionsPaginated(siteID, tt.competitorID, nil, false, false, &from, &to, nil, "", nil, nil, nil, nil, nil, nil, 1, 20)
+			if err != nil || int(total) != len(tt.keywords) || len(combined) != len(tt.keywords) {
+				t.Fatalf("combined: expected keywords %v, got %d: %+v, %v", tt.keywords, total, combined, err)
+			}
+			for i, keywordID := range tt.keywords {
+				if combined[i].KeywordID != keywordID || len(combined[i].Positions) != 2 {
+					t.Fatalf("combined: unexpected keyword %+v", combined[i])
+				}
+				for _, position := range combined[i].Positions {
+					if !tt.ranks[position.Rank] {
+						t.Fatalf("combined: position %+v does not belong to %s", position, tt.name)
+					}
+				}
+			}
+
+			stats, err := positionRepo.GetPositionStatistics(siteID, tt.competitorID, entities.GoogleSearch, from, to, nil, nil)
+			if err != nil || stats.KeywordsCount != len(tt.keywords) || stats.TotalPositions != 2*len(tt.keywords) {
+				t.Fatalf("statistics: expected keywords %v, got %+v, %v", tt.keywords, stats, err)
+			}
+		})
+	}
+
+	// Удаление позиций конкурента не трогает позиции сайта
+	if err := positionRepo.DeleteByCompetitorID(competitorID); err != nil {
+		t.Fatalf("delete competitor positions: %v", err)
+	}
+	var left, own int64
+	tx.Table("positions").Where("competitor_id = ?", competitorID).Count(&left)
+	tx.Table("positions").Where("site_id = ? AND competitor_id IS NULL", siteID).Count(&own)
+	if left != 0 || own != 5 {
+		t.Fatalf("expected no competitor positions and 5 own positions, got %d and %d", left, own)
+	}
+}
@@ -10,10 +10,24 @@ type RepositoryContainer struct {
 	Keyword        repositories.KeywordRepository
 	Site           repositories.SiteRepository
 	Group          repositories.GroupRepository
+	Tag            repositories.TagRepository
+	Suggestion     repositories.KeywordSuggestionRepository
+	Cluster        repositories.KeywordClusterRepository
 	Position       repositories.PositionRepository
+	Retention      repositories.PositionRetentionRepository
+	Visibility     repositories.VisibilityRepository
+	Movement       repositories.MovementRepository
+	LandingPage    repositories.LandingPageRepository
 	TrackingJob    repositories.TrackingJobRepository
 	TrackingTask   repositories.TrackingTaskRepository
 	TrackingResult repositories.TrackingResultRepository
+	SerpSnapshot   repositories.SerpSnapshotRepository
+	Competitor     repositories.CompetitorRepository
+	Schedule       repositories.TrackingScheduleRepository
+	Usage          repositories.UsageRepository
+	Account        repositories.ProviderAccountRepository
+	APIKey         repositories.APIKeyRepository
+	Workspace      repositories.WorkspaceRepository
 }
 
 func NewRepositoryContainer(db *gorm.DB) *RepositoryContainer {
@@ -21,9 +35,23 @@ func NewRepositoryContainer(db *gorm.DB) *RepositoryContainer {
 		Keyword:        NewKeywordRepository(db),
 		Site:           NewSiteRepository(db),
 		Group:          NewGroupRepository(db),
+		Tag:            NewTagRepository(db),
+		Suggestion:     NewKeywordSuggestionRepository(db),
+		Cluster:        NewKeywordClusterRepository(db),
 		Position:       NewPositionRepository(db),
+		Retention:      NewPositionRetentionRepository(db),
+		Visibility:     NewVisibilityRepository(db),
+		Movement:       NewMovementRepository(db),
+		LandingPage:    NewLandingPageRepository(db),
 		TrackingJob:    NewTrackingJobRepository(db),
 		TrackingTask:   NewTrackingTaskRepository(db),
 		TrackingResult: NewTrackingResultRepository(db),
+		SerpSnapshot:   NewSerpSnapshotRepository(db),
+		Competitor:     NewCompetitorRepository(db),
+		Schedule:       NewTrackingScheduleRepository(db),
+		Usage:          NewUsageRepository(db),
+		Account:        NewProviderAccountRepository(db),
+		APIKey:         NewAPIKeyRepository(db),
+		Workspace:      NewWorkspaceRepository(db),
 	}
 }
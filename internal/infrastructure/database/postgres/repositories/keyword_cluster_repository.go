@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"fmt"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+)
+
+// keywordClusterLockKey первый ключ advisory lock кластеризации; второй - ID сайта
+const keywordClusterLockKey = 7240318
+
+// Последняя выдача слова берется из проверок сайта, без проверок конкурентов
+const topURLsQuery = `
+WITH latest AS (
+	SELECT DISTINCT ON (s.keyword_id) s.keyword_id, s.position_id
+	FROM serp_snapshots s
+	JOIN keywords k ON k.id = s.keyword_id AND k.site_id = @site
+	JOIN positions p ON p.id = s.position_id
+	WHERE s.site_id = @site AND s.source = @source AND p.competitor_id IS NULL AND %s
+	ORDER BY s.keyword_id, s.date DESC, s.position_id DESC
+)
+SELECT s.keyword_id, s.url
+FROM serp_snapshots s
+JOIN latest l ON l.position_id = s.position_id AND l.keyword_id = s.keyword_id
+WHERE s.rank BETWEEN 1 AND @depth AND COALESCE(s.url, '') <> ''
+ORDER BY s.keyword_id, s.rank, s.place
+`
+
+type keywordClusterRepository struct {
+	db *gorm.DB
+}
+
+func NewKeywordClusterRepository(db *gorm.DB) repositories.KeywordClusterRepository {
+	return &keywordClusterRepository{db: db}
+}
+
+func (r *keywordClusterRepository) GetTopURLs(siteID int, source string, filterGroupID *int, depth int) ([]*entities.KeywordSerp, error) {
+	condition := "TRUE"
+	if filterGroupID != nil {
+		condition = "p.filter_group_id = @filter_group"
+	}
+
+	var rows []struct {
+		KeywordID int
+		URL       string
+	}
+	if err := r.db.Raw(fmt.Sprintf(topURLsQuery, condition), map[string]interface{}{
+		"site":         siteID,
+		"source":       source,
+		"filter_group": intOrZero(filterGroupID),
+		"depth":        depth,
+	}).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var serps []*entities.KeywordSerp
+	for _, row := range rows {
+		if len(serps) == 0 || serps[len(serps)-1].KeywordID != row.KeywordID {
+			serps = append(serps, &entities.KeywordSerp{KeywordID: row.KeywordID})
+		}
+		last := serps[len(serps)-1]
+		last.URLs = append(last.URLs, row.URL)
+	}
+	return serps, nil
+}
+
+func (r *keywordClusterRepository) Replace(siteID int, clusters []*entities.KeywordCluster) error {
+	records := make([]*models.KeywordCluster, len(clusters))
+	for i, cluster := range clusters {
+		records[i] = &models.KeywordCluster{
+			SiteID:          siteID,
+			Source:          cluster.Source,
+			FilterGroupID:   cluster.FilterGroupID,
+			Mode:            cluster.Mode,
+			Threshold:       cluster.Threshold,
+			Name:            cluster.Name,
+			MarkerKeywordID: cluster.MarkerKeywordID,
+		}
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Параллельные запуски одного сайта перезаписали бы cluster_id слов вперемешку
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", keywordClusterLockKey, siteID).Error; err != nil {
+			return err
+		}
+
+		// cluster_id слов обнуляется внешним ключом
+		if err := tx.Where("site_id = ?", siteID).Delete(&models.KeywordCluster{}).Error; err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(records, 1000).Error; err != nil {
+			return err
+		}
+
+		for i, cluster := range clusters {
+			if err := tx.Model(&models.Keyword{}).
+				Where("site_id = ? AND id IN ?", siteID, cluster.KeywordIDs).
+				Update("cluster_id", records[i].ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	for i, record := range records {
+		clusters[i].ID = record.ID
+		clusters[i].SiteID = siteID
+		clusters[i].CreatedAt = record.CreatedAt
+	}
+	return nil
+}
+
+func (r *keywordClusterRepository) GetBySite(siteID int, offset, limit int) ([]*entities.KeywordCluster, int64, error) {
+	var total int64
+	if err := r.db.Model(&models.KeywordCluster{}).Where("site_id = ?", siteID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var records []models.KeywordCluster
+	if err := r.db.
+		Where("site_id = ?", siteID).
+		Order("(SELECT COUNT(*) FROM keywords k WHERE k.cluster_id = keyword_clusters.id) DESC, id").
+		Offset(offset).Limit(limit).
+		Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+
+	clusters, err := r.withKeywords(records)
+	if err != nil {
+		return nil, 0, err
+	}
+	return clusters, total, nil
+}
+
+func (r *keywordClusterRepository) GetByIDs(ids []int) ([]*entities.KeywordCluster, error) {
+	if len(ids) == 0 {
+		return []*entities.KeywordCluster{}, nil
+	}
+
+	var records []models.KeywordCluster
+	if err := r.db.Where("id IN ?", ids).Order("id").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return r.withKeywords(records)
+}
+
+func (r *keywordClusterRepository) Apply(siteID int, clusterIDs []int) (*entities.KeywordClusterApplyResult, error) {
+	condition := "TRUE"
+	if clusterIDs != nil {
+		condition = "c.id IN @clusters"
+	}
+	params := map[string]interface{}{"site": siteID, "clusters": clusterIDs}
+	result := &entities.KeywordClusterApplyResult{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Группы сайта создаются под той же блокировкой, что и при импорте ключевых слов
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", keywordImportLockKey, siteID).Error; err != nil {
+			return err
+		}
+
+		created := tx.Exec(`
+			INSERT INTO groups (name, site_id, created_at, updated_at)
+			SELECT DISTINCT ON (lower(c.name)) c.name, @site, now(), now()
+			FROM keyword_clusters c
+			WHERE c.site_id = @site AND `+condition+`
+			  AND EXISTS (SELECT 1 FROM keywords k WHERE k.cluster_id = c.id)
+			  AND NOT EXISTS (SELECT 1 FROM groups g WHERE g.site_id = @site AND lower(g.name) = lower(c.name))
+			ORDER BY lower(c.name), c.id
+		`, params)
+		if created.Error != nil {
+			return created.Error
+		}
+		result.GroupsCreated = int(created.RowsAffected)
+
+		moved := tx.Exec(`
+			UPDATE keywords k
+			SET group_id = g.id, updated_at = now()
+			FROM keyword_clusters c
+			JOIN `+siteGroupsQuery+` g ON g.name_key = lower(c.name)
+			WHERE k.cluster_id = c.id AND c.site_id = @site AND `+condition+`
+			  AND k.group_id IS DISTINCT FROM g.id
+		`, params)
+		if moved.Error != nil {
+			return moved.Error
+		}
+		result.KeywordsMoved = int(moved.RowsAffected)
+
+		return nil
+	})
+	if err != nil {
+		return nil, database.WrapDatabaseError(err)
+	}
+
+	return result, nil
+}
+
+// withKeywords переводит записи в сущности и загружает слова кластеров по возрастанию ID
+func (r *keywordClusterRepository) withKeywords(records []models.KeywordCluster) ([]*entities.KeywordCluster, error) {
+	clusters := make([]*entities.KeywordCluster, len(records))
+	byID := make(map[int]*entities.KeywordCluster, len(records))
+	ids := make([]int, len(records))
+	for i, record := range records {
+		clusters[i] = &entities.KeywordCluster{
+			ID:              record.ID,
+			SiteID:          record.SiteID,
+			Source:          record.Source,
+			FilterGroupID:   record.FilterGroupID,
+			Mode:            record.Mode,
+			Threshold:       record.Threshold,
+			Name:            record.Name,
+			MarkerKeywordID: record.MarkerKeywordID,
+			CreatedAt:       record.CreatedAt,
+		}
+		byID[record.ID] = clusters[i]
+		ids[i] = record.ID
+	}
+	if len(ids) == 0 {
+		return clusters, nil
+	}
+
+	var keywords []models.Keyword
+	if err := r.db.Where("cluster_id IN ?", ids).Order("id").Find(&keywords).Error; err != nil {
+		return nil, err
+	}
+	for _, keyword := range keywords {
+		cluster := byID[*keyword.ClusterID]
+		cluster.KeywordIDs = append(cluster.KeywordIDs, keyword.ID)
+		cluster.Keywords = append(cluster.Keywords, &entities.Keyword{
+			ID:              keyword.ID,
+			Value:           keyword.Value,
+			SiteID:          keyword.SiteID,
+			GroupID:         keyword.GroupID,
+			NormalizedValue: keyword.NormalizedValue,
+			ClusterID:       keyword.ClusterID,
+		})
+	}
+	return clusters, nil
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"strings"
+
 	"go-seo/internal/domain/entities"
 	"go-seo/internal/domain/repositories"
 	"go-seo/internal/infrastructure/database"
@@ -19,9 +21,10 @@ func NewKeywordRepository(db *gorm.DB) repositories.KeywordRepository {
 
 func (r *keywordRepository) Create(keyword *entities.Keyword) error {
 	model := &models.Keyword{
-		Value:   keyword.Value,
-		SiteID:  keyword.SiteID,
-		GroupID: keyword.GroupID,
+		Value:           keyword.Value,
+		SiteID:          keyword.SiteID,
+		GroupID:         keyword.GroupID,
+		NormalizedValue: keyword.NormalizedValue,
 	}
 
 	if err := r.db.Create(model).Error; err != nil {
@@ -40,9 +43,10 @@ func (r *keywordRepository) CreateBatch(keywords []*entities.Keyword) error {
 	keywordModels := make([]*models.Keyword, len(keywords))
 	for i, keyword := range keywords {
 		keywordModels[i] = &models.Keyword{
-			Value:   keyword.Value,
-			SiteID:  keyword.SiteID,
-			GroupID: keyword.GroupID,
+			Value:           keyword.Value,
+			SiteID:          keyword.SiteID,
+			GroupID:         keyword.GroupID,
+			NormalizedValue: keyword.NormalizedValue,
 		}
 	}
 
@@ -66,6 +70,20 @@ func (r *keywordRepository) GetByID(id int) (*entities.Keyword, error) {
 	return r.toDomain(&model), nil
 }
 
+func (r *keywordRepository) GetByIDInWorkspace(id int, workspaceID *int) (*entities.Keyword, error) {
+	query := r.db.Where("id = ?", id)
+	if workspaceID != nil {
+		query = query.Where("site_id IN (SELECT id FROM sites WHERE workspace_id = ?)", *workspaceID)
+	}
+
+	var model models.Keyword
+	if err := query.First(&model).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
 func (r *keywordRepository) GetByIDs(ids []int) ([]*entities.Keyword, error) {
 	if len(ids) == 0 {
 		return []*entities.Keyword{}, nil
@@ -84,9 +102,9 @@ func (r *keywordRepository) GetByIDs(ids []int) ([]*entities.Keyword, error) {
 	return keywords, nil
 }
 
-func (r *keywordRepository) GetByValueAndSite(value string, siteID int) (*entities.Keyword, error) {
+func (r *keywordRepository) GetByNormalizedValue(key string, siteID int) (*entities.Keyword, error) {
 	var model models.Keyword
-	if err := r.db.Where("value = ? AND site_id = ?", value, siteID).First(&model).Error; err != nil {
+	if err := r.db.Where("normalized_value = ? AND site_id = ?", key, siteID).First(&model).Error; err != nil {
 		return nil, err
 	}
 
@@ -107,6 +125,20 @@ func (r *keywordRepository) GetBySiteID(siteID int) ([]*entities.Keyword, error)
 	return keywords, nil
 }
 
+func (r *keywordRepository) GetBySiteIDAndTags(siteID int, tags *entities.TagFilter) ([]*entities.Keyword, error) {
+	var models []models.Keyword
+	if err := r.db.Where("site_id = ?", siteID).Where(keywordTagsCondition("id", tags)).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	keywords := make([]*entities.Keyword, len(models))
+	for i, model := range models {
+		keywords[i] = r.toDomain(&model)
+	}
+
+	return keywords, nil
+}
+
 func (r *keywordRepository) GetAll() ([]*entities.Keyword, error) {
 	var modelKeywords []models.Keyword
 	if err := r.db.Find(&modelKeywords).Error; err != nil {
@@ -123,10 +155,12 @@ func (r *keywordRepository) GetAll() ([]*entities.Keyword, error) {
 
 func (r *keywordRepository) Update(keyword *entities.Keyword) error {
 	model := &models.Keyword{
-		ID:      keyword.ID,
-		Value:   keyword.Value,
-		SiteID:  keyword.SiteID,
-		GroupID: keyword.GroupID,
+		ID:              keyword.ID,
+		Value:           keyword.Value,
+		SiteID:          keyword.SiteID,
+		GroupID:         keyword.GroupID,
+		NormalizedValue: keyword.NormalizedValue,
+		ClusterID:       keyword.ClusterID,
 	}
 
 	return r.db.Save(model).Error
@@ -148,11 +182,266 @@ func (r *keywordRepository) CountBySiteID(siteID int) (int, error) {
 	return int(count), nil
 }
 
+// keywordImportLockKey первый ключ advisory lock импорта; второй - ID сайта
+const keywordImportLockKey = 7240317
+
+// keywordImportChunk строк в одном INSERT во временную таблицу: 4 параметра на строку, лимит PostgreSQL - 65535
+const keywordImportChunk = 5000
+
+// siteGroupsQuery группа сайта по имени без учета регистра; из одноименных групп берется самая старая
+const siteGroupsQuery = `(SELECT DISTINCT ON (lower(name)) id, lower(name) AS name_key FROM groups WHERE site_id = @site ORDER BY lower(name), id)`
+
+func (r *keywordRepository) GetExistingKeys(siteID int, keys []string) ([]string, error) {
+	var existing []string
+	for start := 0; start < len(keys); start += keywordImportChunk {
+		end := start + keywordImportChunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		var chunk []string
+		if err := r.db.Model(&models.Keyword{}).
+			Where("site_id = ? AND normalized_value IN ?", siteID, keys[start:end]).
+			Pluck("normalized_value", &chunk).Error; err != nil {
+			return nil, err
+		}
+		existing = append(existing, chunk...)
+	}
+	return existing, nil
+}
+
+func (r *keywordRepository) Import(siteID int, rows []*entities.KeywordImportRow) (*entities.KeywordImportStats, error) {
+	stats := &entities.KeywordImportStats{}
+	params := map[string]interface{}{"site": siteID}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Импорты одного сайта идут по очереди, иначе параллельные импорты создали бы одинаковые слова и группы
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", keywordImportLockKey, siteID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			CREATE TEMP TABLE keyword_import (row_no INT NOT NULL, value TEXT NOT NULL, key TEXT NOT NULL, group_name TEXT NOT NULL) ON COMMIT DROP;
+			CREATE TEMP TABLE keyword_import_tags (key TEXT NOT NULL, tag TEXT NOT NULL) ON COMMIT DROP
+		`).Error; err != nil {
+			return err
+		}
+		if err := r.loadImportRows(tx, rows); err != nil {
+			return err
+		}
+		if err := tx.Exec("ANALYZE keyword_import; ANALYZE keyword_import_tags").Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
+			INSERT INTO groups (name, site_id, created_at, updated_at)
+			SELECT DISTINCT ON (lower(i.group_name)) i.group_name, @site, now(), now()
+			FROM keyword_import i
+			WHERE i.group_name <> ''
+			  AND NOT EXISTS (SELECT 1 FROM groups g WHERE g.site_id = @site AND lower(g.name) = lower(i.group_name))
+			ORDER BY lower(i.group_name), i.row_no
+		`, params)
+		if result.Error != nil {
+			return result.Error
+		}
+		stats.GroupsCreated = int(result.RowsAffected)
+
+		result = tx.Exec(`
+			INSERT INTO keywords (value, normalized_value, site_id, group_id, created_at, updated_at)
+			SELECT i.value, i.key, @site, g.id, now(), now()
+			FROM keyword_import i
+			LEFT JOIN `+siteGroupsQuery+` g ON g.name_key = lower(i.group_name)
+			ORDER BY i.row_no
+			ON CONFLICT (site_id, normalized_value) DO NOTHING
+		`, params)
+		if result.Error != nil {
+			return result.Error
+		}
+		stats.Created = int(result.RowsAffected)
+
+		result = tx.Exec(`
+			UPDATE keywords k
+			SET group_id = g.id, updated_at = now()
+			FROM keyword_import i
+			JOIN `+siteGroupsQuery+` g ON g.name_key = lower(i.group_name)
+			WHERE k.site_id = @site AND k.normalized_value = i.key AND i.group_name <> ''
+			  AND k.group_id IS DISTINCT FROM g.id
+		`, params)
+		if result.Error != nil {
+			return result.Error
+		}
+		stats.Updated = int(result.RowsAffected)
+
+		result = tx.Exec(`
+			INSERT INTO tags (site_id, name, created_at, updated_at)
+			SELECT DISTINCT ON (lower(t.tag)) @site, t.tag, now(), now()
+			FROM keyword_import_tags t
+			ORDER BY lower(t.tag)
+			ON CONFLICT (site_id, lower(name)) DO NOTHING
+		`, params)
+		if result.Error != nil {
+			return result.Error
+		}
+		stats.TagsCreated = int(result.RowsAffected)
+
+		result = tx.Exec(`
+			INSERT INTO keyword_tags (keyword_id, tag_id)
+			SELECT DISTINCT k.id, tg.id
+			FROM keyword_import_tags t
+			JOIN keywords k ON k.site_id = @site AND k.normalized_value = t.key
+			JOIN tags tg ON tg.site_id = @site AND lower(tg.name) = lower(t.tag)
+			ON CONFLICT DO NOTHING
+		`, params)
+		if result.Error != nil {
+			return result.Error
+		}
+		stats.TagsAssigned = int(result.RowsAffected)
+
+		return nil
+	})
+	if err != nil {
+		return nil, database.WrapDatabaseError(err)
+	}
+
+	return stats, nil
+}
+
+// Если за один день (период агрегата) по одним настройкам проверки есть позиции нескольких объединяемых слов,
+// остаются позиции target, а без них - слова с меньшим ID: это один и тот же запрос, и двойные проверки
+// дня исказили бы отчеты. Снимки выдачи удаленных позиций удаляются вместе с ними. Настройки проверки
+// позиции включают устройство, ОС, рекламу, страну и язык; агрегаты сравниваются по ключу idx_position_rollups_key
+const mergePositionsQuery = `
+WITH ranked AS (
+	SELECT id, date, DENSE_RANK() OVER (
+		PARTITION BY DATE(date), COALESCE(competitor_id, 0), COALESCE(source, ''), COALESCE(filter_group_id, 0), COALESCE(wordstat_query_type, ''),
+			device, COALESCE(os, ''), ads, COALESCE(country, ''), COALESCE(lang, '')
+		ORDER BY keyword_id <> @target, keyword_id
+	) AS keyword_rank
+	FROM positions
+	WHERE keyword_id IN @keywords
+),
+deleted AS (
+	DELETE FROM positions p
+	USING ranked r
+	WHERE p.id = r.id AND p.date = r.date AND r.keyword_rank > 1
+	RETURNING p.id
+)
+DELETE FROM serp_snapshots WHERE position_id IN (SELECT id FROM deleted)
+`
+
+const mergeRollupsQuery = `
+DELETE FROM position_rollups r
+USING (
+	SELECT id, DENSE_RANK() OVER (
+		PARTITION BY period, period_start, COALESCE(competitor_id, 0), COALESCE(source, ''), COALESCE(filter_group_id, 0), wordstat_query_type
+		ORDER BY keyword_id <> @target, keyword_id
+	) AS keyword_rank
+	FROM position_rollups
+	WHERE keyword_id IN @keywords
+) ranked
+WHERE r.id = ranked.id AND ranked.keyword_rank > 1
+`
+
+func (r *keywordRepository) Merge(target *entities.Keyword, sourceIDs []int) error {
+	params := map[string]interface{}{
+		"site":     target.SiteID,
+		"target":   target.ID,
+		"sources":  sourceIDs,
+		"keywords": append([]int{target.ID}, sourceIDs...),
+		"key":      stringOrEmpty(target.NormalizedValue),
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Объединение и импорт одного сайта не идут параллельно
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", keywordImportLockKey, target.SiteID).Error; err != nil {
+			return err
+		}
+
+		statements := []string{
+			mergePositionsQuery,
+			mergeRollupsQuery,
+			"UPDATE positions SET keyword_id = @target WHERE keyword_id IN @sources",
+			"UPDATE position_rollups SET keyword_id = @target WHERE keyword_id IN @sources",
+			"UPDATE serp_snapshots SET keyword_id = @target WHERE keyword_id IN @sources",
+			"UPDATE tracking_tasks SET keyword_id = @target WHERE keyword_id IN @sources",
+			"UPDATE tracking_results SET keyword_id = @target WHERE keyword_id IN @sources",
+			`INSERT INTO keyword_tags (keyword_id, tag_id)
+			SELECT DISTINCT @target, tag_id FROM keyword_tags WHERE keyword_id IN @sources
+			ON CONFLICT DO NOTHING`,
+			"DELETE FROM keywords WHERE id IN @sources AND site_id = @site",
+		}
+		if target.NormalizedValue != nil {
+			// Ключ мог быть у одного из удаленных слов
+			statements = append(statements, `
+			UPDATE keywords SET normalized_value = @key, updated_at = now()
+			WHERE id = @target AND normalized_value IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM keywords WHERE site_id = @site AND normalized_value = @key)`)
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement, params).Error; err != nil {
+				return err
+			}
+		}
+
+		var model models.Keyword
+		if err := tx.First(&model, target.ID).Error; err != nil {
+			return err
+		}
+		*target = *r.toDomain(&model)
+		return nil
+	})
+	if err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	return nil
+}
+
+// loadImportRows заполняет временные таблицы импорта многострочными INSERT
+func (r *keywordRepository) loadImportRows(tx *gorm.DB, rows []*entities.KeywordImportRow) error {
+	for start := 0; start < len(rows); start += keywordImportChunk {
+		end := start + keywordImportChunk
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		placeholders := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*4)
+		var tagPlaceholders []string
+		var tagArgs []interface{}
+		for _, row := range rows[start:end] {
+			placeholders = append(placeholders, "(?, ?, ?, ?)")
+			args = append(args, row.Row, row.Value, row.Key, row.GroupName)
+			for _, tag := range row.Tags {
+				tagPlaceholders = append(tagPlaceholders, "(?, ?)")
+				tagArgs = append(tagArgs, row.Key, tag)
+			}
+		}
+
+		if err := tx.Exec("INSERT INTO keyword_import (row_no, value, key, group_name) VALUES "+strings.Join(placeholders, ", "), args...).Error; err != nil {
+			return err
+		}
+		// Меток у строки может быть много, поэтому они вставляются своими пачками
+		for tagStart := 0; tagStart < len(tagPlaceholders); tagStart += keywordImportChunk {
+			tagEnd := tagStart + keywordImportChunk
+			if tagEnd > len(tagPlaceholders) {
+				tagEnd = len(tagPlaceholders)
+			}
+			if err := tx.Exec("INSERT INTO keyword_import_tags (key, tag) VALUES "+strings.Join(tagPlaceholders[tagStart:tagEnd], ", "), tagArgs[tagStart*2:tagEnd*2]...).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (r *keywordRepository) toDomain(model *models.Keyword) *entities.Keyword {
 	return &entities.Keyword{
-		ID:      model.ID,
-		Value:   model.Value,
-		SiteID:  model.SiteID,
-		GroupID: model.GroupID,
+		ID:              model.ID,
+		Value:           model.Value,
+		SiteID:          model.SiteID,
+		GroupID:         model.GroupID,
+		NormalizedValue: model.NormalizedValue,
+		ClusterID:       model.ClusterID,
 	}
 }
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+func insertMergePosition(tb testing.TB, tx *gorm.DB, keywordID, siteID int, device string, rank int, date time.Time) int {
+	tb.Helper()
+
+	var id int
+	if err := tx.Raw(`
+		INSERT INTO positions (keyword_id, site_id, rank, url, title, source, device, ads, pages, date,
+			wordstat_query_type, created_at, updated_at)
+		VALUES (?, ?, ?, '', '', 'google', ?, false, 1, ?, '', NOW(), NOW())
+		RETURNING id`,
+		keywordID, siteID, rank, device, date).Scan(&id).Error; err != nil {
+		tb.Fatalf("insert position: %v", err)
+	}
+	if err := tx.Exec(`
+		INSERT INTO serp_snapshots (position_id, keyword_id, site_id, source, place, rank, url, domain, title, date, created_at)
+		VALUES (?, ?, ?, 'google', 1, 1, '', '', '', ?, NOW())`, id, keywordID, siteID, date).Error; err != nil {
+		tb.Fatalf("insert snapshot: %v", err)
+	}
+	return id
+}
+
+// Проверки одного дня с разными устройствами - разные проверки: при объединении дублем считается
+// только позиция с теми же настройками
+func TestKeywordMergeKeepsOtherDeviceChecks(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &keywordRepository{db: tx}
+	siteID, _, target, source := seedVisibilitySite(t, tx)
+
+	date := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	kept := insertMergePosition(t, tx, target, siteID, "desktop", 3, date)
+	duplicate := insertMergePosition(t, tx, source, siteID, "desktop", 5, date)
+	mobile := insertMergePosition(t, tx, source, siteID, "mobile", 7, date)
+
+	if err := repo.Merge(&entities.Keyword{ID: target, SiteID: siteID}, []int{source}); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	var positions []struct {
+		ID        int
+		KeywordID int
+		Device    string
+	}
+	if err := tx.Raw("SELECT id, keyword_id, device FROM positions WHERE site_id = ? ORDER BY id", siteID).Scan(&positions).Error; err != nil {
+		t.Fatalf("load positions: %v", err)
+	}
+	if len(positions) != 2 || positions[0].ID != kept || positions[1].ID != mobile {
+		t.Fatalf("expected desktop position of target and mobile position of source, got %+v", positions)
+	}
+	for _, position := range positions {
+		if position.KeywordID != target {
+			t.Fatalf("position %d was not moved to the target keyword", position.ID)
+		}
+	}
+
+	var snapshots []int
+	if err := tx.Raw("SELECT position_id FROM serp_snapshots WHERE site_id = ? AND keyword_id = ? ORDER BY position_id", siteID, target).
+		Scan(&snapshots).Error; err != nil {
+		t.Fatalf("load snapshots: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0] != kept || snapshots[1] != mobile {
+		t.Fatalf("expected snapshots of the kept positions, got %v (duplicate %d)", snapshots, duplicate)
+	}
+
+	var sources int64
+	if err := tx.Raw("SELECT COUNT(*) FROM keywords WHERE id = ?", source).Scan(&sources).Error; err != nil || sources != 0 {
+		t.Fatalf("expected the source keyword to be deleted, got %d, %v", sources, err)
+	}
+}
+
+// Ключ из миграции 0006 совпадает с KeywordNormalizer.Normalize и для кириллицы
+func TestKeywordNormalizationBackfill(t *testing.T) {
+	tx := openTestDB(t)
+
+	var key string
+	if err := tx.Raw(`SELECT translate(lower(btrim(regexp_replace(?, '[[:space:]]+', ' ', 'g')) COLLATE "und-x-icu"), 'ё', 'е')`,
+		"  Купить   ЁЛКУ Москва ").Scan(&key).Error; err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if key != "купить елку москва" {
+		t.Fatalf("expected %q, got %q", "купить елку москва", key)
+	}
+}
+
+func TestKeywordImport(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &keywordRepository{db: tx}
+
+	var siteID, chairsID, existingID int
+	steps := []func() error{
+		func() error {
+			return tx.Raw("INSERT INTO sites (workspace_id, domain, created_at, updated_at) VALUES (1, 'import.example', NOW(), NOW()) RETURNING id").
+				Scan(&siteID).Error
+		},
+		func() error {
+			return tx.Raw("INSERT INTO groups (name, site_id, created_at, updated_at) VALUES ('Кресла', ?, NOW(), NOW()) RETURNING id", siteID).
+				Scan(&chairsID).Error
+		},
+		func() error {
+			return tx.Raw("INSERT INTO keywords (value, normalized_value, site_id, created_at, updated_at) VALUES ('кресло', 'кресло', ?, NOW(), NOW()) RETURNING id", siteID).
+				Scan(&existingID).Error
+		},
+		func() error {
+			return tx.Exec("INSERT INTO tags (site_id, name, created_at, updated_at) VALUES (?, 'мебель', NOW(), NOW())", siteID).Error
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	rows := []*entities.KeywordImportRow{
+		{Row: 2, Value: "купить диван", Key: "купить диван", GroupName: "Диваны", Tags: []string{"коммерция", "мебель"}, Status: entities.ImportRowNew},
+		{Row: 4, Value: "Диван угловой", Key: "диван угловой", GroupName: "диваны", Tags: []string{"Мебель"}, Status: entities.ImportRowNew},
+		// Существующее слово переносится в группу из файла, регистр имени группы не важен
+		{Row: 8, Value: "кресло", Key: "кресло", GroupName: "кресла", Status: entities.ImportRowDuplicate},
+	}
+	stats, err := repo.Import(siteID, rows)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	want := entities.KeywordImportStats{Created: 2, Updated: 1, GroupsCreated: 1, TagsCreated: 1, TagsAssigned: 3}
+	if *stats != want {
+		t.Fatalf("expected %+v, got %+v", want, *stats)
+	}
+
+	var keywords []struct {
+		Value     string
+		GroupName string
+		Tags      string
+	}
+	if err := tx.Raw(`
+		SELECT k.value, g.name AS group_name, COALESCE(string_agg(t.name, ',' ORDER BY t.name), '') AS tags
+		FROM keywords k
+		JOIN groups g ON g.id = k.group_id
+		LEFT JOIN keyword_tags kt ON kt.keyword_id = k.id
+		LEFT JOIN tags t ON t.id = kt.tag_id
+		WHERE k.site_id = ?
+		GROUP BY k.id, k.value, g.name
+		ORDER BY k.id`, siteID).Scan(&keywords).Error; err != nil {
+		t.Fatalf("load keywords: %v", err)
+	}
+	expected := []struct {
+		Value     string
+		GroupName string
+		Tags      string
+	}{
+		{"кресло", "Кресла", ""},
+		{"купить диван", "Диваны", "коммерция,мебель"},
+		{"Диван угловой", "Диваны", "мебель"},
+	}
+	if len(keywords) != len(expected) {
+		t.Fatalf("expected %+v, got %+v", expected, keywords)
+	}
+	for i := range expected {
+		if keywords[i] != expected[i] {
+			t.Fatalf("keyword %d: expected %+v, got %+v", i, expected[i], keywords[i])
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+)
+
+type keywordSuggestionRepository struct {
+	db *gorm.DB
+}
+
+func NewKeywordSuggestionRepository(db *gorm.DB) repositories.KeywordSuggestionRepository {
+	return &keywordSuggestionRepository{db: db}
+}
+
+// keywordSuggestionBatchSize строк в одном INSERT: 7 параметров на строку при лимите PostgreSQL в 65535
+const keywordSuggestionBatchSize = 1000
+
+func (r *keywordSuggestionRepository) Save(suggestions []*entities.KeywordSuggestion) error {
+	type suggestionKey struct {
+		key    string
+		region int
+	}
+	byKey := make(map[suggestionKey]*entities.KeywordSuggestion, len(suggestions))
+	for _, suggestion := range suggestions {
+		byKey[suggestionKey{suggestion.NormalizedValue, intOrZero(suggestion.Region)}] = suggestion
+	}
+
+	// Уникальный индекс построен по выражению COALESCE(region, 0), поэтому ON CONFLICT задается текстом
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(suggestions); start += keywordSuggestionBatchSize {
+			end := start + keywordSuggestionBatchSize
+			if end > len(suggestions) {
+				end = len(suggestions)
+			}
+
+			values := make([]string, 0, end-start)
+			args := make([]interface{}, 0, (end-start)*7)
+			for _, suggestion := range suggestions[start:end] {
+				values = append(values, "(?, ?, ?, ?, ?, CAST(? AS INTEGER), ?, now(), now())")
+				args = append(args, suggestion.SiteID, suggestion.Value, suggestion.NormalizedValue, suggestion.Seed,
+					suggestion.Kind, suggestion.Region, suggestion.Frequency)
+			}
+
+			var saved []struct {
+				ID              int
+				NormalizedValue string
+				RegionKey       int
+				CreatedAt       time.Time
+				UpdatedAt       time.Time
+			}
+			if err := tx.Raw(`
+				INSERT INTO keyword_suggestions (site_id, value, normalized_value, seed, kind, region, frequency, created_at, updated_at)
+				VALUES `+strings.Join(values, ", ")+`
+				ON CONFLICT (site_id, normalized_value, COALESCE(region, 0)) DO UPDATE
+				SET value = EXCLUDED.value, seed = EXCLUDED.seed, kind = EXCLUDED.kind,
+					frequency = EXCLUDED.frequency, updated_at = now()
+				RETURNING id, normalized_value, COALESCE(region, 0) AS region_key, created_at, updated_at
+			`, args...).Scan(&saved).Error; err != nil {
+				return err
+			}
+
+			for _, row := range saved {
+				if suggestion, ok := byKey[suggestionKey{row.NormalizedValue, row.RegionKey}]; ok {
+					suggestion.ID = row.ID
+					suggestion.CreatedAt = row.CreatedAt
+					suggestion.UpdatedAt = row.UpdatedAt
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *keywordSuggestionRepository) GetByIDs(ids []int) ([]*entities.KeywordSuggestion, error) {
+	if len(ids) == 0 {
+		return []*entities.KeywordSuggestion{}, nil
+	}
+
+	var records []models.KeywordSuggestion
+	if err := r.db.Where("id IN ?", ids).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomainList(records), nil
+}
+
+func (r *keywordSuggestionRepository) GetBySite(siteID int, region *int, kind *string, offset, limit int) ([]*entities.KeywordSuggestion, int64, error) {
+	query := r.db.Model(&models.KeywordSuggestion{}).
+		Where("site_id = ?", siteID).
+		Where("NOT EXISTS (SELECT 1 FROM keywords k WHERE k.site_id = keyword_suggestions.site_id AND k.normalized_value = keyword_suggestions.normalized_value)")
+	if region != nil {
+		query = query.Where("region = ?", *region)
+	}
+	if kind != nil {
+		query = query.Where("kind = ?", *kind)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var records []models.KeywordSuggestion
+	if err := query.Order("frequency DESC, id").Offset(offset).Limit(limit).Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return r.toDomainList(records), total, nil
+}
+
+func (r *keywordSuggestionRepository) Accept(siteID int, ids []int, groupID *int) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	params := map[string]interface{}{"site": siteID, "ids": ids, "group": groupID}
+	created := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Слова сайта создаются под той же блокировкой, что и при импорте
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", keywordImportLockKey, siteID).Error; err != nil {
+			return err
+		}
+
+		// Фраза из нескольких регионов создается один раз, в написании подсказки с наибольшей частотой
+		result := tx.Exec(`
+			INSERT INTO keywords (value, normalized_value, site_id, group_id, created_at, updated_at)
+			SELECT DISTINCT ON (s.normalized_value) s.value, s.normalized_value, @site, CAST(@group AS BIGINT), now(), now()
+			FROM keyword_suggestions s
+			WHERE s.site_id = @site AND s.id IN @ids
+			ORDER BY s.normalized_value, s.frequency DESC, s.id
+			ON CONFLICT (site_id, normalized_value) DO NOTHING
+		`, params)
+		if result.Error != nil {
+			return result.Error
+		}
+		created = int(result.RowsAffected)
+
+		return tx.Exec(`
+			DELETE FROM keyword_suggestions
+			WHERE site_id = @site AND normalized_value IN (
+				SELECT normalized_value FROM keyword_suggestions WHERE site_id = @site AND id IN @ids
+			)
+		`, params).Error
+	})
+	if err != nil {
+		return 0, database.WrapDatabaseError(err)
+	}
+
+	return created, nil
+}
+
+func (r *keywordSuggestionRepository) Delete(siteID int, ids []int) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.Where("site_id = ? AND id IN ?", siteID, ids).Delete(&models.KeywordSuggestion{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
+
+func (r *keywordSuggestionRepository) toDomainList(records []models.KeywordSuggestion) []*entities.KeywordSuggestion {
+	suggestions := make([]*entities.KeywordSuggestion, len(records))
+	for i, record := range records {
+		suggestions[i] = &entities.KeywordSuggestion{
+			ID:              record.ID,
+			SiteID:          record.SiteID,
+			Value:           record.Value,
+			NormalizedValue: record.NormalizedValue,
+			Seed:            record.Seed,
+			Kind:            record.Kind,
+			Region:          record.Region,
+			Frequency:       record.Frequency,
+			CreatedAt:       record.CreatedAt,
+			UpdatedAt:       record.UpdatedAt,
+		}
+	}
+	return suggestions
+}
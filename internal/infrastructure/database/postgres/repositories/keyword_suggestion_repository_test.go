@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"testing"
+
+	"go-seo/internal/domain/entities"
+)
+
+func TestKeywordSuggestionSaveAndFilter(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &keywordSuggestionRepository{db: tx}
+	siteID, _, _, _ := seedVisibilitySite(t, tx)
+
+	region := 213
+	suggestion := func(value, key, kind string, region *int, frequency int) *entities.KeywordSuggestion {
+		return &entities.KeywordSuggestion{SiteID: siteID, Value: value, NormalizedValue: key, Seed: "елка", Kind: kind, Region: region, Frequency: frequency}
+	}
+	first := []*entities.KeywordSuggestion{
+		suggestion("купить елку", "купить елку", entities.SuggestionPopular, nil, 100),
+		suggestion("купить елку", "купить елку", entities.SuggestionPopular, &region, 40),
+		suggestion("новогодняя елка", "новогодняя елка", entities.SuggestionAssociation, &region, 70),
+		suggestion("елка цена", "елка цена", entities.SuggestionPopular, nil, 500),
+	}
+	if err := repo.Save(first); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	for _, s := range first {
+		if s.ID == 0 {
+			t.Fatalf("expected an id for %+v", s)
+		}
+	}
+
+	// Повторный сбор обновляет подсказку того же региона, а не создает новую
+	again := suggestion("Купить ёлку", "купить елку", entities.SuggestionAssociation, nil, 150)
+	if err := repo.Save([]*entities.KeywordSuggestion{again}); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+	if again.ID != first[0].ID {
+		t.Fatalf("expected the suggestion %d to be updated, got id %d", first[0].ID, again.ID)
+	}
+
+	// Фраза, уже ставшая словом сайта, не показывается
+	if err := tx.Exec("INSERT INTO keywords (value, normalized_value, site_id, created_at, updated_at) VALUES ('елка цена', 'елка цена', ?, NOW(), NOW())", siteID).Error; err != nil {
+		t.Fatalf("insert keyword: %v", err)
+	}
+
+	suggestions, total, err := repo.GetBySite(siteID, nil, nil, 0, 10)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if total != 3 || len(suggestions) != 3 || suggestions[0].ID != again.ID || suggestions[0].Value != "Купить ёлку" || suggestions[0].Frequency != 150 {
+		t.Fatalf("expected 3 suggestions starting with the updated one, got %d: %+v", total, suggestions)
+	}
+
+	kind := entities.SuggestionAssociation
+	suggestions, total, err = repo.GetBySite(siteID, &region, &kind, 0, 10)
+	if err != nil || total != 1 || len(suggestions) != 1 || suggestions[0].ID != first[2].ID {
+		t.Fatalf("expected the association of region 213, got %d: %+v, %v", total, suggestions, err)
+	}
+
+	if page, total, err := repo.GetBySite(siteID, nil, nil, 2, 1); err != nil || total != 3 || len(page) != 1 || page[0].ID != first[1].ID {
+		t.Fatalf("expected the last suggestion on the third page, got %d: %+v, %v", total, page, err)
+	}
+}
+
+func TestKeywordSuggestionAcceptAndDelete(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &keywordSuggestionRepository{db: tx}
+	siteID, groupID, _, _ := seedVisibilitySite(t, tx)
+
+	region := 213
+	suggestions := []*entities.KeywordSuggestion{
+		{SiteID: siteID, Value: "купить елку", NormalizedValue: "купить елку", Seed: "елка", Kind: entities.SuggestionPopular, Frequency: 100},
+		{SiteID: siteID, Value: "Купить ёлку", NormalizedValue: "купить елку", Seed: "елка", Kind: entities.SuggestionPopular, Region: &region, Frequency: 300},
+		{SiteID: siteID, Value: "новогодняя елка", NormalizedValue: "новогодняя елка", Seed: "елка", Kind: entities.SuggestionAssociation, Frequency: 70},
+	}
+	if err := repo.Save(suggestions); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	// Фраза из двух регионов создает одно слово в написании подсказки с наибольшей частотой
+	created, err := repo.Accept(siteID, []int{suggestions[0].ID, suggestions[1].ID}, &groupID)
+	if err != nil || created != 1 {
+		t.Fatalf("expected 1 created keyword, got %d, %v", created, err)
+	}
+	var keyword struct {
+		Value   string
+		GroupID *int
+	}
+	if err := tx.Raw("SELECT value, group_id FROM keywords WHERE site_id = ? AND normalized_value = 'купить елку'", siteID).Scan(&keyword).Error; err != nil {
+		t.Fatalf("load keyword: %v", err)
+	}
+	if keyword.Value != "Купить ёлку" || keyword.GroupID == nil || *keyword.GroupID != groupID {
+		t.Fatalf("unexpected keyword %+v", keyword)
+	}
+	if left, err := repo.GetByIDs([]int{suggestions[0].ID, suggestions[1].ID, suggestions[2].ID}); err != nil || len(left) != 1 || left[0].ID != suggestions[2].ID {
+		t.Fatalf("expected only the not accepted suggestion to remain, got %+v, %v", left, err)
+	}
+
+	// Подсказка другого сайта не удаляется
+	if deleted, err := repo.Delete(siteID+1, []int{suggestions[2].ID}); err != nil || deleted != 0 {
+		t.Fatalf("expected nothing deleted for another site, got %d, %v", deleted, err)
+	}
+	if deleted, err := repo.Delete(siteID, []int{suggestions[2].ID}); err != nil || deleted != 1 {
+		t.Fatalf("expected 1 deleted suggestion, got %d, %v", deleted, err)
+	}
+}
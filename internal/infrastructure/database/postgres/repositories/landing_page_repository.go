@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// Дни, свернутые заданием хранения, берутся из дневных агрегатов: в них сохранен URL последней проверки дня
+const rankingURLsQuery = `
+WITH checks AS (
+	SELECT keyword_id, source, filter_group_id, rank, url, date
+	FROM positions
+	WHERE site_id = @site AND competitor_id IS NULL AND source IN ('google', 'yandex') AND %[1]s
+	  AND date >= CAST(@from AS date) AND date < CAST(@to AS date) + 1
+	UNION ALL
+	SELECT keyword_id, source, filter_group_id, rank, url, date
+	FROM position_rollups
+	WHERE site_id = @site AND competitor_id IS NULL AND source IN ('google', 'yandex') AND %[1]s
+	  AND period = 'day' AND period_start >= CAST(@from AS date) AND period_start <= CAST(@to AS date)
+),
+days AS (
+	SELECT DISTINCT ON (keyword_id, source, COALESCE(filter_group_id, 0), DATE(date))
+		keyword_id, source, filter_group_id, DATE(date) AS day, rank, url
+	FROM checks
+	ORDER BY keyword_id, source, COALESCE(filter_group_id, 0), DATE(date), date DESC
+)
+SELECT d.keyword_id, k.value AS keyword, k.group_id, d.source, d.filter_group_id, d.day AS date, d.url, d.rank
+FROM days d
+JOIN keywords k ON k.id = d.keyword_id
+WHERE d.rank > 0 AND COALESCE(d.url, '') <> '' AND %[2]s
+ORDER BY d.keyword_id, d.source, COALESCE(d.filter_group_id, 0), d.day
+`
+
+// Группа фильтров проверки берется из позиции, к которой сохранен снимок
+const sharedSerpURLsQuery = `
+WITH site_items AS (
+	SELECT position_id, keyword_id, source, date, url, rank
+	FROM serp_snapshots
+	WHERE site_id = @site AND rank > 0 AND COALESCE(url, '') <> '' AND %[1]s
+	  AND (domain = @domain OR domain LIKE @subdomains)
+	  AND date >= CAST(@from AS date) AND date < CAST(@to AS date) + 1
+),
+shared AS (
+	SELECT position_id
+	FROM site_items
+	GROUP BY position_id
+	HAVING COUNT(DISTINCT url) > 1
+)
+SELECT i.position_id, i.keyword_id, k.value AS keyword, k.group_id, i.source, p.filter_group_id,
+	DATE(MAX(i.date)) AS date, i.url, MIN(i.rank) AS rank
+FROM site_items i
+JOIN shared s ON s.position_id = i.position_id
+JOIN keywords k ON k.id = i.keyword_id
+LEFT JOIN positions p ON p.id = i.position_id
+WHERE %[2]s
+GROUP BY i.position_id, i.keyword_id, k.value, k.group_id, i.source, p.filter_group_id, i.url
+ORDER BY i.keyword_id, date, i.position_id
+`
+
+type landingPageRepository struct {
+	db *gorm.DB
+}
+
+func NewLandingPageRepository(db *gorm.DB) repositories.LandingPageRepository {
+	return &landingPageRepository{db: db}
+}
+
+func (r *landingPageRepository) GetRankingURLs(siteID int, source *string, groupID *int, dateFrom, dateTo time.Time) ([]*entities.LandingCheck, error) {
+	return r.scanChecks(fmt.Sprintf(rankingURLsQuery, sourceCondition(source), keywordGroupCondition(groupID)), map[string]interface{}{
+		"site":   siteID,
+		"source": stringOrEmpty(source),
+		"group":  intOrZero(groupID),
+		"from":   dateFrom.Format("2006-01-02"),
+		"to":     dateTo.Format("2006-01-02"),
+	})
+}
+
+func (r *landingPageRepository) GetSharedSerpURLs(siteID int, domain string, source *string, groupID *int, dateFrom, dateTo time.Time) ([]*entities.LandingCheck, error) {
+	domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "www.")
+	return r.scanChecks(fmt.Sprintf(sharedSerpURLsQuery, sourceCondition(source), keywordGroupCondition(groupID)), map[string]interface{}{
+		"site":       siteID,
+		"domain":     domain,
+		"subdomains": "%." + domain,
+		"source":     stringOrEmpty(source),
+		"group":      intOrZero(groupID),
+		"from":       dateFrom.Format("2006-01-02"),
+		"to":         dateTo.Format("2006-01-02"),
+	})
+}
+
+func (r *landingPageRepository) scanChecks(query string, params map[string]interface{}) ([]*entities.LandingCheck, error) {
+	var rows []struct {
+		PositionID    int
+		KeywordID     int
+		Keyword       string
+		GroupID       *int
+		Source        string
+		FilterGroupID *int
+		Date          time.Time
+		URL           string
+		Rank          int
+	}
+	if err := r.db.Raw(query, params).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	checks := make([]*entities.LandingCheck, len(rows))
+	for i, row := range rows {
+		checks[i] = &entities.LandingCheck{
+			PositionID:    row.PositionID,
+			KeywordID:     row.KeywordID,
+			Keyword:       row.Keyword,
+			GroupID:       row.GroupID,
+			Source:        row.Source,
+			FilterGroupID: row.FilterGroupID,
+			Date:          row.Date,
+			URL:           row.URL,
+			Rank:          row.Rank,
+		}
+	}
+	return checks, nil
+}
+
+func sourceCondition(source *string) string {
+	if source == nil {
+		return "TRUE"
+	}
+	return "source = @source"
+}
+
+func keywordGroupCondition(groupID *int) string {
+	if groupID == nil {
+		return "TRUE"
+	}
+	return "k.group_id = @group"
+}
+
+func stringOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+func insertLandingPosition(tb testing.TB, tx *gorm.DB, keywordID, siteID int, competitorID *int, rank int, url string, date time.Time) int {
+	tb.Helper()
+
+	var id int
+	if err := tx.Raw(`
+		INSERT INTO positions (keyword_id, site_id, competitor_id, rank, url, title, source, device, ads, pages, date,
+			wordstat_query_type, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, '', 'google', 'desktop', false, 1, ?, '', NOW(), NOW())
+		RETURNING id`,
+		keywordID, siteID, competitorID, rank, url, date).Scan(&id).Error; err != nil {
+		tb.Fatalf("insert position: %v", err)
+	}
+	return id
+}
+
+func insertLandingSnapshot(tb testing.TB, tx *gorm.DB, positionID, keywordID, siteID, place int, url, domain string, date time.Time) {
+	tb.Helper()
+
+	if err := tx.Exec(`
+		INSERT INTO serp_snapshots (position_id, keyword_id, site_id, source, place, rank, url, domain, title, date, created_at)
+		VALUES (?, ?, ?, 'google', ?, ?, ?, ?, '', ?, NOW())`,
+		positionID, keywordID, siteID, place, place, url, domain, date).Error; err != nil {
+		tb.Fatalf("insert snapshot: %v", err)
+	}
+}
+
+func TestLandingPageRankingURLs(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &landingPageRepository{db: tx}
+	siteID, groupID, grouped, ungrouped := seedVisibilitySite(t, tx)
+
+	// Проверки около полудня UTC попадают в свой день при любой зоне сессии БД
+	noon := func(day int) time.Time { return time.Date(2026, 10, day, 12, 0, 0, 0, time.UTC) }
+	competitorID := 1
+	insertLandingPosition(t, tx, grouped, siteID, nil, 3, "https://visibility.example/a", noon(1))
+	// За день берется последняя проверка
+	insertLandingPosition(t, tx, grouped, siteID, nil, 7, "https://visibility.example/a", noon(2))
+	insertLandingPosition(t, tx, grouped, siteID, nil, 5, "https://visibility.example/b", noon(2).Add(time.Hour))
+	// Не найденные позиции и позиции конкурентов пропускаются
+	insertLandingPosition(t, tx, grouped, siteID, nil, 0, "", noon(3))
+	insertLandingPosition(t, tx, grouped, siteID, &competitorID, 1, "https://rival.example/", noon(3))
+	insertLandingPosition(t, tx, ungrouped, siteID, nil, 9, "https://visibility.example/c", noon(1))
+
+	from, to := noon(1).AddDate(0, 0, -1), noon(3)
+	checks, err := repo.GetRankingURLs(siteID, nil, nil, from, to)
+	if err != nil {
+		t.Fatalf("ranking urls: %v", err)
+	}
+	if len(checks) != 3 {
+		t.Fatalf("expected 3 daily checks, got %+v", checks)
+	}
+	if c := checks[1]; c.KeywordID != grouped || c.URL != "https://visibility.example/b" || c.Rank != 5 || c.Date.Format("2006-01-02") != "2026-10-02" {
+		t.Fatalf("expected the last check of 2026-10-02, got %+v", c)
+	}
+
+	checks, err = repo.GetRankingURLs(siteID, nil, &groupID, from, to)
+	if err != nil || len(checks) != 2 || checks[0].KeywordID != grouped {
+		t.Fatalf("expected checks of the grouped keyword, got %+v, %v", checks, err)
+	}
+	yandex := entities.YandexSearch
+	if checks, err := repo.GetRankingURLs(siteID, &yandex, nil, from, to); err != nil || len(checks) != 0 {
+		t.Fatalf("expected no yandex checks, got %+v, %v", checks, err)
+	}
+}
+
+func TestLandingPageSharedSerpURLs(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &landingPageRepository{db: tx}
+	siteID, _, grouped, ungrouped := seedVisibilitySite(t, tx)
+
+	date := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	// В одной выдаче страница сайта и страница поддомена
+	shared := insertLandingPosition(t, tx, grouped, siteID, nil, 2, "https://visibility.example/a", date)
+	insertLandingSnapshot(t, tx, shared, grouped, siteID, 2, "https://visibility.example/a", "visibility.example", date)
+	insertLandingSnapshot(t, tx, shared, grouped, siteID, 6, "https://blog.visibility.example/a", "blog.visibility.example", date)
+	insertLandingSnapshot(t, tx, shared, grouped, siteID, 1, "https://rival.example/", "rival.example", date)
+	// В этой выдаче только один URL сайта
+	single := insertLandingPosition(t, tx, ungrouped, siteID, nil, 4, "https://visibility.example/c", date)
+	insertLandingSnapshot(t, tx, single, ungrouped, siteID, 4, "https://visibility.example/c", "visibility.example", date)
+	insertLandingSnapshot(t, tx, single, ungrouped, siteID, 5, "https://notvisibility.example/c", "notvisibility.example", date)
+
+	checks, err := repo.GetSharedSerpURLs(siteID, "www.visibility.example", nil, nil, date.AddDate(0, 0, -1), date)
+	if err != nil {
+		t.Fatalf("shared urls: %v", err)
+	}
+	if len(checks) != 2 {
+		t.Fatalf("expected 2 site URLs of the shared check, got %+v", checks)
+	}
+	for _, check := range checks {
+		if check.PositionID != shared || check.KeywordID != grouped {
+			t.Fatalf("unexpected shared check %+v", check)
+		}
+	}
+}
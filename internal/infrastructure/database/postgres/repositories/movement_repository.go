@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// Дни, уже свернутые заданием хранения, берутся из дневных агрегатов. Слово, которое в день previous
+// не проверялось, получает previous_rank = 0
+const keywordMovementQuery = `
+WITH checks AS (
+	SELECT keyword_id, rank, date
+	FROM positions
+	WHERE site_id = @site AND source = @source AND competitor_id IS NULL AND %[1]s
+	  AND ((date >= CAST(@previous AS date) AND date < CAST(@previous AS date) + 1)
+	    OR (date >= CAST(@current AS date) AND date < CAST(@current AS date) + 1))
+	UNION ALL
+	SELECT keyword_id, rank, date
+	FROM position_rollups
+	WHERE site_id = @site AND source = @source AND competitor_id IS NULL AND %[1]s
+	  AND period = 'day' AND period_start IN (CAST(@previous AS date), CAST(@current AS date))
+),
+day_ranks AS (
+	SELECT DISTINCT ON (keyword_id, DATE(date)) keyword_id, DATE(date) AS day, rank
+	FROM checks
+	ORDER BY keyword_id, DATE(date), date DESC
+)
+SELECT c.keyword_id, k.value AS keyword, k.group_id, COALESCE(p.rank, 0) AS previous_rank, c.rank AS current_rank
+FROM day_ranks c
+JOIN keywords k ON k.id = c.keyword_id
+LEFT JOIN day_ranks p ON p.keyword_id = c.keyword_id AND p.day = CAST(@previous AS date)
+WHERE c.day = CAST(@current AS date)
+ORDER BY c.keyword_id
+`
+
+type movementRepository struct {
+	db *gorm.DB
+}
+
+func NewMovementRepository(db *gorm.DB) repositories.MovementRepository {
+	return &movementRepository{db: db}
+}
+
+func (r *movementRepository) GetCheckDays(siteID int, source string, filterGroupID *int, until time.Time, limit int) ([]time.Time, error) {
+	filter := filterGroupCondition(filterGroupID)
+	rows, err := r.db.Raw(`
+		SELECT day FROM (
+			SELECT DISTINCT DATE(date) AS day
+			FROM positions
+			WHERE site_id = @site AND source = @source AND competitor_id IS NULL AND `+filter+`
+			  AND date < CAST(@until AS date) + 1
+			UNION
+			SELECT period_start
+			FROM position_rollups
+			WHERE site_id = @site AND source = @source AND competitor_id IS NULL AND `+filter+`
+			  AND period = 'day' AND period_start <= CAST(@until AS date)
+		) AS checked
+		ORDER BY day DESC
+		LIMIT @limit
+	`, map[string]interface{}{
+		"site":   siteID,
+		"source": source,
+		"filter": intOrZero(filterGroupID),
+		"until":  until.Format("2006-01-02"),
+		"limit":  limit,
+	}).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
+func (r *movementRepository) GetKeywordMovement(siteID int, source string, filterGroupID *int, previous, current time.Time) ([]*entities.KeywordMovement, error) {
+	var rows []struct {
+		KeywordID    int
+		Keyword      string
+		GroupID      *int
+		PreviousRank int
+		CurrentRank  int
+	}
+	if err := r.db.Raw(fmt.Sprintf(keywordMovementQuery, filterGroupCondition(filterGroupID)), map[string]interface{}{
+		"site":     siteID,
+		"source":   source,
+		"filter":   intOrZero(filterGroupID),
+		"previous": previous.Format("2006-01-02"),
+		"current":  current.Format("2006-01-02"),
+	}).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	movements := make([]*entities.KeywordMovement, len(rows))
+	for i, row := range rows {
+		movements[i] = &entities.KeywordMovement{
+			KeywordID:    row.KeywordID,
+			Keyword:      row.Keyword,
+			GroupID:      row.GroupID,
+			PreviousRank: row.PreviousRank,
+			CurrentRank:  row.CurrentRank,
+		}
+	}
+	return movements, nil
+}
+
+func filterGroupCondition(filterGroupID *int) string {
+	if filterGroupID == nil {
+		return "filter_group_id IS NULL"
+	}
+	return "filter_group_id = @filter"
+}
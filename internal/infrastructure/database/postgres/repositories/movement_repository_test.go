@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+func TestMovementBetweenCheckDays(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &movementRepository{db: tx}
+	siteID, groupID, grouped, ungrouped := seedVisibilitySite(t, tx)
+
+	// Проверки около полудня UTC попадают в свой день при любой зоне сессии БД
+	noon := func(day int) time.Time { return time.Date(2026, 10, day, 12, 0, 0, 0, time.UTC) }
+	competitorID := 1
+	insertVisibilityPosition(t, tx, grouped, siteID, nil, entities.GoogleSearch, 10, noon(3), "")
+	insertVisibilityPosition(t, tx, grouped, siteID, nil, entities.GoogleSearch, 8, noon(7), "")
+	// За день берется последняя проверка
+	insertVisibilityPosition(t, tx, grouped, siteID, nil, entities.GoogleSearch, 3, noon(7).Add(time.Hour), "")
+	insertVisibilityPosition(t, tx, ungrouped, siteID, nil, entities.GoogleSearch, 20, noon(7), "")
+	// Конкуренты и другие источники не дают дней проверки
+	insertVisibilityPosition(t, tx, grouped, siteID, &competitorID, entities.GoogleSearch, 1, noon(5), "")
+	insertVisibilityPosition(t, tx, grouped, siteID, nil, entities.YandexSearch, 1, noon(6), "")
+
+	days, err := repo.GetCheckDays(siteID, entities.GoogleSearch, nil, noon(10), 5)
+	if err != nil {
+		t.Fatalf("check days: %v", err)
+	}
+	if len(days) != 2 || days[0].Format("2006-01-02") != "2026-10-07" || days[1].Format("2006-01-02") != "2026-10-03" {
+		t.Fatalf("expected 2026-10-07 and 2026-10-03, got %v", days)
+	}
+	if days, err := repo.GetCheckDays(siteID, entities.GoogleSearch, nil, noon(6), 1); err != nil || len(days) != 1 || days[0].Format("2006-01-02") != "2026-10-03" {
+		t.Fatalf("expected the check before 2026-10-06, got %v, %v", days, err)
+	}
+	if days, err := repo.GetCheckDays(siteID, entities.GoogleSearch, &groupID, noon(10), 5); err != nil || len(days) != 0 {
+		t.Fatalf("expected no checks in the filter group, got %v, %v", days, err)
+	}
+
+	movements, err := repo.GetKeywordMovement(siteID, entities.GoogleSearch, nil, noon(3), noon(7))
+	if err != nil {
+		t.Fatalf("movement: %v", err)
+	}
+	if len(movements) != 2 {
+		t.Fatalf("expected 2 keywords checked on 2026-10-07, got %+v", movements)
+	}
+	if m := movements[0]; m.KeywordID != grouped || m.PreviousRank != 10 || m.CurrentRank != 3 || m.GroupID == nil || *m.GroupID != groupID {
+		t.Fatalf("unexpected movement of the grouped keyword: %+v", m)
+	}
+	// Слово не проверялось в первый день: предыдущая позиция 0
+	if m := movements[1]; m.KeywordID != ungrouped || m.PreviousRank != 0 || m.CurrentRank != 20 || m.Keyword != "ungrouped" {
+		t.Fatalf("unexpected movement of the ungrouped keyword: %+v", m)
+	}
+}
+
+func TestSiteMovementSummary(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &siteRepository{db: tx}
+
+	// Бинарная динамика удалена миграцией 0004
+	var dynamicColumns int64
+	if err := tx.Raw(`SELECT COUNT(*) FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'sites' AND column_name IN ('yandex_dynamic', 'google_dynamic')`).
+		Scan(&dynamicColumns).Error; err != nil || dynamicColumns != 0 {
+		t.Fatalf("expected dynamic columns to be dropped, got %d, %v", dynamicColumns, err)
+	}
+
+	site := &entities.Site{WorkspaceID: 1, Domain: "movement.example", BudgetAction: entities.BudgetActionRefuse}
+	if err := repo.Create(site); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	summary := &entities.MovementSummary{
+		PreviousDate: time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC),
+		CurrentDate:  time.Date(2026, 10, 7, 0, 0, 0, 0, time.UTC),
+		Up:           2, Lost: 1, NetScore: -40,
+	}
+	if err := repo.UpdateMovement(site.ID, entities.GoogleSearch, summary); err != nil {
+		t.Fatalf("update movement: %v", err)
+	}
+	if err := repo.UpdateMovement(site.ID, "wordstat", summary); err == nil {
+		t.Fatal("expected an error for a source without movement")
+	}
+
+	// Сохранение настроек сайта не затирает сводку
+	site.Domain = "renamed.example"
+	if err := repo.Update(site); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	stored, err := repo.GetByID(site.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if stored.Domain != "renamed.example" || stored.YandexMovement != nil {
+		t.Fatalf("unexpected site %+v", stored)
+	}
+	got := stored.GoogleMovement
+	if got == nil || got.Up != 2 || got.Lost != 1 || got.NetScore != -40 || got.CurrentDate.Format("2006-01-02") != "2026-10-07" {
+		t.Fatalf("expected the stored google movement, got %+v", got)
+	}
+
+	if err := repo.UpdateMovement(site.ID, entities.GoogleSearch, nil); err != nil {
+		t.Fatalf("clear movement: %v", err)
+	}
+	if stored, err := repo.GetByID(site.ID); err != nil || stored.GoogleMovement != nil {
+		t.Fatalf("expected the movement to be cleared, got %+v, %v", stored, err)
+	}
+}
@@ -1,10 +1,12 @@
 package repositories
 
 import (
+	"database/sql"
 	"fmt"
 	"go-seo/internal/domain/entities"
 	"go-seo/internal/domain/repositories"
 	positionModels "go-seo/internal/infrastructure/database/postgres/models"
+	"math"
 	"sort"
 	"time"
 
@@ -23,6 +25,7 @@ func (r *positionRepository) Create(position *entities.Position) error {
 	model := &positionModels.Position{
 		KeywordID:         position.KeywordID,
 		SiteID:            position.SiteID,
+		CompetitorID:      position.CompetitorID,
 		Rank:              position.Rank,
 		URL:               position.URL,
 		Title:             position.Title,
@@ -38,7 +41,7 @@ func (r *positionRepository) Create(position *entities.Position) error {
 		WordstatQueryType: position.WordstatQueryType,
 	}
 
-	if err := r.db.Select("keyword_id", "site_id", "rank", "url", "title", "source", "device", "os", "ads", "country", "lang", "pages", "date", "filter_group_id", "wordstat_query_type").Create(model).Error; err != nil {
+	if err := r.db.Select("keyword_id", "site_id", "competitor_id", "rank", "url", "title", "source", "device", "os", "ads", "country", "lang", "pages", "date", "filter_group_id", "wordstat_query_type").Create(model).Error; err != nil {
 		return err
 	}
 
@@ -56,6 +59,7 @@ func (r *positionRepository) CreateBatch(positions []*entities.Position) error {
 		models[i] = &positionModels.Position{
 			KeywordID:         position.KeywordID,
 			SiteID:            position.SiteID,
+			CompetitorID:      position.CompetitorID,
 			Rank:              position.Rank,
 			URL:               position.URL,
 			Title:             position.Title,
@@ -94,7 +98,7 @@ func (r *positionRepository) GetByID(id int) (*entities.Position, error) {
 
 func (r *positionRepository) GetByKeywordAndSite(keywordID, siteID int) ([]*entities.Position, error) {
 	var models []positionModels.Position
-	if err := r.db.Where("keyword_id = ? AND site_id = ?", keywordID, siteID).
+	if err := r.db.Where("keyword_id = ? AND site_id = ? AND competitor_id IS NULL", keywordID, siteID).
 		Order("date DESC").
 		Find(&models).Error; err != nil {
 		return nil, err
@@ -110,7 +114,7 @@ func (r *positionRepository) GetByKeywordAndSite(keywordID, siteID int) ([]*enti
 
 func (r *positionRepository) GetBySiteID(siteID int) ([]*entities.Position, error) {
 	var models []positionModels.Position
-	if err := r.db.Where("site_id = ?", siteID).
+	if err := r.db.Where("site_id = ? AND competitor_id IS NULL", siteID).
 		Order("date DESC").
 		Find(&models).Error; err != nil {
 		return nil, err
@@ -125,7 +129,7 @@ func (r *positionRepository) GetBySiteID(siteID int) ([]*entities.Position, erro
 }
 func (r *positionRepository) GetBySiteIDAndSource(siteID int, source string) ([]*entities.Position, error) {
 	var models []positionModels.Position
-	if err := r.db.Where("site_id = ? AND source = ?", siteID, source).
+	if err := r.db.Where("site_id = ? AND source = ? AND competitor_id IS NULL", siteID, source).
 		Order("date DESC").
 		Find(&models).Error; err != nil {
 		return nil, err
@@ -141,7 +145,7 @@ func (r *positionRepository) GetBySiteIDAndSource(siteID int, source string) ([]
 
 func (r *positionRepository) GetByKeywordAndSiteAndSource(keywordID, siteID int, source string) ([]*entities.Position, error) {
 	var models []positionModels.Position
-	if err := r.db.Where("keyword_id = ? AND site_id = ? AND source = ?", keywordID, siteID, source).
+	if err := r.db.Where("keyword_id = ? AND site_id = ? AND source = ? AND competitor_id IS NULL", keywordID, siteID, source).
 		Order("date DESC").
 		Find(&models).Error; err != nil {
 		return nil, err
@@ -155,7 +159,7 @@ func (r *positionRepository) GetByKeywordAndSiteAndSource(keywordID, siteID int,
 	return positions, nil
 }
 func (r *positionRepository) GetBySiteIDWithDateRange(siteID int, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	query := r.db.Where("site_id = ?", siteID)
+	query := r.db.Where("site_id = ? AND competitor_id IS NULL", siteID)
 
 	if dateFrom != nil {
 		query = query.Where("date >= ?", *dateFrom)
@@ -178,7 +182,7 @@ func (r *positionRepository) GetBySiteIDWithDateRange(siteID int, dateFrom, date
 }
 
 func (r *positionRepository) GetBySiteIDAndSourceWithDateRange(siteID int, source string, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	query := r.db.Where("site_id = ? AND source = ?", siteID, source)
+	query := r.db.Where("site_id = ? AND source = ? AND competitor_id IS NULL", siteID, source)
 
 	if dateFrom != nil {
 		query = query.Where("date >= ?", *dateFrom)
@@ -201,7 +205,7 @@ func (r *positionRepository) GetBySiteIDAndSourceWithDateRange(siteID int, sourc
 }
 
 func (r *positionRepository) GetByKeywordAndSiteWithDateRange(keywordID, siteID int, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	query := r.db.Where("keyword_id = ? AND site_id = ?", keywordID, siteID)
+	query := r.db.Where("keyword_id = ? AND site_id = ? AND competitor_id IS NULL", keywordID, siteID)
 
 	if dateFrom != nil {
 		query = query.Where("date >= ?", *dateFrom)
@@ -224,7 +228,7 @@ func (r *positionRepository) GetByKeywordAndSiteWithDateRange(keywordID, siteID
 }
 
 func (r *positionRepository) GetByKeywordAndSiteAndSourceWithDateRange(keywordID, siteID int, source string, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	query := r.db.Where("keyword_id = ? AND site_id = ? AND source = ?", keywordID, siteID, source)
+	query := r.db.Where("keyword_id = ? AND site_id = ? AND source = ? AND competitor_id IS NULL", keywordID, siteID, source)
 
 	if dateFrom != nil {
 		query = query.Where("date >= ?", *dateFrom)
@@ -248,7 +252,7 @@ func (r *positionRepository) GetByKeywordAndSiteAndSourceWithDateRange(keywordID
 
 func (r *positionRepository) GetLatestByKeywordAndSite(keywordID, siteID int) (*entities.Position, error) {
 	var model positionModels.Position
-	if err := r.db.Where("keyword_id = ? AND site_id = ?", keywordID, siteID).
+	if err := r.db.Where("keyword_id = ? AND site_id = ? AND competitor_id IS NULL", keywordID, siteID).
 		Order("date DESC").
 		First(&model).Error; err != nil {
 		return nil, err
@@ -279,6 +283,7 @@ func (r *positionRepository) Update(position *entities.Position) error {
 		Updates(positionModels.Position{
 			KeywordID:         position.KeywordID,
 			SiteID:            position.SiteID,
+			CompetitorID:      position.CompetitorID,
 			Rank:              position.Rank,
 			URL:               position.URL,
 			Title:             position.Title,
@@ -300,14 +305,37 @@ func (r *positionRepository) Delete(id int) error {
 }
 
 func (r *positionRepository) DeleteBySiteID(siteID int) error {
-	return r.db.Where("site_id = ?", siteID).Delete(&positionModels.Position{}).Error
+	return r.deleteWithRollups("site_id = ?", siteID)
 }
 
 func (r *positionRepository) DeleteByKeywordID(keywordID int) error {
-	return r.db.Where("keyword_id = ?", keywordID).Delete(&positionModels.Position{}).Error
+	return r.deleteWithRollups("keyword_id = ?", keywordID)
 }
 
-func (r *positionRepository) GetTodayByKeywordAndSiteAndSource(keywordID, siteID int, source string, wordstatQueryType string, filterGroupID *int) (*entities.Position, error) {
+func (r *positionRepository) DeleteByCompetitorID(competitorID int) error {
+	return r.deleteWithRollups("competitor_id = ?", competitorID)
+}
+
+func (r *positionRepository) deleteWithRollups(condition string, value int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(condition, value).Delete(&positionModels.PositionRollup{}).Error; err != nil {
+			return err
+		}
+		return tx.Where(condition, value).Delete(&positionModels.Position{}).Error
+	})
+}
+
+// byCompetitor оставляет позиции конкурента или, если competitorID не задан, позиции самого сайта
+func byCompetitor(competitorID *int) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if competitorID != nil {
+			return db.Where("competitor_id = ?", *competitorID)
+		}
+		return db.Where("competitor_id IS NULL")
+	}
+}
+
+func (r *positionRepository) GetTodayByKeywordAndSiteAndSource(keywordID, siteID int, competitorID *int, source string, wordstatQueryType string, filterGroupID *int) (*entities.Position, error) {
 	var model positionModels.Position
 
 	now := time.Now()
@@ -315,7 +343,8 @@ func (r *positionRepository) GetTodayByKeywordAndSiteAndSource(keywordID, siteID
 	endOfDay := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 999999999, now.Location())
 
 	query := r.db.Where("keyword_id = ? AND site_id = ? AND source = ? AND date >= ? AND date <= ?",
-		keywordID, siteID, source, startOfDay, endOfDay)
+		keywordID, siteID, source, startOfDay, endOfDay).
+		Scopes(byCompetitor(competitorID))
 
 	if source == "wordstat" && wordstatQueryType != "" {
 		query = query.Where("wordstat_query_type = ?", wordstatQueryType)
@@ -342,7 +371,7 @@ func (r *positionRepository) CreateOrUpdateToday(position *entities.Position) er
 	if position.Source == "wordstat" {
 		wordstatQueryType = position.WordstatQueryType
 	}
-	existingPosition, err := r.GetTodayByKeywordAndSiteAndSource(position.KeywordID, position.SiteID, position.Source, wordstatQueryType, position.FilterGroupID)
+	existingPosition, err := r.GetTodayByKeywordAndSiteAndSource(position.KeywordID, position.SiteID, position.CompetitorID, position.Source, wordstatQueryType, position.FilterGroupID)
 	if err != nil {
 		return err
 	}
@@ -369,7 +398,7 @@ func (r *positionRepository) CreateOrUpdateToday(position *entities.Position) er
 func (r *positionRepository) GetHistoryBySiteIDWithOnePerDay(siteID int, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
 	query := r.db.Table("positions").
 		Select("DISTINCT ON (keyword_id, DATE(date)) *").
-		Where("site_id = ?", siteID)
+		Where("site_id = ? AND competitor_id IS NULL", siteID)
 
 	if dateFrom != nil {
 		query = query.Where("date >= ?", *dateFrom)
@@ -388,13 +417,15 @@ func (r *positionRepository) GetHistoryBySiteIDWithOnePerDay(siteID int, dateFro
 		positions[i] = r.toDomain(&model)
 	}
 
-	return positions, nil
+	return r.appendRollups(positions, siteID, dateFrom, dateTo, func(db *gorm.DB) *gorm.DB {
+		return db.Where("competitor_id IS NULL")
+	})
 }
 
 func (r *positionRepository) GetHistoryBySiteIDAndSourceWithOnePerDay(siteID int, source string, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
 	query := r.db.Table("positions").
 		Select("DISTINCT ON (keyword_id, DATE(date)) *").
-		Where("site_id = ? AND source = ?", siteID, source)
+		Where("site_id = ? AND source = ? AND competitor_id IS NULL", siteID, source)
 
 	if dateFrom != nil {
 		query = query.Where("date >= ?", *dateFrom)
@@ -413,13 +444,15 @@ func (r *positionRepository) GetHistoryBySiteIDAndSourceWithOnePerDay(siteID int
 		positions[i] = r.toDomain(&model)
 	}
 
-	return positions, nil
+	return r.appendRollups(positions, siteID, dateFrom, dateTo, func(db *gorm.DB) *gorm.DB {
+		return db.Where("source = ? AND competitor_id IS NULL", source)
+	})
 }
 
 func (r *positionRepository) GetHistoryByKeywordAndSiteWithOnePerDay(keywordID, siteID int, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
 	query := r.db.Table("positions").
 		Select("DISTINCT ON (DATE(date)) *").
-		Where("keyword_id = ? AND site_id = ?", keywordID, siteID)
+		Where("keyword_id = ? AND site_id = ? AND competitor_id IS NULL", keywordID, siteID)
 
 	if dateFrom != nil {
 		query = query.Where("date >= ?", *dateFrom)
@@ -438,13 +471,15 @@ func (r *positionRepository) GetHistoryByKeywordAndSiteWithOnePerDay(keywordID,
 		positions[i] = r.toDomain(&model)
 	}
 
-	return positions, nil
+	return r.appendRollups(positions, siteID, dateFrom, dateTo, func(db *gorm.DB) *gorm.DB {
+		return db.Where("keyword_id = ? AND competitor_id IS NULL", keywordID)
+	})
 }
 
 func (r *positionRepository) GetHistoryByKeywordAndSiteAndSourceWithOnePerDay(keywordID, siteID int, source string, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
 	query := r.db.Table("positions").
 		Select("DISTINCT ON (DATE(date)) *").
-		Where("keyword_id = ? AND site_id = ? AND source = ?", keywordID, siteID, source)
+		Where("keyword_id = ? AND site_id = ? AND source = ? AND competitor_id IS NULL", keywordID, siteID, source)
 
 	if dateFrom != nil {
 		query = query.Where("date >= ?", *dateFrom)
@@ -463,7 +498,9 @@ func (r *positionRepository) GetHistoryByKeywordAndSiteAndSourceWithOnePerDay(ke
 		positions[i] = r.toDomain(&model)
 	}
 
-	return positions, nil
+	return r.appendRollups(positions, siteID, dateFrom, dateTo, func(db *gorm.DB) *gorm.DB {
+		return db.Where("keyword_id = ? AND source = ? AND competitor_id IS NULL", keywordID, source)
+	})
 }
 
 func (r *positionRepository) GetLatestBySiteID(siteID int) ([]*entities.Position, error) {
@@ -472,7 +509,7 @@ func (r *positionRepository) GetLatestBySiteID(siteID int) ([]*entities.Position
 	query := `
 		SELECT DISTINCT ON (keyword_id) *
 		FROM positions 
-		WHERE site_id = ?
+		WHERE site_id = ? AND competitor_id IS NULL
 		ORDER BY keyword_id, date DESC
 	`
 
@@ -494,7 +531,7 @@ func (r *positionRepository) GetLatestBySiteIDAndSource(siteID int, source strin
 	query := `
 		SELECT DISTINCT ON (keyword_id) *
 		FROM positions 
-		WHERE site_id = ? AND source = ?
+		WHERE site_id = ? AND source = ? AND competitor_id IS NULL
 		ORDER BY keyword_id, date DESC
 	`
 
@@ -510,11 +547,103 @@ func (r *positionRepository) GetLatestBySiteIDAndSource(siteID int, source strin
 	return positions, nil
 }
 
+// rollupsBefore возвращает true, если диапазон начинается раньше детальных позиций сайта
+// и часть его хранится только в position_rollups
+func (r *positionRepository) rollupsBefore(siteID int, dateFrom *time.Time) (bool, error) {
+	var boundary sql.NullTime
+	if err := r.db.Model(&positionModels.PositionRollup{}).
+		Select("MAX(period_end)").
+		Where("site_id = ?", siteID).
+		Row().Scan(&boundary); err != nil {
+		return false, err
+	}
+	if !boundary.Valid {
+		return false, nil
+	}
+	return dateFrom == nil || dateFrom.Before(boundary.Time), nil
+}
+
+// rollupQuery агрегаты сайта, периоды которых пересекаются с [dateFrom, dateTo]
+func (r *positionRepository) rollupQuery(siteID int, dateFrom, dateTo *time.Time) *gorm.DB {
+	query := r.db.Model(&positionModels.PositionRollup{}).Where("site_id = ?", siteID)
+	if dateFrom != nil {
+		query = query.Where("period_end > ?", *dateFrom)
+	}
+	if dateTo != nil {
+		query = query.Where("period_start <= ?", *dateTo)
+	}
+	return query
+}
+
+// appendRollups дополняет историю агрегатами старых позиций, если диапазон их захватывает.
+// scope накладывает на агрегаты те же условия, что и на детальные позиции
+func (r *positionRepository) appendRollups(positions []*entities.Position, siteID int, dateFrom, dateTo *time.Time, scope func(*gorm.DB) *gorm.DB) ([]*entities.Position, error) {
+	needed, err := r.rollupsBefore(siteID, dateFrom)
+	if err != nil || !needed {
+		return positions, err
+	}
+
+	var rollups []positionModels.PositionRollup
+	if err := r.rollupQuery(siteID, dateFrom, dateTo).Scopes(scope).Find(&rollups).Error; err != nil {
+		return nil, err
+	}
+	for i := range rollups {
+		positions = append(positions, r.rollupToDomain(&rollups[i]))
+	}
+
+	sort.SliceStable(positions, func(i, j int) bool {
+		if positions[i].KeywordID != positions[j].KeywordID {
+			return positions[i].KeywordID < positions[j].KeywordID
+		}
+		return positions[i].Date.After(positions[j].Date)
+	})
+
+	return positions, nil
+}
+
+func (r *positionRepository) rollupToDomain(model *positionModels.PositionRollup) *entities.Position {
+	position := &entities.Position{
+		KeywordID:         model.KeywordID,
+		SiteID:            model.SiteID,
+		CompetitorID:      model.CompetitorID,
+		Rank:              model.Rank,
+		URL:               model.URL,
+		Title:             model.Title,
+		Source:            model.Source,
+		Device:            model.Device,
+		OS:                model.OS,
+		Ads:               model.Ads,
+		Country:           model.Country,
+		Lang:              model.Lang,
+		Pages:             model.Pages,
+		Date:              model.Date,
+		FilterGroupID:     model.FilterGroupID,
+		WordstatQueryType: model.WordstatQueryType,
+		Period:            model.Period,
+		Samples:           model.Samples,
+	}
+
+	if model.VisibleSamples > 0 {
+		avg := math.Round(float64(model.RankSum)/float64(model.VisibleSamples)*100) / 100
+		position.AvgRank = &avg
+	}
+
+	if model.Keyword.ID != 0 {
+		position.Keyword = &entities.Keyword{
+			ID:    model.Keyword.ID,
+			Value: model.Keyword.Value,
+		}
+	}
+
+	return position
+}
+
 func (r *positionRepository) toDomain(model *positionModels.Position) *entities.Position {
 	position := &entities.Position{
 		ID:                model.ID,
 		KeywordID:         model.KeywordID,
 		SiteID:            model.SiteID,
+		CompetitorID:      model.CompetitorID,
 		Rank:              model.Rank,
 		URL:               model.URL,
 		Title:             model.Title,
@@ -547,7 +676,7 @@ func (r *positionRepository) toDomain(model *positionModels.Position) *entities.
 	return position
 }
 
-func (r *positionRepository) GetPositionStatistics(siteID int, source string, dateFrom, dateTo time.Time, filterGroupID *int) (*entities.PositionStatistics, error) {
+func (r *positionRepository) GetPositionStatistics(siteID int, competitorID *int, source string, dateFrom, dateTo time.Time, filterGroupID *int, tags *entities.TagFilter) (*entities.PositionStatistics, error) {
 	var stats entities.PositionStatistics
 
 	query := `
@@ -576,12 +705,28 @@ func (r *positionRepository) GetPositionStatistics(siteID int, source string, da
 		  AND date <= $4::date
 	`
 
-	queryParams := []interface{}{siteID, source, dateFrom, dateTo}
+	// Общие условия по группе фильтров, конкуренту и меткам для всех запросов статистики
+	conditions := ""
+	if tags != nil {
+		conditions += " AND " + keywordTagsCondition("keyword_id", tags)
+	}
+	conditionParams := []interface{}{}
+	paramIndex := 5
 	if filterGroupID != nil {
-		query += ` AND filter_group_id = $5`
-		queryParams = append(queryParams, *filterGroupID)
+		conditions += fmt.Sprintf(" AND filter_group_id = $%d", paramIndex)
+		conditionParams = append(conditionParams, *filterGroupID)
+		paramIndex++
+	}
+	if competitorID != nil {
+		conditions += fmt.Sprintf(" AND competitor_id = $%d", paramIndex)
+		conditionParams = append(conditionParams, *competitorID)
+	} else {
+		conditions += " AND competitor_id IS NULL"
 	}
 
+	query += conditions
+	queryParams := append([]interface{}{siteID, source, dateFrom, dateTo}, conditionParams...)
+
 	var result struct {
 		TotalPositions int     `json:"total_positions"`
 		KeywordsCount  int     `json:"keywords_count"`
@@ -640,11 +785,8 @@ func (r *positionRepository) GetPositionStatistics(siteID int, source string, da
 		FROM positions 
 		WHERE site_id = $1 AND source = $2 AND date >= $3::date AND date <= $4::date AND rank > 0
 	`
-	medianParams := []interface{}{siteID, source, dateFrom, dateTo}
-	if filterGroupID != nil {
-		medianQuery += ` AND filter_group_id = $5`
-		medianParams = append(medianParams, *filterGroupID)
-	}
+	medianQuery += conditions
+	medianParams := append([]interface{}{siteID, source, dateFrom, dateTo}, conditionParams...)
 	if err := r.db.Raw(medianQuery, medianParams...).Scan(&medianPosition).Error; err != nil {
 		medianPosition = 0
 	}
@@ -663,13 +805,8 @@ func (r *positionRepository) GetPositionStatistics(siteID int, source string, da
 			  AND date >= $3::date AND date <= $4::date
 			  AND date >= CURRENT_DATE - INTERVAL '30 days'
 	`
-	trendsParams := []interface{}{siteID, source, dateFrom, dateTo}
-	paramIndex := 5
-	if filterGroupID != nil {
-		trendsQuery += ` AND filter_group_id = $` + fmt.Sprintf("%d", paramIndex)
-		trendsParams = append(trendsParams, *filterGroupID)
-		paramIndex++
-	}
+	trendsQuery += conditions
+	trendsParams := append([]interface{}{siteID, source, dateFrom, dateTo}, conditionParams...)
 	trendsQuery += `
 		),
 		first_ranks AS (
@@ -730,10 +867,116 @@ func (r *positionRepository) GetPositionStatistics(siteID int, source string, da
 		Stable:   trends.Stable,
 	}
 
+	if err := r.mergeRollupStatistics(&stats, siteID, competitorID, source, dateFrom, dateTo, filterGroupID, tags); err != nil {
+		return nil, err
+	}
+
 	return &stats, nil
 }
 
-func (r *positionRepository) GetPositionsHistoryPaginated(siteID int, keywordID *int, source *string, dateFrom, dateTo *time.Time, last bool, page, perPage int) ([]*entities.Position, int64, error) {
+// mergeRollupStatistics добавляет к статистике агрегаты старых позиций, если период их захватывает.
+// Счетчики и средняя позиция остаются точными, медиана по свернутой части считается
+// по последней позиции каждого дня или недели. Тренды строятся по последним 30 дням и агрегаты не используют
+func (r *positionRepository) mergeRollupStatistics(stats *entities.PositionStatistics, siteID int, competitorID *int, source string, dateFrom, dateTo time.Time, filterGroupID *int, tags *entities.TagFilter) error {
+	needed, err := r.rollupsBefore(siteID, &dateFrom)
+	if err != nil || !needed {
+		return err
+	}
+
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("source = ?", source).Scopes(byCompetitor(competitorID))
+		if filterGroupID != nil {
+			db = db.Where("filter_group_id = ?", *filterGroupID)
+		}
+		if tags != nil {
+			db = db.Where(keywordTagsCondition("keyword_id", tags))
+		}
+		return db
+	}
+	rollups := func() *gorm.DB {
+		return r.rollupQuery(siteID, &dateFrom, &dateTo).Scopes(scope)
+	}
+	details := func() *gorm.DB {
+		return r.db.Model(&positionModels.Position{}).
+			Where("site_id = ? AND date >= CAST(? AS date) AND date <= CAST(? AS date)", siteID, dateFrom, dateTo).
+			Scopes(scope)
+	}
+
+	var totals struct {
+		Samples        int   `gorm:"column:samples"`
+		VisibleSamples int   `gorm:"column:visible_samples"`
+		RankSum        int64 `gorm:"column:rank_sum"`
+		BestRank       *int  `gorm:"column:best_rank"`
+		WorstRank      *int  `gorm:"column:worst_rank"`
+		Range1_3       int   `gorm:"column:range_1_3"`
+		Range4_10      int   `gorm:"column:range_4_10"`
+		Range11_30     int   `gorm:"column:range_11_30"`
+		Range31_50     int   `gorm:"column:range_31_50"`
+		Range51_100    int   `gorm:"column:range_51_100"`
+		Range100Plus   int   `gorm:"column:range_100_plus"`
+	}
+	if err := rollups().Select(`
+		COALESCE(SUM(samples), 0) AS samples,
+		COALESCE(SUM(visible_samples), 0) AS visible_samples,
+		COALESCE(SUM(rank_sum), 0) AS rank_sum,
+		MIN(best_rank) AS best_rank,
+		MAX(worst_rank) AS worst_rank,
+		COALESCE(SUM(range_1_3), 0) AS range_1_3,
+		COALESCE(SUM(range_4_10), 0) AS range_4_10,
+		COALESCE(SUM(range_11_30), 0) AS range_11_30,
+		COALESCE(SUM(range_31_50), 0) AS range_31_50,
+		COALESCE(SUM(range_51_100), 0) AS range_51_100,
+		COALESCE(SUM(range_100_plus), 0) AS range_100_plus
+	`).Scan(&totals).Error; err != nil {
+		return err
+	}
+	if totals.Samples == 0 {
+		return nil
+	}
+
+	var keywordsCount int
+	if err := r.db.Raw("SELECT COUNT(*) FROM (? UNION ?) AS keywords",
+		details().Select("keyword_id"), rollups().Select("keyword_id")).Scan(&keywordsCount).Error; err != nil {
+		return err
+	}
+
+	var medianPosition float64
+	if err := r.db.Raw("SELECT COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY rank), 0) FROM (? UNION ALL ?) AS ranks",
+		details().Select("rank").Where("rank > 0"), rollups().Select("rank").Where("rank > 0")).Scan(&medianPosition).Error; err != nil {
+		return err
+	}
+
+	visible := stats.Visible + totals.VisibleSamples
+	if visible > 0 {
+		rankSum := stats.VisibilityStats.AvgPosition*float64(stats.Visible) + float64(totals.RankSum)
+		stats.VisibilityStats.AvgPosition = math.Round(rankSum/float64(visible)*100) / 100
+	}
+	if totals.BestRank != nil && (stats.VisibilityStats.BestPosition == 0 || *totals.BestRank < stats.VisibilityStats.BestPosition) {
+		stats.VisibilityStats.BestPosition = *totals.BestRank
+	}
+	if totals.WorstRank != nil && *totals.WorstRank > stats.VisibilityStats.WorstPosition {
+		stats.VisibilityStats.WorstPosition = *totals.WorstRank
+	}
+	stats.VisibilityStats.MedianPosition = int(medianPosition)
+
+	notVisible := totals.Samples - totals.VisibleSamples
+	stats.TotalPositions += totals.Samples
+	stats.KeywordsCount = keywordsCount
+	stats.Visible = visible
+	stats.NotVisible += notVisible
+
+	stats.PositionRanges.Range1_3 += totals.Range1_3
+	stats.PositionRanges.Range4_10 += totals.Range4_10
+	stats.PositionRanges.Range11_30 += totals.Range11_30
+	stats.PositionRanges.Range31_50 += totals.Range31_50
+	stats.PositionRanges.Range51_100 += totals.Range51_100
+	stats.PositionRanges.Range100Plus += totals.Range100Plus
+	stats.PositionRanges.NotFound += notVisible
+
+	return nil
+}
+
+func (r *positionRepository) GetPositionsHistoryPaginated(siteID int, keywordID *int, tags *entities.TagFilter, source *string, dateFrom, dateTo *time.Time, last bool, page, perPage int) ([]*entities.Position, int64, error) {
 	var positions []*entities.Position
 	var total int64
 	var err error
@@ -788,21 +1031,30 @@ func (r *positionRepository) GetPositionsHistoryPaginated(siteID int, keywordID
 			}
 			total = int64(len(positions))
 		}
+
+		if tags != nil {
+			positions, err = r.filterByTags(siteID, positions, tags)
+			if err != nil {
+				return nil, 0, err
+			}
+			total = int64(len(positions))
+		}
+	} else {
 		var query *gorm.DB
 		var countQuery *gorm.DB
 
 		if keywordID != nil && source != nil {
-			query = r.db.Where("keyword_id = ? AND site_id = ? AND source = ?", *keywordID, siteID, *source)
-			countQuery = r.db.Model(&positionModels.Position{}).Where("keyword_id = ? AND site_id = ? AND source = ?", *keywordID, siteID, *source)
+			query = r.db.Where("keyword_id = ? AND site_id = ? AND source = ? AND competitor_id IS NULL", *keywordID, siteID, *source)
+			countQuery = r.db.Model(&positionModels.Position{}).Where("keyword_id = ? AND site_id = ? AND source = ? AND competitor_id IS NULL", *keywordID, siteID, *source)
 		} else if keywordID != nil {
-			query = r.db.Where("keyword_id = ? AND site_id = ?", *keywordID, siteID)
-			countQuery = r.db.Model(&positionModels.Position{}).Where("keyword_id = ? AND site_id = ?", *keywordID, siteID)
+			query = r.db.Where("keyword_id = ? AND site_id = ? AND competitor_id IS NULL", *keywordID, siteID)
+			countQuery = r.db.Model(&positionModels.Position{}).Where("keyword_id = ? AND site_id = ? AND competitor_id IS NULL", *keywordID, siteID)
 		} else if source != nil {
-			query = r.db.Where("site_id = ? AND source = ?", siteID, *source)
-			countQuery = r.db.Model(&positionModels.Position{}).Where("site_id = ? AND source = ?", siteID, *source)
+			query = r.db.Where("site_id = ? AND source = ? AND competitor_id IS NULL", siteID, *source)
+			countQuery = r.db.Model(&positionModels.Position{}).Where("site_id = ? AND source = ? AND competitor_id IS NULL", siteID, *source)
 		} else {
-			query = r.db.Where("site_id = ?", siteID)
-			countQuery = r.db.Model(&positionModels.Position{}).Where("site_id = ?", siteID)
+			query = r.db.Where("site_id = ? AND competitor_id IS NULL", siteID)
+			countQuery = r.db.Model(&positionModels.Position{}).Where("site_id = ? AND competitor_id IS NULL", siteID)
 		}
 
 		if dateFrom != nil {
@@ -813,6 +1065,10 @@ func (r *positionRepository) GetPositionsHistoryPaginated(siteID int, keywordID
 			query = query.Where("date <= ?", *dateTo)
 			countQuery = countQuery.Where("date <= ?", *dateTo)
 		}
+		if tags != nil {
+			query = query.Where(keywordTagsCondition("keyword_id", tags))
+			countQuery = countQuery.Where(keywordTagsCondition("keyword_id", tags))
+		}
 
 		if err := countQuery.Count(&total).Error; err != nil {
 			return nil, 0, err
@@ -831,295 +1087,256 @@ func (r *positionRepository) GetPositionsHistoryPaginated(siteID int, keywordID
 		for i, model := range models {
 			positions[i] = r.toDomain(&model)
 		}
-	}
-
-	return positions, total, nil
-}
-
-func (r *positionRepository) GetCombinedPositionsPaginated(siteID int, source *string, includeWordstat bool, wordstatSort bool, dateFrom, dateTo, dateSort *time.Time, sortType string, rankFrom, rankTo *int, groupID *int, filterGroupID *int, wordstatQueryType *string, page, perPage int) ([]*entities.CombinedPosition, int64, error) {
-	offset := (page - 1) * perPage
 
-	var allKeywords []positionModels.Keyword
-	query := r.db.Where("site_id = ?", siteID)
-
-	if groupID != nil {
-		query = query.Where("group_id = ?", *groupID)
-	}
-
-	if err := query.Order("id").Find(&allKeywords).Error; err != nil {
-		return nil, 0, err
-	}
-
-	if len(allKeywords) == 0 {
-		return []*entities.CombinedPosition{}, 0, nil
-	}
-
-	hasPositionsWithFilters := func(keywordID int) bool {
-		positionQuery := r.db.Where("site_id = ? AND keyword_id = ? AND source != ?", siteID, keywordID, "wordstat")
-
-		if source != nil {
-			if *source == "google" {
-				positionQuery = positionQuery.Where("source = ?", "google")
-			} else if *source == "yandex" {
-				positionQuery = positionQuery.Where("source = ?", "yandex")
-			}
-		}
-
-		if dateFrom != nil {
-			positionQuery = positionQuery.Where("date >= ?", *dateFrom)
-		}
-		if dateTo != nil {
-			positionQuery = positionQuery.Where("date <= ?", *dateTo)
-		}
-
-		if rankFrom != nil {
-			positionQuery = positionQuery.Where("rank >= ?", *rankFrom)
-		}
-		if rankTo != nil {
-			positionQuery = positionQuery.Where("rank <= ?", *rankTo)
-		}
-
-		if filterGroupID != nil {
-			positionQuery = positionQuery.Where("filter_group_id = ?", *filterGroupID)
-		}
-
-		var count int64
-		positionQuery.Model(&positionModels.Position{}).Count(&count)
-		return count > 0
-	}
-
-	type keywordWithPosition struct {
-		keyword  positionModels.Keyword
-		position int
-	}
-
-	var keywordsWithPositions []keywordWithPosition
-
-	// Фильтруем keywords по наличию позиций с учетом фильтров (включая rank)
-	// и собираем информацию о позициях для сортировки
-	for _, keyword := range allKeywords {
-		keywordID := keyword.ID
-
-		// Проверяем наличие позиций с учетом всех фильтров
-		if !hasPositionsWithFilters(keywordID) {
-			continue
+		// Агрегаты старше любой детальной позиции, поэтому страницы продолжаются ими после детальных записей
+		needed, err := r.rollupsBefore(siteID, dateFrom)
+		if err != nil {
+			return nil, 0, err
 		}
-
-		var position int = 0
-
-		if wordstatSort {
-			wordstatQuery := r.db.Where("site_id = ? AND keyword_id = ? AND source = ?", siteID, keywordID, "wordstat")
-
-			if dateFrom != nil {
-				wordstatQuery = wordstatQuery.Where("date >= ?", *dateFrom)
-			}
-			if dateTo != nil {
-				wordstatQuery = wordstatQuery.Where("date <= ?", *dateTo)
-			}
-
-			if wordstatQueryType != nil {
-				wordstatQuery = wordstatQuery.Where("wordstat_query_type = ?", *wordstatQueryType)
-			} else {
-				wordstatQuery = wordstatQuery.Where("wordstat_query_type = ?", "default")
+		if needed {
+			rollups := func() *gorm.DB {
+				query := r.rollupQuery(siteID, dateFrom, dateTo).Where("competitor_id IS NULL")
+				if keywordID != nil {
+					query = query.Where("keyword_id = ?", *keywordID)
+				}
+				if source != nil {
+					query = query.Where("source = ?", *source)
+				}
+				if tags != nil {
+					query = query.Where(keywordTagsCondition("keyword_id", tags))
+				}
+				return query
 			}
 
-			var wordstatModel positionModels.Position
-			if err := wordstatQuery.Order("date DESC").First(&wordstatModel).Error; err == nil {
-				position = wordstatModel.Rank
+			var rollupTotal int64
+			if err := rollups().Count(&rollupTotal).Error; err != nil {
+				return nil, 0, err
 			}
-		} else if dateSort != nil {
-			positionQuery := r.db.Where("site_id = ? AND keyword_id = ? AND source != ? AND DATE(date) = ?",
-				siteID, keywordID, "wordstat", dateSort.Format("2006-01-02"))
 
-			if filterGroupID != nil {
-				positionQuery = positionQuery.Where("filter_group_id = ?", *filterGroupID)
+			rollupOffset := offset - int(total)
+			if rollupOffset < 0 {
+				rollupOffset = 0
 			}
-
-			if source != nil {
-				if *source == "google" {
-					positionQuery = positionQuery.Where("source = ?", "google")
-				} else if *source == "yandex" {
-					positionQuery = positionQuery.Where("source = ?", "yandex")
+			if limit := perPage - len(positions); limit > 0 && rollupTotal > 0 {
+				var models []positionModels.PositionRollup
+				if err := rollups().Preload("Keyword").
+					Order("period_start DESC, keyword_id").
+					Offset(rollupOffset).
+					Limit(limit).
+					Find(&models).Error; err != nil {
+					return nil, 0, err
 				}
-
-				var positionModel positionModels.Position
-				if err := positionQuery.Order("date DESC").First(&positionModel).Error; err == nil {
-					position = positionModel.Rank
-				}
-			} else {
-				positionQuery = positionQuery.Where("source IN ?", []string{"google", "yandex"})
-				var positions []positionModels.Position
-				if err := positionQuery.Order("rank ASC").Find(&positions).Error; err == nil {
-					if len(positions) > 0 {
-						position = positions[0].Rank
-					}
+				for i := range models {
+					positions = append(positions, r.rollupToDomain(&models[i]))
 				}
 			}
-		}
 
-		keywordsWithPositions = append(keywordsWithPositions, keywordWithPosition{
-			keyword:  keyword,
-			position: position,
-		})
+			total += rollupTotal
+		}
 	}
 
-	// Применяем сортировку, если нужно
-	if wordstatSort || dateSort != nil {
-		sort.Slice(keywordsWithPositions, func(i, j int) bool {
-			posI := keywordsWithPositions[i].position
-			posJ := keywordsWithPositions[j].position
-
-			if posI == 0 && posJ == 0 {
-				return false
-			}
-			if posI == 0 {
-				return false
-			}
-			if posJ == 0 {
-				return true
-			}
+	return positions, total, nil
+}
 
-			if sortType == "asc" {
-				return posI < posJ
-			} else {
-				return posI > posJ
-			}
-		})
+// filterByTags оставляет позиции слов сайта, подходящих под отбор по меткам
+func (r *positionRepository) filterByTags(siteID int, positions []*entities.Position, tags *entities.TagFilter) ([]*entities.Position, error) {
+	var keywordIDs []int
+	if err := r.db.Model(&positionModels.Keyword{}).
+		Where("site_id = ?", siteID).
+		Where(keywordTagsCondition("id", tags)).
+		Pluck("id", &keywordIDs).Error; err != nil {
+		return nil, err
 	}
 
-	// Подсчитываем total после фильтрации
-	total := int64(len(keywordsWithPositions))
-
-	// Применяем пагинацию к отфильтрованным keywords
-	start := offset
-	end := offset + perPage
-	if start > len(keywordsWithPositions) {
-		start = len(keywordsWithPositions)
+	tagged := make(map[int]bool, len(keywordIDs))
+	for _, id := range keywordIDs {
+		tagged[id] = true
 	}
-	if end > len(keywordsWithPositions) {
-		end = len(keywordsWithPositions)
-	}
-
-	var keywords []positionModels.Keyword
-	if start < end {
-		for i := start; i < end; i++ {
-			keywords = append(keywords, keywordsWithPositions[i].keyword)
+	filtered := make([]*entities.Position, 0, len(positions))
+	for _, position := range positions {
+		if tagged[position.KeywordID] {
+			filtered = append(filtered, position)
 		}
 	}
+	return filtered, nil
+}
 
-	if len(keywords) == 0 {
-		return []*entities.CombinedPosition{}, total, nil
+// GetCombinedPositionsPaginated возвращает страницу ключевых слов сайта с их позициями в google/yandex и частотой wordstat.
+// Ключевое слово попадает в выборку, если у него есть хотя бы одна позиция под фильтры. Фильтрация, сортировка
+// и пагинация выполняются одним запросом, позиции страницы загружаются вторым
+func (r *positionRepository) GetCombinedPositionsPaginated(siteID int, competitorID *int, source *string, includeWordstat bool, wordstatSort bool, dateFrom, dateTo, dateSort *time.Time, sortType string, rankFrom, rankTo *int, groupID *int, tags *entities.TagFilter, filterGroupID *int, wordstatQueryType *string, page, perPage int) ([]*entities.CombinedPosition, int64, error) {
+	if page <= 0 {
+		page = 1
 	}
+	offset := (page - 1) * perPage
 
-	keywordMap := make(map[int]*entities.Keyword)
-	for _, kw := range keywords {
-		keywordMap[kw.ID] = &entities.Keyword{
-			ID:     kw.ID,
-			Value:  kw.Value,
-			SiteID: kw.SiteID,
+	// Источник сужает выборку, только если это google или yandex
+	searchSource := func(query *gorm.DB) *gorm.DB {
+		if source != nil && (*source == "google" || *source == "yandex") {
+			return query.Where("source = ?", *source)
 		}
+		return query
 	}
-
-	var allCombinedPositions []*entities.CombinedPosition
-
-	for _, keyword := range keywords {
-		keywordID := keyword.ID
-
-		var positions []positionModels.Position
-
-		query := r.db.Where("site_id = ? AND keyword_id = ? AND source != ?", siteID, keywordID, "wordstat")
-
-		if source != nil {
-			if *source == "google" {
-				query = query.Where("source = ?", "google")
-			} else if *source == "yandex" {
-				query = query.Where("source = ?", "yandex")
-			}
+	byFilterGroup := func(query *gorm.DB) *gorm.DB {
+		if filterGroupID != nil {
+			return query.Where("filter_group_id = ?", *filterGroupID)
 		}
-
+		return query
+	}
+	inDateRange := func(query *gorm.DB) *gorm.DB {
 		if dateFrom != nil {
 			query = query.Where("date >= ?", *dateFrom)
 		}
 		if dateTo != nil {
 			query = query.Where("date <= ?", *dateTo)
 		}
+		return query
+	}
 
+	filtered := func() *gorm.DB {
+		query := r.db.Model(&positionModels.Position{}).
+			Where("site_id = ? AND source != ?", siteID, "wordstat").
+			Scopes(byCompetitor(competitorID), searchSource, inDateRange, byFilterGroup)
 		if rankFrom != nil {
 			query = query.Where("rank >= ?", *rankFrom)
 		}
 		if rankTo != nil {
 			query = query.Where("rank <= ?", *rankTo)
 		}
-
-		if filterGroupID != nil {
-			query = query.Where("filter_group_id = ?", *filterGroupID)
+		return query
+	}
+	wordstat := func() *gorm.DB {
+		queryType := "default"
+		if wordstatQueryType != nil {
+			queryType = *wordstatQueryType
 		}
+		return r.db.Model(&positionModels.Position{}).
+			Where("site_id = ? AND source = ? AND wordstat_query_type = ?", siteID, "wordstat", queryType).
+			Scopes(inDateRange)
+	}
 
-		if err := query.Order("date DESC").Find(&positions).Error; err != nil {
-			return nil, 0, err
-		}
+	keywords := r.db.Model(&positionModels.Keyword{}).
+		Select("id, value, site_id").
+		Where("site_id = ?", siteID).
+		Where("id IN (?)", filtered().Select("keyword_id"))
+	if groupID != nil {
+		keywords = keywords.Where("group_id = ?", *groupID)
+	}
+	if tags != nil {
+		keywords = keywords.Where(keywordTagsCondition("id", tags))
+	}
 
-		var googleYandexPositions []*entities.Position
-		for _, model := range positions {
-			position := r.toDomain(&model)
-			googleYandexPositions = append(googleYandexPositions, position)
+	// Ключ сортировки: последняя частота wordstat или позиция на дату dateSort
+	var sortKeys *gorm.DB
+	if wordstatSort {
+		sortKeys = wordstat().Select("DISTINCT ON (keyword_id) keyword_id, rank").Order("keyword_id, date DESC")
+	} else if dateSort != nil {
+		onDate := r.db.Model(&positionModels.Position{}).
+			Where("site_id = ? AND source != ? AND DATE(date) = ?", siteID, "wordstat", dateSort.Format("2006-01-02")).
+			Scopes(byCompetitor(competitorID), byFilterGroup)
+		if source != nil {
+			sortKeys = onDate.Scopes(searchSource).
+				Select("DISTINCT ON (keyword_id) keyword_id, rank").
+				Order("keyword_id, date DESC")
+		} else {
+			sortKeys = onDate.Where("source IN ?", []string{"google", "yandex"}).
+				Select("keyword_id, MIN(rank) AS rank").
+				Group("keyword_id")
 		}
+	}
 
-		var wordstatPosition *entities.Position
-
-		if includeWordstat {
-			var wordstatModel positionModels.Position
-			wordstatQuery := r.db.Where("site_id = ? AND keyword_id = ? AND source = ?", siteID, keywordID, "wordstat")
-
-			if dateFrom != nil {
-				wordstatQuery = wordstatQuery.Where("date >= ?", *dateFrom)
-			}
-			if dateTo != nil {
-				wordstatQuery = wordstatQuery.Where("date <= ?", *dateTo)
-			}
+	pageQuery := r.db.Table("(?) AS k", keywords).Select("k.id, k.value, k.site_id, COUNT(*) OVER () AS total")
+	if sortKeys != nil {
+		direction := "DESC"
+		if sortType == "asc" {
+			direction = "ASC"
+		}
+		// Ключевые слова без значения (0 - нет данных) идут последними при любом направлении
+		pageQuery = pageQuery.Joins("LEFT JOIN (?) AS s ON s.keyword_id = k.id", sortKeys).
+			Order("COALESCE(s.rank, 0) = 0").
+			Order("s.rank " + direction)
+	}
 
-			if wordstatQueryType != nil {
-				wordstatQuery = wordstatQuery.Where("wordstat_query_type = ?", *wordstatQueryType)
-			} else {
-				wordstatQuery = wordstatQuery.Where("wordstat_query_type = ?", "default")
-			}
+	var rows []struct {
+		ID     int
+		Value  string
+		SiteID int
+		Total  int64
+	}
+	if err := pageQuery.Order("k.id").Offset(offset).Limit(perPage).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
 
-			if err := wordstatQuery.Order("date DESC").First(&wordstatModel).Error; err == nil {
-				wordstatPosition = r.toDomain(&wordstatModel)
+	if len(rows) == 0 {
+		// За пределами последней страницы окно не вернет total, считаем отдельно
+		var total int64
+		if offset > 0 {
+			if err := r.db.Table("(?) AS k", keywords).Count(&total).Error; err != nil {
+				return nil, 0, err
 			}
 		}
+		return []*entities.CombinedPosition{}, total, nil
+	}
+	total := rows[0].Total
 
-		// Теперь эта проверка не должна срабатывать, так как мы уже отфильтровали keywords
-		// Но оставляем для безопасности
-		if len(googleYandexPositions) == 0 {
-			continue
-		}
+	keywordIDs := make([]int, len(rows))
+	for i, row := range rows {
+		keywordIDs[i] = row.ID
+	}
 
-		var latestDate time.Time
-		if len(positions) > 0 {
-			latestDate = positions[0].Date
+	var models []positionModels.Position
+	positionsQuery := filtered().Select("*").Where("keyword_id IN ?", keywordIDs)
+	if includeWordstat {
+		latestWordstat := wordstat().
+			Select("DISTINCT ON (keyword_id) *").
+			Where("keyword_id IN ?", keywordIDs).
+			Order("keyword_id, date DESC")
+		if err := r.db.Raw("(?) UNION ALL (?) ORDER BY keyword_id, date DESC", positionsQuery, latestWordstat).
+			Scan(&models).Error; err != nil {
+			return nil, 0, err
 		}
+	} else if err := positionsQuery.Order("keyword_id, date DESC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
 
+	combinedByKeyword := make(map[int]*entities.CombinedPosition, len(rows))
+	combinedPositions := make([]*entities.CombinedPosition, len(rows))
+	for i, row := range rows {
 		combined := &entities.CombinedPosition{
-			ID:        keywordID,
+			ID:        row.ID,
 			SiteID:    siteID,
-			KeywordID: keywordID,
-			Keyword:   keywordMap[keywordID],
-			Date:      latestDate,
-			Positions: googleYandexPositions,
-			Wordstat:  wordstatPosition,
+			KeywordID: row.ID,
+			Keyword: &entities.Keyword{
+				ID:     row.ID,
+				Value:  row.Value,
+				SiteID: row.SiteID,
+			},
 		}
+		combinedByKeyword[row.ID] = combined
+		combinedPositions[i] = combined
+	}
 
-		allCombinedPositions = append(allCombinedPositions, combined)
+	for i := range models {
+		combined := combinedByKeyword[models[i].KeywordID]
+		position := r.toDomain(&models[i])
+		if position.Source == "wordstat" {
+			combined.Wordstat = position
+			continue
+		}
+		// Позиции отсортированы по убыванию даты, первая - самая свежая
+		if len(combined.Positions) == 0 {
+			combined.Date = position.Date
+		}
+		combined.Positions = append(combined.Positions, position)
 	}
 
-	return allCombinedPositions, total, nil
+	return combinedPositions, total, nil
 }
 
 func (r *positionRepository) GetLastUpdateDateBySiteIDExcludingSource(siteID int, excludeSource string) (*time.Time, error) {
 	var model positionModels.Position
 
-	err := r.db.Where("site_id = ? AND source != ?", siteID, excludeSource).
+	err := r.db.Where("site_id = ? AND source != ? AND competitor_id IS NULL", siteID, excludeSource).
 		Order("date DESC").
 		First(&model).Error
 
@@ -0,0 +1,548 @@
+package repositories
+
+import (
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/infrastructure/database/migrations"
+	positionModels "go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Тесты и бенчмарки этого файла работают с настоящей PostgreSQL и пропускаются без TEST_DATABASE_DSN:
+//
+//	TEST_DATABASE_DSN="host=localhost user=postgres password=password dbname=go_seo_test sslmode=disable" \
+//		go test -run Combined -bench Combined ./internal/infrastructure/database/postgres/repositories/
+//
+// Миграции применяются к указанной БД, тестовые данные создаются в транзакции и откатываются
+
+const (
+	benchKeywords = 2000
+	benchDays     = 30
+)
+
+func openTestDB(tb testing.TB) *gorm.DB {
+	tb.Helper()
+
+	db := connectTestDB(tb)
+	tx := db.Begin()
+	if tx.Error != nil {
+		tb.Fatalf("begin: %v", tx.Error)
+	}
+	tb.Cleanup(func() {
+		tx.Rollback()
+	})
+	return tx
+}
+
+// connectTestDB подключается к тестовой БД и применяет миграции. Данные, записанные без транзакции
+// openTestDB, тест удаляет сам
+func connectTestDB(tb testing.TB) *gorm.DB {
+	tb.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		tb.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		tb.Fatalf("connect: %v", err)
+	}
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		tb.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(0); err != nil {
+		tb.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// seedCombinedPositions создает сайт с keywords ключевыми словами: половина в группе, у каждого десятого нет позиций.
+// Остальные получают позиции google и yandex за days дней (часть не найдена) и еженедельную частоту wordstat
+func seedCombinedPositions(tb testing.TB, tx *gorm.DB, keywords, days int) (int, int) {
+	tb.Helper()
+
+	var siteID, groupID int
+	steps := []func() error{
+		func() error {
+			return tx.Raw("INSERT INTO sites (workspace_id, domain, created_at, updated_at) VALUES (1, ?, NOW(), NOW()) RETURNING id",
+				"bench.example").Scan(&siteID).Error
+		},
+		func() error {
+			return tx.Raw("INSERT INTO groups (name, site_id, created_at, updated_at) VALUES (?, ?, NOW(), NOW()) RETURNING id",
+				"bench", siteID).Scan(&groupID).Error
+		},
+		func() error {
+			return tx.Exec(`
+				INSERT INTO keywords (value, site_id, group_id, created_at, updated_at)
+				SELECT 'keyword ' || n, ?, CASE WHEN n % 2 = 0 THEN ?::bigint END, NOW(), NOW()
+				FROM generate_series(1, ?) AS n`, siteID, groupID, keywords).Error
+		},
+		func() error {
+			return tx.Exec(`
+				INSERT INTO positions (keyword_id, site_id, rank, url, title, source, device, ads, pages, date,
+					wordstat_query_type, created_at, updated_at)
+				SELECT k.id, k.site_id,
+					CASE WHEN (k.id + d) % 7 = 0 THEN 0 ELSE 1 + (k.id * 31 + d * 17 + length(s)) % 120 END,
+					'https://bench.example/' || k.id, 'title', s, 'desktop', false, 10,
+					CURRENT_DATE - d + INTERVAL '12 hours', '', NOW(), NOW()
+				FROM keywords k
+				CROSS JOIN generate_series(0, ? - 1) AS d
+				CROSS JOIN unnest(ARRAY['google', 'yandex']) AS s
+				WHERE k.site_id = ? AND k.id % 10 <> 0`, days, siteID).Error
+		},
+		func() error {
+			return tx.Exec(`
+				INSERT INTO positions (keyword_id, site_id, rank, url, title, source, device, ads, pages, date,
+					wordstat_query_type, created_at, updated_at)
+				SELECT k.id, k.site_id, (k.id * 97 + d) % 5000, '', '', 'wordstat', '', false, 0,
+					CURRENT_DATE - d * 7 + INTERVAL '6 hours', 'default', NOW(), NOW()
+				FROM keywords k
+				CROSS JOIN generate_series(0, ? / 7) AS d
+				WHERE k.site_id = ? AND k.id % 10 <> 0`, days, siteID).Error
+		},
+		func() error {
+			return tx.Exec("ANALYZE positions").Error
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			tb.Fatalf("seed: %v", err)
+		}
+	}
+
+	return siteID, groupID
+}
+
+type combinedCase struct {
+	name         string
+	source       *string
+	wordstat     bool
+	wordstatSort bool
+	dateSort     *time.Time
+	sortType     string
+	rankFrom     *int
+	rankTo       *int
+	inGroup      bool
+}
+
+func combinedCases() []combinedCase {
+	yandex := "yandex"
+	today := time.Now()
+	one, ten := 1, 10
+
+	return []combinedCase{
+		{name: "default"},
+		{name: "wordstat_sort", wordstat: true, wordstatSort: true, sortType: "desc"},
+		{name: "date_sort_source", source: &yandex, dateSort: &today, sortType: "asc"},
+		{name: "date_sort_all_sources", dateSort: &today, sortType: "desc"},
+		{name: "top10_in_group", wordstat: true, rankFrom: &one, rankTo: &ten, inGroup: true},
+	}
+}
+
+type combinedFunc func(r *positionRepository, siteID int, competitorID *int, source *string, includeWordstat bool, wordstatSort bool, dateFrom, dateTo, dateSort *time.Time, sortType string, rankFrom, rankTo *int, groupID *int, filterGroupID *int, wordstatQueryType *string, page, perPage int) ([]*entities.CombinedPosition, int64, error)
+
+func (c combinedCase) run(r *positionRepository, fn combinedFunc, siteID, groupID, page, perPage int) ([]*entities.CombinedPosition, int64, error) {
+	var group *int
+	if c.inGroup {
+		group = &groupID
+	}
+	return fn(r, siteID, nil, c.source, c.wordstat, c.wordstatSort, nil, nil, c.dateSort, c.sortType, c.rankFrom, c.rankTo, group, nil, nil, page, perPage)
+}
+
+func setBased(r *positionRepository, siteID int, competitorID *int, source *string, includeWordstat bool, wordstatSort bool, dateFrom, dateTo, dateSort *time.Time, sortType string, rankFrom, rankTo *int, groupID *int, filterGroupID *int, wordstatQueryType *string, page, perPage int) ([]*entities.CombinedPosition, int64, error) {
+	return r.GetCombinedPositionsPaginated(siteID, competitorID, source, includeWordstat, wordstatSort, dateFrom, dateTo, dateSort, sortType, rankFrom, rankTo, groupID, nil, filterGroupID, wordstatQueryType, page, perPage)
+}
+
+func TestCombinedPositionsMatchesPerKeyword(t *testing.T) {
+	tx := openTestDB(t)
+	siteID, groupID := seedCombinedPositions(t, tx, 200, 10)
+	repo := &positionRepository{db: tx}
+
+	for _, c := range combinedCases() {
+		t.Run(c.name, func(t *testing.T) {
+			got, total, err := c.run(repo, setBased, siteID, groupID, 1, 1000)
+			if err != nil {
+				t.Fatalf("set based: %v", err)
+			}
+			want, wantTotal, err := c.run(repo, perKeywordCombinedPositions, siteID, groupID, 1, 1000)
+			if err != nil {
+				t.Fatalf("per keyword: %v", err)
+			}
+
+			if total != wantTotal || len(got) != len(want) {
+				t.Fatalf("total = %d (%d rows), want %d (%d rows)", total, len(got), wantTotal, len(want))
+			}
+
+			// Старая реализация сортирует нестабильно, поэтому при равных ключах порядок сравнивается только без сортировки
+			wantByKeyword := make(map[int]*entities.CombinedPosition, len(want))
+			for _, combined := range want {
+				wantByKeyword[combined.KeywordID] = combined
+			}
+			for i, combined := range got {
+				if c.dateSort == nil && !c.wordstatSort && combined.KeywordID != want[i].KeywordID {
+					t.Fatalf("row %d: keyword %d, want %d", i, combined.KeywordID, want[i].KeywordID)
+				}
+
+				expected, ok := wantByKeyword[combined.KeywordID]
+				if !ok {
+					t.Fatalf("unexpected keyword %d", combined.KeywordID)
+				}
+				if len(combined.Positions) != len(expected.Positions) || !combined.Date.Equal(expected.Date) {
+					t.Fatalf("keyword %d: %d positions at %s, want %d at %s", combined.KeywordID,
+						len(combined.Positions), combined.Date, len(expected.Positions), expected.Date)
+				}
+				if (combined.Wordstat == nil) != (expected.Wordstat == nil) ||
+					(combined.Wordstat != nil && combined.Wordstat.Rank != expected.Wordstat.Rank) {
+					t.Fatalf("keyword %d: wordstat differs", combined.KeywordID)
+				}
+			}
+
+			// Страницы по 7 записей должны складываться в ту же выборку
+			var paged []int
+			for page := 1; ; page++ {
+				rows, pageTotal, err := c.run(repo, setBased, siteID, groupID, page, 7)
+				if err != nil {
+					t.Fatalf("page %d: %v", page, err)
+				}
+				if pageTotal != total {
+					t.Fatalf("page %d: total = %d, want %d", page, pageTotal, total)
+				}
+				if len(rows) == 0 {
+					break
+				}
+				for _, row := range rows {
+					paged = append(paged, row.KeywordID)
+				}
+			}
+			if len(paged) != len(got) {
+				t.Fatalf("pages returned %d keywords, want %d", len(paged), len(got))
+			}
+			for i := range paged {
+				if paged[i] != got[i].KeywordID {
+					t.Fatalf("paged row %d: keyword %d, want %d", i, paged[i], got[i].KeywordID)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCombinedPositions(b *testing.B) {
+	tx := openTestDB(b)
+	siteID, groupID := seedCombinedPositions(b, tx, benchKeywords, benchDays)
+	repo := &positionRepository{db: tx}
+
+	implementations := []struct {
+		name string
+		fn   combinedFunc
+	}{
+		{name: "set_based", fn: setBased},
+		{name: "per_keyword", fn: perKeywordCombinedPositions},
+	}
+
+	for _, c := range combinedCases() {
+		for _, impl := range implementations {
+			b.Run(c.name+"/"+impl.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, _, err := c.run(repo, impl.fn, siteID, groupID, 3, 50); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// perKeywordCombinedPositions прежняя реализация с запросами на каждое ключевое слово;
+// оставлена для сравнения результатов и скорости
+func perKeywordCombinedPositions(r *positionRepository, siteID int, competitorID *int, source *string, includeWordstat bool, wordstatSort bool, dateFrom, dateTo, dateSort *time.Time, sortType string, rankFrom, rankTo *int, groupID *int, filterGroupID *int, wordstatQueryType *string, page, perPage int) ([]*entities.CombinedPosition, int64, error) {
+	offset := (page - 1) * perPage
+
+	var allKeywords []positionModels.Keyword
+	query := r.db.Where("site_id = ?", siteID)
+
+	if groupID != nil {
+		query = query.Where("group_id = ?", *groupID)
+	}
+
+	if err := query.Order("id").Find(&allKeywords).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if len(allKeywords) == 0 {
+		return []*entities.CombinedPosition{}, 0, nil
+	}
+
+	hasPositionsWithFilters := func(keywordID int) bool {
+		positionQuery := r.db.Where("site_id = ? AND keyword_id = ? AND source != ?", siteID, keywordID, "wordstat").
+			Scopes(byCompetitor(competitorID))
+
+		if source != nil {
+			if *source == "google" {
+				positionQuery = positionQuery.Where("source = ?", "google")
+			} else if *source == "yandex" {
+				positionQuery = positionQuery.Where("source = ?", "yandex")
+			}
+		}
+
+		if dateFrom != nil {
+			positionQuery = positionQuery.Where("date >= ?", *dateFrom)
+		}
+		if dateTo != nil {
+			positionQuery = positionQuery.Where("date <= ?", *dateTo)
+		}
+
+		if rankFrom != nil {
+			positionQuery = positionQuery.Where("rank >= ?", *rankFrom)
+		}
+		if rankTo != nil {
+			positionQuery = positionQuery.Where("rank <= ?", *rankTo)
+		}
+
+		if filterGroupID != nil {
+			positionQuery = positionQuery.Where("filter_group_id = ?", *filterGroupID)
+		}
+
+		var count int64
+		positionQuery.Model(&positionModels.Position{}).Count(&count)
+		return count > 0
+	}
+
+	type keywordWithPosition struct {
+		keyword  positionModels.Keyword
+		position int
+	}
+
+	var keywordsWithPositions []keywordWithPosition
+
+	// Фильтруем keywords по наличию позиций с учетом фильтров (включая rank)
+	// и собираем информацию о позициях для сортировки
+	for _, keyword := range allKeywords {
+		keywordID := keyword.ID
+
+		// Проверяем наличие позиций с учетом всех фильтров
+		if !hasPositionsWithFilters(keywordID) {
+			continue
+		}
+
+		var position int = 0
+
+		if wordstatSort {
+			wordstatQuery := r.db.Where("site_id = ? AND keyword_id = ? AND source = ?", siteID, keywordID, "wordstat")
+
+			if dateFrom != nil {
+				wordstatQuery = wordstatQuery.Where("date >= ?", *dateFrom)
+			}
+			if dateTo != nil {
+				wordstatQuery = wordstatQuery.Where("date <= ?", *dateTo)
+			}
+
+			if wordstatQueryType != nil {
+				wordstatQuery = wordstatQuery.Where("wordstat_query_type = ?", *wordstatQueryType)
+			} else {
+				wordstatQuery = wordstatQuery.Where("wordstat_query_type = ?", "default")
+			}
+
+			var wordstatModel positionModels.Position
+			if err := wordstatQuery.Order("date DESC").First(&wordstatModel).Error; err == nil {
+				position = wordstatModel.Rank
+			}
+		} else if dateSort != nil {
+			positionQuery := r.db.Where("site_id = ? AND keyword_id = ? AND source != ? AND DATE(date) = ?",
+				siteID, keywordID, "wordstat", dateSort.Format("2006-01-02")).
+				Scopes(byCompetitor(competitorID))
+
+			if filterGroupID != nil {
+				positionQuery = positionQuery.Where("filter_group_id = ?", *filterGroupID)
+			}
+
+			if source != nil {
+				if *source == "google" {
+					positionQuery = positionQuery.Where("source = ?", "google")
+				} else if *source == "yandex" {
+					positionQuery = positionQuery.Where("source = ?", "yandex")
+				}
+
+				var positionModel positionModels.Position
+				if err := positionQuery.Order("date DESC").First(&positionModel).Error; err == nil {
+					position = positionModel.Rank
+				}
+			} else {
+				positionQuery = positionQuery.Where("source IN ?", []string{"google", "yandex"})
+				var positions []positionModels.Position
+				if err := positionQuery.Order("rank ASC").Find(&positions).Error; err == nil {
+					if len(positions) > 0 {
+						position = positions[0].Rank
+					}
+				}
+			}
+		}
+
+		keywordsWithPositions = append(keywordsWithPositions, keywordWithPosition{
+			keyword:  keyword,
+			position: position,
+		})
+	}
+
+	// Применяем сортировку, если нужно
+	if wordstatSort || dateSort != nil {
+		sort.Slice(keywordsWithPositions, func(i, j int) bool {
+			posI := keywordsWithPositions[i].position
+			posJ := keywordsWithPositions[j].position
+
+			if posI == 0 && posJ == 0 {
+				return false
+			}
+			if posI == 0 {
+				return false
+			}
+			if posJ == 0 {
+				return true
+			}
+
+			if sortType == "asc" {
+				return posI < posJ
+			} else {
+				return posI > posJ
+			}
+		})
+	}
+
+	// Подсчитываем total после фильтрации
+	total := int64(len(keywordsWithPositions))
+
+	// Применяем пагинацию к отфильтрованным keywords
+	start := offset
+	end := offset + perPage
+	if start > len(keywordsWithPositions) {
+		start = len(keywordsWithPositions)
+	}
+	if end > len(keywordsWithPositions) {
+		end = len(keywordsWithPositions)
+	}
+
+	var keywords []positionModels.Keyword
+	if start < end {
+		for i := start; i < end; i++ {
+			keywords = append(keywords, keywordsWithPositions[i].keyword)
+		}
+	}
+
+	if len(keywords) == 0 {
+		return []*entities.CombinedPosition{}, total, nil
+	}
+
+	keywordMap := make(map[int]*entities.Keyword)
+	for _, kw := range keywords {
+		keywordMap[kw.ID] = &entities.Keyword{
+			ID:     kw.ID,
+			Value:  kw.Value,
+			SiteID: kw.SiteID,
+		}
+	}
+
+	var allCombinedPositions []*entities.CombinedPosition
+
+	for _, keyword := range keywords {
+		keywordID := keyword.ID
+
+		var positions []positionModels.Position
+
+		query := r.db.Where("site_id = ? AND keyword_id = ? AND source != ?", siteID, keywordID, "wordstat").
+			Scopes(byCompetitor(competitorID))
+
+		if source != nil {
+			if *source == "google" {
+				query = query.Where("source = ?", "google")
+			} else if *source == "yandex" {
+				query = query.Where("source = ?", "yandex")
+			}
+		}
+
+		if dateFrom != nil {
+			query = query.Where("date >= ?", *dateFrom)
+		}
+		if dateTo != nil {
+			query = query.Where("date <= ?", *dateTo)
+		}
+
+		if rankFrom != nil {
+			query = query.Where("rank >= ?", *rankFrom)
+		}
+		if rankTo != nil {
+			query = query.Where("rank <= ?", *rankTo)
+		}
+
+		if filterGroupID != nil {
+			query = query.Where("filter_group_id = ?", *filterGroupID)
+		}
+
+		if err := query.Order("date DESC").Find(&positions).Error; err != nil {
+			return nil, 0, err
+		}
+
+		var googleYandexPositions []*entities.Position
+		for _, model := range positions {
+			position := r.toDomain(&model)
+			googleYandexPositions = append(googleYandexPositions, position)
+		}
+
+		var wordstatPosition *entities.Position
+
+		if includeWordstat {
+			var wordstatModel positionModels.Position
+			wordstatQuery := r.db.Where("site_id = ? AND keyword_id = ? AND source = ?", siteID, keywordID, "wordstat")
+
+			if dateFrom != nil {
+				wordstatQuery = wordstatQuery.Where("date >= ?", *dateFrom)
+			}
+			if dateTo != nil {
+				wordstatQuery = wordstatQuery.Where("date <= ?", *dateTo)
+			}
+
+			if wordstatQueryType != nil {
+				wordstatQuery = wordstatQuery.Where("wordstat_query_type = ?", *wordstatQueryType)
+			} else {
+				wordstatQuery = wordstatQuery.Where("wordstat_query_type = ?", "default")
+			}
+
+			if err := wordstatQuery.Order("date DESC").First(&wordstatModel).Error; err == nil {
+				wordstatPosition = r.toDomain(&wordstatModel)
+			}
+		}
+
+		// Теперь эта проверка не должна срабатывать, так как мы уже отфильтровали keywords
+		// Но оставляем для безопасности
+		if len(googleYandexPositions) == 0 {
+			continue
+		}
+
+		var latestDate time.Time
+		if len(positions) > 0 {
+			latestDate = positions[0].Date
+		}
+
+		combined := &entities.CombinedPosition{
+			ID:        keywordID,
+			SiteID:    siteID,
+			KeywordID: keywordID,
+			Keyword:   keywordMap[keywordID],
+			Date:      latestDate,
+			Positions: googleYandexPositions,
+			Wordstat:  wordstatPosition,
+		}
+
+		allCombinedPositions = append(allCombinedPositions, combined)
+	}
+
+	return allCombinedPositions, total, nil
+}
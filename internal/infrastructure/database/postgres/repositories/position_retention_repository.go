@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+)
+
+// retentionLockKey не дает двум экземплярам сервиса сворачивать позиции одновременно
+const retentionLockKey = 7240316
+
+const positionPartitionPrefix = "positions_p"
+
+const rollupColumns = `period, period_start, period_end, keyword_id, site_id, competitor_id, source,
+	filter_group_id, wordstat_query_type, rank, url, title, device, os, ads, country, lang, pages, date,
+	samples, visible_samples, rank_sum, best_rank, worst_rank,
+	range_1_3, range_4_10, range_11_30, range_31_50, range_51_100, range_100_plus`
+
+// Поля последней проверки берутся у более поздней записи, счетчики складываются: так повторное
+// сворачивание периода (например, после загрузки позиций задним числом) не теряет данные
+const rollupConflict = `
+ON CONFLICT (period, period_start, keyword_id, site_id, (COALESCE(competitor_id, 0)), (COALESCE(source, '')),
+	(COALESCE(filter_group_id, 0)), wordstat_query_type)
+DO UPDATE SET
+	rank = CASE WHEN EXCLUDED.date >= position_rollups.date THEN EXCLUDED.rank ELSE position_rollups.rank END,
+	url = CASE WHEN EXCLUDED.date >= position_rollups.date THEN EXCLUDED.url ELSE position_rollups.url END,
+	title = CASE WHEN EXCLUDED.date >= position_rollups.date THEN EXCLUDED.title ELSE position_rollups.title END,
+	device = CASE WHEN EXCLUDED.date >= position_rollups.date THEN EXCLUDED.device ELSE position_rollups.device END,
+	os = CASE WHEN EXCLUDED.date >= position_rollups.date THEN EXCLUDED.os ELSE position_rollups.os END,
+	ads = CASE WHEN EXCLUDED.date >= position_rollups.date THEN EXCLUDED.ads ELSE position_rollups.ads END,
+	country = CASE WHEN EXCLUDED.date >= position_rollups.date THEN EXCLUDED.country ELSE position_rollups.country END,
+	lang = CASE WHEN EXCLUDED.date >= position_rollups.date THEN EXCLUDED.lang ELSE position_rollups.lang END,
+	pages = CASE WHEN EXCLUDED.date >= position_rollups.date THEN EXCLUDED.pages ELSE position_rollups.pages END,
+	date = GREATEST(position_rollups.date, EXCLUDED.date),
+	samples = position_rollups.samples + EXCLUDED.samples,
+	visible_samples = position_rollups.visible_samples + EXCLUDED.visible_samples,
+	rank_sum = position_rollups.rank_sum + EXCLUDED.rank_sum,
+	best_rank = LEAST(position_rollups.best_rank, EXCLUDED.best_rank),
+	worst_rank = GREATEST(position_rollups.worst_rank, EXCLUDED.worst_rank),
+	range_1_3 = position_rollups.range_1_3 + EXCLUDED.range_1_3,
+	range_4_10 = position_rollups.range_4_10 + EXCLUDED.range_4_10,
+	range_11_30 = position_rollups.range_11_30 + EXCLUDED.range_11_30,
+	range_31_50 = position_rollups.range_31_50 + EXCLUDED.range_31_50,
+	range_51_100 = position_rollups.range_51_100 + EXCLUDED.range_51_100,
+	range_100_plus = position_rollups.range_100_plus + EXCLUDED.range_100_plus`
+
+const rollupDaysQuery = `
+INSERT INTO position_rollups (` + rollupColumns + `)
+SELECT 'day', DATE(date), DATE(date) + 1, keyword_id, site_id, competitor_id, COALESCE(source, ''),
+	filter_group_id, COALESCE(wordstat_query_type, ''),
+	(array_agg(rank ORDER BY date DESC))[1], (array_agg(url ORDER BY date DESC))[1],
+	(array_agg(title ORDER BY date DESC))[1], (array_agg(device ORDER BY date DESC))[1],
+	(array_agg(os ORDER BY date DESC))[1], (array_agg(ads ORDER BY date DESC))[1],
+	(array_agg(country ORDER BY date DESC))[1], (array_agg(lang ORDER BY date DESC))[1],
+	(array_agg(pages ORDER BY date DESC))[1], MAX(date),
+	COUNT(*), COUNT(*) FILTER (WHERE rank > 0), COALESCE(SUM(rank) FILTER (WHERE rank > 0), 0),
+	MIN(rank) FILTER (WHERE rank > 0), MAX(rank) FILTER (WHERE rank > 0),
+	COUNT(*) FILTER (WHERE rank BETWEEN 1 AND 3), COUNT(*) FILTER (WHERE rank BETWEEN 4 AND 10),
+	COUNT(*) FILTER (WHERE rank BETWEEN 11 AND 30), COUNT(*) FILTER (WHERE rank BETWEEN 31 AND 50),
+	COUNT(*) FILTER (WHERE rank BETWEEN 51 AND 100), COUNT(*) FILTER (WHERE rank > 100)
+FROM positions
+WHERE date < ?
+GROUP BY DATE(date), keyword_id, site_id, competitor_id, COALESCE(source, ''), filter_group_id, COALESCE(wordstat_query_type, '')
+` + rollupConflict
+
+const rollupWeeksQuery = `
+INSERT INTO position_rollups (` + rollupColumns + `)
+SELECT 'week', date_trunc('week', period_start)::date, date_trunc('week', period_start)::date + 7,
+	keyword_id, site_id, competitor_id, source, filter_group_id, wordstat_query_type,
+	(array_agg(rank ORDER BY date DESC))[1], (array_agg(url ORDER BY date DESC))[1],
+	(array_agg(title ORDER BY date DESC))[1], (array_agg(device ORDER BY date DESC))[1],
+	(array_agg(os ORDER BY date DESC))[1], (array_agg(ads ORDER BY date DESC))[1],
+	(array_agg(country ORDER BY date DESC))[1], (array_agg(lang ORDER BY date DESC))[1],
+	(array_agg(pages ORDER BY date DESC))[1], MAX(date),
+	SUM(samples), SUM(visible_samples), SUM(rank_sum), MIN(best_rank), MAX(worst_rank),
+	SUM(range_1_3), SUM(range_4_10), SUM(range_11_30), SUM(range_31_50), SUM(range_51_100), SUM(range_100_plus)
+FROM position_rollups
+WHERE period = 'day' AND period_start < ?
+GROUP BY date_trunc('week', period_start), keyword_id, site_id, competitor_id, source, filter_group_id, wordstat_query_type
+` + rollupConflict
+
+type positionRetentionRepository struct {
+	db *gorm.DB
+}
+
+func NewPositionRetentionRepository(db *gorm.DB) repositories.PositionRetentionRepository {
+	return &positionRetentionRepository{db: db}
+}
+
+func (r *positionRetentionRepository) EnsurePartitions(from, to time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", retentionLockKey).Error; err != nil {
+			return err
+		}
+
+		for month := monthStart(from); !month.After(to); month = month.AddDate(0, 1, 0) {
+			if err := tx.Exec("SELECT ensure_positions_partition(CAST(? AS date))", month.Format("2006-01-02")).Error; err != nil {
+				return fmt.Errorf("failed to create positions partition for %s: %w", month.Format("2006-01"), err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *positionRetentionRepository) RollupDays(before time.Time) (int64, error) {
+	var written int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", retentionLockKey).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(rollupDaysQuery, before)
+		if result.Error != nil {
+			return result.Error
+		}
+		written = result.RowsAffected
+
+		// Целиком устаревшие секции удаляются без построчного DELETE
+		if err := dropPartitionsBefore(tx, before); err != nil {
+			return err
+		}
+		return tx.Where("date < ?", before).Delete(&models.Position{}).Error
+	})
+	return written, err
+}
+
+func (r *positionRetentionRepository) DeleteSnapshots(before time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", retentionLockKey).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("date < ?", before).Delete(&models.SerpSnapshot{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+func (r *positionRetentionRepository) RollupWeeks(before time.Time) (int64, error) {
+	var written int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", retentionLockKey).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(rollupWeeksQuery, before)
+		if result.Error != nil {
+			return result.Error
+		}
+		written = result.RowsAffected
+
+		return tx.Where("period = ? AND period_start < ?", "day", before).Delete(&models.PositionRollup{}).Error
+	})
+	return written, err
+}
+
+// dropPartitionsBefore удаляет месячные секции, в которых не осталось позиций с датой от before.
+// Границы секций считаются в часовом поясе БД, поэтому кроме имени проверяется и содержимое
+func dropPartitionsBefore(tx *gorm.DB, before time.Time) error {
+	var partitions []string
+	if err := tx.Raw(`
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'positions'::regclass
+	`).Scan(&partitions).Error; err != nil {
+		return err
+	}
+
+	for _, partition := range partitions {
+		start, err := time.ParseInLocation("2006_01", strings.TrimPrefix(partition, positionPartitionPrefix), before.Location())
+		if err != nil || !strings.HasPrefix(partition, positionPartitionPrefix) {
+			continue
+		}
+		if start.AddDate(0, 1, 0).After(before) {
+			continue
+		}
+
+		var newer bool
+		if err := tx.Raw(fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE date >= ?)", partition), before).Scan(&newer).Error; err != nil {
+			return err
+		}
+		if newer {
+			continue
+		}
+
+		if err := tx.Exec(fmt.Sprintf("DROP TABLE %s", partition)).Error; err != nil {
+			return fmt.Errorf("failed to drop partition %s: %w", partition, err)
+		}
+	}
+	return nil
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+)
+
+type providerAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewProviderAccountRepository(db *gorm.DB) repositories.ProviderAccountRepository {
+	return &providerAccountRepository{db: db}
+}
+
+func (r *providerAccountRepository) Create(account *entities.ProviderAccount) error {
+	model := &models.ProviderAccount{
+		WorkspaceID:     account.WorkspaceID,
+		Name:            account.Name,
+		Provider:        account.Provider,
+		UserID:          account.UserID,
+		EncryptedAPIKey: account.EncryptedAPIKey,
+		BaseURL:         account.BaseURL,
+	}
+
+	if err := r.db.Create(model).Error; err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	account.ID = model.ID
+	account.CreatedAt = model.CreatedAt
+	account.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+func (r *providerAccountRepository) GetByID(id int) (*entities.ProviderAccount, error) {
+	var model models.ProviderAccount
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+func (r *providerAccountRepository) GetAll(workspaceID *int) ([]*entities.ProviderAccount, error) {
+	query := r.db.Order("id")
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+
+	var models []models.ProviderAccount
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*entities.ProviderAccount, len(models))
+	for i, model := range models {
+		accounts[i] = r.toDomain(&model)
+	}
+
+	return accounts, nil
+}
+
+func (r *providerAccountRepository) Update(account *entities.ProviderAccount) error {
+	if err := r.db.Model(&models.ProviderAccount{}).
+		Where("id = ?", account.ID).
+		Updates(map[string]interface{}{
+			"name":              account.Name,
+			"provider":          account.Provider,
+			"user_id":           account.UserID,
+			"encrypted_api_key": account.EncryptedAPIKey,
+			"base_url":          account.BaseURL,
+		}).Error; err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *providerAccountRepository) Delete(id int) error {
+	return r.db.Delete(&models.ProviderAccount{}, id).Error
+}
+
+func (r *providerAccountRepository) toDomain(model *models.ProviderAccount) *entities.ProviderAccount {
+	return &entities.ProviderAccount{
+		ID:              model.ID,
+		WorkspaceID:     model.WorkspaceID,
+		Name:            model.Name,
+		Provider:        model.Provider,
+		UserID:          model.UserID,
+		EncryptedAPIKey: model.EncryptedAPIKey,
+		BaseURL:         model.BaseURL,
+		CreatedAt:       model.CreatedAt,
+		UpdatedAt:       model.UpdatedAt,
+	}
+}
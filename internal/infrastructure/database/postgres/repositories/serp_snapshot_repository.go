@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+)
+
+type serpSnapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewSerpSnapshotRepository(db *gorm.DB) repositories.SerpSnapshotRepository {
+	return &serpSnapshotRepository{db: db}
+}
+
+func (r *serpSnapshotRepository) ReplaceForPosition(snapshot *entities.SerpSnapshot) error {
+	rows := make([]models.SerpSnapshot, len(snapshot.Items))
+	for i, item := range snapshot.Items {
+		rows[i] = models.SerpSnapshot{
+			PositionID:  snapshot.PositionID,
+			KeywordID:   snapshot.KeywordID,
+			SiteID:      snapshot.SiteID,
+			Source:      snapshot.Source,
+			Place:       item.Place,
+			Rank:        item.Rank,
+			URL:         item.URL,
+			Domain:      item.Domain,
+			Title:       item.Title,
+			Passage:     item.Passage,
+			ContentType: item.ContentType,
+			Date:        snapshot.Date,
+		}
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("position_id = ?", snapshot.PositionID).Delete(&models.SerpSnapshot{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(rows, 100).Error; err != nil {
+			return database.WrapDatabaseError(err)
+		}
+		return nil
+	})
+}
+
+func (r *serpSnapshotRepository) GetByPositionID(positionID int, workspaceID *int) (*entities.SerpSnapshot, error) {
+	query := r.db.Where("position_id = ?", positionID)
+	if workspaceID != nil {
+		query = query.Where("site_id IN (SELECT id FROM sites WHERE workspace_id = ?)", *workspaceID)
+	}
+
+	var rows []models.SerpSnapshot
+	if err := query.Order("place ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	snapshot := &entities.SerpSnapshot{
+		PositionID: rows[0].PositionID,
+		KeywordID:  rows[0].KeywordID,
+		SiteID:     rows[0].SiteID,
+		Source:     rows[0].Source,
+		Date:       rows[0].Date,
+		Items:      make([]entities.SerpItem, len(rows)),
+	}
+	for i, row := range rows {
+		snapshot.Items[i] = entities.SerpItem{
+			Place:       row.Place,
+			Rank:        row.Rank,
+			URL:         row.URL,
+			Domain:      row.Domain,
+			Title:       row.Title,
+			Passage:     row.Passage,
+			ContentType: row.ContentType,
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (r *serpSnapshotRepository) DeleteBySiteID(siteID int) error {
+	return r.db.Where("site_id = ?", siteID).Delete(&models.SerpSnapshot{}).Error
+}
+
+func (r *serpSnapshotRepository) DeleteByKeywordID(keywordID int) error {
+	return r.db.Where("keyword_id = ?", keywordID).Delete(&models.SerpSnapshot{}).Error
+}
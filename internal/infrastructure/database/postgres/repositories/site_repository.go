@@ -1,6 +1,11 @@
 package repositories
 
 import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
 	"go-seo/internal/domain/entities"
 	"go-seo/internal/domain/repositories"
 	"go-seo/internal/infrastructure/database"
@@ -19,7 +24,9 @@ func NewSiteRepository(db *gorm.DB) repositories.SiteRepository {
 
 func (r *siteRepository) Create(site *entities.Site) error {
 	model := &models.Site{
-		Domain: site.Domain,
+		WorkspaceID:  site.WorkspaceID,
+		Domain:       site.Domain,
+		BudgetAction: site.BudgetAction,
 	}
 
 	if err := r.db.Create(model).Error; err != nil {
@@ -39,6 +46,20 @@ func (r *siteRepository) GetByID(id int) (*entities.Site, error) {
 	return r.toDomain(&model), nil
 }
 
+func (r *siteRepository) GetByIDInWorkspace(id int, workspaceID *int) (*entities.Site, error) {
+	query := r.db.Where("id = ?", id)
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+
+	var model models.Site
+	if err := query.First(&model).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
 func (r *siteRepository) GetByDomain(domain string) (*entities.Site, error) {
 	var model models.Site
 	if err := r.db.Where("domain = ?", domain).First(&model).Error; err != nil {
@@ -62,13 +83,32 @@ func (r *siteRepository) GetAll() ([]*entities.Site, error) {
 	return sites, nil
 }
 
-func (r *siteRepository) GetByIDs(ids []int) ([]*entities.Site, error) {
+func (r *siteRepository) GetByWorkspaceID(workspaceID int) ([]*entities.Site, error) {
+	var models []models.Site
+	if err := r.db.Where("workspace_id = ?", workspaceID).Order("updated_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	sites := make([]*entities.Site, len(models))
+	for i, model := range models {
+		sites[i] = r.toDomain(&model)
+	}
+
+	return sites, nil
+}
+
+func (r *siteRepository) GetByIDs(ids []int, workspaceID *int) ([]*entities.Site, error) {
 	if len(ids) == 0 {
 		return []*entities.Site{}, nil
 	}
 
+	query := r.db.Where("id IN ?", ids)
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+
 	var models []models.Site
-	if err := r.db.Where("id IN ?", ids).Order("updated_at DESC").Find(&models).Error; err != nil {
+	if err := query.Order("updated_at DESC").Find(&models).Error; err != nil {
 		return nil, err
 	}
 
@@ -83,12 +123,47 @@ func (r *siteRepository) GetByIDs(ids []int) ([]*entities.Site, error) {
 func (r *siteRepository) Update(site *entities.Site) error {
 	model := &models.Site{
 		ID:            site.ID,
+		WorkspaceID:   site.WorkspaceID,
 		Domain:        site.Domain,
-		YandexDynamic: site.YandexDynamic,
-		GoogleDynamic: site.GoogleDynamic,
+		MonthlyBudget: site.MonthlyBudget,
+		BudgetAction:  site.BudgetAction,
+	}
+
+	// Сводки движения меняет только UpdateMovement, чтобы сохранение настроек сайта не затирало свежий расчет
+	return r.db.Omit("yandex_movement", "google_movement").Save(model).Error
+}
+
+func (r *siteRepository) UpdateMovement(siteID int, source string, summary *entities.MovementSummary) error {
+	var column string
+	switch source {
+	case entities.GoogleSearch:
+		column = "google_movement"
+	case entities.YandexSearch:
+		column = "yandex_movement"
+	default:
+		return fmt.Errorf("movement is not tracked for source %q", source)
+	}
+
+	var value *string
+	if summary != nil {
+		data, err := json.Marshal(movementSummaryRecord{
+			PreviousDate: summary.PreviousDate.Format("2006-01-02"),
+			CurrentDate:  summary.CurrentDate.Format("2006-01-02"),
+			Up:           summary.Up,
+			Down:         summary.Down,
+			New:          summary.New,
+			Lost:         summary.Lost,
+			Unchanged:    summary.Unchanged,
+			NetScore:     summary.NetScore,
+		})
+		if err != nil {
+			return err
+		}
+		encoded := string(data)
+		value = &encoded
 	}
 
-	return r.db.Save(model).Error
+	return r.db.Model(&models.Site{ID: siteID}).Update(column, value).Error
 }
 
 func (r *siteRepository) Delete(id int) error {
@@ -97,9 +172,54 @@ func (r *siteRepository) Delete(id int) error {
 
 func (r *siteRepository) toDomain(model *models.Site) *entities.Site {
 	return &entities.Site{
-		ID:            model.ID,
-		Domain:        model.Domain,
-		YandexDynamic: model.YandexDynamic,
-		GoogleDynamic: model.GoogleDynamic,
+		ID:             model.ID,
+		WorkspaceID:    model.WorkspaceID,
+		Domain:         model.Domain,
+		MonthlyBudget:  model.MonthlyBudget,
+		BudgetAction:   model.BudgetAction,
+		YandexMovement: movementFromJSON(model.ID, model.YandexMovement),
+		GoogleMovement: movementFromJSON(model.ID, model.GoogleMovement),
+	}
+}
+
+// movementSummaryRecord формат сводки движения в колонках sites.*_movement
+type movementSummaryRecord struct {
+	PreviousDate string `json:"previous_date"`
+	CurrentDate  string `json:"current_date"`
+	Up           int    `json:"up"`
+	Down         int    `json:"down"`
+	New          int    `json:"new"`
+	Lost         int    `json:"lost"`
+	Unchanged    int    `json:"unchanged"`
+	NetScore     int    `json:"net_score"`
+}
+
+// movementFromJSON поврежденная сводка не мешает читать сайт: она пересчитается после следующей проверки
+func movementFromJSON(siteID int, data *string) *entities.MovementSummary {
+	if data == nil {
+		return nil
+	}
+
+	var record movementSummaryRecord
+	if err := json.Unmarshal([]byte(*data), &record); err != nil {
+		log.Printf("WARNING: Invalid movement summary of site %d: %v", siteID, err)
+		return nil
+	}
+	previous, errPrevious := time.ParseInLocation("2006-01-02", record.PreviousDate, time.Local)
+	current, errCurrent := time.ParseInLocation("2006-01-02", record.CurrentDate, time.Local)
+	if errPrevious != nil || errCurrent != nil {
+		log.Printf("WARNING: Invalid movement summary dates of site %d", siteID)
+		return nil
+	}
+
+	return &entities.MovementSummary{
+		PreviousDate: previous,
+		CurrentDate:  current,
+		Up:           record.Up,
+		Down:         record.Down,
+		New:          record.New,
+		Lost:         record.Lost,
+		Unchanged:    record.Unchanged,
+		NetScore:     record.NetScore,
 	}
 }
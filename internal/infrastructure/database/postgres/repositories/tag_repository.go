@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) repositories.TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(tag *entities.Tag) error {
+	model := &models.Tag{
+		SiteID: tag.SiteID,
+		Name:   tag.Name,
+	}
+
+	if err := r.db.Create(model).Error; err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	tag.ID = model.ID
+	return nil
+}
+
+func (r *tagRepository) GetByID(id int) (*entities.Tag, error) {
+	var model models.Tag
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+func (r *tagRepository) GetByIDs(ids []int) ([]*entities.Tag, error) {
+	if len(ids) == 0 {
+		return []*entities.Tag{}, nil
+	}
+
+	var records []models.Tag
+	if err := r.db.Where("id IN ?", ids).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	tags := make([]*entities.Tag, len(records))
+	for i, record := range records {
+		tags[i] = r.toDomain(&record)
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) GetAllBySite(siteID int) ([]*entities.Tag, error) {
+	var records []struct {
+		models.Tag
+		KeywordsCount int
+	}
+	if err := r.db.Model(&models.Tag{}).
+		Select("tags.*, (SELECT COUNT(*) FROM keyword_tags kt WHERE kt.tag_id = tags.id) AS keywords_count").
+		Where("site_id = ?", siteID).
+		Order("name").
+		Scan(&records).Error; err != nil {
+		return nil, err
+	}
+
+	tags := make([]*entities.Tag, len(records))
+	for i, record := range records {
+		tags[i] = r.toDomain(&record.Tag)
+		tags[i].KeywordsCount = record.KeywordsCount
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) GetByKeywordIDs(keywordIDs []int) (map[int][]*entities.Tag, error) {
+	result := make(map[int][]*entities.Tag)
+	if len(keywordIDs) == 0 {
+		return result, nil
+	}
+
+	var records []struct {
+		models.Tag
+		KeywordID int
+	}
+	if err := r.db.Model(&models.Tag{}).
+		Select("tags.*, kt.keyword_id").
+		Joins("JOIN keyword_tags kt ON kt.tag_id = tags.id").
+		Where("kt.keyword_id IN ?", keywordIDs).
+		Order("tags.name").
+		Scan(&records).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range records {
+		result[records[i].KeywordID] = append(result[records[i].KeywordID], r.toDomain(&records[i].Tag))
+	}
+	return result, nil
+}
+
+func (r *tagRepository) Update(tag *entities.Tag) error {
+	err := r.db.Model(&models.Tag{}).Where("id = ?", tag.ID).Update("name", tag.Name).Error
+	return database.WrapDatabaseError(err)
+}
+
+func (r *tagRepository) Delete(id int) error {
+	// Связи со словами удаляются каскадом
+	return r.db.Delete(&models.Tag{}, id).Error
+}
+
+func (r *tagRepository) Assign(keywordIDs, tagIDs []int) (int, error) {
+	links := make([]models.KeywordTag, 0, len(keywordIDs)*len(tagIDs))
+	for _, keywordID := range keywordIDs {
+		for _, tagID := range tagIDs {
+			links = append(links, models.KeywordTag{KeywordID: keywordID, TagID: tagID})
+		}
+	}
+	if len(links) == 0 {
+		return 0, nil
+	}
+
+	// Две колонки на связь: пачки по 10 тысяч укладываются в лимит параметров PostgreSQL
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(links, 10000)
+	if result.Error != nil {
+		return 0, database.WrapDatabaseError(result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+func (r *tagRepository) Unassign(keywordIDs, tagIDs []int) (int, error) {
+	if len(keywordIDs) == 0 || len(tagIDs) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.Where("keyword_id IN ? AND tag_id IN ?", keywordIDs, tagIDs).Delete(&models.KeywordTag{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
+
+func (r *tagRepository) toDomain(model *models.Tag) *entities.Tag {
+	return &entities.Tag{
+		ID:     model.ID,
+		SiteID: model.SiteID,
+		Name:   model.Name,
+	}
+}
+
+// keywordTagsCondition условие отбора по меткам для колонки с ID ключевого слова. ID меток
+// подставляются в текст запроса числами, поэтому условие годится и для запросов с параметрами $n
+func keywordTagsCondition(column string, tags *entities.TagFilter) string {
+	if tags == nil || len(tags.TagIDs) == 0 {
+		return "TRUE"
+	}
+
+	// Для "all" число меток сравнивается с числом связей, поэтому повторы ID убираются
+	seen := make(map[int]bool, len(tags.TagIDs))
+	ids := make([]string, 0, len(tags.TagIDs))
+	for _, id := range tags.TagIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, strconv.Itoa(id))
+		}
+	}
+	list := strings.Join(ids, ", ")
+
+	if tags.Match == entities.TagMatchAll {
+		return fmt.Sprintf("%s IN (SELECT keyword_id FROM keyword_tags WHERE tag_id IN (%s) GROUP BY keyword_id HAVING COUNT(*) = %d)", column, list, len(ids))
+	}
+	return fmt.Sprintf("%s IN (SELECT keyword_id FROM keyword_tags WHERE tag_id IN (%s))", column, list)
+}
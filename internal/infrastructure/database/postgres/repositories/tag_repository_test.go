@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+func TestKeywordTagsCondition(t *testing.T) {
+	tests := []struct {
+		name string
+		tags *entities.TagFilter
+		want string
+	}{
+		{name: "no filter", want: "TRUE"},
+		{name: "no tags", tags: &entities.TagFilter{Match: entities.TagMatchAll}, want: "TRUE"},
+		{
+			name: "any tag",
+			tags: &entities.TagFilter{TagIDs: []int{3, 5, 3}, Match: entities.TagMatchAny},
+			want: "keyword_id IN (SELECT keyword_id FROM keyword_tags WHERE tag_id IN (3, 5))",
+		},
+		{
+			// Повтор ID не должен требовать лишней связи
+			name: "all tags",
+			tags: &entities.TagFilter{TagIDs: []int{3, 5, 3}, Match: entities.TagMatchAll},
+			want: "keyword_id IN (SELECT keyword_id FROM keyword_tags WHERE tag_id IN (3, 5) GROUP BY keyword_id HAVING COUNT(*) = 2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keywordTagsCondition("keyword_id", tt.tags); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTagAssignAndFilter(t *testing.T) {
+	tx := openTestDB(t)
+	tagRepo := &tagRepository{db: tx}
+	positionRepo := &positionRepository{db: tx}
+	siteID, _, first, second := seedVisibilitySite(t, tx)
+
+	var untagged int
+	if err := tx.Raw("INSERT INTO keywords (value, site_id, created_at, updated_at) VALUES ('untagged', ?, NOW(), NOW()) RETURNING id", siteID).
+		Scan(&untagged).Error; err != nil {
+		t.Fatalf("insert keyword: %v", err)
+	}
+
+	a, b := &entities.Tag{SiteID: siteID, Name: "a"}, &entities.Tag{SiteID: siteID, Name: "b"}
+	for _, tag := range []*entities.Tag{a, b} {
+		if err := tagRepo.Create(tag); err != nil {
+			t.Fatalf("create tag: %v", err)
+		}
+	}
+
+	// Уже назначенные метки не считаются новыми связями
+	steps := []struct {
+		name     string
+		run      func() (int, error)
+		expected int
+	}{
+		{"assign a", func() (int, error) { return tagRepo.Assign([]int{first, second}, []int{a.ID}) }, 2},
+		{"assign a and b", func() (int, error) { return tagRepo.Assign([]int{first}, []int{a.ID, b.ID}) }, 1},
+		{"unassign", func() (int, error) { return tagRepo.Unassign([]int{second}, []int{a.ID, b.ID}) }, 1},
+		{"unassign missing", func() (int, error) { return tagRepo.Unassign([]int{untagged}, []int{a.ID}) }, 0},
+		{"assign b", func() (int, error) { return tagRepo.Assign([]int{second}, []int{b.ID}) }, 1},
+	}
+	for _, step := range steps {
+		if got, err := step.run(); err != nil || got != step.expected {
+			t.Fatalf("%s: expected %d links, got %d, %v", step.name, step.expected, got, err)
+		}
+	}
+
+	// Слово first с метками a и b, слово second - с меткой b
+	tags, err := tagRepo.GetAllBySite(siteID)
+	if err != nil || len(tags) != 2 || tags[0].KeywordsCount != 1 || tags[1].KeywordsCount != 2 {
+		t.Fatalf("unexpected tag counts %+v, %v", tags, err)
+	}
+
+	noon := func(day int) time.Time { return time.Date(2026, 10, day, 12, 0, 0, 0, time.UTC) }
+	for _, keywordID := range []int{first, second, untagged} {
+		insertVisibilityPosition(t, tx, keywordID, siteID, nil, entities.GoogleSearch, 5, noon(1), "")
+		insertVisibilityPosition(t, tx, keywordID, siteID, nil, entities.GoogleSearch, 3, noon(2), "")
+	}
+
+	onlyA := &entities.TagFilter{TagIDs: []int{a.ID}, Match: entities.TagMatchAny}
+	bothAll := &entities.TagFilter{TagIDs: []int{a.ID, b.ID}, Match: entities.TagMatchAll}
+	repeatedAll := &entities.TagFilter{TagIDs: []int{b.ID, b.ID}, Match: entities.TagMatchAll}
+	google := entities.GoogleSearch
+
+	filters := []struct {
+		name     string
+		tags     *entities.TagFilter
+		keywords []int
+	}{
+		{"any", onlyA, []int{first}},
+		{"all", bothAll, []int{first}},
+		{"all with repeated id", repeatedAll, []int{first, second}},
+		{"no filter", nil, []int{first, second, untagged}},
+	}
+	for _, f := range filters {
+		t.Run(f.name, func(t *testing.T) {
+			positions, total, err := positionRepo.GetPositionsHistoryPaginated(siteID, nil, f.tags, &google, nil, nil, false, 1, 50)
+			if err != nil || int(total) != 2*len(f.keywords) || !positionsOf(positions, f.keywords) {
+				t.Fatalf("history: expected positions of %v, got %d: %+v, %v", f.keywords, total, positions, err)
+			}
+
+			// Последняя позиция каждого слова, отобранная по меткам
+			positions, total, err = positionRepo.GetPositionsHistoryPaginated(siteID, nil, f.tags, &google, nil, nil, true, 1, 50)
+			if err != nil || int(total) != len(f.keywords) || !positionsOf(positions, f.keywords) {
+				t.Fatalf("last: expected positions of %v, got %d: %+v, %v", f.keywords, total, positions, err)
+			}
+			for _, position := range positions {
+				if position.Rank != 3 {
+					t.Fatalf("last: expected the latest position, got %+v", position)
+				}
+			}
+
+			combined, total, err := positionRepo.GetCombinedPositionsPaginated(siteID, nil, nil, false, false, nil, nil, nil, "", nil, nil, nil, f.tags, nil, nil, 1, 20)
+			if err != nil || int(total) != len(f.keywords) || len(combined) != len(f.keywords) {
+				t.Fatalf("combined: expected keywords %v, got %d: %+v, %v", f.keywords, total, combined, err)
+			}
+			for i, keywordID := range f.keywords {
+				if combined[i].KeywordID != keywordID || len(combined[i].Positions) != 2 {
+					t.Fatalf("combined: unexpected keyword %+v", combined[i])
+				}
+			}
+
+			stats, err := positionRepo.GetPositionStatistics(siteID, nil, google, noon(1), noon(2), nil, f.tags)
+			if err != nil || stats.KeywordsCount != len(f.keywords) || stats.TotalPositions != 2*len(f.keywords) {
+				t.Fatalf("statistics: expected keywords %v, got %+v, %v", f.keywords, stats, err)
+			}
+		})
+	}
+}
+
+// positionsOf проверяет, что позиции относятся ровно к словам keywordIDs
+func positionsOf(positions []*entities.Position, keywordIDs []int) bool {
+	expected := make(map[int]bool, len(keywordIDs))
+	for _, id := range keywordIDs {
+		expected[id] = true
+	}
+	found := make(map[int]bool, len(keywordIDs))
+	for _, position := range positions {
+		if !expected[position.KeywordID] {
+			return false
+		}
+		found[position.KeywordID] = true
+	}
+	return len(found) == len(keywordIDs)
+}
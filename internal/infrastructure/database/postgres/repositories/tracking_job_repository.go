@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"encoding/json"
+	"errors"
 	"time"
 
 	"go-seo/internal/domain/entities"
@@ -8,6 +10,12 @@ import (
 	"go-seo/internal/infrastructure/database/postgres/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errJobStatusChanged = errors.New("job status changed")
+	errNoFailedTasks    = errors.New("job has no failed tasks")
 )
 
 type TrackingJobRepository struct {
@@ -19,19 +27,9 @@ func NewTrackingJobRepository(db *gorm.DB) repositories.TrackingJobRepository {
 }
 
 func (r *TrackingJobRepository) Create(job *entities.TrackingJob) error {
-	model := &models.TrackingJob{
-		ID:             job.ID,
-		SiteID:         job.SiteID,
-		Source:         job.Source,
-		Status:         string(job.Status),
-		CreatedAt:      job.CreatedAt,
-		UpdatedAt:      job.UpdatedAt,
-		CompletedAt:    job.CompletedAt,
-		TotalTasks:     job.TotalTasks,
-		CompletedTasks: job.CompletedTasks,
-		FailedTasks:    job.FailedTasks,
-		FailedRequests: job.FailedRequests,
-		Error:          job.Error,
+	model, err := r.toModel(job)
+	if err != nil {
+		return err
 	}
 
 	return r.db.Create(model).Error
@@ -43,36 +41,27 @@ func (r *TrackingJobRepository) GetByID(id string) (*entities.TrackingJob, error
 		return nil, err
 	}
 
-	return &entities.TrackingJob{
-		ID:             model.ID,
-		SiteID:         model.SiteID,
-		Source:         model.Source,
-		Status:         entities.TrackingTaskStatus(model.Status),
-		CreatedAt:      model.CreatedAt,
-		UpdatedAt:      model.UpdatedAt,
-		CompletedAt:    model.CompletedAt,
-		TotalTasks:     model.TotalTasks,
-		CompletedTasks: model.CompletedTasks,
-		FailedTasks:    model.FailedTasks,
-		FailedRequests: model.FailedRequests,
-		Error:          model.Error,
-	}, nil
+	return r.toDomain(&model)
+}
+
+func (r *TrackingJobRepository) GetByIDInWorkspace(id string, workspaceID *int) (*entities.TrackingJob, error) {
+	query := r.db.Where("id = ?", id)
+	if workspaceID != nil {
+		query = query.Where("site_id IN (SELECT id FROM sites WHERE workspace_id = ?)", *workspaceID)
+	}
+
+	var model models.TrackingJob
+	if err := query.First(&model).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(&model)
 }
 
 func (r *TrackingJobRepository) Update(job *entities.TrackingJob) error {
-	model := &models.TrackingJob{
-		ID:             job.ID,
-		SiteID:         job.SiteID,
-		Source:         job.Source,
-		Status:         string(job.Status),
-		CreatedAt:      job.CreatedAt,
-		UpdatedAt:      job.UpdatedAt,
-		CompletedAt:    job.CompletedAt,
-		TotalTasks:     job.TotalTasks,
-		CompletedTasks: job.CompletedTasks,
-		FailedTasks:    job.FailedTasks,
-		FailedRequests: job.FailedRequests,
-		Error:          job.Error,
+	model, err := r.toModel(job)
+	if err != nil {
+		return err
 	}
 
 	return r.db.Save(model).Error
@@ -87,6 +76,100 @@ func (r *TrackingJobRepository) UpdateStatus(id string, status entities.Tracking
 		}).Error
 }
 
+func (r *TrackingJobRepository) UpdateStatusIf(id string, from, to entities.TrackingTaskStatus) (bool, error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":     string(to),
+		"updated_at": now,
+	}
+	switch to {
+	case entities.TaskStatusCancelled:
+		updates["completed_at"] = now
+	case entities.TaskStatusCompleted:
+		updates["completed_at"] = now
+		updates["error"] = ""
+		updates["locked_by"] = ""
+		updates["locked_until"] = nil
+	case entities.TaskStatusFailed:
+		updates["locked_by"] = ""
+		updates["locked_until"] = nil
+	case entities.TaskStatusPending:
+		// Возобновленный или перезапущенный джоб снова считается незавершенным
+		updates["completed_at"] = nil
+		updates["error"] = ""
+	}
+
+	result := r.db.Model(&models.TrackingJob{}).
+		Where("id = ? AND status = ?", id, string(from)).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *TrackingJobRepository) UpdateError(id string, errText string) error {
+	return r.db.Model(&models.TrackingJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"error":      errText,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *TrackingJobRepository) RequeueFailed(id string, from entities.TrackingTaskStatus, estimatedCost float64) (bool, int64, error) {
+	var reset int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.TrackingJob{}).
+			Where("id = ? AND status = ?", id, string(from)).
+			Updates(map[string]interface{}{
+				"status":         string(entities.TaskStatusPending),
+				"estimated_cost": estimatedCost,
+				"completed_at":   nil,
+				"error":          "",
+				"updated_at":     now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errJobStatusChanged
+		}
+
+		result = tx.Model(&models.TrackingTask{}).
+			Where("job_id = ? AND status = ?", id, string(entities.TaskStatusFailed)).
+			Updates(map[string]interface{}{
+				"status":       string(entities.TaskStatusPending),
+				"error":        "",
+				"completed_at": nil,
+				"updated_at":   now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errNoFailedTasks
+		}
+		reset = result.RowsAffected
+
+		return tx.Model(&models.TrackingJob{}).
+			Where("id = ?", id).
+			Update("failed_tasks", gorm.Expr("GREATEST(failed_tasks - ?, 0)", reset)).Error
+	})
+	switch {
+	case errors.Is(err, errJobStatusChanged):
+		return false, 0, nil
+	case errors.Is(err, errNoFailedTasks):
+		return true, 0, nil
+	case err != nil:
+		return false, 0, err
+	}
+
+	return true, reset, nil
+}
+
 func (r *TrackingJobRepository) UpdateProgress(id string, completed, failed int) error {
 	return r.db.Model(&models.TrackingJob{}).
 		Where("id = ?", id).
@@ -112,25 +195,7 @@ func (r *TrackingJobRepository) GetBySiteID(siteID int) ([]*entities.TrackingJob
 		return nil, err
 	}
 
-	var jobs []*entities.TrackingJob
-	for _, model := range models {
-		jobs = append(jobs, &entities.TrackingJob{
-			ID:             model.ID,
-			SiteID:         model.SiteID,
-			Source:         model.Source,
-			Status:         entities.TrackingTaskStatus(model.Status),
-			CreatedAt:      model.CreatedAt,
-			UpdatedAt:      model.UpdatedAt,
-			CompletedAt:    model.CompletedAt,
-			TotalTasks:     model.TotalTasks,
-			CompletedTasks: model.CompletedTasks,
-			FailedTasks:    model.FailedTasks,
-			FailedRequests: model.FailedRequests,
-			Error:          model.Error,
-		})
-	}
-
-	return jobs, nil
+	return r.toDomainList(models)
 }
 
 func (r *TrackingJobRepository) GetByStatus(status entities.TrackingTaskStatus) ([]*entities.TrackingJob, error) {
@@ -139,34 +204,19 @@ func (r *TrackingJobRepository) GetByStatus(status entities.TrackingTaskStatus)
 		return nil, err
 	}
 
-	var jobs []*entities.TrackingJob
-	for _, model := range models {
-		jobs = append(jobs, &entities.TrackingJob{
-			ID:             model.ID,
-			SiteID:         model.SiteID,
-			Source:         model.Source,
-			Status:         entities.TrackingTaskStatus(model.Status),
-			CreatedAt:      model.CreatedAt,
-			UpdatedAt:      model.UpdatedAt,
-			CompletedAt:    model.CompletedAt,
-			TotalTasks:     model.TotalTasks,
-			CompletedTasks: model.CompletedTasks,
-			FailedTasks:    model.FailedTasks,
-			FailedRequests: model.FailedRequests,
-			Error:          model.Error,
-		})
-	}
-
-	return jobs, nil
+	return r.toDomainList(models)
 }
 
-func (r *TrackingJobRepository) GetJobsWithPagination(page, perPage int, siteID *int, status *entities.TrackingTaskStatus) ([]*entities.TrackingJob, int64, error) {
+func (r *TrackingJobRepository) GetJobsWithPagination(page, perPage int, workspaceID, siteID *int, status *entities.TrackingTaskStatus) ([]*entities.TrackingJob, int64, error) {
 	var jobModels []models.TrackingJob
 	var total int64
 
 	query := r.db.Model(&models.TrackingJob{})
 
 	// Применяем фильтры
+	if workspaceID != nil {
+		query = query.Where("site_id IN (SELECT id FROM sites WHERE workspace_id = ?)", *workspaceID)
+	}
 	if siteID != nil {
 		query = query.Where("site_id = ?", *siteID)
 	}
@@ -185,27 +235,89 @@ func (r *TrackingJobRepository) GetJobsWithPagination(page, perPage int, siteID
 		return nil, 0, err
 	}
 
-	var jobs []*entities.TrackingJob
-	for _, model := range jobModels {
-		jobs = append(jobs, &entities.TrackingJob{
-			ID:             model.ID,
-			SiteID:         model.SiteID,
-			Source:         model.Source,
-			Status:         entities.TrackingTaskStatus(model.Status),
-			CreatedAt:      model.CreatedAt,
-			UpdatedAt:      model.UpdatedAt,
-			CompletedAt:    model.CompletedAt,
-			TotalTasks:     model.TotalTasks,
-			CompletedTasks: model.CompletedTasks,
-			FailedTasks:    model.FailedTasks,
-			FailedRequests: model.FailedRequests,
-			Error:          model.Error,
-		})
+	jobs, err := r.toDomainList(jobModels)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return jobs, total, nil
 }
 
+func (r *TrackingJobRepository) HasActiveJob(siteID int, source string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.TrackingJob{}).
+		Where("site_id = ? AND source = ? AND status IN ?", siteID, source,
+			[]string{string(entities.TaskStatusPending), string(entities.TaskStatusRunning), string(entities.TaskStatusPaused)}).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// ClaimNext берет самый старый незавершенный джоб без действующей аренды.
+// SKIP LOCKED не дает двум инстансам выбрать одну и ту же строку одновременно.
+func (r *TrackingJobRepository) ClaimNext(workerID string, leaseUntil time.Time) (*entities.TrackingJob, error) {
+	var model models.TrackingJob
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND (locked_until IS NULL OR locked_until < ?)",
+				[]string{string(entities.TaskStatusPending), string(entities.TaskStatusRunning)}, time.Now()).
+			Order("created_at").
+			First(&model).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		model.Status = string(entities.TaskStatusRunning)
+		model.LockedBy = workerID
+		model.LockedUntil = &leaseUntil
+		model.UpdatedAt = now
+		if model.StartedAt == nil {
+			model.StartedAt = &now
+		}
+
+		return tx.Model(&models.TrackingJob{}).
+			Where("id = ?", model.ID).
+			Updates(map[string]interface{}{
+				"status":       model.Status,
+				"locked_by":    model.LockedBy,
+				"locked_until": model.LockedUntil,
+				"started_at":   model.StartedAt,
+				"updated_at":   model.UpdatedAt,
+			}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(&model)
+}
+
+func (r *TrackingJobRepository) ExtendLease(id, workerID string, leaseUntil time.Time) (bool, error) {
+	result := r.db.Model(&models.TrackingJob{}).
+		Where("id = ? AND locked_by = ? AND status = ?", id, workerID, string(entities.TaskStatusRunning)).
+		Update("locked_until", leaseUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *TrackingJobRepository) ReleaseLease(id, workerID string) error {
+	return r.db.Model(&models.TrackingJob{}).
+		Where("id = ? AND locked_by = ?", id, workerID).
+		Updates(map[string]interface{}{
+			"locked_by":    "",
+			"locked_until": nil,
+		}).Error
+}
+
 func (r *TrackingJobRepository) Delete(id string) error {
 	return r.db.Delete(&models.TrackingJob{}, "id = ?", id).Error
 }
@@ -213,3 +325,74 @@ func (r *TrackingJobRepository) Delete(id string) error {
 func (r *TrackingJobRepository) DeleteBySiteID(siteID int) error {
 	return r.db.Where("site_id = ?", siteID).Delete(&models.TrackingJob{}).Error
 }
+
+func (r *TrackingJobRepository) toModel(job *entities.TrackingJob) (*models.TrackingJob, error) {
+	params, err := json.Marshal(job.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TrackingJob{
+		ID:             job.ID,
+		SiteID:         job.SiteID,
+		Source:         job.Source,
+		Status:         string(job.Status),
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+		StartedAt:      job.StartedAt,
+		CompletedAt:    job.CompletedAt,
+		TotalTasks:     job.TotalTasks,
+		CompletedTasks: job.CompletedTasks,
+		FailedTasks:    job.FailedTasks,
+		FailedRequests: job.FailedRequests,
+		EstimatedCost:  job.EstimatedCost,
+		Error:          job.Error,
+		Params:         string(params),
+		APIKeyID:       job.APIKeyID,
+		LockedBy:       job.LockedBy,
+		LockedUntil:    job.LockedUntil,
+	}, nil
+}
+
+func (r *TrackingJobRepository) toDomain(model *models.TrackingJob) (*entities.TrackingJob, error) {
+	job := &entities.TrackingJob{
+		ID:             model.ID,
+		SiteID:         model.SiteID,
+		Source:         model.Source,
+		Status:         entities.TrackingTaskStatus(model.Status),
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+		StartedAt:      model.StartedAt,
+		CompletedAt:    model.CompletedAt,
+		TotalTasks:     model.TotalTasks,
+		CompletedTasks: model.CompletedTasks,
+		FailedTasks:    model.FailedTasks,
+		FailedRequests: model.FailedRequests,
+		EstimatedCost:  model.EstimatedCost,
+		Error:          model.Error,
+		APIKeyID:       model.APIKeyID,
+		LockedBy:       model.LockedBy,
+		LockedUntil:    model.LockedUntil,
+	}
+
+	if model.Params != "" {
+		if err := json.Unmarshal([]byte(model.Params), &job.Params); err != nil {
+			return nil, err
+		}
+	}
+
+	return job, nil
+}
+
+func (r *TrackingJobRepository) toDomainList(jobModels []models.TrackingJob) ([]*entities.TrackingJob, error) {
+	var jobs []*entities.TrackingJob
+	for i := range jobModels {
+		job, err := r.toDomain(&jobModels[i])
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,232 @@
+package repositories
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/infrastructure/database/postgres/models"
+)
+
+// Сайт тестовых джобов; created_at в прошлом, чтобы ClaimNext выбирал их раньше остальных джобов БД
+const testJobSiteID = 900001
+
+func createTestJob(tb testing.TB, repo *TrackingJobRepository, id string, createdAt time.Time) {
+	tb.Helper()
+
+	job := &entities.TrackingJob{
+		ID:        id,
+		SiteID:    testJobSiteID,
+		Source:    entities.GoogleSearch,
+		Status:    entities.TaskStatusPending,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	if err := repo.Create(job); err != nil {
+		tb.Fatalf("create job %s: %v", id, err)
+	}
+}
+
+func TestTrackingJobClaimNextLease(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &TrackingJobRepository{db: tx}
+
+	base := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	createTestJob(t, repo, "lease-old", base)
+	createTestJob(t, repo, "lease-new", base.Add(time.Minute))
+
+	leaseUntil := time.Now().Add(time.Minute)
+	first, err := repo.ClaimNext("worker-1", leaseUntil)
+	if err != nil || first == nil {
+		t.Fatalf("first claim: %+v, %v", first, err)
+	}
+	if first.ID != "lease-old" || first.Status != entities.TaskStatusRunning || first.LockedBy != "worker-1" || first.StartedAt == nil {
+		t.Fatalf("expected the oldest job leased by worker-1, got %+v", first)
+	}
+
+	// Арендованный джоб другому воркеру не достается
+	second, err := repo.ClaimNext("worker-2", leaseUntil)
+	if err != nil || second == nil || second.ID != "lease-new" {
+		t.Fatalf("second claim: expected lease-new, got %+v, %v", second, err)
+	}
+
+	// Продлить аренду может только ее владелец
+	if ok, err := repo.ExtendLease("lease-old", "worker-2", leaseUntil); err != nil || ok {
+		t.Fatalf("foreign worker extended the lease: %v, %v", ok, err)
+	}
+	if ok, err := repo.ExtendLease("lease-old", "worker-1", leaseUntil.Add(time.Minute)); err != nil || !ok {
+		t.Fatalf("owner failed to extend the lease: %v, %v", ok, err)
+	}
+
+	// Снятая аренда возвращает джоб в очередь
+	if err := repo.ReleaseLease("lease-new", "worker-2"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	released, err := repo.ClaimNext("worker-3", leaseUntil)
+	if err != nil || released == nil || released.ID != "lease-new" {
+		t.Fatalf("expected released job to be claimed again, got %+v, %v", released, err)
+	}
+}
+
+func TestTrackingJobClaimNextExpiredLease(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &TrackingJobRepository{db: tx}
+
+	createTestJob(t, repo, "lease-expired", time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// Воркер упал, не сняв аренду: после ее истечения джоб подхватывает другой инстанс
+	if job, err := repo.ClaimNext("crashed", time.Now().Add(-time.Second)); err != nil || job == nil {
+		t.Fatalf("claim: %+v, %v", job, err)
+	}
+	job, err := repo.ClaimNext("worker-2", time.Now().Add(time.Minute))
+	if err != nil || job == nil || job.ID != "lease-expired" || job.LockedBy != "worker-2" {
+		t.Fatalf("expected expired lease to be reclaimed, got %+v, %v", job, err)
+	}
+
+	// Джоб, остановленный пользователем, в очередь не возвращается
+	if ok, err := repo.UpdateStatusIf("lease-expired", entities.TaskStatusRunning, entities.TaskStatusPaused); err != nil || !ok {
+		t.Fatalf("pause: %v, %v", ok, err)
+	}
+	if err := repo.ReleaseLease("lease-expired", "worker-2"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if job, err := repo.ClaimNext("worker-3", time.Now().Add(time.Minute)); err != nil || (job != nil && job.ID == "lease-expired") {
+		t.Fatalf("paused job must not be claimed, got %+v, %v", job, err)
+	}
+}
+
+// Параллельные ClaimNext из разных соединений: SKIP LOCKED не дает двум воркерам арендовать один джоб.
+// Транзакция openTestDB здесь не подходит, поэтому джобы коммитятся и удаляются в конце теста
+func TestTrackingJobClaimNextConcurrent(t *testing.T) {
+	db := connectTestDB(t)
+	repo := &TrackingJobRepository{db: db}
+
+	const jobs, workers = 20, 8
+	cleanup := func() {
+		db.Where("site_id = ?", testJobSiteID).Delete(&models.TrackingJob{})
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	base := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	ours := make(map[string]bool, jobs)
+	for i := 0; i < jobs; i++ {
+		id := fmt.Sprintf("concurrent-%02d", i)
+		createTestJob(t, repo, id, base.Add(time.Duration(i)*time.Second))
+		ours[id] = true
+	}
+
+	var mu sync.Mutex
+	claimedBy := make(map[string]string)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		workerID := fmt.Sprintf("worker-%d", w)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, err := repo.ClaimNext(workerID, time.Now().Add(time.Minute))
+				if err != nil {
+					t.Errorf("claim: %v", err)
+					return
+				}
+				if job == nil || !ours[job.ID] {
+					return
+				}
+
+				mu.Lock()
+				if previous, ok := claimedBy[job.ID]; ok {
+					t.Errorf("job %s claimed by both %s and %s", job.ID, previous, workerID)
+				}
+				claimedBy[job.ID] = workerID
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(claimedBy) != jobs {
+		t.Fatalf("expected all %d jobs to be claimed once, got %d", jobs, len(claimedBy))
+	}
+
+	var stored []models.TrackingJob
+	if err := db.Where("site_id = ?", testJobSiteID).Find(&stored).Error; err != nil {
+		t.Fatalf("load jobs: %v", err)
+	}
+	for _, job := range stored {
+		if job.LockedBy != claimedBy[job.ID] {
+			t.Errorf("job %s is leased by %q, claim returned it to %q", job.ID, job.LockedBy, claimedBy[job.ID])
+		}
+	}
+}
+
+func TestTrackingJobRequeueFailed(t *testing.T) {
+	tx := openTestDB(t)
+	jobs := &TrackingJobRepository{db: tx}
+	tasks := &TrackingTaskRepository{db: tx}
+
+	createTestJob(t, jobs, "requeue", time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+	now := time.Now()
+	statuses := []entities.TrackingTaskStatus{entities.TaskStatusCompleted, entities.TaskStatusFailed, entities.TaskStatusFailed}
+	batch := make([]*entities.TrackingTask, len(statuses))
+	for i, status := range statuses {
+		batch[i] = &entities.TrackingTask{
+			ID: fmt.Sprintf("requeue-%d", i), JobID: "requeue", KeywordID: i + 1, SiteID: testJobSiteID,
+			Source: entities.GoogleSearch, Status: status, Error: "failed", CreatedAt: now, UpdatedAt: now,
+		}
+	}
+	if err := tasks.CreateBatch(batch); err != nil {
+		t.Fatalf("create tasks: %v", err)
+	}
+	if err := tx.Model(&models.TrackingJob{}).Where("id = ?", "requeue").
+		Updates(map[string]interface{}{"status": string(entities.TaskStatusCompleted), "failed_tasks": 2, "error": "All tasks failed"}).Error; err != nil {
+		t.Fatalf("finish job: %v", err)
+	}
+
+	// Статус уже сменился: ни джоб, ни задачи не меняются
+	updated, reset, err := jobs.RequeueFailed("requeue", entities.TaskStatusFailed, 5)
+	if err != nil || updated || reset != 0 {
+		t.Fatalf("stale status: expected no update, got %v, %d, %v", updated, reset, err)
+	}
+	if counts, _ := tasks.CountByStatus("requeue"); counts[entities.TaskStatusFailed] != 2 {
+		t.Fatalf("stale status must not touch tasks, got %v", counts)
+	}
+
+	updated, reset, err = jobs.RequeueFailed("requeue", entities.TaskStatusCompleted, 5)
+	if err != nil || !updated || reset != 2 {
+		t.Fatalf("requeue: expected 2 tasks reset, got %v, %d, %v", updated, reset, err)
+	}
+	job, err := jobs.GetByID("requeue")
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	if job.Status != entities.TaskStatusPending || job.FailedTasks != 0 || job.Error != "" || job.CompletedAt != nil || job.EstimatedCost != 5 {
+		t.Fatalf("expected pending job without failures and with the new estimate, got %+v", job)
+	}
+	pending, err := tasks.GetPendingByJobID("requeue")
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("expected 2 pending tasks, got %d, %v", len(pending), err)
+	}
+	for _, task := range pending {
+		if task.Error != "" || task.CompletedAt != nil {
+			t.Fatalf("requeued task keeps its result: %+v", task)
+		}
+	}
+
+	// Без failed-задач транзакция откатывается, и джоб остается в прежнем статусе
+	if err := tx.Model(&models.TrackingTask{}).Where("job_id = ?", "requeue").Update("status", string(entities.TaskStatusCompleted)).Error; err != nil {
+		t.Fatalf("complete tasks: %v", err)
+	}
+	if err := tx.Model(&models.TrackingJob{}).Where("id = ?", "requeue").Update("status", string(entities.TaskStatusCompleted)).Error; err != nil {
+		t.Fatalf("complete job: %v", err)
+	}
+	updated, reset, err = jobs.RequeueFailed("requeue", entities.TaskStatusCompleted, 5)
+	if err != nil || !updated || reset != 0 {
+		t.Fatalf("nothing to retry: expected updated without reset, got %v, %d, %v", updated, reset, err)
+	}
+	if job, _ := jobs.GetByID("requeue"); job.Status != entities.TaskStatusCompleted {
+		t.Fatalf("job without failed tasks must stay completed, got %s", job.Status)
+	}
+}
@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+)
+
+type trackingScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewTrackingScheduleRepository(db *gorm.DB) repositories.TrackingScheduleRepository {
+	return &trackingScheduleRepository{db: db}
+}
+
+func (r *trackingScheduleRepository) Create(schedule *entities.TrackingSchedule) error {
+	model, err := r.toModel(schedule)
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.Create(model).Error; err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	schedule.ID = model.ID
+	schedule.CreatedAt = model.CreatedAt
+	schedule.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+func (r *trackingScheduleRepository) GetByID(id int) (*entities.TrackingSchedule, error) {
+	var model models.TrackingSchedule
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(&model)
+}
+
+func (r *trackingScheduleRepository) GetAll(workspaceID, siteID *int) ([]*entities.TrackingSchedule, error) {
+	query := r.db.Model(&models.TrackingSchedule{})
+	if workspaceID != nil {
+		query = query.Where("site_id IN (SELECT id FROM sites WHERE workspace_id = ?)", *workspaceID)
+	}
+	if siteID != nil {
+		query = query.Where("site_id = ?", *siteID)
+	}
+
+	var scheduleModels []models.TrackingSchedule
+	if err := query.Order("id").Find(&scheduleModels).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomainList(scheduleModels)
+}
+
+func (r *trackingScheduleRepository) GetDue(now time.Time) ([]*entities.TrackingSchedule, error) {
+	var scheduleModels []models.TrackingSchedule
+	if err := r.db.Where("enabled = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, now).
+		Order("next_run_at").
+		Find(&scheduleModels).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomainList(scheduleModels)
+}
+
+// Claim сравнивает next_run_at на точное равенство, поэтому время приводится к точности timestamptz
+func (r *trackingScheduleRepository) Claim(id int, dueAt time.Time, nextRunAt *time.Time) (bool, error) {
+	result := r.db.Model(&models.TrackingSchedule{}).
+		Where("id = ? AND enabled = ? AND next_run_at = ?", id, true, dueAt.Truncate(time.Microsecond)).
+		Updates(map[string]interface{}{
+			"next_run_at": truncateRunAt(nextRunAt),
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *trackingScheduleRepository) Update(schedule *entities.TrackingSchedule) error {
+	model, err := r.toModel(schedule)
+	if err != nil {
+		return err
+	}
+
+	// Save пишет и нулевые значения: выключенный флаг и сброшенное время следующего запуска
+	if err := r.db.Save(model).Error; err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	schedule.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+func (r *trackingScheduleRepository) UpdateRunInfo(id int, lastRunAt time.Time, nextRunAt *time.Time, lastJobID, lastError string) error {
+	return r.db.Model(&models.TrackingSchedule{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_run_at": lastRunAt,
+			"next_run_at": truncateRunAt(nextRunAt),
+			"last_job_id": lastJobID,
+			"last_error":  lastError,
+			"updated_at":  time.Now(),
+		}).Error
+}
+
+func (r *trackingScheduleRepository) Delete(id int) error {
+	return r.db.Delete(&models.TrackingSchedule{}, id).Error
+}
+
+func (r *trackingScheduleRepository) DeleteBySiteID(siteID int) error {
+	return r.db.Where("site_id = ?", siteID).Delete(&models.TrackingSchedule{}).Error
+}
+
+func (r *trackingScheduleRepository) toModel(schedule *entities.TrackingSchedule) (*models.TrackingSchedule, error) {
+	params, err := json.Marshal(schedule.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TrackingSchedule{
+		ID:        schedule.ID,
+		SiteID:    schedule.SiteID,
+		Source:    schedule.Source,
+		Params:    string(params),
+		CronExpr:  schedule.CronExpr,
+		Timezone:  schedule.Timezone,
+		Enabled:   schedule.Enabled,
+		LastRunAt: schedule.LastRunAt,
+		NextRunAt: truncateRunAt(schedule.NextRunAt),
+		LastJobID: schedule.LastJobID,
+		LastError: schedule.LastError,
+		CreatedAt: schedule.CreatedAt,
+	}, nil
+}
+
+// truncateRunAt отбрасывает наносекунды: PostgreSQL округляет их до микросекунд,
+// и записанное время иначе не совпало бы с тем, которое потом передается в Claim
+func truncateRunAt(runAt *time.Time) *time.Time {
+	if runAt == nil {
+		return nil
+	}
+	truncated := runAt.Truncate(time.Microsecond)
+	return &truncated
+}
+
+func (r *trackingScheduleRepository) toDomain(model *models.TrackingSchedule) (*entities.TrackingSchedule, error) {
+	schedule := &entities.TrackingSchedule{
+		ID:        model.ID,
+		SiteID:    model.SiteID,
+		Source:    model.Source,
+		CronExpr:  model.CronExpr,
+		Timezone:  model.Timezone,
+		Enabled:   model.Enabled,
+		LastRunAt: model.LastRunAt,
+		NextRunAt: model.NextRunAt,
+		LastJobID: model.LastJobID,
+		LastError: model.LastError,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+
+	if err := json.Unmarshal([]byte(model.Params), &schedule.Params); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (r *trackingScheduleRepository) toDomainList(scheduleModels []models.TrackingSchedule) ([]*entities.TrackingSchedule, error) {
+	schedules := make([]*entities.TrackingSchedule, len(scheduleModels))
+	for i := range scheduleModels {
+		schedule, err := r.toDomain(&scheduleModels[i])
+		if err != nil {
+			return nil, err
+		}
+		schedules[i] = schedule
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+func TestTrackingScheduleClaimWithNanoseconds(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &trackingScheduleRepository{db: tx}
+
+	// Время с наносекундами, которые timestamptz не хранит
+	dueAt := time.Date(2026, 10, 16, 9, 0, 0, 123456789, time.UTC)
+	schedule := &entities.TrackingSchedule{
+		SiteID:    1,
+		Source:    entities.GoogleSearch,
+		CronExpr:  "0 9 * * *",
+		Timezone:  "UTC",
+		Enabled:   true,
+		NextRunAt: &dueAt,
+	}
+	if err := repo.Create(schedule); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	nextRunAt := dueAt.Add(24 * time.Hour)
+	claimed, err := repo.Claim(schedule.ID, dueAt, &nextRunAt)
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if !claimed {
+		t.Fatal("expected the due schedule to be claimed")
+	}
+
+	// Второй инстанс с тем же временем запуска расписание уже не получает
+	claimed, err = repo.Claim(schedule.ID, dueAt, &nextRunAt)
+	if err != nil {
+		t.Fatalf("second claim: %v", err)
+	}
+	if claimed {
+		t.Fatal("schedule must be claimed only once")
+	}
+
+	// Перенесенное время, прочитанное из БД, снова подходит для Claim
+	stored, err := repo.GetByID(schedule.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if claimed, err := repo.Claim(schedule.ID, *stored.NextRunAt, nil); err != nil || !claimed {
+		t.Fatalf("expected stored next run to be claimable, got %v, %v", claimed, err)
+	}
+}
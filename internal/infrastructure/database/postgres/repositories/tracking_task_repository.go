@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"time"
+
 	"go-seo/internal/domain/entities"
 	"go-seo/internal/domain/repositories"
 	"go-seo/internal/infrastructure/database/postgres/models"
@@ -17,48 +19,20 @@ func NewTrackingTaskRepository(db *gorm.DB) repositories.TrackingTaskRepository
 }
 
 func (r *TrackingTaskRepository) Create(task *entities.TrackingTask) error {
-	model := &models.TrackingTask{
-		ID:                task.ID,
-		JobID:             task.JobID,
-		KeywordID:         task.KeywordID,
-		SiteID:            task.SiteID,
-		Source:            task.Source,
-		Status:            string(task.Status),
-		CreatedAt:         task.CreatedAt,
-		UpdatedAt:         task.UpdatedAt,
-		CompletedAt:       task.CompletedAt,
-		RetryCount:        task.RetryCount,
-		MaxRetries:        task.MaxRetries,
-		Error:             task.Error,
-		Device:            task.Device,
-		OS:                task.OS,
-		Ads:               task.Ads,
-		Country:           task.Country,
-		Lang:              task.Lang,
-		Pages:             task.Pages,
-		Subdomains:        task.Subdomains,
-		XMLUserID:         task.XMLUserID,
-		XMLAPIKey:         task.XMLAPIKey,
-		XMLBaseURL:        task.XMLBaseURL,
-		TBS:               task.TBS,
-		Filter:            task.Filter,
-		Highlights:        task.Highlights,
-		NFPR:              task.NFPR,
-		Loc:               task.Loc,
-		AI:                task.AI,
-		Raw:               task.Raw,
-		GroupBy:           task.GroupBy,
-		Within:            task.Within,
-		LR:                task.LR,
-		InIndex:           task.InIndex,
-		Strict:            task.Strict,
-		Organic:           task.Organic,
-		Regions:           task.Regions,
-		FilterGroupID:     task.FilterGroupID,
-		WordstatQueryType: task.WordstatQueryType,
+	return r.db.Create(r.toModel(task)).Error
+}
+
+func (r *TrackingTaskRepository) CreateBatch(tasks []*entities.TrackingTask) error {
+	if len(tasks) == 0 {
+		return nil
 	}
 
-	return r.db.Create(model).Error
+	taskModels := make([]*models.TrackingTask, len(tasks))
+	for i, task := range tasks {
+		taskModels[i] = r.toModel(task)
+	}
+
+	return r.db.CreateInBatches(taskModels, 500).Error
 }
 
 func (r *TrackingTaskRepository) GetByID(id string) (*entities.TrackingTask, error) {
@@ -84,49 +58,44 @@ func (r *TrackingTaskRepository) GetByJobID(jobID string) ([]*entities.TrackingT
 	return tasks, nil
 }
 
-func (r *TrackingTaskRepository) Update(task *entities.TrackingTask) error {
-	model := &models.TrackingTask{
-		ID:                task.ID,
-		JobID:             task.JobID,
-		KeywordID:         task.KeywordID,
-		SiteID:            task.SiteID,
-		Source:            task.Source,
-		Status:            string(task.Status),
-		CreatedAt:         task.CreatedAt,
-		UpdatedAt:         task.UpdatedAt,
-		CompletedAt:       task.CompletedAt,
-		RetryCount:        task.RetryCount,
-		MaxRetries:        task.MaxRetries,
-		Error:             task.Error,
-		Device:            task.Device,
-		OS:                task.OS,
-		Ads:               task.Ads,
-		Country:           task.Country,
-		Lang:              task.Lang,
-		Pages:             task.Pages,
-		Subdomains:        task.Subdomains,
-		XMLUserID:         task.XMLUserID,
-		XMLAPIKey:         task.XMLAPIKey,
-		XMLBaseURL:        task.XMLBaseURL,
-		TBS:               task.TBS,
-		Filter:            task.Filter,
-		Highlights:        task.Highlights,
-		NFPR:              task.NFPR,
-		Loc:               task.Loc,
-		AI:                task.AI,
-		Raw:               task.Raw,
-		GroupBy:           task.GroupBy,
-		Within:            task.Within,
-		LR:                task.LR,
-		InIndex:           task.InIndex,
-		Strict:            task.Strict,
-		Organic:           task.Organic,
-		Regions:           task.Regions,
-		FilterGroupID:     task.FilterGroupID,
-		WordstatQueryType: task.WordstatQueryType,
+func (r *TrackingTaskRepository) GetPendingByJobID(jobID string) ([]*entities.TrackingTask, error) {
+	var models []models.TrackingTask
+	if err := r.db.Where("job_id = ? AND status = ?", jobID, string(entities.TaskStatusPending)).
+		Order("created_at, id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	var tasks []*entities.TrackingTask
+	for _, model := range models {
+		tasks = append(tasks, r.modelToEntity(&model))
 	}
 
-	return r.db.Save(model).Error
+	return tasks, nil
+}
+
+func (r *TrackingTaskRepository) CountByStatus(jobID string) (map[entities.TrackingTaskStatus]int, error) {
+	var rows []struct {
+		Status string
+		Count  int
+	}
+	if err := r.db.Model(&models.TrackingTask{}).
+		Select("status, COUNT(*) AS count").
+		Where("job_id = ?", jobID).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[entities.TrackingTaskStatus]int, len(rows))
+	for _, row := range rows {
+		counts[entities.TrackingTaskStatus(row.Status)] = row.Count
+	}
+
+	return counts, nil
+}
+
+func (r *TrackingTaskRepository) Update(task *entities.TrackingTask) error {
+	return r.db.Save(r.toModel(task)).Error
 }
 
 func (r *TrackingTaskRepository) UpdateStatus(id string, status entities.TrackingTaskStatus) error {
@@ -141,6 +110,92 @@ func (r *TrackingTaskRepository) UpdateRetryCount(id string, retryCount int) err
 		Update("retry_count", retryCount).Error
 }
 
+func (r *TrackingTaskRepository) Finish(id string, status entities.TrackingTaskStatus, errMsg string, attempts int) error {
+	now := time.Now()
+	return r.db.Model(&models.TrackingTask{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       string(status),
+			"error":        errMsg,
+			"attempts":     gorm.Expr("attempts + ?", attempts),
+			"completed_at": now,
+			"updated_at":   now,
+		}).Error
+}
+
+func (r *TrackingTaskRepository) GetOutcomesByJobID(jobID string, status *entities.TrackingTaskStatus, page, perPage int) ([]*entities.TrackingTaskOutcome, int64, error) {
+	query := r.db.Table("tracking_tasks AS t").Where("t.job_id = ?", jobID)
+	if status != nil {
+		query = query.Where("t.status = ?", string(*status))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		TaskID            string
+		KeywordID         int
+		Keyword           string
+		WordstatQueryType string
+		Status            string
+		Rank              *int
+		URL               string
+		Title             string
+		Error             string
+		Attempts          int
+		CompletedAt       *time.Time
+	}
+	// Берем последний результат задачи: после retry-failed их может быть несколько
+	if err := query.
+		Select(`t.id AS task_id, t.keyword_id, COALESCE(k.value, '') AS keyword, t.wordstat_query_type,
+			t.status, r.rank, COALESCE(r.url, '') AS url, COALESCE(r.title, '') AS title,
+			t.error, t.attempts, t.completed_at`).
+		Joins("LEFT JOIN keywords k ON k.id = t.keyword_id").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT rank, url, title FROM tracking_results
+			WHERE task_id = t.id AND success
+			ORDER BY id DESC LIMIT 1
+		) r ON true`).
+		Order("t.created_at, t.id").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	outcomes := make([]*entities.TrackingTaskOutcome, len(rows))
+	for i, row := range rows {
+		outcomes[i] = &entities.TrackingTaskOutcome{
+			TaskID:            row.TaskID,
+			KeywordID:         row.KeywordID,
+			Keyword:           row.Keyword,
+			WordstatQueryType: row.WordstatQueryType,
+			Status:            entities.TrackingTaskStatus(row.Status),
+			Rank:              row.Rank,
+			URL:               row.URL,
+			Title:             row.Title,
+			Error:             row.Error,
+			Attempts:          row.Attempts,
+			CompletedAt:       row.CompletedAt,
+		}
+	}
+
+	return outcomes, total, nil
+}
+
+func (r *TrackingTaskRepository) CancelPending(jobID string) error {
+	now := time.Now()
+	return r.db.Model(&models.TrackingTask{}).
+		Where("job_id = ? AND status = ?", jobID, string(entities.TaskStatusPending)).
+		Updates(map[string]interface{}{
+			"status":       string(entities.TaskStatusCancelled),
+			"completed_at": now,
+			"updated_at":   now,
+		}).Error
+}
+
 func (r *TrackingTaskRepository) GetPendingTasks(limit int) ([]*entities.TrackingTask, error) {
 	var models []models.TrackingTask
 	if err := r.db.Where("status = ?", string(entities.TaskStatusPending)).
@@ -197,6 +252,7 @@ func (r *TrackingTaskRepository) modelToEntity(model *models.TrackingTask) *enti
 		RetryCount:        model.RetryCount,
 		MaxRetries:        model.MaxRetries,
 		Error:             model.Error,
+		Attempts:          model.Attempts,
 		Device:            model.Device,
 		OS:                model.OS,
 		Ads:               model.Ads,
@@ -204,9 +260,6 @@ func (r *TrackingTaskRepository) modelToEntity(model *models.TrackingTask) *enti
 		Lang:              model.Lang,
 		Pages:             model.Pages,
 		Subdomains:        model.Subdomains,
-		XMLUserID:         model.XMLUserID,
-		XMLAPIKey:         model.XMLAPIKey,
-		XMLBaseURL:        model.XMLBaseURL,
 		TBS:               model.TBS,
 		Filter:            model.Filter,
 		Highlights:        model.Highlights,
@@ -225,3 +278,44 @@ func (r *TrackingTaskRepository) modelToEntity(model *models.TrackingTask) *enti
 		WordstatQueryType: model.WordstatQueryType,
 	}
 }
+
+func (r *TrackingTaskRepository) toModel(task *entities.TrackingTask) *models.TrackingTask {
+	return &models.TrackingTask{
+		ID:                task.ID,
+		JobID:             task.JobID,
+		KeywordID:         task.KeywordID,
+		SiteID:            task.SiteID,
+		Source:            task.Source,
+		Status:            string(task.Status),
+		CreatedAt:         task.CreatedAt,
+		UpdatedAt:         task.UpdatedAt,
+		CompletedAt:       task.CompletedAt,
+		RetryCount:        task.RetryCount,
+		MaxRetries:        task.MaxRetries,
+		Error:             task.Error,
+		Attempts:          task.Attempts,
+		Device:            task.Device,
+		OS:                task.OS,
+		Ads:               task.Ads,
+		Country:           task.Country,
+		Lang:              task.Lang,
+		Pages:             task.Pages,
+		Subdomains:        task.Subdomains,
+		TBS:               task.TBS,
+		Filter:            task.Filter,
+		Highlights:        task.Highlights,
+		NFPR:              task.NFPR,
+		Loc:               task.Loc,
+		AI:                task.AI,
+		Raw:               task.Raw,
+		GroupBy:           task.GroupBy,
+		Within:            task.Within,
+		LR:                task.LR,
+		InIndex:           task.InIndex,
+		Strict:            task.Strict,
+		Organic:           task.Organic,
+		Regions:           task.Regions,
+		FilterGroupID:     task.FilterGroupID,
+		WordstatQueryType: task.WordstatQueryType,
+	}
+}
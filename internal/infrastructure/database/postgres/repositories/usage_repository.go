@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+)
+
+const budgetLockKey = 7240319
+
+// reservedJobsQuery считает для каждого незавершенного джоба остаток его оценки за вычетом уже записанного расхода
+const reservedJobsQuery = `SELECT COALESCE(SUM(GREATEST(j.estimated_cost - COALESCE(
+	(SELECT SUM(u.cost) FROM provider_usage u WHERE u.job_id = j.id), 0), 0)), 0)
+FROM tracking_jobs j
+WHERE j.status IN ? AND `
+
+var activeJobStatuses = []string{
+	string(entities.TaskStatusPending),
+	string(entities.TaskStatusRunning),
+	string(entities.TaskStatusPaused),
+}
+
+type usageRepository struct {
+	db *gorm.DB
+}
+
+func NewUsageRepository(db *gorm.DB) repositories.UsageRepository {
+	return &usageRepository{db: db}
+}
+
+func (r *usageRepository) Create(usage *entities.ProviderUsage) error {
+	model := &models.ProviderUsage{
+		JobID:    usage.JobID,
+		SiteID:   usage.SiteID,
+		Source:   usage.Source,
+		Provider: usage.Provider,
+		Account:  usage.Account,
+		Requests: usage.Requests,
+		Cost:     usage.Cost,
+	}
+
+	if err := r.db.Create(model).Error; err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	usage.ID = model.ID
+	usage.CreatedAt = model.CreatedAt
+	return nil
+}
+
+func (r *usageRepository) GetSpentSince(siteID int, since time.Time) (float64, error) {
+	var spent float64
+	err := r.db.Model(&models.ProviderUsage{}).
+		Select("COALESCE(SUM(cost), 0)").
+		Where("site_id = ? AND created_at >= ?", siteID, since).
+		Scan(&spent).Error
+
+	return spent, err
+}
+
+func (r *usageRepository) GetWorkspaceSpentSince(workspaceID int, since time.Time) (float64, error) {
+	var spent float64
+	err := r.db.Model(&models.ProviderUsage{}).
+		Select("COALESCE(SUM(cost), 0)").
+		Where("site_id IN (SELECT id FROM sites WHERE workspace_id = ?) AND created_at >= ?", workspaceID, since).
+		Scan(&spent).Error
+
+	return spent, err
+}
+
+func (r *usageRepository) GetReserved(siteID int) (float64, error) {
+	var reserved float64
+	err := r.db.Raw(reservedJobsQuery+"j.site_id = ?", activeJobStatuses, siteID).
+		Scan(&reserved).Error
+
+	return reserved, err
+}
+
+func (r *usageRepository) GetWorkspaceReserved(workspaceID int) (float64, error) {
+	var reserved float64
+	err := r.db.Raw(reservedJobsQuery+"j.site_id IN (SELECT id FROM sites WHERE workspace_id = ?)", activeJobStatuses, workspaceID).
+		Scan(&reserved).Error
+
+	return reserved, err
+}
+
+// WithBudgetLock держит транзакционную advisory-блокировку, пока выполняется fn. Сам fn пишет через
+// свои репозитории вне этой транзакции, поэтому его изменения видны следующему владельцу блокировки.
+func (r *usageRepository) WithBudgetLock(workspaceID int, fn func() error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", budgetLockKey, workspaceID).Error; err != nil {
+			return err
+		}
+		return fn()
+	})
+}
+
+func (r *usageRepository) GetJobTotals(jobID string) (int, float64, error) {
+	var totals struct {
+		Requests int
+		Cost     float64
+	}
+	err := r.db.Model(&models.ProviderUsage{}).
+		Select("COALESCE(SUM(requests), 0) AS requests, COALESCE(SUM(cost), 0) AS cost").
+		Where("job_id = ?", jobID).
+		Scan(&totals).Error
+
+	return totals.Requests, totals.Cost, err
+}
+
+func (r *usageRepository) GetDailySummary(workspaceID, siteID *int, source *string, dateFrom, dateTo time.Time) ([]*entities.UsageSummary, error) {
+	query := r.db.Model(&models.ProviderUsage{}).
+		Select("DATE(created_at) AS date, site_id, source, SUM(requests) AS requests, SUM(cost) AS cost").
+		Where("created_at >= ? AND created_at < ?", dateFrom, dateTo)
+	if workspaceID != nil {
+		query = query.Where("site_id IN (SELECT id FROM sites WHERE workspace_id = ?)", *workspaceID)
+	}
+	if siteID != nil {
+		query = query.Where("site_id = ?", *siteID)
+	}
+	if source != nil {
+		query = query.Where("source = ?", *source)
+	}
+
+	var rows []*entities.UsageSummary
+	if err := query.Group("DATE(created_at), site_id, source").
+		Order("date, site_id, source").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
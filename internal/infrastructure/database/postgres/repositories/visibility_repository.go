@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+)
+
+// Позиции дня берутся из детальных записей и из дневных агрегатов, если день уже свернут заданием хранения.
+// Частота - последняя проверка Wordstat (wordstat_query_type = 'default') не позже конца дня
+const keywordRanksQuery = `
+WITH checks AS (
+	SELECT keyword_id, source, filter_group_id, rank, date
+	FROM positions
+	WHERE site_id = @site AND competitor_id IS NULL AND source <> 'wordstat'
+	  AND date >= CAST(@day AS date) AND date < CAST(@day AS date) + 1
+	UNION ALL
+	SELECT keyword_id, source, filter_group_id, rank, date
+	FROM position_rollups
+	WHERE site_id = @site AND competitor_id IS NULL AND source <> 'wordstat'
+	  AND period = 'day' AND period_start = CAST(@day AS date)
+),
+ranks AS (
+	SELECT DISTINCT ON (keyword_id, source, COALESCE(filter_group_id, 0)) keyword_id, source, filter_group_id, rank
+	FROM checks
+	ORDER BY keyword_id, source, COALESCE(filter_group_id, 0), date DESC
+),
+frequencies AS (
+	SELECT DISTINCT ON (keyword_id) keyword_id, rank AS frequency
+	FROM (
+		SELECT keyword_id, rank, date
+		FROM positions
+		WHERE site_id = @site AND source = 'wordstat' AND wordstat_query_type = 'default'
+		  AND date < CAST(@day AS date) + 1
+		UNION ALL
+		SELECT keyword_id, rank, date
+		FROM position_rollups
+		WHERE site_id = @site AND source = 'wordstat' AND wordstat_query_type = 'default'
+		  AND date < CAST(@day AS date) + 1
+	) AS wordstat
+	ORDER BY keyword_id, date DESC
+)
+SELECT r.keyword_id, k.group_id, r.source, r.filter_group_id, r.rank, COALESCE(f.frequency, 0) AS frequency
+FROM ranks r
+JOIN keywords k ON k.id = r.keyword_id
+LEFT JOIN frequencies f ON f.keyword_id = r.keyword_id
+`
+
+type visibilityRepository struct {
+	db *gorm.DB
+}
+
+func NewVisibilityRepository(db *gorm.DB) repositories.VisibilityRepository {
+	return &visibilityRepository{db: db}
+}
+
+func (r *visibilityRepository) GetKeywordRanks(siteID int, day time.Time) ([]*entities.KeywordRank, error) {
+	var rows []struct {
+		KeywordID     int
+		GroupID       *int
+		Source        string
+		FilterGroupID *int
+		Rank          int
+		Frequency     int
+	}
+	if err := r.db.Raw(keywordRanksQuery, map[string]interface{}{
+		"site": siteID,
+		"day":  day.Format("2006-01-02"),
+	}).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	ranks := make([]*entities.KeywordRank, len(rows))
+	for i, row := range rows {
+		ranks[i] = &entities.KeywordRank{
+			KeywordID:     row.KeywordID,
+			GroupID:       row.GroupID,
+			Source:        row.Source,
+			FilterGroupID: row.FilterGroupID,
+			Rank:          row.Rank,
+			Frequency:     row.Frequency,
+		}
+	}
+	return ranks, nil
+}
+
+func (r *visibilityRepository) ReplaceDay(siteID int, day time.Time, rows []*entities.SiteVisibility) error {
+	date := day.Format("2006-01-02")
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("site_id = ? AND date = CAST(? AS date)", siteID, date).Delete(&models.SiteVisibility{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		records := make([]models.SiteVisibility, len(rows))
+		for i, row := range rows {
+			records[i] = models.SiteVisibility{
+				SiteID:           siteID,
+				Date:             row.Date,
+				Source:           row.Source,
+				GroupID:          intOrZero(row.GroupID),
+				FilterGroupID:    intOrZero(row.FilterGroupID),
+				KeywordsCount:    row.KeywordsCount,
+				FoundCount:       row.FoundCount,
+				Top10Count:       row.Top10Count,
+				Frequency:        row.Frequency,
+				EstimatedTraffic: row.EstimatedTraffic,
+				Visibility:       row.Visibility,
+				CalculatedAt:     row.CalculatedAt,
+			}
+		}
+		return tx.CreateInBatches(records, 500).Error
+	})
+}
+
+func (r *visibilityRepository) GetUncalculatedDays(siteID int, dateFrom, dateTo time.Time) ([]time.Time, error) {
+	rows, err := r.db.Raw(`
+		SELECT day FROM (
+			SELECT DISTINCT DATE(date) AS day
+			FROM positions
+			WHERE site_id = @site AND competitor_id IS NULL AND source <> 'wordstat'
+			  AND date >= CAST(@from AS date) AND date < CAST(@to AS date) + 1
+			UNION
+			SELECT period_start
+			FROM position_rollups
+			WHERE site_id = @site AND period = 'day' AND competitor_id IS NULL AND source <> 'wordstat'
+			  AND period_start >= CAST(@from AS date) AND period_start <= CAST(@to AS date)
+		) AS tracked
+		WHERE NOT EXISTS (SELECT 1 FROM site_visibility v WHERE v.site_id = @site AND v.date = tracked.day)
+		ORDER BY day
+	`, map[string]interface{}{
+		"site": siteID,
+		"from": dateFrom.Format("2006-01-02"),
+		"to":   dateTo.Format("2006-01-02"),
+	}).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
+func (r *visibilityRepository) GetSeries(siteID int, source *string, groupID, filterGroupID *int, dateFrom, dateTo time.Time) ([]*entities.SiteVisibility, error) {
+	query := r.db.Where("site_id = ? AND date >= CAST(? AS date) AND date <= CAST(? AS date)",
+		siteID, dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02")).
+		Where("group_id = ? AND filter_group_id = ?", intOrZero(groupID), intOrZero(filterGroupID))
+	if source != nil {
+		query = query.Where("source = ?", *source)
+	}
+
+	var records []models.SiteVisibility
+	if err := query.Order("date, source").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	series := make([]*entities.SiteVisibility, len(records))
+	for i, record := range records {
+		series[i] = &entities.SiteVisibility{
+			SiteID:           record.SiteID,
+			Date:             record.Date,
+			Source:           record.Source,
+			GroupID:          zeroAsNil(record.GroupID),
+			FilterGroupID:    zeroAsNil(record.FilterGroupID),
+			KeywordsCount:    record.KeywordsCount,
+			FoundCount:       record.FoundCount,
+			Top10Count:       record.Top10Count,
+			Frequency:        record.Frequency,
+			EstimatedTraffic: record.EstimatedTraffic,
+			Visibility:       record.Visibility,
+			CalculatedAt:     record.CalculatedAt,
+		}
+	}
+	return series, nil
+}
+
+func intOrZero(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func zeroAsNil(value int) *int {
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+// seedVisibilitySite создает сайт с ключевым словом в группе и ключевым словом без группы
+func seedVisibilitySite(tb testing.TB, tx *gorm.DB) (siteID, groupID, grouped, ungrouped int) {
+	tb.Helper()
+
+	steps := []func() error{
+		func() error {
+			return tx.Raw("INSERT INTO sites (workspace_id, domain, created_at, updated_at) VALUES (1, 'visibility.example', NOW(), NOW()) RETURNING id").
+				Scan(&siteID).Error
+		},
+		func() error {
+			return tx.Raw("INSERT INTO groups (name, site_id, created_at, updated_at) VALUES ('group', ?, NOW(), NOW()) RETURNING id", siteID).
+				Scan(&groupID).Error
+		},
+		func() error {
+			return tx.Raw("INSERT INTO keywords (value, site_id, group_id, created_at, updated_at) VALUES ('grouped', ?, ?, NOW(), NOW()) RETURNING id",
+				siteID, groupID).Scan(&grouped).Error
+		},
+		func() error {
+			return tx.Raw("INSERT INTO keywords (value, site_id, created_at, updated_at) VALUES ('ungrouped', ?, NOW(), NOW()) RETURNING id", siteID).
+				Scan(&ungrouped).Error
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			tb.Fatalf("seed: %v", err)
+		}
+	}
+	return siteID, groupID, grouped, ungrouped
+}
+
+func insertVisibilityPosition(tb testing.TB, tx *gorm.DB, keywordID, siteID int, competitorID *int, source string, rank int, date time.Time, queryType string) {
+	tb.Helper()
+
+	if err := tx.Exec(`
+		INSERT INTO positions (keyword_id, site_id, competitor_id, rank, url, title, source, device, ads, pages, date,
+			wordstat_query_type, created_at, updated_at)
+		VALUES (?, ?, ?, ?, '', '', ?, 'desktop', false, 1, ?, ?, NOW(), NOW())`,
+		keywordID, siteID, competitorID, rank, source, date, queryType).Error; err != nil {
+		tb.Fatalf("insert position: %v", err)
+	}
+}
+
+func TestVisibilityKeywordRanks(t *testing.T) {
+	tx := openTestDB(t)
+	repo := &visibilityRepository{db: tx}
+	siteID, groupID, grouped, ungrouped := seedVisibilitySite(t, tx)
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	// Проверки около полудня UTC попадают в тот же день при любой зоне сессии БД
+	noon := day.Add(12 * time.Hour)
+	competitorID := 1
+	// За день берется последняя проверка; позиции конкурента не учитываются
+	insertVisibilityPosition(t, tx, grouped, siteID, nil, entities.GoogleSearch, 5, noon.Add(-time.Hour), "")
+	insertVisibilityPosition(t, tx, grouped, siteID, nil, entities.GoogleSearch, 3, noon.Add(time.Hour), "")
+	insertVisibilityPosition(t, tx, ungrouped, siteID, nil, entities.GoogleSearch, 0, noon.Add(-time.Hour), "")
+	insertVisibilityPosition(t, tx, ungrouped, siteID, &competitorID, entities.GoogleSearch, 1, noon.Add(-time.Hour), "")
+	// Частота - последняя не позже дня; более поздняя проверка Wordstat не влияет на прошлый день
+	insertVisibilityPosition(t, tx, grouped, siteID, nil, "wordstat", 1000, noon.AddDate(0, 0, -2), "default")
+	insertVisibilityPosition(t, tx, grouped, siteID, nil, "wordstat", 5000, noon.AddDate(0, 0, 1), "default")
+
+	ranks, err := repo.GetKeywordRanks(siteID, day)
+	if err != nil {
+		t.Fatalf("ranks: %v", err)
+	}
+	byKeyword := make(map[int]*entities.KeywordRank, len(ranks))
+	for _, rank := range ranks {
+		byKeyword[rank.KeywordID] = rank
+	}
+	if len(ranks) != 2 {
+		t.Fatalf("expected ranks of 2 keywords, got %d", len(ranks))
+	}
+	if rank := byKeyword[grouped]; rank == nil || rank.Rank != 3 || rank.Frequency != 1000 || rank.GroupID == nil || *rank.GroupID != groupID {
+		t.Fatalf("unexpected rank of the grouped keyword: %+v", rank)
+	}
+	if rank := byKeyword[ungrouped]; rank == nil || rank.Rank != 0 || rank.Frequency != 0 || rank.GroupID != nil {
+		t.Fatalf("unexpected rank of the ungrouped keyword: %+v", rank)
+	}
+
+	// День с проверками без сохраненной видимости досчитывается, после сохранения - нет
+	from, to := day.AddDate(0, 0, -3), day.AddDate(0, 0, 3)
+	missing, err := repo.GetUncalculatedDays(siteID, from, to)
+	if err != nil || len(missing) != 1 || missing[0].Format("2006-01-02") != "2026-10-01" {
+		t.Fatalf("expected 2026-10-01 to be uncalculated, got %v, %v", missing, err)
+	}
+
+	rows := []*entities.SiteVisibility{
+		{Date: noon, Source: entities.GoogleSearch, KeywordsCount: 2, FoundCount: 1, Frequency: 1000, Visibility: 40, CalculatedAt: time.Now()},
+		{Date: noon, Source: entities.GoogleSearch, GroupID: &groupID, KeywordsCount: 1, FoundCount: 1, Frequency: 1000, Visibility: 40, CalculatedAt: time.Now()},
+	}
+	if err := repo.ReplaceDay(siteID, day, rows); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	// Повторный расчет заменяет день, а не добавляет строки
+	rows[0].Visibility = 50
+	if err := repo.ReplaceDay(siteID, day, rows); err != nil {
+		t.Fatalf("replace again: %v", err)
+	}
+	if missing, err := repo.GetUncalculatedDays(siteID, from, to); err != nil || len(missing) != 0 {
+		t.Fatalf("expected no uncalculated days, got %v, %v", missing, err)
+	}
+
+	series, err := repo.GetSeries(siteID, nil, nil, nil, from, to)
+	if err != nil || len(series) != 1 || series[0].Visibility != 50 || series[0].GroupID != nil {
+		t.Fatalf("expected one site-wide row, got %+v, %v", series, err)
+	}
+	series, err = repo.GetSeries(siteID, nil, &groupID, nil, from, to)
+	if err != nil || len(series) != 1 || series[0].KeywordsCount != 1 {
+		t.Fatalf("expected one group row, got %+v, %v", series, err)
+	}
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+	"go-seo/internal/infrastructure/database/postgres/models"
+
+	"gorm.io/gorm"
+)
+
+type workspaceRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkspaceRepository(db *gorm.DB) repositories.WorkspaceRepository {
+	return &workspaceRepository{db: db}
+}
+
+func (r *workspaceRepository) Create(workspace *entities.Workspace) error {
+	model := &models.Workspace{
+		Name:          workspace.Name,
+		MonthlyBudget: workspace.MonthlyBudget,
+	}
+
+	if err := r.db.Create(model).Error; err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	workspace.ID = model.ID
+	workspace.CreatedAt = model.CreatedAt
+	workspace.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+func (r *workspaceRepository) GetByID(id int) (*entities.Workspace, error) {
+	var model models.Workspace
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+func (r *workspaceRepository) GetAll() ([]*entities.Workspace, error) {
+	var models []models.Workspace
+	if err := r.db.Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	workspaces := make([]*entities.Workspace, len(models))
+	for i, model := range models {
+		workspaces[i] = r.toDomain(&model)
+	}
+
+	return workspaces, nil
+}
+
+func (r *workspaceRepository) Update(workspace *entities.Workspace) error {
+	if err := r.db.Model(&models.Workspace{}).
+		Where("id = ?", workspace.ID).
+		Updates(map[string]interface{}{
+			"name":           workspace.Name,
+			"monthly_budget": workspace.MonthlyBudget,
+		}).Error; err != nil {
+		return database.WrapDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *workspaceRepository) Delete(id int) error {
+	return r.db.Delete(&models.Workspace{}, id).Error
+}
+
+func (r *workspaceRepository) toDomain(model *models.Workspace) *entities.Workspace {
+	return &entities.Workspace{
+		ID:            model.ID,
+		Name:          model.Name,
+		MonthlyBudget: model.MonthlyBudget,
+		CreatedAt:     model.CreatedAt,
+		UpdatedAt:     model.UpdatedAt,
+	}
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+// Поиск по ID в пространстве не находит сайт, слово, джоб и снимок выдачи сайта другого пространства,
+// а без пространства находит все
+func TestGetByIDInWorkspace(t *testing.T) {
+	tx := openTestDB(t)
+	siteRepo := &siteRepository{db: tx}
+	keywordRepo := &keywordRepository{db: tx}
+	jobRepo := &TrackingJobRepository{db: tx}
+	snapshotRepo := &serpSnapshotRepository{db: tx}
+
+	siteID, _, keywordID, _ := seedVisibilitySite(t, tx)
+	positionID := insertMergePosition(t, tx, keywordID, siteID, "desktop", 3, time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	job := &entities.TrackingJob{ID: "workspace-scope-job", SiteID: siteID, Source: entities.GoogleSearch, Status: entities.TaskStatusCompleted}
+	if err := jobRepo.Create(job); err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	own, other := 1, 900002
+	tests := []struct {
+		name        string
+		workspaceID *int
+		found       bool
+	}{
+		{name: "own workspace", workspaceID: &own, found: true},
+		{name: "other workspace", workspaceID: &other},
+		{name: "unscoped", found: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site, err := siteRepo.GetByIDInWorkspace(siteID, tt.workspaceID)
+			if (err == nil) != tt.found || (tt.found && site.ID != siteID) {
+				t.Fatalf("site: expected found=%v, got %+v, %v", tt.found, site, err)
+			}
+			sites, err := siteRepo.GetByIDs([]int{siteID}, tt.workspaceID)
+			if err != nil || (len(sites) == 1) != tt.found {
+				t.Fatalf("sites: expected found=%v, got %+v, %v", tt.found, sites, err)
+			}
+			keyword, err := keywordRepo.GetByIDInWorkspace(keywordID, tt.workspaceID)
+			if (err == nil) != tt.found || (tt.found && keyword.ID != keywordID) {
+				t.Fatalf("keyword: expected found=%v, got %+v, %v", tt.found, keyword, err)
+			}
+			scoped, err := jobRepo.GetByIDInWorkspace(job.ID, tt.workspaceID)
+			if (err == nil) != tt.found || (tt.found && scoped.ID != job.ID) {
+				t.Fatalf("job: expected found=%v, got %+v, %v", tt.found, scoped, err)
+			}
+			// Снимок чужой позиции неотличим от несохраненного
+			snapshot, err := snapshotRepo.GetByPositionID(positionID, tt.workspaceID)
+			if err != nil || (snapshot != nil) != tt.found {
+				t.Fatalf("snapshot: expected found=%v, got %+v, %v", tt.found, snapshot, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testCipherKey(fill byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(fill), 32)))
+}
+
+func TestAESCipherRoundTrip(t *testing.T) {
+	c, err := NewAESCipher(testCipherKey('k'))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	encrypted, err := c.Encrypt("secret-api-key")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if strings.Contains(encrypted, "secret-api-key") {
+		t.Fatalf("ciphertext contains plaintext: %s", encrypted)
+	}
+
+	again, err := c.Encrypt("secret-api-key")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if again == encrypted {
+		t.Fatal("expected a fresh nonce for every encryption")
+	}
+
+	decrypted, err := c.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if decrypted != "secret-api-key" {
+		t.Fatalf("expected secret-api-key, got %q", decrypted)
+	}
+}
+
+func TestAESCipherWrongKey(t *testing.T) {
+	c, _ := NewAESCipher(testCipherKey('a'))
+	other, _ := NewAESCipher(testCipherKey('b'))
+
+	encrypted, err := c.Encrypt("secret")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if _, err := other.Decrypt(encrypted); err == nil {
+		t.Fatal("expected error when decrypting with another key")
+	}
+}
+
+func TestNewAESCipherRejectsInvalidKey(t *testing.T) {
+	for _, key := range []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := NewAESCipher(key); err == nil {
+			t.Errorf("expected error for key %q", key)
+		}
+	}
+}
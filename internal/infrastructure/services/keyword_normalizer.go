@@ -0,0 +1,58 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	domainservices "go-seo/internal/domain/services"
+)
+
+// keywordStopWords предлоги, союзы и частицы, которые поисковики не учитывают без оператора "+"
+var keywordStopWords = map[string]bool{
+	"а": true, "без": true, "в": true, "во": true, "да": true, "для": true, "до": true, "же": true,
+	"за": true, "и": true, "из": true, "или": true, "к": true, "ко": true, "ли": true, "на": true,
+	"над": true, "не": true, "но": true, "о": true, "об": true, "от": true, "по": true, "под": true,
+	"при": true, "про": true, "с": true, "со": true, "у": true,
+	"a": true, "an": true, "and": true, "for": true, "in": true, "of": true, "on": true, "or": true,
+	"the": true, "to": true, "with": true,
+}
+
+// KeywordNormalizer нормализация ключевых слов; правила Normalize повторяет заполнение
+// keywords.normalized_value в миграции 0006
+type KeywordNormalizer struct{}
+
+var _ domainservices.KeywordNormalizer = (*KeywordNormalizer)(nil)
+
+func NewKeywordNormalizer() *KeywordNormalizer {
+	return &KeywordNormalizer{}
+}
+
+func (n *KeywordNormalizer) Clean(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func (n *KeywordNormalizer) Normalize(value string) string {
+	return strings.ReplaceAll(strings.ToLower(n.Clean(value)), "ё", "е")
+}
+
+func (n *KeywordNormalizer) MatchKey(value string, ignoreStopWords, ignoreWordOrder bool) string {
+	words := strings.Fields(n.Normalize(value))
+
+	if ignoreStopWords {
+		filtered := make([]string, 0, len(words))
+		for _, word := range words {
+			if !keywordStopWords[word] {
+				filtered = append(filtered, word)
+			}
+		}
+		// Запрос только из стоп-слов сравнивается целиком
+		if len(filtered) > 0 {
+			words = filtered
+		}
+	}
+	if ignoreWordOrder {
+		sort.Strings(words)
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,48 @@
+package services
+
+import "testing"
+
+func TestKeywordNormalizerNormalize(t *testing.T) {
+	normalizer := NewKeywordNormalizer()
+
+	values := []string{"купить диван", "Купить  диван", " купить диван ", "КУПИТЬ\tдиван\n"}
+	for _, value := range values {
+		if key := normalizer.Normalize(value); key != "купить диван" {
+			t.Errorf("Normalize(%q) = %q, expected %q", value, key, "купить диван")
+		}
+	}
+
+	if key := normalizer.Normalize("Ёлка зелёная"); key != "елка зеленая" {
+		t.Errorf("expected ё to be replaced, got %q", key)
+	}
+	if cleaned := normalizer.Clean("  Купить   диван "); cleaned != "Купить диван" {
+		t.Errorf("expected case to be kept by Clean, got %q", cleaned)
+	}
+}
+
+func TestKeywordNormalizerMatchKey(t *testing.T) {
+	normalizer := NewKeywordNormalizer()
+
+	tests := []struct {
+		value           string
+		ignoreStopWords bool
+		ignoreWordOrder bool
+		expected        string
+	}{
+		{"Купить диван в Москве", false, false, "купить диван в москве"},
+		{"Купить диван в Москве", true, false, "купить диван москве"},
+		{"диван купить в Москве", true, true, "диван купить москве"},
+		{"купить в Москве диван", false, true, "в диван купить москве"},
+		{"в на", true, false, "в на"},
+	}
+	for _, test := range tests {
+		key := normalizer.MatchKey(test.value, test.ignoreStopWords, test.ignoreWordOrder)
+		if key != test.expected {
+			t.Errorf("MatchKey(%q, %v, %v) = %q, expected %q", test.value, test.ignoreStopWords, test.ignoreWordOrder, key, test.expected)
+		}
+	}
+
+	if normalizer.MatchKey("купить диван в москве", true, true) != normalizer.MatchKey("москве диван купить", true, true) {
+		t.Error("expected word order and stop words to be ignored")
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	domainservices "go-seo/internal/domain/services"
+)
+
+// xmlRiverErrorKinds коды <error code="..."> XMLRiver-совместимого API
+var xmlRiverErrorKinds = map[string]domainservices.ProviderErrorKind{
+	"2":   domainservices.ProviderErrorInvalidQuery, // Пустой поисковый запрос
+	"15":  domainservices.ProviderErrorNoResults,
+	"18":  domainservices.ProviderErrorNoResults,
+	"31":  domainservices.ProviderErrorAuth, // Пользователь не зарегистрирован
+	"42":  domainservices.ProviderErrorAuth, // Неверный ключ
+	"45":  domainservices.ProviderErrorAuth, // IP не разрешен
+	"110": domainservices.ProviderErrorOverloaded,
+}
+
+// xmlRiverOverloadedRetryAfter пауза перед повтором при коде 110, сам провайдер ее не сообщает
+const xmlRiverOverloadedRetryAfter = 5 * time.Second
+
+// newXMLRiverError классифицирует ошибку из тела ответа; неизвестные коды распознаются по тексту
+func newXMLRiverError(provider, code, message string) *domainservices.ProviderError {
+	message = strings.TrimSpace(message)
+	kind, ok := xmlRiverErrorKinds[code]
+	if !ok {
+		kind = kindFromMessage(message)
+	}
+
+	providerErr := &domainservices.ProviderError{
+		Provider: provider,
+		Kind:     kind,
+		Code:     code,
+		Message:  message,
+	}
+	if kind == domainservices.ProviderErrorOverloaded {
+		providerErr.RetryAfter = xmlRiverOverloadedRetryAfter
+	}
+	return providerErr
+}
+
+func kindFromMessage(message string) domainservices.ProviderErrorKind {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "баланс"), strings.Contains(lower, "недостаточно средств"), strings.Contains(lower, "balance"):
+		return domainservices.ProviderErrorBalance
+	case strings.Contains(lower, "неверный ключ"), strings.Contains(lower, "не зарегистрирован"), strings.Contains(lower, "invalid key"):
+		return domainservices.ProviderErrorAuth
+	case strings.Contains(lower, "перегружен"), strings.Contains(lower, "overload"):
+		return domainservices.ProviderErrorOverloaded
+	default:
+		return domainservices.ProviderErrorUnknown
+	}
+}
+
+// newHTTPStatusError классифицирует ответ с HTTP статусом отличным от 200
+func newHTTPStatusError(provider string, resp *http.Response) *domainservices.ProviderError {
+	var kind domainservices.ProviderErrorKind
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		kind = domainservices.ProviderErrorAuth
+	case resp.StatusCode == http.StatusPaymentRequired:
+		kind = domainservices.ProviderErrorBalance
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		kind = domainservices.ProviderErrorOverloaded
+	case resp.StatusCode == http.StatusBadRequest, resp.StatusCode == http.StatusRequestURITooLong:
+		kind = domainservices.ProviderErrorInvalidQuery
+	default:
+		kind = domainservices.ProviderErrorUnknown
+	}
+
+	return &domainservices.ProviderError{
+		Provider:   provider,
+		Kind:       kind,
+		Code:       strconv.Itoa(resp.StatusCode),
+		Message:    http.StatusText(resp.StatusCode),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+func newNetworkError(provider string, err error) *domainservices.ProviderError {
+	// Ошибка HTTP клиента содержит URL запроса вместе с ключом аккаунта
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = &url.Error{Op: urlErr.Op, URL: redactURL(urlErr.URL), Err: urlErr.Err}
+	}
+
+	return &domainservices.ProviderError{
+		Provider: provider,
+		Kind:     domainservices.ProviderErrorNetwork,
+		Err:      err,
+	}
+}
+
+// parseRetryAfter разбирает Retry-After в секундах или в виде HTTP даты
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	domainservices "go-seo/internal/domain/services"
+)
+
+// ProviderRegistry хранит SERP провайдеров по имени
+type ProviderRegistry struct {
+	mu          sync.RWMutex
+	providers   map[string]domainservices.SearchService
+	order       []string
+	defaultName string
+}
+
+var _ domainservices.SearchProviderRegistry = (*ProviderRegistry)(nil)
+
+func NewProviderRegistry(defaultName string) *ProviderRegistry {
+	return &ProviderRegistry{
+		providers:   make(map[string]domainservices.SearchService),
+		defaultName: defaultName,
+	}
+}
+
+func (r *ProviderRegistry) Register(provider domainservices.SearchService) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := provider.Name()
+	if name == "" {
+		return fmt.Errorf("provider name is required")
+	}
+	if _, exists := r.providers[name]; exists {
+		return fmt.Errorf("provider %s is already registered", name)
+	}
+
+	r.providers[name] = provider
+	r.order = append(r.order, name)
+	return nil
+}
+
+func (r *ProviderRegistry) Get(name string) (domainservices.SearchService, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if name == "" {
+		name = r.defaultName
+	}
+
+	provider, exists := r.providers[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown search provider: %s", name)
+	}
+	return provider, nil
+}
+
+func (r *ProviderRegistry) List() []domainservices.SearchService {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	providers := make([]domainservices.SearchService, 0, len(r.order))
+	for _, name := range r.order {
+		providers = append(providers, r.providers[name])
+	}
+	return providers
+}
+
+func (r *ProviderRegistry) Close() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var firstErr error
+	for _, name := range r.order {
+		if err := r.providers[name].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+
+	"go-seo/internal/domain/entities"
+	domainservices "go-seo/internal/domain/services"
+)
+
+func newTestProvider(t *testing.T, name string, endpoints SearchEndpoints) *XMLRiverService {
+	t.Helper()
+
+	provider, err := NewXMLRiverService(ProviderConfig{
+		Name:      name,
+		BaseURL:   "https://" + name + ".com",
+		UserID:    "1",
+		APIKey:    "key",
+		Endpoints: endpoints,
+		Capabilities: domainservices.ProviderCapabilities{
+			Sources:  []string{entities.GoogleSearch, entities.YandexSearch},
+			MaxPages: 10,
+			GroupBy:  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	return provider
+}
+
+func TestProviderRegistry(t *testing.T) {
+	registry := NewProviderRegistry(ProviderXMLStock)
+	if err := registry.Register(newTestProvider(t, ProviderXMLRiver, XMLRiverEndpoints)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Register(newTestProvider(t, ProviderXMLStock, XMLStockEndpoints)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := registry.Register(newTestProvider(t, ProviderXMLRiver, XMLRiverEndpoints)); err == nil {
+		t.Fatal("expected error on duplicate registration")
+	}
+
+	provider, err := registry.Get("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.Name() != ProviderXMLStock {
+		t.Errorf("expected default provider %s, got %s", ProviderXMLStock, provider.Name())
+	}
+
+	provider, err = registry.Get(ProviderXMLRiver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !provider.Capabilities().SupportsSource(entities.YandexSearch) {
+		t.Error("expected provider to support yandex")
+	}
+	if provider.Capabilities().SupportsSource(entities.Wordstat) {
+		t.Error("expected provider not to support wordstat")
+	}
+
+	if _, err := registry.Get("unknown"); err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+
+	providers := registry.List()
+	if len(providers) != 2 || providers[0].Name() != ProviderXMLRiver || providers[1].Name() != ProviderXMLStock {
+		t.Errorf("expected providers in registration order, got %d", len(providers))
+	}
+}
+
+func TestXMLRiverServiceWithCredentials(t *testing.T) {
+	provider := newTestProvider(t, ProviderXMLRiver, XMLRiverEndpoints)
+
+	custom, err := provider.WithCredentials("", "777", "custom")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clone := custom.(*XMLRiverService)
+	if clone.userID != "777" || clone.apiKey != "custom" {
+		t.Errorf("expected custom credentials, got %s/%s", clone.userID, clone.apiKey)
+	}
+	if provider.userID != "1" || provider.apiKey != "key" {
+		t.Error("original provider credentials must not change")
+	}
+	if clone.Name() != provider.Name() || clone.endpoints != provider.endpoints || clone.baseURL != provider.baseURL {
+		t.Error("clone must keep provider name, endpoints and base URL")
+	}
+
+	// Адрес API из аккаунта заменяет адрес провайдера
+	custom, err = provider.WithCredentials("https://proxy.example.com", "777", "custom")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := custom.(*XMLRiverService).baseURL; got != "https://proxy.example.com" {
+		t.Errorf("expected account base URL, got %s", got)
+	}
+	if provider.baseURL == "https://proxy.example.com" {
+		t.Error("original provider base URL must not change")
+	}
+
+	if _, err := provider.WithCredentials("", "", "custom"); err == nil {
+		t.Fatal("expected error for empty user id")
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	domainservices "go-seo/internal/domain/services"
+)
+
+// ProviderRateLimiter ограничивает частоту, параллелизм и дневное число запросов каждого аккаунта провайдера.
+// Состояние хранится в памяти инстанса: при нескольких репликах лимиты делятся между ними вручную.
+type ProviderRateLimiter struct {
+	mu       sync.Mutex
+	limits   map[string]domainservices.ProviderLimits // По имени провайдера
+	accounts map[accountKey]*accountLimiter
+	now      func() time.Time
+}
+
+type accountKey struct {
+	provider string
+	account  string
+}
+
+type accountLimiter struct {
+	limits   domainservices.ProviderLimits
+	slots    chan struct{} // nil, если MaxInFlight не ограничен
+	next     time.Time     // Не раньше этого момента можно начать следующий запрос
+	day      time.Time     // Начало суток, к которым относится used
+	used     int
+	inFlight int
+	waiting  int
+}
+
+var _ domainservices.RateLimiter = (*ProviderRateLimiter)(nil)
+
+func NewProviderRateLimiter(limits map[string]domainservices.ProviderLimits) *ProviderRateLimiter {
+	return &ProviderRateLimiter{
+		limits:   limits,
+		accounts: make(map[accountKey]*accountLimiter),
+		now:      time.Now,
+	}
+}
+
+// Acquire ждет свободный слот и очередь по частоте; при исчерпанной дневной квоте сразу возвращает ошибку провайдера
+func (l *ProviderRateLimiter) Acquire(ctx context.Context, provider, account string) (func(), error) {
+	l.mu.Lock()
+	limiter := l.account(provider, account)
+	now := l.now()
+	limiter.rollDay(now)
+	if limiter.limits.DailyQuota > 0 && limiter.used >= limiter.limits.DailyQuota {
+		resetAt := limiter.day.AddDate(0, 0, 1)
+		l.mu.Unlock()
+		return nil, &domainservices.ProviderError{
+			Provider:   provider,
+			Kind:       domainservices.ProviderErrorQuota,
+			Message:    "daily request quota exhausted",
+			RetryAfter: resetAt.Sub(now),
+		}
+	}
+	// Квота резервируется сразу, чтобы ожидающие запросы не превысили ее вместе
+	limiter.used++
+	limiter.waiting++
+	reservedDay := limiter.day
+	l.mu.Unlock()
+
+	// Отмененный запрос возвращает квоту только в те сутки, из которых она взята: после сброса
+	// счетчик новых суток этот запрос не учитывал
+	giveBack := func() {
+		l.mu.Lock()
+		limiter.rollDay(l.now())
+		if limiter.day.Equal(reservedDay) {
+			limiter.used--
+		}
+		limiter.waiting--
+		l.mu.Unlock()
+	}
+
+	if limiter.slots != nil {
+		select {
+		case limiter.slots <- struct{}{}:
+		case <-ctx.Done():
+			giveBack()
+			return nil, ctx.Err()
+		}
+	}
+	releaseSlot := func() {
+		if limiter.slots != nil {
+			<-limiter.slots
+		}
+	}
+
+	if limiter.limits.RequestsPerSecond > 0 {
+		l.mu.Lock()
+		now := l.now()
+		start := limiter.next
+		if start.Before(now) {
+			start = now
+		}
+		limiter.next = start.Add(time.Duration(float64(time.Second) / limiter.limits.RequestsPerSecond))
+		l.mu.Unlock()
+
+		if wait := start.Sub(now); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				releaseSlot()
+				giveBack()
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	l.mu.Lock()
+	limiter.waiting--
+	limiter.inFlight++
+	l.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			limiter.inFlight--
+			l.mu.Unlock()
+			releaseSlot()
+		})
+	}, nil
+}
+
+// States возвращает состояние всех аккаунтов, к которым уже были запросы
+func (l *ProviderRateLimiter) States() []domainservices.ProviderLimiterState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	states := make([]domainservices.ProviderLimiterState, 0, len(l.accounts))
+	for key, limiter := range l.accounts {
+		limiter.rollDay(now)
+		states = append(states, domainservices.ProviderLimiterState{
+			Provider:     key.provider,
+			Account:      key.account,
+			Limits:       limiter.limits,
+			InFlight:     limiter.inFlight,
+			Waiting:      limiter.waiting,
+			UsedToday:    limiter.used,
+			QuotaResetAt: limiter.day.AddDate(0, 0, 1),
+		})
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Provider != states[j].Provider {
+			return states[i].Provider < states[j].Provider
+		}
+		return states[i].Account < states[j].Account
+	})
+	return states
+}
+
+// account возвращает лимитер аккаунта, создавая его по лимитам провайдера; вызывается под l.mu
+func (l *ProviderRateLimiter) account(provider, account string) *accountLimiter {
+	key := accountKey{provider: provider, account: account}
+	limiter, ok := l.accounts[key]
+	if !ok {
+		limits := l.limits[provider]
+		limiter = &accountLimiter{limits: limits}
+		if limits.MaxInFlight > 0 {
+			limiter.slots = make(chan struct{}, limits.MaxInFlight)
+		}
+		l.accounts[key] = limiter
+	}
+	return limiter
+}
+
+// rollDay обнуляет счетчик квоты в начале новых суток (по времени сервера)
+func (a *accountLimiter) rollDay(now time.Time) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if !day.Equal(a.day) {
+		a.day = day
+		a.used = 0
+	}
+}
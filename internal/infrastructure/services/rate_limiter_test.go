@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domainservices "go-seo/internal/domain/services"
+)
+
+func TestProviderRateLimiterDailyQuota(t *testing.T) {
+	limiter := NewProviderRateLimiter(map[string]domainservices.ProviderLimits{
+		ProviderXMLRiver: {DailyQuota: 2},
+	})
+	now := time.Date(2025, 10, 20, 23, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		release, err := limiter.Acquire(context.Background(), ProviderXMLRiver, "42")
+		if err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i+1, err)
+		}
+		release()
+	}
+
+	_, err := limiter.Acquire(context.Background(), ProviderXMLRiver, "42")
+	if !domainservices.IsProviderErrorKind(err, domainservices.ProviderErrorQuota) {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+	if providerErr, _ := domainservices.AsProviderError(err); providerErr.RetryAfter != time.Hour {
+		t.Errorf("expected retry after 1h, got %s", providerErr.RetryAfter)
+	}
+
+	// Квота считается по аккаунтам отдельно
+	if _, err := limiter.Acquire(context.Background(), ProviderXMLRiver, "43"); err != nil {
+		t.Fatalf("other account: unexpected error: %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := limiter.Acquire(context.Background(), ProviderXMLRiver, "42"); err != nil {
+		t.Fatalf("next day: unexpected error: %v", err)
+	}
+}
+
+func TestProviderRateLimiterMaxInFlight(t *testing.T) {
+	limiter := NewProviderRateLimiter(map[string]domainservices.ProviderLimits{
+		ProviderXMLStock: {MaxInFlight: 1, DailyQuota: 10},
+	})
+
+	release, err := limiter.Acquire(context.Background(), ProviderXMLStock, "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Acquire(ctx, ProviderXMLStock, "1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected to wait for a free slot, got %v", err)
+	}
+
+	states := limiter.States()
+	if len(states) != 1 || states[0].InFlight != 1 || states[0].Waiting != 0 || states[0].UsedToday != 1 {
+		t.Fatalf("unexpected state: %+v", states)
+	}
+
+	release()
+	release()
+	if _, err := limiter.Acquire(context.Background(), ProviderXMLStock, "1"); err != nil {
+		t.Fatalf("unexpected error after release: %v", err)
+	}
+}
+
+func TestProviderRateLimiterRequestsPerSecond(t *testing.T) {
+	limiter := NewProviderRateLimiter(map[string]domainservices.ProviderLimits{
+		ProviderXMLRiver: {RequestsPerSecond: 20},
+	})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := limiter.Acquire(context.Background(), ProviderXMLRiver, "42")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		release()
+	}
+
+	// Первый запрос сразу, следующие два с интервалом 50ms
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected requests to be paced, took %s", elapsed)
+	}
+}
+
+// Запрос, зарезервировавший квоту вчера и отмененный после полуночи, не уменьшает счетчик новых суток
+func TestProviderRateLimiterGiveBackAfterDayReset(t *testing.T) {
+	limiter := NewProviderRateLimiter(map[string]domainservices.ProviderLimits{
+		ProviderXMLStock: {MaxInFlight: 1, DailyQuota: 2},
+	})
+	var now atomic.Int64
+	now.Store(time.Date(2025, 10, 20, 23, 59, 0, 0, time.UTC).UnixNano())
+	limiter.now = func() time.Time { return time.Unix(0, now.Load()).UTC() }
+
+	release, err := limiter.Acquire(context.Background(), ProviderXMLStock, "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := limiter.Acquire(ctx, ProviderXMLStock, "1")
+		done <- err
+	}()
+	for limiter.States()[0].Waiting != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// После полуночи счетчик уже сброшен (здесь - запросом состояния лимитов), и только затем запрос отменяется
+	now.Add(int64(2 * time.Minute))
+	if used := limiter.States()[0].UsedToday; used != 0 {
+		t.Fatalf("expected the quota to reset at midnight, got %d", used)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the waiting request to be cancelled, got %v", err)
+	}
+	if states := limiter.States(); states[0].UsedToday != 0 || states[0].Waiting != 0 {
+		t.Fatalf("expected a clean counter for the new day, got %+v", states[0])
+	}
+
+	// Новые сутки дают ровно дневную квоту
+	release()
+	for i := 0; i < 2; i++ {
+		release, err := limiter.Acquire(context.Background(), ProviderXMLStock, "1")
+		if err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i+1, err)
+		}
+		release()
+	}
+	if _, err := limiter.Acquire(context.Background(), ProviderXMLStock, "1"); !domainservices.IsProviderErrorKind(err, domainservices.ProviderErrorQuota) {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+}
@@ -1,8 +1,11 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"time"
+
+	domainservices "go-seo/internal/domain/services"
 )
 
 // RetryService handles retry logic with exponential backoff
@@ -21,18 +24,38 @@ func NewRetryService(maxRetries int, baseDelay time.Duration) *RetryService {
 
 // ExecuteWithRetry executes a function with retry logic
 func (r *RetryService) ExecuteWithRetry(fn func() error) error {
+	return r.ExecuteWithRetryContext(context.Background(), fn)
+}
+
+// ExecuteWithRetryContext executes a function with retry logic and stops retrying once ctx is done.
+// Only transient provider errors are retried; the delay is extended to the provider's back-off hint.
+func (r *RetryService) ExecuteWithRetryContext(ctx context.Context, fn func() error) error {
 	var lastErr error
 
 	for attempt := 0; attempt <= r.maxRetries; attempt++ {
 		if attempt > 0 {
 			delay := r.calculateDelay(attempt)
-			time.Sleep(delay)
+			if hint := retryAfter(lastErr); hint > delay {
+				delay = hint
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
 		}
 
 		err := fn()
 		if err == nil {
 			return nil
 		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !isRetryable(err) {
+			return err
+		}
 
 		lastErr = err
 	}
@@ -40,6 +63,19 @@ func (r *RetryService) ExecuteWithRetry(fn func() error) error {
 	return fmt.Errorf("operation failed after %d attempts, last error: %w", r.maxRetries+1, lastErr)
 }
 
+// isRetryable повторяем только временные ошибки провайдера: перегрузку и сбои сети
+func isRetryable(err error) bool {
+	providerErr, ok := domainservices.AsProviderError(err)
+	return ok && providerErr.Retryable()
+}
+
+func retryAfter(err error) time.Duration {
+	if providerErr, ok := domainservices.AsProviderError(err); ok {
+		return providerErr.RetryAfter
+	}
+	return 0
+}
+
 // calculateDelay calculates the delay for the given attempt
 func (r *RetryService) calculateDelay(attempt int) time.Duration {
 	// Exponential backoff: baseDelay * 2^(attempt-1)
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domainservices "go-seo/internal/domain/services"
+)
+
+func TestExecuteWithRetryContextStopsOnCancel(t *testing.T) {
+	retry := NewRetryService(5, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- retry.ExecuteWithRetryContext(ctx, func() error {
+			attempts++
+			return &domainservices.ProviderError{Provider: "xmlriver", Kind: domainservices.ProviderErrorOverloaded}
+		})
+	}()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("retry did not stop after cancel")
+	}
+
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestExecuteWithRetryContextSucceeds(t *testing.T) {
+	retry := NewRetryService(3, time.Millisecond)
+
+	attempts := 0
+	err := retry.ExecuteWithRetryContext(context.Background(), func() error {
+		attempts++
+		if attempts < 3 {
+			return &domainservices.ProviderError{Provider: "xmlriver", Kind: domainservices.ProviderErrorNetwork, Err: errors.New("timeout")}
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestExecuteWithRetryContextStopsOnPermanentError(t *testing.T) {
+	retry := NewRetryService(5, time.Millisecond)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "auth", err: &domainservices.ProviderError{Provider: "xmlriver", Kind: domainservices.ProviderErrorAuth, Code: "42"}},
+		{name: "balance", err: &domainservices.ProviderError{Provider: "xmlriver", Kind: domainservices.ProviderErrorBalance}},
+		{name: "invalid query", err: &domainservices.ProviderError{Provider: "xmlriver", Kind: domainservices.ProviderErrorInvalidQuery, Code: "2"}},
+		{name: "not a provider error", err: errors.New("database error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := retry.ExecuteWithRetryContext(context.Background(), func() error {
+				attempts++
+				return fmt.Errorf("failed to search: %w", tt.err)
+			})
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if attempts != 1 {
+				t.Errorf("expected 1 attempt, got %d", attempts)
+			}
+		})
+	}
+}
+
+func TestExecuteWithRetryContextHonoursRetryAfter(t *testing.T) {
+	retry := NewRetryService(1, time.Millisecond)
+
+	attempts := 0
+	start := time.Now()
+	err := retry.ExecuteWithRetryContext(context.Background(), func() error {
+		attempts++
+		if attempts == 1 {
+			return &domainservices.ProviderError{Provider: "xmlriver", Kind: domainservices.ProviderErrorOverloaded, RetryAfter: 50 * time.Millisecond}
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected to wait for the provider hint, waited %s", elapsed)
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestSpreadsheetReaderCSV(t *testing.T) {
+	reader := NewSpreadsheetReader()
+
+	rows, err := reader.Read("keywords.csv", []byte("\xef\xbb\xbfkeyword;group;tags\nкупить диван;Диваны;\"коммерция, мебель\"\n\nдиван угловой;;\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := [][]string{
+		{"keyword", "group", "tags"},
+		{"купить диван", "Диваны", "коммерция, мебель"},
+		nil,
+		{"диван угловой", "", ""},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected %q, got %q", expected, rows)
+	}
+}
+
+func TestSpreadsheetReaderCSVWindows1251(t *testing.T) {
+	encoded, err := charmap.Windows1251.NewEncoder().Bytes([]byte("купить диван,Диваны\n"))
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	rows, err := NewSpreadsheetReader().Read("keywords.CSV", encoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0][0] != "купить диван" || rows[0][1] != "Диваны" {
+		t.Fatalf("expected decoded Windows-1251 row, got %q", rows)
+	}
+}
+
+func TestSpreadsheetReaderXLSX(t *testing.T) {
+	data := buildTestXLSX(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Keywords" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId3" Type="worksheet" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+			<si><t>keyword</t></si><si><r><t>купить </t></r><r><t>диван</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c></row>
+			<row r="3"><c r="A3" t="s"><v>1</v></c><c r="C3" t="inlineStr"><is><t>Диваны</t></is></c><c r="D3"><v>1500</v></c></row>
+			</sheetData></worksheet>`,
+	})
+
+	rows, err := NewSpreadsheetReader().Read("keywords.xlsx", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := [][]string{
+		{"keyword"},
+		nil,
+		{"купить диван", "", "Диваны", "1500"},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected %q, got %q", expected, rows)
+	}
+}
+
+func TestSpreadsheetReaderRejectsUnknownType(t *testing.T) {
+	if _, err := NewSpreadsheetReader().Read("keywords.xls", []byte("data")); err == nil {
+		t.Fatal("expected an error for .xls")
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	cases := map[string]int{"A1": 0, "C3": 2, "Z10": 25, "AA1": 26, "AB12": 27}
+	for ref, expected := range cases {
+		got, err := columnIndex(ref)
+		if err != nil || got != expected {
+			t.Fatalf("columnIndex(%s): expected %d, got %d (%v)", ref, expected, got, err)
+		}
+	}
+}
+
+func buildTestXLSX(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range parts {
+		part, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("zip: %v", err)
+		}
+		if _, err := part.Write([]byte(content)); err != nil {
+			t.Fatalf("zip: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	return buf.Bytes()
+}
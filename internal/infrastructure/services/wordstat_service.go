@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,13 +9,19 @@ import (
 	"net/url"
 	"strconv"
 	"time"
+
+	domainservices "go-seo/internal/domain/services"
 )
 
+const wordstatProviderName = "wordstat"
+
 type WordstatService struct {
-	baseURL string
-	userID  string
-	apiKey  string
-	client  *http.Client
+	baseURL        string
+	userID         string
+	apiKey         string
+	costPerRequest float64
+	limiter        domainservices.RateLimiter
+	client         *http.Client
 }
 
 type WordstatRequest struct {
@@ -38,7 +45,8 @@ type WordstatPosition struct {
 	Position  int
 }
 
-func NewWordstatService(baseURL, userID, apiKey string) (*WordstatService, error) {
+// NewWordstatService создает клиент Wordstat. Wordstat работает через аккаунт XMLRiver и делит с ним лимиты
+func NewWordstatService(baseURL, userID, apiKey string, costPerRequest float64, limiter domainservices.RateLimiter) (*WordstatService, error) {
 	transport := &http.Transport{
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 50,
@@ -47,9 +55,11 @@ func NewWordstatService(baseURL, userID, apiKey string) (*WordstatService, error
 	}
 
 	return &WordstatService{
-		baseURL: baseURL,
-		userID:  userID,
-		apiKey:  apiKey,
+		baseURL:        baseURL,
+		userID:         userID,
+		apiKey:         apiKey,
+		costPerRequest: costPerRequest,
+		limiter:        limiter,
 		client: &http.Client{
 			Timeout:   30 * time.Second,
 			Transport: transport,
@@ -57,7 +67,30 @@ func NewWordstatService(baseURL, userID, apiKey string) (*WordstatService, error
 	}, nil
 }
 
-func (s *WordstatService) GetWordstatData(query string, regions *int) (*WordstatResponse, error) {
+// WithCredentials возвращает копию сервиса с другими user и key; пустой baseURL оставляет текущий. HTTP клиент и лимиты общие
+func (s *WordstatService) WithCredentials(baseURL, userID, apiKey string) (*WordstatService, error) {
+	if userID == "" || apiKey == "" {
+		return nil, fmt.Errorf("user id and api key are required")
+	}
+
+	clone := *s
+	if baseURL != "" {
+		clone.baseURL = baseURL
+	}
+	clone.userID = userID
+	clone.apiKey = apiKey
+	return &clone, nil
+}
+
+func (s *WordstatService) GetWordstatData(ctx context.Context, query string, regions *int) (*WordstatResponse, error) {
+	if s.limiter != nil {
+		release, err := s.limiter.Acquire(ctx, ProviderXMLRiver, s.userID)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
 	params := url.Values{}
 	params.Set("user", s.userID)
 	params.Set("key", s.apiKey)
@@ -70,19 +103,27 @@ func (s *WordstatService) GetWordstatData(query string, regions *int) (*Wordstat
 	endpoint := "/wordstat/new/json"
 	requestURL := fmt.Sprintf("%s%s?%s", s.baseURL, endpoint, params.Encode())
 
-	resp, err := s.client.Get(requestURL)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request to Wordstat API: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, newNetworkError(wordstatProviderName, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Wordstat API returned status %d", resp.StatusCode)
+		return nil, newHTTPStatusError(wordstatProviderName, resp)
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, newNetworkError(wordstatProviderName, err)
 	}
 
 	var wordstatResp WordstatResponse
@@ -93,8 +134,8 @@ func (s *WordstatService) GetWordstatData(query string, regions *int) (*Wordstat
 	return &wordstatResp, nil
 }
 
-func (s *WordstatService) GetKeywordFrequency(queryForAPI string, originalQuery string, regions *int) (int, error) {
-	resp, err := s.GetWordstatData(queryForAPI, regions)
+func (s *WordstatService) GetKeywordFrequency(ctx context.Context, queryForAPI string, originalQuery string, regions *int) (int, error) {
+	resp, err := s.GetWordstatData(ctx, queryForAPI, regions)
 	if err != nil {
 		return 0, err
 	}
@@ -112,8 +153,8 @@ func (s *WordstatService) GetKeywordFrequency(queryForAPI string, originalQuery
 	return 0, nil
 }
 
-func (s *WordstatService) GetRelatedKeywords(query string, regions *int) ([]WordstatItem, error) {
-	resp, err := s.GetWordstatData(query, regions)
+func (s *WordstatService) GetRelatedKeywords(ctx context.Context, query string, regions *int) ([]WordstatItem, error) {
+	resp, err := s.GetWordstatData(ctx, query, regions)
 	if err != nil {
 		return nil, err
 	}
@@ -121,6 +162,16 @@ func (s *WordstatService) GetRelatedKeywords(query string, regions *int) ([]Word
 	return resp.Associations, nil
 }
 
+// Account user id аккаунта, от имени которого идут запросы
+func (s *WordstatService) Account() string {
+	return s.userID
+}
+
+// CostPerRequest стоимость одного запроса частоты
+func (s *WordstatService) CostPerRequest() float64 {
+	return s.costPerRequest
+}
+
 func (s *WordstatService) Close() error {
 	return nil
 }
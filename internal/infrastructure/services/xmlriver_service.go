@@ -1,41 +1,66 @@
 package services
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"go-seo/internal/domain/entities"
+	domainservices "go-seo/internal/domain/services"
 	"go-seo/pkg/logger"
 )
 
+const (
+	ProviderXMLRiver = "xmlriver"
+	ProviderXMLStock = "xmlstock"
+)
+
+// SearchEndpoints пути поисковых API провайдера
+type SearchEndpoints struct {
+	Google        string
+	Yandex        string
+	YandexOrganic string
+}
+
+var (
+	XMLRiverEndpoints = SearchEndpoints{Google: "/search/xml", Yandex: "/search_yandex/xml", YandexOrganic: "/yandex/xml"}
+	XMLStockEndpoints = SearchEndpoints{Google: "/google/xml", Yandex: "/yandex/xml", YandexOrganic: "/yandexlive/xml"}
+)
+
+// ProviderConfig настройки SERP провайдера с XMLRiver-совместимым API
+type ProviderConfig struct {
+	Name         string
+	BaseURL      string
+	UserID       string
+	APIKey       string
+	SoftID       string
+	Endpoints    SearchEndpoints
+	Capabilities domainservices.ProviderCapabilities
+	Limiter      domainservices.RateLimiter // Необязательный, общий для всех провайдеров и Wordstat
+}
+
 type XMLRiverService struct {
-	baseURL string
-	userID  string
-	apiKey  string
-	softID  string
-	client  *http.Client
-}
-
-type SearchRequest struct {
-	Query   string
-	Page    int
-	Device  string
-	OS      string
-	Ads     bool
-	Country string
-	Lang    string
-	Domain  int  // ID домена Google
-	LR      int  // ID региона Яндекса
-	GroupBy int  // GroupBy для Yandex (page*10 для получения всех результатов сразу)
-	Organic bool // Если true, используем yandexlive endpoint
+	name         string
+	baseURL      string
+	userID       string
+	apiKey       string
+	softID       string
+	endpoints    SearchEndpoints
+	capabilities domainservices.ProviderCapabilities
+	limiter      domainservices.RateLimiter
+	client       *http.Client
 }
 
+// SearchRequest вынесен в домен, чтобы use cases работали с провайдерами через интерфейс
+type SearchRequest = domainservices.SearchRequest
+
 type SearchResponse struct {
 	XMLName  xml.Name `xml:"yandexsearch"`
 	Response Response `xml:"response"`
@@ -69,9 +94,10 @@ type Group struct {
 }
 
 type Doc struct {
-	URL         string `xml:"url"`
-	Title       string `xml:"title"`
-	ContentType string `xml:"contenttype"`
+	URL         string   `xml:"url"`
+	Title       string   `xml:"title"`
+	ContentType string   `xml:"contenttype"`
+	Passages    []string `xml:"passages>passage"`
 }
 
 type Result struct {
@@ -81,7 +107,13 @@ type Result struct {
 	Type     string `xml:"type"`
 }
 
-func NewXMLRiverService(baseURL, userID, apiKey, softID string) (*XMLRiverService, error) {
+var _ domainservices.SearchService = (*XMLRiverService)(nil)
+
+func NewXMLRiverService(cfg ProviderConfig) (*XMLRiverService, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("provider name is required")
+	}
+
 	transport := &http.Transport{
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 50,
@@ -90,10 +122,14 @@ func NewXMLRiverService(baseURL, userID, apiKey, softID string) (*XMLRiverServic
 	}
 
 	return &XMLRiverService{
-		baseURL: baseURL,
-		userID:  userID,
-		apiKey:  apiKey,
-		softID:  softID,
+		name:         cfg.Name,
+		baseURL:      cfg.BaseURL,
+		userID:       cfg.UserID,
+		apiKey:       cfg.APIKey,
+		softID:       cfg.SoftID,
+		endpoints:    cfg.Endpoints,
+		capabilities: cfg.Capabilities,
+		limiter:      cfg.Limiter,
 		client: &http.Client{
 			Timeout:   120 * time.Second,
 			Transport: transport,
@@ -101,7 +137,68 @@ func NewXMLRiverService(baseURL, userID, apiKey, softID string) (*XMLRiverServic
 	}, nil
 }
 
-func (s *XMLRiverService) Search(req SearchRequest, source string) (*SearchResponse, error) {
+// Search выполняет один запрос к провайдеру. ctx прерывает и ожидание лимита, и отправленный запрос
+func (s *XMLRiverService) Search(ctx context.Context, req SearchRequest, source string) (*SearchResponse, error) {
+	if s.limiter != nil {
+		release, err := s.limiter.Acquire(ctx, s.name, s.userID)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	requestURL, params := s.buildSearchURL(req, source)
+
+	paramsMap := make(map[string]string)
+	for key, values := range params {
+		if len(values) > 0 && !slices.Contains(credentialParams, key) {
+			paramsMap[key] = values[0]
+		}
+	}
+	logger.LogXMLRiverURL(redactURL(requestURL), paramsMap)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		// Остановка джоба - не сбой сети, повторять такой запрос не нужно
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, newNetworkError(s.name, err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, newNetworkError(s.name, err)
+	}
+
+	logger.LogXMLRiverResponse(resp.StatusCode, string(bodyBytes))
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPStatusError(s.name, resp)
+	}
+
+	var searchResp SearchResponse
+	if err := xml.Unmarshal(bodyBytes, &searchResp); err != nil {
+		return nil, fmt.Errorf("failed to parse XML response: %w", err)
+	}
+
+	// Проверяем наличие ошибки в ответе
+	if searchResp.Response.Error != nil {
+		return nil, newXMLRiverError(s.name, searchResp.Response.Error.Code, searchResp.Response.Error.Message)
+	}
+
+	return &searchResp, nil
+}
+
+// buildSearchURL собирает URL запроса к провайдеру со всеми параметрами,
+// поддерживаемыми выбранным источником (см. xml-river-docs/google.txt и yandex.txt)
+func (s *XMLRiverService) buildSearchURL(req SearchRequest, source string) (string, url.Values) {
 	params := url.Values{}
 	params.Set("user", s.userID)
 	params.Set("key", s.apiKey)
@@ -112,7 +209,12 @@ func (s *XMLRiverService) Search(req SearchRequest, source string) (*SearchRespo
 	}
 
 	if req.Page > 0 {
-		params.Set("page", strconv.Itoa(req.Page))
+		page := req.Page
+		// В Google первая страница имеет номер 1, в Яндексе - 0
+		if source == entities.GoogleSearch {
+			page++
+		}
+		params.Set("page", strconv.Itoa(page))
 	}
 	if req.Device != "" {
 		params.Set("device", req.Device)
@@ -132,180 +234,191 @@ func (s *XMLRiverService) Search(req SearchRequest, source string) (*SearchRespo
 	if req.LR > 0 {
 		params.Set("lr", strconv.Itoa(req.LR))
 	}
-	if req.Domain > 0 {
-		params.Set("domain", strconv.Itoa(req.Domain))
-	}
 	if req.GroupBy > 0 {
 		params.Set("groupby", strconv.Itoa(req.GroupBy))
 	}
-
-	var endpoint string
-
-	endpoint = s.getSearchUrl(req, source, endpoint)
-
-	requestURL := fmt.Sprintf("%s%s?%s", s.baseURL, endpoint, params.Encode())
-
-	paramsMap := make(map[string]string)
-	for key, values := range params {
-		if len(values) > 0 {
-			paramsMap[key] = values[0]
-		}
+	if req.Filter != nil {
+		params.Set("filter", strconv.Itoa(*req.Filter))
 	}
-	logger.LogXMLRiverURL(requestURL, paramsMap)
-
-	resp, err := s.client.Get(requestURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request to XMLRiver: %w", err)
+	if req.Highlights > 0 {
+		params.Set("highlights", strconv.Itoa(req.Highlights))
+	}
+	if req.Raw != "" {
+		params.Set("raw", req.Raw)
+	}
+	if req.InIndex > 0 {
+		params.Set("inindex", strconv.Itoa(req.InIndex))
+	}
+	if req.Strict > 0 {
+		params.Set("strict", strconv.Itoa(req.Strict))
 	}
-	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	// domain у поисковиков разный: у Google - ID домена, у Яндекса - сам домен
+	if source == entities.YandexSearch {
+		if req.YandexDomain != "" {
+			params.Set("domain", req.YandexDomain)
+		}
+		if req.Within > 0 {
+			params.Set("within", strconv.Itoa(req.Within))
+		}
+	} else {
+		if req.Domain > 0 {
+			params.Set("domain", strconv.Itoa(req.Domain))
+		}
+		if req.TBS != "" {
+			params.Set("tbs", req.TBS)
+		}
+		if req.NFPR > 0 {
+			params.Set("nfpr", strconv.Itoa(req.NFPR))
+		}
+		if req.Loc > 0 {
+			params.Set("loc", strconv.Itoa(req.Loc))
+		}
+		if req.AI > 0 {
+			params.Set("ai", strconv.Itoa(req.AI))
+		}
 	}
 
-	logger.LogXMLRiverResponse(resp.StatusCode, string(bodyBytes))
+	endpoint := s.getSearchUrl(req, source, "")
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("XMLRiver API returned status %d", resp.StatusCode)
-	}
+	return fmt.Sprintf("%s%s?%s", s.baseURL, endpoint, params.Encode()), params
+}
 
-	var searchResp SearchResponse
-	if err := xml.Unmarshal(bodyBytes, &searchResp); err != nil {
-		return nil, fmt.Errorf("failed to parse XML response: %w", err)
-	}
+// credentialParams параметры запроса с учетными данными аккаунта: в логи и тексты ошибок они не попадают
+var credentialParams = []string{"user", "key"}
 
-	// Проверяем наличие ошибки в ответе
-	if searchResp.Response.Error != nil {
-		return nil, fmt.Errorf("Yandex API error %s: %s",
-			searchResp.Response.Error.Code,
-			searchResp.Response.Error.Message)
+// redactURL убирает учетные данные из URL запроса к провайдеру
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
 	}
-
-	return &searchResp, nil
+	query := parsed.Query()
+	for _, name := range credentialParams {
+		query.Del(name)
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
 }
 
 func (s *XMLRiverService) getSearchUrl(req SearchRequest, source string, endpoint string) string {
-	baseURL := strings.ToLower(s.baseURL)
-	useXMLRiverRoutes := strings.Contains(baseURL, "xmlriver")
-
 	if source == entities.YandexSearch {
 		if req.Organic {
-			if useXMLRiverRoutes {
-				return "/yandex/xml"
-			}
-			return "/yandexlive/xml"
+			return s.endpoints.YandexOrganic
 		}
-
-		if useXMLRiverRoutes {
-			return "/search_yandex/xml"
-		}
-		return "/yandex/xml"
+		return s.endpoints.Yandex
 	}
 
-	if useXMLRiverRoutes {
-		return "/search/xml"
-	}
-	return "/google/xml"
+	return s.endpoints.Google
 }
 
-func (s *XMLRiverService) findSitePositionInternalWithSubdomains(req SearchRequest, siteDomain string, source string, maxPages int, subdomains bool) (int, string, string, error) {
+func (s *XMLRiverService) findSitePositionInternalWithSubdomains(ctx context.Context, req SearchRequest, siteDomain string, competitorDomains []string, source string, maxPages int, subdomains bool) (*domainservices.SitePositionResult, error) {
+	result := &domainservices.SitePositionResult{
+		Competitors: make([]domainservices.DomainPosition, len(competitorDomains)),
+	}
+	for i, domain := range competitorDomains {
+		result.Competitors[i].Domain = domain
+	}
+
 	if source == entities.YandexSearch && !req.Organic && req.GroupBy > 0 {
 		req.Page = 0
-		resp, err := s.Search(req, source)
+		resp, err := s.Search(ctx, req, source)
 
 		if err != nil {
-			errStr := err.Error()
-			if strings.Contains(errStr, "error 18") {
-				return 0, "", "", nil
+			if domainservices.IsProviderErrorKind(err, domainservices.ProviderErrorNoResults) {
+				return result, nil
 			}
-			return 0, "", "", fmt.Errorf("failed to search: %w", err)
+			return result, fmt.Errorf("failed to search: %w", err)
 		}
+		result.Requests++
 
-		position := 1
-		for _, group := range resp.Response.Results.Grouping.Groups {
-			for _, doc := range group.Docs {
-				if s.isSiteMatchWithSubdomains(doc.URL, siteDomain, subdomains) {
-					return position, doc.URL, doc.Title, nil
-				}
-				position++
-			}
-		}
-		return 0, "", "", nil
+		s.collectPage(result, resp, 0, siteDomain, source, subdomains)
+		return result, nil
 	}
 
 	for page := 0; page <= maxPages-1; page++ {
 		req.Page = page
 
-		resp, err := s.Search(req, source)
+		resp, err := s.Search(ctx, req, source)
 		if err != nil {
-			errStr := err.Error()
-			if source == entities.YandexSearch && strings.Contains(errStr, "error 18") {
-				return 0, "", "", nil
+			// Выдача закончилась раньше maxPages: сайт и конкуренты на следующих страницах уже не найдутся
+			if domainservices.IsProviderErrorKind(err, domainservices.ProviderErrorNoResults) {
+				return result, nil
 			}
-			return 0, "", "", fmt.Errorf("failed to search page %d: %w", page, err)
+			return result, fmt.Errorf("failed to search page %d: %w", page, err)
 		}
+		result.Requests++
 
-		position := 1
-		for _, group := range resp.Response.Results.Grouping.Groups {
-			for _, doc := range group.Docs {
-				if doc.ContentType != "organic" && source == entities.GoogleSearch {
-					continue
-				}
-				if s.isSiteMatchWithSubdomains(doc.URL, siteDomain, subdomains) {
-					absolutePosition := (page)*10 + position
-					return absolutePosition, doc.URL, doc.Title, nil
-				}
-				position++
-			}
+		s.collectPage(result, resp, page, siteDomain, source, subdomains)
+		// Страница с последним найденным доменом уже сохранена целиком, следующие не запрашиваем
+		if allDomainsFound(result) {
+			break
 		}
 	}
 
-	return 0, "", "", nil
+	return result, nil
 }
-func (s *XMLRiverService) findSitePositionInternal(req SearchRequest, siteDomain string, source string, maxPages int) (int, string, string, error) {
-	for page := 0; page <= maxPages-1; page++ {
-		req.Page = page
 
-		resp, err := s.Search(req, source)
-		if err != nil {
-			errStr := err.Error()
-			if source == entities.YandexSearch && strings.Contains(errStr, "error 18") {
-				return 0, "", "", nil
+// collectPage добавляет документы страницы в снимок выдачи и запоминает первое совпадение с сайтом
+func (s *XMLRiverService) collectPage(result *domainservices.SitePositionResult, resp *SearchResponse, page int, siteDomain, source string, subdomains bool) {
+	position := 1
+	for _, group := range resp.Response.Results.Grouping.Groups {
+		for _, doc := range group.Docs {
+			item := entities.SerpItem{
+				Place:       len(result.Items) + 1,
+				URL:         doc.URL,
+				Domain:      strings.TrimPrefix(s.extractDomain(doc.URL), "www."),
+				Title:       doc.Title,
+				Passage:     joinPassages(doc.Passages),
+				ContentType: doc.ContentType,
+			}
+
+			// Неорганические блоки Google сохраняем, но в позиции не учитываем
+			if doc.ContentType != "organic" && source == entities.GoogleSearch {
+				result.Items = append(result.Items, item)
+				continue
 			}
-			return 0, "", "", fmt.Errorf("failed to search page %d: %w", page, err)
-		}
 
-		position := 1
-		for _, group := range resp.Response.Results.Grouping.Groups {
-			for _, doc := range group.Docs {
-				if doc.ContentType == "organic" {
-					if s.isSiteMatch(doc.URL, siteDomain) {
-						absolutePosition := (page)*10 + position
-						return absolutePosition, doc.URL, doc.Title, nil
-					}
-					position++
+			item.Rank = page*10 + position
+			result.Items = append(result.Items, item)
+			if result.Position == 0 && s.isSiteMatchWithSubdomains(doc.URL, siteDomain, subdomains) {
+				result.Position = item.Rank
+				result.URL = doc.URL
+				result.Title = doc.Title
+			}
+			for i := range result.Competitors {
+				competitor := &result.Competitors[i]
+				if competitor.Position == 0 && s.isSiteMatchWithSubdomains(doc.URL, competitor.Domain, subdomains) {
+					competitor.Position = item.Rank
+					competitor.URL = doc.URL
+					competitor.Title = doc.Title
 				}
 			}
+			position++
 		}
 	}
-
-	return 0, "", "", nil
 }
 
-func (s *XMLRiverService) FindSitePosition(query, siteDomain, source string, maxPages int, device, os string, ads bool, country, lang string, lr int) (int, string, string, error) {
-	req := SearchRequest{
-		Query:   query,
-		Page:    0,
-		Device:  device,
-		OS:      os,
-		Ads:     ads,
-		Country: country,
-		Lang:    lang,
-		LR:      lr,
+func allDomainsFound(result *domainservices.SitePositionResult) bool {
+	if result.Position == 0 {
+		return false
 	}
+	for _, competitor := range result.Competitors {
+		if competitor.Position == 0 {
+			return false
+		}
+	}
+	return true
+}
 
-	return s.findSitePositionInternal(req, siteDomain, source, maxPages)
+func joinPassages(passages []string) string {
+	var parts []string
+	for _, passage := range passages {
+		if passage = strings.TrimSpace(passage); passage != "" {
+			parts = append(parts, passage)
+		}
+	}
+	return strings.Join(parts, " ")
 }
 
 func (s *XMLRiverService) isSiteMatch(resultURL, siteDomain string) bool {
@@ -317,22 +430,11 @@ func (s *XMLRiverService) isSiteMatch(resultURL, siteDomain string) bool {
 
 	return resultDomain == siteDomainExtracted
 }
-func (s *XMLRiverService) FindSitePositionWithSubdomains(query, siteDomain, source string, maxPages int, device, os string, ads bool, country, lang string, subdomains bool, lr int, domain int, organic bool, groupBy int) (int, string, string, error) {
-	req := SearchRequest{
-		Query:   query,
-		Page:    0,
-		Device:  device,
-		OS:      os,
-		Ads:     ads,
-		Country: country,
-		Lang:    lang,
-		LR:      lr,
-		Domain:  domain,
-		Organic: organic,
-		GroupBy: groupBy,
-	}
 
-	return s.findSitePositionInternalWithSubdomains(req, siteDomain, source, maxPages, subdomains)
+// FindSitePosition ищет позицию сайта, передавая провайдеру все параметры из req
+func (s *XMLRiverService) FindSitePosition(ctx context.Context, req SearchRequest, siteDomain string, competitorDomains []string, source string, maxPages int, subdomains bool) (*domainservices.SitePositionResult, error) {
+	req.Page = 0
+	return s.findSitePositionInternalWithSubdomains(ctx, req, siteDomain, competitorDomains, source, maxPages, subdomains)
 }
 
 func (s *XMLRiverService) isSiteMatchWithSubdomains(resultURL, siteDomain string, subdomains bool) bool {
@@ -378,6 +480,33 @@ func (s *XMLRiverService) Close() error {
 	return nil
 }
 
+func (s *XMLRiverService) Name() string {
+	return s.name
+}
+
+func (s *XMLRiverService) Account() string {
+	return s.userID
+}
+
+func (s *XMLRiverService) Capabilities() domainservices.ProviderCapabilities {
+	return s.capabilities
+}
+
+// WithCredentials возвращает копию провайдера с пользовательскими user/key; пустой baseURL оставляет текущий. HTTP клиент общий
+func (s *XMLRiverService) WithCredentials(baseURL, userID, apiKey string) (domainservices.SearchService, error) {
+	if userID == "" || apiKey == "" {
+		return nil, fmt.Errorf("user id and api key are required")
+	}
+
+	clone := *s
+	if baseURL != "" {
+		clone.baseURL = baseURL
+	}
+	clone.userID = userID
+	clone.apiKey = apiKey
+	return &clone, nil
+}
+
 func (s *XMLRiverService) IsSiteMatch(resultURL, siteDomain string) bool {
 	return s.isSiteMatch(resultURL, siteDomain)
 }
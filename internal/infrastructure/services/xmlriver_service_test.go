@@ -1,10 +1,19 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"go-seo/internal/domain/entities"
+	domainservices "go-seo/internal/domain/services"
+	"go-seo/pkg/logger"
 )
 
 func TestSearchResponseErrorHandling(t *testing.T) {
@@ -86,48 +95,48 @@ func TestSearchResponseSuccessHandling(t *testing.T) {
 	}
 }
 
-func TestGetSearchURLByBaseDomain(t *testing.T) {
+func TestGetSearchURLByProviderEndpoints(t *testing.T) {
 	tests := []struct {
 		name        string
-		baseURL     string
+		endpoints   SearchEndpoints
 		source      string
 		organic     bool
 		expectedURL string
 	}{
 		{
 			name:        "XMLStock Google",
-			baseURL:     "https://xmlstock.com",
+			endpoints:   XMLStockEndpoints,
 			source:      entities.GoogleSearch,
 			expectedURL: "/google/xml",
 		},
 		{
 			name:        "XMLStock Yandex organic",
-			baseURL:     "https://xmlstock.com/api",
+			endpoints:   XMLStockEndpoints,
 			source:      entities.YandexSearch,
 			organic:     true,
 			expectedURL: "/yandexlive/xml",
 		},
 		{
 			name:        "XMLStock Yandex regular",
-			baseURL:     "https://xmlstock.com",
+			endpoints:   XMLStockEndpoints,
 			source:      entities.YandexSearch,
 			expectedURL: "/yandex/xml",
 		},
 		{
 			name:        "XMLRiver Google",
-			baseURL:     "https://xmlriver.com",
+			endpoints:   XMLRiverEndpoints,
 			source:      entities.GoogleSearch,
 			expectedURL: "/search/xml",
 		},
 		{
 			name:        "XMLRiver Yandex regular",
-			baseURL:     "https://xmlriver.com",
+			endpoints:   XMLRiverEndpoints,
 			source:      entities.YandexSearch,
 			expectedURL: "/search_yandex/xml",
 		},
 		{
 			name:        "XMLRiver Yandex organic",
-			baseURL:     "https://xmlriver.com",
+			endpoints:   XMLRiverEndpoints,
 			source:      entities.YandexSearch,
 			organic:     true,
 			expectedURL: "/yandex/xml",
@@ -136,7 +145,7 @@ func TestGetSearchURLByBaseDomain(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := &XMLRiverService{baseURL: tt.baseURL}
+			service := &XMLRiverService{endpoints: tt.endpoints}
 			req := SearchRequest{Organic: tt.organic}
 			result := service.getSearchUrl(req, tt.source, "")
 			if result != tt.expectedURL {
@@ -145,3 +154,349 @@ func TestGetSearchURLByBaseDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildSearchURL(t *testing.T) {
+	filterOff := 0
+	filterOn := 1
+
+	tests := []struct {
+		name        string
+		baseURL     string
+		endpoints   SearchEndpoints
+		softID      string
+		source      string
+		req         SearchRequest
+		expectedURL string
+	}{
+		{
+			name:      "XMLRiver Google all parameters",
+			baseURL:   "https://xmlriver.com",
+			endpoints: XMLRiverEndpoints,
+			softID:    "14",
+			source:    entities.GoogleSearch,
+			req: SearchRequest{
+				Query:        "купить диван",
+				Page:         1,
+				Device:       "mobile",
+				OS:           "android",
+				Ads:          true,
+				Country:      "2008",
+				Lang:         "ru",
+				LR:           213,
+				Domain:       10,
+				YandexDomain: "ru",
+				TBS:          "qdr:w",
+				Filter:       &filterOff,
+				Highlights:   1,
+				NFPR:         1,
+				Loc:          1000028,
+				AI:           1,
+				Raw:          "page",
+				InIndex:      1,
+				Strict:       1,
+				Within:       77,
+			},
+			expectedURL: "https://xmlriver.com/search/xml?ads=1&ai=1&country=2008&device=mobile&domain=10&filter=0&highlights=1&inindex=1&key=secret&lang=ru&loc=1000028&lr=213&nfpr=1&os=android&page=2&query=%D0%BA%D1%83%D0%BF%D0%B8%D1%82%D1%8C+%D0%B4%D0%B8%D0%B2%D0%B0%D0%BD&raw=page&soft_id=14&strict=1&tbs=qdr%3Aw&user=42",
+		},
+		{
+			name:        "XMLStock Google first page without optional parameters",
+			baseURL:     "https://xmlstock.com",
+			endpoints:   XMLStockEndpoints,
+			source:      entities.GoogleSearch,
+			req:         SearchRequest{Query: "sofa", Device: "desktop", OS: "ios", Strict: 1},
+			expectedURL: "https://xmlstock.com/google/xml?device=desktop&key=secret&query=sofa&strict=1&user=42",
+		},
+		{
+			name:      "XMLRiver Yandex all parameters",
+			baseURL:   "https://xmlriver.com",
+			endpoints: XMLRiverEndpoints,
+			softID:    "14",
+			source:    entities.YandexSearch,
+			req: SearchRequest{
+				Query:        "sofa",
+				Page:         1,
+				Device:       "desktop",
+				Lang:         "ru",
+				LR:           213,
+				GroupBy:      100,
+				Filter:       &filterOn,
+				Highlights:   1,
+				Within:       2,
+				Raw:          "page",
+				InIndex:      1,
+				Strict:       1,
+				YandexDomain: "com.tr",
+				Domain:       10,
+				TBS:          "qdr:w",
+				NFPR:         1,
+				Loc:          1000028,
+				AI:           1,
+			},
+			expectedURL: "https://xmlriver.com/search_yandex/xml?device=desktop&domain=com.tr&filter=1&groupby=100&highlights=1&inindex=1&key=secret&lang=ru&lr=213&page=1&query=sofa&raw=page&soft_id=14&strict=1&user=42&within=2",
+		},
+		{
+			name:        "XMLRiver Yandex organic",
+			baseURL:     "https://xmlriver.com",
+			endpoints:   XMLRiverEndpoints,
+			source:      entities.YandexSearch,
+			req:         SearchRequest{Query: "sofa", Organic: true, Within: 77},
+			expectedURL: "https://xmlriver.com/yandex/xml?key=secret&query=sofa&user=42&within=77",
+		},
+		{
+			name:        "XMLStock Yandex regular",
+			baseURL:     "https://xmlstock.com",
+			endpoints:   XMLStockEndpoints,
+			softID:      "abc",
+			source:      entities.YandexSearch,
+			req:         SearchRequest{Query: "sofa", LR: 2, YandexDomain: "kz", GroupBy: 30, Filter: &filterOff},
+			expectedURL: "https://xmlstock.com/yandex/xml?domain=kz&filter=0&groupby=30&key=secret&lr=2&query=sofa&soft_id=abc&user=42",
+		},
+		{
+			name:        "XMLStock Yandex organic",
+			baseURL:     "https://xmlstock.com",
+			endpoints:   XMLStockEndpoints,
+			source:      entities.YandexSearch,
+			req:         SearchRequest{Query: "sofa", Organic: true, Page: 3},
+			expectedURL: "https://xmlstock.com/yandexlive/xml?key=secret&page=3&query=sofa&user=42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &XMLRiverService{baseURL: tt.baseURL, endpoints: tt.endpoints, userID: "42", apiKey: "secret", softID: tt.softID}
+			result, _ := service.buildSearchURL(tt.req, tt.source)
+			if result != tt.expectedURL {
+				t.Fatalf("expected %s, got %s", tt.expectedURL, result)
+			}
+		})
+	}
+}
+
+func TestCollectPageKeepsFullSERP(t *testing.T) {
+	googleXML := `<?xml version="1.0" encoding="UTF-8"?>
+<yandexsearch version="1.0">
+<response date="20251007T163152">
+<results>
+<grouping>
+<group id="1"><doccount>1</doccount><doc>
+<url>https://ads.example.com/</url><title>Реклама</title><contenttype>ads</contenttype>
+<passages><passage/></passages>
+</doc></group>
+<group id="2"><doccount>1</doccount><doc>
+<url>https://www.competitor.ru/</url><title>Конкурент</title><contenttype>organic</contenttype>
+<passages><passage>Первый фрагмент.</passage><passage>Второй фрагмент.</passage></passages>
+</doc></group>
+<group id="3"><doccount>1</doccount><doc>
+<url>https://shop.example.ru/catalog</url><title>Наш сайт</title><contenttype>organic</contenttype>
+</doc></group>
+<group id="4"><doccount>1</doccount><doc>
+<url>https://other.ru/</url><title>Ниже нас</title><contenttype>organic</contenttype>
+</doc></group>
+</grouping>
+</results>
+</response>
+</yandexsearch>`
+
+	var resp SearchResponse
+	if err := xml.Unmarshal([]byte(googleXML), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal XML: %v", err)
+	}
+
+	service := &XMLRiverService{}
+	result := &domainservices.SitePositionResult{
+		Competitors: []domainservices.DomainPosition{{Domain: "competitor.ru"}, {Domain: "missing.ru"}},
+	}
+	service.collectPage(result, &resp, 1, "example.ru", entities.GoogleSearch, true)
+
+	if result.Competitors[0].Position != 11 || result.Competitors[0].URL != "https://www.competitor.ru/" {
+		t.Errorf("unexpected competitor match: %+v", result.Competitors[0])
+	}
+	if result.Competitors[1].Position != 0 {
+		t.Errorf("expected missing competitor to stay at 0, got %d", result.Competitors[1].Position)
+	}
+	if allDomainsFound(result) {
+		t.Error("expected search to continue while a competitor is not found")
+	}
+
+	if result.Position != 12 || result.URL != "https://shop.example.ru/catalog" || result.Title != "Наш сайт" {
+		t.Fatalf("unexpected match: %d %s %s", result.Position, result.URL, result.Title)
+	}
+
+	expected := []entities.SerpItem{
+		{Place: 1, Rank: 0, URL: "https://ads.example.com/", Domain: "ads.example.com", Title: "Реклама", ContentType: "ads"},
+		{Place: 2, Rank: 11, URL: "https://www.competitor.ru/", Domain: "competitor.ru", Title: "Конкурент", Passage: "Первый фрагмент. Второй фрагмент.", ContentType: "organic"},
+		{Place: 3, Rank: 12, URL: "https://shop.example.ru/catalog", Domain: "shop.example.ru", Title: "Наш сайт", ContentType: "organic"},
+		{Place: 4, Rank: 13, URL: "https://other.ru/", Domain: "other.ru", Title: "Ниже нас", ContentType: "organic"},
+	}
+	if len(result.Items) != len(expected) {
+		t.Fatalf("expected %d items, got %d", len(expected), len(result.Items))
+	}
+	for i, item := range expected {
+		if result.Items[i] != item {
+			t.Errorf("item %d: expected %+v, got %+v", i, item, result.Items[i])
+		}
+	}
+}
+
+func TestSearchClassifiesProviderErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		kind      domainservices.ProviderErrorKind
+		retryable bool
+		fatal     bool
+	}{
+		{
+			name:      "overloaded",
+			status:    http.StatusOK,
+			body:      `<yandexsearch><response><error code="110">В данный момент сервис сильно перегружен.</error></response></yandexsearch>`,
+			kind:      domainservices.ProviderErrorOverloaded,
+			retryable: true,
+		},
+		{
+			name:   "invalid key",
+			status: http.StatusOK,
+			body:   `<yandexsearch><response><error code="42">Неверный ключ</error></response></yandexsearch>`,
+			kind:   domainservices.ProviderErrorAuth,
+			fatal:  true,
+		},
+		{
+			name:   "balance by message",
+			status: http.StatusOK,
+			body:   `<yandexsearch><response><error code="200">Недостаточно средств на балансе</error></response></yandexsearch>`,
+			kind:   domainservices.ProviderErrorBalance,
+			fatal:  true,
+		},
+		{
+			name:   "empty query",
+			status: http.StatusOK,
+			body:   `<yandexsearch><response><error code="2">Пустой запрос</error></response></yandexsearch>`,
+			kind:   domainservices.ProviderErrorInvalidQuery,
+		},
+		{
+			name:      "http 503",
+			status:    http.StatusServiceUnavailable,
+			kind:      domainservices.ProviderErrorOverloaded,
+			retryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			service, _ := NewXMLRiverService(ProviderConfig{Name: ProviderXMLRiver, BaseURL: server.URL, Endpoints: XMLRiverEndpoints})
+			_, err := service.Search(context.Background(), SearchRequest{Query: "диван"}, entities.GoogleSearch)
+
+			providerErr, ok := domainservices.AsProviderError(err)
+			if !ok {
+				t.Fatalf("expected provider error, got %v", err)
+			}
+			if providerErr.Kind != tt.kind {
+				t.Errorf("expected kind %s, got %s", tt.kind, providerErr.Kind)
+			}
+			if providerErr.Retryable() != tt.retryable {
+				t.Errorf("expected retryable %v", tt.retryable)
+			}
+			if providerErr.Fatal() != tt.fatal {
+				t.Errorf("expected fatal %v", tt.fatal)
+			}
+		})
+	}
+}
+
+func TestFindSitePositionStopsOnNoResults(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`<yandexsearch><response><error code="15">Нет результатов</error></response></yandexsearch>`))
+	}))
+	defer server.Close()
+
+	service, _ := NewXMLRiverService(ProviderConfig{Name: ProviderXMLRiver, BaseURL: server.URL, Endpoints: XMLRiverEndpoints})
+	result, err := service.FindSitePosition(context.Background(), SearchRequest{Query: "диван"}, "example.com", nil, entities.GoogleSearch, 5, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Position != 0 {
+		t.Errorf("expected site not found, got position %d", result.Position)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestSearchAbortsRequestOnCancel(t *testing.T) {
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	service, _ := NewXMLRiverService(ProviderConfig{Name: ProviderXMLRiver, BaseURL: server.URL, Endpoints: XMLRiverEndpoints})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := service.Search(ctx, SearchRequest{Query: "диван"}, entities.GoogleSearch)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if _, ok := domainservices.AsProviderError(err); ok {
+			t.Fatal("stopped request must not be reported as a provider error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("search did not stop after cancel")
+	}
+}
+
+func TestSearchDoesNotLogCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<yandexsearch><response><results><grouping></grouping></results></response></yandexsearch>`))
+	}))
+
+	var logged bytes.Buffer
+	output := logger.InfoLogger.Writer()
+	logger.InfoLogger.SetOutput(&logged)
+	defer logger.InfoLogger.SetOutput(output)
+
+	service, _ := NewXMLRiverService(ProviderConfig{
+		Name: ProviderXMLRiver, BaseURL: server.URL, UserID: "user-4242", APIKey: "secret-key-0001", Endpoints: XMLRiverEndpoints,
+	})
+	if _, err := service.Search(context.Background(), SearchRequest{Query: "диван"}, entities.GoogleSearch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	line := logged.String()
+	if !strings.Contains(line, "XMLRiver request") || !strings.Contains(line, "query=") {
+		t.Fatalf("expected request to be logged, got %q", line)
+	}
+	if strings.Contains(line, "secret-key-0001") || strings.Contains(line, "user-4242") {
+		t.Fatalf("credentials leaked to the log: %q", line)
+	}
+
+	// Сетевая ошибка содержит URL запроса: ключ не должен попасть и в текст ошибки
+	server.Close()
+	_, err := service.Search(context.Background(), SearchRequest{Query: "диван"}, entities.GoogleSearch)
+	if err == nil {
+		t.Fatal("expected network error")
+	}
+	if strings.Contains(err.Error(), "secret-key-0001") || strings.Contains(err.Error(), "user-4242") {
+		t.Fatalf("credentials leaked to the error: %v", err)
+	}
+}
@@ -11,10 +11,24 @@ type Container struct {
 	Keyword        repositories.KeywordRepository
 	Site           repositories.SiteRepository
 	Group          repositories.GroupRepository
+	Tag            repositories.TagRepository
+	Suggestion     repositories.KeywordSuggestionRepository
+	Cluster        repositories.KeywordClusterRepository
 	Position       repositories.PositionRepository
+	Retention      repositories.PositionRetentionRepository
+	Visibility     repositories.VisibilityRepository
+	Movement       repositories.MovementRepository
+	LandingPage    repositories.LandingPageRepository
 	TrackingJob    repositories.TrackingJobRepository
 	TrackingTask   repositories.TrackingTaskRepository
 	TrackingResult repositories.TrackingResultRepository
+	SerpSnapshot   repositories.SerpSnapshotRepository
+	Competitor     repositories.CompetitorRepository
+	Schedule       repositories.TrackingScheduleRepository
+	Usage          repositories.UsageRepository
+	Account        repositories.ProviderAccountRepository
+	APIKey         repositories.APIKeyRepository
+	Workspace      repositories.WorkspaceRepository
 }
 
 func NewContainer(db *gorm.DB) *Container {
@@ -24,9 +38,23 @@ func NewContainer(db *gorm.DB) *Container {
 		Keyword:        postgresRepos.Keyword,
 		Site:           postgresRepos.Site,
 		Group:          postgresRepos.Group,
+		Tag:            postgresRepos.Tag,
+		Suggestion:     postgresRepos.Suggestion,
+		Cluster:        postgresRepos.Cluster,
 		Position:       postgresRepos.Position,
+		Retention:      postgresRepos.Retention,
+		Visibility:     postgresRepos.Visibility,
+		Movement:       postgresRepos.Movement,
+		LandingPage:    postgresRepos.LandingPage,
 		TrackingJob:    postgresRepos.TrackingJob,
 		TrackingTask:   postgresRepos.TrackingTask,
 		TrackingResult: postgresRepos.TrackingResult,
+		SerpSnapshot:   postgresRepos.SerpSnapshot,
+		Competitor:     postgresRepos.Competitor,
+		Schedule:       postgresRepos.Schedule,
+		Usage:          postgresRepos.Usage,
+		Account:        postgresRepos.Account,
+		APIKey:         postgresRepos.APIKey,
+		Workspace:      postgresRepos.Workspace,
 	}
 }
@@ -0,0 +1,246 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	apiKeyPrefix = "gs_"
+	// Длина отпечатка ключа: начало SHA-256, по которому ключ узнают в списке
+	apiKeyFingerprintLen = 12
+	// Время последнего использования пишем не чаще раза в минуту, а не на каждый запрос
+	apiKeyTouchInterval = time.Minute
+)
+
+type APIKeyUseCase struct {
+	keyRepo repositories.APIKeyRepository
+}
+
+func NewAPIKeyUseCase(keyRepo repositories.APIKeyRepository) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		keyRepo: keyRepo,
+	}
+}
+
+// IssueKey выпускает ключ в пространстве запроса и возвращает его в открытом виде; повторно получить его нельзя.
+// Без пространства выпускается системный ключ
+func (uc *APIKeyUseCase) IssueKey(workspaceID *int, name string, scopes []string) (*entities.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", &DomainError{
+			Code:    ErrorValidation,
+			Message: "API key name is required",
+		}
+	}
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	raw, err := generateAPIKey()
+	if err != nil {
+		return nil, "", &DomainError{
+			Code:    ErrorAPIKeyCreation,
+			Message: "Failed to generate API key",
+			Err:     err,
+		}
+	}
+
+	key, err := uc.createKey(workspaceID, name, raw, scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, raw, nil
+}
+
+// EnsureBootstrapKey регистрирует ключ из конфигурации как системный admin, чтобы было чем создать
+// пространства и выпустить первые ключи.
+// Если ключ уже есть (в том числе отозванный), ничего не делает
+func (uc *APIKeyUseCase) EnsureBootstrapKey(raw string) error {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	if _, err := uc.keyRepo.GetByHash(hashAPIKey(raw)); err == nil {
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return &DomainError{
+			Code:    ErrorAPIKeyFetch,
+			Message: "Failed to check bootstrap API key",
+			Err:     err,
+		}
+	}
+
+	key, err := uc.createKey(nil, "bootstrap", raw, []string{entities.ScopeAdmin})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Bootstrap API key registered with id %d", key.ID)
+	return nil
+}
+
+func (uc *APIKeyUseCase) GetKeys(workspaceID *int) ([]*entities.APIKey, error) {
+	keys, err := uc.keyRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorAPIKeyFetch,
+			Message: "Failed to fetch API keys",
+			Err:     err,
+		}
+	}
+
+	return keys, nil
+}
+
+func (uc *APIKeyUseCase) RevokeKey(workspaceID *int, id int) (*entities.APIKey, error) {
+	key, err := uc.keyRepo.GetByID(id)
+	if err == nil && workspaceID != nil && (key.WorkspaceID == nil || *key.WorkspaceID != *workspaceID) {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorAPIKeyNotFound,
+			Message: "API key not found",
+			Err:     err,
+		}
+	}
+
+	revoked, err := uc.keyRepo.Revoke(id, time.Now())
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorAPIKeyRevoke,
+			Message: "Failed to revoke API key",
+			Err:     err,
+		}
+	}
+	if !revoked {
+		return nil, &DomainError{
+			Code:    ErrorAPIKeyRevoked,
+			Message: "API key is already revoked",
+		}
+	}
+
+	return uc.keyRepo.GetByID(id)
+}
+
+// Authenticate находит действующий ключ по его открытому значению
+func (uc *APIKeyUseCase) Authenticate(raw string) (*entities.APIKey, error) {
+	if raw == "" {
+		return nil, &DomainError{
+			Code:    ErrorUnauthorized,
+			Message: "API key is required",
+		}
+	}
+
+	key, err := uc.keyRepo.GetByHash(hashAPIKey(raw))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &DomainError{
+				Code:    ErrorUnauthorized,
+				Message: "Invalid API key",
+			}
+		}
+		return nil, &DomainError{
+			Code:    ErrorAPIKeyFetch,
+			Message: "Failed to check API key",
+			Err:     err,
+		}
+	}
+
+	if key.Revoked() {
+		return nil, &DomainError{
+			Code:    ErrorUnauthorized,
+			Message: "API key is revoked",
+		}
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := uc.keyRepo.UpdateLastUsed(key.ID, now); err != nil {
+			log.Printf("WARNING: Failed to update last use of API key %d: %v", key.ID, err)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key, nil
+}
+
+func (uc *APIKeyUseCase) createKey(workspaceID *int, name, raw string, scopes []string) (*entities.APIKey, error) {
+	// Начало самого ключа сократило бы перебор, поэтому в списке показывается отпечаток хеша
+	keyHash := hashAPIKey(raw)
+	key := &entities.APIKey{
+		WorkspaceID: workspaceID,
+		Name:        name,
+		Prefix:      keyHash[:apiKeyFingerprintLen],
+		KeyHash:     keyHash,
+		Scopes:      scopes,
+	}
+
+	if err := uc.keyRepo.Create(key); err != nil {
+		return nil, &DomainError{
+			Code:    ErrorAPIKeyCreation,
+			Message: "Failed to create API key",
+			Err:     err,
+		}
+	}
+
+	return key, nil
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "At least one scope is required",
+		}
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !entities.IsValidAPIKeyScope(scope) {
+			return nil, &DomainError{
+				Code:    ErrorValidation,
+				Message: fmt.Sprintf("Unknown scope '%s', expected one of: %s", scope, strings.Join(entities.APIKeyScopes, ", ")),
+			}
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+
+	return result, nil
+}
+
+// Ключи случайные и длинные, поэтому для хранения достаточно SHA-256 без соли
+func hashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
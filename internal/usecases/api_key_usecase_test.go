@@ -0,0 +1,229 @@
+package usecases
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+// fakeAPIKeyRepository хранит ключи в памяти
+type fakeAPIKeyRepository struct {
+	keys      []*entities.APIKey
+	lookupErr error
+	touched   int
+}
+
+func (r *fakeAPIKeyRepository) Create(key *entities.APIKey) error {
+	key.ID = len(r.keys) + 1
+	key.CreatedAt = time.Now()
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *fakeAPIKeyRepository) GetByID(id int) (*entities.APIKey, error) {
+	for _, key := range r.keys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAPIKeyRepository) GetByHash(hash string) (*entities.APIKey, error) {
+	if r.lookupErr != nil {
+		return nil, r.lookupErr
+	}
+	for _, key := range r.keys {
+		if key.KeyHash == hash {
+			return key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAPIKeyRepository) GetAll(workspaceID *int) ([]*entities.APIKey, error) {
+	return r.keys, nil
+}
+
+func (r *fakeAPIKeyRepository) Revoke(id int, at time.Time) (bool, error) {
+	key, err := r.GetByID(id)
+	if err != nil {
+		return false, err
+	}
+	if key.Revoked() {
+		return false, nil
+	}
+	key.RevokedAt = &at
+	return true, nil
+}
+
+func (r *fakeAPIKeyRepository) UpdateLastUsed(id int, at time.Time) error {
+	r.touched++
+	return nil
+}
+
+func TestNormalizeScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", scopes: nil, wantErr: true},
+		{name: "unknown scope", scopes: []string{entities.ScopePositionsRead, "positions:write"}, wantErr: true},
+		{name: "blank scope", scopes: []string{" "}, wantErr: true},
+		{name: "trims spaces", scopes: []string{" admin "}, want: []string{entities.ScopeAdmin}},
+		{
+			name:   "drops duplicates keeping order",
+			scopes: []string{entities.ScopeTrackingStart, entities.ScopePositionsRead, entities.ScopeTrackingStart},
+			want:   []string{entities.ScopeTrackingStart, entities.ScopePositionsRead},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeScopes(tt.scopes)
+			if tt.wantErr {
+				if GetDomainErrorCode(err) != ErrorValidation {
+					t.Fatalf("expected validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHashAPIKey(t *testing.T) {
+	hash := hashAPIKey("gs_secret")
+	if len(hash) != 64 {
+		t.Fatalf("expected hex SHA-256, got %q", hash)
+	}
+	if hash != hashAPIKey("gs_secret") {
+		t.Fatal("hash must be deterministic")
+	}
+	if hash == hashAPIKey("gs_secreT") {
+		t.Fatal("different keys must have different hashes")
+	}
+	if strings.Contains(hash, "secret") {
+		t.Fatal("hash must not contain the key")
+	}
+}
+
+func TestIssueKeyStoresOnlyHash(t *testing.T) {
+	repo := &fakeAPIKeyRepository{}
+	uc := NewAPIKeyUseCase(repo)
+
+	key, raw, err := uc.IssueKey(nil, " ci ", []string{entities.ScopePositionsRead})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		t.Fatalf("expected key with prefix %s, got %q", apiKeyPrefix, raw)
+	}
+	if key.Name != "ci" || key.Prefix != hashAPIKey(raw)[:apiKeyFingerprintLen] {
+		t.Fatalf("unexpected key %+v", key)
+	}
+	// Отпечаток не раскрывает начало ключа
+	if strings.Contains(raw, key.Prefix) || strings.HasPrefix(key.Prefix, apiKeyPrefix) {
+		t.Fatalf("prefix %q is part of the secret key", key.Prefix)
+	}
+	if key.KeyHash != hashAPIKey(raw) {
+		t.Fatal("expected the key hash to be stored")
+	}
+
+	if _, _, err := uc.IssueKey(nil, "", []string{entities.ScopeAdmin}); GetDomainErrorCode(err) != ErrorValidation {
+		t.Fatalf("expected validation error for empty name, got %v", err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	repo := &fakeAPIKeyRepository{}
+	uc := NewAPIKeyUseCase(repo)
+
+	_, raw, err := uc.IssueKey(nil, "reader", []string{entities.ScopePositionsRead})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	_, revokedRaw, err := uc.IssueKey(nil, "old", []string{entities.ScopeAdmin})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if _, err := repo.Revoke(2, time.Now()); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+
+	key, err := uc.Authenticate(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key.ID != 1 || key.LastUsedAt == nil {
+		t.Fatalf("expected key 1 with last use, got %+v", key)
+	}
+	// Повторный запрос в пределах минуты не пишет время использования
+	if _, err := uc.Authenticate(raw); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.touched != 1 {
+		t.Fatalf("expected last use to be written once, got %d", repo.touched)
+	}
+
+	for name, value := range map[string]string{"empty": "", "unknown": "gs_unknown", "revoked": revokedRaw} {
+		if _, err := uc.Authenticate(value); GetDomainErrorCode(err) != ErrorUnauthorized {
+			t.Errorf("%s: expected unauthorized, got %v", name, err)
+		}
+	}
+
+	repo.lookupErr = errors.New("connection refused")
+	if _, err := uc.Authenticate(raw); GetDomainErrorCode(err) != ErrorAPIKeyFetch {
+		t.Fatalf("expected fetch error, got %v", err)
+	}
+}
+
+func TestEnsureBootstrapKey(t *testing.T) {
+	repo := &fakeAPIKeyRepository{}
+	uc := NewAPIKeyUseCase(repo)
+
+	if err := uc.EnsureBootstrapKey("  "); err != nil || len(repo.keys) != 0 {
+		t.Fatalf("empty key must be ignored, got %v and %d keys", err, len(repo.keys))
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := uc.EnsureBootstrapKey(" gs_bootstrap "); err != nil {
+			t.Fatalf("run %d: unexpected error: %v", i+1, err)
+		}
+	}
+	if len(repo.keys) != 1 {
+		t.Fatalf("expected one bootstrap key, got %d", len(repo.keys))
+	}
+	key := repo.keys[0]
+	if key.WorkspaceID != nil || key.KeyHash != hashAPIKey("gs_bootstrap") || !key.HasScope(entities.ScopeAdmin) {
+		t.Fatalf("unexpected bootstrap key %+v", key)
+	}
+
+	// Отозванный bootstrap-ключ не восстанавливается при рестарте
+	if _, err := repo.Revoke(key.ID, time.Now()); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if err := uc.EnsureBootstrapKey("gs_bootstrap"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.keys) != 1 || !repo.keys[0].Revoked() {
+		t.Fatal("revoked bootstrap key must stay revoked")
+	}
+
+	repo.lookupErr = errors.New("connection refused")
+	if err := uc.EnsureBootstrapKey("gs_other"); GetDomainErrorCode(err) != ErrorAPIKeyFetch {
+		t.Fatalf("expected fetch error, got %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package usecases
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -9,37 +11,42 @@ import (
 
 	"go-seo/internal/domain/entities"
 	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
 	"go-seo/internal/infrastructure/services"
 )
 
 type taskParams struct {
-	Device            string
-	OS                string
-	Ads               bool
-	Country           string
-	Lang              string
-	Pages             int
-	Subdomains        bool
-	XMLUserID         string
-	XMLAPIKey         string
-	XMLBaseURL        string
-	TBS               string
-	Filter            int
-	Highlights        int
-	NFPR              int
-	Loc               int
-	AI                int
-	Raw               string
-	GroupBy           int
-	Within            int
-	LR                int
-	Domain            int
-	InIndex           int
-	Strict            int
-	Organic           bool
-	Regions           *int
-	FilterGroupID     *int
-	WordstatQueryType string
+	Device        string
+	OS            string
+	Ads           bool
+	Country       string
+	Lang          string
+	Pages         int
+	Subdomains    bool
+	AccountID     *int
+	Provider      string
+	TBS           string
+	Filter        *int
+	Highlights    int
+	NFPR          int
+	Loc           int
+	AI            int
+	Raw           string
+	GroupBy       int
+	Within        int
+	LR            int
+	Domain        int
+	YandexDomain  string
+	InIndex       int
+	Strict        int
+	Organic       bool
+	Regions       *int
+	FilterGroupID *int
+	// Конкуренты сайта, загружаются при запуске джоба
+	Competitors []*entities.Competitor
+	// Клиенты с учетными данными аккаунта джоба, создаются один раз при запуске
+	SearchProvider domainservices.SearchService
+	Wordstat       *services.WordstatService
 }
 
 type AsyncPositionTrackingUseCase struct {
@@ -49,20 +56,24 @@ type AsyncPositionTrackingUseCase struct {
 	jobRepo        repositories.TrackingJobRepository
 	taskRepo       repositories.TrackingTaskRepository
 	resultRepo     repositories.TrackingResultRepository
-	xmlRiver       *services.XMLRiverService
-	xmlStock       *services.XMLRiverService
+	snapshotRepo   repositories.SerpSnapshotRepository
+	competitorRepo repositories.CompetitorRepository
+	usageRepo      repositories.UsageRepository
+	workspaceRepo  repositories.WorkspaceRepository
+	accounts       *ProviderAccountUseCase
+	movement       *MovementUseCase
+	providers      domainservices.SearchProviderRegistry
 	wordstat       *services.WordstatService
 	kafkaService   *services.KafkaService
 	idGenerator    *services.IDGeneratorService
 	retryService   *services.RetryService
 	workerPool     chan struct{}
 	batchSize      int
-	xmlRiverSoftID string
-	xmlStockSoftID string
-	// Семафоры для ограничения параллелизма к каждому xmlriver (по baseURL)
-	xmlRiverSemaphores       map[string]chan struct{}
-	xmlRiverSemMu            sync.RWMutex
-	maxConcurrentPerXMLRiver int
+	// Сигнал очереди о новом джобе, чтобы не ждать следующего опроса
+	queueWake chan struct{}
+	// Джобы, которые обрабатывает этот инстанс, для немедленной остановки при паузе или отмене
+	activeJobs   map[string]context.CancelFunc
+	activeJobsMu sync.Mutex
 }
 
 func NewAsyncPositionTrackingUseCase(
@@ -72,85 +83,78 @@ func NewAsyncPositionTrackingUseCase(
 	jobRepo repositories.TrackingJobRepository,
 	taskRepo repositories.TrackingTaskRepository,
 	resultRepo repositories.TrackingResultRepository,
-	xmlRiver *services.XMLRiverService,
-	xmlStock *services.XMLRiverService,
+	snapshotRepo repositories.SerpSnapshotRepository,
+	competitorRepo repositories.CompetitorRepository,
+	usageRepo repositories.UsageRepository,
+	workspaceRepo repositories.WorkspaceRepository,
+	accounts *ProviderAccountUseCase,
+	movement *MovementUseCase,
+	providers domainservices.SearchProviderRegistry,
 	wordstat *services.WordstatService,
 	kafkaService *services.KafkaService,
 	idGenerator *services.IDGeneratorService,
 	retryService *services.RetryService,
 	workerCount int,
 	batchSize int,
-	xmlRiverSoftID string,
-	xmlStockSoftID string,
 ) *AsyncPositionTrackingUseCase {
 	return &AsyncPositionTrackingUseCase{
-		siteRepo:                 siteRepo,
-		keywordRepo:              keywordRepo,
-		positionRepo:             positionRepo,
-		jobRepo:                  jobRepo,
-		taskRepo:                 taskRepo,
-		resultRepo:               resultRepo,
-		xmlRiver:                 xmlRiver,
-		xmlStock:                 xmlStock,
-		wordstat:                 wordstat,
-		kafkaService:             kafkaService,
-		idGenerator:              idGenerator,
-		retryService:             retryService,
-		workerPool:               make(chan struct{}, workerCount),
-		batchSize:                batchSize,
-		xmlRiverSoftID:           xmlRiverSoftID,
-		xmlStockSoftID:           xmlStockSoftID,
-		xmlRiverSemaphores:       make(map[string]chan struct{}),
-		maxConcurrentPerXMLRiver: 10,
+		siteRepo:       siteRepo,
+		keywordRepo:    keywordRepo,
+		positionRepo:   positionRepo,
+		jobRepo:        jobRepo,
+		taskRepo:       taskRepo,
+		resultRepo:     resultRepo,
+		snapshotRepo:   snapshotRepo,
+		competitorRepo: competitorRepo,
+		usageRepo:      usageRepo,
+		workspaceRepo:  workspaceRepo,
+		accounts:       accounts,
+		movement:       movement,
+		providers:      providers,
+		wordstat:       wordstat,
+		kafkaService:   kafkaService,
+		idGenerator:    idGenerator,
+		retryService:   retryService,
+		workerPool:     make(chan struct{}, workerCount),
+		batchSize:      batchSize,
+		queueWake:      make(chan struct{}, 1),
+		activeJobs:     make(map[string]context.CancelFunc),
 	}
 }
 
 func (uc *AsyncPositionTrackingUseCase) StartAsyncGoogleTracking(
-	siteID int, device, os string, ads bool, country, lang string, pages int, subdomains bool,
-	xmlUserID, xmlAPIKey, xmlBaseURL, tbs string, filter, highlights, nfpr, loc, ai int, raw string,
-	lr int, domain int, filterGroupID *int,
+	workspaceID *int, siteID int, device, os string, ads bool, country, lang string, pages int, subdomains bool,
+	accountID *int, provider, tbs string, filter *int, highlights, nfpr, loc, ai int, raw string,
+	lr int, domain int, strict int, filterGroupID *int, apiKeyID *int,
 ) (string, error) {
-	site, err := uc.siteRepo.GetByID(siteID)
-	if err != nil {
-		return "", &DomainError{
-			Code:    ErrorPositionFetch,
-			Message: "Site not found",
-			Err:     err,
-		}
+	if err := validateTrackingParams(raw, 0); err != nil {
+		return "", err
 	}
 
-	keywords, err := uc.keywordRepo.GetBySiteID(siteID)
+	site, err := authorizeSite(uc.siteRepo, workspaceID, siteID)
 	if err != nil {
-		return "", &DomainError{
-			Code:    ErrorPositionFetch,
-			Message: fmt.Sprintf("Failed to fetch keywords for site %s", site.Domain),
-			Err:     err,
-		}
+		return "", err
 	}
 
-	jobID := uc.idGenerator.GenerateJobID()
-	job := &entities.TrackingJob{
-		ID:             jobID,
-		SiteID:         siteID,
-		Source:         entities.GoogleSearch,
-		Status:         entities.TaskStatusPending,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-		TotalTasks:     len(keywords),
-		CompletedTasks: 0,
-		FailedTasks:    0,
-		FailedRequests: 0,
+	account, err := uc.accounts.Resolve(site.WorkspaceID, accountID)
+	if err != nil {
+		return "", err
+	}
+	searchProvider, err := resolveSearchProvider(uc.providers, provider, entities.GoogleSearch, pages, account)
+	if err != nil {
+		return "", err
 	}
 
-	if err := uc.jobRepo.Create(job); err != nil {
+	keywords, err := uc.keywordRepo.GetBySiteID(siteID)
+	if err != nil {
 		return "", &DomainError{
-			Code:    ErrorPositionCreation,
-			Message: "Failed to create tracking job",
+			Code:    ErrorPositionFetch,
+			Message: fmt.Sprintf("Failed to fetch keywords for site %s", site.Domain),
 			Err:     err,
 		}
 	}
 
-	params := &taskParams{
+	params := entities.TrackingParams{
 		Device:        device,
 		OS:            os,
 		Ads:           ads,
@@ -158,9 +162,8 @@ func (uc *AsyncPositionTrackingUseCase) StartAsyncGoogleTracking(
 		Lang:          lang,
 		Pages:         pages,
 		Subdomains:    subdomains,
-		XMLUserID:     xmlUserID,
-		XMLAPIKey:     xmlAPIKey,
-		XMLBaseURL:    xmlBaseURL,
+		AccountID:     accountID,
+		Provider:      searchProvider.Name(), // Фиксируем провайдера: при возобновлении джоб продолжит с тем же
 		TBS:           tbs,
 		Filter:        filter,
 		Highlights:    highlights,
@@ -170,60 +173,47 @@ func (uc *AsyncPositionTrackingUseCase) StartAsyncGoogleTracking(
 		Raw:           raw,
 		LR:            lr,
 		Domain:        domain,
+		Strict:        strict,
 		FilterGroupID: filterGroupID,
 	}
 
-	go uc.processJob(jobID, params)
-
-	return jobID, nil
+	keywordCost := float64(estimateKeywordRequests(entities.GoogleSearch, params)) * searchProvider.Capabilities().CostPerRequest
+	return uc.enqueueWithinBudget(site, entities.GoogleSearch, keywords, nil, params, keywordCost, apiKeyID)
 }
 
 func (uc *AsyncPositionTrackingUseCase) StartAsyncYandexTracking(
-	siteID int, device, os string, ads bool, country, lang string, pages int, subdomains bool,
-	xmlUserID, xmlAPIKey, xmlBaseURL string, groupBy, filter, highlights, within, lr int, raw string, inIndex, strict int,
-	organic bool, filterGroupID *int,
+	workspaceID *int, siteID int, device, os string, ads bool, country, lang string, pages int, subdomains bool,
+	accountID *int, provider string, groupBy int, filter *int, highlights, within, lr int, domain string, raw string, inIndex, strict int,
+	organic bool, filterGroupID *int, apiKeyID *int,
 ) (string, error) {
-	site, err := uc.siteRepo.GetByID(siteID)
-	if err != nil {
-		return "", &DomainError{
-			Code:    ErrorPositionFetch,
-			Message: "Site not found",
-			Err:     err,
-		}
+	if err := validateTrackingParams(raw, inIndex); err != nil {
+		return "", err
 	}
 
-	keywords, err := uc.keywordRepo.GetBySiteID(siteID)
+	site, err := authorizeSite(uc.siteRepo, workspaceID, siteID)
 	if err != nil {
-		return "", &DomainError{
-			Code:    ErrorPositionFetch,
-			Message: fmt.Sprintf("Failed to fetch keywords for site %s", site.Domain),
-			Err:     err,
-		}
+		return "", err
 	}
 
-	jobID := uc.idGenerator.GenerateJobID()
-	job := &entities.TrackingJob{
-		ID:             jobID,
-		SiteID:         siteID,
-		Source:         entities.YandexSearch,
-		Status:         entities.TaskStatusPending,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-		TotalTasks:     len(keywords),
-		CompletedTasks: 0,
-		FailedTasks:    0,
-		FailedRequests: 0,
+	account, err := uc.accounts.Resolve(site.WorkspaceID, accountID)
+	if err != nil {
+		return "", err
+	}
+	searchProvider, err := resolveSearchProvider(uc.providers, provider, entities.YandexSearch, pages, account)
+	if err != nil {
+		return "", err
 	}
 
-	if err := uc.jobRepo.Create(job); err != nil {
+	keywords, err := uc.keywordRepo.GetBySiteID(siteID)
+	if err != nil {
 		return "", &DomainError{
-			Code:    ErrorPositionCreation,
-			Message: "Failed to create tracking job",
+			Code:    ErrorPositionFetch,
+			Message: fmt.Sprintf("Failed to fetch keywords for site %s", site.Domain),
 			Err:     err,
 		}
 	}
 
-	params := &taskParams{
+	params := entities.TrackingParams{
 		Device:        device,
 		OS:            os,
 		Ads:           ads,
@@ -231,14 +221,14 @@ func (uc *AsyncPositionTrackingUseCase) StartAsyncYandexTracking(
 		Lang:          lang,
 		Pages:         pages,
 		Subdomains:    subdomains,
-		XMLUserID:     xmlUserID,
-		XMLAPIKey:     xmlAPIKey,
-		XMLBaseURL:    xmlBaseURL,
+		AccountID:     accountID,
+		Provider:      searchProvider.Name(), // Фиксируем провайдера: при возобновлении джоб продолжит с тем же
 		GroupBy:       groupBy,
 		Filter:        filter,
 		Highlights:    highlights,
 		Within:        within,
 		LR:            lr,
+		YandexDomain:  domain,
 		Raw:           raw,
 		InIndex:       inIndex,
 		Strict:        strict,
@@ -246,22 +236,25 @@ func (uc *AsyncPositionTrackingUseCase) StartAsyncYandexTracking(
 		FilterGroupID: filterGroupID,
 	}
 
-	go uc.processJob(jobID, params)
-
-	return jobID, nil
+	keywordCost := float64(estimateKeywordRequests(entities.YandexSearch, params)) * searchProvider.Capabilities().CostPerRequest
+	return uc.enqueueWithinBudget(site, entities.YandexSearch, keywords, nil, params, keywordCost, apiKeyID)
 }
 
 func (uc *AsyncPositionTrackingUseCase) StartAsyncWordstatTracking(
-	siteID int, xmlUserID, xmlAPIKey, xmlBaseURL string, regions *int,
-	defaultQuery, quotes, quotesExclamationMarks, exclamationMarks bool,
+	workspaceID *int, siteID int, accountID *int, regions *int,
+	defaultQuery, quotes, quotesExclamationMarks, exclamationMarks bool, apiKeyID *int,
 ) (string, error) {
-	site, err := uc.siteRepo.GetByID(siteID)
+	site, err := authorizeSite(uc.siteRepo, workspaceID, siteID)
 	if err != nil {
-		return "", &DomainError{
-			Code:    ErrorPositionFetch,
-			Message: "Site not found",
-			Err:     err,
-		}
+		return "", err
+	}
+
+	account, err := uc.accounts.Resolve(site.WorkspaceID, accountID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := resolveWordstat(uc.wordstat, account); err != nil {
+		return "", err
 	}
 
 	keywords, err := uc.keywordRepo.GetBySiteID(siteID)
@@ -295,23 +288,107 @@ func (uc *AsyncPositionTrackingUseCase) StartAsyncWordstatTracking(
 		}
 	}
 
-	totalTasks := len(keywords) * len(queryTypes)
+	params := entities.TrackingParams{
+		AccountID:              accountID,
+		Regions:                regions,
+		DefaultQuery:           defaultQuery,
+		Quotes:                 quotes,
+		QuotesExclamationMarks: quotesExclamationMarks,
+		ExclamationMarks:       exclamationMarks,
+	}
+
+	// Каждый тип запроса - отдельный запрос частоты
+	keywordCost := float64(len(queryTypes)) * uc.wordstat.CostPerRequest()
+	return uc.enqueueWithinBudget(site, entities.Wordstat, keywords, queryTypes, params, keywordCost, apiKeyID)
+}
+
+// enqueueWithinBudget проверяет бюджет и создает джоб под блокировкой бюджета пространства:
+// иначе параллельные запуски видят один и тот же остаток и вместе его превышают
+func (uc *AsyncPositionTrackingUseCase) enqueueWithinBudget(
+	site *entities.Site, source string, keywords []*entities.Keyword, queryTypes []string,
+	params entities.TrackingParams, keywordCost float64, apiKeyID *int,
+) (string, error) {
+	var jobID string
+	err := uc.usageRepo.WithBudgetLock(site.WorkspaceID, func() error {
+		fitted, err := fitBudget(uc.usageRepo, uc.workspaceRepo, site, keywords, keywordCost)
+		if err != nil {
+			return err
+		}
+		jobID, err = uc.enqueueJob(site.ID, source, fitted, queryTypes, params, keywordCost*float64(len(fitted)), apiKeyID)
+		return err
+	})
+	if err != nil {
+		var domainErr *DomainError
+		if errors.As(err, &domainErr) {
+			return "", domainErr
+		}
+		return "", &DomainError{
+			Code:    ErrorPositionCreation,
+			Message: "Failed to check budget",
+			Err:     err,
+		}
+	}
+
+	return jobID, nil
+}
+
+// enqueueJob сохраняет джоб с параметрами и по задаче на каждый keyword (и тип запроса Wordstat).
+// Обработку запускает очередь (RunQueue), поэтому джоб переживает рестарт сервера.
+// apiKeyID - ключ API, которым запущен джоб (nil для расписаний).
+func (uc *AsyncPositionTrackingUseCase) enqueueJob(siteID int, source string, keywords []*entities.Keyword, queryTypes []string, params entities.TrackingParams, estimatedCost float64, apiKeyID *int) (string, error) {
+	if len(queryTypes) == 0 {
+		queryTypes = []string{""}
+	}
 
 	jobID := uc.idGenerator.GenerateJobID()
+	now := time.Now()
+
+	tasks := make([]*entities.TrackingTask, 0, len(keywords)*len(queryTypes))
+	for _, keyword := range keywords {
+		for _, queryType := range queryTypes {
+			tasks = append(tasks, &entities.TrackingTask{
+				ID:                uc.idGenerator.GenerateTaskID(),
+				JobID:             jobID,
+				KeywordID:         keyword.ID,
+				SiteID:            siteID,
+				Source:            source,
+				Status:            entities.TaskStatusPending,
+				CreatedAt:         now,
+				UpdatedAt:         now,
+				WordstatQueryType: queryType,
+			})
+		}
+	}
+
+	// Задачи пишем раньше джоба, чтобы воркер не арендовал джоб без задач
+	if err := uc.taskRepo.CreateBatch(tasks); err != nil {
+		return "", &DomainError{
+			Code:    ErrorPositionCreation,
+			Message: "Failed to create tracking tasks",
+			Err:     err,
+		}
+	}
+
 	job := &entities.TrackingJob{
 		ID:             jobID,
 		SiteID:         siteID,
-		Source:         entities.Wordstat,
+		Source:         source,
 		Status:         entities.TaskStatusPending,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-		TotalTasks:     totalTasks,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		TotalTasks:     len(tasks),
 		CompletedTasks: 0,
 		FailedTasks:    0,
 		FailedRequests: 0,
+		EstimatedCost:  estimatedCost,
+		Params:         params,
+		APIKeyID:       apiKeyID,
 	}
 
 	if err := uc.jobRepo.Create(job); err != nil {
+		if deleteErr := uc.taskRepo.DeleteByJobID(jobID); deleteErr != nil {
+			log.Printf("WARNING: Failed to delete tasks of job %s: %v", jobID, deleteErr)
+		}
 		return "", &DomainError{
 			Code:    ErrorPositionCreation,
 			Message: "Failed to create tracking job",
@@ -319,88 +396,114 @@ func (uc *AsyncPositionTrackingUseCase) StartAsyncWordstatTracking(
 		}
 	}
 
-	params := &taskParams{
-		XMLUserID:         xmlUserID,
-		XMLAPIKey:         xmlAPIKey,
-		XMLBaseURL:        xmlBaseURL,
-		Regions:           regions,
-		WordstatQueryType: strings.Join(queryTypes, ","), // Сохраняем все queryTypes через запятую
+	if apiKeyID != nil {
+		log.Printf("Tracking job %s (%s, site %d, %d tasks) started by API key %d", jobID, source, siteID, len(tasks), *apiKeyID)
+	} else {
+		log.Printf("Tracking job %s (%s, site %d, %d tasks) started without API key", jobID, source, siteID, len(tasks))
 	}
 
-	go uc.processJob(jobID, params)
+	uc.notifyQueue()
 
 	return jobID, nil
 }
 
-func (uc *AsyncPositionTrackingUseCase) processJob(jobID string, params *taskParams) {
-	job, err := uc.jobRepo.GetByID(jobID)
+// processJob обрабатывает арендованный джоб. Выполняются только задачи в статусе pending,
+// поэтому прерванный джоб продолжается с того места, где остановился.
+func (uc *AsyncPositionTrackingUseCase) processJob(ctx context.Context, job *entities.TrackingJob, workerID string, leaseDuration time.Duration) {
+	jobID := job.ID
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	uc.registerActiveJob(jobID, cancel)
+	defer uc.unregisterActiveJob(jobID)
+	go uc.keepLease(ctx, cancel, jobID, workerID, leaseDuration)
+
+	// Ошибка авторизации или баланса провайдера: остальные запросы джоба тоже не пройдут, поэтому останавливаем его целиком
+	var abortOnce sync.Once
+	var abortErr error
+	abort := func(err error) {
+		abortOnce.Do(func() {
+			abortErr = err
+			cancel()
+		})
+	}
+
+	if err := uc.kafkaService.SendJobStatus(jobID, string(entities.TaskStatusRunning), "", 0); err != nil {
+		log.Printf("WARNING: Failed to send job status to Kafka: %v", err)
+	}
+
+	// Получаем keywords напрямую
+	keywords, err := uc.keywordRepo.GetBySiteID(job.SiteID)
 	if err != nil {
-		if kafkaErr := uc.kafkaService.SendJobStatus(jobID, string(entities.TaskStatusFailed), err.Error()); kafkaErr != nil {
-			log.Printf("WARNING: Failed to send job status to Kafka: %v", kafkaErr)
-		}
+		uc.failJob(job, err)
 		return
 	}
 
-	if job.Status == entities.TaskStatusCompleted || job.Status == entities.TaskStatusFailed {
+	site, err := uc.siteRepo.GetByID(job.SiteID)
+	if err != nil {
+		uc.failJob(job, err)
 		return
 	}
 
-	if job.Status == entities.TaskStatusRunning {
+	params := newTaskParams(job.Params)
+	if err := uc.prepareClients(site, job.Source, params); err != nil {
+		uc.failJob(job, err)
 		return
 	}
-
-	job.Status = entities.TaskStatusRunning
-	uc.jobRepo.UpdateStatus(jobID, entities.TaskStatusRunning)
-	if err := uc.kafkaService.SendJobStatus(jobID, string(entities.TaskStatusRunning), "", 0); err != nil {
-		log.Printf("WARNING: Failed to send job status to Kafka: %v", err)
+	if job.Source != entities.Wordstat {
+		competitors, err := uc.competitorRepo.GetBySiteID(site.ID)
+		if err != nil {
+			uc.failJob(job, err)
+			return
+		}
+		params.Competitors = competitors
 	}
 
-	// Получаем keywords напрямую
-	keywords, err := uc.keywordRepo.GetBySiteID(job.SiteID)
+	counts, err := uc.taskRepo.CountByStatus(jobID)
 	if err != nil {
-		job.Status = entities.TaskStatusFailed
-		job.Error = err.Error()
-		uc.jobRepo.Update(job)
-		if kafkaErr := uc.kafkaService.SendJobStatus(jobID, string(entities.TaskStatusFailed), err.Error()); kafkaErr != nil {
-			log.Printf("WARNING: Failed to send job status to Kafka: %v", kafkaErr)
-		}
+		uc.failJob(job, err)
+		return
+	}
+	if len(counts) == 0 {
+		// Джобы, созданные до появления очереди, не имеют сохраненных задач
+		uc.failJob(job, fmt.Errorf("job has no persisted tasks"))
 		return
 	}
 
-	site, err := uc.siteRepo.GetByID(job.SiteID)
+	tasks, err := uc.taskRepo.GetPendingByJobID(jobID)
 	if err != nil {
-		job.Status = entities.TaskStatusFailed
-		job.Error = err.Error()
-		uc.jobRepo.Update(job)
-		if kafkaErr := uc.kafkaService.SendJobStatus(jobID, string(entities.TaskStatusFailed), err.Error()); kafkaErr != nil {
-			log.Printf("WARNING: Failed to send job status to Kafka: %v", kafkaErr)
-		}
+		uc.failJob(job, err)
 		return
 	}
 
-	// Для Wordstat нужно обработать каждый keyword с каждым queryType
+	keywordsByID := make(map[int]*entities.Keyword, len(keywords))
+	for _, keyword := range keywords {
+		keywordsByID[keyword.ID] = keyword
+	}
+
 	var workItems []workItem
-	if job.Source == entities.Wordstat {
-		queryTypes := strings.Split(params.WordstatQueryType, ",")
-		for _, keyword := range keywords {
-			for _, queryType := range queryTypes {
-				workItems = append(workItems, workItem{
-					Keyword:   keyword,
-					QueryType: strings.TrimSpace(queryType),
-				})
+	for _, task := range tasks {
+		keyword, ok := keywordsByID[task.KeywordID]
+		if !ok {
+			// Keyword удален после постановки джоба в очередь
+			if err := uc.taskRepo.Finish(task.ID, entities.TaskStatusFailed, "keyword not found", 0); err != nil {
+				log.Printf("WARNING: Failed to finish task %s: %v", task.ID, err)
 			}
+			counts[entities.TaskStatusFailed]++
+			continue
 		}
-	} else {
-		for _, keyword := range keywords {
-			workItems = append(workItems, workItem{
-				Keyword: keyword,
-			})
-		}
+		workItems = append(workItems, workItem{
+			TaskID:    task.ID,
+			Keyword:   keyword,
+			QueryType: task.WordstatQueryType,
+		})
 	}
 
-	// Отслеживание прогресса с отправкой каждые 5%
+	// Отслеживание прогресса с отправкой каждые 5%; при возобновлении счетчики берутся из задач
 	var progressMu sync.Mutex
-	var completedCount, failedCount, failedRequestsCount int
+	completedCount := counts[entities.TaskStatusCompleted]
+	failedCount := counts[entities.TaskStatusFailed]
+	failedRequestsCount := job.FailedRequests
 	lastSentPercent := -1
 	updateProgress := func(completed, failed, failedRequests int) {
 		progressMu.Lock()
@@ -424,6 +527,7 @@ func (uc *AsyncPositionTrackingUseCase) processJob(jobID string, params *taskPar
 			}
 		}
 	}
+	updateProgress(0, 0, 0)
 
 	// Обработка workItems
 	batchSize := uc.calculateOptimalBatchSize(len(workItems))
@@ -448,40 +552,174 @@ func (uc *AsyncPositionTrackingUseCase) processJob(jobID string, params *taskPar
 		go func() {
 			defer wg.Done()
 			for batch := range batchChan {
-				uc.processWorkItemBatch(batch, job, site, params, updateProgress)
+				if ctx.Err() != nil {
+					return
+				}
+				uc.processWorkItemBatch(ctx, batch, job, site, params, updateProgress, abort)
 			}
 		}()
 	}
 
 	wg.Wait()
 
-	job, _ = uc.jobRepo.GetByID(jobID)
-	if job.FailedTasks == job.TotalTasks {
-		job.Status = entities.TaskStatusFailed
-		job.Error = "All tasks failed"
-	} else {
-		job.Status = entities.TaskStatusCompleted
-		job.CompletedAt = &[]time.Time{time.Now()}[0]
-		job.Error = "" // Очищаем ошибку при успешном завершении
+	if abortErr != nil {
+		// Незапущенные задачи остаются pending и будут выполнены вместе с неудачными через retry-failed
+		current, err := uc.jobRepo.GetByID(jobID)
+		if err != nil {
+			log.Printf("ERROR: Failed to reload job %s: %v", jobID, err)
+			return
+		}
+		log.Printf("Job %s aborted: %v", jobID, abortErr)
+		uc.failJob(current, fmt.Errorf("job aborted: %w", abortErr))
+		return
 	}
-	uc.jobRepo.Update(job)
 
-	// Всегда отправляем финальный статус в Kafka
-	if job.Status == entities.TaskStatusCompleted {
-		if err := uc.kafkaService.SendJobStatus(jobID, string(entities.TaskStatusCompleted), "", 100); err != nil {
-			log.Printf("WARNING: Failed to send job completion status to Kafka: %v", err)
+	if ctx.Err() != nil {
+		// Пауза или отмена: фиксируем итоговые счетчики и отправляем статус
+		if current, err := uc.jobRepo.GetByID(jobID); err == nil && current.LockedBy == workerID &&
+			(current.Status == entities.TaskStatusPaused || current.Status == entities.TaskStatusCancelled) {
+			uc.finishStoppedJob(current)
+			return
 		}
-		if job.Source == entities.GoogleSearch || job.Source == entities.YandexSearch {
-			uc.calculateAndUpdateDynamic(job.SiteID, job.Source)
+
+		// Остановка сервера или потеря аренды: оставшиеся задачи подхватит следующий воркер
+		if err := uc.jobRepo.ReleaseLease(jobID, workerID); err != nil {
+			log.Printf("WARNING: Failed to release lease on job %s: %v", jobID, err)
 		}
-	} else {
-		if err := uc.kafkaService.SendJobStatus(jobID, string(entities.TaskStatusFailed), job.Error); err != nil {
-			log.Printf("WARNING: Failed to send job failure status to Kafka: %v", err)
+		log.Printf("Job %s interrupted, %d of %d tasks done", jobID, completedCount+failedCount, job.TotalTasks)
+		return
+	}
+
+	job, err = uc.jobRepo.GetByID(jobID)
+	if err != nil {
+		log.Printf("ERROR: Failed to reload job %s: %v", jobID, err)
+		return
+	}
+	if job.FailedTasks == job.TotalTasks {
+		uc.failJob(job, fmt.Errorf("All tasks failed"))
+		return
+	}
+
+	// Пауза или отмена после последней задачи не должна перезаписываться завершением
+	completed, err := uc.jobRepo.UpdateStatusIf(jobID, entities.TaskStatusRunning, entities.TaskStatusCompleted)
+	if err != nil {
+		log.Printf("ERROR: Failed to complete job %s: %v", jobID, err)
+		return
+	}
+	if !completed {
+		log.Printf("Job %s was stopped before completion, keeping its status", jobID)
+		return
+	}
+
+	if err := uc.kafkaService.SendJobStatus(jobID, string(entities.TaskStatusCompleted), "", 100); err != nil {
+		log.Printf("WARNING: Failed to send job completion status to Kafka: %v", err)
+	}
+	if job.Source == entities.GoogleSearch || job.Source == entities.YandexSearch {
+		if err := uc.movement.UpdateLatest(job.SiteID, job.Source); err != nil {
+			log.Printf("WARNING: Failed to update position movement of site %d: %v", job.SiteID, err)
+		}
+	}
+}
+
+// failJob завершает running-джоб ошибкой и снимает аренду. Джоб, поставленный на паузу
+// или отмененный за это время, сохраняет свой статус
+func (uc *AsyncPositionTrackingUseCase) failJob(job *entities.TrackingJob, err error) {
+	failed, updateErr := uc.jobRepo.UpdateStatusIf(job.ID, entities.TaskStatusRunning, entities.TaskStatusFailed)
+	if updateErr != nil {
+		log.Printf("ERROR: Failed to fail job %s: %v", job.ID, updateErr)
+		return
+	}
+	if !failed {
+		log.Printf("Job %s was stopped before failing, keeping its status", job.ID)
+		return
+	}
+	if updateErr := uc.jobRepo.UpdateError(job.ID, err.Error()); updateErr != nil {
+		log.Printf("WARNING: Failed to save error of job %s: %v", job.ID, updateErr)
+	}
+	if kafkaErr := uc.kafkaService.SendJobStatus(job.ID, string(entities.TaskStatusFailed), err.Error()); kafkaErr != nil {
+		log.Printf("WARNING: Failed to send job status to Kafka: %v", kafkaErr)
+	}
+}
+
+// keepLease продлевает аренду джоба. Аренда продлевается только у running-джоба,
+// поэтому обработка останавливается и при потере аренды, и при паузе или отмене с другого инстанса.
+func (uc *AsyncPositionTrackingUseCase) keepLease(ctx context.Context, cancel context.CancelFunc, jobID, workerID string, leaseDuration time.Duration) {
+	interval := leaseDuration / 3
+	if interval > jobStatusCheckInterval {
+		interval = jobStatusCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := uc.jobRepo.ExtendLease(jobID, workerID, time.Now().Add(leaseDuration))
+			if err != nil {
+				log.Printf("WARNING: Failed to extend lease on job %s: %v", jobID, err)
+				continue
+			}
+			if !ok {
+				log.Printf("Job %s was stopped or its lease was lost, stopping processing", jobID)
+				cancel()
+				return
+			}
 		}
 	}
 }
 
+// newTaskParams переводит сохраненные параметры джоба в параметры выполнения задач
+func newTaskParams(p entities.TrackingParams) *taskParams {
+	return &taskParams{
+		Device:        p.Device,
+		OS:            p.OS,
+		Ads:           p.Ads,
+		Country:       p.Country,
+		Lang:          p.Lang,
+		Pages:         p.Pages,
+		Subdomains:    p.Subdomains,
+		AccountID:     p.AccountID,
+		Provider:      p.Provider,
+		TBS:           p.TBS,
+		Filter:        p.Filter,
+		Highlights:    p.Highlights,
+		NFPR:          p.NFPR,
+		Loc:           p.Loc,
+		AI:            p.AI,
+		Raw:           p.Raw,
+		GroupBy:       p.GroupBy,
+		Within:        p.Within,
+		LR:            p.LR,
+		Domain:        p.Domain,
+		YandexDomain:  p.YandexDomain,
+		InIndex:       p.InIndex,
+		Strict:        p.Strict,
+		Organic:       p.Organic,
+		Regions:       p.Regions,
+		FilterGroupID: p.FilterGroupID,
+	}
+}
+
+// prepareClients расшифровывает аккаунт джоба и создает клиента провайдера один раз на весь джоб
+func (uc *AsyncPositionTrackingUseCase) prepareClients(site *entities.Site, source string, params *taskParams) error {
+	account, err := uc.accounts.Resolve(site.WorkspaceID, params.AccountID)
+	if err != nil {
+		return err
+	}
+
+	if source == entities.Wordstat {
+		params.Wordstat, err = resolveWordstat(uc.wordstat, account)
+		return err
+	}
+
+	params.SearchProvider, err = resolveSearchProvider(uc.providers, params.Provider, source, params.Pages, account)
+	return err
+}
+
 type workItem struct {
+	TaskID    string
 	Keyword   *entities.Keyword
 	QueryType string
 }
@@ -500,20 +738,6 @@ func (uc *AsyncPositionTrackingUseCase) createWorkItemBatches(items []workItem,
 	return batches
 }
 
-func (uc *AsyncPositionTrackingUseCase) createBatches(tasks []*entities.TrackingTask, batchSize int) [][]*entities.TrackingTask {
-	var batches [][]*entities.TrackingTask
-
-	for i := 0; i < len(tasks); i += batchSize {
-		end := i + batchSize
-		if end > len(tasks) {
-			end = len(tasks)
-		}
-		batches = append(batches, tasks[i:end])
-	}
-
-	return batches
-}
-
 func (uc *AsyncPositionTrackingUseCase) calculateOptimalBatchSize(totalTasks int) int {
 	workerCount := cap(uc.workerPool)
 
@@ -536,349 +760,107 @@ func (uc *AsyncPositionTrackingUseCase) calculateOptimalBatchSize(totalTasks int
 	return optimalBatchSize
 }
 
+// processWorkItemBatch выполняет задачи пачки по очереди, занимая один слот workerPool.
+// Пул общий для всех джобов инстанса, поэтому одновременных запросов к провайдерам не больше workerCount
 func (uc *AsyncPositionTrackingUseCase) processWorkItemBatch(
+	ctx context.Context,
 	batch []workItem,
 	job *entities.TrackingJob,
 	site *entities.Site,
 	params *taskParams,
 	updateProgress func(completed, failed, failedRequests int),
+	abort func(err error),
 ) {
-	uc.workerPool <- struct{}{}
-	defer func() { <-uc.workerPool }()
-
-	if len(batch) == 0 {
+	select {
+	case uc.workerPool <- struct{}{}:
+	case <-ctx.Done():
 		return
 	}
+	defer func() { <-uc.workerPool }()
 
-	var completed, failed, failedRequests int
-	var mu sync.Mutex
-
-	var wg sync.WaitGroup
 	for _, item := range batch {
-		wg.Add(1)
-		go func(workItem workItem) {
-			defer wg.Done()
-
-			err := uc.retryService.ExecuteWithRetry(func() error {
-				return uc.executeWorkItem(workItem, job, site, params)
-			})
+		if ctx.Err() != nil {
+			// Незапущенные задачи остаются pending
+			return
+		}
 
-			mu.Lock()
-			if err != nil {
-				failed++
+		attempts, failedRequests := 0, 0
+		err := uc.retryService.ExecuteWithRetryContext(ctx, func() error {
+			attempts++
+			callErr := uc.executeWorkItem(ctx, item, job, site, params)
+			if callErr != nil && !errors.Is(callErr, context.Canceled) {
+				// Считаем каждый неудачный запрос к провайдеру, включая повторные попытки
 				failedRequests++
-			} else {
-				completed++
 			}
-			mu.Unlock()
-		}(item)
-	}
-	wg.Wait()
-
-	// Обновляем прогресс
-	if completed > 0 || failed > 0 || failedRequests > 0 {
-		updateProgress(completed, failed, failedRequests)
-	}
-}
-
-func (uc *AsyncPositionTrackingUseCase) processBatch(batchTasks []*entities.TrackingTask) {
-	uc.workerPool <- struct{}{}
-	defer func() { <-uc.workerPool }()
+			return callErr
+		})
+		if errors.Is(err, context.Canceled) {
+			// Задача не выполнялась до конца и остается pending
+			if failedRequests > 0 {
+				updateProgress(0, 0, failedRequests)
+			}
+			return
+		}
 
-	if len(batchTasks) == 0 {
-		return
-	}
+		status, errMsg := entities.TaskStatusCompleted, ""
+		if err != nil {
+			status, errMsg = entities.TaskStatusFailed, err.Error()
+		}
+		if finishErr := uc.taskRepo.Finish(item.TaskID, status, errMsg, attempts); finishErr != nil {
+			log.Printf("WARNING: Failed to finish task %s: %v", item.TaskID, finishErr)
+		}
+		if providerErr, ok := domainservices.AsProviderError(err); ok && providerErr.Fatal() {
+			abort(err)
+		}
 
-	siteID := batchTasks[0].SiteID
-	site, err := uc.siteRepo.GetByID(siteID)
-	if err != nil {
-		for _, task := range batchTasks {
-			task.Status = entities.TaskStatusFailed
-			task.Error = fmt.Sprintf("Failed to load site: %v", err)
-			uc.taskRepo.Update(task)
-			uc.updateJobProgress(task.JobID, false)
+		// Задачи пачки идут последовательно, поэтому прогресс обновляется после каждой
+		if err != nil {
+			updateProgress(0, 1, failedRequests)
+		} else {
+			updateProgress(1, 0, failedRequests)
 		}
-		return
 	}
+}
 
-	keywordIDs := make([]int, len(batchTasks))
-	for i, task := range batchTasks {
-		keywordIDs[i] = task.KeywordID
+func (uc *AsyncPositionTrackingUseCase) executeWorkItem(ctx context.Context, item workItem, job *entities.TrackingJob, site *entities.Site, params *taskParams) error {
+	switch job.Source {
+	case entities.GoogleSearch:
+		return uc.executeGoogleWorkItem(ctx, item, job, site, params)
+	case entities.YandexSearch:
+		return uc.executeYandexWorkItem(ctx, item, job, site, params)
+	case entities.Wordstat:
+		return uc.executeWordstatWorkItem(ctx, item, job, site, params)
+	default:
+		return fmt.Errorf("unknown source: %s", job.Source)
 	}
+}
 
-	keywords, err := uc.keywordRepo.GetByIDs(keywordIDs)
+func (uc *AsyncPositionTrackingUseCase) executeGoogleWorkItem(ctx context.Context, item workItem, job *entities.TrackingJob, site *entities.Site, params *taskParams) error {
+	provider := params.SearchProvider
+	req := uc.buildSearchRequest(item.Keyword.Value, entities.GoogleSearch, params, provider.Capabilities())
+	// Параллелизм и частоту запросов ограничивает лимитер аккаунта провайдера; ctx прерывает ожидание и запросы при остановке джоба
+	searchResult, err := provider.FindSitePosition(
+		ctx, req, site.Domain, competitorDomains(params.Competitors), entities.GoogleSearch, params.Pages, params.Subdomains,
+	)
+	recordSearchUsage(uc.usageRepo, job.ID, site.ID, entities.GoogleSearch, provider, searchResult)
+	pages, err := searchedPages(ctx, searchResult, err, params.Pages)
 	if err != nil {
-		for _, task := range batchTasks {
-			task.Status = entities.TaskStatusFailed
-			task.Error = fmt.Sprintf("Failed to load keywords: %v", err)
-			uc.taskRepo.Update(task)
-			uc.updateJobProgress(task.JobID, false)
-		}
-		return
-	}
-
-	keywordMap := make(map[int]*entities.Keyword)
-	for _, keyword := range keywords {
-		keywordMap[keyword.ID] = keyword
-	}
-
-	// Параллельная обработка задач внутри батча
-	var taskWg sync.WaitGroup
-	for _, task := range batchTasks {
-		taskWg.Add(1)
-		go func(t *entities.TrackingTask) {
-			defer taskWg.Done()
-			keyword, exists := keywordMap[t.KeywordID]
-			if !exists {
-				t.Status = entities.TaskStatusFailed
-				t.Error = "Keyword not found"
-				uc.taskRepo.Update(t)
-				uc.updateJobProgress(t.JobID, false)
-				return
-			}
-
-			uc.processTaskWithData(t, site, keyword)
-		}(task)
-	}
-	taskWg.Wait()
-}
-
-func (uc *AsyncPositionTrackingUseCase) processTaskWithData(task *entities.TrackingTask, site *entities.Site, keyword *entities.Keyword) {
-	if task.Status == entities.TaskStatusCompleted || task.Status == entities.TaskStatusFailed {
-		return
-	}
-
-	task.Status = entities.TaskStatusRunning
-	uc.taskRepo.UpdateStatus(task.ID, entities.TaskStatusRunning)
-
-	err := uc.retryService.ExecuteWithRetry(func() error {
-		return uc.executeTaskWithData(task, site, keyword)
-	})
-
-	if err != nil {
-		task.Status = entities.TaskStatusFailed
-		task.Error = err.Error()
-		uc.taskRepo.Update(task)
-		uc.kafkaService.SendTaskStatus(&services.TaskStatusMessage{
-			TaskID:    task.ID,
-			JobID:     task.JobID,
-			Status:    string(entities.TaskStatusFailed),
-			Timestamp: time.Now(),
-			Error:     err.Error(),
-		})
-		uc.updateJobProgress(task.JobID, false)
-		return
-	}
-
-	task.Status = entities.TaskStatusCompleted
-	task.CompletedAt = &[]time.Time{time.Now()}[0]
-	uc.taskRepo.Update(task)
-
-	uc.kafkaService.SendTaskStatus(&services.TaskStatusMessage{
-		TaskID:    task.ID,
-		JobID:     task.JobID,
-		Status:    string(entities.TaskStatusCompleted),
-		Timestamp: time.Now(),
-	})
-
-	uc.updateJobProgress(task.JobID, true)
-}
-
-func (uc *AsyncPositionTrackingUseCase) processTask(task *entities.TrackingTask) {
-	task.Status = entities.TaskStatusRunning
-	uc.taskRepo.UpdateStatus(task.ID, entities.TaskStatusRunning)
-
-	err := uc.retryService.ExecuteWithRetry(func() error {
-		return uc.executeTask(task)
-	})
-
-	if err != nil {
-		task.Status = entities.TaskStatusFailed
-		task.Error = err.Error()
-		uc.taskRepo.Update(task)
-		uc.kafkaService.SendTaskStatus(&services.TaskStatusMessage{
-			TaskID:    task.ID,
-			JobID:     task.JobID,
-			Status:    string(entities.TaskStatusFailed),
-			Timestamp: time.Now(),
-			Error:     err.Error(),
-		})
-		uc.updateJobProgress(task.JobID, false)
-		return
-	}
-
-	task.Status = entities.TaskStatusCompleted
-	task.CompletedAt = &[]time.Time{time.Now()}[0]
-	uc.taskRepo.Update(task)
-
-	uc.kafkaService.SendTaskStatus(&services.TaskStatusMessage{
-		TaskID:    task.ID,
-		JobID:     task.JobID,
-		Status:    string(entities.TaskStatusCompleted),
-		Timestamp: time.Now(),
-	})
-
-	uc.updateJobProgress(task.JobID, true)
-}
-
-func (uc *AsyncPositionTrackingUseCase) executeWorkItem(item workItem, job *entities.TrackingJob, site *entities.Site, params *taskParams) error {
-	switch job.Source {
-	case entities.GoogleSearch:
-		return uc.executeGoogleWorkItem(item, job, site, params)
-	case entities.YandexSearch:
-		return uc.executeYandexWorkItem(item, job, site, params)
-	case entities.Wordstat:
-		return uc.executeWordstatWorkItem(item, job, site, params)
-	default:
-		return fmt.Errorf("unknown source: %s", job.Source)
-	}
-}
-
-func (uc *AsyncPositionTrackingUseCase) executeTaskWithData(task *entities.TrackingTask, site *entities.Site, keyword *entities.Keyword) error {
-	switch task.Source {
-	case entities.GoogleSearch:
-		return uc.executeGoogleTaskWithData(task, site, keyword)
-	case entities.YandexSearch:
-		return uc.executeYandexTaskWithData(task, site, keyword)
-	case entities.Wordstat:
-		return uc.executeWordstatTaskWithData(task, site, keyword)
-	default:
-		return fmt.Errorf("unknown source: %s", task.Source)
-	}
-}
-
-func (uc *AsyncPositionTrackingUseCase) executeTask(task *entities.TrackingTask) error {
-	switch task.Source {
-	case entities.GoogleSearch:
-		return uc.executeGoogleTask(task)
-	case entities.YandexSearch:
-		return uc.executeYandexTask(task)
-	case entities.Wordstat:
-		return uc.executeWordstatTask(task)
-	default:
-		return fmt.Errorf("unknown source: %s", task.Source)
-	}
-}
-
-func (uc *AsyncPositionTrackingUseCase) executeGoogleTaskWithData(task *entities.TrackingTask, site *entities.Site, keyword *entities.Keyword) error {
-
-	var xmlRiverService *services.XMLRiverService
-	var baseURL string
-	if task.XMLUserID != "" && task.XMLAPIKey != "" && task.XMLBaseURL != "" {
-		var err error
-		softID := uc.getSoftIDByBaseURL(task.XMLBaseURL)
-		xmlRiverService, err = services.NewXMLRiverService(task.XMLBaseURL, task.XMLUserID, task.XMLAPIKey, softID)
-		if err != nil {
-			return err
-		}
-		baseURL = task.XMLBaseURL
-	} else {
-		xmlRiverService = uc.xmlStock
-		baseURL = uc.xmlStock.GetBaseURL()
-	}
-
-	// Получаем семафор для этого xmlriver и ограничиваем параллелизм
-	sem := uc.getXMLRiverSemaphore(baseURL)
-	sem <- struct{}{}        // Захватываем семафор
-	defer func() { <-sem }() // Освобождаем семафор
-
-	// Для Google используем organic=false и groupBy=0
-	position, url, title, err := xmlRiverService.FindSitePositionWithSubdomains(
-		keyword.Value, site.Domain, entities.GoogleSearch, task.Pages,
-		task.Device, task.OS, task.Ads, task.Country, task.Lang, task.Subdomains, task.LR, task.Domain,
-		false, 0,
-	)
-	if err != nil {
-		return err
-	}
-
-	positionEntity := &entities.Position{
-		KeywordID:     keyword.ID,
-		SiteID:        site.ID,
-		Rank:          position,
-		URL:           url,
-		Title:         title,
-		Source:        entities.GoogleSearch,
-		Device:        task.Device,
-		OS:            task.OS,
-		Ads:           task.Ads,
-		Country:       task.Country,
-		Lang:          task.Lang,
-		Pages:         task.Pages,
-		Date:          time.Now(),
-		FilterGroupID: task.FilterGroupID,
-	}
-
-	if err := uc.positionRepo.CreateOrUpdateToday(positionEntity); err != nil {
-		return err
-	}
-
-	result := &entities.TrackingResult{
-		TaskID:    task.ID,
-		JobID:     task.JobID,
-		KeywordID: keyword.ID,
-		SiteID:    site.ID,
-		Source:    entities.GoogleSearch,
-		Rank:      position,
-		URL:       url,
-		Title:     title,
-		Device:    task.Device,
-		OS:        task.OS,
-		Ads:       task.Ads,
-		Country:   task.Country,
-		Lang:      task.Lang,
-		Pages:     task.Pages,
-		Date:      time.Now(),
-		Success:   true,
-	}
-
-	return uc.resultRepo.Create(result)
-}
-
-func (uc *AsyncPositionTrackingUseCase) executeGoogleWorkItem(item workItem, job *entities.TrackingJob, site *entities.Site, params *taskParams) error {
-	var xmlRiverService *services.XMLRiverService
-	var baseURL string
-	if params.XMLUserID != "" && params.XMLAPIKey != "" && params.XMLBaseURL != "" {
-		var err error
-		softID := uc.getSoftIDByBaseURL(params.XMLBaseURL)
-		xmlRiverService, err = services.NewXMLRiverService(params.XMLBaseURL, params.XMLUserID, params.XMLAPIKey, softID)
-		if err != nil {
-			return err
-		}
-		baseURL = params.XMLBaseURL
-	} else {
-		xmlRiverService = uc.xmlStock
-		baseURL = uc.xmlStock.GetBaseURL()
-	}
-
-	// Получаем семафор для этого xmlriver и ограничиваем параллелизм
-	sem := uc.getXMLRiverSemaphore(baseURL)
-	sem <- struct{}{}        // Захватываем семафор
-	defer func() { <-sem }() // Освобождаем семафор
-
-	// Для Google используем organic=false и groupBy=0
-	position, url, title, err := xmlRiverService.FindSitePositionWithSubdomains(
-		item.Keyword.Value, site.Domain, entities.GoogleSearch, params.Pages,
-		params.Device, params.OS, params.Ads, params.Country, params.Lang, params.Subdomains, params.LR, params.Domain,
-		false, 0,
-	)
-	if err != nil {
-		return err
+		return err
 	}
 
 	positionEntity := &entities.Position{
 		KeywordID:     item.Keyword.ID,
 		SiteID:        site.ID,
-		Rank:          position,
-		URL:           url,
-		Title:         title,
+		Rank:          searchResult.Position,
+		URL:           searchResult.URL,
+		Title:         searchResult.Title,
 		Source:        entities.GoogleSearch,
 		Device:        params.Device,
 		OS:            params.OS,
 		Ads:           params.Ads,
 		Country:       params.Country,
 		Lang:          params.Lang,
-		Pages:         params.Pages,
+		Pages:         pages,
 		Date:          time.Now(),
 		FilterGroupID: params.FilterGroupID,
 	}
@@ -887,21 +869,24 @@ func (uc *AsyncPositionTrackingUseCase) executeGoogleWorkItem(item workItem, job
 		return err
 	}
 
+	saveSerpSnapshot(uc.snapshotRepo, positionEntity, searchResult.Items)
+	saveCompetitorPositions(uc.positionRepo, positionEntity, params.Competitors, searchResult.Competitors, pages < params.Pages)
+
 	result := &entities.TrackingResult{
-		TaskID:    "", // Больше не используем taskID
+		TaskID:    item.TaskID,
 		JobID:     job.ID,
 		KeywordID: item.Keyword.ID,
 		SiteID:    site.ID,
 		Source:    entities.GoogleSearch,
-		Rank:      position,
-		URL:       url,
-		Title:     title,
+		Rank:      searchResult.Position,
+		URL:       searchResult.URL,
+		Title:     searchResult.Title,
 		Device:    params.Device,
 		OS:        params.OS,
 		Ads:       params.Ads,
 		Country:   params.Country,
 		Lang:      params.Lang,
-		Pages:     params.Pages,
+		Pages:     pages,
 		Date:      time.Now(),
 		Success:   true,
 	}
@@ -909,40 +894,15 @@ func (uc *AsyncPositionTrackingUseCase) executeGoogleWorkItem(item workItem, job
 	return uc.resultRepo.Create(result)
 }
 
-func (uc *AsyncPositionTrackingUseCase) executeYandexWorkItem(item workItem, job *entities.TrackingJob, site *entities.Site, params *taskParams) error {
-	var xmlRiverService *services.XMLRiverService
-	var baseURL string
-	if params.XMLUserID != "" && params.XMLAPIKey != "" && params.XMLBaseURL != "" {
-		var err error
-		softID := uc.getSoftIDByBaseURL(params.XMLBaseURL)
-		xmlRiverService, err = services.NewXMLRiverService(params.XMLBaseURL, params.XMLUserID, params.XMLAPIKey, softID)
-		if err != nil {
-			return err
-		}
-		baseURL = params.XMLBaseURL
-	} else {
-		xmlRiverService = uc.xmlStock
-		baseURL = uc.xmlStock.GetBaseURL()
-	}
-
-	// Получаем семафор для этого xmlriver и ограничиваем параллелизм
-	sem := uc.getXMLRiverSemaphore(baseURL)
-	sem <- struct{}{}        // Захватываем семафор
-	defer func() { <-sem }() // Освобождаем семафор
-
-	// Если organic=false, используем groupby=pages*10 для получения всех результатов сразу
-	var groupBy int
-	if !params.Organic && params.Pages > 0 {
-		groupBy = params.Pages * 10
-	} else {
-		groupBy = params.GroupBy
-	}
-
-	position, url, title, err := xmlRiverService.FindSitePositionWithSubdomains(
-		item.Keyword.Value, site.Domain, entities.YandexSearch, params.Pages,
-		params.Device, params.OS, params.Ads, params.Country, params.Lang, params.Subdomains, params.LR, 0,
-		params.Organic, groupBy,
+func (uc *AsyncPositionTrackingUseCase) executeYandexWorkItem(ctx context.Context, item workItem, job *entities.TrackingJob, site *entities.Site, params *taskParams) error {
+	provider := params.SearchProvider
+	req := uc.buildSearchRequest(item.Keyword.Value, entities.YandexSearch, params, provider.Capabilities())
+	// Параллелизм и частоту запросов ограничивает лимитер аккаунта провайдера; ctx прерывает ожидание и запросы при остановке джоба
+	searchResult, err := provider.FindSitePosition(
+		ctx, req, site.Domain, competitorDomains(params.Competitors), entities.YandexSearch, params.Pages, params.Subdomains,
 	)
+	recordSearchUsage(uc.usageRepo, job.ID, site.ID, entities.YandexSearch, provider, searchResult)
+	pages, err := searchedPages(ctx, searchResult, err, params.Pages)
 	if err != nil {
 		return err
 	}
@@ -950,16 +910,16 @@ func (uc *AsyncPositionTrackingUseCase) executeYandexWorkItem(item workItem, job
 	positionEntity := &entities.Position{
 		KeywordID:     item.Keyword.ID,
 		SiteID:        site.ID,
-		Rank:          position,
-		URL:           url,
-		Title:         title,
+		Rank:          searchResult.Position,
+		URL:           searchResult.URL,
+		Title:         searchResult.Title,
 		Source:        entities.YandexSearch,
 		Device:        params.Device,
 		OS:            params.OS,
 		Ads:           params.Ads,
 		Country:       params.Country,
 		Lang:          params.Lang,
-		Pages:         params.Pages,
+		Pages:         pages,
 		Date:          time.Now(),
 		FilterGroupID: params.FilterGroupID,
 	}
@@ -968,21 +928,24 @@ func (uc *AsyncPositionTrackingUseCase) executeYandexWorkItem(item workItem, job
 		return err
 	}
 
+	saveSerpSnapshot(uc.snapshotRepo, positionEntity, searchResult.Items)
+	saveCompetitorPositions(uc.positionRepo, positionEntity, params.Competitors, searchResult.Competitors, pages < params.Pages)
+
 	result := &entities.TrackingResult{
-		TaskID:    "", // Больше не используем taskID
+		TaskID:    item.TaskID,
 		JobID:     job.ID,
 		KeywordID: item.Keyword.ID,
 		SiteID:    site.ID,
 		Source:    entities.YandexSearch,
-		Rank:      position,
-		URL:       url,
-		Title:     title,
+		Rank:      searchResult.Position,
+		URL:       searchResult.URL,
+		Title:     searchResult.Title,
 		Device:    params.Device,
 		OS:        params.OS,
 		Ads:       params.Ads,
 		Country:   params.Country,
 		Lang:      params.Lang,
-		Pages:     params.Pages,
+		Pages:     pages,
 		Date:      time.Now(),
 		Success:   true,
 	}
@@ -990,28 +953,33 @@ func (uc *AsyncPositionTrackingUseCase) executeYandexWorkItem(item workItem, job
 	return uc.resultRepo.Create(result)
 }
 
-func (uc *AsyncPositionTrackingUseCase) executeWordstatWorkItem(item workItem, job *entities.TrackingJob, site *entities.Site, params *taskParams) error {
-	var wordstatService *services.WordstatService
-	if params.XMLUserID != "" && params.XMLAPIKey != "" && params.XMLBaseURL != "" {
-		var err error
-		wordstatService, err = services.NewWordstatService(params.XMLBaseURL, params.XMLUserID, params.XMLAPIKey)
-		if err != nil {
-			return err
-		}
-	} else {
-		wordstatService = uc.wordstat
+// searchedPages глубина, на которую проверен сайт. Если джоб остановлен, когда часть страниц выдачи уже
+// получена и оплачена, а сайт на них найден, позиция сохраняется по этим страницам: задача, оставленная pending,
+// после возобновления оплатила бы их повторно. Не найденный сайт может оказаться на следующих страницах, поэтому
+// такая задача возвращает ошибку и остается pending. Постраничный поиск тратит один запрос на страницу
+func searchedPages(ctx context.Context, result *domainservices.SitePositionResult, err error, pages int) (int, error) {
+	if err == nil {
+		return pages, nil
+	}
+	if ctx.Err() != nil && result != nil && result.Requests > 0 && result.Position > 0 {
+		return min(result.Requests, pages), nil
 	}
+	return 0, err
+}
 
+func (uc *AsyncPositionTrackingUseCase) executeWordstatWorkItem(ctx context.Context, item workItem, job *entities.TrackingJob, site *entities.Site, params *taskParams) error {
+	wordstatService := params.Wordstat
 	queryType := item.QueryType
 	if queryType == "" {
 		queryType = "default"
 	}
 
 	modifiedQuery := uc.modifyWordstatQuery(item.Keyword.Value, queryType)
-	frequency, err := wordstatService.GetKeywordFrequency(modifiedQuery, item.Keyword.Value, params.Regions)
+	frequency, err := wordstatService.GetKeywordFrequency(ctx, modifiedQuery, item.Keyword.Value, params.Regions)
 	if err != nil {
 		return err
 	}
+	recordWordstatUsage(uc.usageRepo, job.ID, item.Keyword.SiteID, wordstatService)
 
 	positionEntity := &entities.Position{
 		KeywordID:         item.Keyword.ID,
@@ -1035,7 +1003,7 @@ func (uc *AsyncPositionTrackingUseCase) executeWordstatWorkItem(item workItem, j
 	}
 
 	result := &entities.TrackingResult{
-		TaskID:    "", // Больше не используем taskID
+		TaskID:    item.TaskID,
 		JobID:     job.ID,
 		KeywordID: item.Keyword.ID,
 		SiteID:    item.Keyword.SiteID,
@@ -1056,350 +1024,44 @@ func (uc *AsyncPositionTrackingUseCase) executeWordstatWorkItem(item workItem, j
 	return uc.resultRepo.Create(result)
 }
 
-func (uc *AsyncPositionTrackingUseCase) getSoftIDByBaseURL(baseURL string) string {
-	baseURLLower := strings.ToLower(baseURL)
-	if strings.Contains(baseURLLower, "xmlriver") {
-		return uc.xmlRiverSoftID
-	}
-	if strings.Contains(baseURLLower, "xmlstock") {
-		return uc.xmlStockSoftID
-	}
-	return uc.xmlRiverSoftID
-}
-
-// getXMLRiverSemaphore возвращает семафор для конкретного baseURL
-// Каждый xmlriver имеет свой семафор с лимитом maxConcurrentPerXMLRiver одновременных запросов
-func (uc *AsyncPositionTrackingUseCase) getXMLRiverSemaphore(baseURL string) chan struct{} {
-	uc.xmlRiverSemMu.Lock()
-	defer uc.xmlRiverSemMu.Unlock()
-
-	sem, exists := uc.xmlRiverSemaphores[baseURL]
-	if !exists {
-		// Создаем новый семафор для этого baseURL
-		sem = make(chan struct{}, uc.maxConcurrentPerXMLRiver)
-		uc.xmlRiverSemaphores[baseURL] = sem
-	}
-	return sem
-}
-
-func (uc *AsyncPositionTrackingUseCase) executeGoogleTask(task *entities.TrackingTask) error {
-	site, err := uc.siteRepo.GetByID(task.SiteID)
-	if err != nil {
-		return err
-	}
-
-	keyword, err := uc.keywordRepo.GetByID(task.KeywordID)
-	if err != nil {
-		return err
-	}
-
-	var xmlRiverService *services.XMLRiverService
-	var baseURL string
-	if task.XMLUserID != "" && task.XMLAPIKey != "" && task.XMLBaseURL != "" {
-		var err error
-		softID := uc.getSoftIDByBaseURL(task.XMLBaseURL)
-		xmlRiverService, err = services.NewXMLRiverService(task.XMLBaseURL, task.XMLUserID, task.XMLAPIKey, softID)
-		if err != nil {
-			return err
+// buildSearchRequest переносит параметры джоба в запрос к провайдеру с учетом источника
+func (uc *AsyncPositionTrackingUseCase) buildSearchRequest(query, source string, params *taskParams, capabilities domainservices.ProviderCapabilities) domainservices.SearchRequest {
+	req := domainservices.SearchRequest{
+		Query:      query,
+		Device:     params.Device,
+		OS:         params.OS,
+		Ads:        params.Ads,
+		Country:    params.Country,
+		Lang:       params.Lang,
+		LR:         params.LR,
+		Filter:     params.Filter,
+		Highlights: params.Highlights,
+		Raw:        params.Raw,
+		InIndex:    params.InIndex,
+		Strict:     params.Strict,
+	}
+
+	if source == entities.YandexSearch {
+		req.Organic = params.Organic
+		req.Within = params.Within
+		req.YandexDomain = params.YandexDomain
+		// Если organic=false, используем groupby=pages*10 для получения всех результатов сразу
+		if !params.Organic && params.Pages > 0 && capabilities.GroupBy {
+			req.GroupBy = params.Pages * 10
+		} else {
+			req.GroupBy = params.GroupBy
 		}
-		baseURL = task.XMLBaseURL
-	} else {
-		xmlRiverService = uc.xmlStock
-		baseURL = uc.xmlStock.GetBaseURL()
+		return req
 	}
 
-	// Получаем семафор для этого xmlriver и ограничиваем параллелизм
-	sem := uc.getXMLRiverSemaphore(baseURL)
-	sem <- struct{}{}        // Захватываем семафор
-	defer func() { <-sem }() // Освобождаем семафор
-
 	// Для Google используем organic=false и groupBy=0
-	position, url, title, err := xmlRiverService.FindSitePositionWithSubdomains(
-		keyword.Value, site.Domain, entities.GoogleSearch, task.Pages,
-		task.Device, task.OS, task.Ads, task.Country, task.Lang, task.Subdomains, task.LR, task.Domain,
-		false, 0,
-	)
-	if err != nil {
-		return err
-	}
-
-	positionEntity := &entities.Position{
-		KeywordID: keyword.ID,
-		SiteID:    site.ID,
-		Rank:      position,
-		URL:       url,
-		Title:     title,
-		Source:    entities.GoogleSearch,
-		Device:    task.Device,
-		OS:        task.OS,
-		Ads:       task.Ads,
-		Country:   task.Country,
-		Lang:      task.Lang,
-		Pages:     task.Pages,
-		Date:      time.Now(),
-	}
-
-	if err := uc.positionRepo.CreateOrUpdateToday(positionEntity); err != nil {
-		return err
-	}
-
-	result := &entities.TrackingResult{
-		TaskID:    task.ID,
-		JobID:     task.JobID,
-		KeywordID: keyword.ID,
-		SiteID:    site.ID,
-		Source:    entities.GoogleSearch,
-		Rank:      position,
-		URL:       url,
-		Title:     title,
-		Device:    task.Device,
-		OS:        task.OS,
-		Ads:       task.Ads,
-		Country:   task.Country,
-		Lang:      task.Lang,
-		Pages:     task.Pages,
-		Date:      time.Now(),
-		Success:   true,
-	}
-
-	return uc.resultRepo.Create(result)
-}
-
-func (uc *AsyncPositionTrackingUseCase) executeYandexTaskWithData(task *entities.TrackingTask, site *entities.Site, keyword *entities.Keyword) error {
-	var xmlRiverService *services.XMLRiverService
-	var baseURL string
-	if task.XMLUserID != "" && task.XMLAPIKey != "" && task.XMLBaseURL != "" {
-		var err error
-		softID := uc.getSoftIDByBaseURL(task.XMLBaseURL)
-		xmlRiverService, err = services.NewXMLRiverService(task.XMLBaseURL, task.XMLUserID, task.XMLAPIKey, softID)
-		if err != nil {
-			return err
-		}
-		baseURL = task.XMLBaseURL
-	} else {
-		xmlRiverService = uc.xmlStock
-		baseURL = uc.xmlStock.GetBaseURL()
-	}
-
-	// Получаем семафор для этого xmlriver и ограничиваем параллелизм
-	sem := uc.getXMLRiverSemaphore(baseURL)
-	sem <- struct{}{}        // Захватываем семафор
-	defer func() { <-sem }() // Освобождаем семафор
-
-	// Если organic=false, используем groupby=pages*10 для получения всех результатов сразу
-	var groupBy int
-	if !task.Organic && task.Pages > 0 {
-		groupBy = task.Pages * 10
-	} else {
-		groupBy = task.GroupBy
-	}
-
-	position, url, title, err := xmlRiverService.FindSitePositionWithSubdomains(
-		keyword.Value, site.Domain, entities.YandexSearch, task.Pages,
-		task.Device, task.OS, task.Ads, task.Country, task.Lang, task.Subdomains, task.LR, 0,
-		task.Organic, groupBy,
-	)
-	if err != nil {
-		return err
-	}
-
-	positionEntity := &entities.Position{
-		KeywordID:     keyword.ID,
-		SiteID:        site.ID,
-		Rank:          position,
-		URL:           url,
-		Title:         title,
-		Source:        entities.YandexSearch,
-		Device:        task.Device,
-		OS:            task.OS,
-		Ads:           task.Ads,
-		Country:       task.Country,
-		Lang:          task.Lang,
-		Pages:         task.Pages,
-		Date:          time.Now(),
-		FilterGroupID: task.FilterGroupID,
-	}
-
-	if err := uc.positionRepo.CreateOrUpdateToday(positionEntity); err != nil {
-		return err
-	}
-
-	result := &entities.TrackingResult{
-		TaskID:    task.ID,
-		JobID:     task.JobID,
-		KeywordID: keyword.ID,
-		SiteID:    site.ID,
-		Source:    entities.YandexSearch,
-		Rank:      position,
-		URL:       url,
-		Title:     title,
-		Device:    task.Device,
-		OS:        task.OS,
-		Ads:       task.Ads,
-		Country:   task.Country,
-		Lang:      task.Lang,
-		Pages:     task.Pages,
-		Date:      time.Now(),
-		Success:   true,
-	}
-
-	return uc.resultRepo.Create(result)
-}
-
-func (uc *AsyncPositionTrackingUseCase) executeWordstatTaskWithData(task *entities.TrackingTask, site *entities.Site, keyword *entities.Keyword) error {
-	var wordstatService *services.WordstatService
-	if task.XMLUserID != "" && task.XMLAPIKey != "" && task.XMLBaseURL != "" {
-		var err error
-		wordstatService, err = services.NewWordstatService(task.XMLBaseURL, task.XMLUserID, task.XMLAPIKey)
-		if err != nil {
-			return err
-		}
-	} else {
-		wordstatService = uc.wordstat
-	}
-
-	queryType := task.WordstatQueryType
-	if queryType == "" {
-		queryType = "default"
-	}
-
-	modifiedQuery := uc.modifyWordstatQuery(keyword.Value, queryType)
-	frequency, err := wordstatService.GetKeywordFrequency(modifiedQuery, keyword.Value, task.Regions)
-	if err != nil {
-		return err
-	}
+	req.Domain = params.Domain
+	req.TBS = params.TBS
+	req.NFPR = params.NFPR
+	req.Loc = params.Loc
+	req.AI = params.AI
 
-	positionEntity := &entities.Position{
-		KeywordID:         keyword.ID,
-		SiteID:            keyword.SiteID,
-		Rank:              frequency,
-		URL:               "",
-		Title:             "",
-		Source:            entities.Wordstat,
-		Device:            "",
-		OS:                "",
-		Ads:               false,
-		Country:           "",
-		Lang:              "",
-		Pages:             0,
-		Date:              time.Now(),
-		WordstatQueryType: queryType,
-	}
-
-	if err := uc.positionRepo.CreateOrUpdateToday(positionEntity); err != nil {
-		return err
-	}
-
-	result := &entities.TrackingResult{
-		TaskID:    task.ID,
-		JobID:     task.JobID,
-		KeywordID: keyword.ID,
-		SiteID:    keyword.SiteID,
-		Source:    entities.Wordstat,
-		Rank:      frequency,
-		URL:       "",
-		Title:     "",
-		Device:    "",
-		OS:        "",
-		Ads:       false,
-		Country:   "",
-		Lang:      "",
-		Pages:     0,
-		Date:      time.Now(),
-		Success:   true,
-	}
-
-	return uc.resultRepo.Create(result)
-}
-
-func (uc *AsyncPositionTrackingUseCase) executeYandexTask(task *entities.TrackingTask) error {
-	site, err := uc.siteRepo.GetByID(task.SiteID)
-	if err != nil {
-		return err
-	}
-
-	keyword, err := uc.keywordRepo.GetByID(task.KeywordID)
-	if err != nil {
-		return err
-	}
-
-	var xmlRiverService *services.XMLRiverService
-	var baseURL string
-	if task.XMLUserID != "" && task.XMLAPIKey != "" && task.XMLBaseURL != "" {
-		var err error
-		softID := uc.getSoftIDByBaseURL(task.XMLBaseURL)
-		xmlRiverService, err = services.NewXMLRiverService(task.XMLBaseURL, task.XMLUserID, task.XMLAPIKey, softID)
-		if err != nil {
-			return err
-		}
-		baseURL = task.XMLBaseURL
-	} else {
-		xmlRiverService = uc.xmlStock
-		baseURL = uc.xmlStock.GetBaseURL()
-	}
-
-	// Получаем семафор для этого xmlriver и ограничиваем параллелизм
-	sem := uc.getXMLRiverSemaphore(baseURL)
-	sem <- struct{}{}        // Захватываем семафор
-	defer func() { <-sem }() // Освобождаем семафор
-
-	// Если organic=false, используем groupby=pages*10 для получения всех результатов сразу
-	var groupBy int
-	if !task.Organic && task.Pages > 0 {
-		groupBy = task.Pages * 10
-	} else {
-		groupBy = task.GroupBy
-	}
-
-	position, url, title, err := xmlRiverService.FindSitePositionWithSubdomains(
-		keyword.Value, site.Domain, entities.YandexSearch, task.Pages,
-		task.Device, task.OS, task.Ads, task.Country, task.Lang, task.Subdomains, task.LR, 0,
-		task.Organic, groupBy,
-	)
-	if err != nil {
-		return err
-	}
-
-	positionEntity := &entities.Position{
-		KeywordID: keyword.ID,
-		SiteID:    site.ID,
-		Rank:      position,
-		URL:       url,
-		Title:     title,
-		Source:    entities.YandexSearch,
-		Device:    task.Device,
-		OS:        task.OS,
-		Ads:       task.Ads,
-		Country:   task.Country,
-		Lang:      task.Lang,
-		Pages:     task.Pages,
-		Date:      time.Now(),
-	}
-
-	if err := uc.positionRepo.CreateOrUpdateToday(positionEntity); err != nil {
-		return err
-	}
-
-	result := &entities.TrackingResult{
-		TaskID:    task.ID,
-		JobID:     task.JobID,
-		KeywordID: keyword.ID,
-		SiteID:    site.ID,
-		Source:    entities.YandexSearch,
-		Rank:      position,
-		URL:       url,
-		Title:     title,
-		Device:    task.Device,
-		OS:        task.OS,
-		Ads:       task.Ads,
-		Country:   task.Country,
-		Lang:      task.Lang,
-		Pages:     task.Pages,
-		Date:      time.Now(),
-		Success:   true,
-	}
-
-	return uc.resultRepo.Create(result)
+	return req
 }
 
 func (uc *AsyncPositionTrackingUseCase) modifyWordstatQuery(query string, queryType string) string {
@@ -1426,182 +1088,3 @@ func (uc *AsyncPositionTrackingUseCase) modifyWordstatQuery(query string, queryT
 		return query
 	}
 }
-
-func (uc *AsyncPositionTrackingUseCase) executeWordstatTask(task *entities.TrackingTask) error {
-	keyword, err := uc.keywordRepo.GetByID(task.KeywordID)
-	if err != nil {
-		return err
-	}
-
-	var wordstatService *services.WordstatService
-	if task.XMLUserID != "" && task.XMLAPIKey != "" && task.XMLBaseURL != "" {
-		var err error
-		wordstatService, err = services.NewWordstatService(task.XMLBaseURL, task.XMLUserID, task.XMLAPIKey)
-		if err != nil {
-			return err
-		}
-	} else {
-		wordstatService = uc.wordstat
-	}
-
-	queryType := task.WordstatQueryType
-	if queryType == "" {
-		queryType = "default"
-	}
-
-	modifiedQuery := uc.modifyWordstatQuery(keyword.Value, queryType)
-	frequency, err := wordstatService.GetKeywordFrequency(modifiedQuery, keyword.Value, task.Regions)
-	if err != nil {
-		return err
-	}
-
-	positionEntity := &entities.Position{
-		KeywordID:         keyword.ID,
-		SiteID:            keyword.SiteID,
-		Rank:              frequency,
-		URL:               "",
-		Title:             "",
-		Source:            entities.Wordstat,
-		Device:            "",
-		OS:                "",
-		Ads:               false,
-		Country:           "",
-		Lang:              "",
-		Pages:             0,
-		Date:              time.Now(),
-		WordstatQueryType: queryType,
-	}
-
-	if err := uc.positionRepo.CreateOrUpdateToday(positionEntity); err != nil {
-		return err
-	}
-
-	result := &entities.TrackingResult{
-		TaskID:    task.ID,
-		JobID:     task.JobID,
-		KeywordID: keyword.ID,
-		SiteID:    keyword.SiteID,
-		Source:    entities.Wordstat,
-		Rank:      frequency,
-		URL:       "",
-		Title:     "",
-		Device:    "",
-		OS:        "",
-		Ads:       false,
-		Country:   "",
-		Lang:      "",
-		Pages:     0,
-		Date:      time.Now(),
-		Success:   true,
-	}
-
-	return uc.resultRepo.Create(result)
-}
-
-func (uc *AsyncPositionTrackingUseCase) updateJobProgress(jobID string, success bool) {
-	job, err := uc.jobRepo.GetByID(jobID)
-	if err != nil {
-		return
-	}
-
-	if success {
-		job.CompletedTasks++
-	} else {
-		job.FailedTasks++
-	}
-
-	uc.jobRepo.UpdateProgress(jobID, job.CompletedTasks, job.FailedTasks)
-
-	if job.TotalTasks > 0 {
-		progress := (job.CompletedTasks + job.FailedTasks) * 100 / job.TotalTasks
-		uc.kafkaService.SendJobStatus(jobID, string(entities.TaskStatusRunning), "", progress)
-	}
-}
-
-func (uc *AsyncPositionTrackingUseCase) calculateAndUpdateDynamic(siteID int, source string) {
-	currentPositions, err := uc.positionRepo.GetLatestBySiteIDAndSource(siteID, source)
-	if err != nil {
-		return
-	}
-
-	type currentPositionData struct {
-		rank int
-		date time.Time
-	}
-
-	currentMap := make(map[int]currentPositionData)
-	for _, pos := range currentPositions {
-		if pos.Rank > 0 {
-			currentMap[pos.KeywordID] = currentPositionData{
-				rank: pos.Rank,
-				date: pos.Date,
-			}
-		}
-	}
-
-	if len(currentMap) == 0 {
-		site, err := uc.siteRepo.GetByID(siteID)
-		if err != nil {
-			return
-		}
-		if source == entities.GoogleSearch {
-			site.GoogleDynamic = nil
-		} else if source == entities.YandexSearch {
-			site.YandexDynamic = nil
-		}
-		uc.siteRepo.Update(site)
-		return
-	}
-
-	var totalDiff int
-	hasComparisons := false
-	for kwID, currentData := range currentMap {
-		dateBefore := currentData.date.Add(-time.Nanosecond)
-		positions, err := uc.positionRepo.GetByKeywordAndSiteAndSourceWithDateRange(kwID, siteID, source, nil, &dateBefore)
-		if err != nil {
-			continue
-		}
-
-		if len(positions) == 0 {
-			continue
-		}
-
-		var previousRank int
-		for _, pos := range positions {
-			if pos.Rank > 0 {
-				previousRank = pos.Rank
-				break
-			}
-		}
-
-		if previousRank > 0 {
-			diff := previousRank - currentData.rank
-			totalDiff += diff
-			hasComparisons = true
-		}
-	}
-
-	site, err := uc.siteRepo.GetByID(siteID)
-	if err != nil {
-		return
-	}
-
-	var dynamic *int
-	if hasComparisons {
-		if totalDiff > 0 {
-			val := 1
-			dynamic = &val
-		} else if totalDiff < 0 {
-			val := 0
-			dynamic = &val
-		}
-	}
-
-	if source == entities.GoogleSearch {
-		site.GoogleDynamic = dynamic
-	} else if source == entities.YandexSearch {
-		site.YandexDynamic = dynamic
-	}
-
-	uc.siteRepo.Update(site)
-}
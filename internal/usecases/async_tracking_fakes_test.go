@@ -0,0 +1,479 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
+	"go-seo/internal/infrastructure/services"
+
+	"gorm.io/gorm"
+)
+
+// memTrackingJobRepository хранит джобы в памяти и повторяет условные переходы и аренду PostgreSQL-репозитория
+type memTrackingJobRepository struct {
+	repositories.TrackingJobRepository
+	mu    sync.Mutex
+	jobs  map[string]*entities.TrackingJob
+	order []string
+	tasks *memTrackingTaskRepository
+	sites *fakeSiteRepository
+}
+
+func (r *memTrackingJobRepository) Create(job *entities.TrackingJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *job
+	r.jobs[job.ID] = &stored
+	r.order = append(r.order, job.ID)
+	return nil
+}
+
+func (r *memTrackingJobRepository) GetByID(id string) (*entities.TrackingJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (r *memTrackingJobRepository) GetByIDInWorkspace(id string, workspaceID *int) (*entities.TrackingJob, error) {
+	job, err := r.GetByID(id)
+	if err == nil && workspaceID != nil && !r.sites.visible(job.SiteID, workspaceID) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return job, err
+}
+
+func (r *memTrackingJobRepository) UpdateStatusIf(id string, from, to entities.TrackingTaskStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.Status != from {
+		return false, nil
+	}
+	job.Status = to
+	if to == entities.TaskStatusCompleted || to == entities.TaskStatusFailed {
+		job.LockedBy, job.LockedUntil = "", nil
+	}
+	return true, nil
+}
+
+func (r *memTrackingJobRepository) UpdateError(id string, errText string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[id].Error = errText
+	return nil
+}
+
+func (r *memTrackingJobRepository) UpdateProgress(id string, completed, failed int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[id].CompletedTasks, r.jobs[id].FailedTasks = completed, failed
+	return nil
+}
+
+func (r *memTrackingJobRepository) UpdateFailedRequests(id string, failedRequests int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[id].FailedRequests = failedRequests
+	return nil
+}
+
+func (r *memTrackingJobRepository) RequeueFailed(id string, from entities.TrackingTaskStatus, estimatedCost float64) (bool, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.Status != from {
+		return false, 0, nil
+	}
+
+	reset := r.tasks.resetFailed(id)
+	if reset == 0 {
+		// Как и транзакция в PostgreSQL, без failed-задач джоб не меняется
+		return true, 0, nil
+	}
+	job.Status = entities.TaskStatusPending
+	job.EstimatedCost = estimatedCost
+	job.Error = ""
+	job.FailedTasks = max(job.FailedTasks-int(reset), 0)
+	return true, reset, nil
+}
+
+func (r *memTrackingJobRepository) ClaimNext(workerID string, leaseUntil time.Time) (*entities.TrackingJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, id := range r.order {
+		job := r.jobs[id]
+		if job.Status != entities.TaskStatusPending && job.Status != entities.TaskStatusRunning {
+			continue
+		}
+		if job.LockedUntil != nil && job.LockedUntil.After(now) {
+			continue
+		}
+		job.Status = entities.TaskStatusRunning
+		job.LockedBy = workerID
+		job.LockedUntil = &leaseUntil
+		copied := *job
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memTrackingJobRepository) ExtendLease(id, workerID string, leaseUntil time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[id]
+	if job.LockedBy != workerID || job.Status != entities.TaskStatusRunning {
+		return false, nil
+	}
+	job.LockedUntil = &leaseUntil
+	return true, nil
+}
+
+func (r *memTrackingJobRepository) ReleaseLease(id, workerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job := r.jobs[id]; job.LockedBy == workerID {
+		job.LockedBy, job.LockedUntil = "", nil
+	}
+	return nil
+}
+
+func (r *memTrackingJobRepository) status(id string) entities.TrackingTaskStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.jobs[id].Status
+}
+
+type memTrackingTaskRepository struct {
+	repositories.TrackingTaskRepository
+	mu    sync.Mutex
+	tasks map[string]*entities.TrackingTask
+	order []string
+}
+
+func (r *memTrackingTaskRepository) CreateBatch(tasks []*entities.TrackingTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, task := range tasks {
+		stored := *task
+		r.tasks[task.ID] = &stored
+		r.order = append(r.order, task.ID)
+	}
+	return nil
+}
+
+func (r *memTrackingTaskRepository) CountByStatus(jobID string) (map[entities.TrackingTaskStatus]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[entities.TrackingTaskStatus]int)
+	for _, task := range r.tasks {
+		if task.JobID == jobID {
+			counts[task.Status]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *memTrackingTaskRepository) GetPendingByJobID(jobID string) ([]*entities.TrackingTask, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var pending []*entities.TrackingTask
+	for _, id := range r.order {
+		if task := r.tasks[id]; task.JobID == jobID && task.Status == entities.TaskStatusPending {
+			copied := *task
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (r *memTrackingTaskRepository) Finish(id string, status entities.TrackingTaskStatus, errMsg string, attempts int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task := r.tasks[id]
+	task.Status, task.Error = status, errMsg
+	task.Attempts += attempts
+	return nil
+}
+
+func (r *memTrackingTaskRepository) CancelPending(jobID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, task := range r.tasks {
+		if task.JobID == jobID && task.Status == entities.TaskStatusPending {
+			task.Status = entities.TaskStatusCancelled
+		}
+	}
+	return nil
+}
+
+func (r *memTrackingTaskRepository) resetFailed(jobID string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var reset int64
+	for _, task := range r.tasks {
+		if task.JobID == jobID && task.Status == entities.TaskStatusFailed {
+			task.Status, task.Error = entities.TaskStatusPending, ""
+			reset++
+		}
+	}
+	return reset
+}
+
+func (r *memTrackingTaskRepository) statuses(jobID string) map[entities.TrackingTaskStatus]int {
+	counts, _ := r.CountByStatus(jobID)
+	return counts
+}
+
+// trackingSiteRepository дополняет fakeSiteRepository сохранением сводки движения позиций после джоба
+type trackingSiteRepository struct {
+	*fakeSiteRepository
+}
+
+func (r *trackingSiteRepository) UpdateMovement(siteID int, source string, summary *entities.MovementSummary) error {
+	return nil
+}
+
+type fakeMovementRepository struct {
+	repositories.MovementRepository
+}
+
+func (r *fakeMovementRepository) GetCheckDays(siteID int, source string, filterGroupID *int, until time.Time, limit int) ([]time.Time, error) {
+	return nil, nil
+}
+
+type fakeKeywordRepository struct {
+	repositories.KeywordRepository
+	keywords []*entities.Keyword
+}
+
+func (r *fakeKeywordRepository) GetBySiteID(siteID int) ([]*entities.Keyword, error) {
+	return r.keywords, nil
+}
+
+type fakeCompetitorRepository struct {
+	repositories.CompetitorRepository
+}
+
+func (r *fakeCompetitorRepository) GetBySiteID(siteID int) ([]*entities.Competitor, error) {
+	return nil, nil
+}
+
+type fakePositionRepository struct {
+	repositories.PositionRepository
+	mu    sync.Mutex
+	saved []entities.Position
+}
+
+func (r *fakePositionRepository) CreateOrUpdateToday(position *entities.Position) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saved = append(r.saved, *position)
+	return nil
+}
+
+type fakeSerpSnapshotRepository struct {
+	repositories.SerpSnapshotRepository
+}
+
+func (r *fakeSerpSnapshotRepository) ReplaceForPosition(snapshot *entities.SerpSnapshot) error {
+	return nil
+}
+
+type fakeTrackingResultRepository struct {
+	repositories.TrackingResultRepository
+}
+
+func (r *fakeTrackingResultRepository) Create(result *entities.TrackingResult) error {
+	return nil
+}
+
+type fakeUsageRepository struct {
+	budgetUsageRepository
+	mu       sync.Mutex
+	requests int
+	cost     float64
+}
+
+func (r *fakeUsageRepository) Create(usage *entities.ProviderUsage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests += usage.Requests
+	r.cost += usage.Cost
+	return nil
+}
+
+func (r *fakeUsageRepository) WithBudgetLock(workspaceID int, fn func() error) error {
+	return fn()
+}
+
+// GetJobTotals отдает расход по всем джобам: в тестах очереди расход считается по одному джобу
+func (r *fakeUsageRepository) GetJobTotals(jobID string) (int, float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests, r.cost, nil
+}
+
+// fakeSearchService SERP провайдер без сети: search подменяет ответ, счетчики фиксируют обращения
+type fakeSearchService struct {
+	search func(ctx context.Context, req domainservices.SearchRequest) (*domainservices.SitePositionResult, error)
+
+	mu          sync.Mutex
+	calls       map[string]int
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *fakeSearchService) Name() string    { return "fake" }
+func (s *fakeSearchService) Account() string { return "test" }
+
+func (s *fakeSearchService) Capabilities() domainservices.ProviderCapabilities {
+	return domainservices.ProviderCapabilities{
+		Sources:        []string{entities.GoogleSearch, entities.YandexSearch},
+		MaxPages:       10,
+		CostPerRequest: 1,
+	}
+}
+
+func (s *fakeSearchService) FindSitePosition(ctx context.Context, req domainservices.SearchRequest, siteDomain string, competitorDomains []string, source string, maxPages int, subdomains bool) (*domainservices.SitePositionResult, error) {
+	s.mu.Lock()
+	s.calls[req.Query]++
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	if s.search != nil {
+		return s.search(ctx, req)
+	}
+	return &domainservices.SitePositionResult{Position: 1, Requests: 1}, nil
+}
+
+func (s *fakeSearchService) WithCredentials(baseURL, userID, apiKey string) (domainservices.SearchService, error) {
+	return s, nil
+}
+
+func (s *fakeSearchService) Close() error { return nil }
+
+func (s *fakeSearchService) callsFor(query string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[query]
+}
+
+func (s *fakeSearchService) peakInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.maxInFlight
+}
+
+// trackingHarness собирает AsyncPositionTrackingUseCase на репозиториях в памяти.
+// Сайт 1 с keywordCount ключевыми словами "keyword N" принадлежит пространству 1
+type trackingHarness struct {
+	uc        *AsyncPositionTrackingUseCase
+	jobs      *memTrackingJobRepository
+	tasks     *memTrackingTaskRepository
+	positions *fakePositionRepository
+	usage     *fakeUsageRepository
+	provider  *fakeSearchService
+	keywords  []*entities.Keyword
+}
+
+func newTrackingHarness(t *testing.T, keywordCount, workerCount, batchSize int) *trackingHarness {
+	t.Helper()
+
+	sites := &fakeSiteRepository{sites: map[int]*entities.Site{1: {ID: 1, WorkspaceID: 1, Domain: "own.com"}}}
+	tasks := &memTrackingTaskRepository{tasks: make(map[string]*entities.TrackingTask)}
+	jobs := &memTrackingJobRepository{jobs: make(map[string]*entities.TrackingJob), tasks: tasks, sites: sites}
+
+	keywords := make([]*entities.Keyword, keywordCount)
+	for i := range keywords {
+		keywords[i] = &entities.Keyword{ID: i + 1, SiteID: 1, Value: fmt.Sprintf("keyword %d", i+1)}
+	}
+
+	provider := &fakeSearchService{calls: make(map[string]int)}
+	registry := services.NewProviderRegistry(provider.Name())
+	if err := registry.Register(provider); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+
+	kafka, err := services.NewKafkaService(nil)
+	if err != nil {
+		t.Fatalf("kafka: %v", err)
+	}
+
+	h := &trackingHarness{
+		jobs:      jobs,
+		tasks:     tasks,
+		positions: &fakePositionRepository{},
+		usage:     &fakeUsageRepository{},
+		provider:  provider,
+		keywords:  keywords,
+	}
+	h.uc = NewAsyncPositionTrackingUseCase(
+		&trackingSiteRepository{sites}, &fakeKeywordRepository{keywords: keywords}, h.positions, jobs, tasks,
+		&fakeTrackingResultRepository{}, &fakeSerpSnapshotRepository{}, &fakeCompetitorRepository{}, h.usage,
+		&fakeWorkspaceRepository{workspaces: map[int]*entities.Workspace{1: {ID: 1}}},
+		&ProviderAccountUseCase{}, NewMovementUseCase(&fakeMovementRepository{}, &trackingSiteRepository{sites}),
+		registry, nil, kafka, services.NewIDGeneratorService(), services.NewRetryService(0, time.Millisecond),
+		workerCount, batchSize,
+	)
+	return h
+}
+
+// enqueue ставит в очередь google-джоб по первым count ключевым словам сайта
+func (h *trackingHarness) enqueue(t *testing.T, count int) string {
+	t.Helper()
+	return h.enqueuePages(t, count, 1)
+}
+
+// enqueuePages ставит в очередь google-джоб с глубиной поиска pages страниц
+func (h *trackingHarness) enqueuePages(t *testing.T, count, pages int) string {
+	t.Helper()
+
+	jobID, err := h.uc.enqueueJob(1, entities.GoogleSearch, h.keywords[:count], nil, entities.TrackingParams{Pages: pages}, 0, nil)
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	return jobID
+}
+
+// process арендует следующий джоб и обрабатывает его синхронно, как это делает RunQueue
+func (h *trackingHarness) process(t *testing.T, workerID string) *entities.TrackingJob {
+	t.Helper()
+
+	job, err := h.jobs.ClaimNext(workerID, time.Now().Add(time.Minute))
+	if err != nil || job == nil {
+		t.Fatalf("claim: %+v, %v", job, err)
+	}
+	h.uc.processJob(context.Background(), job, workerID, time.Minute)
+	return job
+}
+
+// waitFor ждет выполнения условия, пока не истечет timeout
+func waitFor(t *testing.T, timeout time.Duration, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,349 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+// RunQueue забирает джобы отслеживания из БД и обрабатывает до maxJobs одновременно, пока не отменен ctx.
+// Джобы, прерванные рестартом или падением инстанса, подхватываются после истечения их аренды.
+func (uc *AsyncPositionTrackingUseCase) RunQueue(ctx context.Context, workerID string, pollInterval, leaseDuration time.Duration, maxJobs int) {
+	slots := make(chan struct{}, maxJobs)
+	var wg sync.WaitGroup
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		uc.claimJobs(ctx, &wg, slots, workerID, leaseDuration)
+
+		select {
+		case <-ctx.Done():
+			// Дожидаемся, пока джобы отпустят аренду, чтобы их сразу подхватил другой инстанс
+			wg.Wait()
+			return
+		case <-ticker.C:
+		case <-uc.queueWake:
+		}
+	}
+}
+
+// claimJobs арендует джобы, пока есть свободные слоты и очередь не пуста
+func (uc *AsyncPositionTrackingUseCase) claimJobs(ctx context.Context, wg *sync.WaitGroup, slots chan struct{}, workerID string, leaseDuration time.Duration) {
+	for ctx.Err() == nil {
+		select {
+		case slots <- struct{}{}:
+		default:
+			return
+		}
+
+		job, err := uc.jobRepo.ClaimNext(workerID, time.Now().Add(leaseDuration))
+		if err != nil || job == nil {
+			<-slots
+			if err != nil {
+				log.Printf("ERROR: Failed to claim tracking job: %v", err)
+			}
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				<-slots
+				uc.notifyQueue()
+			}()
+			uc.processJob(ctx, job, workerID, leaseDuration)
+		}()
+	}
+}
+
+// notifyQueue будит RunQueue без ожидания следующего опроса
+func (uc *AsyncPositionTrackingUseCase) notifyQueue() {
+	select {
+	case uc.queueWake <- struct{}{}:
+	default:
+	}
+}
+
+// Как часто обрабатывающий инстанс проверяет, не поставлен ли джоб на паузу или отменен
+const jobStatusCheckInterval = 5 * time.Second
+
+// CancelJob отменяет джоб: новые задачи не запускаются, оставшиеся помечаются cancelled
+func (uc *AsyncPositionTrackingUseCase) CancelJob(workspaceID *int, id string) (*entities.TrackingJob, error) {
+	return uc.changeJobStatus(workspaceID, id, entities.TaskStatusCancelled)
+}
+
+// PauseJob приостанавливает джоб; невыполненные задачи остаются pending до возобновления
+func (uc *AsyncPositionTrackingUseCase) PauseJob(workspaceID *int, id string) (*entities.TrackingJob, error) {
+	return uc.changeJobStatus(workspaceID, id, entities.TaskStatusPaused)
+}
+
+// ResumeJob возвращает приостановленный джоб в очередь
+func (uc *AsyncPositionTrackingUseCase) ResumeJob(workspaceID *int, id string) (*entities.TrackingJob, error) {
+	return uc.changeJobStatus(workspaceID, id, entities.TaskStatusPending)
+}
+
+// jobTransitions допустимые переходы: целевой статус -> статусы, из которых он возможен
+var jobTransitions = map[entities.TrackingTaskStatus][]entities.TrackingTaskStatus{
+	entities.TaskStatusCancelled: {entities.TaskStatusPending, entities.TaskStatusRunning, entities.TaskStatusPaused},
+	entities.TaskStatusPaused:    {entities.TaskStatusPending, entities.TaskStatusRunning},
+	entities.TaskStatusPending:   {entities.TaskStatusPaused},
+}
+
+func (uc *AsyncPositionTrackingUseCase) changeJobStatus(workspaceID *int, id string, to entities.TrackingTaskStatus) (*entities.TrackingJob, error) {
+	// Статус может измениться между чтением и записью (например, джоб как раз арендован), поэтому пробуем несколько раз
+	for attempt := 0; attempt < 3; attempt++ {
+		job, err := authorizeJob(uc.jobRepo, workspaceID, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(jobTransitions[to], job.Status) {
+			return nil, &DomainError{
+				Code:    ErrorJobStateConflict,
+				Message: fmt.Sprintf("Job in status %s cannot be moved to %s", job.Status, to),
+			}
+		}
+
+		updated, err := uc.jobRepo.UpdateStatusIf(id, job.Status, to)
+		if err != nil {
+			return nil, &DomainError{
+				Code:    ErrorJobUpdate,
+				Message: "Failed to update tracking job",
+				Err:     err,
+			}
+		}
+		if !updated {
+			continue
+		}
+
+		previous := job.Status
+		job.Status = to
+
+		switch {
+		case to == entities.TaskStatusPending:
+			uc.notifyQueue()
+			if err := uc.kafkaService.SendJobStatus(id, string(to), ""); err != nil {
+				log.Printf("WARNING: Failed to send job status to Kafka: %v", err)
+			}
+		case previous == entities.TaskStatusRunning && job.LockedUntil != nil && job.LockedUntil.After(time.Now()):
+			// Джоб обрабатывается: воркер дождется текущих запросов и сам отправит итог
+			uc.stopActiveJob(id)
+		default:
+			uc.finishStoppedJob(job)
+		}
+
+		if reloaded, err := uc.jobRepo.GetByID(id); err == nil {
+			job = reloaded
+		}
+		return job, nil
+	}
+
+	return nil, &DomainError{
+		Code:    ErrorJobStateConflict,
+		Message: "Tracking job status changed concurrently, try again",
+	}
+}
+
+// RetryFailedJob возвращает в очередь завершенный джоб, перезапуская только задачи со статусом failed.
+// Задачи, не запущенные из-за остановки джоба по ошибке провайдера, остаются pending и выполняются вместе с ними.
+// Повтор оплачивается заново, поэтому проверяется по бюджету, как новый запуск: под той же блокировкой,
+// без урезания. Оценка джоба становится равной уже потраченному плюс стоимость повтора
+func (uc *AsyncPositionTrackingUseCase) RetryFailedJob(workspaceID *int, id string) (*entities.TrackingJob, error) {
+	job, err := authorizeJob(uc.jobRepo, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch job.Status {
+	case entities.TaskStatusCompleted, entities.TaskStatusFailed, entities.TaskStatusCancelled:
+	default:
+		return nil, &DomainError{
+			Code:    ErrorJobStateConflict,
+			Message: fmt.Sprintf("Job in status %s is not finished", job.Status),
+		}
+	}
+
+	site, err := uc.siteRepo.GetByID(job.SiteID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorSiteNotFound,
+			Message: fmt.Sprintf("Site %d not found", job.SiteID),
+			Err:     err,
+		}
+	}
+	retryCost, err := uc.retryCost(site, job)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated bool
+	var reset int64
+	err = uc.usageRepo.WithBudgetLock(site.WorkspaceID, func() error {
+		if retryCost.tasks > 0 {
+			retried := make([]*entities.Keyword, retryCost.tasks)
+			fitted, err := fitBudget(uc.usageRepo, uc.workspaceRepo, site, retried, retryCost.taskCost)
+			if err != nil {
+				return err
+			}
+			// Урезать повтор нельзя: перезапускаются все failed-задачи
+			if len(fitted) < len(retried) {
+				return &DomainError{
+					Code:    ErrorBudgetExceeded,
+					Message: fmt.Sprintf("Retry of %d tasks would cost up to %.2f and does not fit the remaining monthly budget", len(retried), retryCost.total()),
+				}
+			}
+		}
+
+		updated, reset, err = uc.jobRepo.RequeueFailed(id, job.Status, retryCost.spent+retryCost.total())
+		return err
+	})
+	if err != nil {
+		var domainErr *DomainError
+		if errors.As(err, &domainErr) {
+			return nil, domainErr
+		}
+		return nil, &DomainError{
+			Code:    ErrorJobUpdate,
+			Message: "Failed to update tracking job",
+			Err:     err,
+		}
+	}
+	if !updated {
+		return nil, &DomainError{
+			Code:    ErrorJobStateConflict,
+			Message: "Tracking job status changed concurrently, try again",
+		}
+	}
+	if reset == 0 {
+		return nil, &DomainError{
+			Code:    ErrorJobStateConflict,
+			Message: "Job has no failed keywords to retry",
+		}
+	}
+
+	uc.notifyQueue()
+	if err := uc.kafkaService.SendJobStatus(id, string(entities.TaskStatusPending), ""); err != nil {
+		log.Printf("WARNING: Failed to send job status to Kafka: %v", err)
+	}
+	log.Printf("Job %s queued to retry %d failed keywords", id, reset)
+
+	if reloaded, err := uc.jobRepo.GetByID(id); err == nil {
+		job = reloaded
+	}
+	return job, nil
+}
+
+// jobRetryCost оценка повтора: tasks задач по taskCost и уже потраченное джобом spent
+type jobRetryCost struct {
+	tasks    int
+	taskCost float64
+	spent    float64
+}
+
+func (c jobRetryCost) total() float64 {
+	return float64(c.tasks) * c.taskCost
+}
+
+// retryCost оценивает повтор джоба по его сохраненным параметрам. Перезапускаются failed-задачи
+// и задачи, оставшиеся pending после остановки джоба
+func (uc *AsyncPositionTrackingUseCase) retryCost(site *entities.Site, job *entities.TrackingJob) (jobRetryCost, error) {
+	var cost jobRetryCost
+
+	counts, err := uc.taskRepo.CountByStatus(job.ID)
+	if err != nil {
+		return cost, &DomainError{
+			Code:    ErrorJobFetch,
+			Message: "Failed to count tracking tasks",
+			Err:     err,
+		}
+	}
+	if counts[entities.TaskStatusFailed] == 0 {
+		return cost, nil
+	}
+	cost.tasks = counts[entities.TaskStatusFailed] + counts[entities.TaskStatusPending]
+
+	params := newTaskParams(job.Params)
+	if err := uc.prepareClients(site, job.Source, params); err != nil {
+		return cost, err
+	}
+	if job.Source == entities.Wordstat {
+		// Задача Wordstat - один запрос частоты одного типа
+		cost.taskCost = params.Wordstat.CostPerRequest()
+	} else {
+		cost.taskCost = float64(estimateKeywordRequests(job.Source, job.Params)) * params.SearchProvider.Capabilities().CostPerRequest
+	}
+
+	if _, cost.spent, err = uc.usageRepo.GetJobTotals(job.ID); err != nil {
+		return cost, &DomainError{
+			Code:    ErrorJobFetch,
+			Message: "Failed to fetch tracking job usage",
+			Err:     err,
+		}
+	}
+	return cost, nil
+}
+
+// finishStoppedJob фиксирует итоговые счетчики остановленного джоба, снимает аренду и отправляет статус в Kafka
+func (uc *AsyncPositionTrackingUseCase) finishStoppedJob(job *entities.TrackingJob) {
+	if job.Status == entities.TaskStatusCancelled {
+		if err := uc.taskRepo.CancelPending(job.ID); err != nil {
+			log.Printf("WARNING: Failed to cancel tasks of job %s: %v", job.ID, err)
+		}
+	}
+
+	counts, err := uc.taskRepo.CountByStatus(job.ID)
+	if err != nil {
+		log.Printf("WARNING: Failed to count tasks of job %s: %v", job.ID, err)
+	} else {
+		job.CompletedTasks = counts[entities.TaskStatusCompleted]
+		job.FailedTasks = counts[entities.TaskStatusFailed]
+		if err := uc.jobRepo.UpdateProgress(job.ID, job.CompletedTasks, job.FailedTasks); err != nil {
+			log.Printf("WARNING: Failed to update progress of job %s: %v", job.ID, err)
+		}
+	}
+
+	if job.LockedBy != "" {
+		if err := uc.jobRepo.ReleaseLease(job.ID, job.LockedBy); err != nil {
+			log.Printf("WARNING: Failed to release lease on job %s: %v", job.ID, err)
+		}
+	}
+
+	percent := 0
+	if job.TotalTasks > 0 {
+		percent = (job.CompletedTasks + job.FailedTasks) * 100 / job.TotalTasks
+	}
+	if err := uc.kafkaService.SendJobStatus(job.ID, string(job.Status), "", percent); err != nil {
+		log.Printf("WARNING: Failed to send job status to Kafka: %v", err)
+	}
+	log.Printf("Job %s %s: %d completed, %d failed of %d", job.ID, job.Status, job.CompletedTasks, job.FailedTasks, job.TotalTasks)
+}
+
+func (uc *AsyncPositionTrackingUseCase) registerActiveJob(id string, cancel context.CancelFunc) {
+	uc.activeJobsMu.Lock()
+	defer uc.activeJobsMu.Unlock()
+	uc.activeJobs[id] = cancel
+}
+
+func (uc *AsyncPositionTrackingUseCase) unregisterActiveJob(id string) {
+	uc.activeJobsMu.Lock()
+	defer uc.activeJobsMu.Unlock()
+	delete(uc.activeJobs, id)
+}
+
+// stopActiveJob останавливает джоб, если он обрабатывается этим инстансом; другие инстансы заметят смену статуса в keepLease
+func (uc *AsyncPositionTrackingUseCase) stopActiveJob(id string) {
+	uc.activeJobsMu.Lock()
+	defer uc.activeJobsMu.Unlock()
+	if cancel, ok := uc.activeJobs[id]; ok {
+		cancel()
+	}
+}
@@ -0,0 +1,394 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	domainservices "go-seo/internal/domain/services"
+)
+
+func TestRunQueueRespectsJobSlots(t *testing.T) {
+	const jobs, maxJobs = 5, 2
+
+	// По одному keyword на джоб: одновременных запросов к провайдеру столько же, сколько джобов в работе
+	h := newTrackingHarness(t, 1, 4, 10)
+	h.provider.search = func(ctx context.Context, req domainservices.SearchRequest) (*domainservices.SitePositionResult, error) {
+		time.Sleep(20 * time.Millisecond)
+		return &domainservices.SitePositionResult{Position: 1, Requests: 1}, nil
+	}
+
+	ids := make([]string, jobs)
+	for i := range ids {
+		ids[i] = h.enqueue(t, 1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.uc.RunQueue(ctx, "worker", 10*time.Millisecond, time.Minute, maxJobs)
+		close(done)
+	}()
+
+	waitFor(t, 5*time.Second, func() bool {
+		for _, id := range ids {
+			if h.jobs.status(id) != entities.TaskStatusCompleted {
+				return false
+			}
+		}
+		return true
+	})
+	cancel()
+	<-done
+
+	if peak := h.provider.peakInFlight(); peak > maxJobs {
+		t.Fatalf("expected at most %d jobs at once, got %d", maxJobs, peak)
+	}
+	for _, id := range ids {
+		if job, _ := h.jobs.GetByID(id); job.LockedBy != "" || job.CompletedTasks != 1 {
+			t.Fatalf("job %s: expected released lease and one completed task, got %+v", id, job)
+		}
+	}
+}
+
+// failingClaimRepository отвечает ошибкой на ClaimNext, как при недоступной БД
+type failingClaimRepository struct {
+	*memTrackingJobRepository
+}
+
+func (r *failingClaimRepository) ClaimNext(workerID string, leaseUntil time.Time) (*entities.TrackingJob, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestClaimJobsReturnsSlotWithoutJob(t *testing.T) {
+	h := newTrackingHarness(t, 1, 1, 1)
+	slots := make(chan struct{}, 2)
+	var wg sync.WaitGroup
+
+	// Пустая очередь: слот, занятый под попытку аренды, освобождается
+	h.uc.claimJobs(context.Background(), &wg, slots, "worker", time.Minute)
+	if len(slots) != 0 {
+		t.Fatalf("empty queue kept %d slots", len(slots))
+	}
+
+	h.uc.jobRepo = &failingClaimRepository{h.jobs}
+	h.uc.claimJobs(context.Background(), &wg, slots, "worker", time.Minute)
+	if len(slots) != 0 {
+		t.Fatalf("failed claim kept %d slots", len(slots))
+	}
+
+	// Все слоты заняты: новые джобы не арендуются, пока обрабатывающиеся не завершатся
+	h.uc.jobRepo = h.jobs
+	jobID := h.enqueue(t, 1)
+	slots <- struct{}{}
+	slots <- struct{}{}
+	h.uc.claimJobs(context.Background(), &wg, slots, "worker", time.Minute)
+	if status := h.jobs.status(jobID); status != entities.TaskStatusPending {
+		t.Fatalf("job claimed without a free slot, status %s", status)
+	}
+
+	<-slots
+	h.uc.claimJobs(context.Background(), &wg, slots, "worker", time.Minute)
+	wg.Wait()
+	if status := h.jobs.status(jobID); status != entities.TaskStatusCompleted {
+		t.Fatalf("expected job to run in the freed slot, status %s", status)
+	}
+	if len(slots) != 1 {
+		t.Fatalf("finished job must return its slot, %d slots busy", len(slots))
+	}
+}
+
+func TestResumeAfterPartialSearch(t *testing.T) {
+	tests := []struct {
+		name string
+		// found - позиция сайта на уже полученной первой странице, 0 - сайт на ней не найден
+		found int
+		// Сайт найден: позиция сохраняется по одной странице и первое слово повторно не оплачивается.
+		// Не найден: задача остается pending и после возобновления выполняется заново
+		wantCalls    int
+		wantRequests int
+	}{
+		{name: "site found on fetched pages", found: 3, wantCalls: 1, wantRequests: 2},
+		{name: "site not found on fetched pages", wantCalls: 2, wantRequests: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Один воркер: задачи выполняются по очереди, вторая не стартует после паузы
+			h := newTrackingHarness(t, 2, 1, 1)
+			jobID := h.enqueuePages(t, 2, 3)
+
+			h.provider.search = func(ctx context.Context, req domainservices.SearchRequest) (*domainservices.SitePositionResult, error) {
+				if req.Query != "keyword 1" || h.provider.callsFor(req.Query) > 1 {
+					return &domainservices.SitePositionResult{Position: 1, Requests: 1}, nil
+				}
+				// Пауза приходит, когда первая страница выдачи уже получена, а вторая еще запрашивается
+				if _, err := h.uc.PauseJob(nil, jobID); err != nil {
+					t.Errorf("pause: %v", err)
+				}
+				return &domainservices.SitePositionResult{Position: tt.found, Requests: 1}, ctx.Err()
+			}
+
+			h.process(t, "worker")
+			if status := h.jobs.status(jobID); status != entities.TaskStatusPaused {
+				t.Fatalf("expected paused job, got %s", status)
+			}
+			counts := h.tasks.statuses(jobID)
+			if tt.found > 0 {
+				if counts[entities.TaskStatusCompleted] != 1 || counts[entities.TaskStatusPending] != 1 {
+					t.Fatalf("expected the paid task completed and the other pending, got %v", counts)
+				}
+				if len(h.positions.saved) != 1 || h.positions.saved[0].Rank != tt.found || h.positions.saved[0].Pages != 1 {
+					t.Fatalf("expected the position from the fetched page, got %+v", h.positions.saved)
+				}
+			} else {
+				if counts[entities.TaskStatusPending] != 2 {
+					t.Fatalf("expected both tasks pending, got %v", counts)
+				}
+				// Позиция 0 означала бы, что сайт выпал из выдачи
+				if len(h.positions.saved) != 0 {
+					t.Fatalf("expected no position, got %+v", h.positions.saved)
+				}
+			}
+
+			if _, err := h.uc.ResumeJob(nil, jobID); err != nil {
+				t.Fatalf("resume: %v", err)
+			}
+			h.process(t, "worker")
+
+			if status := h.jobs.status(jobID); status != entities.TaskStatusCompleted {
+				t.Fatalf("expected completed job, got %s", status)
+			}
+			if calls := h.provider.callsFor("keyword 1"); calls != tt.wantCalls {
+				t.Fatalf("keyword 1 was searched %d times, want %d", calls, tt.wantCalls)
+			}
+			if calls := h.provider.callsFor("keyword 2"); calls != 1 {
+				t.Fatalf("pending keyword was searched %d times", calls)
+			}
+			if h.usage.requests != tt.wantRequests {
+				t.Fatalf("expected %d paid requests, got %d", tt.wantRequests, h.usage.requests)
+			}
+			for _, position := range h.positions.saved {
+				if position.Rank == 0 {
+					t.Fatalf("unexpected not found position %+v", position)
+				}
+			}
+		})
+	}
+}
+
+func TestPauseBeforeLastTaskFinishes(t *testing.T) {
+	tests := []struct {
+		name  string
+		pause func(h *trackingHarness, jobID string) error
+	}{
+		{
+			// Джоб обрабатывается этим инстансом: ctx отменяется, но ответ на последний запрос уже получен
+			name: "paused on this instance",
+			pause: func(h *trackingHarness, jobID string) error {
+				_, err := h.uc.PauseJob(nil, jobID)
+				return err
+			},
+		},
+		{
+			// Другой инстанс меняет статус в БД; обрабатывающий заметит это только при продлении аренды
+			name: "paused on another instance",
+			pause: func(h *trackingHarness, jobID string) error {
+				_, err := h.jobs.UpdateStatusIf(jobID, entities.TaskStatusRunning, entities.TaskStatusPaused)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTrackingHarness(t, 2, 1, 1)
+			jobID := h.enqueue(t, 2)
+
+			h.provider.search = func(ctx context.Context, req domainservices.SearchRequest) (*domainservices.SitePositionResult, error) {
+				if req.Query == "keyword 2" {
+					if err := tt.pause(h, jobID); err != nil {
+						t.Errorf("pause: %v", err)
+					}
+				}
+				return &domainservices.SitePositionResult{Position: 1, Requests: 1}, nil
+			}
+
+			h.process(t, "worker")
+
+			job, _ := h.jobs.GetByID(jobID)
+			if job.Status != entities.TaskStatusPaused {
+				t.Fatalf("pause must not be overwritten by completion, got %s", job.Status)
+			}
+			if counts := h.tasks.statuses(jobID); counts[entities.TaskStatusCompleted] != 2 {
+				t.Fatalf("expected both tasks completed, got %v", counts)
+			}
+			if job.CompletedTasks != 2 {
+				t.Fatalf("expected progress 2, got %d", job.CompletedTasks)
+			}
+		})
+	}
+}
+
+// staleStatusJobRepository отдает джоб в статусе, который успел измениться до RequeueFailed
+type staleStatusJobRepository struct {
+	*memTrackingJobRepository
+	status entities.TrackingTaskStatus
+}
+
+func (r *staleStatusJobRepository) GetByIDInWorkspace(id string, workspaceID *int) (*entities.TrackingJob, error) {
+	job, err := r.memTrackingJobRepository.GetByIDInWorkspace(id, workspaceID)
+	if err == nil {
+		job.Status = r.status
+	}
+	return job, err
+}
+
+func TestRetryFailedJob(t *testing.T) {
+	h := newTrackingHarness(t, 3, 1, 1)
+	jobID := h.enqueue(t, 3)
+
+	h.provider.search = func(ctx context.Context, req domainservices.SearchRequest) (*domainservices.SitePositionResult, error) {
+		if req.Query == "keyword 2" && h.provider.callsFor(req.Query) == 1 {
+			return &domainservices.SitePositionResult{}, errors.New("unexpected response")
+		}
+		return &domainservices.SitePositionResult{Position: 1, Requests: 1}, nil
+	}
+	h.process(t, "worker")
+
+	job, _ := h.jobs.GetByID(jobID)
+	if job.Status != entities.TaskStatusCompleted || job.FailedTasks != 1 {
+		t.Fatalf("expected completed job with one failed task, got %+v", job)
+	}
+
+	// Из другого пространства джоб не виден
+	if _, err := h.uc.RetryFailedJob(intPtr(2), jobID); GetDomainErrorCode(err) != ErrorJobNotFound {
+		t.Fatalf("expected %s for a foreign workspace, got %v", ErrorJobNotFound, err)
+	}
+
+	job, err := h.uc.RetryFailedJob(intPtr(1), jobID)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if job.Status != entities.TaskStatusPending || job.FailedTasks != 0 {
+		t.Fatalf("expected pending job without failed tasks, got %+v", job)
+	}
+	if counts := h.tasks.statuses(jobID); counts[entities.TaskStatusPending] != 1 || counts[entities.TaskStatusCompleted] != 2 {
+		t.Fatalf("only the failed task must be requeued, got %v", counts)
+	}
+
+	h.process(t, "worker")
+	if status := h.jobs.status(jobID); status != entities.TaskStatusCompleted {
+		t.Fatalf("expected completed job after retry, got %s", status)
+	}
+	for query, want := range map[string]int{"keyword 1": 1, "keyword 2": 2, "keyword 3": 1} {
+		if calls := h.provider.callsFor(query); calls != want {
+			t.Errorf("%s searched %d times, want %d", query, calls, want)
+		}
+	}
+
+	// Все задачи выполнены: перезапускать нечего, статус не меняется
+	if _, err := h.uc.RetryFailedJob(intPtr(1), jobID); GetDomainErrorCode(err) != ErrorJobStateConflict {
+		t.Fatalf("expected %s without failed tasks, got %v", ErrorJobStateConflict, err)
+	}
+	if status := h.jobs.status(jobID); status != entities.TaskStatusCompleted {
+		t.Fatalf("job without failed tasks must stay completed, got %s", status)
+	}
+}
+
+func TestRetryFailedJobRejectsUnfinished(t *testing.T) {
+	h := newTrackingHarness(t, 1, 1, 1)
+	jobID := h.enqueue(t, 1)
+
+	if _, err := h.uc.RetryFailedJob(nil, jobID); GetDomainErrorCode(err) != ErrorJobStateConflict {
+		t.Fatalf("expected %s for a pending job, got %v", ErrorJobStateConflict, err)
+	}
+
+	// Статус сменился между чтением и перезапуском: транзакция RequeueFailed ничего не меняет
+	h.uc.jobRepo = &staleStatusJobRepository{memTrackingJobRepository: h.jobs, status: entities.TaskStatusFailed}
+	if _, err := h.uc.RetryFailedJob(nil, jobID); GetDomainErrorCode(err) != ErrorJobStateConflict {
+		t.Fatalf("expected %s for a concurrently changed job, got %v", ErrorJobStateConflict, err)
+	}
+	if status := h.jobs.status(jobID); status != entities.TaskStatusPending {
+		t.Fatalf("expected job to stay pending, got %s", status)
+	}
+}
+
+func TestRetryFailedJobWithinBudget(t *testing.T) {
+	h := newTrackingHarness(t, 3, 1, 1)
+	jobID := h.enqueue(t, 3)
+
+	h.provider.search = func(ctx context.Context, req domainservices.SearchRequest) (*domainservices.SitePositionResult, error) {
+		if req.Query != "keyword 1" && h.provider.callsFor(req.Query) == 1 {
+			return &domainservices.SitePositionResult{}, errors.New("unexpected response")
+		}
+		return &domainservices.SitePositionResult{Position: 1, Requests: 1}, nil
+	}
+	h.process(t, "worker")
+
+	// Повтор двух задач стоит 2, а в бюджете сайта остается 1: урезать повтор нельзя
+	site, _ := h.uc.siteRepo.GetByID(1)
+	site.MonthlyBudget = floatPtr(10)
+	h.usage.siteSpent = 9
+	for _, action := range []string{entities.BudgetActionRefuse, entities.BudgetActionTrim} {
+		site.BudgetAction = action
+		if _, err := h.uc.RetryFailedJob(intPtr(1), jobID); GetDomainErrorCode(err) != ErrorBudgetExceeded {
+			t.Fatalf("%s: expected %s, got %v", action, ErrorBudgetExceeded, err)
+		}
+		if status := h.jobs.status(jobID); status != entities.TaskStatusCompleted {
+			t.Fatalf("%s: rejected retry must keep the job completed, got %s", action, status)
+		}
+		if counts := h.tasks.statuses(jobID); counts[entities.TaskStatusFailed] != 2 {
+			t.Fatalf("%s: rejected retry must keep the tasks failed, got %v", action, counts)
+		}
+	}
+
+	// Резерв повтора - оценка джоба сверх уже потраченного им
+	h.usage.siteSpent = 8
+	job, err := h.uc.RetryFailedJob(intPtr(1), jobID)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if job.Status != entities.TaskStatusPending || job.EstimatedCost != 3 {
+		t.Fatalf("expected pending job estimated at 3, got %+v", job)
+	}
+}
+
+func TestProcessJobLimitsConcurrentRequests(t *testing.T) {
+	const workers = 3
+
+	// Пачки по 10 задач и два джоба одновременно: пул воркеров общий, запросов не больше workers
+	h := newTrackingHarness(t, 60, workers, 10)
+	h.provider.search = func(ctx context.Context, req domainservices.SearchRequest) (*domainservices.SitePositionResult, error) {
+		time.Sleep(time.Millisecond)
+		return &domainservices.SitePositionResult{Position: 1, Requests: 1}, nil
+	}
+	first, second := h.enqueue(t, 60), h.enqueue(t, 60)
+
+	var wg sync.WaitGroup
+	for _, workerID := range []string{"worker-1", "worker-2"} {
+		job, err := h.jobs.ClaimNext(workerID, time.Now().Add(time.Minute))
+		if err != nil || job == nil {
+			t.Fatalf("claim: %+v, %v", job, err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.uc.processJob(context.Background(), job, workerID, time.Minute)
+		}()
+	}
+	wg.Wait()
+
+	if peak := h.provider.peakInFlight(); peak > workers {
+		t.Fatalf("expected at most %d requests at once, got %d", workers, peak)
+	}
+	for _, id := range []string{first, second} {
+		job, _ := h.jobs.GetByID(id)
+		if job.Status != entities.TaskStatusCompleted || job.CompletedTasks != 60 {
+			t.Fatalf("job %s: expected 60 completed tasks, got %+v", id, job)
+		}
+	}
+}
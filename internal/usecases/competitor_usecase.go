@@ -0,0 +1,210 @@
+package usecases
+
+import (
+	"log"
+	"strings"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
+	"go-seo/internal/infrastructure/database"
+)
+
+type CompetitorUseCase struct {
+	competitorRepo repositories.CompetitorRepository
+	siteRepo       repositories.SiteRepository
+	positionRepo   repositories.PositionRepository
+}
+
+func NewCompetitorUseCase(
+	competitorRepo repositories.CompetitorRepository,
+	siteRepo repositories.SiteRepository,
+	positionRepo repositories.PositionRepository,
+) *CompetitorUseCase {
+	return &CompetitorUseCase{
+		competitorRepo: competitorRepo,
+		siteRepo:       siteRepo,
+		positionRepo:   positionRepo,
+	}
+}
+
+func (uc *CompetitorUseCase) CreateCompetitor(workspaceID *int, siteID int, domain string) (*entities.Competitor, error) {
+	site, err := authorizeSite(uc.siteRepo, workspaceID, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	domain, err = validateCompetitorDomain(site, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	competitor := &entities.Competitor{
+		SiteID: siteID,
+		Domain: domain,
+	}
+
+	if err := uc.competitorRepo.Create(competitor); err != nil {
+		if database.IsDatabaseError(err) && database.GetDatabaseErrorCode(err) == "DUPLICATE_ENTRY" {
+			return nil, &DomainError{
+				Code:    ErrorCompetitorExists,
+				Message: "Competitor with this domain already exists",
+				Err:     err,
+			}
+		}
+		return nil, &DomainError{
+			Code:    ErrorCompetitorCreation,
+			Message: "Failed to create competitor",
+			Err:     err,
+		}
+	}
+
+	return competitor, nil
+}
+
+func (uc *CompetitorUseCase) GetCompetitorsBySite(workspaceID *int, siteID int) ([]*entities.Competitor, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
+	competitors, err := uc.competitorRepo.GetBySiteID(siteID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorCompetitorFetch,
+			Message: "Failed to fetch competitors",
+			Err:     err,
+		}
+	}
+
+	return competitors, nil
+}
+
+func (uc *CompetitorUseCase) UpdateCompetitor(workspaceID *int, siteID, id int, domain string) (*entities.Competitor, error) {
+	site, err := authorizeSite(uc.siteRepo, workspaceID, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	competitor, err := uc.getSiteCompetitor(siteID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	domain, err = validateCompetitorDomain(site, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	competitor.Domain = domain
+	if err := uc.competitorRepo.Update(competitor); err != nil {
+		if database.IsDatabaseError(err) && database.GetDatabaseErrorCode(err) == "DUPLICATE_ENTRY" {
+			return nil, &DomainError{
+				Code:    ErrorCompetitorExists,
+				Message: "Competitor with this domain already exists",
+				Err:     err,
+			}
+		}
+		return nil, &DomainError{
+			Code:    ErrorCompetitorUpdate,
+			Message: "Failed to update competitor",
+			Err:     err,
+		}
+	}
+
+	return competitor, nil
+}
+
+func (uc *CompetitorUseCase) DeleteCompetitor(workspaceID *int, siteID, id int) error {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return err
+	}
+
+	if _, err := uc.getSiteCompetitor(siteID, id); err != nil {
+		return err
+	}
+
+	if err := uc.positionRepo.DeleteByCompetitorID(id); err != nil {
+		return &DomainError{
+			Code:    ErrorPositionDeletion,
+			Message: "Failed to delete competitor positions",
+			Err:     err,
+		}
+	}
+
+	if err := uc.competitorRepo.Delete(id); err != nil {
+		return &DomainError{
+			Code:    ErrorCompetitorDeletion,
+			Message: "Failed to delete competitor",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// getSiteCompetitor возвращает конкурента, только если он относится к указанному сайту
+func (uc *CompetitorUseCase) getSiteCompetitor(siteID, id int) (*entities.Competitor, error) {
+	competitor, err := uc.competitorRepo.GetByID(id)
+	if err != nil || competitor.SiteID != siteID {
+		return nil, &DomainError{
+			Code:    ErrorCompetitorNotFound,
+			Message: "Competitor not found",
+			Err:     err,
+		}
+	}
+
+	return competitor, nil
+}
+
+func validateCompetitorDomain(site *entities.Site, domain string) (string, error) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if domain == "" {
+		return "", &DomainError{
+			Code:    ErrorValidation,
+			Message: "Competitor domain is required",
+		}
+	}
+
+	if domain == strings.ToLower(site.Domain) {
+		return "", &DomainError{
+			Code:    ErrorValidation,
+			Message: "Competitor domain must differ from the site domain",
+		}
+	}
+
+	return domain, nil
+}
+
+func competitorDomains(competitors []*entities.Competitor) []string {
+	domains := make([]string, len(competitors))
+	for i, competitor := range competitors {
+		domains[i] = competitor.Domain
+	}
+	return domains
+}
+
+// saveCompetitorPositions записывает позиции конкурентов, найденные в той же выдаче, что и позиция сайта.
+// Ошибки только логируются: позиция сайта уже записана, а повтор проверки означал бы повторную оплату запроса.
+// partial - получена только часть страниц выдачи: не найденный на них конкурент не записывается, иначе он выглядел бы выпавшим
+func saveCompetitorPositions(repo repositories.PositionRepository, sitePosition *entities.Position, competitors []*entities.Competitor, results []domainservices.DomainPosition, partial bool) {
+	for i, competitor := range competitors {
+		if i >= len(results) {
+			break
+		}
+		if partial && results[i].Position == 0 {
+			continue
+		}
+
+		competitorID := competitor.ID
+		position := *sitePosition
+		position.ID = 0
+		position.CompetitorID = &competitorID
+		position.Rank = results[i].Position
+		position.URL = results[i].URL
+		position.Title = results[i].Title
+
+		if err := repo.CreateOrUpdateToday(&position); err != nil {
+			log.Printf("WARNING: Failed to save position for competitor %d (keyword %d): %v", competitor.ID, sitePosition.KeywordID, err)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
+	"go-seo/internal/infrastructure/database"
+
+	"gorm.io/gorm"
+)
+
+// memCompetitorRepository хранит конкурентов в памяти и, как уникальный индекс (site_id, domain), отклоняет повторы
+type memCompetitorRepository struct {
+	repositories.CompetitorRepository
+	competitors map[int]*entities.Competitor
+	nextID      int
+}
+
+func (r *memCompetitorRepository) duplicate(competitor *entities.Competitor) error {
+	for _, existing := range r.competitors {
+		if existing.ID != competitor.ID && existing.SiteID == competitor.SiteID && existing.Domain == competitor.Domain {
+			return &database.DatabaseError{Code: "DUPLICATE_ENTRY", Message: "Record already exists"}
+		}
+	}
+	return nil
+}
+
+func (r *memCompetitorRepository) Create(competitor *entities.Competitor) error {
+	if err := r.duplicate(competitor); err != nil {
+		return err
+	}
+	r.nextID++
+	competitor.ID = r.nextID
+	stored := *competitor
+	r.competitors[competitor.ID] = &stored
+	return nil
+}
+
+func (r *memCompetitorRepository) GetByID(id int) (*entities.Competitor, error) {
+	if competitor, ok := r.competitors[id]; ok {
+		copied := *competitor
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memCompetitorRepository) GetBySiteID(siteID int) ([]*entities.Competitor, error) {
+	var result []*entities.Competitor
+	for id := 1; id <= r.nextID; id++ {
+		if competitor, ok := r.competitors[id]; ok && competitor.SiteID == siteID {
+			copied := *competitor
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (r *memCompetitorRepository) Update(competitor *entities.Competitor) error {
+	if err := r.duplicate(competitor); err != nil {
+		return err
+	}
+	r.competitors[competitor.ID].Domain = competitor.Domain
+	return nil
+}
+
+func (r *memCompetitorRepository) Delete(id int) error {
+	delete(r.competitors, id)
+	return nil
+}
+
+// competitorPositionRepository запоминает конкурентов, чьи позиции удалены
+type competitorPositionRepository struct {
+	repositories.PositionRepository
+	deleted []int
+}
+
+func (r *competitorPositionRepository) DeleteByCompetitorID(competitorID int) error {
+	r.deleted = append(r.deleted, competitorID)
+	return nil
+}
+
+func newCompetitorUseCase() (*CompetitorUseCase, *memCompetitorRepository, *competitorPositionRepository) {
+	sites, _ := newScopeRepositories()
+	competitors := &memCompetitorRepository{competitors: make(map[int]*entities.Competitor)}
+	positions := &competitorPositionRepository{}
+	return NewCompetitorUseCase(competitors, sites, positions), competitors, positions
+}
+
+func TestCompetitorCRUD(t *testing.T) {
+	uc, _, positions := newCompetitorUseCase()
+
+	rival, err := uc.CreateCompetitor(intPtr(1), 1, "  Rival.COM ")
+	if err != nil || rival.Domain != "rival.com" || rival.SiteID != 1 {
+		t.Fatalf("expected normalized competitor of site 1, got %+v, %v", rival, err)
+	}
+	other, err := uc.CreateCompetitor(intPtr(1), 1, "other-rival.com")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if _, err := uc.CreateCompetitor(intPtr(1), 1, "RIVAL.com"); GetDomainErrorCode(err) != ErrorCompetitorExists {
+		t.Fatalf("create duplicate: expected %s, got %v", ErrorCompetitorExists, err)
+	}
+	if _, err := uc.UpdateCompetitor(intPtr(1), 1, other.ID, "rival.com"); GetDomainErrorCode(err) != ErrorCompetitorExists {
+		t.Fatalf("update to duplicate: expected %s, got %v", ErrorCompetitorExists, err)
+	}
+	// Собственный домен сайта и пустой домен конкурентом быть не могут
+	for _, domain := range []string{"OWN.com", " "} {
+		if _, err := uc.CreateCompetitor(intPtr(1), 1, domain); GetDomainErrorCode(err) != ErrorValidation {
+			t.Fatalf("create %q: expected %s, got %v", domain, ErrorValidation, err)
+		}
+		if _, err := uc.UpdateCompetitor(intPtr(1), 1, other.ID, domain); GetDomainErrorCode(err) != ErrorValidation {
+			t.Fatalf("update %q: expected %s, got %v", domain, ErrorValidation, err)
+		}
+	}
+
+	updated, err := uc.UpdateCompetitor(intPtr(1), 1, other.ID, "Renamed.com")
+	if err != nil || updated.Domain != "renamed.com" {
+		t.Fatalf("expected renamed competitor, got %+v, %v", updated, err)
+	}
+
+	if err := uc.DeleteCompetitor(intPtr(1), 1, rival.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(positions.deleted) != 1 || positions.deleted[0] != rival.ID {
+		t.Fatalf("expected positions of competitor %d to be deleted, got %v", rival.ID, positions.deleted)
+	}
+
+	competitors, err := uc.GetCompetitorsBySite(intPtr(1), 1)
+	if err != nil || len(competitors) != 1 || competitors[0].Domain != "renamed.com" {
+		t.Fatalf("expected only the renamed competitor, got %+v, %v", competitors, err)
+	}
+}
+
+func TestCompetitorScope(t *testing.T) {
+	uc, repo, positions := newCompetitorUseCase()
+	foreign, err := uc.CreateCompetitor(intPtr(2), 2, "rival.com")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// Сайт другого пространства не виден
+	if _, err := uc.CreateCompetitor(intPtr(1), 2, "new.com"); GetDomainErrorCode(err) != ErrorSiteNotFound {
+		t.Fatalf("create: expected %s, got %v", ErrorSiteNotFound, err)
+	}
+	if _, err := uc.GetCompetitorsBySite(intPtr(1), 2); GetDomainErrorCode(err) != ErrorSiteNotFound {
+		t.Fatalf("list: expected %s, got %v", ErrorSiteNotFound, err)
+	}
+	if _, err := uc.UpdateCompetitor(intPtr(1), 2, foreign.ID, "new.com"); GetDomainErrorCode(err) != ErrorSiteNotFound {
+		t.Fatalf("update: expected %s, got %v", ErrorSiteNotFound, err)
+	}
+	if err := uc.DeleteCompetitor(intPtr(1), 2, foreign.ID); GetDomainErrorCode(err) != ErrorSiteNotFound {
+		t.Fatalf("delete: expected %s, got %v", ErrorSiteNotFound, err)
+	}
+
+	// Конкурент другого сайта через свой сайт тоже не виден
+	for _, id := range []int{foreign.ID, 99} {
+		if _, err := uc.UpdateCompetitor(intPtr(1), 1, id, "new.com"); GetDomainErrorCode(err) != ErrorCompetitorNotFound {
+			t.Fatalf("update %d: expected %s, got %v", id, ErrorCompetitorNotFound, err)
+		}
+		if err := uc.DeleteCompetitor(intPtr(1), 1, id); GetDomainErrorCode(err) != ErrorCompetitorNotFound {
+			t.Fatalf("delete %d: expected %s, got %v", id, ErrorCompetitorNotFound, err)
+		}
+	}
+
+	if competitor, _ := repo.GetByID(foreign.ID); competitor.Domain != "rival.com" || len(positions.deleted) != 0 {
+		t.Fatalf("foreign competitor must stay untouched, got %+v and deleted positions %v", competitor, positions.deleted)
+	}
+}
+
+func TestSaveCompetitorPositions(t *testing.T) {
+	sitePosition := &entities.Position{
+		ID: 7, KeywordID: 3, SiteID: 1, Rank: 4, URL: "https://own.com/", Title: "own",
+		Source: entities.GoogleSearch, Device: "desktop", Pages: 2,
+	}
+	competitors := []*entities.Competitor{{ID: 10, Domain: "found.com"}, {ID: 11, Domain: "lost.com"}, {ID: 12, Domain: "missing.com"}}
+	results := []domainservices.DomainPosition{
+		{Domain: "found.com", Position: 2, URL: "https://found.com/", Title: "found"},
+		{Domain: "lost.com"},
+	}
+
+	tests := []struct {
+		name    string
+		partial bool
+		want    map[int]int // конкурент -> позиция
+	}{
+		{name: "full search", want: map[int]int{10: 2, 11: 0}},
+		// По части страниц не найденный конкурент мог быть дальше: его позиция не записывается
+		{name: "partial search", partial: true, want: map[int]int{10: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePositionRepository{}
+			saveCompetitorPositions(repo, sitePosition, competitors, results, tt.partial)
+
+			if len(repo.saved) != len(tt.want) {
+				t.Fatalf("expected %d competitor positions, got %+v", len(tt.want), repo.saved)
+			}
+			for _, position := range repo.saved {
+				rank, ok := tt.want[*position.CompetitorID]
+				if !ok || position.Rank != rank || position.ID != 0 || position.KeywordID != 3 || position.Source != entities.GoogleSearch || position.Pages != 2 {
+					t.Fatalf("unexpected competitor position %+v", position)
+				}
+			}
+			if found := repo.saved[0]; found.URL != "https://found.com/" || found.Title != "found" {
+				t.Fatalf("expected the competitor url and title, got %+v", found)
+			}
+		})
+	}
+	if sitePosition.CompetitorID != nil || sitePosition.Rank != 4 {
+		t.Fatalf("site position must stay unchanged, got %+v", sitePosition)
+	}
+}
+
+func TestProcessJobSavesCompetitorPositions(t *testing.T) {
+	h := newTrackingHarness(t, 1, 1, 1)
+	h.uc.competitorRepo = &memCompetitorRepository{competitors: map[int]*entities.Competitor{
+		1: {ID: 1, SiteID: 1, Domain: "rival.com"},
+		2: {ID: 2, SiteID: 2, Domain: "foreign.com"},
+	}, nextID: 2}
+	h.provider.search = func(ctx context.Context, req domainservices.SearchRequest) (*domainservices.SitePositionResult, error) {
+		return &domainservices.SitePositionResult{
+			Position: 3, Requests: 1,
+			Competitors: []domainservices.DomainPosition{{Domain: "rival.com", Position: 1, URL: "https://rival.com/"}},
+		}, nil
+	}
+	h.enqueue(t, 1)
+	h.process(t, "worker")
+
+	if len(h.positions.saved) != 2 {
+		t.Fatalf("expected site and competitor positions, got %+v", h.positions.saved)
+	}
+	site, competitor := h.positions.saved[0], h.positions.saved[1]
+	if site.CompetitorID != nil || site.Rank != 3 {
+		t.Fatalf("unexpected site position %+v", site)
+	}
+	if competitor.CompetitorID == nil || *competitor.CompetitorID != 1 || competitor.Rank != 1 || competitor.URL != "https://rival.com/" ||
+		competitor.KeywordID != site.KeywordID || competitor.Source != entities.GoogleSearch {
+		t.Fatalf("unexpected competitor position %+v", competitor)
+	}
+}
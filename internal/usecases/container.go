@@ -1,6 +1,7 @@
 package usecases
 
 import (
+	domainservices "go-seo/internal/domain/services"
 	"go-seo/internal/infrastructure/services"
 	"go-seo/internal/repositories"
 )
@@ -9,20 +10,54 @@ type Container struct {
 	Site                  *SiteUseCase
 	Keyword               *KeywordUseCase
 	Group                 *GroupUseCase
+	Tag                   *TagUseCase
+	KeywordSuggestion     *KeywordSuggestionUseCase
+	KeywordCluster        *KeywordClusterUseCase
 	PositionTracking      *PositionTrackingUseCase
+	PositionRetention     *PositionRetentionUseCase
+	Visibility            *VisibilityUseCase
+	Movement              *MovementUseCase
+	LandingPage           *LandingPageUseCase
 	AsyncPositionTracking *AsyncPositionTrackingUseCase
 	TrackingJob           *TrackingJobUseCase
+	Provider              *ProviderUseCase
+	SerpSnapshot          *SerpSnapshotUseCase
+	Competitor            *CompetitorUseCase
+	TrackingSchedule      *TrackingScheduleUseCase
+	ProviderAccount       *ProviderAccountUseCase
+	APIKey                *APIKeyUseCase
+	Workspace             *WorkspaceUseCase
+	Usage                 *UsageUseCase
 	Debug                 *DebugUseCase
 }
 
-func NewContainer(repos *repositories.Container, xmlRiver *services.XMLRiverService, xmlStock *services.XMLRiverService, wordstat *services.WordstatService, kafkaService *services.KafkaService, idGenerator *services.IDGeneratorService, retryService *services.RetryService, workerCount int, batchSize int, xmlRiverSoftID string, xmlStockSoftID string) *Container {
+func NewContainer(repos *repositories.Container, providers domainservices.SearchProviderRegistry, limiter domainservices.RateLimiter, cipher domainservices.SecretCipher, wordstat *services.WordstatService, kafkaService *services.KafkaService, idGenerator *services.IDGeneratorService, retryService *services.RetryService, retention PositionRetentionSettings, workerCount int, batchSize int) *Container {
+	providerAccount := NewProviderAccountUseCase(repos.Account, providers, cipher)
+	movement := NewMovementUseCase(repos.Movement, repos.Site)
+	asyncPositionTracking := NewAsyncPositionTrackingUseCase(repos.Site, repos.Keyword, repos.Position, repos.TrackingJob, repos.TrackingTask, repos.TrackingResult, repos.SerpSnapshot, repos.Competitor, repos.Usage, repos.Workspace, providerAccount, movement, providers, wordstat, kafkaService, idGenerator, retryService, workerCount, batchSize)
+
 	return &Container{
-		Site:                  NewSiteUseCase(repos.Site, repos.Position, repos.Keyword, repos.Group, repos.TrackingJob, repos.TrackingTask, repos.TrackingResult),
-		Keyword:               NewKeywordUseCase(repos.Keyword, repos.Position),
-		Group:                 NewGroupUseCase(repos.Group),
-		PositionTracking:      NewPositionTrackingUseCase(repos.Site, repos.Keyword, repos.Position, xmlRiver, xmlStock, wordstat, xmlRiverSoftID, xmlStockSoftID),
-		AsyncPositionTracking: NewAsyncPositionTrackingUseCase(repos.Site, repos.Keyword, repos.Position, repos.TrackingJob, repos.TrackingTask, repos.TrackingResult, xmlRiver, xmlStock, wordstat, kafkaService, idGenerator, retryService, workerCount, batchSize, xmlRiverSoftID, xmlStockSoftID),
-		TrackingJob:           NewTrackingJobUseCase(repos.TrackingJob),
+		Site:                  NewSiteUseCase(repos.Site, repos.Position, repos.Keyword, repos.Group, repos.TrackingJob, repos.TrackingTask, repos.TrackingResult, repos.SerpSnapshot, repos.Competitor, repos.Schedule),
+		Keyword:               NewKeywordUseCase(repos.Keyword, repos.Site, repos.Position, repos.SerpSnapshot, repos.Group, repos.Tag, services.NewSpreadsheetReader(), services.NewKeywordNormalizer()),
+		Group:                 NewGroupUseCase(repos.Group, repos.Site),
+		Tag:                   NewTagUseCase(repos.Tag, repos.Keyword, repos.Site),
+		KeywordSuggestion:     NewKeywordSuggestionUseCase(repos.Suggestion, repos.Keyword, repos.Group, repos.Site, repos.Usage, repos.Workspace, providerAccount, wordstat, services.NewKeywordNormalizer()),
+		KeywordCluster:        NewKeywordClusterUseCase(repos.Cluster, repos.Keyword, repos.Site),
+		PositionTracking:      NewPositionTrackingUseCase(repos.Site, repos.Keyword, repos.Position, repos.SerpSnapshot, repos.Competitor, repos.Usage, providerAccount, providers, wordstat, workerCount),
+		PositionRetention:     NewPositionRetentionUseCase(repos.Retention, retention),
+		Visibility:            NewVisibilityUseCase(repos.Visibility, repos.Site),
+		Movement:              movement,
+		LandingPage:           NewLandingPageUseCase(repos.LandingPage, repos.Site),
+		AsyncPositionTracking: asyncPositionTracking,
+		TrackingJob:           NewTrackingJobUseCase(repos.TrackingJob, repos.TrackingTask, repos.Usage),
+		Provider:              NewProviderUseCase(providers, limiter, repos.Account),
+		SerpSnapshot:          NewSerpSnapshotUseCase(repos.SerpSnapshot),
+		Competitor:            NewCompetitorUseCase(repos.Competitor, repos.Site, repos.Position),
+		TrackingSchedule:      NewTrackingScheduleUseCase(repos.Schedule, repos.Site, repos.TrackingJob, asyncPositionTracking),
+		ProviderAccount:       providerAccount,
+		APIKey:                NewAPIKeyUseCase(repos.APIKey),
+		Workspace:             NewWorkspaceUseCase(repos.Workspace, repos.Site, repos.Account, repos.APIKey),
+		Usage:                 NewUsageUseCase(repos.Usage),
 		Debug:                 NewDebugUseCase(kafkaService),
 	}
 }
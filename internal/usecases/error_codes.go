@@ -6,6 +6,10 @@ const (
 	ErrorSiteCreation = "SITE_CREATION_FAILED"
 	ErrorSiteDeletion = "SITE_DELETION_FAILED"
 	ErrorSiteFetch    = "SITE_FETCH_FAILED"
+	ErrorSiteUpdate   = "SITE_UPDATE_FAILED"
+
+	ErrorBudgetExceeded = "BUDGET_EXCEEDED"
+	ErrorUsageFetch     = "USAGE_FETCH_FAILED"
 
 	ErrorKeywordExists   = "KEYWORD_EXISTS"
 	ErrorKeywordNotFound = "KEYWORD_NOT_FOUND"
@@ -13,10 +17,24 @@ const (
 	ErrorKeywordUpdate   = "KEYWORD_UPDATE_FAILED"
 	ErrorKeywordDeletion = "KEYWORD_DELETION_FAILED"
 	ErrorKeywordFetch    = "KEYWORD_FETCH_FAILED"
+	ErrorKeywordImport   = "KEYWORD_IMPORT_FAILED"
+	ErrorKeywordMerge    = "KEYWORD_MERGE_FAILED"
+
+	ErrorSuggestionNotFound = "KEYWORD_SUGGESTION_NOT_FOUND"
+	ErrorSuggestionCollect  = "KEYWORD_SUGGESTION_COLLECT_FAILED"
+	ErrorSuggestionFetch    = "KEYWORD_SUGGESTION_FETCH_FAILED"
+	ErrorSuggestionAccept   = "KEYWORD_SUGGESTION_ACCEPT_FAILED"
+	ErrorSuggestionDeletion = "KEYWORD_SUGGESTION_DELETION_FAILED"
 
 	ErrorPositionCreation = "POSITION_CREATION_FAILED"
 	ErrorPositionDeletion = "POSITION_DELETION_FAILED"
 	ErrorPositionFetch    = "POSITION_FETCH_FAILED"
+	ErrorVisibilityFetch  = "VISIBILITY_FETCH_FAILED"
+	ErrorMovementFetch    = "MOVEMENT_FETCH_FAILED"
+	ErrorLandingPageFetch = "LANDING_PAGE_FETCH_FAILED"
+
+	ErrorSerpSnapshotNotFound = "SERP_SNAPSHOT_NOT_FOUND"
+	ErrorSerpSnapshotFetch    = "SERP_SNAPSHOT_FETCH_FAILED"
 
 	ErrorGroupExists   = "GROUP_EXISTS"
 	ErrorGroupNotFound = "GROUP_NOT_FOUND"
@@ -24,6 +42,64 @@ const (
 	ErrorGroupDeletion = "GROUP_DELETION_FAILED"
 	ErrorGroupFetch    = "GROUP_FETCH_FAILED"
 
+	ErrorClusterNotFound = "KEYWORD_CLUSTER_NOT_FOUND"
+	ErrorClusterCreation = "KEYWORD_CLUSTER_CREATION_FAILED"
+	ErrorClusterFetch    = "KEYWORD_CLUSTER_FETCH_FAILED"
+	ErrorClusterApply    = "KEYWORD_CLUSTER_APPLY_FAILED"
+
+	ErrorTagExists   = "TAG_EXISTS"
+	ErrorTagNotFound = "TAG_NOT_FOUND"
+	ErrorTagCreation = "TAG_CREATION_FAILED"
+	ErrorTagUpdate   = "TAG_UPDATE_FAILED"
+	ErrorTagDeletion = "TAG_DELETION_FAILED"
+	ErrorTagFetch    = "TAG_FETCH_FAILED"
+	ErrorTagAssign   = "TAG_ASSIGN_FAILED"
+
+	ErrorCompetitorExists   = "COMPETITOR_EXISTS"
+	ErrorCompetitorNotFound = "COMPETITOR_NOT_FOUND"
+	ErrorCompetitorCreation = "COMPETITOR_CREATION_FAILED"
+	ErrorCompetitorUpdate   = "COMPETITOR_UPDATE_FAILED"
+	ErrorCompetitorDeletion = "COMPETITOR_DELETION_FAILED"
+	ErrorCompetitorFetch    = "COMPETITOR_FETCH_FAILED"
+
+	ErrorScheduleNotFound = "SCHEDULE_NOT_FOUND"
+	ErrorScheduleCreation = "SCHEDULE_CREATION_FAILED"
+	ErrorScheduleUpdate   = "SCHEDULE_UPDATE_FAILED"
+	ErrorScheduleDeletion = "SCHEDULE_DELETION_FAILED"
+	ErrorScheduleFetch    = "SCHEDULE_FETCH_FAILED"
+
+	ErrorJobNotFound      = "JOB_NOT_FOUND"
+	ErrorJobStateConflict = "JOB_STATE_CONFLICT"
+	ErrorJobUpdate        = "JOB_UPDATE_FAILED"
+	ErrorJobFetch         = "JOB_FETCH_FAILED"
+
+	ErrorProviderNotFound    = "PROVIDER_NOT_FOUND"
+	ErrorProviderUnsupported = "PROVIDER_UNSUPPORTED"
+
+	ErrorAccountExists           = "PROVIDER_ACCOUNT_EXISTS"
+	ErrorAccountNotFound         = "PROVIDER_ACCOUNT_NOT_FOUND"
+	ErrorAccountCreation         = "PROVIDER_ACCOUNT_CREATION_FAILED"
+	ErrorAccountUpdate           = "PROVIDER_ACCOUNT_UPDATE_FAILED"
+	ErrorAccountDeletion         = "PROVIDER_ACCOUNT_DELETION_FAILED"
+	ErrorAccountFetch            = "PROVIDER_ACCOUNT_FETCH_FAILED"
+	ErrorEncryptionNotConfigured = "ENCRYPTION_NOT_CONFIGURED"
+
+	ErrorWorkspaceExists   = "WORKSPACE_EXISTS"
+	ErrorWorkspaceNotFound = "WORKSPACE_NOT_FOUND"
+	ErrorWorkspaceNotEmpty = "WORKSPACE_NOT_EMPTY"
+	ErrorWorkspaceCreation = "WORKSPACE_CREATION_FAILED"
+	ErrorWorkspaceUpdate   = "WORKSPACE_UPDATE_FAILED"
+	ErrorWorkspaceDeletion = "WORKSPACE_DELETION_FAILED"
+	ErrorWorkspaceFetch    = "WORKSPACE_FETCH_FAILED"
+
+	ErrorUnauthorized   = "UNAUTHORIZED"
+	ErrorForbidden      = "FORBIDDEN"
+	ErrorAPIKeyNotFound = "API_KEY_NOT_FOUND"
+	ErrorAPIKeyRevoked  = "API_KEY_REVOKED"
+	ErrorAPIKeyCreation = "API_KEY_CREATION_FAILED"
+	ErrorAPIKeyRevoke   = "API_KEY_REVOKE_FAILED"
+	ErrorAPIKeyFetch    = "API_KEY_FETCH_FAILED"
+
 	ErrorValidation = "VALIDATION_ERROR"
 	ErrorInternal   = "INTERNAL_ERROR"
 )
@@ -8,15 +8,21 @@ import (
 
 type GroupUseCase struct {
 	groupRepo repositories.GroupRepository
+	siteRepo  repositories.SiteRepository
 }
 
-func NewGroupUseCase(groupRepo repositories.GroupRepository) *GroupUseCase {
+func NewGroupUseCase(groupRepo repositories.GroupRepository, siteRepo repositories.SiteRepository) *GroupUseCase {
 	return &GroupUseCase{
 		groupRepo: groupRepo,
+		siteRepo:  siteRepo,
 	}
 }
 
-func (uc *GroupUseCase) CreateGroup(name string, siteID int) (*entities.Group, error) {
+func (uc *GroupUseCase) CreateGroup(workspaceID *int, name string, siteID int) (*entities.Group, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
 	group := &entities.Group{
 		Name:   name,
 		SiteID: siteID,
@@ -49,14 +55,10 @@ func (uc *GroupUseCase) CreateGroup(name string, siteID int) (*entities.Group, e
 	return group, nil
 }
 
-func (uc *GroupUseCase) UpdateGroup(id int, name string) (*entities.Group, error) {
-	group, err := uc.groupRepo.GetByID(id)
+func (uc *GroupUseCase) UpdateGroup(workspaceID *int, id int, name string) (*entities.Group, error) {
+	group, err := uc.getGroup(workspaceID, id)
 	if err != nil {
-		return nil, &DomainError{
-			Code:    ErrorGroupNotFound,
-			Message: "Group not found",
-			Err:     err,
-		}
+		return nil, err
 	}
 
 	group.Name = name
@@ -71,14 +73,9 @@ func (uc *GroupUseCase) UpdateGroup(id int, name string) (*entities.Group, error
 	return group, nil
 }
 
-func (uc *GroupUseCase) DeleteGroup(id int) error {
-	_, err := uc.groupRepo.GetByID(id)
-	if err != nil {
-		return &DomainError{
-			Code:    ErrorGroupNotFound,
-			Message: "Group not found",
-			Err:     err,
-		}
+func (uc *GroupUseCase) DeleteGroup(workspaceID *int, id int) error {
+	if _, err := uc.getGroup(workspaceID, id); err != nil {
+		return err
 	}
 
 	if err := uc.groupRepo.Delete(id); err != nil {
@@ -92,7 +89,11 @@ func (uc *GroupUseCase) DeleteGroup(id int) error {
 	return nil
 }
 
-func (uc *GroupUseCase) GetGroupsBySite(siteID int) ([]*entities.Group, error) {
+func (uc *GroupUseCase) GetGroupsBySite(workspaceID *int, siteID int) ([]*entities.Group, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
 	groups, err := uc.groupRepo.GetAllBySite(siteID)
 	if err != nil {
 		return nil, &DomainError{
@@ -104,3 +105,20 @@ func (uc *GroupUseCase) GetGroupsBySite(siteID int) ([]*entities.Group, error) {
 
 	return groups, nil
 }
+
+// getGroup возвращает группу, если ее сайт доступен в области запроса
+func (uc *GroupUseCase) getGroup(workspaceID *int, id int) (*entities.Group, error) {
+	group, err := uc.groupRepo.GetByID(id)
+	if err == nil && workspaceID != nil {
+		_, err = authorizeSite(uc.siteRepo, workspaceID, group.SiteID)
+	}
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorGroupNotFound,
+			Message: "Group not found",
+			Err:     err,
+		}
+	}
+
+	return group, nil
+}
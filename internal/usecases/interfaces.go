@@ -6,25 +6,45 @@ import (
 )
 
 type SiteUseCaseInterface interface {
-	CreateSite(domain string) (*entities.Site, error)
-	DeleteSite(id int) error
-	GetAllSites() ([]*entities.Site, error)
-	GetSitesByIDs(ids []int) ([]*entities.Site, error)
+	CreateSite(workspaceID *int, domain string) (*entities.Site, error)
+	DeleteSite(workspaceID *int, id int) error
+	GetAllSites(workspaceID *int) ([]*entities.Site, error)
+	GetSitesByIDs(workspaceID *int, ids []int) ([]*entities.Site, error)
 	GetKeywordsCount(siteID int) (int, error)
 	GetLastPositionUpdateDate(siteID int) (*time.Time, error)
+	SetBudget(workspaceID *int, id int, monthlyBudget *float64, action string) (*entities.Site, error)
 }
 
 type KeywordUseCaseInterface interface {
-	CreateKeyword(value string, siteID int, groupID *int) (*entities.Keyword, error)
-	CreateKeywordsBatch(keywords []*entities.Keyword) ([]*entities.Keyword, []error)
-	UpdateKeyword(id int, groupID *int) (*entities.Keyword, error)
-	DeleteKeyword(id int) error
-	GetKeywordsBySite(siteID int) ([]*entities.Keyword, error)
+	CreateKeyword(workspaceID *int, value string, siteID int, groupID *int) (*entities.Keyword, error)
+	CreateKeywordsBatch(workspaceID *int, keywords []*entities.Keyword) ([]*entities.Keyword, []error)
+	UpdateKeyword(workspaceID *int, id int, groupID *int) (*entities.Keyword, error)
+	DeleteKeyword(workspaceID *int, id int) error
+	GetKeywordsBySite(workspaceID *int, siteID int, tags *entities.TagFilter) ([]*entities.Keyword, error)
+	ImportKeywords(workspaceID *int, siteID int, filename string, data []byte, options KeywordImportOptions) (*entities.KeywordImportResult, error)
+	GetNearDuplicates(workspaceID *int, siteID int, ignoreStopWords, ignoreWordOrder bool, page, perPage int) ([]*entities.KeywordDuplicateGroup, int64, error)
+	MergeKeywords(workspaceID *int, targetID int, sourceIDs []int) (*entities.Keyword, error)
 }
 
 type GroupUseCaseInterface interface {
-	CreateGroup(name string, siteID int) (*entities.Group, error)
-	UpdateGroup(id int, name string) (*entities.Group, error)
-	DeleteGroup(id int) error
-	GetGroupsBySite(siteID int) ([]*entities.Group, error)
+	CreateGroup(workspaceID *int, name string, siteID int) (*entities.Group, error)
+	UpdateGroup(workspaceID *int, id int, name string) (*entities.Group, error)
+	DeleteGroup(workspaceID *int, id int) error
+	GetGroupsBySite(workspaceID *int, siteID int) ([]*entities.Group, error)
+}
+
+type TagUseCaseInterface interface {
+	CreateTag(workspaceID *int, siteID int, name string) (*entities.Tag, error)
+	UpdateTag(workspaceID *int, id int, name string) (*entities.Tag, error)
+	DeleteTag(workspaceID *int, id int) error
+	GetTagsBySite(workspaceID *int, siteID int) ([]*entities.Tag, error)
+	AssignTags(workspaceID *int, keywordIDs, tagIDs []int) (int, error)
+	UnassignTags(workspaceID *int, keywordIDs, tagIDs []int) (int, error)
+}
+
+type CompetitorUseCaseInterface interface {
+	CreateCompetitor(workspaceID *int, siteID int, domain string) (*entities.Competitor, error)
+	GetCompetitorsBySite(workspaceID *int, siteID int) ([]*entities.Competitor, error)
+	UpdateCompetitor(workspaceID *int, siteID, id int, domain string) (*entities.Competitor, error)
+	DeleteCompetitor(workspaceID *int, siteID, id int) error
 }
@@ -0,0 +1,280 @@
+package usecases
+
+import (
+	"fmt"
+	"sort"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+)
+
+const defaultClusterThreshold = 4
+
+type KeywordClusterUseCase struct {
+	clusterRepo repositories.KeywordClusterRepository
+	keywordRepo repositories.KeywordRepository
+	siteRepo    repositories.SiteRepository
+}
+
+func NewKeywordClusterUseCase(clusterRepo repositories.KeywordClusterRepository, keywordRepo repositories.KeywordRepository, siteRepo repositories.SiteRepository) *KeywordClusterUseCase {
+	return &KeywordClusterUseCase{
+		clusterRepo: clusterRepo,
+		keywordRepo: keywordRepo,
+		siteRepo:    siteRepo,
+	}
+}
+
+// ClusterKeywords группирует слова сайта по общим URL в топ-10 последней сохраненной выдачи источника
+// и заменяет ими прежние кластеры сайта. threshold - сколько общих URL нужно (0 - 4), mode - soft или hard
+// (пусто - soft). Слова без похожих в кластеры не попадают
+func (uc *KeywordClusterUseCase) ClusterKeywords(workspaceID *int, siteID int, source string, filterGroupID *int, threshold int, mode string) (*entities.KeywordClusterResult, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
+	if source != entities.GoogleSearch && source != entities.YandexSearch {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: fmt.Sprintf("source must be %q or %q", entities.GoogleSearch, entities.YandexSearch),
+		}
+	}
+	if threshold == 0 {
+		threshold = defaultClusterThreshold
+	}
+	if threshold < 1 || threshold > entities.ClusterDepth {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: fmt.Sprintf("threshold must be from 1 to %d", entities.ClusterDepth),
+		}
+	}
+	if mode == "" {
+		mode = entities.ClusterModeSoft
+	}
+	if mode != entities.ClusterModeSoft && mode != entities.ClusterModeHard {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: fmt.Sprintf("mode must be %q or %q", entities.ClusterModeSoft, entities.ClusterModeHard),
+		}
+	}
+
+	keywords, err := uc.keywordRepo.GetBySiteID(siteID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorKeywordFetch,
+			Message: "Failed to fetch keywords",
+			Err:     err,
+		}
+	}
+	serps, err := uc.clusterRepo.GetTopURLs(siteID, source, filterGroupID, entities.ClusterDepth)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorClusterFetch,
+			Message: "Failed to fetch SERP snapshots",
+			Err:     err,
+		}
+	}
+	if len(serps) == 0 {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: fmt.Sprintf("Site has no saved %s SERP snapshots, track positions with SERP snapshots first", source),
+		}
+	}
+
+	values := make(map[int]string, len(keywords))
+	for _, keyword := range keywords {
+		values[keyword.ID] = keyword.Value
+	}
+
+	result := &entities.KeywordClusterResult{
+		Keywords:    len(serps),
+		WithoutSerp: len(keywords) - len(serps),
+	}
+	clustered := 0
+	for _, members := range clusterSerps(serps, threshold, mode == entities.ClusterModeHard) {
+		marker := members[0]
+		result.Clusters = append(result.Clusters, &entities.KeywordCluster{
+			SiteID:          siteID,
+			Source:          source,
+			FilterGroupID:   filterGroupID,
+			Mode:            mode,
+			Threshold:       threshold,
+			Name:            values[marker],
+			MarkerKeywordID: &marker,
+			KeywordIDs:      members,
+		})
+		clustered += len(members)
+	}
+	result.Unclustered = len(serps) - clustered
+
+	if err := uc.clusterRepo.Replace(siteID, result.Clusters); err != nil {
+		return nil, &DomainError{
+			Code:    ErrorClusterCreation,
+			Message: "Failed to save keyword clusters",
+			Err:     err,
+		}
+	}
+
+	return result, nil
+}
+
+// GetClusters кластеры последней кластеризации сайта по убыванию размера
+func (uc *KeywordClusterUseCase) GetClusters(workspaceID *int, siteID int, page, perPage int) ([]*entities.KeywordCluster, int64, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, 0, err
+	}
+
+	page, perPage = normalizePage(page, perPage)
+	clusters, total, err := uc.clusterRepo.GetBySite(siteID, (page-1)*perPage, perPage)
+	if err != nil {
+		return nil, 0, &DomainError{
+			Code:    ErrorClusterFetch,
+			Message: "Failed to fetch keyword clusters",
+			Err:     err,
+		}
+	}
+
+	return clusters, total, nil
+}
+
+// ApplyClusters переносит слова кластеров в группы с именами кластеров, создавая недостающие группы.
+// Без clusterIDs применяются все кластеры сайта
+func (uc *KeywordClusterUseCase) ApplyClusters(workspaceID *int, siteID int, clusterIDs []int) (*entities.KeywordClusterApplyResult, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
+	if len(clusterIDs) > 0 {
+		clusterIDs = uniqueIDs(clusterIDs)
+		clusters, err := uc.clusterRepo.GetByIDs(clusterIDs)
+		if err != nil {
+			return nil, &DomainError{
+				Code:    ErrorClusterFetch,
+				Message: "Failed to fetch keyword clusters",
+				Err:     err,
+			}
+		}
+		for _, cluster := range clusters {
+			if cluster.SiteID != siteID {
+				clusters = nil
+				break
+			}
+		}
+		if len(clusters) != len(clusterIDs) {
+			return nil, &DomainError{
+				Code:    ErrorClusterNotFound,
+				Message: "Keyword cluster not found",
+			}
+		}
+	} else {
+		clusterIDs = nil
+	}
+
+	result, err := uc.clusterRepo.Apply(siteID, clusterIDs)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorClusterApply,
+			Message: "Failed to apply keyword clusters",
+			Err:     err,
+		}
+	}
+
+	return result, nil
+}
+
+// clusterSerps разбивает слова на кластеры по числу общих URL выдачи. Маркерами по очереди становятся слова
+// с наибольшим числом похожих (при равенстве - с меньшим ID). В soft-режиме к маркеру добавляются все
+// свободные слова, похожие на него, в hard-режиме - только похожие на каждое уже добавленное слово.
+// Возвращает ID слов кластеров, маркер первым; кластеры из одного слова не создаются
+func clusterSerps(serps []*entities.KeywordSerp, threshold int, hard bool) [][]int {
+	urls := make([]map[string]bool, len(serps))
+	byURL := make(map[string][]int)
+	for i, serp := range serps {
+		urls[i] = make(map[string]bool, len(serp.URLs))
+		for _, rawURL := range serp.URLs {
+			url := normalizeLandingURL(rawURL)
+			if !urls[i][url] {
+				urls[i][url] = true
+				byURL[url] = append(byURL[url], i)
+			}
+		}
+	}
+
+	// similar[i][j] - число общих URL, только для пар не ниже порога
+	similar := make([]map[int]int, len(serps))
+	for i := range serps {
+		shared := make(map[int]int)
+		for url := range urls[i] {
+			for _, j := range byURL[url] {
+				if j != i {
+					shared[j]++
+				}
+			}
+		}
+		similar[i] = make(map[int]int)
+		for j, count := range shared {
+			if count >= threshold {
+				similar[i][j] = count
+			}
+		}
+	}
+
+	order := make([]int, len(serps))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		if len(similar[order[a]]) != len(similar[order[b]]) {
+			return len(similar[order[a]]) > len(similar[order[b]])
+		}
+		return serps[order[a]].KeywordID < serps[order[b]].KeywordID
+	})
+
+	assigned := make([]bool, len(serps))
+	var clusters [][]int
+	for _, marker := range order {
+		if assigned[marker] {
+			continue
+		}
+
+		var candidates []int
+		for j := range similar[marker] {
+			if !assigned[j] {
+				candidates = append(candidates, j)
+			}
+		}
+		sort.Slice(candidates, func(a, b int) bool {
+			if similar[marker][candidates[a]] != similar[marker][candidates[b]] {
+				return similar[marker][candidates[a]] > similar[marker][candidates[b]]
+			}
+			return serps[candidates[a]].KeywordID < serps[candidates[b]].KeywordID
+		})
+
+		members := []int{marker}
+		for _, candidate := range candidates {
+			if hard && !similarToAll(similar[candidate], members) {
+				continue
+			}
+			members = append(members, candidate)
+		}
+		if len(members) < 2 {
+			continue
+		}
+
+		ids := make([]int, len(members))
+		for i, member := range members {
+			assigned[member] = true
+			ids[i] = serps[member].KeywordID
+		}
+		clusters = append(clusters, ids)
+	}
+	return clusters
+}
+
+func similarToAll(similar map[int]int, members []int) bool {
+	for _, member := range members {
+		if _, ok := similar[member]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,210 @@
+package usecases
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go-seo/internal/domain/entities"
+)
+
+func keywordSerp(keywordID int, urls ...string) *entities.KeywordSerp {
+	return &entities.KeywordSerp{KeywordID: keywordID, URLs: urls}
+}
+
+// topURLs возвращает count URL вида site.ru/<prefix><n>
+func topURLs(prefix string, count int) []string {
+	urls := make([]string, count)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("site.ru/%s%d", prefix, i+1)
+	}
+	return urls
+}
+
+func TestClusterSerps(t *testing.T) {
+	identical := topURLs("u", entities.ClusterDepth)
+	almost := append(topURLs("u", entities.ClusterDepth-1), "other.ru/page")
+
+	tests := []struct {
+		name      string
+		serps     []*entities.KeywordSerp
+		threshold int
+		hard      bool
+		want      [][]int
+	}{
+		{
+			name:      "empty input",
+			threshold: 1,
+		},
+		{
+			name:      "no shared urls",
+			serps:     []*entities.KeywordSerp{keywordSerp(1, "a.ru/1", "a.ru/2"), keywordSerp(2, "b.ru/1", "b.ru/2")},
+			threshold: 1,
+		},
+		{
+			name:      "minimal threshold needs one shared url",
+			serps:     []*entities.KeywordSerp{keywordSerp(1, "a.ru/1", "x.ru/1"), keywordSerp(2, "b.ru/1", "x.ru/1")},
+			threshold: 1,
+			want:      [][]int{{1, 2}},
+		},
+		{
+			name:      "threshold is inclusive",
+			serps:     []*entities.KeywordSerp{keywordSerp(1, topURLs("u", 3)...), keywordSerp(2, append(topURLs("u", 3), "b.ru/1")...)},
+			threshold: 3,
+			want:      [][]int{{1, 2}},
+		},
+		{
+			name:      "overlap below threshold",
+			serps:     []*entities.KeywordSerp{keywordSerp(1, topURLs("u", 3)...), keywordSerp(2, append(topURLs("u", 3), "b.ru/1")...)},
+			threshold: 4,
+		},
+		{
+			name:      "maximal threshold needs the whole top",
+			serps:     []*entities.KeywordSerp{keywordSerp(1, identical...), keywordSerp(2, identical...), keywordSerp(3, almost...)},
+			threshold: entities.ClusterDepth,
+			want:      [][]int{{1, 2}},
+		},
+		{
+			name: "urls are normalized and counted once per keyword",
+			serps: []*entities.KeywordSerp{
+				keywordSerp(1, "https://www.site.ru/page/", "site.ru/page", "site.ru/other"),
+				keywordSerp(2, "http://site.ru/page", "https://site.ru/other/"),
+				keywordSerp(3, "site.ru/page", "site.ru/page"),
+			},
+			threshold: 2,
+			want:      [][]int{{1, 2}},
+		},
+		{
+			name: "singletons are dropped",
+			serps: []*entities.KeywordSerp{
+				keywordSerp(1, topURLs("u", 4)...),
+				keywordSerp(2, topURLs("u", 4)...),
+				keywordSerp(3, topURLs("z", 4)...),
+			},
+			threshold: 4,
+			want:      [][]int{{1, 2}},
+		},
+		{
+			name: "marker is the keyword with most similar ones",
+			serps: []*entities.KeywordSerp{
+				keywordSerp(1, "a.ru/1", "a.ru/2"),
+				keywordSerp(2, "b.ru/1", "b.ru/2"),
+				keywordSerp(9, "a.ru/1", "a.ru/2", "b.ru/1", "b.ru/2"),
+			},
+			threshold: 2,
+			want:      [][]int{{9, 1, 2}},
+		},
+		{
+			name: "marker tie is broken by lower id",
+			serps: []*entities.KeywordSerp{
+				keywordSerp(4, "b.ru/1", "b.ru/2"),
+				keywordSerp(3, "b.ru/1", "b.ru/2"),
+				keywordSerp(2, "a.ru/1", "a.ru/2"),
+				keywordSerp(1, "a.ru/1", "a.ru/2"),
+			},
+			threshold: 2,
+			want:      [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name: "members follow marker by overlap, then by id",
+			serps: []*entities.KeywordSerp{
+				keywordSerp(1, "a.ru/1", "a.ru/2"),
+				keywordSerp(2, "c.ru/1", "c.ru/2"),
+				keywordSerp(5, "a.ru/1", "a.ru/2", "b.ru/1", "b.ru/2", "b.ru/3", "c.ru/1", "c.ru/2"),
+				keywordSerp(9, "b.ru/1", "b.ru/2", "b.ru/3"),
+			},
+			threshold: 2,
+			want:      [][]int{{5, 9, 1, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clusterSerps(tt.serps, tt.threshold, tt.hard)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// Слово 1 похоже на 2, 3 и 4; 2 и 3 похожи друг на друга, 4 похоже только на 1
+func TestClusterSerpsSoftAndHard(t *testing.T) {
+	serps := []*entities.KeywordSerp{
+		keywordSerp(1, "u.ru/1", "u.ru/2", "u.ru/3", "d.ru/1", "d.ru/2", "d.ru/3"),
+		keywordSerp(2, "u.ru/1", "u.ru/2", "u.ru/3"),
+		keywordSerp(3, "u.ru/1", "u.ru/2", "u.ru/3"),
+		keywordSerp(4, "d.ru/1", "d.ru/2", "d.ru/3"),
+	}
+
+	tests := []struct {
+		name string
+		hard bool
+		want [][]int
+	}{
+		// soft: в кластер попадает каждое слово, похожее на маркер
+		{name: "soft", want: [][]int{{1, 2, 3, 4}}},
+		// hard: слово 4 не похоже на 2 и 3 и остается одно, поэтому в кластеры не попадает
+		{name: "hard", hard: true, want: [][]int{{1, 2, 3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clusterSerps(serps, 3, tt.hard)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// Уже распределенные слова не переходят в кластер следующего маркера
+func TestClusterSerpsAssignsKeywordOnce(t *testing.T) {
+	serps := []*entities.KeywordSerp{
+		keywordSerp(1, "a.ru/1", "a.ru/2", "b.ru/1", "b.ru/2"),
+		keywordSerp(2, "a.ru/1", "a.ru/2", "c.ru/1", "c.ru/2"),
+		keywordSerp(3, "b.ru/1", "b.ru/2", "c.ru/1", "c.ru/2"),
+		keywordSerp(4, "c.ru/1", "c.ru/2"),
+	}
+
+	// 2 и 3 похожи на троих, маркер - 2 с меньшим ID
+	want := [][]int{{2, 1, 3, 4}}
+	if got := clusterSerps(serps, 2, false); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// 4 не похоже на 1 и не входит в кластер 2, а похожие на него 2 и 3 уже распределены
+	want = [][]int{{2, 1, 3}}
+	if got := clusterSerps(serps, 2, true); !reflect.DeepEqual(got, want) {
+		t.Fatalf("hard: expected %v, got %v", want, got)
+	}
+}
+
+func TestClusterKeywordsValidation(t *testing.T) {
+	sites, _ := newScopeRepositories()
+	uc := NewKeywordClusterUseCase(nil, nil, sites)
+
+	tests := []struct {
+		name      string
+		source    string
+		threshold int
+		mode      string
+	}{
+		{name: "wordstat source", source: entities.Wordstat, threshold: 4},
+		{name: "negative threshold", source: entities.GoogleSearch, threshold: -1},
+		{name: "threshold above depth", source: entities.GoogleSearch, threshold: entities.ClusterDepth + 1},
+		{name: "unknown mode", source: entities.YandexSearch, threshold: 4, mode: "strict"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.ClusterKeywords(nil, 1, tt.source, nil, tt.threshold, tt.mode); GetDomainErrorCode(err) != ErrorValidation {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+
+	if _, err := uc.ClusterKeywords(intPtr(1), 2, entities.GoogleSearch, nil, 4, ""); GetDomainErrorCode(err) != ErrorSiteNotFound {
+		t.Fatalf("expected site of another workspace to be not found, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"fmt"
+	"sort"
+
+	"go-seo/internal/domain/entities"
+)
+
+// GetNearDuplicates группы слов сайта, совпадающих после нормализации. По выбору не учитываются
+// стоп-слова и порядок слов. Группы упорядочены по самому старому слову
+func (uc *KeywordUseCase) GetNearDuplicates(workspaceID *int, siteID int, ignoreStopWords, ignoreWordOrder bool, page, perPage int) ([]*entities.KeywordDuplicateGroup, int64, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, 0, err
+	}
+
+	keywords, err := uc.keywordRepo.GetBySiteID(siteID)
+	if err != nil {
+		return nil, 0, &DomainError{
+			Code:    ErrorKeywordFetch,
+			Message: "Failed to fetch keywords",
+			Err:     err,
+		}
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		return keywords[i].ID < keywords[j].ID
+	})
+
+	var groups []*entities.KeywordDuplicateGroup
+	byKey := make(map[string]*entities.KeywordDuplicateGroup)
+	for _, keyword := range keywords {
+		key := uc.normalizer.MatchKey(keyword.Value, ignoreStopWords, ignoreWordOrder)
+		group, ok := byKey[key]
+		if !ok {
+			group = &entities.KeywordDuplicateGroup{Key: key}
+			byKey[key] = group
+			groups = append(groups, group)
+		}
+		group.Keywords = append(group.Keywords, keyword)
+	}
+
+	var result []*entities.KeywordDuplicateGroup
+	for _, group := range groups {
+		if len(group.Keywords) > 1 {
+			result = append(result, group)
+		}
+	}
+
+	start, end := pageBounds(len(result), page, perPage)
+	return result[start:end], int64(len(result)), nil
+}
+
+// MergeKeywords объединяет слова sourceIDs со словом targetID: их позиции, снимки выдачи и метки переходят
+// к targetID, сами слова удаляются. Группа targetID не меняется
+func (uc *KeywordUseCase) MergeKeywords(workspaceID *int, targetID int, sourceIDs []int) (*entities.Keyword, error) {
+	target, err := uc.getKeyword(workspaceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool, len(sourceIDs))
+	ids := make([]int, 0, len(sourceIDs))
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return nil, &DomainError{
+				Code:    ErrorValidation,
+				Message: "source_ids must not contain target_id",
+			}
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "source_ids must not be empty",
+		}
+	}
+
+	sources, err := uc.keywordRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorKeywordFetch,
+			Message: "Failed to fetch keywords",
+			Err:     err,
+		}
+	}
+	found := make(map[int]bool, len(sources))
+	for _, source := range sources {
+		found[source.ID] = true
+		if source.SiteID != target.SiteID {
+			return nil, &DomainError{
+				Code:    ErrorValidation,
+				Message: fmt.Sprintf("Keyword %d belongs to another site", source.ID),
+			}
+		}
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, &DomainError{
+				Code:    ErrorKeywordNotFound,
+				Message: fmt.Sprintf("Keyword %d not found", id),
+			}
+		}
+	}
+
+	// Дубль без ключа забирает ключ у объединяемых слов
+	if target.NormalizedValue == nil {
+		key := uc.normalizer.Normalize(target.Value)
+		target.NormalizedValue = &key
+	}
+
+	if err := uc.keywordRepo.Merge(target, ids); err != nil {
+		return nil, &DomainError{
+			Code:    ErrorKeywordMerge,
+			Message: "Failed to merge keywords",
+			Err:     err,
+		}
+	}
+
+	return target, nil
+}
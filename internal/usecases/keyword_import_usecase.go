@@ -0,0 +1,281 @@
+package usecases
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go-seo/internal/domain/entities"
+)
+
+const (
+	maxImportRows         = 50000
+	maxImportValueLength  = 255 // Длина колонок value и name в keywords и groups
+	maxImportTagLength    = 100
+	defaultImportTagSplit = ","
+)
+
+// KeywordImportOptions сопоставление колонок файла импорта. Колонка задается номером с 1
+// или, если в файле есть заголовок, его текстом без учета регистра
+type KeywordImportOptions struct {
+	KeywordColumn string // Пусто - первая колонка
+	GroupColumn   string // Пусто - группы не меняются
+	TagsColumn    string // Пусто - метки не добавляются
+	TagSeparator  string // Пусто - запятая
+	HasHeader     bool
+	DryRun        bool // Только разобрать файл и посчитать изменения
+}
+
+// ImportKeywords импортирует ключевые слова сайта из CSV или XLSX. Недостающие группы и метки создаются,
+// существующие слова переносятся в группу из файла и получают метки из файла. Повторы и ошибочные строки
+// пропускаются и возвращаются в результате. Все изменения вносятся одной транзакцией
+func (uc *KeywordUseCase) ImportKeywords(workspaceID *int, siteID int, filename string, data []byte, options KeywordImportOptions) (*entities.KeywordImportResult, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
+	table, err := uc.spreadsheets.Read(filename, data)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: fmt.Sprintf("Failed to read file: %v", err),
+			Err:     err,
+		}
+	}
+
+	var header []string
+	firstRow := 0
+	if options.HasHeader && len(table) > 0 {
+		header = table[0]
+		firstRow = 1
+	}
+	if len(table)-firstRow > maxImportRows {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: fmt.Sprintf("File must not contain more than %d rows", maxImportRows),
+		}
+	}
+
+	keywordColumn, err := importColumn(options.KeywordColumn, "keyword_column", header)
+	if err != nil {
+		return nil, err
+	}
+	if keywordColumn < 0 {
+		keywordColumn = 0
+	}
+	groupColumn, err := importColumn(options.GroupColumn, "group_column", header)
+	if err != nil {
+		return nil, err
+	}
+	tagsColumn, err := importColumn(options.TagsColumn, "tags_column", header)
+	if err != nil {
+		return nil, err
+	}
+	separator := options.TagSeparator
+	if separator == "" {
+		separator = defaultImportTagSplit
+	}
+
+	result := &entities.KeywordImportResult{DryRun: options.DryRun}
+	var valid []*entities.KeywordImportRow
+	firstSeen := make(map[string]int)
+	for i := firstRow; i < len(table); i++ {
+		if isBlankRow(table[i]) {
+			continue
+		}
+
+		row := parseImportRow(i+1, table[i], keywordColumn, groupColumn, tagsColumn, separator)
+		row.Value = uc.normalizer.Clean(row.Value)
+		row.Key = uc.normalizer.Normalize(row.Value)
+		result.Total++
+		if row.Status == entities.ImportRowInvalid {
+			result.Invalid++
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+		if first, ok := firstSeen[row.Key]; ok {
+			row.Status = entities.ImportRowDuplicate
+			row.Reason = fmt.Sprintf("Repeated in row %d", first)
+			result.Duplicate++
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+		firstSeen[row.Key] = row.Row
+		valid = append(valid, row)
+	}
+
+	keys := make([]string, len(valid))
+	for i, row := range valid {
+		keys[i] = row.Key
+	}
+	existingKeys, err := uc.keywordRepo.GetExistingKeys(siteID, keys)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorKeywordFetch,
+			Message: "Failed to fetch existing keywords",
+			Err:     err,
+		}
+	}
+	existing := make(map[string]bool, len(existingKeys))
+	for _, key := range existingKeys {
+		existing[key] = true
+	}
+
+	// Существующие слова не создаются, но группа и метки из файла к ним применяются
+	for _, row := range valid {
+		if existing[row.Key] {
+			row.Status = entities.ImportRowDuplicate
+			row.Reason = "Keyword already exists for this site"
+			result.Duplicate++
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+		row.Status = entities.ImportRowNew
+		result.New++
+	}
+
+	if result.NewGroups, result.NewTags, err = uc.newImportNames(siteID, valid); err != nil {
+		return nil, err
+	}
+
+	if options.DryRun || len(valid) == 0 {
+		return result, nil
+	}
+
+	stats, err := uc.keywordRepo.Import(siteID, valid)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorKeywordImport,
+			Message: "Failed to import keywords",
+			Err:     err,
+		}
+	}
+	result.Stats = *stats
+
+	return result, nil
+}
+
+// newImportNames группы и метки из строк, которых еще нет у сайта. Имена сравниваются без учета регистра,
+// как и при импорте
+func (uc *KeywordUseCase) newImportNames(siteID int, rows []*entities.KeywordImportRow) ([]string, []string, error) {
+	groups, err := uc.groupRepo.GetAllBySite(siteID)
+	if err != nil {
+		return nil, nil, &DomainError{
+			Code:    ErrorGroupFetch,
+			Message: "Failed to fetch groups",
+			Err:     err,
+		}
+	}
+	tags, err := uc.tagRepo.GetAllBySite(siteID)
+	if err != nil {
+		return nil, nil, &DomainError{
+			Code:    ErrorTagFetch,
+			Message: "Failed to fetch tags",
+			Err:     err,
+		}
+	}
+
+	knownGroups := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		knownGroups[strings.ToLower(group.Name)] = true
+	}
+	knownTags := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		knownTags[strings.ToLower(tag.Name)] = true
+	}
+
+	var newGroups, newTags []string
+	for _, row := range rows {
+		if key := strings.ToLower(row.GroupName); row.GroupName != "" && !knownGroups[key] {
+			knownGroups[key] = true
+			newGroups = append(newGroups, row.GroupName)
+		}
+		for _, tag := range row.Tags {
+			if key := strings.ToLower(tag); !knownTags[key] {
+				knownTags[key] = true
+				newTags = append(newTags, tag)
+			}
+		}
+	}
+	return newGroups, newTags, nil
+}
+
+// importColumn индекс колонки (с 0) по номеру или заголовку; -1, если колонка не задана
+func importColumn(value, field string, header []string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return -1, nil
+	}
+	if number, err := strconv.Atoi(value); err == nil {
+		if number < 1 {
+			return 0, &DomainError{
+				Code:    ErrorValidation,
+				Message: fmt.Sprintf("%s must be a column number starting from 1 or a header name", field),
+			}
+		}
+		return number - 1, nil
+	}
+
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), value) {
+			return i, nil
+		}
+	}
+	return 0, &DomainError{
+		Code:    ErrorValidation,
+		Message: fmt.Sprintf("%s: column %q not found in the file header", field, value),
+	}
+}
+
+func parseImportRow(number int, cells []string, keywordColumn, groupColumn, tagsColumn int, separator string) *entities.KeywordImportRow {
+	row := &entities.KeywordImportRow{
+		Row:       number,
+		Value:     importCell(cells, keywordColumn),
+		GroupName: importCell(cells, groupColumn),
+	}
+
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(importCell(cells, tagsColumn), separator) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		row.Tags = append(row.Tags, tag)
+	}
+
+	switch {
+	case row.Value == "":
+		row.Reason = "Keyword is empty"
+	case utf8.RuneCountInString(row.Value) > maxImportValueLength:
+		row.Reason = fmt.Sprintf("Keyword is longer than %d characters", maxImportValueLength)
+	case utf8.RuneCountInString(row.GroupName) > maxImportValueLength:
+		row.Reason = fmt.Sprintf("Group name is longer than %d characters", maxImportValueLength)
+	}
+	for _, tag := range row.Tags {
+		if row.Reason == "" && utf8.RuneCountInString(tag) > maxImportTagLength {
+			row.Reason = fmt.Sprintf("Tag %q is longer than %d characters", tag, maxImportTagLength)
+		}
+	}
+	if row.Reason != "" {
+		row.Status = entities.ImportRowInvalid
+	}
+	return row
+}
+
+func importCell(cells []string, column int) string {
+	if column < 0 || column >= len(cells) {
+		return ""
+	}
+	return strings.TrimSpace(cells[column])
+}
+
+func isBlankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,201 @@
+package usecases
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/infrastructure/services"
+)
+
+// importKeywordRepository запоминает строки, переданные в Import
+type importKeywordRepository struct {
+	*existingKeywordRepository
+	imported [][]*entities.KeywordImportRow
+}
+
+func (r *importKeywordRepository) Import(siteID int, rows []*entities.KeywordImportRow) (*entities.KeywordImportStats, error) {
+	r.imported = append(r.imported, rows)
+	return &entities.KeywordImportStats{Created: 2, Updated: 1, GroupsCreated: 1, TagsCreated: 1, TagsAssigned: 3}, nil
+}
+
+func (r *fakeGroupRepository) GetAllBySite(siteID int) ([]*entities.Group, error) {
+	var result []*entities.Group
+	for _, group := range r.groups {
+		if group.SiteID == siteID {
+			result = append(result, group)
+		}
+	}
+	return result, nil
+}
+
+func (r *memTagRepository) GetAllBySite(siteID int) ([]*entities.Tag, error) {
+	var result []*entities.Tag
+	for _, tag := range r.tags {
+		if tag.SiteID == siteID {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// newImportUseCase: у сайта 1 есть слово "кресло", группа "Кресла" и метка "мебель"
+func newImportUseCase() (*KeywordUseCase, *importKeywordRepository) {
+	sites, _ := newScopeRepositories()
+	keywords := &importKeywordRepository{existingKeywordRepository: &existingKeywordRepository{existing: map[string]bool{"кресло": true}}}
+	groups := &fakeGroupRepository{groups: map[int]*entities.Group{1: {ID: 1, SiteID: 1, Name: "Кресла"}}}
+	tags := &memTagRepository{tags: map[int]*entities.Tag{1: {ID: 1, SiteID: 1, Name: "мебель"}}}
+	uc := NewKeywordUseCase(keywords, sites, nil, nil, groups, tags, services.NewSpreadsheetReader(), services.NewKeywordNormalizer())
+	return uc, keywords
+}
+
+const importFile = "Фраза;Группа;Метки\n" +
+	"купить диван;Диваны;коммерция, мебель\n" +
+	"  Купить   ДИВАН ;Другая;\n" +
+	"диван угловой;диваны;Мебель;лишняя колонка\n" +
+	";Диваны;\n" +
+	"%s;;\n" +
+	";;\n" +
+	"кресло;Кресла;\n"
+
+func TestImportKeywords(t *testing.T) {
+	data := []byte(strings.Replace(importFile, "%s", strings.Repeat("я", maxImportValueLength+1), 1))
+	options := KeywordImportOptions{KeywordColumn: "фраза", GroupColumn: "2", TagsColumn: "МЕТКИ", HasHeader: true}
+
+	for _, dryRun := range []bool{true, false} {
+		uc, repo := newImportUseCase()
+		options.DryRun = dryRun
+
+		result, err := uc.ImportKeywords(intPtr(1), 1, "keywords.csv", data, options)
+		if err != nil {
+			t.Fatalf("dry run %v: unexpected error: %v", dryRun, err)
+		}
+
+		// Пустая строка 7 не считается
+		if result.DryRun != dryRun || result.Total != 6 || result.New != 2 || result.Duplicate != 2 || result.Invalid != 2 {
+			t.Fatalf("dry run %v: unexpected counts %+v", dryRun, result)
+		}
+		// Имена групп и меток сравниваются без учета регистра
+		if !reflect.DeepEqual(result.NewGroups, []string{"Диваны"}) || !reflect.DeepEqual(result.NewTags, []string{"коммерция"}) {
+			t.Fatalf("dry run %v: expected new group Диваны and tag коммерция, got %v and %v", dryRun, result.NewGroups, result.NewTags)
+		}
+
+		reported := make([]string, len(result.Rows))
+		for i, row := range result.Rows {
+			reported[i] = fmt.Sprintf("%d|%s|%s", row.Row, row.Status, row.Reason)
+		}
+		want := []string{
+			"3|duplicate|Repeated in row 2",
+			"5|invalid|Keyword is empty",
+			"6|invalid|Keyword is longer than 255 characters",
+			"8|duplicate|Keyword already exists for this site",
+		}
+		if !reflect.DeepEqual(reported, want) {
+			t.Fatalf("dry run %v: expected reported rows %q, got %q", dryRun, want, reported)
+		}
+
+		if dryRun {
+			if len(repo.imported) != 0 || result.Stats != (entities.KeywordImportStats{}) {
+				t.Fatalf("dry run must not write anything, imported %v, stats %+v", repo.imported, result.Stats)
+			}
+			continue
+		}
+
+		// Существующее слово передается в Import, чтобы получить группу и метки из файла
+		if len(repo.imported) != 1 || len(repo.imported[0]) != 3 {
+			t.Fatalf("expected one import of 3 rows, got %+v", repo.imported)
+		}
+		first := repo.imported[0][0]
+		if first.Value != "купить диван" || first.Key != "купить диван" || first.GroupName != "Диваны" ||
+			!reflect.DeepEqual(first.Tags, []string{"коммерция", "мебель"}) || first.Status != entities.ImportRowNew {
+			t.Fatalf("unexpected first row %+v", first)
+		}
+		if existing := repo.imported[0][2]; existing.Key != "кресло" || existing.Status != entities.ImportRowDuplicate {
+			t.Fatalf("unexpected existing row %+v", existing)
+		}
+		if result.Stats.Created != 2 || result.Stats.TagsAssigned != 3 {
+			t.Fatalf("expected the repository stats, got %+v", result.Stats)
+		}
+	}
+}
+
+func TestImportKeywordsWithoutHeader(t *testing.T) {
+	uc, repo := newImportUseCase()
+
+	// Без заголовка первая строка - данные, колонка слова по умолчанию первая, повтор в файле считается
+	result, err := uc.ImportKeywords(intPtr(1), 1, "keywords.csv", []byte("диван\nкровать\nДиван\n"), KeywordImportOptions{TagsColumn: "2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Total != 3 || result.New != 2 || result.Duplicate != 1 || len(result.NewGroups) != 0 || len(result.NewTags) != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(repo.imported) != 1 || repo.imported[0][0].GroupName != "" || repo.imported[0][0].Tags != nil {
+		t.Fatalf("expected rows without group and tags, got %+v", repo.imported)
+	}
+}
+
+func TestImportKeywordsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		siteID  int
+		data    string
+		options KeywordImportOptions
+		code    string
+	}{
+		{name: "other workspace site", siteID: 2, data: "диван\n", code: ErrorSiteNotFound},
+		{name: "unknown header", siteID: 1, data: "keyword\nдиван\n", options: KeywordImportOptions{KeywordColumn: "phrase", HasHeader: true}, code: ErrorValidation},
+		{name: "header name without header", siteID: 1, data: "keyword\nдиван\n", options: KeywordImportOptions{GroupColumn: "keyword"}, code: ErrorValidation},
+		{name: "zero column", siteID: 1, data: "диван\n", options: KeywordImportOptions{TagsColumn: "0"}, code: ErrorValidation},
+		{name: "too many rows", siteID: 1, data: strings.Repeat("диван\n", maxImportRows+1), code: ErrorValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo := newImportUseCase()
+			if _, err := uc.ImportKeywords(intPtr(1), tt.siteID, "keywords.csv", []byte(tt.data), tt.options); GetDomainErrorCode(err) != tt.code {
+				t.Fatalf("expected %s, got %v", tt.code, err)
+			}
+			if len(repo.imported) != 0 {
+				t.Fatalf("rejected import must not write anything, got %+v", repo.imported)
+			}
+		})
+	}
+}
+
+func TestImportColumn(t *testing.T) {
+	header := []string{"Keyword", " Group ", "Tags"}
+
+	tests := []struct {
+		value   string
+		header  []string
+		want    int
+		wantErr bool
+	}{
+		{value: "", header: header, want: -1},
+		{value: " ", want: -1},
+		{value: "1", want: 0},
+		{value: "5", header: header, want: 4},
+		{value: "group", header: header, want: 1},
+		{value: "TAGS", header: header, want: 2},
+		{value: "0", header: header, wantErr: true},
+		{value: "-1", wantErr: true},
+		{value: "phrase", header: header, wantErr: true},
+		{value: "keyword", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := importColumn(tt.value, "column", tt.header)
+		if tt.wantErr {
+			if GetDomainErrorCode(err) != ErrorValidation {
+				t.Errorf("importColumn(%q): expected %s, got %d, %v", tt.value, ErrorValidation, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("importColumn(%q) = %d, %v, want %d", tt.value, got, err, tt.want)
+		}
+	}
+}
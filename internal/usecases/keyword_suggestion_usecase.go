@@ -0,0 +1,383 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
+	"go-seo/internal/infrastructure/services"
+)
+
+const (
+	maxSuggestionSeeds   = 20
+	maxSuggestionRegions = 5
+	maxSuggestionIDs     = 1000
+)
+
+type KeywordSuggestionUseCase struct {
+	suggestionRepo repositories.KeywordSuggestionRepository
+	keywordRepo    repositories.KeywordRepository
+	groupRepo      repositories.GroupRepository
+	siteRepo       repositories.SiteRepository
+	usageRepo      repositories.UsageRepository
+	workspaceRepo  repositories.WorkspaceRepository
+	accounts       *ProviderAccountUseCase
+	wordstat       *services.WordstatService
+	normalizer     domainservices.KeywordNormalizer
+}
+
+func NewKeywordSuggestionUseCase(
+	suggestionRepo repositories.KeywordSuggestionRepository,
+	keywordRepo repositories.KeywordRepository,
+	groupRepo repositories.GroupRepository,
+	siteRepo repositories.SiteRepository,
+	usageRepo repositories.UsageRepository,
+	workspaceRepo repositories.WorkspaceRepository,
+	accounts *ProviderAccountUseCase,
+	wordstat *services.WordstatService,
+	normalizer domainservices.KeywordNormalizer,
+) *KeywordSuggestionUseCase {
+	return &KeywordSuggestionUseCase{
+		suggestionRepo: suggestionRepo,
+		keywordRepo:    keywordRepo,
+		groupRepo:      groupRepo,
+		siteRepo:       siteRepo,
+		usageRepo:      usageRepo,
+		workspaceRepo:  workspaceRepo,
+		accounts:       accounts,
+		wordstat:       wordstat,
+		normalizer:     normalizer,
+	}
+}
+
+// CollectSuggestions запрашивает Wordstat по каждой исходной фразе в каждом регионе (без регионов - по всем
+// регионам сразу) и сохраняет популярные и похожие запросы с частотами. Фразы, которые уже есть среди ключевых
+// слов сайта, не сохраняются. Запросы учитываются в расходах и бюджете сайта; при BudgetActionTrim лишние
+// исходные фразы отбрасываются
+func (uc *KeywordSuggestionUseCase) CollectSuggestions(workspaceID *int, siteID int, seeds []string, regions []int, accountID *int) (*entities.KeywordSuggestionResult, error) {
+	site, err := authorizeSite(uc.siteRepo, workspaceID, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	seeds, err = uc.cleanSeeds(seeds)
+	if err != nil {
+		return nil, err
+	}
+	regionList, err := suggestionRegions(regions)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := uc.accounts.Resolve(site.WorkspaceID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := resolveWordstat(uc.wordstat, account)
+	if err != nil {
+		return nil, err
+	}
+
+	seedKeywords := make([]*entities.Keyword, len(seeds))
+	for i, seed := range seeds {
+		seedKeywords[i] = &entities.Keyword{Value: seed, SiteID: siteID}
+	}
+	seedKeywords, err = fitBudget(uc.usageRepo, uc.workspaceRepo, site, seedKeywords, float64(len(regionList))*client.CostPerRequest())
+	if err != nil {
+		return nil, err
+	}
+	seeds = seeds[:len(seedKeywords)]
+
+	type suggestionKey struct {
+		key    string
+		region int
+	}
+	result := &entities.KeywordSuggestionResult{}
+	found := make(map[suggestionKey]*entities.KeywordSuggestion)
+	var order []suggestionKey
+	for _, seed := range seeds {
+		failed := false
+		for _, region := range regionList {
+			response, err := client.GetWordstatData(context.Background(), seed, region)
+			if err != nil {
+				log.Printf("WARNING: Wordstat suggestions for %q failed: %v", seed, err)
+				failed = true
+				continue
+			}
+			result.Requests++
+			recordWordstatUsage(uc.usageRepo, "", siteID, client)
+
+			for _, item := range uc.wordstatSuggestions(siteID, seed, region, response) {
+				key := suggestionKey{item.NormalizedValue, 0}
+				if region != nil {
+					key.region = *region
+				}
+				if existing, ok := found[key]; ok {
+					if item.Frequency > existing.Frequency {
+						found[key] = item
+					}
+					continue
+				}
+				found[key] = item
+				order = append(order, key)
+			}
+		}
+		if failed {
+			result.FailedSeeds = append(result.FailedSeeds, seed)
+		}
+	}
+	if result.Requests == 0 && len(result.FailedSeeds) > 0 {
+		return nil, &DomainError{
+			Code:    ErrorSuggestionCollect,
+			Message: "Failed to get suggestions from Wordstat",
+		}
+	}
+	result.Found = len(order)
+
+	keys := make([]string, 0, len(order))
+	seenKeys := make(map[string]bool, len(order))
+	for _, key := range order {
+		if !seenKeys[key.key] {
+			seenKeys[key.key] = true
+			keys = append(keys, key.key)
+		}
+	}
+	existingKeys, err := uc.keywordRepo.GetExistingKeys(siteID, keys)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorKeywordFetch,
+			Message: "Failed to fetch existing keywords",
+			Err:     err,
+		}
+	}
+	existing := make(map[string]bool, len(existingKeys))
+	for _, key := range existingKeys {
+		existing[key] = true
+	}
+
+	for _, key := range order {
+		if existing[key.key] {
+			result.Existing++
+			continue
+		}
+		result.Suggestions = append(result.Suggestions, found[key])
+	}
+	sort.SliceStable(result.Suggestions, func(i, j int) bool {
+		return result.Suggestions[i].Frequency > result.Suggestions[j].Frequency
+	})
+
+	if err := uc.suggestionRepo.Save(result.Suggestions); err != nil {
+		return nil, &DomainError{
+			Code:    ErrorSuggestionCollect,
+			Message: "Failed to save keyword suggestions",
+			Err:     err,
+		}
+	}
+
+	return result, nil
+}
+
+// GetSuggestions сохраненные подсказки сайта по убыванию частоты
+func (uc *KeywordSuggestionUseCase) GetSuggestions(workspaceID *int, siteID int, region *int, kind *string, page, perPage int) ([]*entities.KeywordSuggestion, int64, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, 0, err
+	}
+
+	page, perPage = normalizePage(page, perPage)
+	suggestions, total, err := uc.suggestionRepo.GetBySite(siteID, region, kind, (page-1)*perPage, perPage)
+	if err != nil {
+		return nil, 0, &DomainError{
+			Code:    ErrorSuggestionFetch,
+			Message: "Failed to fetch keyword suggestions",
+			Err:     err,
+		}
+	}
+
+	return suggestions, total, nil
+}
+
+// AcceptSuggestions создает из подсказок ключевые слова сайта в группе groupID (nil - без группы).
+// Возвращает число созданных слов; принятые подсказки удаляются
+func (uc *KeywordSuggestionUseCase) AcceptSuggestions(workspaceID *int, siteID int, ids []int, groupID *int) (int, error) {
+	ids, err := uc.checkSuggestions(workspaceID, siteID, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	if groupID != nil {
+		group, err := uc.groupRepo.GetByID(*groupID)
+		if err != nil || group.SiteID != siteID {
+			return 0, &DomainError{
+				Code:    ErrorGroupNotFound,
+				Message: "Group not found",
+				Err:     err,
+			}
+		}
+	}
+
+	created, err := uc.suggestionRepo.Accept(siteID, ids, groupID)
+	if err != nil {
+		return 0, &DomainError{
+			Code:    ErrorSuggestionAccept,
+			Message: "Failed to accept keyword suggestions",
+			Err:     err,
+		}
+	}
+
+	return created, nil
+}
+
+// DismissSuggestions удаляет подсказки; при следующем сборе они могут появиться снова
+func (uc *KeywordSuggestionUseCase) DismissSuggestions(workspaceID *int, siteID int, ids []int) (int, error) {
+	ids, err := uc.checkSuggestions(workspaceID, siteID, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := uc.suggestionRepo.Delete(siteID, ids)
+	if err != nil {
+		return 0, &DomainError{
+			Code:    ErrorSuggestionDeletion,
+			Message: "Failed to delete keyword suggestions",
+			Err:     err,
+		}
+	}
+
+	return deleted, nil
+}
+
+// checkSuggestions проверяет доступ к сайту и что все подсказки существуют и относятся к нему
+func (uc *KeywordSuggestionUseCase) checkSuggestions(workspaceID *int, siteID int, ids []int) ([]int, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 || len(ids) > maxSuggestionIDs {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: fmt.Sprintf("ids must contain from 1 to %d suggestions", maxSuggestionIDs),
+		}
+	}
+
+	suggestions, err := uc.suggestionRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorSuggestionFetch,
+			Message: "Failed to fetch keyword suggestions",
+			Err:     err,
+		}
+	}
+	for _, suggestion := range suggestions {
+		if suggestion.SiteID != siteID {
+			suggestions = nil
+			break
+		}
+	}
+	if len(suggestions) != len(ids) {
+		return nil, &DomainError{
+			Code:    ErrorSuggestionNotFound,
+			Message: "Keyword suggestion not found",
+		}
+	}
+
+	return ids, nil
+}
+
+// cleanSeeds очищает исходные фразы и убирает совпадающие после нормализации
+func (uc *KeywordSuggestionUseCase) cleanSeeds(seeds []string) ([]string, error) {
+	var result []string
+	seen := make(map[string]bool, len(seeds))
+	for _, seed := range seeds {
+		seed = uc.normalizer.Clean(seed)
+		key := uc.normalizer.Normalize(seed)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, seed)
+	}
+
+	if len(result) == 0 || len(result) > maxSuggestionSeeds {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: fmt.Sprintf("seeds must contain from 1 to %d phrases", maxSuggestionSeeds),
+		}
+	}
+	return result, nil
+}
+
+// wordstatSuggestions подсказки из ответа Wordstat: сначала популярные запросы, затем похожие
+func (uc *KeywordSuggestionUseCase) wordstatSuggestions(siteID int, seed string, region *int, response *services.WordstatResponse) []*entities.KeywordSuggestion {
+	var result []*entities.KeywordSuggestion
+	add := func(items []services.WordstatItem, kind string) {
+		for _, item := range items {
+			value := uc.normalizer.Clean(item.Text)
+			key := uc.normalizer.Normalize(value)
+			if key == "" || utf8.RuneCountInString(value) > maxImportValueLength {
+				continue
+			}
+			result = append(result, &entities.KeywordSuggestion{
+				SiteID:          siteID,
+				Value:           value,
+				NormalizedValue: key,
+				Seed:            seed,
+				Kind:            kind,
+				Region:          region,
+				Frequency:       wordstatFrequency(item.Value),
+			})
+		}
+	}
+	add(response.Popular, entities.SuggestionPopular)
+	add(response.Associations, entities.SuggestionAssociation)
+	return result
+}
+
+// suggestionRegions список регионов запросов; nil в списке - все регионы
+func suggestionRegions(regions []int) ([]*int, error) {
+	regions = uniqueIDs(regions)
+	if len(regions) > maxSuggestionRegions {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: fmt.Sprintf("regions must not contain more than %d regions", maxSuggestionRegions),
+		}
+	}
+	if len(regions) == 0 {
+		return []*int{nil}, nil
+	}
+
+	result := make([]*int, len(regions))
+	for i := range regions {
+		if regions[i] <= 0 {
+			return nil, &DomainError{
+				Code:    ErrorValidation,
+				Message: fmt.Sprintf("Invalid region %d", regions[i]),
+			}
+		}
+		result[i] = &regions[i]
+	}
+	return result, nil
+}
+
+// wordstatFrequency частота из ответа Wordstat; разделители разрядов отбрасываются
+func wordstatFrequency(value string) int {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, value)
+	frequency, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0
+	}
+	return frequency
+}
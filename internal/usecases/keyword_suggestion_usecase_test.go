@@ -0,0 +1,295 @@
+package usecases
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/services"
+
+	"gorm.io/gorm"
+)
+
+// fakeKeywordSuggestionRepository хранит подсказки в памяти и запоминает сохраненные и принятые
+type fakeKeywordSuggestionRepository struct {
+	suggestions map[int]*entities.KeywordSuggestion
+	saved       []*entities.KeywordSuggestion
+	accepted    []int
+	groupID     *int
+}
+
+func (r *fakeKeywordSuggestionRepository) Save(suggestions []*entities.KeywordSuggestion) error {
+	r.saved = suggestions
+	return nil
+}
+
+func (r *fakeKeywordSuggestionRepository) GetByIDs(ids []int) ([]*entities.KeywordSuggestion, error) {
+	var result []*entities.KeywordSuggestion
+	for _, id := range ids {
+		if suggestion, ok := r.suggestions[id]; ok {
+			result = append(result, suggestion)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeKeywordSuggestionRepository) GetBySite(siteID int, region *int, kind *string, offset, limit int) ([]*entities.KeywordSuggestion, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeKeywordSuggestionRepository) Accept(siteID int, ids []int, groupID *int) (int, error) {
+	r.accepted = ids
+	r.groupID = groupID
+	return len(ids), nil
+}
+
+func (r *fakeKeywordSuggestionRepository) Delete(siteID int, ids []int) (int, error) {
+	return len(ids), nil
+}
+
+// existingKeywordRepository считает занятыми заданные нормализованные ключи
+type existingKeywordRepository struct {
+	repositories.KeywordRepository
+	existing map[string]bool
+}
+
+func (r *existingKeywordRepository) GetExistingKeys(siteID int, keys []string) ([]string, error) {
+	var result []string
+	for _, key := range keys {
+		if r.existing[key] {
+			result = append(result, key)
+		}
+	}
+	return result, nil
+}
+
+type fakeGroupRepository struct {
+	repositories.GroupRepository
+	groups map[int]*entities.Group
+}
+
+func (r *fakeGroupRepository) GetByID(id int) (*entities.Group, error) {
+	if group, ok := r.groups[id]; ok {
+		return group, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// newWordstatServer отвечает на запрос фразы заданными подсказками; фразы без ответа получают 500
+func newWordstatServer(t *testing.T, responses map[string]services.WordstatResponse) *services.WordstatService {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Query().Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	wordstat, err := services.NewWordstatService(server.URL, "user", "key", 0.5, nil)
+	if err != nil {
+		t.Fatalf("wordstat: %v", err)
+	}
+	return wordstat
+}
+
+func newKeywordSuggestionUseCase(t *testing.T, responses map[string]services.WordstatResponse) (*KeywordSuggestionUseCase, *fakeKeywordSuggestionRepository, *fakeUsageRepository) {
+	sites, _ := newScopeRepositories()
+	suggestions := &fakeKeywordSuggestionRepository{suggestions: map[int]*entities.KeywordSuggestion{
+		1: {ID: 1, SiteID: 1, Value: "купить елку недорого"},
+		2: {ID: 2, SiteID: 1, Value: "новогодняя елка"},
+		3: {ID: 3, SiteID: 2, Value: "чужая подсказка"},
+	}}
+	keywords := &existingKeywordRepository{existing: map[string]bool{"купить елку": true}}
+	groups := &fakeGroupRepository{groups: map[int]*entities.Group{
+		10: {ID: 10, SiteID: 1},
+		20: {ID: 20, SiteID: 2},
+	}}
+	usage := &fakeUsageRepository{}
+	workspaces := &fakeWorkspaceRepository{workspaces: map[int]*entities.Workspace{1: {ID: 1}}}
+
+	uc := NewKeywordSuggestionUseCase(suggestions, keywords, groups, sites, usage, workspaces,
+		&ProviderAccountUseCase{}, newWordstatServer(t, responses), services.NewKeywordNormalizer())
+	return uc, suggestions, usage
+}
+
+func TestWordstatFrequency(t *testing.T) {
+	tests := map[string]int{
+		"1 234":  1234,
+		"12 345": 12345,
+		"987":    987,
+		"":       0,
+		"нет":    0,
+	}
+
+	for value, want := range tests {
+		if got := wordstatFrequency(value); got != want {
+			t.Errorf("wordstatFrequency(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
+
+func TestSuggestionRegions(t *testing.T) {
+	regions, err := suggestionRegions(nil)
+	if err != nil || len(regions) != 1 || regions[0] != nil {
+		t.Fatalf("expected a single request over all regions, got %v, %v", regions, err)
+	}
+
+	regions, err = suggestionRegions([]int{213, 2, 213})
+	if err != nil || len(regions) != 2 || *regions[0] != 213 || *regions[1] != 2 {
+		t.Fatalf("expected unique regions 213 and 2, got %v, %v", regions, err)
+	}
+
+	for _, invalid := range [][]int{{0}, {213, -1}, {1, 2, 3, 4, 5, 6}} {
+		if _, err := suggestionRegions(invalid); GetDomainErrorCode(err) != ErrorValidation {
+			t.Errorf("regions %v: expected %s, got %v", invalid, ErrorValidation, err)
+		}
+	}
+}
+
+func TestCleanSeeds(t *testing.T) {
+	uc, _, _ := newKeywordSuggestionUseCase(t, nil)
+
+	seeds, err := uc.cleanSeeds([]string{"  купить   елку ", "Купить ЁЛКУ", "", "елка цена"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"купить елку", "елка цена"}; !reflect.DeepEqual(seeds, want) {
+		t.Fatalf("expected %v, got %v", want, seeds)
+	}
+
+	tooMany := make([]string, maxSuggestionSeeds+1)
+	for i := range tooMany {
+		tooMany[i] = "фраза " + string(rune('а'+i))
+	}
+	for _, invalid := range [][]string{nil, {" ", ""}, tooMany} {
+		if _, err := uc.cleanSeeds(invalid); GetDomainErrorCode(err) != ErrorValidation {
+			t.Errorf("seeds %q: expected %s, got %v", invalid, ErrorValidation, err)
+		}
+	}
+}
+
+func TestCollectSuggestions(t *testing.T) {
+	uc, repo, usage := newKeywordSuggestionUseCase(t, map[string]services.WordstatResponse{
+		"купить елку": {
+			Popular: []services.WordstatItem{
+				{Value: "1 200", Text: "купить елку"},
+				{Value: "300", Text: "купить елку недорого"},
+			},
+			Associations: []services.WordstatItem{{Value: "5 000", Text: "Новогодняя  елка"}},
+		},
+		"елка цена": {
+			Popular: []services.WordstatItem{
+				{Value: "800", Text: "елка цена"},
+				{Value: "450", Text: "купить ёлку недорого"},
+			},
+			Associations: []services.WordstatItem{{Value: "4 000", Text: "новогодняя елка"}},
+		},
+	})
+
+	result, err := uc.CollectSuggestions(intPtr(1), 1, []string{"купить елку", "елка цена", "сломано"}, []int{213}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Requests != 2 || usage.requests != 2 {
+		t.Fatalf("expected 2 recorded requests, got %d and %d in usage", result.Requests, usage.requests)
+	}
+	if !reflect.DeepEqual(result.FailedSeeds, []string{"сломано"}) {
+		t.Fatalf("expected the failed seed, got %v", result.FailedSeeds)
+	}
+	// Слово сайта "купить елку" найдено, но не предлагается
+	if result.Found != 4 || result.Existing != 1 {
+		t.Fatalf("expected 4 found and 1 existing phrases, got %d and %d", result.Found, result.Existing)
+	}
+
+	// Повторы между фразами сливаются с наибольшей частотой, подсказки идут по убыванию частоты
+	want := []struct {
+		value, seed, kind string
+		frequency         int
+	}{
+		{"Новогодняя елка", "купить елку", entities.SuggestionAssociation, 5000},
+		{"елка цена", "елка цена", entities.SuggestionPopular, 800},
+		{"купить ёлку недорого", "елка цена", entities.SuggestionPopular, 450},
+	}
+	if len(result.Suggestions) != len(want) {
+		t.Fatalf("expected %d suggestions, got %+v", len(want), result.Suggestions)
+	}
+	for i, w := range want {
+		got := result.Suggestions[i]
+		if got.Value != w.value || got.Seed != w.seed || got.Kind != w.kind || got.Frequency != w.frequency ||
+			got.SiteID != 1 || got.Region == nil || *got.Region != 213 {
+			t.Fatalf("suggestion %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+	if !reflect.DeepEqual(repo.saved, result.Suggestions) {
+		t.Fatalf("expected the suggestions to be saved, got %+v", repo.saved)
+	}
+}
+
+func TestCollectSuggestionsErrors(t *testing.T) {
+	uc, repo, _ := newKeywordSuggestionUseCase(t, nil)
+
+	if _, err := uc.CollectSuggestions(intPtr(1), 2, []string{"купить елку"}, nil, nil); GetDomainErrorCode(err) != ErrorSiteNotFound {
+		t.Fatalf("expected %s, got %v", ErrorSiteNotFound, err)
+	}
+	// Wordstat не ответил ни по одной фразе
+	if _, err := uc.CollectSuggestions(intPtr(1), 1, []string{"купить елку"}, nil, nil); GetDomainErrorCode(err) != ErrorSuggestionCollect {
+		t.Fatalf("expected %s, got %v", ErrorSuggestionCollect, err)
+	}
+	if repo.saved != nil {
+		t.Fatalf("expected nothing to be saved, got %+v", repo.saved)
+	}
+}
+
+func TestAcceptAndDismissSuggestions(t *testing.T) {
+	uc, repo, _ := newKeywordSuggestionUseCase(t, nil)
+
+	created, err := uc.AcceptSuggestions(intPtr(1), 1, []int{2, 1, 2}, intPtr(10))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created != 2 || !reflect.DeepEqual(repo.accepted, []int{2, 1}) || repo.groupID == nil || *repo.groupID != 10 {
+		t.Fatalf("expected unique ids accepted into group 10, got %d: %v, %v", created, repo.accepted, repo.groupID)
+	}
+
+	if deleted, err := uc.DismissSuggestions(intPtr(1), 1, []int{1}); err != nil || deleted != 1 {
+		t.Fatalf("expected 1 dismissed suggestion, got %d, %v", deleted, err)
+	}
+
+	tests := []struct {
+		name    string
+		siteID  int
+		ids     []int
+		groupID *int
+		code    string
+	}{
+		{name: "other workspace site", siteID: 2, ids: []int{3}, code: ErrorSiteNotFound},
+		{name: "no ids", siteID: 1, code: ErrorValidation},
+		{name: "suggestion of another site", siteID: 1, ids: []int{1, 3}, code: ErrorSuggestionNotFound},
+		{name: "missing suggestion", siteID: 1, ids: []int{1, 4}, code: ErrorSuggestionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.AcceptSuggestions(intPtr(1), tt.siteID, tt.ids, nil); GetDomainErrorCode(err) != tt.code {
+				t.Fatalf("accept: expected %s, got %v", tt.code, err)
+			}
+			if _, err := uc.DismissSuggestions(intPtr(1), tt.siteID, tt.ids); GetDomainErrorCode(err) != tt.code {
+				t.Fatalf("dismiss: expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+
+	for _, groupID := range []int{20, 30} {
+		if _, err := uc.AcceptSuggestions(intPtr(1), 1, []int{1}, intPtr(groupID)); GetDomainErrorCode(err) != ErrorGroupNotFound {
+			t.Errorf("group %d: expected %s, got %v", groupID, ErrorGroupNotFound, err)
+		}
+	}
+}
@@ -4,34 +4,61 @@ import (
 	"fmt"
 	"go-seo/internal/domain/entities"
 	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
 	"go-seo/internal/infrastructure/database"
 )
 
 type KeywordUseCase struct {
 	keywordRepo  repositories.KeywordRepository
+	siteRepo     repositories.SiteRepository
 	positionRepo repositories.PositionRepository
+	snapshotRepo repositories.SerpSnapshotRepository
+	groupRepo    repositories.GroupRepository
+	tagRepo      repositories.TagRepository
+	spreadsheets domainservices.SpreadsheetReader
+	normalizer   domainservices.KeywordNormalizer
 }
 
-func NewKeywordUseCase(keywordRepo repositories.KeywordRepository, positionRepo repositories.PositionRepository) *KeywordUseCase {
+func NewKeywordUseCase(keywordRepo repositories.KeywordRepository, siteRepo repositories.SiteRepository, positionRepo repositories.PositionRepository, snapshotRepo repositories.SerpSnapshotRepository, groupRepo repositories.GroupRepository, tagRepo repositories.TagRepository, spreadsheets domainservices.SpreadsheetReader, normalizer domainservices.KeywordNormalizer) *KeywordUseCase {
 	return &KeywordUseCase{
 		keywordRepo:  keywordRepo,
+		siteRepo:     siteRepo,
 		positionRepo: positionRepo,
+		snapshotRepo: snapshotRepo,
+		groupRepo:    groupRepo,
+		tagRepo:      tagRepo,
+		spreadsheets: spreadsheets,
+		normalizer:   normalizer,
 	}
 }
 
-func (uc *KeywordUseCase) CreateKeyword(value string, siteID int, groupID *int) (*entities.Keyword, error) {
-	existingKeyword, err := uc.keywordRepo.GetByValueAndSite(value, siteID)
+func (uc *KeywordUseCase) CreateKeyword(workspaceID *int, value string, siteID int, groupID *int) (*entities.Keyword, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
+	value = uc.normalizer.Clean(value)
+	if value == "" {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "Keyword value must not be empty",
+		}
+	}
+	key := uc.normalizer.Normalize(value)
+
+	existingKeyword, err := uc.keywordRepo.GetByNormalizedValue(key, siteID)
 	if err == nil && existingKeyword != nil {
 		return nil, &DomainError{
 			Code:    ErrorKeywordExists,
-			Message: "Keyword already exists for this site",
+			Message: fmt.Sprintf("Keyword already exists for this site as '%s'", existingKeyword.Value),
 		}
 	}
 
 	keyword := &entities.Keyword{
-		Value:   value,
-		SiteID:  siteID,
-		GroupID: groupID,
+		Value:           value,
+		SiteID:          siteID,
+		GroupID:         groupID,
+		NormalizedValue: &key,
 	}
 
 	if err := uc.keywordRepo.Create(keyword); err != nil {
@@ -68,7 +95,7 @@ func (uc *KeywordUseCase) CreateKeyword(value string, siteID int, groupID *int)
 	return keyword, nil
 }
 
-func (uc *KeywordUseCase) CreateKeywordsBatch(keywords []*entities.Keyword) ([]*entities.Keyword, []error) {
+func (uc *KeywordUseCase) CreateKeywordsBatch(workspaceID *int, keywords []*entities.Keyword) ([]*entities.Keyword, []error) {
 	if len(keywords) == 0 {
 		return []*entities.Keyword{}, []error{}
 	}
@@ -76,15 +103,69 @@ func (uc *KeywordUseCase) CreateKeywordsBatch(keywords []*entities.Keyword) ([]*
 	var toCreate []*entities.Keyword
 	var errors []error
 
+	// Существующие слова проверяются одним запросом на сайт, а не запросом на каждое слово
+	siteErrors := make(map[int]error)
+	siteKeys := make(map[int][]string)
+	for _, keyword := range keywords {
+		keyword.Value = uc.normalizer.Clean(keyword.Value)
+		key := uc.normalizer.Normalize(keyword.Value)
+		keyword.NormalizedValue = &key
+
+		if _, checked := siteErrors[keyword.SiteID]; !checked {
+			_, siteErrors[keyword.SiteID] = authorizeSite(uc.siteRepo, workspaceID, keyword.SiteID)
+		}
+		if siteErrors[keyword.SiteID] == nil {
+			siteKeys[keyword.SiteID] = append(siteKeys[keyword.SiteID], key)
+		}
+	}
+
+	existing := make(map[int]map[string]bool, len(siteKeys))
+	for siteID, keys := range siteKeys {
+		existingKeys, err := uc.keywordRepo.GetExistingKeys(siteID, keys)
+		if err != nil {
+			siteErrors[siteID] = err
+			continue
+		}
+		existing[siteID] = make(map[string]bool, len(keys))
+		for _, key := range existingKeys {
+			existing[siteID][key] = true
+		}
+	}
+
 	for i, keyword := range keywords {
-		existingKeyword, err := uc.keywordRepo.GetByValueAndSite(keyword.Value, keyword.SiteID)
-		if err == nil && existingKeyword != nil {
+		if siteErr := siteErrors[keyword.SiteID]; siteErr != nil {
+			if IsDomainError(siteErr) {
+				errors = append(errors, &DomainError{
+					Code:    ErrorSiteNotFound,
+					Message: fmt.Sprintf("Site %d not found for keyword '%s'", keyword.SiteID, keyword.Value),
+				})
+			} else {
+				errors = append(errors, &DomainError{
+					Code:    ErrorKeywordFetch,
+					Message: fmt.Sprintf("Failed to check keyword '%s' for site %d", keyword.Value, keyword.SiteID),
+					Err:     siteErr,
+				})
+			}
+			continue
+		}
+
+		if keyword.Value == "" {
+			errors = append(errors, &DomainError{
+				Code:    ErrorValidation,
+				Message: fmt.Sprintf("Keyword value for site %d must not be empty", keyword.SiteID),
+			})
+			continue
+		}
+
+		// Повтор внутри запроса тоже считается существующим словом
+		if existing[keyword.SiteID][*keyword.NormalizedValue] {
 			errors = append(errors, &DomainError{
 				Code:    ErrorKeywordExists,
 				Message: fmt.Sprintf("Keyword '%s' already exists for site %d", keyword.Value, keyword.SiteID),
 			})
 			continue
 		}
+		existing[keyword.SiteID][*keyword.NormalizedValue] = true
 		toCreate = append(toCreate, keywords[i])
 	}
 
@@ -135,14 +216,10 @@ func (uc *KeywordUseCase) CreateKeywordsBatch(keywords []*entities.Keyword) ([]*
 	return toCreate, errors
 }
 
-func (uc *KeywordUseCase) UpdateKeyword(id int, groupID *int) (*entities.Keyword, error) {
-	keyword, err := uc.keywordRepo.GetByID(id)
+func (uc *KeywordUseCase) UpdateKeyword(workspaceID *int, id int, groupID *int) (*entities.Keyword, error) {
+	keyword, err := uc.getKeyword(workspaceID, id)
 	if err != nil {
-		return nil, &DomainError{
-			Code:    ErrorKeywordNotFound,
-			Message: "Keyword not found",
-			Err:     err,
-		}
+		return nil, err
 	}
 
 	keyword.GroupID = groupID
@@ -157,12 +234,15 @@ func (uc *KeywordUseCase) UpdateKeyword(id int, groupID *int) (*entities.Keyword
 	return keyword, nil
 }
 
-func (uc *KeywordUseCase) DeleteKeyword(id int) error {
-	_, err := uc.keywordRepo.GetByID(id)
-	if err != nil {
+func (uc *KeywordUseCase) DeleteKeyword(workspaceID *int, id int) error {
+	if _, err := uc.getKeyword(workspaceID, id); err != nil {
+		return err
+	}
+
+	if err := uc.snapshotRepo.DeleteByKeywordID(id); err != nil {
 		return &DomainError{
-			Code:    ErrorKeywordNotFound,
-			Message: "Keyword not found",
+			Code:    ErrorPositionDeletion,
+			Message: "Failed to delete keyword SERP snapshots",
 			Err:     err,
 		}
 	}
@@ -186,8 +266,13 @@ func (uc *KeywordUseCase) DeleteKeyword(id int) error {
 	return nil
 }
 
-func (uc *KeywordUseCase) GetKeywordsBySite(siteID int) ([]*entities.Keyword, error) {
-	keywords, err := uc.keywordRepo.GetBySiteID(siteID)
+// GetKeywordsBySite слова сайта с их метками; tags отбирает слова по меткам
+func (uc *KeywordUseCase) GetKeywordsBySite(workspaceID *int, siteID int, tags *entities.TagFilter) ([]*entities.Keyword, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
+	keywords, err := uc.keywordRepo.GetBySiteIDAndTags(siteID, tags)
 	if err != nil {
 		return nil, &DomainError{
 			Code:    ErrorKeywordFetch,
@@ -196,5 +281,35 @@ func (uc *KeywordUseCase) GetKeywordsBySite(siteID int) ([]*entities.Keyword, er
 		}
 	}
 
+	ids := make([]int, len(keywords))
+	for i, keyword := range keywords {
+		ids[i] = keyword.ID
+	}
+	tagsByKeyword, err := uc.tagRepo.GetByKeywordIDs(ids)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorTagFetch,
+			Message: "Failed to fetch keyword tags",
+			Err:     err,
+		}
+	}
+	for _, keyword := range keywords {
+		keyword.Tags = tagsByKeyword[keyword.ID]
+	}
+
 	return keywords, nil
 }
+
+// getKeyword возвращает keyword, если его сайт доступен в области запроса
+func (uc *KeywordUseCase) getKeyword(workspaceID *int, id int) (*entities.Keyword, error) {
+	keyword, err := uc.keywordRepo.GetByIDInWorkspace(id, workspaceID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorKeywordNotFound,
+			Message: "Keyword not found",
+			Err:     err,
+		}
+	}
+
+	return keyword, nil
+}
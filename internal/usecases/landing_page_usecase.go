@@ -0,0 +1,358 @@
+package usecases
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+)
+
+// maxLandingPageDays ограничивает период анализа: все проверки периода разбираются в памяти
+const maxLandingPageDays = 92
+
+type LandingPageUseCase struct {
+	landingRepo repositories.LandingPageRepository
+	siteRepo    repositories.SiteRepository
+}
+
+func NewLandingPageUseCase(landingRepo repositories.LandingPageRepository, siteRepo repositories.SiteRepository) *LandingPageUseCase {
+	return &LandingPageUseCase{
+		landingRepo: landingRepo,
+		siteRepo:    siteRepo,
+	}
+}
+
+// GetURLChanges ключевые слова, у которых ранжирующийся URL сайта менялся между проверками периода,
+// со всеми сменами и историей позиций каждого URL. Без дат берутся последние 30 дней
+func (uc *LandingPageUseCase) GetURLChanges(workspaceID *int, siteID int, source *string, groupID *int, dateFrom, dateTo *time.Time, page, perPage int) ([]*entities.KeywordURLChanges, int64, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, 0, err
+	}
+
+	from, to, err := landingPagePeriod(dateFrom, dateTo)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	checks, err := uc.landingRepo.GetRankingURLs(siteID, source, groupID, from, to)
+	if err != nil {
+		return nil, 0, &DomainError{
+			Code:    ErrorLandingPageFetch,
+			Message: "Failed to fetch ranking URLs",
+			Err:     err,
+		}
+	}
+
+	var result []*entities.KeywordURLChanges
+	for _, keyword := range groupLandingChecks(checks) {
+		changes, _ := detectURLChanges(keyword.checks)
+		if len(changes) == 0 {
+			continue
+		}
+
+		first := keyword.checks[0]
+		result = append(result, &entities.KeywordURLChanges{
+			KeywordID:     first.KeywordID,
+			Keyword:       first.Keyword,
+			GroupID:       first.GroupID,
+			Source:        first.Source,
+			FilterGroupID: first.FilterGroupID,
+			Changes:       changes,
+			URLs:          buildURLHistories(keyword.checks),
+		})
+	}
+
+	start, end := pageBounds(len(result), page, perPage)
+	return result[start:end], int64(len(result)), nil
+}
+
+// GetCannibalization ключевые слова, по которым конкурируют несколько URL сайта: ранжирующийся URL
+// возвращается к уже ранжировавшемуся раньше или несколько URL сайта попадают в одну сохраненную выдачу.
+// История позиций каждого URL собирается из позиций и снимков выдачи. Без дат берутся последние 30 дней
+func (uc *LandingPageUseCase) GetCannibalization(workspaceID *int, siteID int, source *string, groupID *int, dateFrom, dateTo *time.Time, page, perPage int) ([]*entities.CannibalizationCase, int64, error) {
+	site, err := authorizeSite(uc.siteRepo, workspaceID, siteID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	from, to, err := landingPagePeriod(dateFrom, dateTo)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	checks, err := uc.landingRepo.GetRankingURLs(siteID, source, groupID, from, to)
+	if err != nil {
+		return nil, 0, &DomainError{
+			Code:    ErrorLandingPageFetch,
+			Message: "Failed to fetch ranking URLs",
+			Err:     err,
+		}
+	}
+	shared, err := uc.landingRepo.GetSharedSerpURLs(siteID, site.Domain, source, groupID, from, to)
+	if err != nil {
+		return nil, 0, &DomainError{
+			Code:    ErrorLandingPageFetch,
+			Message: "Failed to fetch SERP snapshots",
+			Err:     err,
+		}
+	}
+
+	rankingByKey := make(map[landingKey][]*entities.LandingCheck)
+	for _, keyword := range groupLandingChecks(checks) {
+		rankingByKey[keyword.key] = keyword.checks
+	}
+	sharedByKey := make(map[landingKey][]*entities.LandingCheck)
+	for _, keyword := range groupLandingChecks(shared) {
+		sharedByKey[keyword.key] = sharedChecks(keyword.checks)
+	}
+
+	keys := make([]landingKey, 0, len(rankingByKey)+len(sharedByKey))
+	for key := range rankingByKey {
+		keys = append(keys, key)
+	}
+	for key := range sharedByKey {
+		if _, ok := rankingByKey[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sortLandingKeys(keys)
+
+	var result []*entities.CannibalizationCase
+	for _, key := range keys {
+		ranking, sharedRows := rankingByKey[key], sharedByKey[key]
+		_, rotating := detectURLChanges(ranking)
+		sharedCount := countPositions(sharedRows)
+		if !rotating && sharedCount == 0 {
+			continue
+		}
+
+		all := append(append([]*entities.LandingCheck{}, ranking...), sharedRows...)
+		first := all[0]
+		result = append(result, &entities.CannibalizationCase{
+			KeywordID:     first.KeywordID,
+			Keyword:       first.Keyword,
+			GroupID:       first.GroupID,
+			Source:        first.Source,
+			FilterGroupID: first.FilterGroupID,
+			Rotating:      rotating,
+			SharedChecks:  sharedCount,
+			URLs:          buildURLHistories(all),
+		})
+	}
+
+	start, end := pageBounds(len(result), page, perPage)
+	return result[start:end], int64(len(result)), nil
+}
+
+func landingPagePeriod(dateFrom, dateTo *time.Time) (time.Time, time.Time, error) {
+	to := startOfDay(time.Now())
+	if dateTo != nil {
+		to = startOfDay(*dateTo)
+	}
+	from := to.AddDate(0, 0, -29)
+	if dateFrom != nil {
+		from = startOfDay(*dateFrom)
+	}
+
+	if to.Before(from) {
+		return from, to, &DomainError{
+			Code:    ErrorValidation,
+			Message: "date_to must not be before date_from",
+		}
+	}
+	if to.Sub(from) >= maxLandingPageDays*24*time.Hour {
+		return from, to, &DomainError{
+			Code:    ErrorValidation,
+			Message: "Period must not exceed 92 days",
+		}
+	}
+	return from, to, nil
+}
+
+// landingKey ключевое слово в рамках источника и группы фильтров: URL разных настроек проверки не сравниваются
+type landingKey struct {
+	keywordID     int
+	source        string
+	filterGroupID int
+}
+
+type landingKeyword struct {
+	key    landingKey
+	checks []*entities.LandingCheck
+}
+
+// groupLandingChecks группирует проверки по landingKey, сохраняя порядок строк репозитория
+func groupLandingChecks(checks []*entities.LandingCheck) []landingKeyword {
+	var result []landingKeyword
+	index := make(map[landingKey]int)
+	for _, check := range checks {
+		key := landingKey{keywordID: check.KeywordID, source: check.Source}
+		if check.FilterGroupID != nil {
+			key.filterGroupID = *check.FilterGroupID
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(result)
+			index[key] = i
+			result = append(result, landingKeyword{key: key})
+		}
+		result[i].checks = append(result[i].checks, check)
+	}
+	return result
+}
+
+func sortLandingKeys(keys []landingKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].keywordID != keys[j].keywordID {
+			return keys[i].keywordID < keys[j].keywordID
+		}
+		if keys[i].source != keys[j].source {
+			return keys[i].source < keys[j].source
+		}
+		return keys[i].filterGroupID < keys[j].filterGroupID
+	})
+}
+
+// detectURLChanges находит смены URL между соседними проверками дня. rotating - URL вернулся
+// к одному из ранжировавшихся раньше, то есть страницы сменяют друг друга
+func detectURLChanges(checks []*entities.LandingCheck) ([]entities.URLChange, bool) {
+	var changes []entities.URLChange
+	rotating := false
+	seen := make(map[string]bool)
+
+	for i, check := range checks {
+		current := normalizeLandingURL(check.URL)
+		if i > 0 {
+			previous := checks[i-1]
+			if normalizeLandingURL(previous.URL) != current {
+				changes = append(changes, entities.URLChange{
+					Date:         check.Date,
+					PreviousURL:  previous.URL,
+					URL:          check.URL,
+					PreviousRank: previous.Rank,
+					Rank:         check.Rank,
+				})
+				if seen[current] {
+					rotating = true
+				}
+			}
+		}
+		seen[current] = true
+	}
+
+	return changes, rotating
+}
+
+// sharedChecks оставляет строки проверок, где после нормализации осталось больше одного URL сайта
+func sharedChecks(rows []*entities.LandingCheck) []*entities.LandingCheck {
+	urls := make(map[int]map[string]bool)
+	for _, row := range rows {
+		if urls[row.PositionID] == nil {
+			urls[row.PositionID] = make(map[string]bool)
+		}
+		urls[row.PositionID][normalizeLandingURL(row.URL)] = true
+	}
+
+	var result []*entities.LandingCheck
+	for _, row := range rows {
+		if len(urls[row.PositionID]) > 1 {
+			result = append(result, row)
+		}
+	}
+	return result
+}
+
+func countPositions(rows []*entities.LandingCheck) int {
+	positions := make(map[int]bool)
+	for _, row := range rows {
+		positions[row.PositionID] = true
+	}
+	return len(positions)
+}
+
+// buildURLHistories собирает позиции по URL: за день берется лучшая позиция, адресом URL служит последний встреченный вариант
+func buildURLHistories(checks []*entities.LandingCheck) []entities.URLHistory {
+	histories := make(map[string]*entities.URLHistory)
+	days := make(map[string]map[time.Time]int)
+	var order []string
+
+	for _, check := range checks {
+		key := normalizeLandingURL(check.URL)
+		history, ok := histories[key]
+		if !ok {
+			history = &entities.URLHistory{FirstSeen: check.Date, LastSeen: check.Date, BestRank: check.Rank}
+			histories[key] = history
+			days[key] = make(map[time.Time]int)
+			order = append(order, key)
+		}
+
+		if !check.Date.Before(history.LastSeen) {
+			history.URL = check.URL
+			history.LastSeen = check.Date
+		}
+		if check.Date.Before(history.FirstSeen) {
+			history.FirstSeen = check.Date
+		}
+		if check.Rank < history.BestRank {
+			history.BestRank = check.Rank
+		}
+		if rank, ok := days[key][check.Date]; !ok || check.Rank < rank {
+			days[key][check.Date] = check.Rank
+		}
+	}
+
+	result := make([]entities.URLHistory, 0, len(order))
+	for _, key := range order {
+		history := histories[key]
+		for date, rank := range days[key] {
+			history.Ranks = append(history.Ranks, entities.URLRank{Date: date, Rank: rank})
+		}
+		sort.Slice(history.Ranks, func(i, j int) bool {
+			return history.Ranks[i].Date.Before(history.Ranks[j].Date)
+		})
+		result = append(result, *history)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].FirstSeen.Before(result[j].FirstSeen)
+	})
+	return result
+}
+
+// normalizeLandingURL приводит URL к виду host/path?query без схемы, www, фрагмента и завершающего слеша,
+// чтобы http/https и варианты записи одной страницы не считались сменой URL
+func normalizeLandingURL(rawURL string) string {
+	value := strings.TrimSpace(rawURL)
+	if !strings.Contains(value, "://") {
+		value = "http://" + value
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(rawURL))
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	normalized := host + strings.TrimSuffix(parsed.EscapedPath(), "/")
+	if parsed.RawQuery != "" {
+		normalized += "?" + parsed.RawQuery
+	}
+	return normalized
+}
+
+// pageBounds границы страницы в срезе из total элементов; страницы считаются с 1
+func pageBounds(total, page, perPage int) (int, int) {
+	page, perPage = normalizePage(page, perPage)
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+	return start, end
+}
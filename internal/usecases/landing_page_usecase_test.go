@@ -0,0 +1,163 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+type fakeLandingPageRepository struct {
+	ranking []*entities.LandingCheck
+	shared  []*entities.LandingCheck
+}
+
+func (r *fakeLandingPageRepository) GetRankingURLs(siteID int, source *string, groupID *int, dateFrom, dateTo time.Time) ([]*entities.LandingCheck, error) {
+	return r.ranking, nil
+}
+
+func (r *fakeLandingPageRepository) GetSharedSerpURLs(siteID int, domain string, source *string, groupID *int, dateFrom, dateTo time.Time) ([]*entities.LandingCheck, error) {
+	return r.shared, nil
+}
+
+func landingDay(d int) time.Time {
+	return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC)
+}
+
+func landingCheck(keywordID, day int, url string, rank int) *entities.LandingCheck {
+	return &entities.LandingCheck{KeywordID: keywordID, Source: entities.GoogleSearch, Date: landingDay(day), URL: url, Rank: rank}
+}
+
+func TestNormalizeLandingURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "https://www.Own.com/catalog/", want: "own.com/catalog"},
+		{raw: "http://own.com/catalog#reviews", want: "own.com/catalog"},
+		{raw: "own.com/catalog?page=2", want: "own.com/catalog?page=2"},
+		{raw: "https://blog.own.com/", want: "blog.own.com"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeLandingURL(tt.raw); got != tt.want {
+			t.Errorf("normalizeLandingURL(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestGetURLChanges(t *testing.T) {
+	sites, _ := newScopeRepositories()
+	repo := &fakeLandingPageRepository{ranking: []*entities.LandingCheck{
+		landingCheck(1, 1, "https://own.com/a", 3),
+		// Другая запись того же URL сменой не считается
+		landingCheck(1, 2, "http://www.own.com/a/", 4),
+		landingCheck(1, 3, "https://own.com/b", 5),
+		landingCheck(2, 1, "https://own.com/c", 1),
+		landingCheck(2, 2, "https://own.com/c", 2),
+	}}
+	uc := NewLandingPageUseCase(repo, sites)
+
+	result, total, err := uc.GetURLChanges(intPtr(1), 1, nil, nil, nil, nil, 1, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 1 || len(result) != 1 || result[0].KeywordID != 1 {
+		t.Fatalf("expected only keyword 1, got %d: %+v", total, result)
+	}
+
+	changes := result[0].Changes
+	if len(changes) != 1 || changes[0].PreviousURL != "http://www.own.com/a/" || changes[0].URL != "https://own.com/b" ||
+		changes[0].PreviousRank != 4 || changes[0].Rank != 5 || !changes[0].Date.Equal(landingDay(3)) {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+
+	urls := result[0].URLs
+	if len(urls) != 2 {
+		t.Fatalf("expected 2 URL histories, got %+v", urls)
+	}
+	// Адрес URL - последний встреченный вариант, лучшая позиция - за весь период
+	if urls[0].URL != "http://www.own.com/a/" || urls[0].BestRank != 3 || len(urls[0].Ranks) != 2 || !urls[0].LastSeen.Equal(landingDay(2)) {
+		t.Fatalf("unexpected history of the first URL %+v", urls[0])
+	}
+	if urls[1].URL != "https://own.com/b" || !urls[1].FirstSeen.Equal(landingDay(3)) {
+		t.Fatalf("unexpected history of the second URL %+v", urls[1])
+	}
+}
+
+func TestGetCannibalization(t *testing.T) {
+	sites, _ := newScopeRepositories()
+	shared := func(positionID, keywordID int, url string, rank int) *entities.LandingCheck {
+		check := landingCheck(keywordID, 2, url, rank)
+		check.PositionID = positionID
+		return check
+	}
+	repo := &fakeLandingPageRepository{
+		ranking: []*entities.LandingCheck{
+			// URL сменился и вернулся: страницы сменяют друг друга
+			landingCheck(1, 1, "https://own.com/a", 3),
+			landingCheck(1, 2, "https://own.com/b", 6),
+			landingCheck(1, 3, "https://own.com/a", 4),
+			// Одна смена без возврата каннибализацией не считается
+			landingCheck(2, 1, "https://own.com/c", 2),
+			landingCheck(2, 2, "https://own.com/d", 2),
+		},
+		shared: []*entities.LandingCheck{
+			shared(100, 3, "https://own.com/e", 4),
+			shared(100, 3, "https://blog.own.com/e", 9),
+			// http и https одной страницы - один URL
+			shared(200, 4, "http://own.com/f", 5),
+			shared(200, 4, "https://own.com/f", 7),
+		},
+	}
+	uc := NewLandingPageUseCase(repo, sites)
+
+	result, total, err := uc.GetCannibalization(intPtr(1), 1, nil, nil, nil, nil, 1, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 2 || len(result) != 2 {
+		t.Fatalf("expected keywords 1 and 3, got %d: %+v", total, result)
+	}
+	if c := result[0]; c.KeywordID != 1 || !c.Rotating || c.SharedChecks != 0 || len(c.URLs) != 2 {
+		t.Fatalf("unexpected rotating case %+v", c)
+	}
+	if c := result[1]; c.KeywordID != 3 || c.Rotating || c.SharedChecks != 1 || len(c.URLs) != 2 {
+		t.Fatalf("unexpected shared case %+v", c)
+	}
+
+	// Страницы считаются по найденным случаям
+	page, total, err := uc.GetCannibalization(intPtr(1), 1, nil, nil, nil, nil, 2, 1)
+	if err != nil || total != 2 || len(page) != 1 || page[0].KeywordID != 3 {
+		t.Fatalf("expected keyword 3 on the second page, got %d: %+v, %v", total, page, err)
+	}
+}
+
+func TestLandingPageErrors(t *testing.T) {
+	sites, _ := newScopeRepositories()
+	uc := NewLandingPageUseCase(&fakeLandingPageRepository{}, sites)
+	from, to := landingDay(1), landingDay(1).AddDate(0, 0, maxLandingPageDays)
+	before := landingDay(1).AddDate(0, 0, -1)
+
+	tests := []struct {
+		name     string
+		siteID   int
+		from, to *time.Time
+		code     string
+	}{
+		{name: "other workspace site", siteID: 2, code: ErrorSiteNotFound},
+		{name: "reversed period", siteID: 1, from: &from, to: &before, code: ErrorValidation},
+		{name: "period too long", siteID: 1, from: &from, to: &to, code: ErrorValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := uc.GetURLChanges(intPtr(1), tt.siteID, nil, nil, tt.from, tt.to, 1, 20); GetDomainErrorCode(err) != tt.code {
+				t.Fatalf("url changes: expected %s, got %v", tt.code, err)
+			}
+			if _, _, err := uc.GetCannibalization(intPtr(1), tt.siteID, nil, nil, tt.from, tt.to, 1, 20); GetDomainErrorCode(err) != tt.code {
+				t.Fatalf("cannibalization: expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
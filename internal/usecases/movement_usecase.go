@@ -0,0 +1,183 @@
+package usecases
+
+import (
+	"sort"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+)
+
+type MovementUseCase struct {
+	movementRepo repositories.MovementRepository
+	siteRepo     repositories.SiteRepository
+}
+
+func NewMovementUseCase(movementRepo repositories.MovementRepository, siteRepo repositories.SiteRepository) *MovementUseCase {
+	return &MovementUseCase{
+		movementRepo: movementRepo,
+		siteRepo:     siteRepo,
+	}
+}
+
+// GetMovement сравнивает позиции сайта по источнику в две даты проверки. Без dateTo берется последняя
+// проверка, без dateFrom - предыдущая перед dateTo. groupID ограничивает отчет группой ключевых слов,
+// status - только список ключевых слов. Если сравнивать не с чем, отчет пустой и без дат
+func (uc *MovementUseCase) GetMovement(workspaceID *int, siteID int, source string, groupID, filterGroupID *int, dateFrom, dateTo *time.Time, status string) (*entities.MovementReport, error) {
+	if source != entities.GoogleSearch && source != entities.YandexSearch {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "Movement is available for google and yandex sources",
+		}
+	}
+	if dateFrom != nil && dateTo != nil && !dateFrom.Before(*dateTo) {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "date_from must be before date_to",
+		}
+	}
+
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
+	report, err := uc.buildReport(siteID, source, filterGroupID, dateFrom, dateTo, groupID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorMovementFetch,
+			Message: "Failed to calculate position movement",
+			Err:     err,
+		}
+	}
+
+	if status != "" {
+		keywords := make([]*entities.KeywordMovement, 0, len(report.Keywords))
+		for _, movement := range report.Keywords {
+			if movement.Status == status {
+				keywords = append(keywords, movement)
+			}
+		}
+		report.Keywords = keywords
+	}
+
+	return report, nil
+}
+
+// UpdateLatest пересчитывает сводку движения между двумя последними проверками сайта и сохраняет ее в сайте
+func (uc *MovementUseCase) UpdateLatest(siteID int, source string) error {
+	report, err := uc.buildReport(siteID, source, nil, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var summary *entities.MovementSummary
+	if !report.Summary.CurrentDate.IsZero() {
+		summary = &report.Summary
+	}
+	return uc.siteRepo.UpdateMovement(siteID, source, summary)
+}
+
+func (uc *MovementUseCase) buildReport(siteID int, source string, filterGroupID *int, dateFrom, dateTo *time.Time, groupID *int) (*entities.MovementReport, error) {
+	report := &entities.MovementReport{
+		SiteID:   siteID,
+		Source:   source,
+		Groups:   []entities.GroupMovement{},
+		Keywords: []*entities.KeywordMovement{},
+	}
+
+	previous, current, found, err := uc.resolveDates(siteID, source, filterGroupID, dateFrom, dateTo)
+	if err != nil || !found {
+		return report, err
+	}
+
+	movements, err := uc.movementRepo.GetKeywordMovement(siteID, source, filterGroupID, previous, current)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Summary = entities.MovementSummary{PreviousDate: previous, CurrentDate: current}
+	groups := make(map[int]*entities.GroupMovement)
+	for _, movement := range movements {
+		if groupID != nil && (movement.GroupID == nil || *movement.GroupID != *groupID) {
+			continue
+		}
+
+		classifyMovement(movement)
+		report.Keywords = append(report.Keywords, movement)
+		report.Summary.Add(movement)
+
+		key := 0
+		if movement.GroupID != nil {
+			key = *movement.GroupID
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &entities.GroupMovement{
+				GroupID: movement.GroupID,
+				Summary: entities.MovementSummary{PreviousDate: previous, CurrentDate: current},
+			}
+			groups[key] = group
+		}
+		group.Summary.Add(movement)
+	}
+
+	keys := make([]int, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	for _, key := range keys {
+		report.Groups = append(report.Groups, *groups[key])
+	}
+
+	return report, nil
+}
+
+// resolveDates подставляет недостающие даты по дням проверок; found false - второй даты для сравнения нет
+func (uc *MovementUseCase) resolveDates(siteID int, source string, filterGroupID *int, dateFrom, dateTo *time.Time) (time.Time, time.Time, bool, error) {
+	var current time.Time
+	if dateTo != nil {
+		current = startOfDay(*dateTo)
+	} else {
+		days, err := uc.movementRepo.GetCheckDays(siteID, source, filterGroupID, time.Now(), 1)
+		if err != nil || len(days) == 0 {
+			return time.Time{}, time.Time{}, false, err
+		}
+		current = days[0]
+	}
+
+	if dateFrom != nil {
+		return startOfDay(*dateFrom), current, true, nil
+	}
+
+	days, err := uc.movementRepo.GetCheckDays(siteID, source, filterGroupID, current.AddDate(0, 0, -1), 1)
+	if err != nil || len(days) == 0 {
+		return time.Time{}, time.Time{}, false, err
+	}
+	return days[0], current, true, nil
+}
+
+// classifyMovement заполняет Delta и Status; "не найдено" считается позицией MovementNotFoundRank
+func classifyMovement(movement *entities.KeywordMovement) {
+	previous, current := movement.PreviousRank, movement.CurrentRank
+	if previous == 0 {
+		previous = entities.MovementNotFoundRank
+	}
+	if current == 0 {
+		current = entities.MovementNotFoundRank
+	}
+	movement.Delta = previous - current
+
+	switch {
+	case movement.PreviousRank == 0 && movement.CurrentRank > 0:
+		movement.Status = entities.MovementNew
+	case movement.PreviousRank > 0 && movement.CurrentRank == 0:
+		movement.Status = entities.MovementLost
+	case movement.Delta > 0:
+		movement.Status = entities.MovementUp
+	case movement.Delta < 0:
+		movement.Status = entities.MovementDown
+	default:
+		movement.Status = entities.MovementUnchanged
+	}
+}
@@ -0,0 +1,191 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+)
+
+// stubMovementRepository отдает дни проверок (по убыванию) и позиции ключевых слов между двумя днями
+type stubMovementRepository struct {
+	repositories.MovementRepository
+	days      []time.Time
+	movements []*entities.KeywordMovement
+	compared  [2]time.Time
+}
+
+func (r *stubMovementRepository) GetCheckDays(siteID int, source string, filterGroupID *int, until time.Time, limit int) ([]time.Time, error) {
+	var days []time.Time
+	for _, day := range r.days {
+		if !day.After(until) && len(days) < limit {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+func (r *stubMovementRepository) GetKeywordMovement(siteID int, source string, filterGroupID *int, previous, current time.Time) ([]*entities.KeywordMovement, error) {
+	r.compared = [2]time.Time{previous, current}
+	// Отчет заполняет Delta и Status, поэтому каждый вызов получает свои копии
+	movements := make([]*entities.KeywordMovement, len(r.movements))
+	for i, movement := range r.movements {
+		copied := *movement
+		movements[i] = &copied
+	}
+	return movements, nil
+}
+
+type movementSiteRepository struct {
+	*fakeSiteRepository
+	saved map[string]*entities.MovementSummary
+}
+
+func (r *movementSiteRepository) UpdateMovement(siteID int, source string, summary *entities.MovementSummary) error {
+	r.saved[source] = summary
+	return nil
+}
+
+func TestClassifyMovement(t *testing.T) {
+	tests := []struct {
+		name              string
+		previous, current int
+		delta             int
+		status            string
+	}{
+		{name: "up", previous: 10, current: 3, delta: 7, status: entities.MovementUp},
+		{name: "down", previous: 3, current: 10, delta: -7, status: entities.MovementDown},
+		{name: "new", previous: 0, current: 50, delta: 51, status: entities.MovementNew},
+		{name: "lost", previous: 50, current: 0, delta: -51, status: entities.MovementLost},
+		{name: "unchanged", previous: 4, current: 4, status: entities.MovementUnchanged},
+		{name: "not found twice", previous: 0, current: 0, status: entities.MovementUnchanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movement := &entities.KeywordMovement{PreviousRank: tt.previous, CurrentRank: tt.current}
+			classifyMovement(movement)
+			if movement.Delta != tt.delta || movement.Status != tt.status {
+				t.Fatalf("expected %d/%s, got %d/%s", tt.delta, tt.status, movement.Delta, movement.Status)
+			}
+		})
+	}
+}
+
+func newMovementUseCase(days []time.Time) (*MovementUseCase, *stubMovementRepository, *movementSiteRepository) {
+	sites, _ := newScopeRepositories()
+	movementRepo := &stubMovementRepository{
+		days: days,
+		movements: []*entities.KeywordMovement{
+			{KeywordID: 1, GroupID: intPtr(5), PreviousRank: 10, CurrentRank: 3},
+			{KeywordID: 2, GroupID: intPtr(5), PreviousRank: 3, CurrentRank: 0},
+			{KeywordID: 3, PreviousRank: 0, CurrentRank: 20},
+		},
+	}
+	siteRepo := &movementSiteRepository{fakeSiteRepository: sites, saved: make(map[string]*entities.MovementSummary)}
+	return NewMovementUseCase(movementRepo, siteRepo), movementRepo, siteRepo
+}
+
+func TestGetMovementComparesLastTwoChecks(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	uc, repo, _ := newMovementUseCase([]time.Time{day(10), day(7), day(3)})
+
+	report, err := uc.GetMovement(intPtr(1), 1, entities.GoogleSearch, nil, nil, nil, nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !repo.compared[0].Equal(day(7)) || !repo.compared[1].Equal(day(10)) {
+		t.Fatalf("expected the last two check days, compared %v", repo.compared)
+	}
+
+	want := entities.MovementSummary{PreviousDate: day(7), CurrentDate: day(10), Up: 1, New: 1, Lost: 1, NetScore: 7 - 98 + 81}
+	if report.Summary != want {
+		t.Fatalf("expected summary %+v, got %+v", want, report.Summary)
+	}
+	if len(report.Groups) != 2 || report.Groups[0].GroupID != nil || report.Groups[1].Summary.Up != 1 || report.Groups[1].Summary.Lost != 1 {
+		t.Fatalf("unexpected groups %+v", report.Groups)
+	}
+
+	// Группа ключевых слов и статус сужают отчет
+	report, err = uc.GetMovement(intPtr(1), 1, entities.GoogleSearch, intPtr(5), nil, nil, nil, entities.MovementLost)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Summary.Up != 1 || report.Summary.Lost != 1 || report.Summary.New != 0 {
+		t.Fatalf("expected the group summary, got %+v", report.Summary)
+	}
+	if len(report.Keywords) != 1 || report.Keywords[0].KeywordID != 2 {
+		t.Fatalf("expected only the lost keyword, got %+v", report.Keywords)
+	}
+
+	// Без dateFrom берется проверка перед dateTo
+	dateTo := day(7)
+	if _, err := uc.GetMovement(intPtr(1), 1, entities.GoogleSearch, nil, nil, nil, &dateTo, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !repo.compared[0].Equal(day(3)) || !repo.compared[1].Equal(day(7)) {
+		t.Fatalf("expected the check before date_to, compared %v", repo.compared)
+	}
+}
+
+func TestGetMovementWithoutPreviousCheck(t *testing.T) {
+	uc, _, _ := newMovementUseCase([]time.Time{time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)})
+
+	report, err := uc.GetMovement(intPtr(1), 1, entities.YandexSearch, nil, nil, nil, nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Summary.CurrentDate.IsZero() || len(report.Keywords) != 0 || len(report.Groups) != 0 {
+		t.Fatalf("expected an empty report, got %+v", report)
+	}
+}
+
+func TestGetMovementErrors(t *testing.T) {
+	uc, _, _ := newMovementUseCase(nil)
+	from := time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name     string
+		siteID   int
+		source   string
+		from, to *time.Time
+		code     string
+	}{
+		{name: "wordstat source", siteID: 1, source: "wordstat", code: ErrorValidation},
+		{name: "reversed dates", siteID: 1, source: entities.GoogleSearch, from: &from, to: &to, code: ErrorValidation},
+		{name: "other workspace site", siteID: 2, source: entities.GoogleSearch, code: ErrorSiteNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := uc.GetMovement(intPtr(1), tt.siteID, tt.source, nil, nil, tt.from, tt.to, "")
+			if GetDomainErrorCode(err) != tt.code || report != nil {
+				t.Fatalf("expected %s, got %+v and error %v", tt.code, report, err)
+			}
+		})
+	}
+}
+
+func TestUpdateLatestMovement(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+
+	uc, _, sites := newMovementUseCase([]time.Time{day(10), day(7)})
+	if err := uc.UpdateLatest(1, entities.GoogleSearch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary := sites.saved[entities.GoogleSearch]; summary == nil || !summary.CurrentDate.Equal(day(10)) || summary.Up != 1 {
+		t.Fatalf("expected the latest summary to be saved, got %+v", summary)
+	}
+
+	// Одна проверка: сравнивать не с чем, сводка сбрасывается
+	uc, _, sites = newMovementUseCase([]time.Time{day(10)})
+	sites.saved[entities.GoogleSearch] = &entities.MovementSummary{}
+	if err := uc.UpdateLatest(1, entities.GoogleSearch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary, ok := sites.saved[entities.GoogleSearch]; !ok || summary != nil {
+		t.Fatalf("expected the summary to be cleared, got %+v", summary)
+	}
+}
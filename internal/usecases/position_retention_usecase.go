@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go-seo/internal/domain/repositories"
+)
+
+// minDetailRetentionDays тренды статистики строятся по детальным позициям за последние 30 дней
+const minDetailRetentionDays = 31
+
+// PositionRetentionSettings сроки хранения позиций в днях; 0 - не сворачивать
+type PositionRetentionSettings struct {
+	DetailDays      int // Детальные позиции старше сворачиваются в дневные агрегаты
+	DailyRollupDays int // Дневные агрегаты старше сворачиваются в недельные
+	SnapshotDays    int // SERP снапшоты старше удаляются; срок не зависит от сворачивания позиций
+	PartitionsAhead int // На сколько месяцев вперед создаются секции positions
+}
+
+type PositionRetentionUseCase struct {
+	retentionRepo repositories.PositionRetentionRepository
+	settings      PositionRetentionSettings
+}
+
+func NewPositionRetentionUseCase(retentionRepo repositories.PositionRetentionRepository, settings PositionRetentionSettings) *PositionRetentionUseCase {
+	if settings.DetailDays > 0 && settings.DetailDays < minDetailRetentionDays {
+		log.Printf("WARNING: Position detail retention %d days is too short, using %d", settings.DetailDays, minDetailRetentionDays)
+		settings.DetailDays = minDetailRetentionDays
+	}
+	// Недельные агрегаты не должны захватывать дни, для которых еще хранятся детальные позиции
+	if settings.DailyRollupDays > 0 && settings.DailyRollupDays < settings.DetailDays {
+		settings.DailyRollupDays = settings.DetailDays
+	}
+
+	return &PositionRetentionUseCase{
+		retentionRepo: retentionRepo,
+		settings:      settings,
+	}
+}
+
+// Run выполняет обслуживание позиций каждые interval, пока не отменен ctx
+func (uc *PositionRetentionUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	uc.runAndLog(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			uc.runAndLog(now)
+		}
+	}
+}
+
+func (uc *PositionRetentionUseCase) runAndLog(now time.Time) {
+	if err := uc.RunRetention(now); err != nil {
+		log.Printf("ERROR: Position retention failed: %v", err)
+	}
+}
+
+// RunRetention создает секции positions на ближайшие месяцы и сворачивает позиции старше сроков хранения.
+// Границы выровнены по дням и неделям, поэтому детальные позиции, дневные и недельные агрегаты не пересекаются
+func (uc *PositionRetentionUseCase) RunRetention(now time.Time) error {
+	if err := uc.retentionRepo.EnsurePartitions(now, now.AddDate(0, uc.settings.PartitionsAhead, 0)); err != nil {
+		return err
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if uc.settings.DetailDays > 0 {
+		before := today.AddDate(0, 0, -uc.settings.DetailDays)
+		written, err := uc.retentionRepo.RollupDays(before)
+		if err != nil {
+			return fmt.Errorf("failed to roll up positions before %s: %w", before.Format("2006-01-02"), err)
+		}
+		if written > 0 {
+			log.Printf("Rolled up positions before %s into %d daily rollups", before.Format("2006-01-02"), written)
+		}
+	}
+
+	if uc.settings.DailyRollupDays > 0 {
+		before := weekStart(today.AddDate(0, 0, -uc.settings.DailyRollupDays))
+		written, err := uc.retentionRepo.RollupWeeks(before)
+		if err != nil {
+			return fmt.Errorf("failed to roll up daily rollups before %s: %w", before.Format("2006-01-02"), err)
+		}
+		if written > 0 {
+			log.Printf("Rolled up daily rollups before %s into %d weekly rollups", before.Format("2006-01-02"), written)
+		}
+	}
+
+	if uc.settings.SnapshotDays > 0 {
+		before := today.AddDate(0, 0, -uc.settings.SnapshotDays)
+		deleted, err := uc.retentionRepo.DeleteSnapshots(before)
+		if err != nil {
+			return fmt.Errorf("failed to delete SERP snapshots before %s: %w", before.Format("2006-01-02"), err)
+		}
+		if deleted > 0 {
+			log.Printf("Deleted %d SERP snapshot rows before %s", deleted, before.Format("2006-01-02"))
+		}
+	}
+
+	return nil
+}
+
+// weekStart понедельник недели day, как date_trunc('week') в PostgreSQL
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
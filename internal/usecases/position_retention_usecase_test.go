@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeRetentionRepository запоминает границы, с которыми вызывалось обслуживание
+type fakeRetentionRepository struct {
+	rollupDays      []time.Time
+	rollupWeeks     []time.Time
+	snapshotsBefore []time.Time
+}
+
+func (r *fakeRetentionRepository) EnsurePartitions(from, to time.Time) error {
+	return nil
+}
+
+func (r *fakeRetentionRepository) RollupDays(before time.Time) (int64, error) {
+	r.rollupDays = append(r.rollupDays, before)
+	return 0, nil
+}
+
+func (r *fakeRetentionRepository) RollupWeeks(before time.Time) (int64, error) {
+	r.rollupWeeks = append(r.rollupWeeks, before)
+	return 0, nil
+}
+
+func (r *fakeRetentionRepository) DeleteSnapshots(before time.Time) (int64, error) {
+	r.snapshotsBefore = append(r.snapshotsBefore, before)
+	return 0, nil
+}
+
+func TestRunRetention(t *testing.T) {
+	// Четверг; неделя начинается в понедельник
+	now := time.Date(2026, 10, 15, 13, 30, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name          string
+		settings      PositionRetentionSettings
+		wantDays      []time.Time
+		wantWeeks     []time.Time
+		wantSnapshots []time.Time
+	}{
+		{name: "nothing configured"},
+		{
+			// Снапшоты хранятся бессрочно, хотя позиции сворачиваются
+			name:      "positions only keep snapshots",
+			settings:  PositionRetentionSettings{DetailDays: 90, DailyRollupDays: 365},
+			wantDays:  []time.Time{day(7, 17)},
+			wantWeeks: []time.Time{day(10, 13).AddDate(-1, 0, 0)},
+		},
+		{
+			name:          "snapshots only keep positions",
+			settings:      PositionRetentionSettings{SnapshotDays: 30},
+			wantSnapshots: []time.Time{day(9, 15)},
+		},
+		{
+			name:          "separate windows",
+			settings:      PositionRetentionSettings{DetailDays: 90, SnapshotDays: 400},
+			wantDays:      []time.Time{day(7, 17)},
+			wantSnapshots: []time.Time{day(9, 10).AddDate(-1, 0, 0)},
+		},
+		{
+			name:          "short detail window is raised",
+			settings:      PositionRetentionSettings{DetailDays: 7, DailyRollupDays: 14, SnapshotDays: 7},
+			wantDays:      []time.Time{day(9, 14)},
+			wantWeeks:     []time.Time{day(9, 14)},
+			wantSnapshots: []time.Time{day(10, 8)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRetentionRepository{}
+			uc := NewPositionRetentionUseCase(repo, tt.settings)
+
+			if err := uc.RunRetention(now); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assertBoundaries(t, "daily rollup", repo.rollupDays, tt.wantDays)
+			assertBoundaries(t, "weekly rollup", repo.rollupWeeks, tt.wantWeeks)
+			assertBoundaries(t, "snapshot deletion", repo.snapshotsBefore, tt.wantSnapshots)
+		})
+	}
+}
+
+func assertBoundaries(t *testing.T, step string, got, want []time.Time) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected boundaries %v, got %v", step, want, got)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Fatalf("%s: expected boundaries %v, got %v", step, want, got)
+		}
+	}
+}
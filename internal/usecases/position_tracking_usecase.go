@@ -3,12 +3,12 @@ package usecases
 import (
 	"context"
 	"fmt"
-	"strings"
 	"sync"
 	"time"
 
 	"go-seo/internal/domain/entities"
 	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
 	"go-seo/internal/infrastructure/services"
 )
 
@@ -16,46 +16,58 @@ type PositionTrackingUseCase struct {
 	siteRepo       repositories.SiteRepository
 	keywordRepo    repositories.KeywordRepository
 	positionRepo   repositories.PositionRepository
-	xmlRiver       *services.XMLRiverService
-	xmlStock       *services.XMLRiverService
+	snapshotRepo   repositories.SerpSnapshotRepository
+	competitorRepo repositories.CompetitorRepository
+	usageRepo      repositories.UsageRepository
+	accounts       *ProviderAccountUseCase
+	providers      domainservices.SearchProviderRegistry
 	wordstat       *services.WordstatService
-	xmlRiverSoftID string
-	xmlStockSoftID string
+	// Сколько keyword проверяется одновременно
+	workerCount int
 }
 
 func NewPositionTrackingUseCase(
 	siteRepo repositories.SiteRepository,
 	keywordRepo repositories.KeywordRepository,
 	positionRepo repositories.PositionRepository,
-	xmlRiver *services.XMLRiverService,
-	xmlStock *services.XMLRiverService,
+	snapshotRepo repositories.SerpSnapshotRepository,
+	competitorRepo repositories.CompetitorRepository,
+	usageRepo repositories.UsageRepository,
+	accounts *ProviderAccountUseCase,
+	providers domainservices.SearchProviderRegistry,
 	wordstat *services.WordstatService,
-	xmlRiverSoftID string,
-	xmlStockSoftID string,
+	workerCount int,
 ) *PositionTrackingUseCase {
 	return &PositionTrackingUseCase{
 		siteRepo:       siteRepo,
 		keywordRepo:    keywordRepo,
 		positionRepo:   positionRepo,
-		xmlRiver:       xmlRiver,
-		xmlStock:       xmlStock,
+		snapshotRepo:   snapshotRepo,
+		competitorRepo: competitorRepo,
+		usageRepo:      usageRepo,
+		accounts:       accounts,
+		providers:      providers,
 		wordstat:       wordstat,
-		xmlRiverSoftID: xmlRiverSoftID,
-		xmlStockSoftID: xmlStockSoftID,
+		workerCount:    workerCount,
 	}
 }
 
 func (uc *PositionTrackingUseCase) TrackGooglePositions(
-	siteID int, device, os string, ads bool, country, lang string, pages int, subdomains bool,
-	xmlUserID, xmlAPIKey, xmlBaseURL, tbs string, filter, highlights, nfpr, loc, ai int, raw string,
+	workspaceID *int, siteID int, device, os string, ads bool, country, lang string, pages int, subdomains bool,
+	accountID *int, provider, tbs string, filter *int, highlights, nfpr, loc, ai int, raw string,
 ) (int, error) {
-	site, err := uc.siteRepo.GetByID(siteID)
+	if err := validateTrackingParams(raw, 0); err != nil {
+		return 0, err
+	}
+
+	site, err := authorizeSite(uc.siteRepo, workspaceID, siteID)
 	if err != nil {
-		return 0, &DomainError{
-			Code:    ErrorPositionFetch,
-			Message: "Site not found",
-			Err:     err,
-		}
+		return 0, err
+	}
+
+	searchProvider, err := uc.resolveProvider(site, accountID, provider, entities.GoogleSearch, pages)
+	if err != nil {
+		return 0, err
 	}
 
 	keywords, err := uc.keywordRepo.GetBySiteID(siteID)
@@ -67,50 +79,38 @@ func (uc *PositionTrackingUseCase) TrackGooglePositions(
 		}
 	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var count int
-	var firstError error
-
-	for _, keyword := range keywords {
-		wg.Add(1)
-		go func(kw *entities.Keyword) {
-			defer wg.Done()
-
-			err := uc.trackGoogleKeywordPosition(site, kw, device, os, ads, country, lang, pages, subdomains,
-				xmlUserID, xmlAPIKey, xmlBaseURL, tbs, filter, highlights, nfpr, loc, ai, raw)
-
-			mu.Lock()
-			if err != nil && firstError == nil {
-				firstError = err
-			} else if err == nil {
-				count++
-			}
-			mu.Unlock()
-		}(keyword)
-	}
-
-	wg.Wait()
-
-	if firstError != nil {
-		return count, firstError
+	competitors, err := uc.competitorRepo.GetBySiteID(siteID)
+	if err != nil {
+		return 0, &DomainError{
+			Code:    ErrorCompetitorFetch,
+			Message: fmt.Sprintf("Failed to fetch competitors for site %s", site.Domain),
+			Err:     err,
+		}
 	}
 
-	return count, nil
+	return trackKeywords(keywords, uc.workerCount, func(kw *entities.Keyword) error {
+		return uc.trackGoogleKeywordPosition(site, competitors, kw, device, os, ads, country, lang, pages, subdomains,
+			searchProvider, tbs, filter, highlights, nfpr, loc, ai, raw)
+	})
 }
 
 func (uc *PositionTrackingUseCase) TrackYandexPositions(
-	siteID int, device, os string, ads bool, country, lang string, pages int, subdomains bool,
-	xmlUserID, xmlAPIKey, xmlBaseURL string, groupBy, filter, highlights, within, lr int, raw string, inIndex, strict int,
+	workspaceID *int, siteID int, device, os string, ads bool, country, lang string, pages int, subdomains bool,
+	accountID *int, provider string, groupBy int, filter *int, highlights, within, lr int, raw string, inIndex, strict int,
 	organic bool,
 ) (int, error) {
-	site, err := uc.siteRepo.GetByID(siteID)
+	if err := validateTrackingParams(raw, inIndex); err != nil {
+		return 0, err
+	}
+
+	site, err := authorizeSite(uc.siteRepo, workspaceID, siteID)
 	if err != nil {
-		return 0, &DomainError{
-			Code:    ErrorPositionFetch,
-			Message: "Site not found",
-			Err:     err,
-		}
+		return 0, err
+	}
+
+	searchProvider, err := uc.resolveProvider(site, accountID, provider, entities.YandexSearch, pages)
+	if err != nil {
+		return 0, err
 	}
 
 	keywords, err := uc.keywordRepo.GetBySiteID(siteID)
@@ -122,46 +122,34 @@ func (uc *PositionTrackingUseCase) TrackYandexPositions(
 		}
 	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var count int
-	var firstError error
-
-	for _, keyword := range keywords {
-		wg.Add(1)
-		go func(kw *entities.Keyword) {
-			defer wg.Done()
-
-			err := uc.trackYandexKeywordPosition(site, kw, device, os, ads, country, lang, pages, subdomains,
-				xmlUserID, xmlAPIKey, xmlBaseURL, groupBy, filter, highlights, within, lr, raw, inIndex, strict, organic)
-
-			mu.Lock()
-			if err != nil && firstError == nil {
-				firstError = err
-			} else if err == nil {
-				count++
-			}
-			mu.Unlock()
-		}(keyword)
+	competitors, err := uc.competitorRepo.GetBySiteID(siteID)
+	if err != nil {
+		return 0, &DomainError{
+			Code:    ErrorCompetitorFetch,
+			Message: fmt.Sprintf("Failed to fetch competitors for site %s", site.Domain),
+			Err:     err,
+		}
 	}
 
-	wg.Wait()
+	return trackKeywords(keywords, uc.workerCount, func(kw *entities.Keyword) error {
+		return uc.trackYandexKeywordPosition(site, competitors, kw, device, os, ads, country, lang, pages, subdomains,
+			searchProvider, groupBy, filter, highlights, within, lr, raw, inIndex, strict, organic)
+	})
+}
 
-	if firstError != nil {
-		return count, firstError
+func (uc *PositionTrackingUseCase) TrackWordstatPositions(workspaceID *int, siteID int, accountID *int, regions *int) (int, error) {
+	site, err := authorizeSite(uc.siteRepo, workspaceID, siteID)
+	if err != nil {
+		return 0, err
 	}
 
-	return count, nil
-}
-
-func (uc *PositionTrackingUseCase) TrackWordstatPositions(siteID int, xmlUserID, xmlAPIKey, xmlBaseURL string, regions *int) (int, error) {
-	site, err := uc.siteRepo.GetByID(siteID)
+	account, err := uc.accounts.Resolve(site.WorkspaceID, accountID)
 	if err != nil {
-		return 0, &DomainError{
-			Code:    ErrorPositionFetch,
-			Message: "Site not found",
-			Err:     err,
-		}
+		return 0, err
+	}
+	wordstatService, err := resolveWordstat(uc.wordstat, account)
+	if err != nil {
+		return 0, err
 	}
 
 	keywords, err := uc.keywordRepo.GetBySiteID(siteID)
@@ -176,49 +164,57 @@ func (uc *PositionTrackingUseCase) TrackWordstatPositions(siteID int, xmlUserID,
 	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
 	defer cancel()
 
+	// Частоту запросов ограничивает общий лимитер аккаунта внутри Wordstat сервиса
+	return trackKeywords(keywords, uc.workerCount, func(kw *entities.Keyword) error {
+		return uc.trackWordstatKeywordPosition(ctx, wordstatService, kw, regions)
+	})
+}
+
+// trackKeywords проверяет keywords не более чем в workers горутин и возвращает число успешных проверок
+// вместе с первой ошибкой
+func trackKeywords(keywords []*entities.Keyword, workers int, track func(kw *entities.Keyword) error) (int, error) {
+	workers = min(max(workers, 1), len(keywords))
+
+	queue := make(chan *entities.Keyword)
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var count int
 	var firstError error
 
-	semaphore := make(chan struct{}, 10)
-
-	for _, keyword := range keywords {
+	for i := 0; i < workers; i++ {
 		wg.Add(1)
-		go func(kw *entities.Keyword) {
+		go func() {
 			defer wg.Done()
+			for kw := range queue {
+				err := track(kw)
 
-			select {
-			case semaphore <- struct{}{}:
-				defer func() { <-semaphore }()
-			case <-ctx.Done():
 				mu.Lock()
-				if firstError == nil {
-					firstError = ctx.Err()
+				if err != nil && firstError == nil {
+					firstError = err
+				} else if err == nil {
+					count++
 				}
 				mu.Unlock()
-				return
 			}
-
-			err := uc.trackWordstatKeywordPosition(kw, xmlUserID, xmlAPIKey, xmlBaseURL, regions)
-
-			mu.Lock()
-			if err != nil && firstError == nil {
-				firstError = err
-			} else if err == nil {
-				count++
-			}
-			mu.Unlock()
-		}(keyword)
+		}()
 	}
 
+	for _, keyword := range keywords {
+		queue <- keyword
+	}
+	close(queue)
 	wg.Wait()
 
-	if firstError != nil {
-		return count, firstError
-	}
+	return count, firstError
+}
 
-	return count, nil
+// resolveProvider выбирает провайдера с учетными данными аккаунта пространства сайта один раз на весь запуск
+func (uc *PositionTrackingUseCase) resolveProvider(site *entities.Site, accountID *int, name, source string, pages int) (domainservices.SearchService, error) {
+	account, err := uc.accounts.Resolve(site.WorkspaceID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return resolveSearchProvider(uc.providers, name, source, pages, account)
 }
 
 func (uc *PositionTrackingUseCase) trackKeywordPosition(
@@ -234,8 +230,22 @@ func (uc *PositionTrackingUseCase) trackKeywordPosition(
 		return uc.trackWordstatPosition(keyword)
 	}
 
+	provider, err := resolveSearchProvider(uc.providers, "", source, pages, nil)
+	if err != nil {
+		return err
+	}
+
 	// Для общего случая используем organic=false и groupBy=0
-	position, url, title, err := uc.xmlRiver.FindSitePositionWithSubdomains(keyword.Value, site.Domain, source, pages, device, os, ads, country, lang, subdomains, 0, 0, false, 0)
+	req := domainservices.SearchRequest{
+		Query:   keyword.Value,
+		Device:  device,
+		OS:      os,
+		Ads:     ads,
+		Country: country,
+		Lang:    lang,
+	}
+	searchResult, err := provider.FindSitePosition(context.Background(), req, site.Domain, nil, source, pages, subdomains)
+	recordSearchUsage(uc.usageRepo, "", site.ID, source, provider, searchResult)
 	if err != nil {
 		return &DomainError{
 			Code:    ErrorPositionCreation,
@@ -247,9 +257,9 @@ func (uc *PositionTrackingUseCase) trackKeywordPosition(
 	positionEntity := &entities.Position{
 		KeywordID: keyword.ID,
 		SiteID:    site.ID,
-		Rank:      position,
-		URL:       url,
-		Title:     title,
+		Rank:      searchResult.Position,
+		URL:       searchResult.URL,
+		Title:     searchResult.Title,
 		Source:    source,
 		Device:    device,
 		OS:        os,
@@ -268,6 +278,8 @@ func (uc *PositionTrackingUseCase) trackKeywordPosition(
 		}
 	}
 
+	saveSerpSnapshot(uc.snapshotRepo, positionEntity, searchResult.Items)
+
 	return nil
 }
 
@@ -338,8 +350,12 @@ func (uc *PositionTrackingUseCase) GetPositionsHistory(siteID int, keywordID *in
 	return positions, nil
 }
 
-func (uc *PositionTrackingUseCase) GetPositionsHistoryPaginated(siteID int, keywordID *int, source *string, dateFrom, dateTo *time.Time, last bool, page, perPage int) ([]*entities.Position, int64, error) {
-	positions, total, err := uc.positionRepo.GetPositionsHistoryPaginated(siteID, keywordID, source, dateFrom, dateTo, last, page, perPage)
+func (uc *PositionTrackingUseCase) GetPositionsHistoryPaginated(workspaceID *int, siteID int, keywordID *int, tags *entities.TagFilter, source *string, dateFrom, dateTo *time.Time, last bool, page, perPage int) ([]*entities.Position, int64, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, 0, err
+	}
+
+	positions, total, err := uc.positionRepo.GetPositionsHistoryPaginated(siteID, keywordID, tags, source, dateFrom, dateTo, last, page, perPage)
 	if err != nil {
 		return nil, 0, &DomainError{
 			Code:    ErrorPositionFetch,
@@ -360,21 +376,9 @@ func (uc *PositionTrackingUseCase) GetPositionsHistoryPaginated(siteID int, keyw
 	return positions, total, nil
 }
 
-func (uc *PositionTrackingUseCase) GetPositionStatistics(siteID int, source string, dateFrom, dateTo time.Time, filterGroupID *int) (*entities.PositionStatistics, error) {
-	site, err := uc.siteRepo.GetByID(siteID)
-	if err != nil {
-		return nil, &DomainError{
-			Code:    ErrorPositionFetch,
-			Message: "Site not found",
-			Err:     err,
-		}
-	}
-	if site == nil {
-		return nil, &DomainError{
-			Code:    ErrorPositionFetch,
-			Message: "Site not found",
-			Err:     fmt.Errorf("site with ID %d not found", siteID),
-		}
+func (uc *PositionTrackingUseCase) GetPositionStatistics(workspaceID *int, siteID int, competitorID *int, source string, dateFrom, dateTo time.Time, filterGroupID *int, tags *entities.TagFilter) (*entities.PositionStatistics, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
 	}
 
 	if source != "google" && source != "yandex" && source != "wordstat" {
@@ -385,7 +389,11 @@ func (uc *PositionTrackingUseCase) GetPositionStatistics(siteID int, source stri
 		}
 	}
 
-	stats, err := uc.positionRepo.GetPositionStatistics(siteID, source, dateFrom, dateTo, filterGroupID)
+	if err := uc.checkSiteCompetitor(siteID, competitorID); err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.positionRepo.GetPositionStatistics(siteID, competitorID, source, dateFrom, dateTo, filterGroupID, tags)
 	if err != nil {
 		return nil, &DomainError{
 			Code:    ErrorPositionFetch,
@@ -397,8 +405,14 @@ func (uc *PositionTrackingUseCase) GetPositionStatistics(siteID int, source stri
 	return stats, nil
 }
 
-func (uc *PositionTrackingUseCase) GetLatestPositions() ([]*entities.Position, error) {
-	sites, err := uc.siteRepo.GetAll()
+func (uc *PositionTrackingUseCase) GetLatestPositions(workspaceID *int) ([]*entities.Position, error) {
+	var sites []*entities.Site
+	var err error
+	if workspaceID != nil {
+		sites, err = uc.siteRepo.GetByWorkspaceID(*workspaceID)
+	} else {
+		sites, err = uc.siteRepo.GetAll()
+	}
 	if err != nil {
 		return nil, &DomainError{
 			Code:    ErrorPositionFetch,
@@ -426,7 +440,7 @@ func (uc *PositionTrackingUseCase) GetLatestPositions() ([]*entities.Position, e
 	return latestPositions, nil
 }
 
-func (uc *PositionTrackingUseCase) GetCombinedPositionsPaginated(siteID int, source *string, includeWordstat bool, wordstatSort bool, dateFrom, dateTo, dateSort *time.Time, sortType string, rankFrom, rankTo *int, groupID *int, filterGroupID *int, wordstatQueryType *string, page, perPage int) ([]*entities.CombinedPosition, int64, error) {
+func (uc *PositionTrackingUseCase) GetCombinedPositionsPaginated(workspaceID *int, siteID int, competitorID *int, source *string, includeWordstat bool, wordstatSort bool, dateFrom, dateTo, dateSort *time.Time, sortType string, rankFrom, rankTo *int, groupID *int, tags *entities.TagFilter, filterGroupID *int, wordstatQueryType *string, page, perPage int) ([]*entities.CombinedPosition, int64, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -455,7 +469,15 @@ func (uc *PositionTrackingUseCase) GetCombinedPositionsPaginated(siteID int, sou
 		}
 	}
 
-	combinedPositions, total, err := uc.positionRepo.GetCombinedPositionsPaginated(siteID, source, includeWordstat, wordstatSort, dateFrom, dateTo, dateSort, sortType, rankFrom, rankTo, groupID, filterGroupID, wordstatQueryType, page, perPage)
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, 0, err
+	}
+
+	if err := uc.checkSiteCompetitor(siteID, competitorID); err != nil {
+		return nil, 0, err
+	}
+
+	combinedPositions, total, err := uc.positionRepo.GetCombinedPositionsPaginated(siteID, competitorID, source, includeWordstat, wordstatSort, dateFrom, dateTo, dateSort, sortType, rankFrom, rankTo, groupID, tags, filterGroupID, wordstatQueryType, page, perPage)
 	if err != nil {
 		return nil, 0, &DomainError{
 			Code:    ErrorPositionFetch,
@@ -467,8 +489,26 @@ func (uc *PositionTrackingUseCase) GetCombinedPositionsPaginated(siteID int, sou
 	return combinedPositions, total, nil
 }
 
+// checkSiteCompetitor проверяет, что запрошенный конкурент относится к сайту
+func (uc *PositionTrackingUseCase) checkSiteCompetitor(siteID int, competitorID *int) error {
+	if competitorID == nil {
+		return nil
+	}
+
+	competitor, err := uc.competitorRepo.GetByID(*competitorID)
+	if err != nil || competitor.SiteID != siteID {
+		return &DomainError{
+			Code:    ErrorCompetitorNotFound,
+			Message: "Competitor not found",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
 func (uc *PositionTrackingUseCase) trackWordstatPosition(keyword *entities.Keyword) error {
-	frequency, err := uc.wordstat.GetKeywordFrequency(keyword.Value, keyword.Value, nil)
+	frequency, err := uc.wordstat.GetKeywordFrequency(context.Background(), keyword.Value, keyword.Value, nil)
 	if err != nil {
 		return &DomainError{
 			Code:    ErrorPositionCreation,
@@ -476,6 +516,7 @@ func (uc *PositionTrackingUseCase) trackWordstatPosition(keyword *entities.Keywo
 			Err:     err,
 		}
 	}
+	recordWordstatUsage(uc.usageRepo, "", keyword.SiteID, uc.wordstat)
 
 	positionEntity := &entities.Position{
 		KeywordID: keyword.ID,
@@ -504,50 +545,38 @@ func (uc *PositionTrackingUseCase) trackWordstatPosition(keyword *entities.Keywo
 	return nil
 }
 
-func (uc *PositionTrackingUseCase) getSoftIDByBaseURL(baseURL string) string {
-	baseURLLower := strings.ToLower(baseURL)
-	if strings.Contains(baseURLLower, "xmlriver") {
-		return uc.xmlRiverSoftID
-	}
-	if strings.Contains(baseURLLower, "xmlstock") {
-		return uc.xmlStockSoftID
-	}
-	// По умолчанию используем XMLRiver soft_id
-	return uc.xmlRiverSoftID
-}
-
 func (uc *PositionTrackingUseCase) trackGoogleKeywordPosition(
 	site *entities.Site,
+	competitors []*entities.Competitor,
 	keyword *entities.Keyword,
 	device, os string,
 	ads bool,
 	country, lang string,
 	pages int,
 	subdomains bool,
-	xmlUserID, xmlAPIKey, xmlBaseURL, tbs string,
-	filter, highlights, nfpr, loc, ai int,
+	provider domainservices.SearchService,
+	tbs string,
+	filter *int, highlights, nfpr, loc, ai int,
 	raw string,
 ) error {
-	// Создаем временный XMLRiver сервис с кастомными настройками
-	var xmlRiverService *services.XMLRiverService
-	if xmlUserID != "" && xmlAPIKey != "" && xmlBaseURL != "" {
-		var err error
-		softID := uc.getSoftIDByBaseURL(xmlBaseURL)
-		xmlRiverService, err = services.NewXMLRiverService(xmlBaseURL, xmlUserID, xmlAPIKey, softID)
-		if err != nil {
-			return &DomainError{
-				Code:    ErrorPositionCreation,
-				Message: "Failed to create XMLRiver service with custom settings",
-				Err:     err,
-			}
-		}
-	} else {
-		// По умолчанию используем XMLStock для Google
-		xmlRiverService = uc.xmlStock
-	}
-
 	// Для Google используем organic=false и groupBy=0
-	position, url, title, err := xmlRiverService.FindSitePositionWithSubdomains(keyword.Value, site.Domain, entities.GoogleSearch, pages, device, os, ads, country, lang, subdomains, 0, 0, false, 0)
+	req := domainservices.SearchRequest{
+		Query:      keyword.Value,
+		Device:     device,
+		OS:         os,
+		Ads:        ads,
+		Country:    country,
+		Lang:       lang,
+		TBS:        tbs,
+		Filter:     filter,
+		Highlights: highlights,
+		NFPR:       nfpr,
+		Loc:        loc,
+		AI:         ai,
+		Raw:        raw,
+	}
+	searchResult, err := provider.FindSitePosition(context.Background(), req, site.Domain, competitorDomains(competitors), entities.GoogleSearch, pages, subdomains)
+	recordSearchUsage(uc.usageRepo, "", site.ID, entities.GoogleSearch, provider, searchResult)
 	if err != nil {
 		return &DomainError{
 			Code:    ErrorPositionCreation,
@@ -559,9 +588,9 @@ func (uc *PositionTrackingUseCase) trackGoogleKeywordPosition(
 	positionEntity := &entities.Position{
 		KeywordID: keyword.ID,
 		SiteID:    site.ID,
-		Rank:      position,
-		URL:       url,
-		Title:     title,
+		Rank:      searchResult.Position,
+		URL:       searchResult.URL,
+		Title:     searchResult.Title,
 		Source:    entities.GoogleSearch,
 		Device:    device,
 		OS:        os,
@@ -580,50 +609,54 @@ func (uc *PositionTrackingUseCase) trackGoogleKeywordPosition(
 		}
 	}
 
+	saveSerpSnapshot(uc.snapshotRepo, positionEntity, searchResult.Items)
+	saveCompetitorPositions(uc.positionRepo, positionEntity, competitors, searchResult.Competitors, false)
+
 	return nil
 }
 
 func (uc *PositionTrackingUseCase) trackYandexKeywordPosition(
 	site *entities.Site,
+	competitors []*entities.Competitor,
 	keyword *entities.Keyword,
 	device, os string,
 	ads bool,
 	country, lang string,
 	pages int,
 	subdomains bool,
-	xmlUserID, xmlAPIKey, xmlBaseURL string,
-	groupBy, filter, highlights, within, lr int,
+	provider domainservices.SearchService,
+	groupBy int, filter *int, highlights, within, lr int,
 	raw string,
 	inIndex, strict int,
 	organic bool,
 ) error {
-	// Создаем временный XMLRiver сервис с кастомными настройками
-	var xmlRiverService *services.XMLRiverService
-	if xmlUserID != "" && xmlAPIKey != "" && xmlBaseURL != "" {
-		var err error
-		softID := uc.getSoftIDByBaseURL(xmlBaseURL)
-		xmlRiverService, err = services.NewXMLRiverService(xmlBaseURL, xmlUserID, xmlAPIKey, softID)
-		if err != nil {
-			return &DomainError{
-				Code:    ErrorPositionCreation,
-				Message: "Failed to create XMLRiver service with custom settings",
-				Err:     err,
-			}
-		}
-	} else {
-		// По умолчанию используем XMLStock для Yandex
-		xmlRiverService = uc.xmlStock
-	}
-
 	// Если organic=false, используем groupby=pages*10 для получения всех результатов сразу
 	var calculatedGroupBy int
-	if !organic && pages > 0 {
+	if !organic && pages > 0 && provider.Capabilities().GroupBy {
 		calculatedGroupBy = pages * 10
 	} else {
 		calculatedGroupBy = groupBy
 	}
 
-	position, url, title, err := xmlRiverService.FindSitePositionWithSubdomains(keyword.Value, site.Domain, entities.YandexSearch, pages, device, os, ads, country, lang, subdomains, lr, 0, organic, calculatedGroupBy)
+	req := domainservices.SearchRequest{
+		Query:      keyword.Value,
+		Device:     device,
+		OS:         os,
+		Ads:        ads,
+		Country:    country,
+		Lang:       lang,
+		LR:         lr,
+		GroupBy:    calculatedGroupBy,
+		Organic:    organic,
+		Filter:     filter,
+		Highlights: highlights,
+		Within:     within,
+		Raw:        raw,
+		InIndex:    inIndex,
+		Strict:     strict,
+	}
+	searchResult, err := provider.FindSitePosition(context.Background(), req, site.Domain, competitorDomains(competitors), entities.YandexSearch, pages, subdomains)
+	recordSearchUsage(uc.usageRepo, "", site.ID, entities.YandexSearch, provider, searchResult)
 	if err != nil {
 		return &DomainError{
 			Code:    ErrorPositionCreation,
@@ -635,9 +668,9 @@ func (uc *PositionTrackingUseCase) trackYandexKeywordPosition(
 	positionEntity := &entities.Position{
 		KeywordID: keyword.ID,
 		SiteID:    site.ID,
-		Rank:      position,
-		URL:       url,
-		Title:     title,
+		Rank:      searchResult.Position,
+		URL:       searchResult.URL,
+		Title:     searchResult.Title,
 		Source:    entities.YandexSearch,
 		Device:    device,
 		OS:        os,
@@ -656,32 +689,19 @@ func (uc *PositionTrackingUseCase) trackYandexKeywordPosition(
 		}
 	}
 
+	saveSerpSnapshot(uc.snapshotRepo, positionEntity, searchResult.Items)
+	saveCompetitorPositions(uc.positionRepo, positionEntity, competitors, searchResult.Competitors, false)
+
 	return nil
 }
 
 func (uc *PositionTrackingUseCase) trackWordstatKeywordPosition(
+	ctx context.Context,
+	wordstatService *services.WordstatService,
 	keyword *entities.Keyword,
-	xmlUserID, xmlAPIKey, xmlBaseURL string,
 	regions *int,
 ) error {
-	// Создаем временный Wordstat сервис с кастомными настройками
-	var wordstatService *services.WordstatService
-	if xmlUserID != "" && xmlAPIKey != "" && xmlBaseURL != "" {
-		var err error
-		wordstatService, err = services.NewWordstatService(xmlBaseURL, xmlUserID, xmlAPIKey)
-		if err != nil {
-			return &DomainError{
-				Code:    ErrorPositionCreation,
-				Message: "Failed to create Wordstat service with custom settings",
-				Err:     err,
-			}
-		}
-	} else {
-		// По умолчанию используем XMLRiver для Wordstat
-		wordstatService = uc.wordstat
-	}
-
-	frequency, err := wordstatService.GetKeywordFrequency(keyword.Value, keyword.Value, regions)
+	frequency, err := wordstatService.GetKeywordFrequency(ctx, keyword.Value, keyword.Value, regions)
 	if err != nil {
 		return &DomainError{
 			Code:    ErrorPositionCreation,
@@ -689,6 +709,7 @@ func (uc *PositionTrackingUseCase) trackWordstatKeywordPosition(
 			Err:     err,
 		}
 	}
+	recordWordstatUsage(uc.usageRepo, "", keyword.SiteID, wordstatService)
 
 	positionEntity := &entities.Position{
 		KeywordID: keyword.ID,
@@ -716,68 +737,3 @@ func (uc *PositionTrackingUseCase) trackWordstatKeywordPosition(
 
 	return nil
 }
-
-func (uc *PositionTrackingUseCase) CalculatePositionDynamic(siteID int, source string) (*int, error) {
-	currentPositions, err := uc.positionRepo.GetLatestBySiteIDAndSource(siteID, source)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(currentPositions) == 0 {
-		return nil, nil
-	}
-
-	currentMap := make(map[int]int)
-	for _, pos := range currentPositions {
-		if pos.Rank > 0 {
-			currentMap[pos.KeywordID] = pos.Rank
-		}
-	}
-
-	if len(currentMap) == 0 {
-		return nil, nil
-	}
-
-	keywordIDs := make([]int, 0, len(currentMap))
-	for kwID := range currentMap {
-		keywordIDs = append(keywordIDs, kwID)
-	}
-
-	var totalDiff int
-	for _, kwID := range keywordIDs {
-		currentRank := currentMap[kwID]
-
-		positions, err := uc.positionRepo.GetByKeywordAndSiteAndSource(kwID, siteID, source)
-		if err != nil {
-			continue
-		}
-
-		if len(positions) < 2 {
-			continue
-		}
-
-		var previousRank int
-		for i := 1; i < len(positions); i++ {
-			if positions[i].Rank > 0 {
-				previousRank = positions[i].Rank
-				break
-			}
-		}
-
-		if previousRank > 0 {
-			diff := previousRank - currentRank
-			totalDiff += diff
-		}
-	}
-
-	var result *int
-	if totalDiff > 0 {
-		val := 1
-		result = &val
-	} else if totalDiff < 0 {
-		val := 0
-		result = &val
-	}
-
-	return result, nil
-}
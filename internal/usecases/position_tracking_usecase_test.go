@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	domainservices "go-seo/internal/domain/services"
+)
+
+func TestTrackKeywordsLimitsWorkers(t *testing.T) {
+	keywords := make([]*entities.Keyword, 50)
+	for i := range keywords {
+		keywords[i] = &entities.Keyword{ID: i + 1}
+	}
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	count, err := trackKeywords(keywords, 4, func(kw *entities.Keyword) error {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if kw.ID%10 == 0 {
+			return errors.New("search failed")
+		}
+		return nil
+	})
+
+	if peak > 4 {
+		t.Fatalf("expected at most 4 keywords at once, got %d", peak)
+	}
+	if count != 45 || err == nil {
+		t.Fatalf("expected 45 tracked keywords and the first error, got %d, %v", count, err)
+	}
+}
+
+func TestTrackGooglePositionsLimitsConcurrency(t *testing.T) {
+	h := newTrackingHarness(t, 40, 3, 10)
+	h.provider.search = func(ctx context.Context, req domainservices.SearchRequest) (*domainservices.SitePositionResult, error) {
+		time.Sleep(time.Millisecond)
+		return &domainservices.SitePositionResult{Position: 1, Requests: 1}, nil
+	}
+
+	uc := NewPositionTrackingUseCase(h.uc.siteRepo, h.uc.keywordRepo, h.positions, &fakeSerpSnapshotRepository{},
+		&fakeCompetitorRepository{}, h.usage, &ProviderAccountUseCase{}, h.uc.providers, nil, 3)
+	count, err := uc.TrackGooglePositions(intPtr(1), 1, "", "", false, "", "", 1, false, nil, "", "", nil, 0, 0, 0, 0, "")
+	if err != nil || count != 40 {
+		t.Fatalf("expected 40 tracked keywords, got %d, %v", count, err)
+	}
+	if peak := h.provider.peakInFlight(); peak > 3 {
+		t.Fatalf("expected at most 3 requests at once, got %d", peak)
+	}
+}
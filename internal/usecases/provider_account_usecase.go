@@ -0,0 +1,233 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
+	"go-seo/internal/infrastructure/database"
+)
+
+type ProviderAccountUseCase struct {
+	accountRepo repositories.ProviderAccountRepository
+	providers   domainservices.SearchProviderRegistry
+	cipher      domainservices.SecretCipher // nil, если ключ шифрования не настроен
+}
+
+func NewProviderAccountUseCase(
+	accountRepo repositories.ProviderAccountRepository,
+	providers domainservices.SearchProviderRegistry,
+	cipher domainservices.SecretCipher,
+) *ProviderAccountUseCase {
+	return &ProviderAccountUseCase{
+		accountRepo: accountRepo,
+		providers:   providers,
+		cipher:      cipher,
+	}
+}
+
+// CreateAccount создает аккаунт в пространстве запроса; без пространства - в пространстве по умолчанию
+func (uc *ProviderAccountUseCase) CreateAccount(workspaceID *int, name, provider, userID, apiKey, baseURL string) (*entities.ProviderAccount, error) {
+	if err := uc.requireCipher(); err != nil {
+		return nil, err
+	}
+
+	account := &entities.ProviderAccount{
+		WorkspaceID: workspaceOrDefault(workspaceID),
+		Name:        strings.TrimSpace(name),
+		Provider:    provider,
+		UserID:      strings.TrimSpace(userID),
+		BaseURL:     strings.TrimSpace(baseURL),
+	}
+	if err := uc.validateAccount(account); err != nil {
+		return nil, err
+	}
+
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "API key is required",
+		}
+	}
+	if err := uc.encryptKey(account, apiKey); err != nil {
+		return nil, err
+	}
+
+	if err := uc.accountRepo.Create(account); err != nil {
+		if database.IsDatabaseError(err) && database.GetDatabaseErrorCode(err) == "DUPLICATE_ENTRY" {
+			return nil, &DomainError{
+				Code:    ErrorAccountExists,
+				Message: "Provider account with this name already exists",
+				Err:     err,
+			}
+		}
+		return nil, &DomainError{
+			Code:    ErrorAccountCreation,
+			Message: "Failed to create provider account",
+			Err:     err,
+		}
+	}
+
+	return account, nil
+}
+
+func (uc *ProviderAccountUseCase) GetAccounts(workspaceID *int) ([]*entities.ProviderAccount, error) {
+	accounts, err := uc.accountRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorAccountFetch,
+			Message: "Failed to fetch provider accounts",
+			Err:     err,
+		}
+	}
+
+	return accounts, nil
+}
+
+func (uc *ProviderAccountUseCase) GetAccount(workspaceID *int, id int) (*entities.ProviderAccount, error) {
+	account, err := uc.accountRepo.GetByID(id)
+	if err == nil && !inWorkspace(workspaceID, account.WorkspaceID) {
+		err = fmt.Errorf("provider account %d belongs to another workspace", id)
+	}
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorAccountNotFound,
+			Message: "Provider account not found",
+			Err:     err,
+		}
+	}
+
+	return account, nil
+}
+
+// UpdateAccount заменяет данные аккаунта; пустой apiKey оставляет сохраненный ключ
+func (uc *ProviderAccountUseCase) UpdateAccount(workspaceID *int, id int, name, provider, userID, apiKey, baseURL string) (*entities.ProviderAccount, error) {
+	account, err := uc.GetAccount(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	account.Name = strings.TrimSpace(name)
+	account.Provider = provider
+	account.UserID = strings.TrimSpace(userID)
+	account.BaseURL = strings.TrimSpace(baseURL)
+	if err := uc.validateAccount(account); err != nil {
+		return nil, err
+	}
+
+	if apiKey = strings.TrimSpace(apiKey); apiKey != "" {
+		if err := uc.requireCipher(); err != nil {
+			return nil, err
+		}
+		if err := uc.encryptKey(account, apiKey); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.accountRepo.Update(account); err != nil {
+		if database.IsDatabaseError(err) && database.GetDatabaseErrorCode(err) == "DUPLICATE_ENTRY" {
+			return nil, &DomainError{
+				Code:    ErrorAccountExists,
+				Message: "Provider account with this name already exists",
+				Err:     err,
+			}
+		}
+		return nil, &DomainError{
+			Code:    ErrorAccountUpdate,
+			Message: "Failed to update provider account",
+			Err:     err,
+		}
+	}
+
+	return uc.GetAccount(workspaceID, id)
+}
+
+// DeleteAccount удаляет аккаунт. Джобы и расписания, которые на него ссылаются, завершатся ошибкой при запуске
+func (uc *ProviderAccountUseCase) DeleteAccount(workspaceID *int, id int) error {
+	if _, err := uc.GetAccount(workspaceID, id); err != nil {
+		return err
+	}
+
+	if err := uc.accountRepo.Delete(id); err != nil {
+		return &DomainError{
+			Code:    ErrorAccountDeletion,
+			Message: "Failed to delete provider account",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// Resolve возвращает аккаунт с расшифрованным ключом для запросов к провайдеру; nil id - аккаунт из конфигурации.
+// Сайт может работать только с аккаунтами своего пространства, поэтому workspaceID - пространство сайта
+func (uc *ProviderAccountUseCase) Resolve(workspaceID int, id *int) (*entities.ProviderAccount, error) {
+	if id == nil {
+		return nil, nil
+	}
+	if err := uc.requireCipher(); err != nil {
+		return nil, err
+	}
+
+	account, err := uc.GetAccount(&workspaceID, *id)
+	if err != nil {
+		return nil, err
+	}
+
+	account.APIKey, err = uc.cipher.Decrypt(account.EncryptedAPIKey)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorAccountFetch,
+			Message: fmt.Sprintf("Failed to decrypt key of provider account %d", account.ID),
+			Err:     err,
+		}
+	}
+
+	return account, nil
+}
+
+func (uc *ProviderAccountUseCase) validateAccount(account *entities.ProviderAccount) error {
+	if account.Name == "" || account.UserID == "" {
+		return &DomainError{
+			Code:    ErrorValidation,
+			Message: "Account name and user id are required",
+		}
+	}
+
+	if _, err := uc.providers.Get(account.Provider); err != nil || account.Provider == "" {
+		return &DomainError{
+			Code:    ErrorProviderNotFound,
+			Message: fmt.Sprintf("Search provider '%s' not found", account.Provider),
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+func (uc *ProviderAccountUseCase) encryptKey(account *entities.ProviderAccount, apiKey string) error {
+	encrypted, err := uc.cipher.Encrypt(apiKey)
+	if err != nil {
+		return &DomainError{
+			Code:    ErrorInternal,
+			Message: "Failed to encrypt API key",
+			Err:     err,
+		}
+	}
+
+	account.EncryptedAPIKey = encrypted
+	return nil
+}
+
+func (uc *ProviderAccountUseCase) requireCipher() error {
+	if uc.cipher == nil {
+		return &DomainError{
+			Code:    ErrorEncryptionNotConfigured,
+			Message: "CREDENTIALS_ENCRYPTION_KEY is not configured, provider accounts are unavailable",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
+	"go-seo/internal/infrastructure/services"
+)
+
+// recordUsage записывает в журнал запросы, сделанные при проверке одного keyword. Ошибка записи
+// не должна ронять проверку, результат которой уже оплачен, поэтому только логируется.
+func recordUsage(usageRepo repositories.UsageRepository, usage *entities.ProviderUsage) {
+	if usage.Requests == 0 {
+		return
+	}
+	if err := usageRepo.Create(usage); err != nil {
+		log.Printf("WARNING: Failed to record provider usage for site %d: %v", usage.SiteID, err)
+	}
+}
+
+// recordSearchUsage записывает запросы SERP провайдера; result при ошибке поиска частичный, но запросы уже оплачены
+func recordSearchUsage(usageRepo repositories.UsageRepository, jobID string, siteID int, source string, provider domainservices.SearchService, result *domainservices.SitePositionResult) {
+	if result == nil {
+		return
+	}
+	recordUsage(usageRepo, &entities.ProviderUsage{
+		JobID:    jobID,
+		SiteID:   siteID,
+		Source:   source,
+		Provider: provider.Name(),
+		Account:  provider.Account(),
+		Requests: result.Requests,
+		Cost:     float64(result.Requests) * provider.Capabilities().CostPerRequest,
+	})
+}
+
+// recordWordstatUsage записывает один запрос частоты. Wordstat работает через аккаунт XMLRiver
+func recordWordstatUsage(usageRepo repositories.UsageRepository, jobID string, siteID int, wordstat *services.WordstatService) {
+	recordUsage(usageRepo, &entities.ProviderUsage{
+		JobID:    jobID,
+		SiteID:   siteID,
+		Source:   entities.Wordstat,
+		Provider: services.ProviderXMLRiver,
+		Account:  wordstat.Account(),
+		Requests: 1,
+		Cost:     wordstat.CostPerRequest(),
+	})
+}
+
+// estimateKeywordRequests оценивает сверху число запросов на один keyword
+func estimateKeywordRequests(source string, params entities.TrackingParams) int {
+	// Яндекс с groupby получает весь топ одним запросом
+	if source == entities.YandexSearch && !params.Organic && params.GroupBy > 0 {
+		return 1
+	}
+	if params.Pages > 0 {
+		return params.Pages
+	}
+	return 1
+}
+
+// fitBudget проверяет, укладывается ли запуск в остаток месячного бюджета сайта и общего бюджета его пространства.
+// Остаток уменьшается на резерв незавершенных джобов, поэтому проверку вместе с созданием джоба
+// выполняют под UsageRepository.WithBudgetLock.
+// При BudgetActionTrim сайта оставляет только keywords, которые помещаются в меньший из остатков.
+func fitBudget(usageRepo repositories.UsageRepository, workspaceRepo repositories.WorkspaceRepository, site *entities.Site, keywords []*entities.Keyword, keywordCost float64) ([]*entities.Keyword, error) {
+	if keywordCost <= 0 || len(keywords) == 0 {
+		return keywords, nil
+	}
+
+	workspace, err := workspaceRepo.GetByID(site.WorkspaceID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorWorkspaceNotFound,
+			Message: fmt.Sprintf("Workspace of site %d not found", site.ID),
+			Err:     err,
+		}
+	}
+	if site.MonthlyBudget == nil && workspace.MonthlyBudget == nil {
+		return keywords, nil
+	}
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	remaining := math.Inf(1)
+	limitedBy := ""
+	if site.MonthlyBudget != nil {
+		spent, err := usageRepo.GetSpentSince(site.ID, monthStart)
+		if err != nil {
+			return nil, &DomainError{
+				Code:    ErrorInternal,
+				Message: "Failed to check site budget",
+				Err:     err,
+			}
+		}
+		reserved, err := usageRepo.GetReserved(site.ID)
+		if err != nil {
+			return nil, &DomainError{
+				Code:    ErrorInternal,
+				Message: "Failed to check site budget",
+				Err:     err,
+			}
+		}
+		remaining = *site.MonthlyBudget - spent - reserved
+		limitedBy = "site"
+	}
+	if workspace.MonthlyBudget != nil {
+		spent, err := usageRepo.GetWorkspaceSpentSince(workspace.ID, monthStart)
+		if err != nil {
+			return nil, &DomainError{
+				Code:    ErrorInternal,
+				Message: "Failed to check workspace budget",
+				Err:     err,
+			}
+		}
+		reserved, err := usageRepo.GetWorkspaceReserved(workspace.ID)
+		if err != nil {
+			return nil, &DomainError{
+				Code:    ErrorInternal,
+				Message: "Failed to check workspace budget",
+				Err:     err,
+			}
+		}
+		if workspaceRemaining := *workspace.MonthlyBudget - spent - reserved; workspaceRemaining < remaining {
+			remaining = workspaceRemaining
+			limitedBy = "workspace"
+		}
+	}
+
+	estimate := keywordCost * float64(len(keywords))
+	if estimate <= remaining {
+		return keywords, nil
+	}
+
+	fits := 0
+	if remaining > 0 {
+		fits = int(math.Floor(remaining / keywordCost))
+	}
+	if site.BudgetAction == entities.BudgetActionTrim && fits > 0 {
+		log.Printf("Site %d %s budget allows %d of %d keywords, trimming the job", site.ID, limitedBy, fits, len(keywords))
+		return keywords[:fits], nil
+	}
+
+	return nil, &DomainError{
+		Code:    ErrorBudgetExceeded,
+		Message: fmt.Sprintf("Job would cost up to %.2f, remaining monthly budget of %s is %.2f", estimate, limitedBy, math.Max(remaining, 0)),
+	}
+}
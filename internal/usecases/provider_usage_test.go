@@ -0,0 +1,150 @@
+package usecases
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// budgetUsageRepository отдает заданные расходы и резерв сайта и пространства
+type budgetUsageRepository struct {
+	repositories.UsageRepository
+	siteSpent, siteReserved           float64
+	workspaceSpent, workspaceReserved float64
+}
+
+func (r *budgetUsageRepository) GetSpentSince(siteID int, since time.Time) (float64, error) {
+	return r.siteSpent, nil
+}
+
+func (r *budgetUsageRepository) GetReserved(siteID int) (float64, error) {
+	return r.siteReserved, nil
+}
+
+func (r *budgetUsageRepository) GetWorkspaceSpentSince(workspaceID int, since time.Time) (float64, error) {
+	return r.workspaceSpent, nil
+}
+
+func (r *budgetUsageRepository) GetWorkspaceReserved(workspaceID int) (float64, error) {
+	return r.workspaceReserved, nil
+}
+
+type fakeWorkspaceRepository struct {
+	repositories.WorkspaceRepository
+	workspaces map[int]*entities.Workspace
+}
+
+func (r *fakeWorkspaceRepository) GetByID(id int) (*entities.Workspace, error) {
+	if workspace, ok := r.workspaces[id]; ok {
+		return workspace, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestFitBudget(t *testing.T) {
+	keywords := make([]*entities.Keyword, 10)
+	for i := range keywords {
+		keywords[i] = &entities.Keyword{ID: i + 1}
+	}
+
+	tests := []struct {
+		name            string
+		siteBudget      *float64
+		workspaceBudget *float64
+		action          string
+		usage           budgetUsageRepository
+		keywordCost     float64
+		want            int
+		wantLimitedBy   string // Пусто - запуск укладывается в бюджет
+	}{
+		{name: "no budgets", action: entities.BudgetActionRefuse, keywordCost: 1, want: 10},
+		{name: "free requests", siteBudget: floatPtr(0), action: entities.BudgetActionRefuse, want: 10},
+		{
+			name: "fits site budget", siteBudget: floatPtr(20), action: entities.BudgetActionRefuse,
+			usage: budgetUsageRepository{siteSpent: 10}, keywordCost: 1, want: 10,
+		},
+		{
+			name: "refuse over site budget", siteBudget: floatPtr(10), action: entities.BudgetActionRefuse,
+			usage: budgetUsageRepository{siteSpent: 3}, keywordCost: 1, wantLimitedBy: "site",
+		},
+		{
+			name: "trim to site budget", siteBudget: floatPtr(10), action: entities.BudgetActionTrim,
+			usage: budgetUsageRepository{siteSpent: 3}, keywordCost: 1, want: 7,
+		},
+		{
+			name: "site reserve of unfinished jobs", siteBudget: floatPtr(20), action: entities.BudgetActionTrim,
+			usage: budgetUsageRepository{siteSpent: 5, siteReserved: 8}, keywordCost: 1, want: 7,
+		},
+		{
+			name: "trim rounds down", siteBudget: floatPtr(10), action: entities.BudgetActionTrim,
+			usage: budgetUsageRepository{siteSpent: 0.5}, keywordCost: 2, want: 4,
+		},
+		{
+			name: "trim with nothing left", siteBudget: floatPtr(10), action: entities.BudgetActionTrim,
+			usage: budgetUsageRepository{siteSpent: 9, siteReserved: 2}, keywordCost: 1, wantLimitedBy: "site",
+		},
+		{
+			name: "workspace budget is lower", siteBudget: floatPtr(100), workspaceBudget: floatPtr(10), action: entities.BudgetActionRefuse,
+			usage: budgetUsageRepository{workspaceSpent: 2, workspaceReserved: 4}, keywordCost: 1, wantLimitedBy: "workspace",
+		},
+		{
+			name: "trim to workspace budget", siteBudget: floatPtr(100), workspaceBudget: floatPtr(10), action: entities.BudgetActionTrim,
+			usage: budgetUsageRepository{workspaceSpent: 2, workspaceReserved: 4}, keywordCost: 1, want: 4,
+		},
+		{
+			name: "workspace budget without site budget", workspaceBudget: floatPtr(5), action: entities.BudgetActionTrim,
+			usage: budgetUsageRepository{siteSpent: 100}, keywordCost: 1, want: 5,
+		},
+		{
+			name: "site budget is lower", siteBudget: floatPtr(5), workspaceBudget: floatPtr(100), action: entities.BudgetActionRefuse,
+			usage: budgetUsageRepository{workspaceSpent: 50, workspaceReserved: 40}, keywordCost: 1, wantLimitedBy: "site",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := &entities.Site{ID: 1, WorkspaceID: 1, MonthlyBudget: tt.siteBudget, BudgetAction: tt.action}
+			workspaces := &fakeWorkspaceRepository{workspaces: map[int]*entities.Workspace{
+				1: {ID: 1, MonthlyBudget: tt.workspaceBudget},
+			}}
+
+			fitted, err := fitBudget(&tt.usage, workspaces, site, keywords, tt.keywordCost)
+			if tt.wantLimitedBy != "" {
+				var domainErr *DomainError
+				if !errors.As(err, &domainErr) || domainErr.Code != ErrorBudgetExceeded {
+					t.Fatalf("expected %s, got %v", ErrorBudgetExceeded, err)
+				}
+				if !strings.Contains(domainErr.Message, "budget of "+tt.wantLimitedBy) {
+					t.Fatalf("expected %s budget in message, got %q", tt.wantLimitedBy, domainErr.Message)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(fitted) != tt.want {
+				t.Fatalf("expected %d keywords, got %d", tt.want, len(fitted))
+			}
+		})
+	}
+}
+
+func TestFitBudgetMissingWorkspace(t *testing.T) {
+	site := &entities.Site{ID: 1, WorkspaceID: 2, MonthlyBudget: floatPtr(10)}
+	workspaces := &fakeWorkspaceRepository{workspaces: map[int]*entities.Workspace{}}
+
+	_, err := fitBudget(&budgetUsageRepository{}, workspaces, site, []*entities.Keyword{{ID: 1}}, 1)
+	if GetDomainErrorCode(err) != ErrorWorkspaceNotFound {
+		t.Fatalf("expected %s, got %v", ErrorWorkspaceNotFound, err)
+	}
+}
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
+)
+
+type ProviderUseCase struct {
+	providers   domainservices.SearchProviderRegistry
+	limiter     domainservices.RateLimiter
+	accountRepo repositories.ProviderAccountRepository
+}
+
+func NewProviderUseCase(providers domainservices.SearchProviderRegistry, limiter domainservices.RateLimiter, accountRepo repositories.ProviderAccountRepository) *ProviderUseCase {
+	return &ProviderUseCase{
+		providers:   providers,
+		limiter:     limiter,
+		accountRepo: accountRepo,
+	}
+}
+
+// GetProviders возвращает зарегистрированных провайдеров и имя провайдера по умолчанию
+func (uc *ProviderUseCase) GetProviders() ([]domainservices.SearchService, string) {
+	defaultName := ""
+	if provider, err := uc.providers.Get(""); err == nil {
+		defaultName = provider.Name()
+	}
+	return uc.providers.List(), defaultName
+}
+
+// GetLimits возвращает текущее состояние лимитов по аккаунтам провайдеров, к которым были запросы.
+// Пространство видит только свои аккаунты; аккаунт по умолчанию из конфигурации и чужие аккаунты
+// доступны лишь системному ключу (workspaceID == nil)
+func (uc *ProviderUseCase) GetLimits(workspaceID *int) ([]domainservices.ProviderLimiterState, error) {
+	states := uc.limiter.States()
+	if workspaceID == nil {
+		return states, nil
+	}
+
+	accounts, err := uc.accountRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorAccountFetch,
+			Message: "Failed to fetch provider accounts",
+			Err:     err,
+		}
+	}
+
+	// Лимитер ведет счетчики по имени провайдера и user ID аккаунта
+	type limiterKey struct{ provider, account string }
+	own := make(map[limiterKey]bool, len(accounts))
+	for _, account := range accounts {
+		own[limiterKey{account.Provider, account.UserID}] = true
+	}
+
+	filtered := make([]domainservices.ProviderLimiterState, 0, len(states))
+	for _, state := range states {
+		if own[limiterKey{state.Provider, state.Account}] {
+			filtered = append(filtered, state)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"fmt"
+
+	"go-seo/internal/domain/entities"
+	domainservices "go-seo/internal/domain/services"
+	"go-seo/internal/infrastructure/services"
+)
+
+// resolveSearchProvider выбирает провайдера из реестра и проверяет, что он подходит под запрос.
+// Если передан аккаунт, возвращается копия провайдера с его учетными данными; провайдер по умолчанию - провайдер аккаунта.
+func resolveSearchProvider(
+	registry domainservices.SearchProviderRegistry,
+	name, source string,
+	pages int,
+	account *entities.ProviderAccount,
+) (domainservices.SearchService, error) {
+	if account != nil {
+		if name == "" {
+			name = account.Provider
+		} else if name != account.Provider {
+			return nil, &DomainError{
+				Code:    ErrorValidation,
+				Message: fmt.Sprintf("Provider account %d belongs to '%s', not '%s'", account.ID, account.Provider, name),
+			}
+		}
+	}
+
+	provider, err := registry.Get(name)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorProviderNotFound,
+			Message: fmt.Sprintf("Search provider '%s' not found", name),
+			Err:     err,
+		}
+	}
+
+	capabilities := provider.Capabilities()
+	if !capabilities.SupportsSource(source) {
+		return nil, &DomainError{
+			Code:    ErrorProviderUnsupported,
+			Message: fmt.Sprintf("Search provider '%s' does not support %s", provider.Name(), source),
+			Err:     fmt.Errorf("unsupported source: %s", source),
+		}
+	}
+
+	if capabilities.MaxPages > 0 && pages > capabilities.MaxPages {
+		return nil, &DomainError{
+			Code:    ErrorProviderUnsupported,
+			Message: fmt.Sprintf("Search provider '%s' supports at most %d pages", provider.Name(), capabilities.MaxPages),
+			Err:     fmt.Errorf("pages %d exceed provider limit", pages),
+		}
+	}
+
+	if account != nil {
+		provider, err = provider.WithCredentials(account.BaseURL, account.UserID, account.APIKey)
+		if err != nil {
+			return nil, &DomainError{
+				Code:    ErrorProviderNotFound,
+				Message: fmt.Sprintf("Failed to use provider account %d", account.ID),
+				Err:     err,
+			}
+		}
+	}
+
+	return provider, nil
+}
+
+// validateTrackingParams отклоняет параметры провайдера, несовместимые со съемом позиций:
+// raw возвращает HTML вместо XML, а inindex проверяет индексацию URL, переданного вместо запроса
+func validateTrackingParams(raw string, inIndex int) error {
+	if raw != "" {
+		return &DomainError{
+			Code:    ErrorValidation,
+			Message: "Parameter 'raw' is not supported for position tracking: the provider returns HTML instead of search results",
+		}
+	}
+	if inIndex != 0 {
+		return &DomainError{
+			Code:    ErrorValidation,
+			Message: "Parameter 'inindex' is not supported for position tracking: it checks indexing of a URL, not keyword positions",
+		}
+	}
+
+	return nil
+}
+
+// resolveWordstat возвращает клиента Wordstat для аккаунта; без аккаунта - клиента из конфигурации.
+// Wordstat работает через XMLRiver, поэтому подходят только аккаунты xmlriver
+func resolveWordstat(wordstat *services.WordstatService, account *entities.ProviderAccount) (*services.WordstatService, error) {
+	if account == nil {
+		return wordstat, nil
+	}
+
+	if account.Provider != services.ProviderXMLRiver {
+		return nil, &DomainError{
+			Code:    ErrorProviderUnsupported,
+			Message: fmt.Sprintf("Provider account %d belongs to '%s', Wordstat requires an %s account", account.ID, account.Provider, services.ProviderXMLRiver),
+		}
+	}
+
+	client, err := wordstat.WithCredentials(account.BaseURL, account.UserID, account.APIKey)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorProviderNotFound,
+			Message: fmt.Sprintf("Failed to use provider account %d for Wordstat", account.ID),
+			Err:     err,
+		}
+	}
+
+	return client, nil
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"go-seo/internal/domain/entities"
+	domainservices "go-seo/internal/domain/services"
+	"go-seo/internal/infrastructure/services"
+)
+
+func TestValidateTrackingParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		inIndex int
+		wantErr bool
+	}{
+		{name: "defaults"},
+		{name: "raw page returns html", raw: "page", wantErr: true},
+		{name: "index check expects url", inIndex: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTrackingParams(tt.raw, tt.inIndex)
+			if tt.wantErr {
+				if GetDomainErrorCode(err) != ErrorValidation {
+					t.Fatalf("expected validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTrackingRejectsRawResponse(t *testing.T) {
+	// Проверка идет до обращения к репозиториям, поэтому зависимости не нужны
+	uc := &AsyncPositionTrackingUseCase{}
+	if _, err := uc.StartAsyncGoogleTracking(nil, 1, "", "", false, "", "", 1, false,
+		nil, "", "", nil, 0, 0, 0, 0, "page", 0, 0, 0, nil, nil); GetDomainErrorCode(err) != ErrorValidation {
+		t.Fatalf("google: expected validation error, got %v", err)
+	}
+	if _, err := uc.StartAsyncYandexTracking(nil, 1, "", "", false, "", "", 1, false,
+		nil, "", 0, nil, 0, 0, 0, "", "", 1, 0, false, nil, nil); GetDomainErrorCode(err) != ErrorValidation {
+		t.Fatalf("yandex: expected validation error, got %v", err)
+	}
+}
+
+func TestTrackingParamsReachProviderQuery(t *testing.T) {
+	google := func(uc *AsyncPositionTrackingUseCase) (string, error) {
+		return uc.StartAsyncGoogleTracking(nil, 1, "desktop", "", false, "", "", 1, false,
+			nil, "", "qdr:w", nil, 0, 0, 0, 0, "", 0, 10, 1, nil, nil)
+	}
+	yandex := func(uc *AsyncPositionTrackingUseCase) (string, error) {
+		return uc.StartAsyncYandexTracking(nil, 1, "desktop", "", false, "", "", 1, false,
+			nil, "", 0, nil, 0, 0, 213, "com.tr", "", 0, 1, false, nil, nil)
+	}
+	googleQuery := map[string]string{"domain": "10", "strict": "1", "tbs": "qdr:w"}
+	yandexQuery := map[string]string{"domain": "com.tr", "strict": "1", "lr": "213"}
+
+	tests := []struct {
+		name      string
+		provider  string
+		endpoints services.SearchEndpoints
+		start     func(uc *AsyncPositionTrackingUseCase) (string, error)
+		want      map[string]string
+	}{
+		{"xmlriver google", services.ProviderXMLRiver, services.XMLRiverEndpoints, google, googleQuery},
+		{"xmlstock google", services.ProviderXMLStock, services.XMLStockEndpoints, google, googleQuery},
+		{"xmlriver yandex", services.ProviderXMLRiver, services.XMLRiverEndpoints, yandex, yandexQuery},
+		{"xmlstock yandex", services.ProviderXMLStock, services.XMLStockEndpoints, yandex, yandexQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var queries []url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				queries = append(queries, r.URL.Query())
+				mu.Unlock()
+				w.Write([]byte(`<yandexsearch><response><error code="15">Нет результатов</error></response></yandexsearch>`))
+			}))
+			defer server.Close()
+
+			provider, err := services.NewXMLRiverService(services.ProviderConfig{
+				Name: tt.provider, BaseURL: server.URL, UserID: "1", APIKey: "key", Endpoints: tt.endpoints,
+				Capabilities: domainservices.ProviderCapabilities{
+					Sources:  []string{entities.GoogleSearch, entities.YandexSearch},
+					MaxPages: 10,
+					GroupBy:  true,
+				},
+			})
+			if err != nil {
+				t.Fatalf("provider: %v", err)
+			}
+			registry := services.NewProviderRegistry(tt.provider)
+			if err := registry.Register(provider); err != nil {
+				t.Fatalf("register provider: %v", err)
+			}
+
+			h := newTrackingHarness(t, 1, 1, 1)
+			h.uc.providers = registry
+			if _, err := tt.start(h.uc); err != nil {
+				t.Fatalf("start: %v", err)
+			}
+			h.process(t, "worker")
+
+			if len(queries) != 1 {
+				t.Fatalf("expected one provider request, got %d", len(queries))
+			}
+			for name, value := range tt.want {
+				if got := queries[0].Get(name); got != value {
+					t.Errorf("%s: expected %q, got %q in %s", name, value, got, queries[0].Encode())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+)
+
+type SerpSnapshotUseCase struct {
+	snapshotRepo repositories.SerpSnapshotRepository
+}
+
+func NewSerpSnapshotUseCase(snapshotRepo repositories.SerpSnapshotRepository) *SerpSnapshotUseCase {
+	return &SerpSnapshotUseCase{
+		snapshotRepo: snapshotRepo,
+	}
+}
+
+func (uc *SerpSnapshotUseCase) GetByPositionID(workspaceID *int, positionID int) (*entities.SerpSnapshot, error) {
+	snapshot, err := uc.snapshotRepo.GetByPositionID(positionID, workspaceID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorSerpSnapshotFetch,
+			Message: "Failed to fetch SERP snapshot",
+			Err:     err,
+		}
+	}
+
+	if snapshot == nil {
+		return nil, &DomainError{
+			Code:    ErrorSerpSnapshotNotFound,
+			Message: "SERP snapshot not found",
+			Err:     fmt.Errorf("no snapshot for position %d", positionID),
+		}
+	}
+
+	return snapshot, nil
+}
+
+// saveSerpSnapshot сохраняет топ выдачи для позиции. Ошибка только логируется:
+// позиция уже записана, а повтор проверки означал бы повторную оплату запроса к провайдеру
+func saveSerpSnapshot(repo repositories.SerpSnapshotRepository, position *entities.Position, items []entities.SerpItem) {
+	snapshot := &entities.SerpSnapshot{
+		PositionID: position.ID,
+		KeywordID:  position.KeywordID,
+		SiteID:     position.SiteID,
+		Source:     position.Source,
+		Date:       position.Date,
+		Items:      items,
+	}
+
+	if err := repo.ReplaceForPosition(snapshot); err != nil {
+		log.Printf("WARNING: Failed to save SERP snapshot for position %d: %v", position.ID, err)
+	}
+}
@@ -8,13 +8,16 @@ import (
 )
 
 type SiteUseCase struct {
-	siteRepo     repositories.SiteRepository
-	positionRepo repositories.PositionRepository
-	keywordRepo  repositories.KeywordRepository
-	groupRepo    repositories.GroupRepository
-	jobRepo      repositories.TrackingJobRepository
-	taskRepo     repositories.TrackingTaskRepository
-	resultRepo   repositories.TrackingResultRepository
+	siteRepo       repositories.SiteRepository
+	positionRepo   repositories.PositionRepository
+	keywordRepo    repositories.KeywordRepository
+	groupRepo      repositories.GroupRepository
+	jobRepo        repositories.TrackingJobRepository
+	taskRepo       repositories.TrackingTaskRepository
+	resultRepo     repositories.TrackingResultRepository
+	snapshotRepo   repositories.SerpSnapshotRepository
+	competitorRepo repositories.CompetitorRepository
+	scheduleRepo   repositories.TrackingScheduleRepository
 }
 
 func NewSiteUseCase(
@@ -25,21 +28,30 @@ func NewSiteUseCase(
 	jobRepo repositories.TrackingJobRepository,
 	taskRepo repositories.TrackingTaskRepository,
 	resultRepo repositories.TrackingResultRepository,
+	snapshotRepo repositories.SerpSnapshotRepository,
+	competitorRepo repositories.CompetitorRepository,
+	scheduleRepo repositories.TrackingScheduleRepository,
 ) *SiteUseCase {
 	return &SiteUseCase{
-		siteRepo:     siteRepo,
-		positionRepo: positionRepo,
-		keywordRepo:  keywordRepo,
-		groupRepo:    groupRepo,
-		jobRepo:      jobRepo,
-		taskRepo:     taskRepo,
-		resultRepo:   resultRepo,
+		siteRepo:       siteRepo,
+		positionRepo:   positionRepo,
+		keywordRepo:    keywordRepo,
+		groupRepo:      groupRepo,
+		jobRepo:        jobRepo,
+		taskRepo:       taskRepo,
+		resultRepo:     resultRepo,
+		snapshotRepo:   snapshotRepo,
+		competitorRepo: competitorRepo,
+		scheduleRepo:   scheduleRepo,
 	}
 }
 
-func (uc *SiteUseCase) CreateSite(domain string) (*entities.Site, error) {
+// CreateSite создает сайт в пространстве запроса; без пространства - в пространстве по умолчанию
+func (uc *SiteUseCase) CreateSite(workspaceID *int, domain string) (*entities.Site, error) {
 	site := &entities.Site{
-		Domain: domain,
+		WorkspaceID:  workspaceOrDefault(workspaceID),
+		Domain:       domain,
+		BudgetAction: entities.BudgetActionRefuse,
 	}
 
 	if err := uc.siteRepo.Create(site); err != nil {
@@ -53,12 +65,40 @@ func (uc *SiteUseCase) CreateSite(domain string) (*entities.Site, error) {
 	return site, nil
 }
 
-func (uc *SiteUseCase) DeleteSite(id int) error {
-	_, err := uc.siteRepo.GetByID(id)
+// SetBudget задает месячный бюджет сайта на запросы к провайдерам; nil снимает ограничение
+func (uc *SiteUseCase) SetBudget(workspaceID *int, id int, monthlyBudget *float64, action string) (*entities.Site, error) {
+	site, err := authorizeSite(uc.siteRepo, workspaceID, id)
 	if err != nil {
+		return nil, err
+	}
+
+	if action == "" {
+		action = entities.BudgetActionRefuse
+	}
+	site.MonthlyBudget = monthlyBudget
+	site.BudgetAction = action
+
+	if err := uc.siteRepo.Update(site); err != nil {
+		return nil, &DomainError{
+			Code:    ErrorSiteUpdate,
+			Message: "Failed to update site budget",
+			Err:     err,
+		}
+	}
+
+	return site, nil
+}
+
+func (uc *SiteUseCase) DeleteSite(workspaceID *int, id int) error {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, id); err != nil {
+		return err
+	}
+
+	// Сначала удаляем расписания, чтобы планировщик не запустил новый джоб для удаляемого сайта
+	if err := uc.scheduleRepo.DeleteBySiteID(id); err != nil {
 		return &DomainError{
-			Code:    ErrorSiteNotFound,
-			Message: "Site not found",
+			Code:    ErrorScheduleDeletion,
+			Message: "Failed to delete site tracking schedules",
 			Err:     err,
 		}
 	}
@@ -87,6 +127,14 @@ func (uc *SiteUseCase) DeleteSite(id int) error {
 		}
 	}
 
+	if err := uc.snapshotRepo.DeleteBySiteID(id); err != nil {
+		return &DomainError{
+			Code:    ErrorPositionDeletion,
+			Message: "Failed to delete site SERP snapshots",
+			Err:     err,
+		}
+	}
+
 	if err := uc.positionRepo.DeleteBySiteID(id); err != nil {
 		return &DomainError{
 			Code:    ErrorPositionDeletion,
@@ -111,6 +159,14 @@ func (uc *SiteUseCase) DeleteSite(id int) error {
 		}
 	}
 
+	if err := uc.competitorRepo.DeleteBySiteID(id); err != nil {
+		return &DomainError{
+			Code:    ErrorCompetitorDeletion,
+			Message: "Failed to delete site competitors",
+			Err:     err,
+		}
+	}
+
 	if err := uc.siteRepo.Delete(id); err != nil {
 		return &DomainError{
 			Code:    ErrorSiteDeletion,
@@ -122,8 +178,15 @@ func (uc *SiteUseCase) DeleteSite(id int) error {
 	return nil
 }
 
-func (uc *SiteUseCase) GetAllSites() ([]*entities.Site, error) {
-	sites, err := uc.siteRepo.GetAll()
+// GetAllSites возвращает сайты пространства запроса; без пространства - все сайты
+func (uc *SiteUseCase) GetAllSites(workspaceID *int) ([]*entities.Site, error) {
+	var sites []*entities.Site
+	var err error
+	if workspaceID != nil {
+		sites, err = uc.siteRepo.GetByWorkspaceID(*workspaceID)
+	} else {
+		sites, err = uc.siteRepo.GetAll()
+	}
 	if err != nil {
 		return nil, &DomainError{
 			Code:    ErrorSiteFetch,
@@ -135,8 +198,9 @@ func (uc *SiteUseCase) GetAllSites() ([]*entities.Site, error) {
 	return sites, nil
 }
 
-func (uc *SiteUseCase) GetSitesByIDs(ids []int) ([]*entities.Site, error) {
-	sites, err := uc.siteRepo.GetByIDs(ids)
+// GetSitesByIDs возвращает найденные сайты; сайты других пространств пропускаются, как несуществующие
+func (uc *SiteUseCase) GetSitesByIDs(workspaceID *int, ids []int) ([]*entities.Site, error) {
+	sites, err := uc.siteRepo.GetByIDs(ids, workspaceID)
 	if err != nil {
 		return nil, &DomainError{
 			Code:    ErrorSiteFetch,
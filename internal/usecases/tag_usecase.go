@@ -0,0 +1,269 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+)
+
+const (
+	maxTagNameLength  = 100
+	maxTagAssignLinks = 100000 // Слов, умноженных на метки, в одном назначении
+)
+
+type TagUseCase struct {
+	tagRepo     repositories.TagRepository
+	keywordRepo repositories.KeywordRepository
+	siteRepo    repositories.SiteRepository
+}
+
+func NewTagUseCase(tagRepo repositories.TagRepository, keywordRepo repositories.KeywordRepository, siteRepo repositories.SiteRepository) *TagUseCase {
+	return &TagUseCase{
+		tagRepo:     tagRepo,
+		keywordRepo: keywordRepo,
+		siteRepo:    siteRepo,
+	}
+}
+
+func (uc *TagUseCase) CreateTag(workspaceID *int, siteID int, name string) (*entities.Tag, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
+	name, err := validateTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &entities.Tag{
+		SiteID: siteID,
+		Name:   name,
+	}
+	if err := uc.tagRepo.Create(tag); err != nil {
+		return nil, tagWriteError(err, ErrorTagCreation, "Failed to create tag")
+	}
+
+	return tag, nil
+}
+
+func (uc *TagUseCase) UpdateTag(workspaceID *int, id int, name string) (*entities.Tag, error) {
+	tag, err := uc.getTag(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag.Name, err = validateTagName(name); err != nil {
+		return nil, err
+	}
+	if err := uc.tagRepo.Update(tag); err != nil {
+		return nil, tagWriteError(err, ErrorTagUpdate, "Failed to update tag")
+	}
+
+	return tag, nil
+}
+
+func (uc *TagUseCase) DeleteTag(workspaceID *int, id int) error {
+	if _, err := uc.getTag(workspaceID, id); err != nil {
+		return err
+	}
+
+	if err := uc.tagRepo.Delete(id); err != nil {
+		return &DomainError{
+			Code:    ErrorTagDeletion,
+			Message: "Failed to delete tag",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+func (uc *TagUseCase) GetTagsBySite(workspaceID *int, siteID int) ([]*entities.Tag, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
+	tags, err := uc.tagRepo.GetAllBySite(siteID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorTagFetch,
+			Message: "Failed to fetch tags",
+			Err:     err,
+		}
+	}
+
+	return tags, nil
+}
+
+// AssignTags добавляет каждому слову каждую метку; уже назначенные метки пропускаются.
+// Возвращает число новых связей
+func (uc *TagUseCase) AssignTags(workspaceID *int, keywordIDs, tagIDs []int) (int, error) {
+	keywordIDs, tagIDs, err := uc.checkAssignment(workspaceID, keywordIDs, tagIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	assigned, err := uc.tagRepo.Assign(keywordIDs, tagIDs)
+	if err != nil {
+		return 0, &DomainError{
+			Code:    ErrorTagAssign,
+			Message: "Failed to assign tags",
+			Err:     err,
+		}
+	}
+
+	return assigned, nil
+}
+
+// UnassignTags снимает метки со слов. Возвращает число удаленных связей
+func (uc *TagUseCase) UnassignTags(workspaceID *int, keywordIDs, tagIDs []int) (int, error) {
+	keywordIDs, tagIDs, err := uc.checkAssignment(workspaceID, keywordIDs, tagIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	removed, err := uc.tagRepo.Unassign(keywordIDs, tagIDs)
+	if err != nil {
+		return 0, &DomainError{
+			Code:    ErrorTagAssign,
+			Message: "Failed to unassign tags",
+			Err:     err,
+		}
+	}
+
+	return removed, nil
+}
+
+// checkAssignment убирает повторы ID и проверяет, что слова и метки существуют и относятся
+// к одному сайту, доступному в области запроса
+func (uc *TagUseCase) checkAssignment(workspaceID *int, keywordIDs, tagIDs []int) ([]int, []int, error) {
+	keywordIDs, tagIDs = uniqueIDs(keywordIDs), uniqueIDs(tagIDs)
+	if len(keywordIDs) == 0 || len(tagIDs) == 0 {
+		return nil, nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "keyword_ids and tag_ids must not be empty",
+		}
+	}
+	if len(keywordIDs)*len(tagIDs) > maxTagAssignLinks {
+		return nil, nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: fmt.Sprintf("Number of keywords multiplied by number of tags must not exceed %d", maxTagAssignLinks),
+		}
+	}
+
+	tags, err := uc.tagRepo.GetByIDs(tagIDs)
+	if err != nil {
+		return nil, nil, &DomainError{
+			Code:    ErrorTagFetch,
+			Message: "Failed to fetch tags",
+			Err:     err,
+		}
+	}
+	if len(tags) != len(tagIDs) {
+		return nil, nil, &DomainError{
+			Code:    ErrorTagNotFound,
+			Message: "Tag not found",
+		}
+	}
+	siteID := tags[0].SiteID
+	for _, tag := range tags {
+		if tag.SiteID != siteID {
+			return nil, nil, &DomainError{
+				Code:    ErrorValidation,
+				Message: "All tags must belong to the same site",
+			}
+		}
+	}
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, nil, &DomainError{
+			Code:    ErrorTagNotFound,
+			Message: "Tag not found",
+			Err:     err,
+		}
+	}
+
+	keywords, err := uc.keywordRepo.GetByIDs(keywordIDs)
+	if err != nil {
+		return nil, nil, &DomainError{
+			Code:    ErrorKeywordFetch,
+			Message: "Failed to fetch keywords",
+			Err:     err,
+		}
+	}
+	if len(keywords) != len(keywordIDs) {
+		return nil, nil, &DomainError{
+			Code:    ErrorKeywordNotFound,
+			Message: "Keyword not found",
+		}
+	}
+	for _, keyword := range keywords {
+		if keyword.SiteID != siteID {
+			return nil, nil, &DomainError{
+				Code:    ErrorValidation,
+				Message: fmt.Sprintf("Keyword %d and tags belong to different sites", keyword.ID),
+			}
+		}
+	}
+
+	return keywordIDs, tagIDs, nil
+}
+
+// getTag возвращает метку, если ее сайт доступен в области запроса
+func (uc *TagUseCase) getTag(workspaceID *int, id int) (*entities.Tag, error) {
+	tag, err := uc.tagRepo.GetByID(id)
+	if err == nil && workspaceID != nil {
+		_, err = authorizeSite(uc.siteRepo, workspaceID, tag.SiteID)
+	}
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorTagNotFound,
+			Message: "Tag not found",
+			Err:     err,
+		}
+	}
+
+	return tag, nil
+}
+
+func validateTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", &DomainError{
+			Code:    ErrorValidation,
+			Message: fmt.Sprintf("Tag name must be from 1 to %d characters", maxTagNameLength),
+		}
+	}
+	return name, nil
+}
+
+// tagWriteError имя метки уникально в сайте без учета регистра, нарушение уникальности - ErrorTagExists
+func tagWriteError(err error, code, message string) error {
+	if database.IsDatabaseError(err) && database.GetDatabaseErrorCode(err) == "DUPLICATE_ENTRY" {
+		return &DomainError{
+			Code:    ErrorTagExists,
+			Message: "Tag with this name already exists",
+			Err:     err,
+		}
+	}
+	return &DomainError{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,168 @@
+package usecases
+
+import (
+	"testing"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// memTagRepository хранит метки и их связи со словами в памяти
+type memTagRepository struct {
+	repositories.TagRepository
+	tags  map[int]*entities.Tag
+	links map[[2]int]bool // keyword ID, tag ID
+}
+
+func (r *memTagRepository) GetByID(id int) (*entities.Tag, error) {
+	if tag, ok := r.tags[id]; ok {
+		return tag, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memTagRepository) GetByIDs(ids []int) ([]*entities.Tag, error) {
+	var result []*entities.Tag
+	for _, id := range ids {
+		if tag, ok := r.tags[id]; ok {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+func (r *memTagRepository) Assign(keywordIDs, tagIDs []int) (int, error) {
+	added := 0
+	for _, keywordID := range keywordIDs {
+		for _, tagID := range tagIDs {
+			if link := [2]int{keywordID, tagID}; !r.links[link] {
+				r.links[link] = true
+				added++
+			}
+		}
+	}
+	return added, nil
+}
+
+func (r *memTagRepository) Unassign(keywordIDs, tagIDs []int) (int, error) {
+	removed := 0
+	for _, keywordID := range keywordIDs {
+		for _, tagID := range tagIDs {
+			if link := [2]int{keywordID, tagID}; r.links[link] {
+				delete(r.links, link)
+				removed++
+			}
+		}
+	}
+	return removed, nil
+}
+
+type memKeywordRepository struct {
+	repositories.KeywordRepository
+	keywords map[int]*entities.Keyword
+}
+
+func (r *memKeywordRepository) GetByIDs(ids []int) ([]*entities.Keyword, error) {
+	var result []*entities.Keyword
+	for _, id := range ids {
+		if keyword, ok := r.keywords[id]; ok {
+			result = append(result, keyword)
+		}
+	}
+	return result, nil
+}
+
+// newTagUseCase: метки 1 и 2 и слова 1 и 2 сайта 1, метка 3 и слово 3 сайта 2 другого пространства,
+// метка 4 и слово 4 сайта 3 того же пространства
+func newTagUseCase() (*TagUseCase, *memTagRepository) {
+	sites, _ := newScopeRepositories()
+	sites.sites[3] = &entities.Site{ID: 3, WorkspaceID: 1, Domain: "second.com"}
+
+	tags := &memTagRepository{
+		tags: map[int]*entities.Tag{
+			1: {ID: 1, SiteID: 1, Name: "a"},
+			2: {ID: 2, SiteID: 1, Name: "b"},
+			3: {ID: 3, SiteID: 2, Name: "other"},
+			4: {ID: 4, SiteID: 3, Name: "second"},
+		},
+		links: make(map[[2]int]bool),
+	}
+	keywords := &memKeywordRepository{keywords: map[int]*entities.Keyword{
+		1: {ID: 1, SiteID: 1},
+		2: {ID: 2, SiteID: 1},
+		3: {ID: 3, SiteID: 2},
+		4: {ID: 4, SiteID: 3},
+	}}
+	return NewTagUseCase(tags, keywords, sites), tags
+}
+
+func TestAssignAndUnassignTags(t *testing.T) {
+	uc, repo := newTagUseCase()
+
+	// Повторы ID не дают лишних связей, уже назначенные метки пропускаются
+	if assigned, err := uc.AssignTags(intPtr(1), []int{1, 2, 1}, []int{1}); err != nil || assigned != 2 {
+		t.Fatalf("expected 2 new links, got %d, %v", assigned, err)
+	}
+	if assigned, err := uc.AssignTags(intPtr(1), []int{1}, []int{1, 2, 2}); err != nil || assigned != 1 {
+		t.Fatalf("expected 1 new link, got %d, %v", assigned, err)
+	}
+	if len(repo.links) != 3 {
+		t.Fatalf("expected 3 links, got %v", repo.links)
+	}
+
+	if removed, err := uc.UnassignTags(intPtr(1), []int{1, 2}, []int{2}); err != nil || removed != 1 {
+		t.Fatalf("expected 1 removed link, got %d, %v", removed, err)
+	}
+	if removed, err := uc.UnassignTags(intPtr(1), []int{2}, []int{2}); err != nil || removed != 0 {
+		t.Fatalf("expected no removed links, got %d, %v", removed, err)
+	}
+	// Без пространства доступны все сайты
+	if assigned, err := uc.AssignTags(nil, []int{3}, []int{3}); err != nil || assigned != 1 {
+		t.Fatalf("expected an unscoped assignment, got %d, %v", assigned, err)
+	}
+}
+
+func TestTagAssignmentChecks(t *testing.T) {
+	uc, repo := newTagUseCase()
+
+	tests := []struct {
+		name       string
+		keywordIDs []int
+		tagIDs     []int
+		code       string
+	}{
+		{name: "no keywords", tagIDs: []int{1}, code: ErrorValidation},
+		{name: "no tags", keywordIDs: []int{1}, code: ErrorValidation},
+		{name: "missing tag", keywordIDs: []int{1}, tagIDs: []int{1, 9}, code: ErrorTagNotFound},
+		{name: "tag of another workspace", keywordIDs: []int{3}, tagIDs: []int{3}, code: ErrorTagNotFound},
+		{name: "tags of different sites", keywordIDs: []int{1}, tagIDs: []int{1, 4}, code: ErrorValidation},
+		{name: "tag of another site", keywordIDs: []int{1}, tagIDs: []int{4}, code: ErrorValidation},
+		{name: "keyword of another workspace", keywordIDs: []int{1, 3}, tagIDs: []int{1}, code: ErrorValidation},
+		{name: "missing keyword", keywordIDs: []int{1, 9}, tagIDs: []int{1}, code: ErrorKeywordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.AssignTags(intPtr(1), tt.keywordIDs, tt.tagIDs); GetDomainErrorCode(err) != tt.code {
+				t.Fatalf("assign: expected %s, got %v", tt.code, err)
+			}
+			if _, err := uc.UnassignTags(intPtr(1), tt.keywordIDs, tt.tagIDs); GetDomainErrorCode(err) != tt.code {
+				t.Fatalf("unassign: expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+
+	if len(repo.links) != 0 {
+		t.Fatalf("rejected assignments must not create links, got %v", repo.links)
+	}
+
+	// Метка другого пространства неотличима от несуществующей
+	if _, err := uc.UpdateTag(intPtr(1), 3, "renamed"); GetDomainErrorCode(err) != ErrorTagNotFound {
+		t.Fatalf("update: expected %s, got %v", ErrorTagNotFound, err)
+	}
+	if err := uc.DeleteTag(intPtr(1), 3); GetDomainErrorCode(err) != ErrorTagNotFound {
+		t.Fatalf("delete: expected %s, got %v", ErrorTagNotFound, err)
+	}
+}
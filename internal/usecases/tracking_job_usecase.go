@@ -7,28 +7,21 @@ import (
 )
 
 type TrackingJobUseCase struct {
-	trackingJobRepo repositories.TrackingJobRepository
+	trackingJobRepo  repositories.TrackingJobRepository
+	trackingTaskRepo repositories.TrackingTaskRepository
+	usageRepo        repositories.UsageRepository
 }
 
-func NewTrackingJobUseCase(trackingJobRepo repositories.TrackingJobRepository) *TrackingJobUseCase {
+func NewTrackingJobUseCase(trackingJobRepo repositories.TrackingJobRepository, trackingTaskRepo repositories.TrackingTaskRepository, usageRepo repositories.UsageRepository) *TrackingJobUseCase {
 	return &TrackingJobUseCase{
-		trackingJobRepo: trackingJobRepo,
+		trackingJobRepo:  trackingJobRepo,
+		trackingTaskRepo: trackingTaskRepo,
+		usageRepo:        usageRepo,
 	}
 }
 
-func (uc *TrackingJobUseCase) GetJobsWithPagination(req *dto.TrackingJobsRequest) (*dto.TrackingJobsResponse, error) {
-	// Устанавливаем значения по умолчанию
-	page := req.Page
-	if page <= 0 {
-		page = 1
-	}
-	perPage := req.PerPage
-	if perPage <= 0 {
-		perPage = 20
-	}
-	if perPage > 100 {
-		perPage = 100
-	}
+func (uc *TrackingJobUseCase) GetJobsWithPagination(workspaceID *int, req *dto.TrackingJobsRequest) (*dto.TrackingJobsResponse, error) {
+	page, perPage := normalizePage(req.Page, req.PerPage)
 
 	// Конвертируем статус в entity
 	var status *entities.TrackingTaskStatus
@@ -38,7 +31,7 @@ func (uc *TrackingJobUseCase) GetJobsWithPagination(req *dto.TrackingJobsRequest
 	}
 
 	// Получаем данные из репозитория
-	jobs, total, err := uc.trackingJobRepo.GetJobsWithPagination(page, perPage, req.SiteID, status)
+	jobs, total, err := uc.trackingJobRepo.GetJobsWithPagination(page, perPage, workspaceID, req.SiteID, status)
 	if err != nil {
 		return nil, err
 	}
@@ -63,11 +56,92 @@ func (uc *TrackingJobUseCase) GetJobsWithPagination(req *dto.TrackingJobsRequest
 			CompletedTasks: job.CompletedTasks,
 			FailedTasks:    job.FailedTasks,
 			Error:          job.Error,
+			APIKeyID:       job.APIKeyID,
 			Progress:       progress,
 		})
 	}
 
-	// Вычисляем пагинацию
+	return &dto.TrackingJobsResponse{
+		Data:       jobItems,
+		Pagination: newPaginationInfo(page, perPage, total),
+		Meta: dto.MetaInfo{
+			QueryTimeMs: 0, // Можно добавить измерение времени запроса
+			Cached:      false,
+		},
+	}, nil
+}
+
+func (uc *TrackingJobUseCase) GetJob(workspaceID *int, id string) (*entities.TrackingJob, error) {
+	return authorizeJob(uc.trackingJobRepo, workspaceID, id)
+}
+
+// GetJobUsage возвращает число запросов к провайдеру и их стоимость по журналу расхода
+func (uc *TrackingJobUseCase) GetJobUsage(jobID string) (int, float64, error) {
+	requests, cost, err := uc.usageRepo.GetJobTotals(jobID)
+	if err != nil {
+		return 0, 0, &DomainError{
+			Code:    ErrorJobFetch,
+			Message: "Failed to fetch job usage",
+			Err:     err,
+		}
+	}
+	return requests, cost, nil
+}
+
+// GetJobResults возвращает постранично итоги джоба по каждому keyword: позицию, ошибку и число попыток
+func (uc *TrackingJobUseCase) GetJobResults(jobID string, req *dto.TrackingJobDetailRequest) ([]dto.TrackingJobKeywordResult, dto.PaginationInfo, error) {
+	page, perPage := normalizePage(req.Page, req.PerPage)
+
+	var status *entities.TrackingTaskStatus
+	if req.Status != nil {
+		s := entities.TrackingTaskStatus(*req.Status)
+		status = &s
+	}
+
+	outcomes, total, err := uc.trackingTaskRepo.GetOutcomesByJobID(jobID, status, page, perPage)
+	if err != nil {
+		return nil, dto.PaginationInfo{}, &DomainError{
+			Code:    ErrorJobFetch,
+			Message: "Failed to fetch tracking job results",
+			Err:     err,
+		}
+	}
+
+	results := make([]dto.TrackingJobKeywordResult, len(outcomes))
+	for i, outcome := range outcomes {
+		results[i] = dto.TrackingJobKeywordResult{
+			TaskID:      outcome.TaskID,
+			KeywordID:   outcome.KeywordID,
+			Keyword:     outcome.Keyword,
+			QueryType:   outcome.WordstatQueryType,
+			Status:      string(outcome.Status),
+			Rank:        outcome.Rank,
+			URL:         outcome.URL,
+			Title:       outcome.Title,
+			Error:       outcome.Error,
+			Attempts:    outcome.Attempts,
+			CompletedAt: outcome.CompletedAt,
+		}
+	}
+
+	return results, newPaginationInfo(page, perPage, total), nil
+}
+
+// normalizePage подставляет значения по умолчанию: первая страница, 20 записей, не больше 100
+func normalizePage(page, perPage int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = 20
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+	return page, perPage
+}
+
+func newPaginationInfo(page, perPage int, total int64) dto.PaginationInfo {
 	lastPage := int((total + int64(perPage) - 1) / int64(perPage))
 	from := (page-1)*perPage + 1
 	to := page * perPage
@@ -78,20 +152,13 @@ func (uc *TrackingJobUseCase) GetJobsWithPagination(req *dto.TrackingJobsRequest
 		from = 0
 	}
 
-	return &dto.TrackingJobsResponse{
-		Data: jobItems,
-		Pagination: dto.PaginationInfo{
-			CurrentPage: page,
-			PerPage:     perPage,
-			Total:       int(total),
-			LastPage:    lastPage,
-			From:        from,
-			To:          to,
-			HasMore:     page < lastPage,
-		},
-		Meta: dto.MetaInfo{
-			QueryTimeMs: 0, // Можно добавить измерение времени запроса
-			Cached:      false,
-		},
-	}, nil
+	return dto.PaginationInfo{
+		CurrentPage: page,
+		PerPage:     perPage,
+		Total:       int(total),
+		LastPage:    lastPage,
+		From:        from,
+		To:          to,
+		HasMore:     page < lastPage,
+	}
 }
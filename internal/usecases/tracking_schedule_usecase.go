@@ -0,0 +1,332 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/pkg/cron"
+)
+
+type TrackingScheduleUseCase struct {
+	scheduleRepo repositories.TrackingScheduleRepository
+	siteRepo     repositories.SiteRepository
+	jobRepo      repositories.TrackingJobRepository
+	tracking     *AsyncPositionTrackingUseCase
+}
+
+func NewTrackingScheduleUseCase(
+	scheduleRepo repositories.TrackingScheduleRepository,
+	siteRepo repositories.SiteRepository,
+	jobRepo repositories.TrackingJobRepository,
+	tracking *AsyncPositionTrackingUseCase,
+) *TrackingScheduleUseCase {
+	return &TrackingScheduleUseCase{
+		scheduleRepo: scheduleRepo,
+		siteRepo:     siteRepo,
+		jobRepo:      jobRepo,
+		tracking:     tracking,
+	}
+}
+
+func (uc *TrackingScheduleUseCase) CreateSchedule(workspaceID *int, schedule *entities.TrackingSchedule) (*entities.TrackingSchedule, error) {
+	if err := uc.prepareSchedule(workspaceID, schedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := uc.scheduleRepo.Create(schedule); err != nil {
+		return nil, &DomainError{
+			Code:    ErrorScheduleCreation,
+			Message: "Failed to create tracking schedule",
+			Err:     err,
+		}
+	}
+
+	return schedule, nil
+}
+
+func (uc *TrackingScheduleUseCase) GetSchedule(workspaceID *int, id int) (*entities.TrackingSchedule, error) {
+	schedule, err := uc.scheduleRepo.GetByID(id)
+	if err == nil && workspaceID != nil {
+		_, err = authorizeSite(uc.siteRepo, workspaceID, schedule.SiteID)
+	}
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorScheduleNotFound,
+			Message: "Tracking schedule not found",
+			Err:     err,
+		}
+	}
+
+	return schedule, nil
+}
+
+func (uc *TrackingScheduleUseCase) GetSchedules(workspaceID *int, siteID *int) ([]*entities.TrackingSchedule, error) {
+	schedules, err := uc.scheduleRepo.GetAll(workspaceID, siteID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorScheduleFetch,
+			Message: "Failed to fetch tracking schedules",
+			Err:     err,
+		}
+	}
+
+	return schedules, nil
+}
+
+// UpdateSchedule заменяет параметры расписания; история запусков сохраняется
+func (uc *TrackingScheduleUseCase) UpdateSchedule(workspaceID *int, id int, update *entities.TrackingSchedule) (*entities.TrackingSchedule, error) {
+	schedule, err := uc.GetSchedule(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.SiteID = update.SiteID
+	schedule.Source = update.Source
+	schedule.Params = update.Params
+	schedule.CronExpr = update.CronExpr
+	schedule.Timezone = update.Timezone
+	schedule.Enabled = update.Enabled
+
+	if err := uc.prepareSchedule(workspaceID, schedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := uc.scheduleRepo.Update(schedule); err != nil {
+		return nil, &DomainError{
+			Code:    ErrorScheduleUpdate,
+			Message: "Failed to update tracking schedule",
+			Err:     err,
+		}
+	}
+
+	return schedule, nil
+}
+
+// SetEnabled ставит расписание на паузу или возобновляет его; при возобновлении время запуска считается от текущего момента
+func (uc *TrackingScheduleUseCase) SetEnabled(workspaceID *int, id int, enabled bool) (*entities.TrackingSchedule, error) {
+	schedule, err := uc.GetSchedule(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Enabled = enabled
+	if err := uc.prepareSchedule(workspaceID, schedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := uc.scheduleRepo.Update(schedule); err != nil {
+		return nil, &DomainError{
+			Code:    ErrorScheduleUpdate,
+			Message: "Failed to update tracking schedule",
+			Err:     err,
+		}
+	}
+
+	return schedule, nil
+}
+
+func (uc *TrackingScheduleUseCase) DeleteSchedule(workspaceID *int, id int) error {
+	if _, err := uc.GetSchedule(workspaceID, id); err != nil {
+		return err
+	}
+
+	if err := uc.scheduleRepo.Delete(id); err != nil {
+		return &DomainError{
+			Code:    ErrorScheduleDeletion,
+			Message: "Failed to delete tracking schedule",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// Run проверяет расписания каждые interval, пока не отменен ctx
+func (uc *TrackingScheduleUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	uc.RunDueSchedules(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			uc.RunDueSchedules(now)
+		}
+	}
+}
+
+// RunDueSchedules запускает все расписания, время которых наступило.
+// Пропущенные во время простоя запуски не догоняются: расписание срабатывает один раз и переносится на следующее время.
+func (uc *TrackingScheduleUseCase) RunDueSchedules(now time.Time) {
+	schedules, err := uc.scheduleRepo.GetDue(now)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch due tracking schedules: %v", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		uc.runSchedule(schedule, now)
+	}
+}
+
+func (uc *TrackingScheduleUseCase) runSchedule(schedule *entities.TrackingSchedule, now time.Time) {
+	nextRunAt, err := nextScheduleRun(schedule, now)
+
+	// Планировщик работает на каждом экземпляре сервиса: запуск достается тому, кто первым перенес next_run_at
+	claimed, claimErr := uc.scheduleRepo.Claim(schedule.ID, *schedule.NextRunAt, nextRunAt)
+	if claimErr != nil {
+		log.Printf("ERROR: Failed to claim tracking schedule %d: %v", schedule.ID, claimErr)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	if err != nil {
+		log.Printf("ERROR: Tracking schedule %d has invalid timing: %v", schedule.ID, err)
+		if updateErr := uc.scheduleRepo.UpdateRunInfo(schedule.ID, now, nil, schedule.LastJobID, err.Error()); updateErr != nil {
+			log.Printf("ERROR: Failed to update tracking schedule %d: %v", schedule.ID, updateErr)
+		}
+		return
+	}
+
+	jobID := schedule.LastJobID
+	var runError string
+
+	active, err := uc.jobRepo.HasActiveJob(schedule.SiteID, schedule.Source)
+	switch {
+	case err != nil:
+		runError = fmt.Sprintf("failed to check previous job: %v", err)
+	case active:
+		runError = "skipped: previous job for this site and source is not finished"
+	default:
+		jobID, err = uc.startJob(schedule)
+		if err != nil {
+			jobID = schedule.LastJobID
+			runError = err.Error()
+		}
+	}
+
+	if runError != "" {
+		log.Printf("WARNING: Tracking schedule %d did not start a job: %s", schedule.ID, runError)
+	}
+
+	if err := uc.scheduleRepo.UpdateRunInfo(schedule.ID, now, nextRunAt, jobID, runError); err != nil {
+		log.Printf("ERROR: Failed to update tracking schedule %d: %v", schedule.ID, err)
+	}
+}
+
+// startJob запускает джоб без области запроса: сайт и аккаунт уже проверены при сохранении расписания
+func (uc *TrackingScheduleUseCase) startJob(schedule *entities.TrackingSchedule) (string, error) {
+	p := schedule.Params
+
+	switch schedule.Source {
+	case entities.GoogleSearch:
+		return uc.tracking.StartAsyncGoogleTracking(
+			nil, schedule.SiteID, p.Device, p.OS, p.Ads, p.Country, p.Lang, p.Pages, p.Subdomains,
+			p.AccountID, p.Provider, p.TBS, p.Filter, p.Highlights, p.NFPR, p.Loc, p.AI, p.Raw,
+			p.LR, p.Domain, p.Strict, p.FilterGroupID, nil,
+		)
+	case entities.YandexSearch:
+		return uc.tracking.StartAsyncYandexTracking(
+			nil, schedule.SiteID, p.Device, p.OS, p.Ads, p.Country, p.Lang, p.Pages, p.Subdomains,
+			p.AccountID, p.Provider, p.GroupBy, p.Filter, p.Highlights, p.Within, p.LR, p.YandexDomain, p.Raw, p.InIndex, p.Strict,
+			p.Organic, p.FilterGroupID, nil,
+		)
+	case entities.Wordstat:
+		return uc.tracking.StartAsyncWordstatTracking(
+			nil, schedule.SiteID, p.AccountID, p.Regions,
+			p.DefaultQuery, p.Quotes, p.QuotesExclamationMarks, p.ExclamationMarks, nil,
+		)
+	default:
+		return "", fmt.Errorf("unknown source: %s", schedule.Source)
+	}
+}
+
+// prepareSchedule проверяет расписание и пересчитывает время следующего запуска.
+// Аккаунт провайдера должен принадлежать пространству сайта, а не ключа
+func (uc *TrackingScheduleUseCase) prepareSchedule(workspaceID *int, schedule *entities.TrackingSchedule, now time.Time) error {
+	site, err := authorizeSite(uc.siteRepo, workspaceID, schedule.SiteID)
+	if err != nil {
+		return err
+	}
+
+	account, err := uc.tracking.accounts.Resolve(site.WorkspaceID, schedule.Params.AccountID)
+	if err != nil {
+		return err
+	}
+
+	switch schedule.Source {
+	case entities.GoogleSearch, entities.YandexSearch:
+		p := schedule.Params
+		if err := validateTrackingParams(p.Raw, p.InIndex); err != nil {
+			return err
+		}
+		if _, err := resolveSearchProvider(uc.tracking.providers, p.Provider, schedule.Source, p.Pages, account); err != nil {
+			return err
+		}
+	case entities.Wordstat:
+		p := schedule.Params
+		if _, err := resolveWordstat(uc.tracking.wordstat, account); err != nil {
+			return err
+		}
+		if !p.DefaultQuery && !p.Quotes && !p.QuotesExclamationMarks && !p.ExclamationMarks {
+			return &DomainError{
+				Code:    ErrorValidation,
+				Message: "At least one wordstat query type must be enabled",
+			}
+		}
+	default:
+		return &DomainError{
+			Code:    ErrorValidation,
+			Message: "Invalid source. Must be 'google', 'yandex' or 'wordstat'",
+		}
+	}
+
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+
+	nextRunAt, err := nextScheduleRun(schedule, now)
+	if err != nil {
+		return &DomainError{
+			Code:    ErrorValidation,
+			Message: err.Error(),
+			Err:     err,
+		}
+	}
+
+	schedule.NextRunAt = nil
+	if schedule.Enabled {
+		schedule.NextRunAt = nextRunAt
+	}
+
+	return nil
+}
+
+func nextScheduleRun(schedule *entities.TrackingSchedule, now time.Time) (*time.Time, error) {
+	expr, err := cron.Parse(schedule.CronExpr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression: %w", err)
+	}
+
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %w", err)
+	}
+
+	next := expr.Next(now.In(loc))
+	if next.IsZero() {
+		return nil, fmt.Errorf("cron expression %q never fires", schedule.CronExpr)
+	}
+
+	// Время хранится с точностью timestamptz, иначе Claim не найдет расписание по next_run_at
+	next = next.UTC().Truncate(time.Microsecond)
+	return &next, nil
+}
@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+)
+
+type UsageUseCase struct {
+	usageRepo repositories.UsageRepository
+}
+
+func NewUsageUseCase(usageRepo repositories.UsageRepository) *UsageUseCase {
+	return &UsageUseCase{
+		usageRepo: usageRepo,
+	}
+}
+
+// GetUsage возвращает расход по дням, сайтам и источникам. dateTo включается целиком;
+// без дат берется текущий месяц, как при проверке бюджета
+func (uc *UsageUseCase) GetUsage(workspaceID *int, siteID *int, source *string, dateFrom, dateTo *time.Time) ([]*entities.UsageSummary, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	if dateFrom != nil {
+		from = *dateFrom
+	}
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if dateTo != nil {
+		to = *dateTo
+	}
+
+	if to.Before(from) {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "date_to must not be before date_from",
+		}
+	}
+
+	summary, err := uc.usageRepo.GetDailySummary(workspaceID, siteID, source, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorUsageFetch,
+			Message: "Failed to fetch provider usage",
+			Err:     err,
+		}
+	}
+
+	return summary, nil
+}
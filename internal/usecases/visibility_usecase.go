@@ -0,0 +1,212 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+)
+
+// maxVisibilityDays ограничивает период запроса: пропущенные дни досчитываются при чтении
+const maxVisibilityDays = 366
+
+type VisibilityUseCase struct {
+	visibilityRepo repositories.VisibilityRepository
+	siteRepo       repositories.SiteRepository
+	curve          entities.CTRCurve
+}
+
+func NewVisibilityUseCase(visibilityRepo repositories.VisibilityRepository, siteRepo repositories.SiteRepository) *VisibilityUseCase {
+	return &VisibilityUseCase{
+		visibilityRepo: visibilityRepo,
+		siteRepo:       siteRepo,
+		curve:          entities.DefaultCTRCurve,
+	}
+}
+
+// GetVisibility возвращает сохраненную видимость сайта по дням. Дни, когда сайт проверялся,
+// но видимость еще не считалась, рассчитываются и сохраняются. Без дат берутся последние 30 дней
+func (uc *VisibilityUseCase) GetVisibility(workspaceID *int, siteID int, source *string, groupID, filterGroupID *int, dateFrom, dateTo *time.Time) ([]*entities.SiteVisibility, error) {
+	if _, err := authorizeSite(uc.siteRepo, workspaceID, siteID); err != nil {
+		return nil, err
+	}
+
+	to := startOfDay(time.Now())
+	if dateTo != nil {
+		to = startOfDay(*dateTo)
+	}
+	from := to.AddDate(0, 0, -29)
+	if dateFrom != nil {
+		from = startOfDay(*dateFrom)
+	}
+
+	if to.Before(from) {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "date_to must not be before date_from",
+		}
+	}
+	if to.Sub(from) >= maxVisibilityDays*24*time.Hour {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "Visibility period must not exceed 366 days",
+		}
+	}
+
+	missing, err := uc.visibilityRepo.GetUncalculatedDays(siteID, from, to)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorVisibilityFetch,
+			Message: "Failed to fetch visibility",
+			Err:     err,
+		}
+	}
+	for _, day := range missing {
+		if err := uc.RecalculateDay(siteID, day); err != nil {
+			return nil, &DomainError{
+				Code:    ErrorVisibilityFetch,
+				Message: "Failed to calculate visibility",
+				Err:     err,
+			}
+		}
+	}
+
+	series, err := uc.visibilityRepo.GetSeries(siteID, source, groupID, filterGroupID, from, to)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorVisibilityFetch,
+			Message: "Failed to fetch visibility",
+			Err:     err,
+		}
+	}
+
+	return series, nil
+}
+
+// RecalculateDay пересчитывает и сохраняет видимость сайта за день по всем источникам, группам и группам фильтров
+func (uc *VisibilityUseCase) RecalculateDay(siteID int, day time.Time) error {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+
+	ranks, err := uc.visibilityRepo.GetKeywordRanks(siteID, day)
+	if err != nil {
+		return err
+	}
+
+	return uc.visibilityRepo.ReplaceDay(siteID, day, calculateVisibility(siteID, day, ranks, uc.curve, time.Now()))
+}
+
+// Run пересчитывает видимость всех сайтов за сегодня и вчера каждые interval, пока не отменен ctx.
+// Вчерашний день пересчитывается, чтобы учесть проверки, завершившиеся после полуночи
+func (uc *VisibilityUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	uc.recalculateRecent(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			uc.recalculateRecent(ctx, now)
+		}
+	}
+}
+
+func (uc *VisibilityUseCase) recalculateRecent(ctx context.Context, now time.Time) {
+	sites, err := uc.siteRepo.GetAll()
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch sites for visibility: %v", err)
+		return
+	}
+
+	today := startOfDay(now)
+	for _, site := range sites {
+		if ctx.Err() != nil {
+			return
+		}
+		for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+			if err := uc.RecalculateDay(site.ID, day); err != nil {
+				log.Printf("ERROR: Failed to calculate visibility of site %d for %s: %v", site.ID, day.Format("2006-01-02"), err)
+			}
+		}
+	}
+}
+
+type visibilityKey struct {
+	source        string
+	groupID       int
+	filterGroupID int
+}
+
+// calculateVisibility группирует позиции по источнику и группе фильтров: по всем ключевым словам сайта
+// и отдельно по каждой группе ключевых слов. Ключевые слова без частоты Wordstat в трафик не входят
+func calculateVisibility(siteID int, day time.Time, ranks []*entities.KeywordRank, curve entities.CTRCurve, now time.Time) []*entities.SiteVisibility {
+	buckets := make(map[visibilityKey]*entities.SiteVisibility)
+	var order []visibilityKey
+
+	add := func(key visibilityKey, rank *entities.KeywordRank) {
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &entities.SiteVisibility{
+				SiteID:        siteID,
+				Date:          day,
+				Source:        key.source,
+				GroupID:       zeroToNil(key.groupID),
+				FilterGroupID: zeroToNil(key.filterGroupID),
+				CalculatedAt:  now,
+			}
+			buckets[key] = bucket
+			order = append(order, key)
+		}
+
+		bucket.KeywordsCount++
+		if rank.Rank > 0 {
+			bucket.FoundCount++
+		}
+		if rank.Rank > 0 && rank.Rank <= 10 {
+			bucket.Top10Count++
+		}
+		bucket.Frequency += int64(rank.Frequency)
+		bucket.EstimatedTraffic += float64(rank.Frequency) * curve.CTR(rank.Rank)
+	}
+
+	for _, rank := range ranks {
+		key := visibilityKey{source: rank.Source}
+		if rank.FilterGroupID != nil {
+			key.filterGroupID = *rank.FilterGroupID
+		}
+		add(key, rank)
+
+		if rank.GroupID != nil {
+			key.groupID = *rank.GroupID
+			add(key, rank)
+		}
+	}
+
+	maxCTR := curve.CTR(1)
+	result := make([]*entities.SiteVisibility, 0, len(order))
+	for _, key := range order {
+		bucket := buckets[key]
+		if bucket.Frequency > 0 && maxCTR > 0 {
+			bucket.Visibility = math.Round(bucket.EstimatedTraffic/(float64(bucket.Frequency)*maxCTR)*10000) / 100
+		}
+		bucket.EstimatedTraffic = math.Round(bucket.EstimatedTraffic*100) / 100
+		result = append(result, bucket)
+	}
+
+	return result
+}
+
+func zeroToNil(value int) *int {
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-seo/internal/domain/entities"
+)
+
+type fakeVisibilityRepository struct {
+	ranks    []*entities.KeywordRank
+	missing  []time.Time
+	replaced map[string][]*entities.SiteVisibility
+	err      error
+}
+
+func (r *fakeVisibilityRepository) GetKeywordRanks(siteID int, day time.Time) ([]*entities.KeywordRank, error) {
+	return r.ranks, nil
+}
+
+func (r *fakeVisibilityRepository) ReplaceDay(siteID int, day time.Time, rows []*entities.SiteVisibility) error {
+	if r.replaced == nil {
+		r.replaced = make(map[string][]*entities.SiteVisibility)
+	}
+	r.replaced[day.Format("2006-01-02")] = rows
+	return nil
+}
+
+func (r *fakeVisibilityRepository) GetUncalculatedDays(siteID int, dateFrom, dateTo time.Time) ([]time.Time, error) {
+	return r.missing, r.err
+}
+
+func (r *fakeVisibilityRepository) GetSeries(siteID int, source *string, groupID, filterGroupID *int, dateFrom, dateTo time.Time) ([]*entities.SiteVisibility, error) {
+	var series []*entities.SiteVisibility
+	for _, rows := range r.replaced {
+		series = append(series, rows...)
+	}
+	return series, nil
+}
+
+func TestCalculateVisibility(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	ranks := []*entities.KeywordRank{
+		{KeywordID: 1, GroupID: intPtr(5), Source: entities.GoogleSearch, Rank: 1, Frequency: 100},
+		{KeywordID: 2, Source: entities.GoogleSearch, Rank: 15, Frequency: 100},
+		// Без частоты ключевое слово учитывается в счетчиках, но не в трафике
+		{KeywordID: 3, Source: entities.GoogleSearch, Rank: 0},
+		{KeywordID: 4, Source: entities.YandexSearch, FilterGroupID: intPtr(7), Rank: 2, Frequency: 50},
+	}
+
+	rows := calculateVisibility(1, day, ranks, entities.DefaultCTRCurve, day)
+
+	type bucket struct {
+		source                 string
+		groupID, filterGroupID int
+		keywords, found, top10 int
+		frequency              int64
+		traffic, visibility    float64
+	}
+	want := []bucket{
+		{source: entities.GoogleSearch, keywords: 3, found: 2, top10: 1, frequency: 200, traffic: 28.8, visibility: 51.43},
+		{source: entities.GoogleSearch, groupID: 5, keywords: 1, found: 1, top10: 1, frequency: 100, traffic: 28, visibility: 100},
+		{source: entities.YandexSearch, filterGroupID: 7, keywords: 1, found: 1, top10: 1, frequency: 50, traffic: 7.85, visibility: 56.07},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("expected %d buckets, got %d", len(want), len(rows))
+	}
+	for i, row := range rows {
+		got := bucket{
+			source:        row.Source,
+			groupID:       intOrZero(row.GroupID),
+			filterGroupID: intOrZero(row.FilterGroupID),
+			keywords:      row.KeywordsCount,
+			found:         row.FoundCount,
+			top10:         row.Top10Count,
+			frequency:     row.Frequency,
+			traffic:       row.EstimatedTraffic,
+			visibility:    row.Visibility,
+		}
+		if got != want[i] {
+			t.Errorf("bucket %d: expected %+v, got %+v", i, want[i], got)
+		}
+	}
+}
+
+func intOrZero(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func TestGetVisibilityCalculatesMissingDays(t *testing.T) {
+	sites, _ := newScopeRepositories()
+	missing := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	repo := &fakeVisibilityRepository{
+		ranks:   []*entities.KeywordRank{{KeywordID: 1, Source: entities.GoogleSearch, Rank: 3, Frequency: 10}},
+		missing: []time.Time{missing},
+	}
+	uc := NewVisibilityUseCase(repo, sites)
+
+	from, to := missing.AddDate(0, 0, -3), missing.AddDate(0, 0, 3)
+	series, err := uc.GetVisibility(intPtr(1), 1, nil, nil, nil, &from, &to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.replaced) != 1 || len(repo.replaced["2026-10-01"]) != 1 {
+		t.Fatalf("expected the missing day to be calculated once, got %v", repo.replaced)
+	}
+	if len(series) != 1 || series[0].KeywordsCount != 1 || series[0].FoundCount != 1 {
+		t.Fatalf("expected the calculated day in the series, got %+v", series)
+	}
+}
+
+func TestGetVisibilityErrors(t *testing.T) {
+	sites, _ := newScopeRepositories()
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	before, yearLater := day.AddDate(0, 0, -1), day.AddDate(1, 0, 1)
+
+	tests := []struct {
+		name        string
+		workspaceID *int
+		siteID      int
+		from, to    *time.Time
+		repoErr     error
+		code        string
+	}{
+		{name: "other workspace site", workspaceID: intPtr(1), siteID: 2, code: ErrorSiteNotFound},
+		{name: "reversed period", workspaceID: intPtr(1), siteID: 1, from: &day, to: &before, code: ErrorValidation},
+		{name: "period longer than a year", workspaceID: intPtr(1), siteID: 1, from: &day, to: &yearLater, code: ErrorValidation},
+		{name: "repository failure", workspaceID: intPtr(1), siteID: 1, repoErr: errors.New("connection refused"), code: ErrorVisibilityFetch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewVisibilityUseCase(&fakeVisibilityRepository{err: tt.repoErr}, sites)
+
+			series, err := uc.GetVisibility(tt.workspaceID, tt.siteID, nil, nil, nil, tt.from, tt.to)
+			if GetDomainErrorCode(err) != tt.code || series != nil {
+				t.Fatalf("expected %s, got %+v and error %v", tt.code, series, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+)
+
+// Область данных запроса передается в use case как workspaceID *int: ID пространства ключа API
+// (или выбранного системным ключом), nil - без ограничения (системный ключ или выключенная авторизация).
+// Все данные пространства привязаны к сайтам, поэтому доступ проверяется по сайту.
+
+func inWorkspace(workspaceID *int, siteWorkspaceID int) bool {
+	return workspaceID == nil || *workspaceID == siteWorkspaceID
+}
+
+// workspaceOrDefault пространство, в котором создаются сайты и аккаунты
+func workspaceOrDefault(workspaceID *int) int {
+	if workspaceID == nil {
+		return entities.DefaultWorkspaceID
+	}
+	return *workspaceID
+}
+
+// authorizeSite возвращает сайт, если он доступен в области запроса. Пространство проверяет сам запрос
+// к репозиторию: сайт чужого пространства неотличим от несуществующего, чтобы не раскрывать чужие ID
+func authorizeSite(siteRepo repositories.SiteRepository, workspaceID *int, siteID int) (*entities.Site, error) {
+	site, err := siteRepo.GetByIDInWorkspace(siteID, workspaceID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorSiteNotFound,
+			Message: "Site not found",
+			Err:     err,
+		}
+	}
+
+	return site, nil
+}
+
+// authorizeJob возвращает джоб, если его сайт доступен в области запроса
+func authorizeJob(jobRepo repositories.TrackingJobRepository, workspaceID *int, id string) (*entities.TrackingJob, error) {
+	job, err := jobRepo.GetByIDInWorkspace(id, workspaceID)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorJobNotFound,
+			Message: "Tracking job not found",
+			Err:     err,
+		}
+	}
+
+	return job, nil
+}
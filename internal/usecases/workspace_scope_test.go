@@ -0,0 +1,216 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
+
+	"gorm.io/gorm"
+)
+
+// fakeSiteRepository реализует только поиск по ID; остальные методы не нужны проверкам доступа
+type fakeSiteRepository struct {
+	repositories.SiteRepository
+	sites map[int]*entities.Site
+}
+
+func (r *fakeSiteRepository) GetByID(id int) (*entities.Site, error) {
+	if site, ok := r.sites[id]; ok {
+		return site, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSiteRepository) GetByIDInWorkspace(id int, workspaceID *int) (*entities.Site, error) {
+	if !r.visible(id, workspaceID) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.sites[id], nil
+}
+
+// visible повторяет условие запросов репозиториев: строка видна, если ее сайт есть в пространстве workspaceID
+func (r *fakeSiteRepository) visible(siteID int, workspaceID *int) bool {
+	site, ok := r.sites[siteID]
+	return ok && (workspaceID == nil || site.WorkspaceID == *workspaceID)
+}
+
+type fakeTrackingJobRepository struct {
+	repositories.TrackingJobRepository
+	jobs  map[string]*entities.TrackingJob
+	sites *fakeSiteRepository
+}
+
+func (r *fakeTrackingJobRepository) GetByIDInWorkspace(id string, workspaceID *int) (*entities.TrackingJob, error) {
+	if job, ok := r.jobs[id]; ok && (workspaceID == nil || r.sites.visible(job.SiteID, workspaceID)) {
+		return job, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// Сайт 1 принадлежит пространству 1, сайт 2 - пространству 2
+func newScopeRepositories() (*fakeSiteRepository, *fakeTrackingJobRepository) {
+	sites := &fakeSiteRepository{sites: map[int]*entities.Site{
+		1: {ID: 1, WorkspaceID: 1, Domain: "own.com"},
+		2: {ID: 2, WorkspaceID: 2, Domain: "other.com"},
+	}}
+	jobs := &fakeTrackingJobRepository{jobs: map[string]*entities.TrackingJob{
+		"own":    {ID: "own", SiteID: 1},
+		"other":  {ID: "other", SiteID: 2},
+		"orphan": {ID: "orphan", SiteID: 3},
+	}, sites: sites}
+	return sites, jobs
+}
+
+func TestAuthorizeSite(t *testing.T) {
+	sites, _ := newScopeRepositories()
+
+	tests := []struct {
+		name        string
+		workspaceID *int
+		siteID      int
+		wantErr     bool
+	}{
+		{name: "own site", workspaceID: intPtr(1), siteID: 1},
+		{name: "other workspace site", workspaceID: intPtr(1), siteID: 2, wantErr: true},
+		{name: "missing site", workspaceID: intPtr(1), siteID: 3, wantErr: true},
+		{name: "unscoped request sees every site", workspaceID: nil, siteID: 2},
+		{name: "unscoped request and missing site", workspaceID: nil, siteID: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site, err := authorizeSite(sites, tt.workspaceID, tt.siteID)
+			if tt.wantErr {
+				// Чужой сайт неотличим от несуществующего
+				if GetDomainErrorCode(err) != ErrorSiteNotFound || site != nil {
+					t.Fatalf("expected %s, got site %+v and error %v", ErrorSiteNotFound, site, err)
+				}
+				return
+			}
+			if err != nil || site.ID != tt.siteID {
+				t.Fatalf("expected site %d, got %+v and error %v", tt.siteID, site, err)
+			}
+		})
+	}
+}
+
+func TestAuthorizeJob(t *testing.T) {
+	_, jobs := newScopeRepositories()
+
+	tests := []struct {
+		name        string
+		workspaceID *int
+		jobID       string
+		wantErr     bool
+	}{
+		{name: "own job", workspaceID: intPtr(1), jobID: "own"},
+		{name: "other workspace job", workspaceID: intPtr(1), jobID: "other", wantErr: true},
+		{name: "job of a deleted site", workspaceID: intPtr(1), jobID: "orphan", wantErr: true},
+		{name: "missing job", workspaceID: intPtr(1), jobID: "missing", wantErr: true},
+		{name: "unscoped request sees every job", workspaceID: nil, jobID: "other"},
+		{name: "unscoped request and missing job", workspaceID: nil, jobID: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := authorizeJob(jobs, tt.workspaceID, tt.jobID)
+			if tt.wantErr {
+				if GetDomainErrorCode(err) != ErrorJobNotFound || job != nil {
+					t.Fatalf("expected %s, got job %+v and error %v", ErrorJobNotFound, job, err)
+				}
+				return
+			}
+			if err != nil || job.ID != tt.jobID {
+				t.Fatalf("expected job %s, got %+v and error %v", tt.jobID, job, err)
+			}
+		})
+	}
+}
+
+func TestWorkspaceOrDefault(t *testing.T) {
+	if got := workspaceOrDefault(nil); got != entities.DefaultWorkspaceID {
+		t.Fatalf("expected default workspace %d, got %d", entities.DefaultWorkspaceID, got)
+	}
+	if got := workspaceOrDefault(intPtr(7)); got != 7 {
+		t.Fatalf("expected workspace 7, got %d", got)
+	}
+}
+
+type scopeAccountRepository struct {
+	repositories.ProviderAccountRepository
+	accounts []*entities.ProviderAccount
+	err      error
+}
+
+func (r *scopeAccountRepository) GetAll(workspaceID *int) ([]*entities.ProviderAccount, error) {
+	var result []*entities.ProviderAccount
+	for _, account := range r.accounts {
+		if workspaceID == nil || account.WorkspaceID == *workspaceID {
+			result = append(result, account)
+		}
+	}
+	return result, r.err
+}
+
+type scopeRateLimiter struct {
+	domainservices.RateLimiter
+	states []domainservices.ProviderLimiterState
+}
+
+func (l *scopeRateLimiter) States() []domainservices.ProviderLimiterState {
+	return l.states
+}
+
+func TestGetLimitsByWorkspace(t *testing.T) {
+	accounts := &scopeAccountRepository{accounts: []*entities.ProviderAccount{
+		{ID: 1, WorkspaceID: 1, Provider: "xmlriver", UserID: "own"},
+		{ID: 2, WorkspaceID: 2, Provider: "xmlriver", UserID: "other"},
+	}}
+	limiter := &scopeRateLimiter{states: []domainservices.ProviderLimiterState{
+		{Provider: "xmlriver", Account: "default"},
+		{Provider: "xmlriver", Account: "own"},
+		{Provider: "xmlriver", Account: "other"},
+		// Тот же user ID у другого провайдера - другой аккаунт
+		{Provider: "serpapi", Account: "own"},
+	}}
+	uc := NewProviderUseCase(nil, limiter, accounts)
+
+	tests := []struct {
+		name        string
+		workspaceID *int
+		want        []string
+	}{
+		{name: "workspace sees own accounts", workspaceID: intPtr(1), want: []string{"xmlriver/own"}},
+		{name: "workspace without used accounts", workspaceID: intPtr(3), want: nil},
+		{name: "system key sees every account", workspaceID: nil, want: []string{"xmlriver/default", "xmlriver/own", "xmlriver/other", "serpapi/own"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states, err := uc.GetLimits(tt.workspaceID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(states) != len(tt.want) {
+				t.Fatalf("expected %v, got %+v", tt.want, states)
+			}
+			for i, state := range states {
+				if got := state.Provider + "/" + state.Account; got != tt.want[i] {
+					t.Fatalf("expected %v, got %+v", tt.want, states)
+				}
+			}
+		})
+	}
+
+	accounts.err = errors.New("connection refused")
+	if _, err := uc.GetLimits(intPtr(1)); GetDomainErrorCode(err) != ErrorAccountFetch {
+		t.Fatalf("expected %s, got %v", ErrorAccountFetch, err)
+	}
+}
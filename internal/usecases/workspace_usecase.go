@@ -0,0 +1,183 @@
+package usecases
+
+import (
+	"strings"
+
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/database"
+)
+
+type WorkspaceUseCase struct {
+	workspaceRepo repositories.WorkspaceRepository
+	siteRepo      repositories.SiteRepository
+	accountRepo   repositories.ProviderAccountRepository
+	keyRepo       repositories.APIKeyRepository
+}
+
+func NewWorkspaceUseCase(
+	workspaceRepo repositories.WorkspaceRepository,
+	siteRepo repositories.SiteRepository,
+	accountRepo repositories.ProviderAccountRepository,
+	keyRepo repositories.APIKeyRepository,
+) *WorkspaceUseCase {
+	return &WorkspaceUseCase{
+		workspaceRepo: workspaceRepo,
+		siteRepo:      siteRepo,
+		accountRepo:   accountRepo,
+		keyRepo:       keyRepo,
+	}
+}
+
+func (uc *WorkspaceUseCase) CreateWorkspace(name string, monthlyBudget *float64) (*entities.Workspace, error) {
+	workspace := &entities.Workspace{
+		Name:          strings.TrimSpace(name),
+		MonthlyBudget: monthlyBudget,
+	}
+	if workspace.Name == "" {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "Workspace name is required",
+		}
+	}
+
+	if err := uc.workspaceRepo.Create(workspace); err != nil {
+		if database.IsDatabaseError(err) && database.GetDatabaseErrorCode(err) == "DUPLICATE_ENTRY" {
+			return nil, &DomainError{
+				Code:    ErrorWorkspaceExists,
+				Message: "Workspace with this name already exists",
+				Err:     err,
+			}
+		}
+		return nil, &DomainError{
+			Code:    ErrorWorkspaceCreation,
+			Message: "Failed to create workspace",
+			Err:     err,
+		}
+	}
+
+	return workspace, nil
+}
+
+func (uc *WorkspaceUseCase) GetWorkspaces() ([]*entities.Workspace, error) {
+	workspaces, err := uc.workspaceRepo.GetAll()
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorWorkspaceFetch,
+			Message: "Failed to fetch workspaces",
+			Err:     err,
+		}
+	}
+
+	return workspaces, nil
+}
+
+func (uc *WorkspaceUseCase) GetWorkspace(id int) (*entities.Workspace, error) {
+	workspace, err := uc.workspaceRepo.GetByID(id)
+	if err != nil {
+		return nil, &DomainError{
+			Code:    ErrorWorkspaceNotFound,
+			Message: "Workspace not found",
+			Err:     err,
+		}
+	}
+
+	return workspace, nil
+}
+
+// UpdateWorkspace меняет название и общий месячный бюджет; nil бюджет снимает ограничение
+func (uc *WorkspaceUseCase) UpdateWorkspace(id int, name string, monthlyBudget *float64) (*entities.Workspace, error) {
+	workspace, err := uc.GetWorkspace(id)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace.Name = strings.TrimSpace(name)
+	workspace.MonthlyBudget = monthlyBudget
+	if workspace.Name == "" {
+		return nil, &DomainError{
+			Code:    ErrorValidation,
+			Message: "Workspace name is required",
+		}
+	}
+
+	if err := uc.workspaceRepo.Update(workspace); err != nil {
+		if database.IsDatabaseError(err) && database.GetDatabaseErrorCode(err) == "DUPLICATE_ENTRY" {
+			return nil, &DomainError{
+				Code:    ErrorWorkspaceExists,
+				Message: "Workspace with this name already exists",
+				Err:     err,
+			}
+		}
+		return nil, &DomainError{
+			Code:    ErrorWorkspaceUpdate,
+			Message: "Failed to update workspace",
+			Err:     err,
+		}
+	}
+
+	return uc.GetWorkspace(id)
+}
+
+// DeleteWorkspace удаляет пустое пространство. Сайты, аккаунты и ключи нужно удалить заранее,
+// чтобы пространство нельзя было удалить вместе с историей позиций по ошибке
+func (uc *WorkspaceUseCase) DeleteWorkspace(id int) error {
+	if _, err := uc.GetWorkspace(id); err != nil {
+		return err
+	}
+
+	if id == entities.DefaultWorkspaceID {
+		return &DomainError{
+			Code:    ErrorValidation,
+			Message: "Default workspace cannot be deleted",
+		}
+	}
+
+	sites, err := uc.siteRepo.GetByWorkspaceID(id)
+	if err != nil {
+		return &DomainError{
+			Code:    ErrorWorkspaceFetch,
+			Message: "Failed to check workspace sites",
+			Err:     err,
+		}
+	}
+	accounts, err := uc.accountRepo.GetAll(&id)
+	if err != nil {
+		return &DomainError{
+			Code:    ErrorWorkspaceFetch,
+			Message: "Failed to check workspace provider accounts",
+			Err:     err,
+		}
+	}
+	keys, err := uc.keyRepo.GetAll(&id)
+	if err != nil {
+		return &DomainError{
+			Code:    ErrorWorkspaceFetch,
+			Message: "Failed to check workspace API keys",
+			Err:     err,
+		}
+	}
+	activeKeys := 0
+	for _, key := range keys {
+		if !key.Revoked() {
+			activeKeys++
+		}
+	}
+
+	if len(sites) > 0 || len(accounts) > 0 || activeKeys > 0 {
+		return &DomainError{
+			Code:    ErrorWorkspaceNotEmpty,
+			Message: "Workspace still has sites, provider accounts or active API keys",
+		}
+	}
+
+	if err := uc.workspaceRepo.Delete(id); err != nil {
+		return &DomainError{
+			Code:    ErrorWorkspaceDeletion,
+			Message: "Failed to delete workspace",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skipf("timezone data is not available: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "every 15 minutes",
+			expr:     "*/15 * * * *",
+			from:     time.Date(2025, 3, 10, 10, 7, 30, 0, time.UTC),
+			expected: time.Date(2025, 3, 10, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:     "strictly after current minute",
+			expr:     "0 6 * * *",
+			from:     time.Date(2025, 3, 10, 6, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 3, 11, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekdays only",
+			expr:     "30 9 * * 1-5",
+			from:     time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC), // пятница
+			expected: time.Date(2025, 3, 17, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "sunday as 7",
+			expr:     "0 0 * * 7",
+			from:     time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month or day of week",
+			expr:     "0 12 1 * 3",
+			from:     time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), // понедельник
+			expected: time.Date(2025, 3, 12, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "descriptor",
+			expr:     "@monthly",
+			from:     time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "timezone",
+			expr:     "0 3 * * *",
+			from:     time.Date(2025, 3, 10, 1, 0, 0, 0, moscow),
+			expected: time.Date(2025, 3, 10, 3, 0, 0, 0, moscow),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.expected) {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestNextImpossibleDate(t *testing.T) {
+	schedule, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next := schedule.Next(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)); !next.IsZero() {
+		t.Errorf("expected zero time, got %s", next)
+	}
+}
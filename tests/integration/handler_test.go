@@ -6,11 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
-	"time"
 
 	"go-seo/internal/delivery/http/dto"
 	"go-seo/internal/delivery/http/handlers"
 	"go-seo/internal/domain/entities"
+	"go-seo/internal/usecases"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -24,7 +24,7 @@ func TestSiteHandler_CreateSite(t *testing.T) {
 	handler := handlers.NewSiteHandler(mockSiteUseCase)
 
 	expectedSite := &entities.Site{ID: 1, Domain: "test.com"}
-	mockSiteUseCase.On("CreateSite", "test.com").Return(expectedSite, nil)
+	mockSiteUseCase.On("CreateSite", (*int)(nil), "test.com").Return(expectedSite, nil)
 
 	reqBody := dto.CreateSiteRequest{
 		Domain: "test.com",
@@ -81,13 +81,14 @@ func TestKeywordHandler_CreateKeyword(t *testing.T) {
 	mockKeywordUseCase := new(MockKeywordUseCase)
 	handler := handlers.NewKeywordHandler(mockKeywordUseCase)
 
-	expectedKeyword := &entities.Keyword{ID: 1, Value: "купить чай", SiteID: 1, GroupID: 1}
-	mockKeywordUseCase.On("CreateKeyword", "купить чай", 1, 1).Return(expectedKeyword, nil)
+	groupID := 1
+	expectedKeyword := &entities.Keyword{ID: 1, Value: "купить чай", SiteID: 1, GroupID: &groupID}
+	mockKeywordUseCase.On("CreateKeyword", (*int)(nil), "купить чай", 1, &groupID).Return(expectedKeyword, nil)
 
 	reqBody := dto.CreateKeywordRequest{
 		Value:   "купить чай",
 		SiteID:  1,
-		GroupID: 1,
+		GroupID: &groupID,
 	}
 	jsonBody, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest("POST", "/api/keywords", bytes.NewBuffer(jsonBody))
@@ -118,10 +119,10 @@ func TestKeywordHandler_GetKeywords(t *testing.T) {
 	handler := handlers.NewKeywordHandler(mockKeywordUseCase)
 
 	keywords := []*entities.Keyword{
-		{ID: 1, Value: "купить чай", SiteID: 1, GroupID: 1},
-		{ID: 2, Value: "купить кофе", SiteID: 1, GroupID: 1},
+		{ID: 1, Value: "купить чай", SiteID: 1},
+		{ID: 2, Value: "купить кофе", SiteID: 1},
 	}
-	mockKeywordUseCase.On("GetKeywordsBySite", 1).Return(keywords, nil)
+	mockKeywordUseCase.On("GetKeywordsBySite", (*int)(nil), 1, (*entities.TagFilter)(nil)).Return(keywords, nil)
 
 	req := httptest.NewRequest("GET", "/api/keywords?site_id=1", nil)
 
@@ -166,58 +167,37 @@ func TestKeywordHandler_GetKeywords_MissingSiteID(t *testing.T) {
 	assert.Contains(t, response.Message, "site_id parameter is required")
 }
 
+// MockSiteUseCase реализует только методы, которые вызывают тесты; остальные паникуют через nil-интерфейс
 type MockSiteUseCase struct {
+	usecases.SiteUseCaseInterface
 	mock.Mock
 }
 
-func (m *MockSiteUseCase) CreateSite(domain string) (*entities.Site, error) {
-	args := m.Called(domain)
-	return args.Get(0).(*entities.Site), args.Error(1)
-}
-
-func (m *MockSiteUseCase) DeleteSite(id int) error {
-	args := m.Called(id)
-	return args.Error(0)
-}
-
-func (m *MockSiteUseCase) GetAllSites() ([]*entities.Site, error) {
-	args := m.Called()
-	return args.Get(0).([]*entities.Site), args.Error(1)
-}
-
-func (m *MockSiteUseCase) GetSitesByIDs(ids []int) ([]*entities.Site, error) {
-	args := m.Called(ids)
-	return args.Get(0).([]*entities.Site), args.Error(1)
-}
-
-func (m *MockSiteUseCase) GetKeywordsCount(siteID int) (int, error) {
-	args := m.Called(siteID)
-	return args.Get(0).(int), args.Error(1)
-}
-
-func (m *MockSiteUseCase) GetLastPositionUpdateDate(siteID int) (*time.Time, error) {
-	args := m.Called(siteID)
+func (m *MockSiteUseCase) CreateSite(workspaceID *int, domain string) (*entities.Site, error) {
+	args := m.Called(workspaceID, domain)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*time.Time), args.Error(1)
+	return args.Get(0).(*entities.Site), args.Error(1)
 }
 
 type MockKeywordUseCase struct {
+	usecases.KeywordUseCaseInterface
 	mock.Mock
 }
 
-func (m *MockKeywordUseCase) CreateKeyword(value string, siteID int, groupID int) (*entities.Keyword, error) {
-	args := m.Called(value, siteID, groupID)
+func (m *MockKeywordUseCase) CreateKeyword(workspaceID *int, value string, siteID int, groupID *int) (*entities.Keyword, error) {
+	args := m.Called(workspaceID, value, siteID, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).(*entities.Keyword), args.Error(1)
 }
 
-func (m *MockKeywordUseCase) DeleteKeyword(id int) error {
-	args := m.Called(id)
-	return args.Error(0)
-}
-
-func (m *MockKeywordUseCase) GetKeywordsBySite(siteID int) ([]*entities.Keyword, error) {
-	args := m.Called(siteID)
+func (m *MockKeywordUseCase) GetKeywordsBySite(workspaceID *int, siteID int, tags *entities.TagFilter) ([]*entities.Keyword, error) {
+	args := m.Called(workspaceID, siteID, tags)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]*entities.Keyword), args.Error(1)
 }
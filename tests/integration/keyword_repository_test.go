@@ -28,7 +28,7 @@ func TestKeywordRepository_Create(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO "keywords"`).
-		WithArgs("купить чай", 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs("купить чай", 1, nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 	mock.ExpectCommit()
 
@@ -45,7 +45,7 @@ func TestKeywordRepository_Create(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestKeywordRepository_GetByValueAndSite(t *testing.T) {
+func TestKeywordRepository_GetByNormalizedValue(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
 	defer db.Close()
@@ -58,17 +58,17 @@ func TestKeywordRepository_GetByValueAndSite(t *testing.T) {
 	repo := repositories.NewKeywordRepository(gormDB)
 
 	now := time.Now()
-	rows := sqlmock.NewRows([]string{"id", "value", "site_id", "created_at", "updated_at"}).
-		AddRow(1, "купить чай", 1, now, now)
+	rows := sqlmock.NewRows([]string{"id", "value", "normalized_value", "site_id", "created_at", "updated_at"}).
+		AddRow(1, "Купить чай", "купить чай", 1, now, now)
 
-	mock.ExpectQuery(`SELECT \* FROM "keywords" WHERE value = \$1 AND site_id = \$2 ORDER BY "keywords"\."id" LIMIT \$3`).
+	mock.ExpectQuery(`SELECT \* FROM "keywords" WHERE normalized_value = \$1 AND site_id = \$2 ORDER BY "keywords"\."id" LIMIT \$3`).
 		WithArgs("купить чай", 1, 1).
 		WillReturnRows(rows)
 
-	keyword, err := repo.GetByValueAndSite("купить чай", 1)
+	keyword, err := repo.GetByNormalizedValue("купить чай", 1)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, keyword.ID)
-	assert.Equal(t, "купить чай", keyword.Value)
+	assert.Equal(t, "Купить чай", keyword.Value)
 	assert.Equal(t, 1, keyword.SiteID)
 
 	err = mock.ExpectationsWereMet()
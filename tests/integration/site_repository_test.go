@@ -28,12 +28,14 @@ func TestSiteRepository_Create(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO "sites"`).
-		WithArgs("test.com", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(entities.DefaultWorkspaceID, "test.com", entities.BudgetActionRefuse, sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 	mock.ExpectCommit()
 
 	site := &entities.Site{
-		Domain: "test.com",
+		WorkspaceID:  entities.DefaultWorkspaceID,
+		Domain:       "test.com",
+		BudgetAction: entities.BudgetActionRefuse,
 	}
 
 	err = repo.Create(site)
@@ -2,16 +2,21 @@ package integration
 
 import (
 	"testing"
-	"time"
 
 	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	"go-seo/internal/infrastructure/services"
 	"go-seo/internal/usecases"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// Моки репозиториев встраивают интерфейс и реализуют только методы, которые вызывают тесты:
+// вызов остальных паникует на nil-интерфейсе и сразу показывает, что тест надо дополнить
+
 type MockSiteRepository struct {
+	repositories.SiteRepository
 	mock.Mock
 }
 
@@ -21,40 +26,21 @@ func (m *MockSiteRepository) Create(site *entities.Site) error {
 	return args.Error(0)
 }
 
-func (m *MockSiteRepository) GetByID(id int) (*entities.Site, error) {
-	args := m.Called(id)
-	return args.Get(0).(*entities.Site), args.Error(1)
-}
-
-func (m *MockSiteRepository) GetByDomain(domain string) (*entities.Site, error) {
-	args := m.Called(domain)
+func (m *MockSiteRepository) GetByIDInWorkspace(id int, workspaceID *int) (*entities.Site, error) {
+	args := m.Called(id, workspaceID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entities.Site), args.Error(1)
 }
 
-func (m *MockSiteRepository) GetAll() ([]*entities.Site, error) {
-	args := m.Called()
-	return args.Get(0).([]*entities.Site), args.Error(1)
-}
-
-func (m *MockSiteRepository) GetByIDs(ids []int) ([]*entities.Site, error) {
-	args := m.Called(ids)
-	return args.Get(0).([]*entities.Site), args.Error(1)
-}
-
-func (m *MockSiteRepository) Update(site *entities.Site) error {
-	args := m.Called(site)
-	return args.Error(0)
-}
-
 func (m *MockSiteRepository) Delete(id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
 type MockKeywordRepository struct {
+	repositories.KeywordRepository
 	mock.Mock
 }
 
@@ -64,26 +50,24 @@ func (m *MockKeywordRepository) Create(keyword *entities.Keyword) error {
 	return args.Error(0)
 }
 
-func (m *MockKeywordRepository) GetByID(id int) (*entities.Keyword, error) {
-	args := m.Called(id)
+func (m *MockKeywordRepository) GetByIDInWorkspace(id int, workspaceID *int) (*entities.Keyword, error) {
+	args := m.Called(id, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).(*entities.Keyword), args.Error(1)
 }
 
-func (m *MockKeywordRepository) GetByValueAndSite(value string, siteID int) (*entities.Keyword, error) {
-	args := m.Called(value, siteID)
+func (m *MockKeywordRepository) GetByNormalizedValue(key string, siteID int) (*entities.Keyword, error) {
+	args := m.Called(key, siteID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entities.Keyword), args.Error(1)
 }
 
-func (m *MockKeywordRepository) GetBySiteID(siteID int) ([]*entities.Keyword, error) {
-	args := m.Called(siteID)
-	return args.Get(0).([]*entities.Keyword), args.Error(1)
-}
-
-func (m *MockKeywordRepository) GetAll() ([]*entities.Keyword, error) {
-	args := m.Called()
+func (m *MockKeywordRepository) GetBySiteIDAndTags(siteID int, tags *entities.TagFilter) ([]*entities.Keyword, error) {
+	args := m.Called(siteID, tags)
 	return args.Get(0).([]*entities.Keyword), args.Error(1)
 }
 
@@ -97,403 +81,80 @@ func (m *MockKeywordRepository) Delete(id int) error {
 	return args.Error(0)
 }
 
-func (m *MockKeywordRepository) GetByIDs(ids []int) ([]*entities.Keyword, error) {
-	args := m.Called(ids)
-	return args.Get(0).([]*entities.Keyword), args.Error(1)
-}
-
-func (m *MockKeywordRepository) CountBySiteID(siteID int) (int, error) {
-	args := m.Called(siteID)
-	return args.Get(0).(int), args.Error(1)
-}
-
-func (m *MockKeywordRepository) DeleteBySiteID(siteID int) error {
-	args := m.Called(siteID)
-	return args.Error(0)
-}
-
-type MockPositionRepository struct {
+type MockTagRepository struct {
+	repositories.TagRepository
 	mock.Mock
 }
 
-func (m *MockPositionRepository) Create(position *entities.Position) error {
-	args := m.Called(position)
-	position.ID = 1
-	return args.Error(0)
-}
-
-func (m *MockPositionRepository) CreateBatch(positions []*entities.Position) error {
-	args := m.Called(positions)
-	for i := range positions {
-		positions[i].ID = i + 1
-	}
-	return args.Error(0)
-}
-
-func (m *MockPositionRepository) GetByID(id int) (*entities.Position, error) {
-	args := m.Called(id)
-	return args.Get(0).(*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetByKeywordAndSite(keywordID, siteID int) ([]*entities.Position, error) {
-	args := m.Called(keywordID, siteID)
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetBySiteID(siteID int) ([]*entities.Position, error) {
-	args := m.Called(siteID)
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetLatestByKeywordAndSite(keywordID, siteID int) (*entities.Position, error) {
-	args := m.Called(keywordID, siteID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetAll() ([]*entities.Position, error) {
-	args := m.Called()
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) Update(position *entities.Position) error {
-	args := m.Called(position)
-	return args.Error(0)
-}
-
-func (m *MockPositionRepository) Delete(id int) error {
+func (m *MockTagRepository) GetByID(id int) (*entities.Tag, error) {
 	args := m.Called(id)
-	return args.Error(0)
-}
-
-func (m *MockPositionRepository) DeleteBySiteID(siteID int) error {
-	args := m.Called(siteID)
-	return args.Error(0)
-}
-
-func (m *MockPositionRepository) DeleteByKeywordID(keywordID int) error {
-	args := m.Called(keywordID)
-	return args.Error(0)
-}
-func (m *MockPositionRepository) GetBySiteIDAndSource(siteID int, source string) ([]*entities.Position, error) {
-	args := m.Called(siteID, source)
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetByKeywordAndSiteAndSource(keywordID, siteID int, source string) ([]*entities.Position, error) {
-	args := m.Called(keywordID, siteID, source)
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-func (m *MockPositionRepository) GetBySiteIDWithDateRange(siteID int, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	args := m.Called(siteID, dateFrom, dateTo)
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetBySiteIDAndSourceWithDateRange(siteID int, source string, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	args := m.Called(siteID, source, dateFrom, dateTo)
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetByKeywordAndSiteWithDateRange(keywordID, siteID int, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	args := m.Called(keywordID, siteID, dateFrom, dateTo)
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetByKeywordAndSiteAndSourceWithDateRange(keywordID, siteID int, source string, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	args := m.Called(keywordID, siteID, source, dateFrom, dateTo)
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetLastUpdateDateBySiteIDExcludingSource(siteID int, excludeSource string) (*time.Time, error) {
-	args := m.Called(siteID, excludeSource)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*time.Time), args.Error(1)
+	return args.Get(0).(*entities.Tag), args.Error(1)
 }
 
-func (m *MockPositionRepository) GetTodayByKeywordAndSiteAndSource(keywordID, siteID int, source string, wordstatQueryType string, filterGroupID *int) (*entities.Position, error) {
-	args := m.Called(keywordID, siteID, source, wordstatQueryType, filterGroupID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) CreateOrUpdateToday(position *entities.Position) error {
-	args := m.Called(position)
-	return args.Error(0)
-}
-
-func (m *MockPositionRepository) GetHistoryBySiteIDWithOnePerDay(siteID int, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	args := m.Called(siteID, dateFrom, dateTo)
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetHistoryBySiteIDAndSourceWithOnePerDay(siteID int, source string, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	args := m.Called(siteID, source, dateFrom, dateTo)
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetHistoryByKeywordAndSiteWithOnePerDay(keywordID, siteID int, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	args := m.Called(keywordID, siteID, dateFrom, dateTo)
-	return args.Get(0).([]*entities.Position), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetHistoryByKeywordAndSiteAndSourceWithOnePerDay(keywordID, siteID int, source string, dateFrom, dateTo *time.Time) ([]*entities.Position, error) {
-	args := m.Called(keywordID, siteID, source, dateFrom, dateTo)
-	return args.Get(0).([]*entities.Position), args.Error(1)
+func (m *MockTagRepository) GetByIDs(ids []int) ([]*entities.Tag, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]*entities.Tag), args.Error(1)
 }
 
-func (m *MockPositionRepository) GetLatestBySiteID(siteID int) ([]*entities.Position, error) {
-	args := m.Called(siteID)
-	return args.Get(0).([]*entities.Position), args.Error(1)
+func (m *MockTagRepository) GetByKeywordIDs(keywordIDs []int) (map[int][]*entities.Tag, error) {
+	args := m.Called(keywordIDs)
+	return args.Get(0).(map[int][]*entities.Tag), args.Error(1)
 }
 
-func (m *MockPositionRepository) GetLatestBySiteIDAndSource(siteID int, source string) ([]*entities.Position, error) {
-	args := m.Called(siteID, source)
-	return args.Get(0).([]*entities.Position), args.Error(1)
+type MockTrackingJobRepository struct {
+	repositories.TrackingJobRepository
+	mock.Mock
 }
 
-func (m *MockPositionRepository) GetPositionStatistics(siteID int, source string, dateFrom, dateTo time.Time, filterGroupID *int) (*entities.PositionStatistics, error) {
-	args := m.Called(siteID, source, dateFrom, dateTo, filterGroupID)
+func (m *MockTrackingJobRepository) GetByIDInWorkspace(id string, workspaceID *int) (*entities.TrackingJob, error) {
+	args := m.Called(id, workspaceID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*entities.PositionStatistics), args.Error(1)
-}
-
-func (m *MockPositionRepository) GetPositionsHistoryPaginated(siteID int, keywordID *int, source *string, dateFrom, dateTo *time.Time, last bool, page, perPage int) ([]*entities.Position, int64, error) {
-	args := m.Called(siteID, keywordID, source, dateFrom, dateTo, last, page, perPage)
-	return args.Get(0).([]*entities.Position), args.Get(1).(int64), args.Error(2)
-}
-
-func (m *MockPositionRepository) GetCombinedPositionsPaginated(siteID int, source *string, includeWordstat bool, wordstatSort bool, dateFrom, dateTo, dateSort *time.Time, sortType string, rankFrom, rankTo *int, page, perPage int) ([]*entities.CombinedPosition, int64, error) {
-	args := m.Called(siteID, source, includeWordstat, wordstatSort, dateFrom, dateTo, dateSort, sortType, rankFrom, rankTo, page, perPage)
-	return args.Get(0).([]*entities.CombinedPosition), args.Get(1).(int64), args.Error(2)
-}
-
-type MockGroupRepository struct {
-	mock.Mock
-}
-
-func (m *MockGroupRepository) Create(group *entities.Group) error {
-	args := m.Called(group)
-	group.ID = 1
-	return args.Error(0)
-}
-
-func (m *MockGroupRepository) GetByID(id int) (*entities.Group, error) {
-	args := m.Called(id)
-	return args.Get(0).(*entities.Group), args.Error(1)
-}
-
-func (m *MockGroupRepository) GetAllBySite(siteID int) ([]*entities.Group, error) {
-	args := m.Called(siteID)
-	return args.Get(0).([]*entities.Group), args.Error(1)
-}
-
-func (m *MockGroupRepository) Update(group *entities.Group) error {
-	args := m.Called(group)
-	return args.Error(0)
-}
-
-func (m *MockGroupRepository) Delete(id int) error {
-	args := m.Called(id)
-	return args.Error(0)
-}
-
-func (m *MockGroupRepository) DeleteBySiteID(siteID int) error {
-	args := m.Called(siteID)
-	return args.Error(0)
-}
-
-type MockTrackingJobRepository struct {
-	mock.Mock
-}
-
-func (m *MockTrackingJobRepository) Create(job *entities.TrackingJob) error {
-	args := m.Called(job)
-	return args.Error(0)
-}
-
-func (m *MockTrackingJobRepository) GetByID(id string) (*entities.TrackingJob, error) {
-	args := m.Called(id)
 	return args.Get(0).(*entities.TrackingJob), args.Error(1)
 }
 
-func (m *MockTrackingJobRepository) Update(job *entities.TrackingJob) error {
-	args := m.Called(job)
-	return args.Error(0)
-}
-
-func (m *MockTrackingJobRepository) UpdateStatus(id string, status entities.TrackingTaskStatus) error {
-	args := m.Called(id, status)
-	return args.Error(0)
-}
-
-func (m *MockTrackingJobRepository) UpdateProgress(id string, completed, failed int) error {
-	args := m.Called(id, completed, failed)
-	return args.Error(0)
-}
-
-func (m *MockTrackingJobRepository) GetBySiteID(siteID int) ([]*entities.TrackingJob, error) {
-	args := m.Called(siteID)
-	return args.Get(0).([]*entities.TrackingJob), args.Error(1)
-}
-
-func (m *MockTrackingJobRepository) GetByStatus(status entities.TrackingTaskStatus) ([]*entities.TrackingJob, error) {
-	args := m.Called(status)
-	return args.Get(0).([]*entities.TrackingJob), args.Error(1)
-}
-
-func (m *MockTrackingJobRepository) GetJobsWithPagination(page, perPage int, siteID *int, status *entities.TrackingTaskStatus) ([]*entities.TrackingJob, int64, error) {
-	args := m.Called(page, perPage, siteID, status)
+func (m *MockTrackingJobRepository) GetJobsWithPagination(page, perPage int, workspaceID, siteID *int, status *entities.TrackingTaskStatus) ([]*entities.TrackingJob, int64, error) {
+	args := m.Called(page, perPage, workspaceID, siteID, status)
 	return args.Get(0).([]*entities.TrackingJob), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockTrackingJobRepository) Delete(id string) error {
-	args := m.Called(id)
-	return args.Error(0)
-}
-
-func (m *MockTrackingJobRepository) DeleteBySiteID(siteID int) error {
-	args := m.Called(siteID)
-	return args.Error(0)
-}
-
-type MockTrackingTaskRepository struct {
-	mock.Mock
-}
-
-func (m *MockTrackingTaskRepository) Create(task *entities.TrackingTask) error {
-	args := m.Called(task)
-	return args.Error(0)
-}
-
-func (m *MockTrackingTaskRepository) GetByID(id string) (*entities.TrackingTask, error) {
-	args := m.Called(id)
-	return args.Get(0).(*entities.TrackingTask), args.Error(1)
-}
-
-func (m *MockTrackingTaskRepository) GetByJobID(jobID string) ([]*entities.TrackingTask, error) {
-	args := m.Called(jobID)
-	return args.Get(0).([]*entities.TrackingTask), args.Error(1)
-}
-
-func (m *MockTrackingTaskRepository) Update(task *entities.TrackingTask) error {
-	args := m.Called(task)
-	return args.Error(0)
-}
-
-func (m *MockTrackingTaskRepository) UpdateStatus(id string, status entities.TrackingTaskStatus) error {
-	args := m.Called(id, status)
-	return args.Error(0)
-}
-
-func (m *MockTrackingTaskRepository) UpdateRetryCount(id string, retryCount int) error {
-	args := m.Called(id, retryCount)
-	return args.Error(0)
-}
-
-func (m *MockTrackingTaskRepository) GetPendingTasks(limit int) ([]*entities.TrackingTask, error) {
-	args := m.Called(limit)
-	return args.Get(0).([]*entities.TrackingTask), args.Error(1)
-}
-
-func (m *MockTrackingTaskRepository) GetFailedTasks(limit int) ([]*entities.TrackingTask, error) {
-	args := m.Called(limit)
-	return args.Get(0).([]*entities.TrackingTask), args.Error(1)
-}
-
-func (m *MockTrackingTaskRepository) Delete(id string) error {
-	args := m.Called(id)
-	return args.Error(0)
-}
-
-func (m *MockTrackingTaskRepository) DeleteByJobID(jobID string) error {
-	args := m.Called(jobID)
-	return args.Error(0)
-}
-
-func (m *MockTrackingTaskRepository) DeleteBySiteID(siteID int) error {
-	args := m.Called(siteID)
-	return args.Error(0)
-}
-
-type MockTrackingResultRepository struct {
-	mock.Mock
-}
-
-func (m *MockTrackingResultRepository) Create(result *entities.TrackingResult) error {
-	args := m.Called(result)
-	return args.Error(0)
-}
-
-func (m *MockTrackingResultRepository) GetByTaskID(taskID string) (*entities.TrackingResult, error) {
-	args := m.Called(taskID)
-	return args.Get(0).(*entities.TrackingResult), args.Error(1)
-}
-
-func (m *MockTrackingResultRepository) GetByJobID(jobID string) ([]*entities.TrackingResult, error) {
-	args := m.Called(jobID)
-	return args.Get(0).([]*entities.TrackingResult), args.Error(1)
-}
-
-func (m *MockTrackingResultRepository) GetBySiteID(siteID int) ([]*entities.TrackingResult, error) {
-	args := m.Called(siteID)
-	return args.Get(0).([]*entities.TrackingResult), args.Error(1)
-}
-
-func (m *MockTrackingResultRepository) Delete(id string) error {
-	args := m.Called(id)
-	return args.Error(0)
-}
-
-func (m *MockTrackingResultRepository) DeleteByJobID(jobID string) error {
-	args := m.Called(jobID)
-	return args.Error(0)
-}
-
-func (m *MockTrackingResultRepository) DeleteBySiteID(siteID int) error {
-	args := m.Called(siteID)
-	return args.Error(0)
+func newKeywordUseCase(keywordRepo repositories.KeywordRepository, siteRepo repositories.SiteRepository, tagRepo repositories.TagRepository) *usecases.KeywordUseCase {
+	return usecases.NewKeywordUseCase(keywordRepo, siteRepo, nil, nil, nil, tagRepo, nil, services.NewKeywordNormalizer())
 }
 
 func TestSiteUseCase_CreateSite(t *testing.T) {
 	mockSiteRepo := new(MockSiteRepository)
-	mockPositionRepo := new(MockPositionRepository)
-	mockKeywordRepo := new(MockKeywordRepository)
-	mockGroupRepo := new(MockGroupRepository)
-	mockJobRepo := new(MockTrackingJobRepository)
-	mockTaskRepo := new(MockTrackingTaskRepository)
-	mockResultRepo := new(MockTrackingResultRepository)
 
-	useCase := usecases.NewSiteUseCase(mockSiteRepo, mockPositionRepo, mockKeywordRepo, mockGroupRepo, mockJobRepo, mockTaskRepo, mockResultRepo)
+	useCase := usecases.NewSiteUseCase(mockSiteRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	mockSiteRepo.On("Create", mock.AnythingOfType("*entities.Site")).Return(nil)
 
-	site, err := useCase.CreateSite("test.com")
+	site, err := useCase.CreateSite(nil, "test.com")
 
 	assert.NoError(t, err)
 	assert.Equal(t, "test.com", site.Domain)
 	assert.Equal(t, 1, site.ID)
+	assert.Equal(t, entities.DefaultWorkspaceID, site.WorkspaceID)
 
 	mockSiteRepo.AssertExpectations(t)
 }
 
 func TestKeywordUseCase_CreateKeyword(t *testing.T) {
 	mockKeywordRepo := new(MockKeywordRepository)
-	mockPositionRepo := new(MockPositionRepository)
+	mockSiteRepo := new(MockSiteRepository)
 
-	useCase := usecases.NewKeywordUseCase(mockKeywordRepo, mockPositionRepo)
+	useCase := newKeywordUseCase(mockKeywordRepo, mockSiteRepo, nil)
 
-	mockKeywordRepo.On("GetByValueAndSite", "купить чай", 1).Return(nil, assert.AnError)
+	mockSiteRepo.On("GetByIDInWorkspace", 1, (*int)(nil)).Return(&entities.Site{ID: 1, WorkspaceID: entities.DefaultWorkspaceID}, nil)
+	mockKeywordRepo.On("GetByNormalizedValue", "купить чай", 1).Return(nil, assert.AnError)
 	mockKeywordRepo.On("Create", mock.AnythingOfType("*entities.Keyword")).Return(nil)
 
-	keyword, err := useCase.CreateKeyword("купить чай", 1, 1)
+	groupID := 1
+	keyword, err := useCase.CreateKeyword(nil, "купить чай", 1, &groupID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "купить чай", keyword.Value)
@@ -505,15 +166,16 @@ func TestKeywordUseCase_CreateKeyword(t *testing.T) {
 
 func TestKeywordUseCase_CreateKeyword_AlreadyExists(t *testing.T) {
 	mockKeywordRepo := new(MockKeywordRepository)
-	mockPositionRepo := new(MockPositionRepository)
+	mockSiteRepo := new(MockSiteRepository)
 
-	useCase := usecases.NewKeywordUseCase(mockKeywordRepo, mockPositionRepo)
+	useCase := newKeywordUseCase(mockKeywordRepo, mockSiteRepo, nil)
 
 	// Настраиваем мок - ключевое слово уже существует
-	existingKeyword := &entities.Keyword{ID: 1, Value: "купить чай", SiteID: 1, GroupID: 1}
-	mockKeywordRepo.On("GetByValueAndSite", "купить чай", 1).Return(existingKeyword, nil)
+	existingKeyword := &entities.Keyword{ID: 1, Value: "купить чай", SiteID: 1}
+	mockSiteRepo.On("GetByIDInWorkspace", 1, (*int)(nil)).Return(&entities.Site{ID: 1, WorkspaceID: entities.DefaultWorkspaceID}, nil)
+	mockKeywordRepo.On("GetByNormalizedValue", "купить чай", 1).Return(existingKeyword, nil)
 
-	keyword, err := useCase.CreateKeyword("купить чай", 1, 1)
+	keyword, err := useCase.CreateKeyword(nil, "купить чай", 1, nil)
 
 	assert.Error(t, err)
 	assert.Nil(t, keyword)
@@ -524,17 +186,20 @@ func TestKeywordUseCase_CreateKeyword_AlreadyExists(t *testing.T) {
 
 func TestKeywordUseCase_GetKeywordsBySite(t *testing.T) {
 	mockKeywordRepo := new(MockKeywordRepository)
-	mockPositionRepo := new(MockPositionRepository)
+	mockSiteRepo := new(MockSiteRepository)
+	mockTagRepo := new(MockTagRepository)
 
-	useCase := usecases.NewKeywordUseCase(mockKeywordRepo, mockPositionRepo)
+	useCase := newKeywordUseCase(mockKeywordRepo, mockSiteRepo, mockTagRepo)
 
 	keywords := []*entities.Keyword{
-		{ID: 1, Value: "купить чай", SiteID: 1, GroupID: 1},
-		{ID: 2, Value: "купить кофе", SiteID: 1, GroupID: 1},
+		{ID: 1, Value: "купить чай", SiteID: 1},
+		{ID: 2, Value: "купить кофе", SiteID: 1},
 	}
-	mockKeywordRepo.On("GetBySiteID", 1).Return(keywords, nil)
+	mockSiteRepo.On("GetByIDInWorkspace", 1, (*int)(nil)).Return(&entities.Site{ID: 1, WorkspaceID: entities.DefaultWorkspaceID}, nil)
+	mockKeywordRepo.On("GetBySiteIDAndTags", 1, (*entities.TagFilter)(nil)).Return(keywords, nil)
+	mockTagRepo.On("GetByKeywordIDs", []int{1, 2}).Return(map[int][]*entities.Tag{}, nil)
 
-	result, err := useCase.GetKeywordsBySite(1)
+	result, err := useCase.GetKeywordsBySite(nil, 1, nil)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
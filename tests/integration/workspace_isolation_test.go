@@ -0,0 +1,467 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-seo/internal/delivery/http/dto"
+	"go-seo/internal/delivery/http/handlers"
+	"go-seo/internal/delivery/http/middleware"
+	"go-seo/internal/domain/entities"
+	"go-seo/internal/domain/repositories"
+	domainservices "go-seo/internal/domain/services"
+	"go-seo/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockAPIKeyRepository struct {
+	repositories.APIKeyRepository
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) GetByHash(hash string) (*entities.APIKey, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByID(id int) (*entities.APIKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.APIKey), args.Error(1)
+}
+
+type MockProviderAccountRepository struct {
+	repositories.ProviderAccountRepository
+	mock.Mock
+}
+
+func (m *MockProviderAccountRepository) GetAll(workspaceID *int) ([]*entities.ProviderAccount, error) {
+	args := m.Called(workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.ProviderAccount), args.Error(1)
+}
+
+func (m *MockProviderAccountRepository) GetByID(id int) (*entities.ProviderAccount, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.ProviderAccount), args.Error(1)
+}
+
+type MockGroupRepository struct {
+	repositories.GroupRepository
+	mock.Mock
+}
+
+func (m *MockGroupRepository) GetByID(id int) (*entities.Group, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Group), args.Error(1)
+}
+
+type MockTrackingScheduleRepository struct {
+	repositories.TrackingScheduleRepository
+	mock.Mock
+}
+
+func (m *MockTrackingScheduleRepository) GetByID(id int) (*entities.TrackingSchedule, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.TrackingSchedule), args.Error(1)
+}
+
+func (m *MockTrackingScheduleRepository) GetAll(workspaceID, siteID *int) ([]*entities.TrackingSchedule, error) {
+	args := m.Called(workspaceID, siteID)
+	return args.Get(0).([]*entities.TrackingSchedule), args.Error(1)
+}
+
+type MockSerpSnapshotRepository struct {
+	repositories.SerpSnapshotRepository
+	mock.Mock
+}
+
+func (m *MockSerpSnapshotRepository) GetByPositionID(positionID int, workspaceID *int) (*entities.SerpSnapshot, error) {
+	args := m.Called(positionID, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.SerpSnapshot), args.Error(1)
+}
+
+type MockUsageRepository struct {
+	repositories.UsageRepository
+	mock.Mock
+}
+
+func (m *MockUsageRepository) GetDailySummary(workspaceID, siteID *int, source *string, dateFrom, dateTo time.Time) ([]*entities.UsageSummary, error) {
+	args := m.Called(workspaceID, siteID, source, dateFrom, dateTo)
+	return args.Get(0).([]*entities.UsageSummary), args.Error(1)
+}
+
+// stubRateLimiter отдает заранее заданное состояние лимитов
+type stubRateLimiter struct {
+	domainservices.RateLimiter
+	states []domainservices.ProviderLimiterState
+}
+
+func (l *stubRateLimiter) States() []domainservices.ProviderLimiterState {
+	return l.states
+}
+
+// isolationRepositories - моки, на которых собран роутер изоляции
+type isolationRepositories struct {
+	sites     *MockSiteRepository
+	keywords  *MockKeywordRepository
+	jobs      *MockTrackingJobRepository
+	schedules *MockTrackingScheduleRepository
+	usage     *MockUsageRepository
+}
+
+// ownWorkspace совпадает с областью запроса ключа пространства 1
+var ownWorkspace = mock.MatchedBy(func(id *int) bool { return id != nil && *id == 1 })
+
+// newIsolationRouter собирает маршруты как SetupRoutes с включенной авторизацией. Любой ключ запроса
+// принадлежит пространству 1; сайт 1 - в пространстве 1, сайт 2 - в пространстве 2.
+// Репозитории сайтов, слов, джобов и снимков выдачи получают пространство запроса и, как запрос к базе
+// с условием workspace_id, находят только данные пространства 1
+func newIsolationRouter(t *testing.T) (*gin.Engine, *isolationRepositories) {
+	gin.SetMode(gin.TestMode)
+
+	workspaceID, otherWorkspaceID := 1, 2
+	now := time.Now()
+	keyRepo := new(MockAPIKeyRepository)
+	keyRepo.On("GetByHash", mock.Anything).Return(&entities.APIKey{
+		ID:          1,
+		WorkspaceID: &workspaceID,
+		Name:        "tenant",
+		Scopes:      []string{entities.ScopeAdmin},
+		LastUsedAt:  &now,
+	}, nil)
+	keyRepo.On("GetByID", 2).Return(&entities.APIKey{ID: 2, WorkspaceID: &otherWorkspaceID, Name: "other"}, nil)
+
+	siteRepo := new(MockSiteRepository)
+	siteRepo.On("GetByIDInWorkspace", 1, ownWorkspace).Return(&entities.Site{ID: 1, WorkspaceID: 1, Domain: "own.com"}, nil)
+	siteRepo.On("GetByIDInWorkspace", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	keywordRepo := new(MockKeywordRepository)
+	keywordRepo.On("GetByIDInWorkspace", 10, ownWorkspace).Return(&entities.Keyword{ID: 10, Value: "own", SiteID: 1}, nil)
+	keywordRepo.On("GetByIDInWorkspace", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	jobRepo := new(MockTrackingJobRepository)
+	jobRepo.On("GetByIDInWorkspace", "own-job", ownWorkspace).Return(&entities.TrackingJob{ID: "own-job", SiteID: 1}, nil)
+	jobRepo.On("GetByIDInWorkspace", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	snapshotRepo := new(MockSerpSnapshotRepository)
+	snapshotRepo.On("GetByPositionID", mock.Anything, ownWorkspace).Return(nil, nil)
+
+	// Группы, метки, расписания и аккаунты читаются по ID, а доступ к ним проверяется по сайту или пространству
+	groupRepo := new(MockGroupRepository)
+	groupRepo.On("GetByID", 20).Return(&entities.Group{ID: 20, Name: "other", SiteID: 2}, nil)
+
+	tagRepo := new(MockTagRepository)
+	tagRepo.On("GetByID", 20).Return(&entities.Tag{ID: 20, Name: "other", SiteID: 2}, nil)
+	tagRepo.On("GetByIDs", []int{20}).Return([]*entities.Tag{{ID: 20, Name: "other", SiteID: 2}}, nil)
+
+	scheduleRepo := new(MockTrackingScheduleRepository)
+	scheduleRepo.On("GetByID", 20).Return(&entities.TrackingSchedule{ID: 20, SiteID: 2, Source: entities.GoogleSearch}, nil)
+
+	// Лимитер общий для инстанса: в нем счетчики аккаунта по умолчанию и аккаунтов обоих пространств
+	accountRepo := new(MockProviderAccountRepository)
+	accountRepo.On("GetAll", ownWorkspace).Return([]*entities.ProviderAccount{
+		{ID: 1, WorkspaceID: 1, Provider: "xmlriver", UserID: "own-user"},
+	}, nil)
+	accountRepo.On("GetByID", 2).Return(&entities.ProviderAccount{ID: 2, WorkspaceID: 2, Provider: "xmlriver", UserID: "other-user"}, nil)
+	limiter := &stubRateLimiter{states: []domainservices.ProviderLimiterState{
+		{Provider: "xmlriver", Account: "default-user", UsedToday: 5},
+		{Provider: "xmlriver", Account: "own-user", UsedToday: 3},
+		{Provider: "xmlriver", Account: "other-user", UsedToday: 7},
+	}}
+
+	usageRepo := new(MockUsageRepository)
+
+	accounts := usecases.NewProviderAccountUseCase(accountRepo, nil, nil)
+	tracking := usecases.NewAsyncPositionTrackingUseCase(siteRepo, keywordRepo, nil, jobRepo, nil, nil, snapshotRepo, nil, usageRepo, nil,
+		accounts, nil, nil, nil, nil, nil, nil, 1, 1)
+
+	siteHandler := handlers.NewSiteHandler(usecases.NewSiteUseCase(siteRepo, nil, keywordRepo, nil, jobRepo, nil, nil, nil, nil, nil))
+	keywordHandler := handlers.NewKeywordHandler(newKeywordUseCase(keywordRepo, siteRepo, nil))
+	groupHandler := handlers.NewGroupHandler(usecases.NewGroupUseCase(groupRepo, siteRepo))
+	tagHandler := handlers.NewTagHandler(usecases.NewTagUseCase(tagRepo, keywordRepo, siteRepo))
+	competitorHandler := handlers.NewCompetitorHandler(usecases.NewCompetitorUseCase(nil, siteRepo, nil))
+	landingPageHandler := handlers.NewLandingPageHandler(usecases.NewLandingPageUseCase(nil, siteRepo))
+	suggestionHandler := handlers.NewKeywordSuggestionHandler(usecases.NewKeywordSuggestionUseCase(nil, keywordRepo, groupRepo, siteRepo, usageRepo, nil, accounts, nil, nil))
+	clusterHandler := handlers.NewKeywordClusterHandler(usecases.NewKeywordClusterUseCase(nil, keywordRepo, siteRepo))
+	positionHandler := handlers.NewPositionHandler(usecases.NewPositionTrackingUseCase(siteRepo, keywordRepo, nil, snapshotRepo, nil, usageRepo, accounts, nil, nil, 1), tracking)
+	visibilityHandler := handlers.NewVisibilityHandler(usecases.NewVisibilityUseCase(nil, siteRepo))
+	movementHandler := handlers.NewMovementHandler(usecases.NewMovementUseCase(nil, siteRepo))
+	serpSnapshotHandler := handlers.NewSerpSnapshotHandler(usecases.NewSerpSnapshotUseCase(snapshotRepo))
+	trackingJobHandler := handlers.NewTrackingJobHandler(usecases.NewTrackingJobUseCase(jobRepo, nil, usageRepo), tracking)
+	scheduleHandler := handlers.NewTrackingScheduleHandler(usecases.NewTrackingScheduleUseCase(scheduleRepo, siteRepo, jobRepo, tracking))
+	usageHandler := handlers.NewUsageHandler(usecases.NewUsageUseCase(usageRepo))
+	providerHandler := handlers.NewProviderHandler(usecases.NewProviderUseCase(nil, limiter, accountRepo))
+	providerAccountHandler := handlers.NewProviderAccountHandler(accounts)
+	apiKeyHandler := handlers.NewAPIKeyHandler(usecases.NewAPIKeyUseCase(keyRepo))
+
+	r := gin.New()
+	api := r.Group("/api")
+	api.Use(middleware.APIKeyAuth(usecases.NewAPIKeyUseCase(keyRepo)))
+	api.Use(middleware.WorkspaceScope(usecases.NewWorkspaceUseCase(nil, nil, nil, nil)))
+	api.DELETE("/sites/:id", siteHandler.DeleteSite)
+	api.PUT("/sites/:id/budget", siteHandler.SetSiteBudget)
+	api.GET("/sites/:id/competitors", competitorHandler.GetCompetitors)
+	api.POST("/sites/:id/competitors", competitorHandler.CreateCompetitor)
+	api.PUT("/sites/:id/competitors/:competitor_id", competitorHandler.UpdateCompetitor)
+	api.DELETE("/sites/:id/competitors/:competitor_id", competitorHandler.DeleteCompetitor)
+	api.GET("/sites/:id/url-changes", landingPageHandler.GetURLChanges)
+	api.GET("/sites/:id/cannibalization", landingPageHandler.GetCannibalization)
+	api.POST("/sites/:id/keyword-suggestions", suggestionHandler.CollectSuggestions)
+	api.GET("/sites/:id/keyword-suggestions", suggestionHandler.GetSuggestions)
+	api.POST("/sites/:id/keyword-suggestions/accept", suggestionHandler.AcceptSuggestions)
+	api.POST("/sites/:id/keyword-suggestions/dismiss", suggestionHandler.DismissSuggestions)
+	api.POST("/sites/:id/clusters", clusterHandler.ClusterKeywords)
+	api.GET("/sites/:id/clusters", clusterHandler.GetClusters)
+	api.POST("/sites/:id/clusters/apply", clusterHandler.ApplyClusters)
+	api.POST("/groups", groupHandler.CreateGroup)
+	api.GET("/groups", groupHandler.GetGroups)
+	api.PUT("/groups/:id", groupHandler.UpdateGroup)
+	api.DELETE("/groups/:id", groupHandler.DeleteGroup)
+	api.POST("/tags", tagHandler.CreateTag)
+	api.GET("/tags", tagHandler.GetTags)
+	api.POST("/tags/assign", tagHandler.AssignTags)
+	api.POST("/tags/unassign", tagHandler.UnassignTags)
+	api.PUT("/tags/:id", tagHandler.UpdateTag)
+	api.DELETE("/tags/:id", tagHandler.DeleteTag)
+	api.GET("/keywords", keywordHandler.GetKeywords)
+	api.POST("/keywords", keywordHandler.CreateKeyword)
+	api.POST("/keywords/merge", keywordHandler.MergeKeywords)
+	api.GET("/keywords/duplicates", keywordHandler.GetNearDuplicates)
+	api.PUT("/keywords/:id", keywordHandler.UpdateKeyword)
+	api.DELETE("/keywords/:id", keywordHandler.DeleteKeyword)
+	api.POST("/positions/track-google", positionHandler.TrackGooglePositions)
+	api.POST("/positions/track-yandex", positionHandler.TrackYandexPositions)
+	api.POST("/positions/track-wordstat", positionHandler.TrackWordstatPositions)
+	api.GET("/positions/history", positionHandler.GetPositionsHistory)
+	api.POST("/positions/statistics", positionHandler.GetPositionStatistics)
+	api.GET("/positions/combined", positionHandler.GetCombinedPositions)
+	api.GET("/positions/visibility", visibilityHandler.GetVisibility)
+	api.GET("/positions/movement", movementHandler.GetMovement)
+	api.GET("/positions/:id/serp", serpSnapshotHandler.GetSerpSnapshot)
+	api.GET("/tracking-jobs", trackingJobHandler.GetTrackingJobs)
+	api.GET("/tracking-jobs/:id", trackingJobHandler.GetTrackingJob)
+	api.POST("/tracking-jobs/:id/cancel", trackingJobHandler.CancelTrackingJob)
+	api.POST("/tracking-jobs/:id/pause", trackingJobHandler.PauseTrackingJob)
+	api.POST("/tracking-jobs/:id/resume", trackingJobHandler.ResumeTrackingJob)
+	api.POST("/tracking-jobs/:id/retry-failed", trackingJobHandler.RetryFailedTrackingJob)
+	api.GET("/tracking-schedules", scheduleHandler.GetSchedules)
+	api.POST("/tracking-schedules", scheduleHandler.CreateSchedule)
+	api.GET("/tracking-schedules/:id", scheduleHandler.GetSchedule)
+	api.PUT("/tracking-schedules/:id", scheduleHandler.UpdateSchedule)
+	api.DELETE("/tracking-schedules/:id", scheduleHandler.DeleteSchedule)
+	api.POST("/tracking-schedules/:id/pause", scheduleHandler.PauseSchedule)
+	api.POST("/tracking-schedules/:id/resume", scheduleHandler.ResumeSchedule)
+	api.GET("/provider-accounts/:id", providerAccountHandler.GetProviderAccount)
+	api.PUT("/provider-accounts/:id", providerAccountHandler.UpdateProviderAccount)
+	api.DELETE("/provider-accounts/:id", providerAccountHandler.DeleteProviderAccount)
+	api.GET("/providers/limits", providerHandler.GetProviderLimits)
+	api.GET("/usage", usageHandler.GetUsage)
+	api.POST("/api-keys/:id/revoke", apiKeyHandler.RevokeAPIKey)
+
+	return r, &isolationRepositories{sites: siteRepo, keywords: keywordRepo, jobs: jobRepo, schedules: scheduleRepo, usage: usageRepo}
+}
+
+func isolationRequest(router *gin.Engine, method, path string, body interface{}, headers map[string]string) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "gs_tenant")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// Каждый обработчик, принимающий ID сайта, слова, группы, метки, позиции, джоба, расписания, аккаунта
+// или ключа, отвечает на ID пространства 2 так же, как на несуществующий
+func TestWorkspaceIsolation_OtherWorkspaceIsNotFound(t *testing.T) {
+	router, repos := newIsolationRouter(t)
+	budget := 100.0
+	schedule := gin.H{"site_id": 2, "source": "google", "cron_expr": "0 6 * * *"}
+	account := gin.H{"name": "other", "provider": "xmlriver", "user_id": "other-user"}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		code   string
+	}{
+		{name: "delete site", method: http.MethodDelete, path: "/api/sites/2", code: usecases.ErrorSiteNotFound},
+		{name: "set site budget", method: http.MethodPut, path: "/api/sites/2/budget", body: dto.SiteBudgetRequest{MonthlyBudget: &budget}, code: usecases.ErrorSiteNotFound},
+
+		{name: "list competitors", method: http.MethodGet, path: "/api/sites/2/competitors", code: usecases.ErrorSiteNotFound},
+		{name: "create competitor", method: http.MethodPost, path: "/api/sites/2/competitors", body: gin.H{"domain": "rival.com"}, code: usecases.ErrorSiteNotFound},
+		{name: "update competitor", method: http.MethodPut, path: "/api/sites/2/competitors/1", body: gin.H{"domain": "rival.com"}, code: usecases.ErrorSiteNotFound},
+		{name: "delete competitor", method: http.MethodDelete, path: "/api/sites/2/competitors/1", code: usecases.ErrorSiteNotFound},
+
+		{name: "url changes", method: http.MethodGet, path: "/api/sites/2/url-changes", code: usecases.ErrorSiteNotFound},
+		{name: "cannibalization", method: http.MethodGet, path: "/api/sites/2/cannibalization", code: usecases.ErrorSiteNotFound},
+
+		{name: "collect suggestions", method: http.MethodPost, path: "/api/sites/2/keyword-suggestions", body: gin.H{"seeds": []string{"чай"}}, code: usecases.ErrorSiteNotFound},
+		{name: "list suggestions", method: http.MethodGet, path: "/api/sites/2/keyword-suggestions", code: usecases.ErrorSiteNotFound},
+		{name: "accept suggestions", method: http.MethodPost, path: "/api/sites/2/keyword-suggestions/accept", body: gin.H{"ids": []int{1}}, code: usecases.ErrorSiteNotFound},
+		{name: "dismiss suggestions", method: http.MethodPost, path: "/api/sites/2/keyword-suggestions/dismiss", body: gin.H{"ids": []int{1}}, code: usecases.ErrorSiteNotFound},
+
+		{name: "cluster keywords", method: http.MethodPost, path: "/api/sites/2/clusters", body: gin.H{"source": "google"}, code: usecases.ErrorSiteNotFound},
+		{name: "list clusters", method: http.MethodGet, path: "/api/sites/2/clusters", code: usecases.ErrorSiteNotFound},
+		{name: "apply clusters", method: http.MethodPost, path: "/api/sites/2/clusters/apply", code: usecases.ErrorSiteNotFound},
+
+		{name: "create group", method: http.MethodPost, path: "/api/groups", body: gin.H{"name": "чай", "site_id": 2}, code: usecases.ErrorSiteNotFound},
+		{name: "list groups", method: http.MethodGet, path: "/api/groups?site_id=2", code: usecases.ErrorSiteNotFound},
+		{name: "update group", method: http.MethodPut, path: "/api/groups/20", body: gin.H{"name": "чай"}, code: usecases.ErrorGroupNotFound},
+		{name: "delete group", method: http.MethodDelete, path: "/api/groups/20", code: usecases.ErrorGroupNotFound},
+
+		{name: "create tag", method: http.MethodPost, path: "/api/tags", body: gin.H{"name": "чай", "site_id": 2}, code: usecases.ErrorSiteNotFound},
+		{name: "list tags", method: http.MethodGet, path: "/api/tags?site_id=2", code: usecases.ErrorSiteNotFound},
+		{name: "assign tags", method: http.MethodPost, path: "/api/tags/assign", body: gin.H{"keyword_ids": []int{20}, "tag_ids": []int{20}}, code: usecases.ErrorTagNotFound},
+		{name: "unassign tags", method: http.MethodPost, path: "/api/tags/unassign", body: gin.H{"keyword_ids": []int{20}, "tag_ids": []int{20}}, code: usecases.ErrorTagNotFound},
+		{name: "update tag", method: http.MethodPut, path: "/api/tags/20", body: gin.H{"name": "чай"}, code: usecases.ErrorTagNotFound},
+		{name: "delete tag", method: http.MethodDelete, path: "/api/tags/20", code: usecases.ErrorTagNotFound},
+
+		{name: "list site keywords", method: http.MethodGet, path: "/api/keywords?site_id=2", code: usecases.ErrorSiteNotFound},
+		{name: "create keyword in site", method: http.MethodPost, path: "/api/keywords", body: dto.CreateKeywordRequest{Value: "чай", SiteID: 2}, code: usecases.ErrorSiteNotFound},
+		{name: "update keyword", method: http.MethodPut, path: "/api/keywords/20", body: dto.UpdateKeywordRequest{}, code: usecases.ErrorKeywordNotFound},
+		{name: "delete keyword", method: http.MethodDelete, path: "/api/keywords/20", code: usecases.ErrorKeywordNotFound},
+		{name: "merge into keyword", method: http.MethodPost, path: "/api/keywords/merge", body: gin.H{"target_id": 20, "source_ids": []int{10}}, code: usecases.ErrorKeywordNotFound},
+		{name: "near duplicates", method: http.MethodGet, path: "/api/keywords/duplicates?site_id=2", code: usecases.ErrorSiteNotFound},
+
+		{name: "track google", method: http.MethodPost, path: "/api/positions/track-google", body: gin.H{"site_id": 2}, code: usecases.ErrorSiteNotFound},
+		{name: "track yandex", method: http.MethodPost, path: "/api/positions/track-yandex", body: gin.H{"site_id": 2}, code: usecases.ErrorSiteNotFound},
+		{name: "track wordstat", method: http.MethodPost, path: "/api/positions/track-wordstat", body: gin.H{"site_id": 2}, code: usecases.ErrorSiteNotFound},
+		{name: "positions history", method: http.MethodGet, path: "/api/positions/history?site_id=2", code: usecases.ErrorSiteNotFound},
+		{name: "position statistics", method: http.MethodPost, path: "/api/positions/statistics", body: gin.H{"site_id": 2, "date_from": "2026-09-01", "date_to": "2026-09-30", "source": "google"}, code: usecases.ErrorSiteNotFound},
+		{name: "combined positions", method: http.MethodGet, path: "/api/positions/combined?site_id=2", code: usecases.ErrorSiteNotFound},
+		{name: "visibility", method: http.MethodGet, path: "/api/positions/visibility?site_id=2", code: usecases.ErrorSiteNotFound},
+		{name: "movement", method: http.MethodGet, path: "/api/positions/movement?site_id=2&source=google", code: usecases.ErrorSiteNotFound},
+		{name: "serp snapshot", method: http.MethodGet, path: "/api/positions/200/serp", code: usecases.ErrorSerpSnapshotNotFound},
+
+		{name: "get tracking job", method: http.MethodGet, path: "/api/tracking-jobs/other-job", code: usecases.ErrorJobNotFound},
+		{name: "cancel tracking job", method: http.MethodPost, path: "/api/tracking-jobs/other-job/cancel", code: usecases.ErrorJobNotFound},
+		{name: "pause tracking job", method: http.MethodPost, path: "/api/tracking-jobs/other-job/pause", code: usecases.ErrorJobNotFound},
+		{name: "resume tracking job", method: http.MethodPost, path: "/api/tracking-jobs/other-job/resume", code: usecases.ErrorJobNotFound},
+		{name: "retry tracking job", method: http.MethodPost, path: "/api/tracking-jobs/other-job/retry-failed", code: usecases.ErrorJobNotFound},
+
+		{name: "create schedule", method: http.MethodPost, path: "/api/tracking-schedules", body: schedule, code: usecases.ErrorSiteNotFound},
+		{name: "get schedule", method: http.MethodGet, path: "/api/tracking-schedules/20", code: usecases.ErrorScheduleNotFound},
+		{name: "update schedule", method: http.MethodPut, path: "/api/tracking-schedules/20", body: gin.H{"site_id": 1, "source": "google", "cron_expr": "0 6 * * *"}, code: usecases.ErrorScheduleNotFound},
+		{name: "delete schedule", method: http.MethodDelete, path: "/api/tracking-schedules/20", code: usecases.ErrorScheduleNotFound},
+		{name: "pause schedule", method: http.MethodPost, path: "/api/tracking-schedules/20/pause", code: usecases.ErrorScheduleNotFound},
+		{name: "resume schedule", method: http.MethodPost, path: "/api/tracking-schedules/20/resume", code: usecases.ErrorScheduleNotFound},
+
+		{name: "get provider account", method: http.MethodGet, path: "/api/provider-accounts/2", code: usecases.ErrorAccountNotFound},
+		{name: "update provider account", method: http.MethodPut, path: "/api/provider-accounts/2", body: account, code: usecases.ErrorAccountNotFound},
+		{name: "delete provider account", method: http.MethodDelete, path: "/api/provider-accounts/2", code: usecases.ErrorAccountNotFound},
+		{name: "revoke api key", method: http.MethodPost, path: "/api/api-keys/2/revoke", code: usecases.ErrorAPIKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := isolationRequest(router, tt.method, tt.path, tt.body, nil)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+			var response dto.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.code, response.Error)
+		})
+	}
+
+	// Данные чужого пространства не изменялись
+	repos.sites.AssertNotCalled(t, "Delete", mock.Anything)
+	repos.keywords.AssertNotCalled(t, "Create", mock.Anything)
+	repos.keywords.AssertNotCalled(t, "Update", mock.Anything)
+	repos.keywords.AssertNotCalled(t, "Delete", mock.Anything)
+	// Сайт, слово и джоб ищутся только в пространстве запроса
+	repos.sites.AssertNotCalled(t, "GetByIDInWorkspace", mock.Anything, (*int)(nil))
+	repos.keywords.AssertNotCalled(t, "GetByIDInWorkspace", mock.Anything, (*int)(nil))
+	repos.jobs.AssertNotCalled(t, "GetByIDInWorkspace", mock.Anything, (*int)(nil))
+}
+
+// Списки с фильтром по сайту передают в репозиторий пространство запроса: сайт 2 в них просто не попадает
+func TestWorkspaceIsolation_ListsFilterByWorkspace(t *testing.T) {
+	router, repos := newIsolationRouter(t)
+	otherSite := mock.MatchedBy(func(id *int) bool { return id != nil && *id == 2 })
+	repos.jobs.On("GetJobsWithPagination", 1, 20, ownWorkspace, otherSite, (*entities.TrackingTaskStatus)(nil)).Return([]*entities.TrackingJob{}, int64(0), nil)
+	repos.schedules.On("GetAll", ownWorkspace, otherSite).Return([]*entities.TrackingSchedule{}, nil)
+	repos.usage.On("GetDailySummary", ownWorkspace, otherSite, (*string)(nil), mock.Anything, mock.Anything).Return([]*entities.UsageSummary{}, nil)
+
+	for _, path := range []string{"/api/tracking-jobs?site_id=2", "/api/tracking-schedules?site_id=2", "/api/usage?site_id=2"} {
+		w := isolationRequest(router, http.MethodGet, path, nil, nil)
+		assert.Equal(t, http.StatusOK, w.Code, path+": "+w.Body.String())
+	}
+
+	repos.jobs.AssertCalled(t, "GetJobsWithPagination", 1, 20, ownWorkspace, otherSite, (*entities.TrackingTaskStatus)(nil))
+	repos.schedules.AssertCalled(t, "GetAll", ownWorkspace, otherSite)
+	repos.usage.AssertCalled(t, "GetDailySummary", ownWorkspace, otherSite, (*string)(nil), mock.Anything, mock.Anything)
+}
+
+func TestWorkspaceIsolation_OwnWorkspaceIsVisible(t *testing.T) {
+	router, repos := newIsolationRouter(t)
+	repos.keywords.On("Update", mock.AnythingOfType("*entities.Keyword")).Return(nil)
+
+	w := isolationRequest(router, http.MethodPut, "/api/keywords/10", dto.UpdateKeywordRequest{}, nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = isolationRequest(router, http.MethodGet, "/api/keywords?site_id=3", nil, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestWorkspaceIsolation_MismatchedWorkspaceHeader(t *testing.T) {
+	router, _ := newIsolationRouter(t)
+
+	w := isolationRequest(router, http.MethodGet, "/api/tracking-jobs/own-job", nil, map[string]string{"X-Workspace-ID": "2"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var response dto.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, usecases.ErrorForbidden, response.Error)
+}
+
+func TestWorkspaceIsolation_ProviderLimitsShowOnlyOwnAccounts(t *testing.T) {
+	router, _ := newIsolationRouter(t)
+
+	w := isolationRequest(router, http.MethodGet, "/api/providers/limits", nil, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response []dto.ProviderLimitsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	// Аккаунт по умолчанию и аккаунт другого пространства не видны
+	if assert.Len(t, response, 1) {
+		assert.Equal(t, "own-user", response[0].Account)
+		assert.Equal(t, 3, response[0].UsedToday)
+	}
+}